@@ -7,18 +7,50 @@ import (
 	"context"
 	"log"
 
+	"golang.org/x/xerrors"
+
 	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/upgrade"
 	"github.com/greenplum-db/gpupgrade/utils"
 )
 
+// ArchiveLogDirectory moves the log directory to the archive directory. It is
+// safe to retry since the hub retries it when an attempt times out, and the
+// substep is re-run when finalize or revert is resumed. A retry waits for any
+// attempt still in flight, and succeeds without moving anything when the log
+// directory was already archived.
 func (s *Server) ArchiveLogDirectory(ctx context.Context, req *idl.ArchiveLogDirectoryRequest) (*idl.ArchiveLogDirectoryReply, error) {
 	log.Printf("starting %s", idl.Substep_archive_log_directories)
 
+	s.archiveMutex.Lock()
+	defer s.archiveMutex.Unlock()
+
 	logDir, err := utils.GetLogDir()
 	if err != nil {
 		return &idl.ArchiveLogDirectoryReply{}, err
 	}
 
+	logDirExists, err := upgrade.PathExist(logDir)
+	if err != nil {
+		return &idl.ArchiveLogDirectoryReply{}, err
+	}
+
+	if !logDirExists {
+		log.Printf("skipping archiving log directory %q since it was already archived", logDir)
+		return &idl.ArchiveLogDirectoryReply{}, nil
+	}
+
+	// Moving onto an existing directory would nest the log directory inside it
+	// rather than archive it.
+	archiveDirExists, err := upgrade.PathExist(req.GetLogArchiveDir())
+	if err != nil {
+		return &idl.ArchiveLogDirectoryReply{}, err
+	}
+
+	if archiveDirExists {
+		return &idl.ArchiveLogDirectoryReply{}, xerrors.Errorf("archive log directory %q: %q already exists", logDir, req.GetLogArchiveDir())
+	}
+
 	log.Printf("moving directory %q to %q", logDir, req.GetLogArchiveDir())
 	err = utils.Move(logDir, req.GetLogArchiveDir())
 	return &idl.ArchiveLogDirectoryReply{}, err
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/greenplum-db/gpupgrade/agent"
+	"github.com/greenplum-db/gpupgrade/greenplum"
 	"github.com/greenplum-db/gpupgrade/hub"
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/testutils"
@@ -49,7 +50,94 @@ func TestArchiveLogDirectories(t *testing.T) {
 		testutils.PathMustExist(t, logArchiveDir)
 	})
 
+	t.Run("archives the pg_upgrade log files of each segment", func(t *testing.T) {
+		homeDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, homeDir)
+
+		utils.System.Current = func() (*user.User, error) {
+			return &user.User{HomeDir: homeDir}, nil
+		}
+		defer utils.ResetSystemFunctions()
+
+		logFile, err := utils.GetPgUpgradeLogFile(greenplum.PrimaryRole, 1)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		testutils.MustCreateDir(t, filepath.Dir(logFile))
+		testutils.MustWriteToFile(t, logFile, "pg_upgrade output")
+
+		logArchiveDir := filepath.Join(homeDir, "gpAdminLogs", "gpupgrade-archive")
+		_, err = agentServer.ArchiveLogDirectory(context.Background(), &idl.ArchiveLogDirectoryRequest{LogArchiveDir: logArchiveDir})
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		testutils.PathMustNotExist(t, logFile)
+		testutils.PathMustExist(t, filepath.Join(logArchiveDir, filepath.Base(logFile)))
+	})
+
+	t.Run("succeeds when retried after the log directory was archived", func(t *testing.T) {
+		homeDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, homeDir)
+
+		utils.System.Current = func() (*user.User, error) {
+			return &user.User{HomeDir: homeDir}, nil
+		}
+		defer utils.ResetSystemFunctions()
+
+		logDir := filepath.Join(homeDir, "gpAdminLogs", "gpupgrade")
+		testutils.MustCreateDir(t, logDir)
+		testutils.MustWriteToFile(t, filepath.Join(logDir, "agent.log"), "")
+
+		logArchiveDir := filepath.Join(homeDir, "gpAdminLogs", "gpupgrade-archive")
+		for i := 0; i < 2; i++ {
+			_, err := agentServer.ArchiveLogDirectory(context.Background(), &idl.ArchiveLogDirectoryRequest{LogArchiveDir: logArchiveDir})
+			if err != nil {
+				t.Errorf("attempt %d: unexpected error %#v", i, err)
+			}
+		}
+
+		testutils.PathMustNotExist(t, logDir)
+		testutils.PathMustExist(t, filepath.Join(logArchiveDir, "agent.log"))
+		testutils.PathMustNotExist(t, filepath.Join(logArchiveDir, "gpupgrade"))
+	})
+
+	t.Run("errors rather than nesting the log directory when the archive directory exists", func(t *testing.T) {
+		homeDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, homeDir)
+
+		utils.System.Current = func() (*user.User, error) {
+			return &user.User{HomeDir: homeDir}, nil
+		}
+		defer utils.ResetSystemFunctions()
+
+		logDir := filepath.Join(homeDir, "gpAdminLogs", "gpupgrade")
+		testutils.MustCreateDir(t, logDir)
+
+		logArchiveDir := filepath.Join(homeDir, "gpAdminLogs", "gpupgrade-archive")
+		testutils.MustCreateDir(t, logArchiveDir)
+
+		_, err := agentServer.ArchiveLogDirectory(context.Background(), &idl.ArchiveLogDirectoryRequest{LogArchiveDir: logArchiveDir})
+		if err == nil {
+			t.Errorf("expected error")
+		}
+
+		testutils.PathMustExist(t, logDir)
+		testutils.PathMustNotExist(t, filepath.Join(logArchiveDir, "gpupgrade"))
+	})
+
 	t.Run("errors when failing to archive log directory on segment host", func(t *testing.T) {
+		homeDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, homeDir)
+
+		utils.System.Current = func() (*user.User, error) {
+			return &user.User{HomeDir: homeDir}, nil
+		}
+		defer utils.ResetSystemFunctions()
+
+		testutils.MustCreateDir(t, filepath.Join(homeDir, "gpAdminLogs", "gpupgrade"))
+
 		logArchiveDir := "" // use an empty target directory string to force an error
 		_, err := agentServer.ArchiveLogDirectory(context.Background(), &idl.ArchiveLogDirectoryRequest{LogArchiveDir: logArchiveDir})
 		if err == nil {
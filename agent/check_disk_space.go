@@ -15,7 +15,13 @@ import (
 func (s *Server) CheckDiskSpace(ctx context.Context, in *idl.CheckSegmentDiskSpaceRequest) (*idl.CheckDiskSpaceReply, error) {
 	log.Printf("starting %s", idl.Substep_check_disk_space)
 
-	usage, err := disk.CheckUsage(step.DevNullStream, disk.Local, in.GetDiskFreeRatio(), in.GetDirs()...)
+	var usage disk.FileSystemDiskUsage
+	var err error
+	if len(in.GetRequirements()) > 0 {
+		usage, err = disk.CheckRequirements(ctx, step.DevNullStream, disk.Local, in.GetRequirements()...)
+	} else {
+		usage, err = disk.CheckUsage(step.DevNullStream, disk.Local, in.GetDiskFreeRatio(), in.GetDirs()...)
+	}
 	if err != nil {
 		return nil, err
 	}
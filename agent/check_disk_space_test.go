@@ -0,0 +1,53 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/agent"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+)
+
+func TestCheckDiskSpace(t *testing.T) {
+	testlog.SetupTestLogger()
+
+	agentServer := agent.New()
+
+	t.Run("checks the requirements rather than the disk free ratio when set", func(t *testing.T) {
+		dir := t.TempDir()
+
+		reply, err := agentServer.CheckDiskSpace(context.Background(), &idl.CheckSegmentDiskSpaceRequest{
+			DiskFreeRatio: 1.0,
+			Dirs:          []string{dir},
+			Requirements:  []*idl.DiskSpaceRequirement{{Dir: dir, Measure: true}},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if len(reply.GetUsage()) != 0 {
+			t.Errorf("got usage %v want none", reply.GetUsage())
+		}
+	})
+
+	t.Run("returns the usage when the requirements exceed the available space", func(t *testing.T) {
+		dir := t.TempDir()
+
+		reply, err := agentServer.CheckDiskSpace(context.Background(), &idl.CheckSegmentDiskSpaceRequest{
+			Requirements: []*idl.DiskSpaceRequirement{{Dir: dir, Required: math.MaxUint64 / 2}},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		usage := reply.GetUsage()
+		if len(usage) != 1 || usage[0].GetRequired() != math.MaxUint64/2 {
+			t.Errorf("got usage %v want one filesystem requiring %d", usage, uint64(math.MaxUint64/2))
+		}
+	})
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+func (s *Server) CheckPorts(ctx context.Context, in *idl.CheckPortsRequest) (*idl.CheckPortsReply, error) {
+	log.Printf("starting %s", idl.Substep_check_target_cluster_ports)
+
+	var ports []int
+	for _, port := range in.GetPorts() {
+		ports = append(ports, int(port))
+	}
+
+	var unavailable []*idl.CheckPortsReply_UnavailablePort
+	for port, err := range utils.UnavailablePorts(ports) {
+		unavailable = append(unavailable, &idl.CheckPortsReply_UnavailablePort{Port: int32(port), Error: err.Error()})
+	}
+
+	sort.Slice(unavailable, func(i, j int) bool {
+		return unavailable[i].GetPort() < unavailable[j].GetPort()
+	})
+
+	return &idl.CheckPortsReply{Unavailable: unavailable}, nil
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/agent"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+)
+
+func TestCheckPorts(t *testing.T) {
+	testlog.SetupTestLogger()
+
+	agentServer := agent.New()
+
+	t.Run("returns no ports when all can be listened on", func(t *testing.T) {
+		reply, err := agentServer.CheckPorts(context.Background(), &idl.CheckPortsRequest{
+			Ports: []int32{int32(testutils.MustGetPort(t)), int32(testutils.MustGetPort(t))},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if len(reply.GetUnavailable()) != 0 {
+			t.Errorf("got unavailable ports %v want none", reply.GetUnavailable())
+		}
+	})
+
+	t.Run("returns the ports held by another process sorted by port", func(t *testing.T) {
+		var ports []int32
+		for i := 0; i < 2; i++ {
+			listener, err := net.Listen("tcp", ":0")
+			if err != nil {
+				t.Fatalf("listen: %v", err)
+			}
+			defer listener.Close()
+
+			ports = append(ports, int32(listener.Addr().(*net.TCPAddr).Port))
+		}
+
+		freePort := int32(testutils.MustGetPort(t))
+		if ports[0] > ports[1] {
+			ports[0], ports[1] = ports[1], ports[0]
+		}
+
+		reply, err := agentServer.CheckPorts(context.Background(), &idl.CheckPortsRequest{
+			Ports: []int32{ports[1], freePort, ports[0]},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		unavailable := reply.GetUnavailable()
+		if len(unavailable) != 2 {
+			t.Fatalf("got unavailable ports %v want %v", unavailable, ports)
+		}
+
+		for i, port := range unavailable {
+			if port.GetPort() != ports[i] {
+				t.Errorf("got port %d want %d", port.GetPort(), ports[i])
+			}
+
+			if port.GetError() == "" {
+				t.Errorf("expected an error for port %d", port.GetPort())
+			}
+		}
+	})
+}
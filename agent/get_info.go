@@ -0,0 +1,56 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/disk"
+)
+
+// Version is the version of the running agent in the format of "gpupgrade
+// version --format oneline". It is set by the agent command from the version
+// compiled into the binary, which may differ from the binary on disk such as
+// when gpupgrade is reinstalled while the agent is running.
+var Version string
+
+// GetInfo reports the agent information. Errors getting the disk space of a
+// data directory are reported for that directory rather than failing the
+// request so that the other directories are still shown.
+func (s *Server) GetInfo(ctx context.Context, in *idl.GetInfoRequest) (*idl.GetInfoReply, error) {
+	hostname, err := utils.System.Hostname()
+	if err != nil {
+		return nil, xerrors.Errorf("get hostname: %w", err)
+	}
+
+	binaryPath, err := utils.GetGpupgradePath()
+	if err != nil {
+		return nil, xerrors.Errorf("get gpupgrade binary path: %w", err)
+	}
+
+	var diskSpace []*idl.AgentInfo_DiskSpace
+	for _, dir := range in.GetDataDirs() {
+		usage, err := disk.Local.Usage(dir)
+		if err != nil {
+			diskSpace = append(diskSpace, &idl.AgentInfo_DiskSpace{Dir: dir, Error: xerrors.Errorf("get disk usage: %w", err).Error()})
+			continue
+		}
+
+		diskSpace = append(diskSpace, &idl.AgentInfo_DiskSpace{Dir: dir, Available: usage.Avail})
+	}
+
+	return &idl.GetInfoReply{Info: &idl.AgentInfo{
+		Version:       Version,
+		Hostname:      hostname,
+		UptimeSeconds: time.Since(s.startTime).Seconds(),
+		StateDir:      utils.GetStateDir(),
+		BinaryPath:    binaryPath,
+		DiskSpace:     diskSpace,
+	}}, nil
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/agent"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+func TestGetInfo(t *testing.T) {
+	testlog.SetupTestLogger()
+
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+	defer resetEnv()
+
+	utils.System.Hostname = func() (string, error) {
+		return "sdw1", nil
+	}
+	defer utils.ResetSystemFunctions()
+
+	agentServer := agent.New()
+
+	agent.Version = agent.GpupgradeVersionOutput
+	defer func() { agent.Version = "" }()
+
+	t.Run("returns the agent information", func(t *testing.T) {
+		dataDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dataDir)
+
+		reply, err := agentServer.GetInfo(context.Background(), &idl.GetInfoRequest{DataDirs: []string{dataDir}})
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		info := reply.GetInfo()
+		if info.GetVersion() != agent.GpupgradeVersionOutput {
+			t.Errorf("got version %q want %q", info.GetVersion(), agent.GpupgradeVersionOutput)
+		}
+
+		if info.GetHostname() != "sdw1" {
+			t.Errorf("got hostname %q want %q", info.GetHostname(), "sdw1")
+		}
+
+		if info.GetStateDir() != stateDir {
+			t.Errorf("got state directory %q want %q", info.GetStateDir(), stateDir)
+		}
+
+		if info.GetUptimeSeconds() <= 0 {
+			t.Errorf("got uptime %f want a positive uptime", info.GetUptimeSeconds())
+		}
+
+		executable, err := os.Executable()
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expectedPath := filepath.Join(filepath.Dir(executable), "gpupgrade")
+		if info.GetBinaryPath() != expectedPath {
+			t.Errorf("got binary path %q want %q", info.GetBinaryPath(), expectedPath)
+		}
+
+		diskSpace := info.GetDiskSpace()
+		if len(diskSpace) != 1 || diskSpace[0].GetDir() != dataDir || diskSpace[0].GetAvailable() == 0 {
+			t.Errorf("got disk space %v want available space for %q", diskSpace, dataDir)
+		}
+	})
+
+	t.Run("reports the error of each data directory whose disk usage cannot be found", func(t *testing.T) {
+		dataDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dataDir)
+
+		missingDir := filepath.Join(stateDir, "does-not-exist")
+		reply, err := agentServer.GetInfo(context.Background(), &idl.GetInfoRequest{DataDirs: []string{missingDir, dataDir}})
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		diskSpace := reply.GetInfo().GetDiskSpace()
+		if len(diskSpace) != 2 {
+			t.Fatalf("got disk space %v want an entry for each data directory", diskSpace)
+		}
+
+		if diskSpace[0].GetDir() != missingDir || !strings.Contains(diskSpace[0].GetError(), "get disk usage") {
+			t.Errorf("got disk space %v want an error for %q", diskSpace[0], missingDir)
+		}
+
+		if diskSpace[1].GetDir() != dataDir || diskSpace[1].GetAvailable() == 0 || diskSpace[1].GetError() != "" {
+			t.Errorf("got disk space %v want available space for %q", diskSpace[1], dataDir)
+		}
+	})
+}
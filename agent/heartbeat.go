@@ -0,0 +1,40 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+const defaultHeartbeatInterval = 10 * time.Second
+
+// Heartbeat sends a heartbeat immediately and then every interval until the
+// hub closes the stream or the agent is stopping.
+func (s *Server) Heartbeat(in *idl.HeartbeatRequest, stream idl.Agent_HeartbeatServer) error {
+	interval := in.GetInterval().AsDuration()
+	if interval <= 0 {
+		interval = defaultHeartbeatInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := stream.Send(&idl.HeartbeatReply{}); err != nil {
+			return xerrors.Errorf("send heartbeat: %w", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stream.Context().Done():
+			return nil
+		case <-s.shutdown:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/greenplum-db/gpupgrade/agent"
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+func TestHeartbeat(t *testing.T) {
+	agentServer := agent.New()
+
+	t.Run("sends heartbeats until the hub closes the stream", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		stream := &heartbeatStream{ctx: ctx, onSend: func(sent int) {
+			if sent == 3 {
+				cancel()
+			}
+		}}
+
+		err := agentServer.Heartbeat(&idl.HeartbeatRequest{Interval: durationpb.New(time.Millisecond)}, stream)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		if stream.sent < 3 {
+			t.Errorf("got %d heartbeats want at least 3", stream.sent)
+		}
+	})
+
+	t.Run("errors when sending a heartbeat fails", func(t *testing.T) {
+		expected := errors.New("transport is closing")
+		stream := &heartbeatStream{ctx: context.Background(), err: expected}
+
+		err := agentServer.Heartbeat(&idl.HeartbeatRequest{Interval: durationpb.New(time.Millisecond)}, stream)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+}
+
+type heartbeatStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	err    error
+	sent   int
+	onSend func(sent int)
+}
+
+func (s *heartbeatStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *heartbeatStream) Send(reply *idl.HeartbeatReply) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	s.sent++
+	if s.onSend != nil {
+		s.onSend(s.sent)
+	}
+
+	return nil
+}
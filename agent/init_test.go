@@ -4,7 +4,9 @@
 package agent
 
 import (
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/greenplum-db/gpupgrade/testutils/exectest"
 )
@@ -24,10 +26,41 @@ func FailedRsync() {
 	os.Exit(2)
 }
 
+// Prints progress the way pg_upgrade --progress redraws its status line.
+func PgUpgradeProgress() {
+	os.Stdout.WriteString("Performing Upgrade\n  copying 1/2\r  copying 2/2\n")
+	os.Stderr.WriteString("warning")
+}
+
+// Prints numbered lines of output before failing like pg_upgrade.
+func FailedPgUpgrade() {
+	for i := 1; i <= 30; i++ {
+		fmt.Printf("line %d\n", i)
+	}
+	fmt.Print("fatal error")
+	os.Exit(1)
+}
+
+// Runs until it is killed.
+func HangingPgUpgrade() {
+	time.Sleep(time.Minute)
+}
+
+const GpupgradeVersionOutput = "Version: 1.0.0 Commit: 83aaa4 Release: Enterprise"
+
+// Succeeds after a delay to simulate an in-flight request.
+func SlowRsync() {
+	time.Sleep(500 * time.Millisecond)
+}
+
 func init() {
 	exectest.RegisterMains(
 		Success,
 		FailedMain,
 		FailedRsync,
+		PgUpgradeProgress,
+		FailedPgUpgrade,
+		HangingPgUpgrade,
+		SlowRsync,
 	)
 }
@@ -0,0 +1,177 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+// Package launcher starts the gpupgrade agents on the segment hosts and runs
+// commands on those hosts. The launcher is chosen with the agent_launcher
+// configuration parameter such that the agents can be supervised by systemd,
+// or started by the hosts themselves.
+package launcher
+
+import (
+	"errors"
+	"log"
+	"os/exec"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/testutils/exectest"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+// ErrCommandsNotSupported is returned by launchers that cannot run commands
+// on the hosts.
+var ErrCommandsNotSupported = errors.New("agent launcher does not support running commands on hosts")
+
+type Launcher interface {
+	// Start starts the agent on the host with the agent arguments such as
+	// the port and state directory.
+	Start(host string, agentArgs []string) error
+
+	// Output runs the shell command on the host and returns its output.
+	Output(host string, command string) ([]byte, error)
+}
+
+const (
+	SSHLauncher     = "ssh"
+	SystemdLauncher = "systemd"
+	RunningLauncher = "running"
+)
+
+// DefaultUnit is the systemd unit of the agent, and DefaultSocket is the
+// socket unit which starts it.
+const (
+	DefaultUnit   = "gpupgrade-agent.service"
+	DefaultSocket = "gpupgrade-agent.socket"
+)
+
+// New returns the launcher with the given name. An empty name returns the
+// ssh launcher which was the only launcher before it was configurable.
+func New(name string) (Launcher, error) {
+	switch name {
+	case "", SSHLauncher:
+		return SSH{}, nil
+	case SystemdLauncher:
+		return Systemd{Socket: DefaultSocket}, nil
+	case RunningLauncher:
+		return Running{}, nil
+	}
+
+	return nil, xerrors.Errorf("invalid agent launcher %q. Expected one of %s, %s, or %s.", name, SSHLauncher, SystemdLauncher, RunningLauncher)
+}
+
+var execCommand = exec.Command
+
+// XXX: for internal testing only
+func SetExecCommand(command exectest.Command) {
+	execCommand = command
+}
+
+// XXX: for internal testing only
+func ResetExecCommand() {
+	execCommand = exec.Command
+}
+
+// SSH starts the agents and runs commands over ssh.
+type SSH struct{}
+
+func (SSH) Start(host string, agentArgs []string) error {
+	agentCmd, err := agentCommand(agentArgs)
+	if err != nil {
+		return err
+	}
+
+	// Quote each argument since ssh passes the command to the remote shell
+	// as a single string, and paths such as the TLS certificates may contain
+	// spaces.
+	cmd := execCommand("ssh", host, "bash -c "+utils.ShellQuote(utils.ShellJoin(agentCmd...)))
+	log.Printf("Executing: %q", cmd.String())
+	stdout, err := cmd.Output()
+	if err != nil {
+		return xerrors.Errorf("start agent on host %s: %w", host, err)
+	}
+
+	log.Print(string(stdout))
+	return nil
+}
+
+// Output uses -q to suppress motd banner messages from polluting the output.
+func (SSH) Output(host string, command string) ([]byte, error) {
+	return output(execCommand("ssh", "-q", host, command))
+}
+
+// Systemd relies on the agent socket unit installed on each host with
+// "gpupgrade agent install-unit", which starts the agent unit when the hub
+// connects. Thus the agent is started without the hub running commands on the
+// host. The agent arguments such as the port, state directory, and TLS
+// certificates are fixed when the unit is installed. Start is only called when
+// the hub cannot connect, such as when the socket is not installed, enabled,
+// or listening on the port the hub expects.
+type Systemd struct {
+	Socket string
+}
+
+func (s Systemd) Start(host string, agentArgs []string) error {
+	return xerrors.Errorf("agent socket %s on host %s is not accepting connections. On the host install the agent units with %q and start the socket with %q.",
+		s.Socket, host, "gpupgrade agent install-unit "+utils.ShellJoin(agentArgs...), "systemctl enable --now "+s.Socket)
+}
+
+func (s Systemd) Output(host string, command string) ([]byte, error) {
+	return nil, ErrCommandsNotSupported
+}
+
+// Running expects the agents to already be running such as when they are
+// started by the host's init system or an administrator.
+type Running struct{}
+
+func (Running) Start(host string, agentArgs []string) error {
+	return xerrors.Errorf("agent on host %s is not running. Start the agent with %q or configure a different agent_launcher.", host, "gpupgrade agent "+utils.ShellJoin(agentArgs...))
+}
+
+func (Running) Output(host string, command string) ([]byte, error) {
+	return nil, ErrCommandsNotSupported
+}
+
+// Local starts the agents and runs commands on the local host regardless of
+// the host they are meant for. It is used for testing.
+type Local struct{}
+
+func (Local) Start(host string, agentArgs []string) error {
+	agentCmd, err := agentCommand(agentArgs)
+	if err != nil {
+		return err
+	}
+
+	cmd := execCommand(agentCmd[0], agentCmd[1:]...)
+	log.Printf("Executing: %q", cmd.String())
+	stdout, err := cmd.Output()
+	if err != nil {
+		return xerrors.Errorf("start agent for host %s: %w", host, err)
+	}
+
+	log.Print(string(stdout))
+	return nil
+}
+
+func (Local) Output(host string, command string) ([]byte, error) {
+	return output(execCommand("/bin/bash", "-c", command))
+}
+
+func agentCommand(agentArgs []string) ([]string, error) {
+	path, err := utils.GetGpupgradePath()
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]string{path, "agent", "--daemonize"}, agentArgs...), nil
+}
+
+func output(cmd *exec.Cmd) ([]byte, error) {
+	log.Printf("Executing: %q", cmd.String())
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, xerrors.Errorf("%q failed with %q: %w", cmd.String(), string(output), err)
+	}
+
+	log.Printf("Output: %q", output)
+	return output, nil
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package launcher_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/agent/launcher"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/testutils/exectest"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+)
+
+func Success() {}
+
+func Failure() {
+	os.Stderr.WriteString("permission denied")
+	os.Exit(1)
+}
+
+func Output() {
+	fmt.Print("/usr/local/bin:/usr/bin")
+}
+
+func init() {
+	exectest.RegisterMains(
+		Success,
+		Failure,
+		Output,
+	)
+}
+
+// Enable exectest.NewCommand mocking.
+func TestMain(m *testing.M) {
+	os.Exit(exectest.Run(m))
+}
+
+func TestNew(t *testing.T) {
+	cases := []struct {
+		name     string
+		expected launcher.Launcher
+	}{
+		{"", launcher.SSH{}},
+		{"ssh", launcher.SSH{}},
+		{"systemd", launcher.Systemd{Socket: launcher.DefaultSocket}},
+		{"running", launcher.Running{}},
+	}
+
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("returns the %q launcher", c.name), func(t *testing.T) {
+			l, err := launcher.New(c.name)
+			if err != nil {
+				t.Errorf("unexpected error %#v", err)
+			}
+
+			if !reflect.DeepEqual(l, c.expected) {
+				t.Errorf("got %#v want %#v", l, c.expected)
+			}
+		})
+	}
+
+	t.Run("errors on an invalid launcher", func(t *testing.T) {
+		_, err := launcher.New("telnet")
+		if err == nil || !strings.Contains(err.Error(), `invalid agent launcher "telnet"`) {
+			t.Errorf("got error %v want an invalid agent launcher error", err)
+		}
+	})
+}
+
+func TestSSH(t *testing.T) {
+	testlog.SetupTestLogger()
+
+	t.Run("starts the agent over ssh", func(t *testing.T) {
+		launcher.SetExecCommand(exectest.NewCommandWithVerifier(Success, func(name string, args ...string) {
+			if name != "ssh" {
+				t.Errorf("got %q want ssh", name)
+			}
+
+			agentCmd := fmt.Sprintf(`'%s/gpupgrade' 'agent' '--daemonize' '--port' '1234' '--tls-key' '/etc/my certs/agent.key'`, testutils.MustGetExecutablePath(t))
+			cmd := "bash -c '" + strings.ReplaceAll(agentCmd, "'", `'\''`) + "'"
+			expected := []string{"sdw1", cmd}
+			if !reflect.DeepEqual(args, expected) {
+				t.Errorf("got %q want %q", args, expected)
+			}
+		}))
+		defer launcher.ResetExecCommand()
+
+		err := launcher.SSH{}.Start("sdw1", []string{"--port", "1234", "--tls-key", "/etc/my certs/agent.key"})
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+
+	t.Run("errors when starting the agent fails", func(t *testing.T) {
+		launcher.SetExecCommand(exectest.NewCommand(Failure))
+		defer launcher.ResetExecCommand()
+
+		err := launcher.SSH{}.Start("sdw1", nil)
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+			t.Errorf("got error %#v want exit code 1", err)
+		}
+	})
+
+	t.Run("runs commands over ssh using -q to suppress motd banner messages", func(t *testing.T) {
+		launcher.SetExecCommand(exectest.NewCommandWithVerifier(Output, func(name string, args ...string) {
+			expected := []string{"-q", "sdw1", "echo $PATH"}
+			if name != "ssh" || !reflect.DeepEqual(args, expected) {
+				t.Errorf("got %s %q want ssh %q", name, args, expected)
+			}
+		}))
+		defer launcher.ResetExecCommand()
+
+		output, err := launcher.SSH{}.Output("sdw1", "echo $PATH")
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		if string(output) != "/usr/local/bin:/usr/bin" {
+			t.Errorf("got output %q", output)
+		}
+	})
+
+	t.Run("errors with the output when the command fails", func(t *testing.T) {
+		launcher.SetExecCommand(exectest.NewCommand(Failure))
+		defer launcher.ResetExecCommand()
+
+		_, err := launcher.SSH{}.Output("sdw1", "echo $PATH")
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+			t.Errorf("got error %#v want exit code 1", err)
+		}
+
+		if !strings.Contains(err.Error(), "permission denied") {
+			t.Errorf("got error %q want it to contain the output", err)
+		}
+	})
+}
+
+func TestSystemd(t *testing.T) {
+	t.Run("does not run commands to start the agent", func(t *testing.T) {
+		launcher.SetExecCommand(exectest.NewCommandWithVerifier(Success, func(name string, args ...string) {
+			t.Errorf("unexpected command %s %q", name, args)
+		}))
+		defer launcher.ResetExecCommand()
+
+		_ = launcher.Systemd{Socket: launcher.DefaultSocket}.Start("sdw1", []string{"--port", "1234"})
+	})
+
+	t.Run("errors with how to install and start the socket when the agent is not accepting connections", func(t *testing.T) {
+		err := launcher.Systemd{Socket: launcher.DefaultSocket}.Start("sdw1", []string{"--port", "1234", "--tls-key", "/etc/my certs/agent.key"})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+
+		for _, expected := range []string{
+			"host sdw1",
+			`gpupgrade agent install-unit '--port' '1234' '--tls-key' '/etc/my certs/agent.key'`,
+			"systemctl enable --now gpupgrade-agent.socket",
+		} {
+			if !strings.Contains(err.Error(), expected) {
+				t.Errorf("got error %q want it to contain %q", err, expected)
+			}
+		}
+	})
+
+	t.Run("does not support running commands", func(t *testing.T) {
+		_, err := launcher.Systemd{Socket: launcher.DefaultSocket}.Output("sdw1", "echo $PATH")
+		if !errors.Is(err, launcher.ErrCommandsNotSupported) {
+			t.Errorf("got error %#v want %#v", err, launcher.ErrCommandsNotSupported)
+		}
+	})
+}
+
+func TestRunning(t *testing.T) {
+	t.Run("errors when the agent is not running", func(t *testing.T) {
+		err := launcher.Running{}.Start("sdw1", []string{"--port", "1234"})
+		if err == nil || !strings.Contains(err.Error(), "agent on host sdw1 is not running") {
+			t.Errorf("got error %v want the agent to not be running", err)
+		}
+	})
+
+	t.Run("quotes the agent arguments in the suggested command", func(t *testing.T) {
+		err := launcher.Running{}.Start("sdw1", []string{"--listen-address", "fe80::1%eth0"})
+		expected := `gpupgrade agent '--listen-address' 'fe80::1%eth0'`
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("got error %v want it to contain %q", err, expected)
+		}
+	})
+
+	t.Run("does not support running commands", func(t *testing.T) {
+		_, err := launcher.Running{}.Output("sdw1", "echo $PATH")
+		if !errors.Is(err, launcher.ErrCommandsNotSupported) {
+			t.Errorf("got error %#v want %#v", err, launcher.ErrCommandsNotSupported)
+		}
+	})
+}
+
+func TestLocal(t *testing.T) {
+	testlog.SetupTestLogger()
+
+	t.Run("starts the agent locally", func(t *testing.T) {
+		launcher.SetExecCommand(exectest.NewCommandWithVerifier(Success, func(name string, args ...string) {
+			expectedName := testutils.MustGetExecutablePath(t) + "/gpupgrade"
+			expected := []string{"agent", "--daemonize", "--port", "1234"}
+			if name != expectedName || !reflect.DeepEqual(args, expected) {
+				t.Errorf("got %s %q want %s %q", name, args, expectedName, expected)
+			}
+		}))
+		defer launcher.ResetExecCommand()
+
+		err := launcher.Local{}.Start("sdw1", []string{"--port", "1234"})
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+
+	t.Run("runs commands locally", func(t *testing.T) {
+		resetEnv := testutils.SetEnv(t, "GPUPGRADE_TEST_VALUE", "local")
+		defer resetEnv()
+
+		output, err := launcher.Local{}.Output("sdw1", "echo $GPUPGRADE_TEST_VALUE")
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		if string(output) != "local\n" {
+			t.Errorf("got output %q want %q", output, "local\n")
+		}
+	})
+}
@@ -13,6 +13,7 @@ import (
 )
 
 var RenameDirectories = upgrade.RenameDirectories
+var AlreadyRenamed = upgrade.AlreadyRenamed
 
 func (s *Server) RenameDirectories(ctx context.Context, in *idl.RenameDirectoriesRequest) (*idl.RenameDirectoriesReply, error) {
 	log.Printf("starting %s", idl.Substep_update_data_directories)
@@ -27,3 +28,20 @@ func (s *Server) RenameDirectories(ctx context.Context, in *idl.RenameDirectorie
 
 	return &idl.RenameDirectoriesReply{}, mErr
 }
+
+// AlreadyRenamedDirectories reports whether all target directories have already
+// been renamed to their source location and their source archived.
+func (s *Server) AlreadyRenamedDirectories(ctx context.Context, in *idl.RenameDirectoriesRequest) (*idl.AlreadyRenamedDirectoriesReply, error) {
+	for _, dir := range in.GetDirs() {
+		renamed, err := AlreadyRenamed(dir.GetTarget(), dir.GetTarget()+upgrade.OldSuffix)
+		if err != nil {
+			return &idl.AlreadyRenamedDirectoriesReply{}, err
+		}
+
+		if !renamed {
+			return &idl.AlreadyRenamedDirectoriesReply{Renamed: false}, nil
+		}
+	}
+
+	return &idl.AlreadyRenamedDirectoriesReply{Renamed: true}, nil
+}
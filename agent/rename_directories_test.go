@@ -6,6 +6,7 @@ package agent_test
 import (
 	"context"
 	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/greenplum-db/gpupgrade/agent"
@@ -29,4 +30,54 @@ func TestRenameDirectories(t *testing.T) {
 			t.Errorf("returned error %#v, want %#v", err, expected)
 		}
 	})
+
+	t.Run("reports when all directories have been renamed", func(t *testing.T) {
+		var calls []string
+		agent.AlreadyRenamed = func(src, dst string) (bool, error) {
+			calls = append(calls, src+" "+dst)
+			return true, nil
+		}
+
+		dirs := []*idl.RenameDirectories{{Source: "/data/dbfast1/seg1", Target: "/data/dbfast1/seg.AAAA.1"}, {Source: "/data/dbfast2/seg2", Target: "/data/dbfast2/seg.AAAA.2"}}
+		reply, err := agentServer.AlreadyRenamedDirectories(context.Background(), &idl.RenameDirectoriesRequest{Dirs: dirs})
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		if !reply.GetRenamed() {
+			t.Errorf("expected directories to be renamed")
+		}
+
+		expected := []string{"/data/dbfast1/seg.AAAA.1 /data/dbfast1/seg.AAAA.1.old", "/data/dbfast2/seg.AAAA.2 /data/dbfast2/seg.AAAA.2.old"}
+		if !reflect.DeepEqual(calls, expected) {
+			t.Errorf("got %v want %v", calls, expected)
+		}
+	})
+
+	t.Run("reports when a directory has not been renamed", func(t *testing.T) {
+		agent.AlreadyRenamed = func(src, dst string) (bool, error) {
+			return false, nil
+		}
+
+		reply, err := agentServer.AlreadyRenamedDirectories(context.Background(), &idl.RenameDirectoriesRequest{Dirs: []*idl.RenameDirectories{{}}})
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		if reply.GetRenamed() {
+			t.Errorf("expected directories to not be renamed")
+		}
+	})
+
+	t.Run("bubbles up errors when checking if renamed", func(t *testing.T) {
+		expected := errors.New("permission denied")
+		agent.AlreadyRenamed = func(src, dst string) (bool, error) {
+			return false, expected
+		}
+
+		_, err := agentServer.AlreadyRenamedDirectories(context.Background(), &idl.RenameDirectoriesRequest{Dirs: []*idl.RenameDirectories{{}}})
+		if !errors.Is(err, expected) {
+			t.Errorf("returned error %#v, want %#v", err, expected)
+		}
+	})
 }
@@ -32,7 +32,8 @@ func (s *Server) RsyncDataDirectories(ctx context.Context, in *idl.RsyncRequest)
 		return &idl.RsyncReply{}, mErr
 	}
 
-	return &idl.RsyncReply{}, rsyncRequestDirs(in)
+	stats, err := rsyncRequestDirs(ctx, in)
+	return &idl.RsyncReply{Stats: stats}, err
 }
 
 func (s *Server) RsyncTablespaceDirectories(ctx context.Context, in *idl.RsyncRequest) (*idl.RsyncReply, error) {
@@ -48,17 +49,21 @@ func (s *Server) RsyncTablespaceDirectories(ctx context.Context, in *idl.RsyncRe
 		}
 	}
 
-	return &idl.RsyncReply{}, rsyncRequestDirs(in)
+	stats, err := rsyncRequestDirs(ctx, in)
+	return &idl.RsyncReply{Stats: stats}, err
 }
 
-func rsyncRequestDirs(in *idl.RsyncRequest) error {
+// rsyncRequestDirs returns the statistics of each rsync such that the hub can
+// record them.
+func rsyncRequestDirs(ctx context.Context, in *idl.RsyncRequest) ([]*idl.RsyncStats, error) {
 	hostname, err := os.Hostname()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var wg sync.WaitGroup
 	errs := make(chan error, len(in.GetOptions()))
+	statsChan := make(chan *idl.RsyncStats, len(in.GetOptions()))
 
 	for _, opts := range in.GetOptions() {
 		opts := opts
@@ -67,14 +72,21 @@ func rsyncRequestDirs(in *idl.RsyncRequest) error {
 		go func() {
 			defer wg.Done()
 
+			var stats rsync.Stats
 			opts := []rsync.Option{
 				rsync.WithSources(opts.GetSources()...),
 				rsync.WithDestinationHost(opts.GetDestinationHost()),
 				rsync.WithDestination(opts.GetDestination()),
 				rsync.WithOptions(opts.GetOptions()...),
 				rsync.WithExcludedFiles(opts.GetExcludedFiles()...),
+				rsync.WithStats(&stats),
+				rsync.WithContext(ctx),
 			}
 			err := rsync.Rsync(opts...)
+			statsChan <- &idl.RsyncStats{
+				TransferredBytes: stats.TransferredBytes,
+				DurationSeconds:  stats.Duration.Seconds(),
+			}
 			if err != nil {
 				errs <- fmt.Errorf("on host %q: %w", hostname, err)
 			}
@@ -83,10 +95,16 @@ func rsyncRequestDirs(in *idl.RsyncRequest) error {
 
 	wg.Wait()
 	close(errs)
+	close(statsChan)
 
 	for e := range errs {
 		err = errorlist.Append(err, e)
 	}
 
-	return err
+	var stats []*idl.RsyncStats
+	for s := range statsChan {
+		stats = append(stats, s)
+	}
+
+	return stats, err
 }
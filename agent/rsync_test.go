@@ -90,10 +90,14 @@ func TestRsync(t *testing.T) {
 			}},
 		}
 
-		_, err := agentServer.RsyncDataDirectories(context.Background(), request)
+		reply, err := agentServer.RsyncDataDirectories(context.Background(), request)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
+
+		if len(reply.GetStats()) != 1 {
+			t.Errorf("got stats %v want stats for one rsync", reply.GetStats())
+		}
 	})
 
 	t.Run("errors when source data directory is empty", func(t *testing.T) {
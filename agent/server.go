@@ -9,47 +9,130 @@ import (
 	"log"
 	"net"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"strconv"
 	"sync"
+	"syscall"
+	"time"
 
 	"golang.org/x/xerrors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/reflection"
+	grpcStatus "google.golang.org/grpc/status"
 
 	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
 	"github.com/greenplum-db/gpupgrade/utils/daemon"
 	"github.com/greenplum-db/gpupgrade/utils/logger"
+	"github.com/greenplum-db/gpupgrade/utils/mtls"
 )
 
+// PIDFileName is the file in the state directory containing the process ID
+// of the running agent.
+const PIDFileName = "agent.pid"
+
 type Server struct {
 	mutex       sync.Mutex
 	gRPCserver  *grpc.Server
 	listener    net.Listener
 	stoppedChan chan struct{}
+	startTime   time.Time
+
+	// archiveMutex serializes the attempts of ArchiveLogDirectory, which the
+	// hub retries when an attempt times out.
+	archiveMutex sync.Mutex
+
+	// draining is set once a graceful stop has begun after which new RPCs
+	// are rejected. rpcs tracks the RPCs in flight, and shutdown is closed
+	// once they have finished to end any heartbeat streams.
+	draining     bool
+	rpcs         sync.WaitGroup
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
 }
 
 func New() *Server {
 	return &Server{
-		stoppedChan: make(chan struct{}, 1),
+		stoppedChan: make(chan struct{}),
+		startTime:   time.Now(),
+		shutdown:    make(chan struct{}),
 	}
 }
 
-func (s *Server) Start(port int, stateDir string, daemonize bool) error {
+// Start serves the agent until it is stopped. On SIGTERM the agent stops
+// gracefully by finishing any in-flight RPCs. While running, the process ID
+// is written to PIDFileName in the state directory, and readiness is signaled
+// when running as a systemd service with Type=notify. When socket activated the
+// agent listens on the socket passed by systemd.
+func (s *Server) Start(listenAddress string, port int, stateDir string, tlsConfig mtls.AgentConfig, daemonize bool) error {
 	err := createStateDirectory(stateDir)
 	if err != nil {
 		return err
 	}
 
-	listener, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	opts, err := tlsConfig.ServerOptions()
 	if err != nil {
-		return fmt.Errorf("listen on port %d: %w", port, err)
+		return err
+	}
+
+	// When socket activated by systemd, listen on the socket of the unit
+	// rather than the port and listen address.
+	listener, err := daemon.Listener()
+	if err != nil {
+		return err
+	}
+
+	address := net.JoinHostPort(listenAddress, strconv.Itoa(port))
+	if listener != nil {
+		address = listener.Addr().String()
+	} else {
+		listener, err = net.Listen("tcp", address)
+		if err != nil {
+			return fmt.Errorf("listen on %q: %w", address, err)
+		}
 	}
 
 	interceptor := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
 		defer logger.WritePanics()
+
+		// StopAgent waits for the agent to stop, and thus is not waited on
+		// by GracefulStop since otherwise they would wait on each other.
+		if info.FullMethod == "/idl.Agent/StopAgent" {
+			return handler(ctx, req)
+		}
+
+		if err := s.beginRPC(); err != nil {
+			return nil, err
+		}
+		defer s.rpcs.Done()
+
 		return handler(ctx, req)
 	}
-	gRPCserver := grpc.NewServer(grpc.UnaryInterceptor(interceptor))
+
+	streamInterceptor := func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		defer logger.WritePanics()
+
+		// Heartbeats last as long as the hub is connected and thus are not
+		// waited on. Instead they end once the in-flight RPCs have finished.
+		if info.FullMethod == "/idl.Agent/Heartbeat" {
+			return handler(srv, stream)
+		}
+
+		if err := s.beginRPC(); err != nil {
+			return err
+		}
+		defer s.rpcs.Done()
+
+		return handler(srv, stream)
+	}
+
+	gRPCserver := grpc.NewServer(append(opts, grpc.UnaryInterceptor(interceptor), grpc.StreamInterceptor(streamInterceptor))...)
 
 	s.mutex.Lock()
 	s.gRPCserver = gRPCserver
@@ -59,17 +142,64 @@ func (s *Server) Start(port int, stateDir string, daemonize bool) error {
 	idl.RegisterAgentServer(gRPCserver, s)
 	reflection.Register(gRPCserver)
 
+	pidFile := filepath.Join(stateDir, PIDFileName)
+	err = utils.AtomicallyWrite(pidFile, []byte(strconv.Itoa(os.Getpid())+"\n"))
+	if err != nil {
+		return xerrors.Errorf("write pid file: %w", err)
+	}
+	defer func() {
+		if err := os.Remove(pidFile); err != nil {
+			log.Printf("removing pid file %q: %v", pidFile, err)
+		}
+	}()
+
+	served := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+
+		select {
+		case sig := <-signals:
+			log.Printf("Received %s. Stopping the agent after in-flight requests finish.", sig)
+			if err := daemon.Notify("STOPPING=1"); err != nil {
+				log.Printf("notifying systemd: %v", err)
+			}
+
+			s.GracefulStop()
+		case <-served:
+		}
+	}()
+
 	if daemonize {
-		log.Printf("Agent started on port %d with pid %d", port, os.Getpid())
+		log.Printf("Agent started on %s with pid %d", address, os.Getpid())
 		daemon.Daemonize()
 	}
 
+	if err := daemon.Notify("READY=1"); err != nil {
+		log.Printf("notifying systemd: %v", err)
+	}
+
 	err = gRPCserver.Serve(listener)
+	close(served)
+	<-stopped
 	if err != nil {
 		return fmt.Errorf("agent gRPC Serve: %w", err)
 	}
 
-	s.stoppedChan <- struct{}{}
+	close(s.stoppedChan)
+	return nil
+}
+
+// beginRPC tracks a new RPC unless the agent is stopping.
+func (s *Server) beginRPC() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.draining {
+		return grpcStatus.Error(codes.Unavailable, "agent is shutting down")
+	}
+
+	s.rpcs.Add(1)
 	return nil
 }
 
@@ -78,16 +208,36 @@ func (s *Server) StopAgent(ctx context.Context, in *idl.StopAgentRequest) (*idl.
 	return &idl.StopAgentReply{}, nil
 }
 
+// Stop stops the agent without waiting for the in-flight RPCs and returns
+// once the agent has stopped. The mutex is not held while waiting such that a
+// graceful stop already in progress can finish.
 func (s *Server) Stop() {
 	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	gRPCserver := s.gRPCserver
+	s.mutex.Unlock()
 
-	if s.gRPCserver != nil {
-		s.gRPCserver.Stop()
+	if gRPCserver != nil {
+		gRPCserver.Stop()
 		<-s.stoppedChan
 	}
 }
 
+// GracefulStop rejects new RPCs and stops the agent once the in-flight RPCs
+// have finished.
+func (s *Server) GracefulStop() {
+	s.mutex.Lock()
+	s.draining = true
+	gRPCserver := s.gRPCserver
+	s.mutex.Unlock()
+
+	s.rpcs.Wait()
+	s.shutdownOnce.Do(func() { close(s.shutdown) })
+
+	if gRPCserver != nil {
+		gRPCserver.GracefulStop()
+	}
+}
+
 func createStateDirectory(dir string) error {
 	// When the agent is started it is passed the state directory. Ensure it also
 	// sets GPUPGRADE_HOME in its environment such that utils functions work.
@@ -4,19 +4,29 @@
 package agent_test
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"os"
 	"path"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"syscall"
 	"testing"
 	"time"
 
 	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
 
 	"github.com/greenplum-db/gpupgrade/agent"
+	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/testutils/exectest"
 	"github.com/greenplum-db/gpupgrade/testutils/testlog"
 	"github.com/greenplum-db/gpupgrade/upgrade"
+	"github.com/greenplum-db/gpupgrade/utils/mtls"
+	"github.com/greenplum-db/gpupgrade/utils/rsync"
 )
 
 const timeout = 1 * time.Second
@@ -35,7 +45,7 @@ func TestServerStart(t *testing.T) {
 
 		errChan := make(chan error, 1)
 		go func() {
-			errChan <- agentServer.Start(testutils.MustGetPort(t), stateDir, false)
+			errChan <- agentServer.Start("", testutils.MustGetPort(t), stateDir, mtls.AgentConfig{}, false)
 		}()
 
 		exists, err := doesPathEventuallyExist(t, stateDir)
@@ -69,7 +79,7 @@ func TestServerStart(t *testing.T) {
 
 		errChan := make(chan error, 1)
 		go func() {
-			errChan <- agentServer.Start(testutils.MustGetPort(t), stateDir, false)
+			errChan <- agentServer.Start("", testutils.MustGetPort(t), stateDir, mtls.AgentConfig{}, false)
 		}()
 
 		testutils.PathMustExist(t, stateDir)
@@ -97,12 +107,12 @@ func TestServerStart(t *testing.T) {
 
 		errChan := make(chan error, 1)
 		go func() {
-			errChan <- agentServer.Start(portInUse, stateDir, false)
+			errChan <- agentServer.Start("", portInUse, stateDir, mtls.AgentConfig{}, false)
 		}()
 
 		select {
 		case err := <-errChan:
-			expected := fmt.Sprintf("listen on port %d: listen tcp :%d: bind: address already in use", portInUse, portInUse)
+			expected := fmt.Sprintf("listen on \":%d\": listen tcp :%d: bind: address already in use", portInUse, portInUse)
 			if err != nil && !strings.Contains(err.Error(), expected) {
 				t.Errorf("got error %#v want %#v", err, expected)
 			}
@@ -110,6 +120,253 @@ func TestServerStart(t *testing.T) {
 			t.Error("timeout exceeded")
 		}
 	})
+
+	t.Run("start returns an error when the listen address is not on this host", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, ".gpupgrade")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		agentServer := agent.New()
+
+		// 192.0.2.0/24 is reserved for documentation and is never assigned to
+		// a local interface.
+		port := testutils.MustGetPort(t)
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- agentServer.Start("192.0.2.1", port, stateDir, mtls.AgentConfig{}, false)
+		}()
+
+		select {
+		case err := <-errChan:
+			expected := fmt.Sprintf("listen on \"192.0.2.1:%d\"", port)
+			if err == nil || !strings.Contains(err.Error(), expected) {
+				t.Errorf("got error %#v want %q", err, expected)
+			}
+		case <-time.After(timeout):
+			agentServer.Stop()
+			t.Error("timeout exceeded")
+		}
+	})
+}
+
+func TestServerPIDFile(t *testing.T) {
+	testlog.SetupTestLogger()
+
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	agentServer := agent.New()
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- agentServer.Start("", testutils.MustGetPort(t), stateDir, mtls.AgentConfig{}, false)
+	}()
+
+	pidFile := filepath.Join(stateDir, agent.PIDFileName)
+	exists, err := doesPathEventuallyExist(t, pidFile)
+	if err != nil || !exists {
+		t.Fatalf("expected pid file %q to be written: %v", pidFile, err)
+	}
+
+	contents := testutils.MustReadFile(t, pidFile)
+	if contents != strconv.Itoa(os.Getpid())+"\n" {
+		t.Errorf("got pid file contents %q want %d", contents, os.Getpid())
+	}
+
+	agentServer.Stop()
+
+	select {
+	case err := <-errChan:
+		if err != nil {
+			t.Errorf("unexpected error: %#v", err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timeout exceeded")
+	}
+
+	testutils.PathMustNotExist(t, pidFile)
+}
+
+func TestServerGracefulStop(t *testing.T) {
+	testlog.SetupTestLogger()
+
+	t.Run("stops on SIGTERM", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		agentServer := agent.New()
+
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- agentServer.Start("", testutils.MustGetPort(t), stateDir, mtls.AgentConfig{}, false)
+		}()
+
+		// The pid file is written once the agent handles SIGTERM.
+		exists, err := doesPathEventuallyExist(t, filepath.Join(stateDir, agent.PIDFileName))
+		if err != nil || !exists {
+			t.Fatalf("expected pid file to be written: %v", err)
+		}
+
+		err = syscall.Kill(os.Getpid(), syscall.SIGTERM)
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		select {
+		case err := <-errChan:
+			if err != nil {
+				t.Errorf("unexpected error: %#v", err)
+			}
+		case <-time.After(timeout):
+			agentServer.Stop()
+			t.Fatal("timeout exceeded")
+		}
+	})
+
+	t.Run("waits for in-flight requests to finish", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		rsync.SetRsyncCommand(exectest.NewCommand(agent.SlowRsync))
+		defer rsync.ResetRsyncCommand()
+
+		dataDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dataDir)
+
+		for _, file := range upgrade.PostgresFiles {
+			testutils.MustWriteToFile(t, filepath.Join(dataDir, file), "")
+		}
+
+		port := testutils.MustGetPort(t)
+		agentServer := agent.New()
+
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- agentServer.Start("", port, stateDir, mtls.AgentConfig{}, false)
+		}()
+
+		exists, err := doesPathEventuallyExist(t, filepath.Join(stateDir, agent.PIDFileName))
+		if err != nil || !exists {
+			t.Fatalf("expected pid file to be written: %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		conn, err := grpc.DialContext(ctx, net.JoinHostPort("localhost", strconv.Itoa(port)), grpc.WithInsecure(), grpc.WithBlock())
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+		defer conn.Close()
+
+		replyChan := make(chan error, 1)
+		go func() {
+			_, err := idl.NewAgentClient(conn).RsyncDataDirectories(context.Background(), &idl.RsyncRequest{
+				Options: []*idl.RsyncRequest_RsyncOptions{{Sources: []string{dataDir}, Destination: filepath.Join(dataDir, "copy")}},
+			})
+			replyChan <- err
+		}()
+
+		// Give the request time to start before stopping.
+		time.Sleep(100 * time.Millisecond)
+		agentServer.GracefulStop()
+
+		select {
+		case err := <-replyChan:
+			if err != nil {
+				t.Errorf("unexpected error: %#v", err)
+			}
+		default:
+			t.Error("expected the in-flight request to finish before stopping")
+		}
+
+		select {
+		case err := <-errChan:
+			if err != nil {
+				t.Errorf("unexpected error: %#v", err)
+			}
+		case <-time.After(timeout):
+			t.Fatal("timeout exceeded")
+		}
+
+		_, err = idl.NewAgentClient(conn).GetInfo(context.Background(), &idl.GetInfoRequest{})
+		if err == nil {
+			t.Error("expected requests after stopping to fail")
+		}
+	})
+
+	t.Run("stops when StopAgent is called while stopping gracefully", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		rsync.SetRsyncCommand(exectest.NewCommand(agent.SlowRsync))
+		defer rsync.ResetRsyncCommand()
+
+		dataDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dataDir)
+
+		for _, file := range upgrade.PostgresFiles {
+			testutils.MustWriteToFile(t, filepath.Join(dataDir, file), "")
+		}
+
+		port := testutils.MustGetPort(t)
+		agentServer := agent.New()
+
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- agentServer.Start("", port, stateDir, mtls.AgentConfig{}, false)
+		}()
+
+		exists, err := doesPathEventuallyExist(t, filepath.Join(stateDir, agent.PIDFileName))
+		if err != nil || !exists {
+			t.Fatalf("expected pid file to be written: %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		conn, err := grpc.DialContext(ctx, net.JoinHostPort("localhost", strconv.Itoa(port)), grpc.WithInsecure(), grpc.WithBlock())
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+		defer conn.Close()
+
+		client := idl.NewAgentClient(conn)
+		go func() {
+			_, _ = client.RsyncDataDirectories(context.Background(), &idl.RsyncRequest{
+				Options: []*idl.RsyncRequest_RsyncOptions{{Sources: []string{dataDir}, Destination: filepath.Join(dataDir, "copy")}},
+			})
+		}()
+
+		// Give the request time to start before stopping.
+		time.Sleep(100 * time.Millisecond)
+		err = syscall.Kill(os.Getpid(), syscall.SIGTERM)
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		// StopAgent begins while the graceful stop waits on the request.
+		time.Sleep(100 * time.Millisecond)
+		stopChan := make(chan struct{})
+		go func() {
+			defer close(stopChan)
+			_, _ = client.StopAgent(context.Background(), &idl.StopAgentRequest{})
+		}()
+
+		select {
+		case err := <-errChan:
+			if err != nil {
+				t.Errorf("unexpected error: %#v", err)
+			}
+		case <-time.After(timeout):
+			t.Fatal("timeout exceeded")
+		}
+
+		select {
+		case <-stopChan:
+		case <-time.After(timeout):
+			t.Fatal("expected StopAgent to return once the agent stopped")
+		}
+	})
 }
 
 func doesPathEventuallyExist(t *testing.T, path string) (bool, error) {
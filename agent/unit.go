@@ -0,0 +1,125 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/agent/launcher"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+// AgentArgsEnv is the environment variable in the EnvironmentFile of the agent
+// unit containing the agent arguments such as the port, state directory, and
+// TLS certificates.
+const AgentArgsEnv = "GPUPGRADE_AGENT_ARGS"
+
+// Unit returns a systemd unit that runs the agent as the user with the
+// arguments from the environment file. The agent is started by its socket on
+// the first connection from the hub. The agent signals readiness with
+// Type=notify and finishes any in-flight requests when stopped with SIGTERM.
+// KillMode=mixed sends SIGTERM only to the agent so that the commands it runs
+// such as rsync are not interrupted, while anything still running after
+// TimeoutStopSec is killed.
+func Unit(gpupgradePath string, user string, environmentFile string) string {
+	return fmt.Sprintf(`[Unit]
+Description=gpupgrade agent
+Requires=%[1]s
+After=network-online.target %[1]s
+
+[Service]
+Type=notify
+User=%[2]s
+EnvironmentFile=%[3]s
+ExecStart=%[4]s agent
+KillSignal=SIGTERM
+KillMode=mixed
+TimeoutStopSec=30min
+Restart=on-failure
+
+[Install]
+Also=%[1]s
+`, launcher.DefaultSocket, user, quoteUnitArg(environmentFile), quoteUnitArg(gpupgradePath))
+}
+
+// Socket returns the systemd socket unit which starts the agent unit when the
+// hub connects to the port.
+func Socket(listenAddress string, port int) string {
+	listen := strconv.Itoa(port)
+	if listenAddress != "" {
+		listen = net.JoinHostPort(listenAddress, listen)
+	}
+
+	return fmt.Sprintf(`[Unit]
+Description=gpupgrade agent socket
+
+[Socket]
+ListenStream=%s
+Service=%s
+
+[Install]
+WantedBy=sockets.target
+`, listen, launcher.DefaultUnit)
+}
+
+// Environment returns the contents of the environment file of the agent unit
+// which passes the agent arguments in AgentArgsEnv.
+func Environment(agentArgs []string) string {
+	value := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(utils.ShellJoin(agentArgs...))
+	return fmt.Sprintf("%s=\"%s\"\n", AgentArgsEnv, value)
+}
+
+func quoteUnitArg(arg string) string {
+	if arg == "" || strings.ContainsAny(arg, " \t\"'\\") {
+		return strconv.Quote(arg)
+	}
+
+	return arg
+}
+
+// InstallUnit writes the agent unit and its socket to the directory, and the
+// agent arguments to the environment file. It returns the paths written.
+func InstallUnit(dir string, environmentFile string, user string, listenAddress string, port int, agentArgs []string) ([]string, error) {
+	if listenAddress != "" && net.ParseIP(listenAddress) == nil {
+		return nil, xerrors.Errorf("listen address %q must be an IP address to be used by the socket unit", listenAddress)
+	}
+
+	gpupgradePath, err := utils.GetGpupgradePath()
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(filepath.Dir(environmentFile), 0755)
+	if err != nil {
+		return nil, xerrors.Errorf("create environment file directory: %w", err)
+	}
+
+	files := []struct {
+		path     string
+		contents string
+	}{
+		{environmentFile, Environment(agentArgs)},
+		{filepath.Join(dir, launcher.DefaultSocket), Socket(listenAddress, port)},
+		{filepath.Join(dir, launcher.DefaultUnit), Unit(gpupgradePath, user, environmentFile)},
+	}
+
+	var paths []string
+	for _, file := range files {
+		err = os.WriteFile(file.path, []byte(file.contents), 0644)
+		if err != nil {
+			return nil, xerrors.Errorf("write unit: %w", err)
+		}
+
+		paths = append(paths, file.path)
+	}
+
+	return paths, nil
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/agent"
+	"github.com/greenplum-db/gpupgrade/agent/launcher"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestUnit(t *testing.T) {
+	t.Run("runs the agent as the user with the arguments from the environment file", func(t *testing.T) {
+		unit := agent.Unit("/usr/local/gpupgrade/gpupgrade", "gpadmin", "/etc/gpupgrade/agent.env")
+
+		expected := []string{
+			"Requires=gpupgrade-agent.socket\n",
+			"Type=notify\n",
+			"User=gpadmin\n",
+			"EnvironmentFile=/etc/gpupgrade/agent.env\n",
+			"ExecStart=/usr/local/gpupgrade/gpupgrade agent\n",
+			"KillSignal=SIGTERM\n",
+			"KillMode=mixed\n",
+			"TimeoutStopSec=30min\n",
+			"Also=gpupgrade-agent.socket\n",
+		}
+
+		for _, line := range expected {
+			if !strings.Contains(unit, line) {
+				t.Errorf("expected unit %q to contain %q", unit, line)
+			}
+		}
+	})
+}
+
+func TestSocket(t *testing.T) {
+	cases := []struct {
+		name          string
+		listenAddress string
+		expected      string
+	}{
+		{
+			name:     "listens on the port on all interfaces",
+			expected: "ListenStream=6416\n",
+		},
+		{
+			name:          "listens on the listen address",
+			listenAddress: "10.0.0.1",
+			expected:      "ListenStream=10.0.0.1:6416\n",
+		},
+		{
+			name:          "listens on an IPv6 listen address",
+			listenAddress: "fe80::1",
+			expected:      "ListenStream=[fe80::1]:6416\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			socket := agent.Socket(c.listenAddress, 6416)
+
+			for _, line := range []string{c.expected, "Service=gpupgrade-agent.service\n", "WantedBy=sockets.target\n"} {
+				if !strings.Contains(socket, line) {
+					t.Errorf("expected socket %q to contain %q", socket, line)
+				}
+			}
+		})
+	}
+}
+
+func TestEnvironment(t *testing.T) {
+	t.Run("quotes the agent arguments", func(t *testing.T) {
+		env := agent.Environment([]string{"--port", "6416", "--state-directory", `/home/gpadmin/my "state"`})
+
+		expected := `GPUPGRADE_AGENT_ARGS="'--port' '6416' '--state-directory' '/home/gpadmin/my \"state\"'"` + "\n"
+		if env != expected {
+			t.Errorf("got %q want %q", env, expected)
+		}
+	})
+}
+
+func TestInstallUnit(t *testing.T) {
+	t.Run("writes the units to the directory and the arguments to the environment file", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		environmentFile := filepath.Join(dir, "gpupgrade", "agent.env")
+		paths, err := agent.InstallUnit(dir, environmentFile, "gpadmin", "10.0.0.1", 6416, []string{"--port", "6416"})
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := []string{environmentFile, filepath.Join(dir, launcher.DefaultSocket), filepath.Join(dir, launcher.DefaultUnit)}
+		if !reflect.DeepEqual(paths, expected) {
+			t.Errorf("got paths %q want %q", paths, expected)
+		}
+
+		contents := testutils.MustReadFile(t, filepath.Join(dir, launcher.DefaultUnit))
+		if !strings.Contains(contents, "User=gpadmin\n") || !strings.Contains(contents, "EnvironmentFile="+environmentFile+"\n") {
+			t.Errorf("got unit %q want it to run as gpadmin with environment file %q", contents, environmentFile)
+		}
+
+		contents = testutils.MustReadFile(t, filepath.Join(dir, launcher.DefaultSocket))
+		if !strings.Contains(contents, "ListenStream=10.0.0.1:6416\n") {
+			t.Errorf("got socket %q want it to listen on 10.0.0.1:6416", contents)
+		}
+
+		contents = testutils.MustReadFile(t, environmentFile)
+		if contents != `GPUPGRADE_AGENT_ARGS="'--port' '6416'"`+"\n" {
+			t.Errorf("got environment file %q", contents)
+		}
+	})
+
+	t.Run("errors when the listen address is not an IP address", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		_, err := agent.InstallUnit(dir, filepath.Join(dir, "agent.env"), "gpadmin", "localhost", 6416, nil)
+		if err == nil || !strings.Contains(err.Error(), "must be an IP address") {
+			t.Errorf("got error %v want it to require an IP address", err)
+		}
+	})
+
+	t.Run("errors when the directory does not exist", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		_, err := agent.InstallUnit(filepath.Join(dir, "does-not-exist"), filepath.Join(dir, "agent.env"), "gpadmin", "", 6416, nil)
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("got error %#v want a not exist error", err)
+		}
+	})
+}
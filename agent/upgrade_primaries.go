@@ -4,13 +4,17 @@
 package agent
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"golang.org/x/xerrors"
 
@@ -22,32 +26,58 @@ import (
 	"github.com/greenplum-db/gpupgrade/utils/rsync"
 )
 
-func (s *Server) UpgradePrimaries(ctx context.Context, req *idl.UpgradePrimariesRequest) (*idl.UpgradePrimariesReply, error) {
+func (s *Server) UpgradePrimaries(req *idl.UpgradePrimariesRequest, stream idl.Agent_UpgradePrimariesServer) error {
 	log.Printf("starting %s", req.GetAction())
 
-	err := upgradePrimariesInParallel(req.GetOpts())
-	if err != nil {
-		return &idl.UpgradePrimariesReply{}, err
-	}
-
-	return &idl.UpgradePrimariesReply{}, nil
+	return upgradePrimariesInParallel(stream.Context(), req.GetOpts(), req.GetMaxConcurrency(), &replySender{stream: stream})
 }
 
-func upgradePrimariesInParallel(opts []*idl.PgOptions) error {
+// upgradePrimariesInParallel streams the pg_upgrade output of each segment
+// followed by its result such that the hub can report progress. At most
+// maxConcurrency segments are upgraded at a time, or all of them when zero,
+// since each pg_upgrade may itself run multiple jobs.
+func upgradePrimariesInParallel(ctx context.Context, opts []*idl.PgOptions, maxConcurrency uint32, sender *replySender) error {
 	host, err := utils.System.Hostname()
 	if err != nil {
 		return err
 	}
 
+	limit := len(opts)
+	if maxConcurrency > 0 && int(maxConcurrency) < limit {
+		limit = int(maxConcurrency)
+	}
+
 	var wg sync.WaitGroup
 	errs := make(chan error, len(opts))
+	semaphore := make(chan struct{}, limit)
 
 	for _, opt := range opts {
 		wg.Add(1)
 		go func(host string, opt *idl.PgOptions) {
 			defer wg.Done()
 
-			errs <- upgradePrimarySegment(host, opt)
+			// Segments waiting to be upgraded are not started once the hub
+			// has gone away.
+			var duration time.Duration
+			var err error
+			select {
+			case semaphore <- struct{}{}:
+				duration, err = upgradePrimarySegment(ctx, host, opt, sender)
+				<-semaphore
+			case <-ctx.Done():
+				err = ctx.Err()
+			}
+			errs <- err
+
+			result := &idl.UpgradePrimariesReply_Result{
+				ContentID:       opt.GetContentID(),
+				DurationSeconds: duration.Seconds(),
+			}
+			if err != nil {
+				result.Error = err.Error()
+			}
+
+			sender.send(&idl.UpgradePrimariesReply{Contents: &idl.UpgradePrimariesReply_Result_{Result: result}})
 		}(host, opt)
 	}
 
@@ -61,29 +91,219 @@ func upgradePrimariesInParallel(opts []*idl.PgOptions) error {
 	return err
 }
 
-func upgradePrimarySegment(host string, opt *idl.PgOptions) error {
+// upgradePrimarySegment returns how long pg_upgrade ran for the segment.
+func upgradePrimarySegment(ctx context.Context, host string, opt *idl.PgOptions, sender *replySender) (time.Duration, error) {
 	if opt.GetAction() != idl.PgOptions_check {
-		err := restoreBackup(opt.GetBackupDir(), opt.GetNewDataDir())
+		err := restoreBackup(ctx, opt.GetBackupDir(), opt.GetNewDataDir())
 		if err != nil {
-			return xerrors.Errorf("restore backup of upgraded master data directory on host %s for content id %d: %w", host, opt.GetContentID(), err)
+			return 0, xerrors.Errorf("restore backup of upgraded master data directory on host %s for content id %d: %w", host, opt.GetContentID(), err)
 		}
 
-		err = RestoreTablespaces(opt.GetBackupDir(), opt.GetTablespaces(), opt.GetOldDBID(), opt.GetNewDataDir())
+		err = RestoreTablespaces(ctx, opt.GetBackupDir(), opt.GetTablespaces(), opt.GetOldDBID(), opt.GetNewDataDir())
 		if err != nil {
-			return xerrors.Errorf("restore tablespace on host %s for content id %d: %w", host, opt.GetContentID(), err)
+			return 0, xerrors.Errorf("restore tablespace on host %s for content id %d: %w", host, opt.GetContentID(), err)
 		}
 	}
 
-	err := upgrade.Run(io.Discard, io.Discard, opt)
+	logFile, err := openPgUpgradeLogFile(opt)
 	if err != nil {
-		return xerrors.Errorf("%s primary on host %s with content %d: %w", opt.GetAction(), host, opt.GetContentID(), err)
+		return 0, xerrors.Errorf("%s primary on host %s with content %d: %w", opt.GetAction(), host, opt.GetContentID(), err)
 	}
+	defer func() {
+		if cErr := logFile.Close(); cErr != nil {
+			log.Printf("close %q: %v", logFile.Name(), cErr)
+		}
+	}()
 
-	return nil
+	stdout := &progressWriter{contentID: opt.GetContentID(), sender: sender}
+	stderr := &progressWriter{contentID: opt.GetContentID(), sender: sender}
+	tail := &tailWriter{lines: outputTailLines}
+
+	// The log file and tail are shared by stdout and stderr which are written
+	// concurrently.
+	output := &syncWriter{writer: io.MultiWriter(logFile, tail)}
+
+	start := time.Now()
+	err = upgrade.Run(ctx, io.MultiWriter(output, stdout), io.MultiWriter(output, stderr), opt)
+	duration := time.Since(start)
+
+	stdout.flush()
+	stderr.flush()
+
+	if err != nil {
+		err = xerrors.Errorf("%s primary on host %s with content %d: %w", opt.GetAction(), host, opt.GetContentID(), err)
+		if tail.String() != "" {
+			err = OutputErr{Err: err, LogFile: logFile.Name(), Tail: tail.String()}
+		}
+
+		return duration, err
+	}
+
+	return duration, nil
+}
+
+// outputTailLines is the number of lines of pg_upgrade output included in the
+// error when a segment fails.
+const outputTailLines = 20
+
+// maxTailBytes bounds the tail when output is not split into lines.
+const maxTailBytes = 64 * 1024
+
+// openPgUpgradeLogFile opens the log file capturing the pg_upgrade output of
+// the segment. Each run is appended with a header such that retries can be
+// told apart.
+func openPgUpgradeLogFile(opt *idl.PgOptions) (*os.File, error) {
+	path, err := utils.GetPgUpgradeLogFile(opt.GetRole(), opt.GetContentID())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := utils.System.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = fmt.Fprintf(file, "\n%s pg_upgrade %s for content %d\n", time.Now().Format(time.RFC3339), opt.GetAction(), opt.GetContentID())
+	if err != nil {
+		return nil, errorlist.Append(err, file.Close())
+	}
+
+	return file, nil
+}
+
+// OutputErr attaches the last lines of pg_upgrade output to the error of a
+// failed segment since the log file is on the segment host.
+type OutputErr struct {
+	Err     error
+	LogFile string
+	Tail    string
+}
+
+func (e OutputErr) Error() string {
+	return fmt.Sprintf("%v\n\nLast lines of %q:\n%s", e.Err, e.LogFile, e.Tail)
+}
+
+func (e OutputErr) Unwrap() error {
+	return e.Err
+}
+
+// syncWriter serializes writes to the underlying writer.
+type syncWriter struct {
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.writer.Write(p)
+}
+
+// tailWriter retains the last lines written to it.
+type tailWriter struct {
+	lines int
+	buf   []byte
+}
+
+func (w *tailWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	if len(w.buf) > maxTailBytes {
+		w.buf = w.buf[len(w.buf)-maxTailBytes:]
+	}
+
+	// Keep a trailing partial line in addition to the last complete lines.
+	count := 0
+	for i := len(w.buf) - 2; i >= 0; i-- {
+		if w.buf[i] != '\n' {
+			continue
+		}
+
+		count++
+		if count == w.lines {
+			w.buf = w.buf[i+1:]
+			break
+		}
+	}
+
+	return len(p), nil
+}
+
+func (w *tailWriter) String() string {
+	return strings.TrimRight(string(w.buf), "\n")
+}
+
+// replySender serializes sends from each segment onto the stream. Since the
+// hub may close the stream at any point, errors are logged and otherwise
+// ignored. After the first send error, no more attempts are made.
+type replySender struct {
+	mutex  sync.Mutex
+	stream idl.Agent_UpgradePrimariesServer
+}
+
+func (s *replySender) send(reply *idl.UpgradePrimariesReply) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.stream == nil {
+		return
+	}
+
+	if err := s.stream.Send(reply); err != nil {
+		log.Printf("halting upgrade primaries stream: %v", err)
+		s.stream = nil
+	}
+}
+
+// progressWriter sends each line of pg_upgrade output for a segment. Lines
+// are terminated by either a newline or the carriage return pg_upgrade uses
+// to redraw its progress.
+type progressWriter struct {
+	contentID int32
+	sender    *replySender
+	buf       []byte
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+
+	for {
+		i := bytes.IndexAny(w.buf, "\r\n")
+		if i < 0 {
+			break
+		}
+
+		w.sendLine(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// flush sends any remaining output not terminated by a newline.
+func (w *progressWriter) flush() {
+	w.sendLine(string(w.buf))
+	w.buf = nil
+}
+
+func (w *progressWriter) sendLine(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+
+	w.sender.send(&idl.UpgradePrimariesReply{Contents: &idl.UpgradePrimariesReply_Progress_{
+		Progress: &idl.UpgradePrimariesReply_Progress{ContentID: w.contentID, Line: line},
+	}})
 }
 
-func restoreBackup(backupDir string, newDataDir string) error {
+func restoreBackup(ctx context.Context, backupDir string, newDataDir string) error {
 	options := []rsync.Option{
+		rsync.WithContext(ctx),
 		rsync.WithSources(utils.GetCoordinatorPostUpgradeBackupDir(backupDir) + string(os.PathSeparator)),
 		rsync.WithDestination(newDataDir),
 		rsync.WithOptions("--archive", "--delete"),
@@ -100,7 +320,7 @@ func restoreBackup(backupDir string, newDataDir string) error {
 	return rsync.Rsync(options...)
 }
 
-func RestoreTablespaces(backupDir string, tablespaces map[int32]*idl.TablespaceInfo, oldDBID string, newDataDir string) error {
+func RestoreTablespaces(ctx context.Context, backupDir string, tablespaces map[int32]*idl.TablespaceInfo, oldDBID string, newDataDir string) error {
 	dbid, err := strconv.Atoi(oldDBID)
 	if err != nil {
 		return err
@@ -115,6 +335,7 @@ func RestoreTablespaces(backupDir string, tablespaces map[int32]*idl.TablespaceI
 		sourceDir := greenplum.GetCoordinatorTablespaceLocation(utils.GetTablespaceBackupDir(backupDir), int(oid)) + string(os.PathSeparator)
 
 		options := []rsync.Option{
+			rsync.WithContext(ctx),
 			rsync.WithSources(sourceDir),
 			rsync.WithDestination(targetDir),
 			rsync.WithOptions("--archive", "--delete"),
@@ -9,17 +9,23 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"os/user"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
 
 	"github.com/greenplum-db/gpupgrade/agent"
 	"github.com/greenplum-db/gpupgrade/greenplum"
 	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
 	"github.com/greenplum-db/gpupgrade/testutils/exectest"
 	"github.com/greenplum-db/gpupgrade/testutils/testlog"
 	"github.com/greenplum-db/gpupgrade/upgrade"
@@ -41,14 +47,115 @@ func TestUpgradePrimaries(t *testing.T) {
 
 		opts := []*idl.PgOptions{{
 			Role:          greenplum.PrimaryRole,
+			ContentID:     1,
 			Action:        idl.PgOptions_check,
 			TargetVersion: "6.0.0",
 		}}
 
-		_, err := agentServer.UpgradePrimaries(context.Background(), &idl.UpgradePrimariesRequest{Opts: opts})
+		stream := &upgradePrimariesStream{}
+		err := agentServer.UpgradePrimaries(&idl.UpgradePrimariesRequest{Opts: opts}, stream)
 		if err != nil {
 			t.Fatalf("unexpected error %+v", err)
 		}
+
+		if len(stream.replies) != 1 {
+			t.Fatalf("got %d replies want 1", len(stream.replies))
+		}
+
+		result := stream.replies[0].GetResult()
+		if result.GetContentID() != 1 || result.GetError() != "" {
+			t.Errorf("got result %v want a successful result for content 1", result)
+		}
+	})
+
+	t.Run("streams pg_upgrade output followed by the result of each segment", func(t *testing.T) {
+		upgrade.SetPgUpgradeCommand(exectest.NewCommand(agent.PgUpgradeProgress))
+		defer upgrade.ResetPgUpgradeCommand()
+
+		opts := []*idl.PgOptions{{
+			Role:          greenplum.PrimaryRole,
+			ContentID:     3,
+			Action:        idl.PgOptions_check,
+			TargetVersion: "6.0.0",
+		}}
+
+		stream := &upgradePrimariesStream{}
+		err := agentServer.UpgradePrimaries(&idl.UpgradePrimariesRequest{Opts: opts}, stream)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		var lines []string
+		for _, reply := range stream.replies[:len(stream.replies)-1] {
+			progress := reply.GetProgress()
+			if progress.GetContentID() != 3 {
+				t.Errorf("got content %d want 3", progress.GetContentID())
+			}
+
+			lines = append(lines, progress.GetLine())
+		}
+
+		sort.Strings(lines)
+		expected := []string{"Performing Upgrade", "copying 1/2", "copying 2/2", "warning"}
+		if !reflect.DeepEqual(lines, expected) {
+			t.Errorf("got lines %q want %q", lines, expected)
+		}
+
+		result := stream.replies[len(stream.replies)-1].GetResult()
+		if result.GetContentID() != 3 || result.GetError() != "" {
+			t.Errorf("got result %v want a successful result for content 3", result)
+		}
+	})
+
+	t.Run("upgrades at most the maximum number of segments concurrently", func(t *testing.T) {
+		upgrade.SetPgUpgradeCommand(exectest.NewCommand(agent.PgUpgradeProgress))
+		defer upgrade.ResetPgUpgradeCommand()
+
+		var opts []*idl.PgOptions
+		for contentID := int32(0); contentID < 4; contentID++ {
+			opts = append(opts, &idl.PgOptions{
+				Role:          greenplum.PrimaryRole,
+				ContentID:     contentID,
+				Action:        idl.PgOptions_check,
+				TargetVersion: "6.0.0",
+			})
+		}
+
+		stream := &upgradePrimariesStream{}
+		err := agentServer.UpgradePrimaries(&idl.UpgradePrimariesRequest{Opts: opts, MaxConcurrency: 1}, stream)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		// With a single segment at a time the replies of each segment are
+		// not interleaved with those of another.
+		running := int32(-1)
+		results := 0
+		for _, reply := range stream.replies {
+			if progress := reply.GetProgress(); progress != nil {
+				if running == -1 {
+					running = progress.GetContentID()
+				}
+
+				if progress.GetContentID() != running {
+					t.Fatalf("got progress for content %d while content %d is running", progress.GetContentID(), running)
+				}
+
+				continue
+			}
+
+			result := reply.GetResult()
+			if running != -1 && result.GetContentID() != running {
+				t.Fatalf("got result for content %d while content %d is running", result.GetContentID(), running)
+			}
+
+			running = -1
+			results++
+		}
+
+		if results != len(opts) {
+			t.Errorf("got %d results want %d", results, len(opts))
+		}
 	})
 
 	t.Run("restores backup and tablespaces when not calling --check", func(t *testing.T) {
@@ -85,7 +192,7 @@ func TestUpgradePrimaries(t *testing.T) {
 			},
 		}
 
-		_, err := agentServer.UpgradePrimaries(context.Background(), &idl.UpgradePrimariesRequest{Opts: opts})
+		err := agentServer.UpgradePrimaries(&idl.UpgradePrimariesRequest{Opts: opts}, &upgradePrimariesStream{})
 		if err != nil {
 			t.Fatalf("unexpected error %+v", err)
 		}
@@ -127,7 +234,7 @@ func TestUpgradePrimaries(t *testing.T) {
 			TargetVersion: "6.0.0",
 		}}
 
-		_, err := agentServer.UpgradePrimaries(context.Background(), &idl.UpgradePrimariesRequest{Opts: opts})
+		err := agentServer.UpgradePrimaries(&idl.UpgradePrimariesRequest{Opts: opts}, &upgradePrimariesStream{})
 		if err != nil {
 			t.Fatalf("unexpected error %+v", err)
 		}
@@ -158,7 +265,7 @@ func TestUpgradePrimaries(t *testing.T) {
 			},
 		}
 
-		_, err := agentServer.UpgradePrimaries(context.Background(), &idl.UpgradePrimariesRequest{Opts: opts})
+		err := agentServer.UpgradePrimaries(&idl.UpgradePrimariesRequest{Opts: opts}, &upgradePrimariesStream{})
 		var exitErr *exec.ExitError
 		if !errors.As(err, &exitErr) {
 			t.Fatalf("got error %T, want type %T", err, exitErr)
@@ -190,7 +297,7 @@ func TestUpgradePrimaries(t *testing.T) {
 			},
 		}
 
-		_, err := agentServer.UpgradePrimaries(context.Background(), &idl.UpgradePrimariesRequest{Opts: opts})
+		err := agentServer.UpgradePrimaries(&idl.UpgradePrimariesRequest{Opts: opts}, &upgradePrimariesStream{})
 		var linkErr *os.LinkError
 		if !errors.As(err, &linkErr) {
 			t.Errorf("got error %T, want type %T", err, linkErr)
@@ -213,7 +320,7 @@ func TestUpgradePrimaries(t *testing.T) {
 			{Role: greenplum.PrimaryRole, Action: idl.PgOptions_upgrade, TargetVersion: "6.0.0", ContentID: 2, OldDBID: "2"},
 		}
 
-		_, err := agentServer.UpgradePrimaries(context.Background(), &idl.UpgradePrimariesRequest{Opts: opts})
+		err := agentServer.UpgradePrimaries(&idl.UpgradePrimariesRequest{Opts: opts}, &upgradePrimariesStream{})
 		var errs errorlist.Errors
 		if !xerrors.As(err, &errs) {
 			t.Errorf("error %T does not contain type %T", err, errs)
@@ -231,6 +338,217 @@ func TestUpgradePrimaries(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("writes the pg_upgrade output of each segment to a log file", func(t *testing.T) {
+		homeDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, homeDir)
+
+		utils.System.Current = func() (*user.User, error) {
+			return &user.User{HomeDir: homeDir}, nil
+		}
+		defer utils.ResetSystemFunctions()
+
+		upgrade.SetPgUpgradeCommand(exectest.NewCommand(agent.PgUpgradeProgress))
+		defer upgrade.ResetPgUpgradeCommand()
+
+		opts := []*idl.PgOptions{
+			{Role: greenplum.PrimaryRole, Action: idl.PgOptions_check, TargetVersion: "6.0.0", ContentID: 1},
+			{Role: greenplum.PrimaryRole, Action: idl.PgOptions_check, TargetVersion: "6.0.0", ContentID: 2},
+		}
+
+		// run twice to ensure each run is appended
+		for i := 0; i < 2; i++ {
+			err := agentServer.UpgradePrimaries(&idl.UpgradePrimariesRequest{Opts: opts}, &upgradePrimariesStream{})
+			if err != nil {
+				t.Fatalf("unexpected error %+v", err)
+			}
+		}
+
+		for _, opt := range opts {
+			logFile, err := utils.GetPgUpgradeLogFile(opt.GetRole(), opt.GetContentID())
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if filepath.Dir(logFile) != filepath.Join(homeDir, "gpAdminLogs", "gpupgrade") {
+				t.Errorf("got log file %q want it in the log directory", logFile)
+			}
+
+			contents := testutils.MustReadFile(t, logFile)
+			header := fmt.Sprintf("pg_upgrade check for content %d\n", opt.GetContentID())
+			if strings.Count(contents, header) != 2 {
+				t.Errorf("expected %q to contain %q twice", contents, header)
+			}
+
+			for _, expected := range []string{"Performing Upgrade\n", "copying 2/2\n", "warning"} {
+				if !strings.Contains(contents, expected) {
+					t.Errorf("expected %q to contain %q", contents, expected)
+				}
+			}
+		}
+	})
+
+	t.Run("attaches the last lines of pg_upgrade output to the error of a failed segment", func(t *testing.T) {
+		homeDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, homeDir)
+
+		utils.System.Current = func() (*user.User, error) {
+			return &user.User{HomeDir: homeDir}, nil
+		}
+		utils.System.Hostname = func() (string, error) {
+			return "sdw1", nil
+		}
+		defer utils.ResetSystemFunctions()
+
+		upgrade.SetPgUpgradeCommand(exectest.NewCommand(agent.FailedPgUpgrade))
+		defer upgrade.ResetPgUpgradeCommand()
+
+		opts := []*idl.PgOptions{
+			{Role: greenplum.PrimaryRole, Action: idl.PgOptions_check, TargetVersion: "6.0.0", ContentID: 1},
+		}
+
+		stream := &upgradePrimariesStream{}
+		err := agentServer.UpgradePrimaries(&idl.UpgradePrimariesRequest{Opts: opts}, stream)
+
+		var outputErr agent.OutputErr
+		if !errors.As(err, &outputErr) {
+			t.Fatalf("got error %#v want %T", err, outputErr)
+		}
+
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			t.Errorf("got error %#v want %T", err, exitErr)
+		}
+
+		expectedLogFile, err := utils.GetPgUpgradeLogFile(greenplum.PrimaryRole, 1)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if outputErr.LogFile != expectedLogFile {
+			t.Errorf("got log file %q want %q", outputErr.LogFile, expectedLogFile)
+		}
+
+		lines := strings.Split(outputErr.Tail, "\n")
+		if len(lines) != 20 {
+			t.Errorf("got %d lines want 20 in tail %q", len(lines), outputErr.Tail)
+		}
+
+		if strings.Contains(outputErr.Tail, "line 10\n") || !strings.Contains(outputErr.Tail, "line 30") || !strings.Contains(outputErr.Tail, "fatal error") {
+			t.Errorf("got tail %q want the last lines of output", outputErr.Tail)
+		}
+
+		result := stream.replies[len(stream.replies)-1].GetResult()
+		if !strings.Contains(result.GetError(), "line 30") {
+			t.Errorf("expected result error %q to contain the last lines of output", result.GetError())
+		}
+	})
+
+	t.Run("sends the error of each failed segment", func(t *testing.T) {
+		utils.System.Hostname = func() (string, error) {
+			return "sdw1", nil
+		}
+		defer utils.ResetSystemFunctions()
+
+		upgrade.SetPgUpgradeCommand(exectest.NewCommand(agent.FailedMain))
+		defer upgrade.ResetPgUpgradeCommand()
+
+		opts := []*idl.PgOptions{
+			{Role: greenplum.PrimaryRole, Action: idl.PgOptions_check, TargetVersion: "6.0.0", ContentID: 1, OldDBID: "1"},
+		}
+
+		stream := &upgradePrimariesStream{}
+		err := agentServer.UpgradePrimaries(&idl.UpgradePrimariesRequest{Opts: opts}, stream)
+		if err == nil {
+			t.Fatalf("expected an error")
+		}
+
+		result := stream.replies[len(stream.replies)-1].GetResult()
+		expected := "check primary on host sdw1 with content 1: exit status 1"
+		if result.GetContentID() != 1 || result.GetError() != expected {
+			t.Errorf("got result %v want content 1 with error %q", result, expected)
+		}
+	})
+
+	t.Run("kills pg_upgrade and does not start waiting segments when the hub goes away", func(t *testing.T) {
+		upgrade.SetPgUpgradeCommand(exectest.NewCommand(agent.HangingPgUpgrade))
+		defer upgrade.ResetPgUpgradeCommand()
+
+		opts := []*idl.PgOptions{
+			{Role: greenplum.PrimaryRole, Action: idl.PgOptions_check, TargetVersion: "6.0.0", ContentID: 1, OldDBID: "2"},
+			{Role: greenplum.PrimaryRole, Action: idl.PgOptions_check, TargetVersion: "6.0.0", ContentID: 2, OldDBID: "3"},
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			cancel()
+		}()
+
+		start := time.Now()
+		stream := &upgradePrimariesStream{ctx: ctx}
+		err := agentServer.UpgradePrimaries(&idl.UpgradePrimariesRequest{Opts: opts, MaxConcurrency: 1}, stream)
+
+		var errs errorlist.Errors
+		if !errors.As(err, &errs) {
+			t.Fatalf("error %#v does not contain type %T", err, errs)
+		}
+
+		for _, err := range errs {
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("got error %#v want %#v", err, context.Canceled)
+			}
+		}
+
+		if elapsed := time.Since(start); elapsed > 30*time.Second {
+			t.Errorf("took %s want pg_upgrade to be killed", elapsed)
+		}
+
+		// Only the running segment was started and killed while the other
+		// segment was never started.
+		var killed, notStarted int
+		for _, reply := range stream.replies {
+			result := reply.GetResult()
+			switch {
+			case result == nil:
+				continue
+			case strings.Contains(result.GetError(), "signal: killed"):
+				killed++
+			case result.GetError() == context.Canceled.Error():
+				notStarted++
+			default:
+				t.Errorf("got result %v want a canceled segment", result)
+			}
+		}
+
+		if killed != 1 || notStarted != 1 {
+			t.Errorf("got %d killed and %d not started segments want 1 of each", killed, notStarted)
+		}
+	})
+}
+
+// upgradePrimariesStream records the replies sent by UpgradePrimaries.
+type upgradePrimariesStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	mutex   sync.Mutex
+	replies []*idl.UpgradePrimariesReply
+}
+
+func (s *upgradePrimariesStream) Context() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+
+	return s.ctx
+}
+
+func (s *upgradePrimariesStream) Send(reply *idl.UpgradePrimariesReply) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.replies = append(s.replies, reply)
+	return nil
 }
 
 func TestRestoreTablespaces(t *testing.T) {
@@ -252,7 +570,7 @@ func TestRestoreTablespaces(t *testing.T) {
 			1664: {Location: "/tmp/primary1/1664", UserDefined: true},
 		}
 
-		err := agent.RestoreTablespaces(backupDir, tablespaces, "2", "/new/data/dir")
+		err := agent.RestoreTablespaces(context.Background(), backupDir, tablespaces, "2", "/new/data/dir")
 		if err != nil {
 			t.Fatalf("unexpected error %+v", err)
 		}
@@ -292,14 +610,14 @@ func TestRestoreTablespaces(t *testing.T) {
 			1664: {Location: "/tmp/primary1/1664", UserDefined: true},
 		}
 
-		err := agent.RestoreTablespaces(backupDir, tablespaces, "2", "/new/data/dir")
+		err := agent.RestoreTablespaces(context.Background(), backupDir, tablespaces, "2", "/new/data/dir")
 		if err != nil {
 			t.Fatalf("unexpected error %+v", err)
 		}
 	})
 
 	t.Run("errors when parse dbID fails", func(t *testing.T) {
-		err := agent.RestoreTablespaces(backupDir, nil, "", "")
+		err := agent.RestoreTablespaces(context.Background(), backupDir, nil, "", "")
 		var expected *strconv.NumError
 		if !errors.As(err, &expected) {
 			t.Errorf("got error type %T want %T", err, expected)
@@ -314,7 +632,7 @@ func TestRestoreTablespaces(t *testing.T) {
 			1664: {Location: "/tmp/primary1/1664", UserDefined: true},
 		}
 
-		err := agent.RestoreTablespaces(backupDir, tablespaces, "2", "/new/data/dir")
+		err := agent.RestoreTablespaces(context.Background(), backupDir, tablespaces, "2", "/new/data/dir")
 		var expected rsync.RsyncError
 		if !errors.As(err, &expected) {
 			t.Errorf("got error type %T want %T", err, expected)
@@ -335,7 +653,7 @@ func TestRestoreTablespaces(t *testing.T) {
 			1664: {Location: "/tmp/primary1/1664", UserDefined: true},
 		}
 
-		err := agent.RestoreTablespaces(backupDir, tablespaces, "2", "/new/data/dir")
+		err := agent.RestoreTablespaces(context.Background(), backupDir, tablespaces, "2", "/new/data/dir")
 		if !errors.Is(err, expected) {
 			t.Errorf("got %#v want %#v", err, expected.Error())
 		}
@@ -360,7 +678,7 @@ func TestRestoreTablespaces(t *testing.T) {
 			1664: {Location: "/tmp/primary1/1664", UserDefined: true},
 		}
 
-		err := agent.RestoreTablespaces(backupDir, tablespaces, "2", "/new/data/dir")
+		err := agent.RestoreTablespaces(context.Background(), backupDir, tablespaces, "2", "/new/data/dir")
 		if !errors.Is(err, expected) {
 			t.Errorf("got %#v want %#v", err, expected)
 		}
@@ -390,7 +708,7 @@ func TestRestoreTablespaces(t *testing.T) {
 			1664: {Location: "/tmp/primary1/1664", UserDefined: true},
 		}
 
-		err := agent.RestoreTablespaces(backupDir, tablespaces, "2", "/new/data/dir")
+		err := agent.RestoreTablespaces(context.Background(), backupDir, tablespaces, "2", "/new/data/dir")
 		if !errors.Is(err, expected) {
 			t.Errorf("got %#v want %#v", err, expected)
 		}
@@ -353,6 +353,55 @@ __gpupgrade_handle_word()
     __gpupgrade_handle_word
 }
 
+_gpupgrade_agents_help()
+{
+    last_command="gpupgrade_agents_help"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_gpupgrade_agents()
+{
+    last_command="gpupgrade_agents"
+
+    command_aliases=()
+
+    commands=()
+    commands+=("help")
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--?")
+    flags+=("-?")
+    local_nonpersistent_flags+=("--?")
+    local_nonpersistent_flags+=("-?")
+    flags+=("--format=")
+    two_word_flags+=("--format")
+    local_nonpersistent_flags+=("--format")
+    local_nonpersistent_flags+=("--format=")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
 _gpupgrade_apply_help()
 {
     last_command="gpupgrade_apply_help"
@@ -400,6 +449,14 @@ _gpupgrade_apply()
     two_word_flags+=("--input-dir")
     local_nonpersistent_flags+=("--input-dir")
     local_nonpersistent_flags+=("--input-dir=")
+    flags+=("--mode=")
+    two_word_flags+=("--mode")
+    local_nonpersistent_flags+=("--mode")
+    local_nonpersistent_flags+=("--mode=")
+    flags+=("--pg-upgrade-jobs=")
+    two_word_flags+=("--pg-upgrade-jobs")
+    local_nonpersistent_flags+=("--pg-upgrade-jobs")
+    local_nonpersistent_flags+=("--pg-upgrade-jobs=")
     flags+=("--phase=")
     two_word_flags+=("--phase")
     local_nonpersistent_flags+=("--phase")
@@ -414,6 +471,130 @@ _gpupgrade_apply()
     noun_aliases=()
 }
 
+_gpupgrade_break-step-lock_help()
+{
+    last_command="gpupgrade_break-step-lock_help"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_gpupgrade_break-step-lock()
+{
+    last_command="gpupgrade_break-step-lock"
+
+    command_aliases=()
+
+    commands=()
+    commands+=("help")
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--?")
+    flags+=("-?")
+    local_nonpersistent_flags+=("--?")
+    local_nonpersistent_flags+=("-?")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_gpupgrade_check_help()
+{
+    last_command="gpupgrade_check_help"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_gpupgrade_check()
+{
+    last_command="gpupgrade_check"
+
+    command_aliases=()
+
+    commands=()
+    commands+=("help")
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--?")
+    flags+=("-?")
+    local_nonpersistent_flags+=("--?")
+    local_nonpersistent_flags+=("-?")
+    flags+=("--agent-launcher=")
+    two_word_flags+=("--agent-launcher")
+    local_nonpersistent_flags+=("--agent-launcher")
+    local_nonpersistent_flags+=("--agent-launcher=")
+    flags+=("--disk-free-ratio=")
+    two_word_flags+=("--disk-free-ratio")
+    local_nonpersistent_flags+=("--disk-free-ratio")
+    local_nonpersistent_flags+=("--disk-free-ratio=")
+    flags+=("--file=")
+    two_word_flags+=("--file")
+    two_word_flags+=("-f")
+    local_nonpersistent_flags+=("--file")
+    local_nonpersistent_flags+=("--file=")
+    local_nonpersistent_flags+=("-f")
+    flags+=("--mode=")
+    two_word_flags+=("--mode")
+    local_nonpersistent_flags+=("--mode")
+    local_nonpersistent_flags+=("--mode=")
+    flags+=("--source-gphome=")
+    two_word_flags+=("--source-gphome")
+    local_nonpersistent_flags+=("--source-gphome")
+    local_nonpersistent_flags+=("--source-gphome=")
+    flags+=("--source-master-port=")
+    two_word_flags+=("--source-master-port")
+    local_nonpersistent_flags+=("--source-master-port")
+    local_nonpersistent_flags+=("--source-master-port=")
+    flags+=("--target-gphome=")
+    two_word_flags+=("--target-gphome")
+    local_nonpersistent_flags+=("--target-gphome")
+    local_nonpersistent_flags+=("--target-gphome=")
+    flags+=("--temp-port-range=")
+    two_word_flags+=("--temp-port-range")
+    local_nonpersistent_flags+=("--temp-port-range")
+    local_nonpersistent_flags+=("--temp-port-range=")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
 _gpupgrade_config_show_help()
 {
     last_command="gpupgrade_config_show_help"
@@ -529,6 +710,8 @@ _gpupgrade_execute()
     flags+=("-?")
     local_nonpersistent_flags+=("--?")
     local_nonpersistent_flags+=("-?")
+    flags+=("--dry-run")
+    local_nonpersistent_flags+=("--dry-run")
     flags+=("--parent-backup-dirs=")
     two_word_flags+=("--parent-backup-dirs")
     local_nonpersistent_flags+=("--parent-backup-dirs")
@@ -584,6 +767,8 @@ _gpupgrade_finalize()
     flags+=("-?")
     local_nonpersistent_flags+=("--?")
     local_nonpersistent_flags+=("-?")
+    flags+=("--dry-run")
+    local_nonpersistent_flags+=("--dry-run")
     flags+=("--verbose")
     flags+=("-v")
     local_nonpersistent_flags+=("--verbose")
@@ -710,10 +895,22 @@ _gpupgrade_initialize()
     flags+=("-?")
     local_nonpersistent_flags+=("--?")
     local_nonpersistent_flags+=("-?")
+    flags+=("--agent-launcher=")
+    two_word_flags+=("--agent-launcher")
+    local_nonpersistent_flags+=("--agent-launcher")
+    local_nonpersistent_flags+=("--agent-launcher=")
+    flags+=("--agent-listen-address=")
+    two_word_flags+=("--agent-listen-address")
+    local_nonpersistent_flags+=("--agent-listen-address")
+    local_nonpersistent_flags+=("--agent-listen-address=")
     flags+=("--agent-port=")
     two_word_flags+=("--agent-port")
     local_nonpersistent_flags+=("--agent-port")
     local_nonpersistent_flags+=("--agent-port=")
+    flags+=("--agent-rpc-timeouts=")
+    two_word_flags+=("--agent-rpc-timeouts")
+    local_nonpersistent_flags+=("--agent-rpc-timeouts")
+    local_nonpersistent_flags+=("--agent-rpc-timeouts=")
     flags+=("--disk-free-ratio=")
     two_word_flags+=("--disk-free-ratio")
     local_nonpersistent_flags+=("--disk-free-ratio")
@@ -728,10 +925,28 @@ _gpupgrade_initialize()
     local_nonpersistent_flags+=("--file")
     local_nonpersistent_flags+=("--file=")
     local_nonpersistent_flags+=("-f")
+    flags+=("--hub-listen-address=")
+    two_word_flags+=("--hub-listen-address")
+    local_nonpersistent_flags+=("--hub-listen-address")
+    local_nonpersistent_flags+=("--hub-listen-address=")
     flags+=("--hub-port=")
     two_word_flags+=("--hub-port")
     local_nonpersistent_flags+=("--hub-port")
     local_nonpersistent_flags+=("--hub-port=")
+    flags+=("--max-parallel-hosts=")
+    two_word_flags+=("--max-parallel-hosts")
+    local_nonpersistent_flags+=("--max-parallel-hosts")
+    local_nonpersistent_flags+=("--max-parallel-hosts=")
+    flags+=("--max-parallel-segments=")
+    two_word_flags+=("--max-parallel-segments")
+    local_nonpersistent_flags+=("--max-parallel-segments")
+    local_nonpersistent_flags+=("--max-parallel-segments=")
+    flags+=("--measure-disk-space")
+    local_nonpersistent_flags+=("--measure-disk-space")
+    flags+=("--metrics-port=")
+    two_word_flags+=("--metrics-port")
+    local_nonpersistent_flags+=("--metrics-port")
+    local_nonpersistent_flags+=("--metrics-port=")
     flags+=("--mode=")
     two_word_flags+=("--mode")
     local_nonpersistent_flags+=("--mode")
@@ -762,6 +977,34 @@ _gpupgrade_initialize()
     two_word_flags+=("--temp-port-range")
     local_nonpersistent_flags+=("--temp-port-range")
     local_nonpersistent_flags+=("--temp-port-range=")
+    flags+=("--tls-agent-certificate=")
+    two_word_flags+=("--tls-agent-certificate")
+    local_nonpersistent_flags+=("--tls-agent-certificate")
+    local_nonpersistent_flags+=("--tls-agent-certificate=")
+    flags+=("--tls-agent-key=")
+    two_word_flags+=("--tls-agent-key")
+    local_nonpersistent_flags+=("--tls-agent-key")
+    local_nonpersistent_flags+=("--tls-agent-key=")
+    flags+=("--tls-ca-certificate=")
+    two_word_flags+=("--tls-ca-certificate")
+    local_nonpersistent_flags+=("--tls-ca-certificate")
+    local_nonpersistent_flags+=("--tls-ca-certificate=")
+    flags+=("--tls-cli-certificate=")
+    two_word_flags+=("--tls-cli-certificate")
+    local_nonpersistent_flags+=("--tls-cli-certificate")
+    local_nonpersistent_flags+=("--tls-cli-certificate=")
+    flags+=("--tls-cli-key=")
+    two_word_flags+=("--tls-cli-key")
+    local_nonpersistent_flags+=("--tls-cli-key")
+    local_nonpersistent_flags+=("--tls-cli-key=")
+    flags+=("--tls-hub-certificate=")
+    two_word_flags+=("--tls-hub-certificate")
+    local_nonpersistent_flags+=("--tls-hub-certificate")
+    local_nonpersistent_flags+=("--tls-hub-certificate=")
+    flags+=("--tls-hub-key=")
+    two_word_flags+=("--tls-hub-key")
+    local_nonpersistent_flags+=("--tls-hub-key")
+    local_nonpersistent_flags+=("--tls-hub-key=")
     flags+=("--use-hba-hostnames")
     local_nonpersistent_flags+=("--use-hba-hostnames")
     flags+=("--verbose")
@@ -788,12 +1031,68 @@ _gpupgrade_kill-services()
     flags_with_completion=()
     flags_completion=()
 
+    flags+=("--force")
+    local_nonpersistent_flags+=("--force")
+    flags+=("--wait=")
+    two_word_flags+=("--wait")
+    local_nonpersistent_flags+=("--wait")
+    local_nonpersistent_flags+=("--wait=")
 
     must_have_one_flag=()
     must_have_one_noun=()
     noun_aliases=()
 }
 
+_gpupgrade_recover_help()
+{
+    last_command="gpupgrade_recover_help"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_gpupgrade_recover()
+{
+    last_command="gpupgrade_recover"
+
+    command_aliases=()
+
+    commands=()
+    commands+=("help")
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--?")
+    flags+=("-?")
+    local_nonpersistent_flags+=("--?")
+    local_nonpersistent_flags+=("-?")
+    flags+=("--substep=")
+    two_word_flags+=("--substep")
+    local_nonpersistent_flags+=("--substep")
+    local_nonpersistent_flags+=("--substep=")
+
+    must_have_one_flag=()
+    must_have_one_flag+=("--substep=")
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
 _gpupgrade_restart-services()
 {
     last_command="gpupgrade_restart-services"
@@ -853,6 +1152,8 @@ _gpupgrade_revert()
     flags+=("-?")
     local_nonpersistent_flags+=("--?")
     local_nonpersistent_flags+=("-?")
+    flags+=("--dry-run")
+    local_nonpersistent_flags+=("--dry-run")
     flags+=("--verbose")
     flags+=("-v")
     local_nonpersistent_flags+=("--verbose")
@@ -863,6 +1164,55 @@ _gpupgrade_revert()
     noun_aliases=()
 }
 
+_gpupgrade_status_help()
+{
+    last_command="gpupgrade_status_help"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_gpupgrade_status()
+{
+    last_command="gpupgrade_status"
+
+    command_aliases=()
+
+    commands=()
+    commands+=("help")
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--?")
+    flags+=("-?")
+    local_nonpersistent_flags+=("--?")
+    local_nonpersistent_flags+=("-?")
+    flags+=("--format=")
+    two_word_flags+=("--format")
+    local_nonpersistent_flags+=("--format")
+    local_nonpersistent_flags+=("--format=")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
 _gpupgrade_version()
 {
     last_command="gpupgrade_version"
@@ -894,7 +1244,10 @@ _gpupgrade_root_command()
     command_aliases=()
 
     commands=()
+    commands+=("agents")
     commands+=("apply")
+    commands+=("break-step-lock")
+    commands+=("check")
     commands+=("config")
     commands+=("execute")
     commands+=("finalize")
@@ -902,8 +1255,10 @@ _gpupgrade_root_command()
     commands+=("help")
     commands+=("initialize")
     commands+=("kill-services")
+    commands+=("recover")
     commands+=("restart-services")
     commands+=("revert")
+    commands+=("status")
     commands+=("version")
 
     flags=()
@@ -24,7 +24,7 @@ import (
 	"github.com/greenplum-db/gpupgrade/utils/stopwatch"
 )
 
-const StepsFileName = "steps.json"
+const StepsFileName = step.StepsFileName
 
 const nextActionRunRevertText = "If you would like to return the cluster to its original state, please run \"gpupgrade revert\".\n"
 
@@ -119,24 +119,24 @@ func (s *Step) RunHubSubstep(f func(streams step.OutStreams) error) {
 	}
 }
 
-func (s *Step) AlwaysRun(substep idl.Substep, f func(streams step.OutStreams) error) {
-	s.run(substep, f, true)
+func (s *Step) AlwaysRun(substep idl.Substep, f func(streams step.OutStreams) error, opts ...step.Option) {
+	s.run(substep, f, true, opts...)
 }
 
-func (s *Step) RunConditionally(substep idl.Substep, shouldRun bool, f func(streams step.OutStreams) error) {
+func (s *Step) RunConditionally(substep idl.Substep, shouldRun bool, f func(streams step.OutStreams) error, opts ...step.Option) {
 	if !shouldRun {
 		log.Printf("skipping %s", substep)
 		return
 	}
 
-	s.run(substep, f, false)
+	s.run(substep, f, false, opts...)
 }
 
-func (s *Step) Run(substep idl.Substep, f func(streams step.OutStreams) error) {
-	s.run(substep, f, false)
+func (s *Step) Run(substep idl.Substep, f func(streams step.OutStreams) error, opts ...step.Option) {
+	s.run(substep, f, false, opts...)
 }
 
-func (s *Step) run(substep idl.Substep, f func(streams step.OutStreams) error, alwaysRun bool) {
+func (s *Step) run(substep idl.Substep, f func(streams step.OutStreams) error, alwaysRun bool, opts ...step.Option) {
 	var err error
 	defer func() {
 		if err != nil {
@@ -154,8 +154,9 @@ func (s *Step) run(substep idl.Substep, f func(streams step.OutStreams) error, a
 		return
 	}
 
-	if status == idl.Status_running {
-		err = fmt.Errorf("Found previous substep %s was running. Manual intervention needed to cleanup. Please contact support.", substep)
+	idempotent := step.IsDeclaredIdempotent(opts...)
+	if status == idl.Status_running && !idempotent {
+		err = step.RunningSubstepErr(substep)
 		if pErr := s.printStatus(substep, idl.Status_failed); pErr != nil {
 			err = errorlist.Append(err, pErr)
 			return
@@ -179,11 +180,21 @@ func (s *Step) run(substep idl.Substep, f func(streams step.OutStreams) error, a
 		logDuration(substep.String(), s.verbose, substepTimer.Stop().String())
 	}()
 
-	if pErr := s.printStatus(substep, idl.Status_running); pErr != nil {
+	if pErr := s.start(substep, idempotent); pErr != nil {
 		err = errorlist.Append(err, pErr)
 		return
 	}
 
+	defer func() {
+		if s.substepStore == nil {
+			return
+		}
+
+		if wErr := s.substepStore.WriteRun(s.step, substep, substepTimer.Stop().Elapsed(), err); wErr != nil {
+			err = errorlist.Append(err, wErr)
+		}
+	}()
+
 	err = f(s.streams)
 	if s.verbose {
 		fmt.Println() // Reset the cursor so verbose output does not run into the status.
@@ -266,11 +277,6 @@ func (s *Step) Complete(completedText string) error {
 }
 
 func (s *Step) printStatus(substep idl.Substep, status idl.Status) error {
-	if substep == s.lastSubstep {
-		// For the same substep reset the cursor to overwrite the current status.
-		fmt.Print("\r")
-	}
-
 	storeStatus := status
 	if status == idl.Status_skipped {
 		// Special case: we want to mark an explicitly-skipped substep complete on disk.
@@ -284,6 +290,30 @@ func (s *Step) printStatus(substep idl.Substep, status idl.Status) error {
 		}
 	}
 
+	s.print(substep, status)
+	return nil
+}
+
+// start begins a new run of the substep in the store and prints its running
+// status.
+func (s *Step) start(substep idl.Substep, idempotent bool) error {
+	if s.substepStore != nil {
+		err := s.substepStore.Start(s.step, substep, idempotent)
+		if err != nil {
+			return err
+		}
+	}
+
+	s.print(substep, idl.Status_running)
+	return nil
+}
+
+func (s *Step) print(substep idl.Substep, status idl.Status) {
+	if substep == s.lastSubstep {
+		// For the same substep reset the cursor to overwrite the current status.
+		fmt.Print("\r")
+	}
+
 	text := commanders.SubstepDescriptions[substep]
 	fmt.Print(commanders.Format(text.OutputText, status))
 
@@ -294,7 +324,6 @@ func (s *Step) printStatus(substep idl.Substep, status idl.Status) error {
 	}
 
 	s.lastSubstep = substep
-	return nil
 }
 
 func logDuration(operation string, verbose bool, duration string) {
@@ -40,6 +40,13 @@ func NewStepFileStore() (*StepStoreFileStore, error) {
 	return &StepStoreFileStore{store: step.NewSubstepStoreUsingFile(path)}, nil
 }
 
+// NewStepStoreUsingFile returns a StepStoreFileStore backed by the given path
+// without creating it, such as when reading the status of an upgrade that may
+// not have been initialized.
+func NewStepStoreUsingFile(path string) *StepStoreFileStore {
+	return &StepStoreFileStore{store: step.NewSubstepStoreUsingFile(path)}
+}
+
 func (s *StepStoreFileStore) Write(stepName idl.Step, status idl.Status) error {
 	err := s.store.Write(stepName, idl.Substep_step_status, status)
 	if err != nil {
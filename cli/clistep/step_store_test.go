@@ -52,6 +52,25 @@ func TestStepStore(t *testing.T) {
 		}
 	})
 
+	t.Run("NewStepStoreUsingFile reads the status without creating the steps status file", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, clistep.StepsFileName)
+		store := clistep.NewStepStoreUsingFile(path)
+
+		status, err := store.Read(idl.Step_initialize)
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("got error %#v want ErrNotExist", err)
+		}
+
+		if status != idl.Status_unknown_status {
+			t.Errorf("got status %q want %q", status, idl.Status_unknown_status)
+		}
+
+		testutils.PathMustNotExist(t, path)
+	})
+
 	t.Run("cannot create a new step store if state directory does not exist", func(t *testing.T) {
 		resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", "/does/not/exist")
 		defer resetEnv()
@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/greenplum-db/gpupgrade/cli/clistep"
 	"github.com/greenplum-db/gpupgrade/cli/commanders"
@@ -177,22 +178,54 @@ func TestSubstep(t *testing.T) {
 		}
 	})
 
-	t.Run("errors when a substep was previously running", func(t *testing.T) {
+	t.Run("re-runs an idempotent substep that was previously running", func(t *testing.T) {
 		substepStore := &MockSubstepStore{Status: idl.Status_running}
 		st, err := clistep.NewStep(idl.Step_initialize, "initialize", &MockStepStore{}, substepStore, &step.BufferedStreams{}, false)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
 
+		var called bool
 		st.Run(idl.Substep_check_disk_space, func(streams step.OutStreams) error {
+			called = true
+			return nil
+		}, step.Idempotent())
+
+		if !called {
+			t.Error("expected substep to be called")
+		}
+
+		if st.Err() != nil {
+			t.Errorf("unexpected err %#v", st.Err())
+		}
+	})
+
+	t.Run("errors when a substep was previously running", func(t *testing.T) {
+		substepStore := &MockSubstepStore{Status: idl.Status_running}
+		st, err := clistep.NewStep(idl.Step_initialize, "initialize", &MockStepStore{}, substepStore, &step.BufferedStreams{}, false)
+		if err != nil {
+			t.Errorf("unexpected err %#v", err)
+		}
+
+		st.Run(idl.Substep_saving_source_cluster_config, func(streams step.OutStreams) error {
 			return nil
 		})
 
 		err = st.Complete("")
-		expected := fmt.Sprintf("Found previous substep %s was running. Manual intervention needed to cleanup.", idl.Substep_check_disk_space)
+		expected := fmt.Sprintf("Found previous substep %s was running.", idl.Substep_saving_source_cluster_config)
 		if !strings.Contains(err.Error(), expected) {
 			t.Errorf("expected err %#v to contain %q", err, expected)
 		}
+
+		var nextActionErr utils.NextActionErr
+		if !errors.As(err, &nextActionErr) {
+			t.Fatalf("got %#v want %T", err, nextActionErr)
+		}
+
+		expected = fmt.Sprintf("gpupgrade recover --substep %s", idl.Substep_saving_source_cluster_config)
+		if !strings.Contains(nextActionErr.NextAction, expected) {
+			t.Errorf("expected next action %q to contain %q", nextActionErr.NextAction, expected)
+		}
 	})
 
 	t.Run("when a CLI substep is quit by the user its status is printed without the generic next action error", func(t *testing.T) {
@@ -754,7 +787,20 @@ func (t *MockSubstepStore) Read(_ idl.Step, substep idl.Substep) (idl.Status, er
 	return t.Status, nil
 }
 
+func (t *MockSubstepStore) ReadRun(_ idl.Step, substep idl.Substep) (step.SubstepRun, error) {
+	return step.SubstepRun{Status: step.PrettyStatus{Status: t.Status}}, nil
+}
+
 func (t *MockSubstepStore) Write(_ idl.Step, substep idl.Substep, status idl.Status) error {
 	t.Status = status
 	return t.WriteErr
 }
+
+func (t *MockSubstepStore) Start(_ idl.Step, substep idl.Substep, idempotent bool) error {
+	t.Status = idl.Status_running
+	return t.WriteErr
+}
+
+func (t *MockSubstepStore) WriteRun(_ idl.Step, substep idl.Substep, duration time.Duration, err error) error {
+	return nil
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commanders
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils/disk"
+)
+
+func GetAgents(client idl.CliToHubClient) ([]*idl.AgentStatus, error) {
+	reply, err := client.GetAgents(context.Background(), &idl.GetAgentsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return reply.GetAgents(), nil
+}
+
+// FormatAgents renders the agents in either "text" or "json" format.
+func FormatAgents(agents []*idl.AgentStatus, format string) (string, error) {
+	switch format {
+	case "", "text":
+		return formatAgentsText(agents), nil
+	case "json":
+		return formatAgentsJSON(agents)
+	}
+
+	return "", xerrors.Errorf("invalid format %q. Expected either \"text\" or \"json\".", format)
+}
+
+// formatAgentsText renders a table with a row per data directory of each
+// agent. Agents that could not be reached show their error instead.
+func formatAgentsText(agents []*idl.AgentStatus) string {
+	rows := [][]string{{"Host", "Version", "Uptime", "State Directory", "Binary Path", "Data Directory", "Available"}}
+	for _, agent := range agents {
+		if agent.GetError() != "" {
+			rows = append(rows, []string{agent.GetHost(), "error: " + agent.GetError()})
+			continue
+		}
+
+		info := agent.GetInfo()
+		agentColumns := []string{agent.GetHost(), info.GetVersion(), uptime(info), info.GetStateDir(), info.GetBinaryPath()}
+
+		diskSpace := info.GetDiskSpace()
+		if len(diskSpace) == 0 {
+			rows = append(rows, agentColumns)
+			continue
+		}
+
+		// The agent columns are only shown on the row of its first data directory.
+		for i, space := range diskSpace {
+			row := make([]string, len(agentColumns))
+			if i == 0 {
+				copy(row, agentColumns)
+			}
+
+			available := disk.FormatBytes(space.GetAvailable())
+			if space.GetError() != "" {
+				available = "error: " + space.GetError()
+			}
+
+			rows = append(rows, append(row, space.GetDir(), available))
+		}
+	}
+
+	var b strings.Builder
+
+	var t tabwriter.Writer
+	t.Init(&b, 0, 0, 2, ' ', 0)
+
+	for _, row := range rows {
+		fmt.Fprintln(&t, strings.Join(row, "\t"))
+	}
+
+	t.Flush()
+	return b.String()
+}
+
+func uptime(info *idl.AgentInfo) string {
+	return time.Duration(info.GetUptimeSeconds() * float64(time.Second)).Round(time.Second).String()
+}
+
+type agentJSON struct {
+	Host          string          `json:"host"`
+	Version       string          `json:"version,omitempty"`
+	UptimeSeconds float64         `json:"uptime_seconds,omitempty"`
+	StateDir      string          `json:"state_dir,omitempty"`
+	BinaryPath    string          `json:"binary_path,omitempty"`
+	DiskSpace     []diskSpaceJSON `json:"disk_space,omitempty"`
+	Error         string          `json:"error,omitempty"`
+}
+
+type diskSpaceJSON struct {
+	Dir         string `json:"dir"`
+	AvailableKB uint64 `json:"available_kb"`
+	Error       string `json:"error,omitempty"`
+}
+
+func formatAgentsJSON(agents []*idl.AgentStatus) (string, error) {
+	output := make([]agentJSON, 0, len(agents))
+	for _, agent := range agents {
+		info := agent.GetInfo()
+
+		agentJSON := agentJSON{
+			Host:          agent.GetHost(),
+			Version:       info.GetVersion(),
+			UptimeSeconds: info.GetUptimeSeconds(),
+			StateDir:      info.GetStateDir(),
+			BinaryPath:    info.GetBinaryPath(),
+			Error:         agent.GetError(),
+		}
+
+		for _, space := range info.GetDiskSpace() {
+			agentJSON.DiskSpace = append(agentJSON.DiskSpace, diskSpaceJSON{Dir: space.GetDir(), AvailableKB: space.GetAvailable(), Error: space.GetError()})
+		}
+
+		output = append(output, agentJSON)
+	}
+
+	contents, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", xerrors.Errorf("marshal agents: %w", err)
+	}
+
+	return string(contents) + "\n", nil
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commanders_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+)
+
+func TestGetAgents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("returns the agents from the hub", func(t *testing.T) {
+		expected := []*idl.AgentStatus{{Host: "sdw1", Info: &idl.AgentInfo{Version: "1.0.0"}}}
+
+		client := mock_idl.NewMockCliToHubClient(ctrl)
+		client.EXPECT().GetAgents(gomock.Any(), &idl.GetAgentsRequest{}).Return(&idl.GetAgentsReply{Agents: expected}, nil)
+
+		agents, err := commanders.GetAgents(client)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		if !reflect.DeepEqual(agents, expected) {
+			t.Errorf("got %v want %v", agents, expected)
+		}
+	})
+
+	t.Run("returns errors from the hub", func(t *testing.T) {
+		expected := errors.New("permission denied")
+
+		client := mock_idl.NewMockCliToHubClient(ctrl)
+		client.EXPECT().GetAgents(gomock.Any(), gomock.Any()).Return(nil, expected)
+
+		_, err := commanders.GetAgents(client)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+}
+
+func TestFormatAgents(t *testing.T) {
+	agents := []*idl.AgentStatus{
+		{Host: "sdw1", Info: &idl.AgentInfo{
+			Version:       "1.0.0",
+			Hostname:      "sdw1",
+			UptimeSeconds: 90.4,
+			StateDir:      "/home/gpadmin/.gpupgrade",
+			BinaryPath:    "/usr/local/bin/gpupgrade",
+			DiskSpace: []*idl.AgentInfo_DiskSpace{
+				{Dir: "/data/dbfast1/seg1", Available: 2048},
+				{Dir: "/data/dbfast2/seg2", Available: 512},
+				{Dir: "/data/dbfast3/seg3", Error: "no such file or directory"},
+			},
+		}},
+		{Host: "sdw2", Error: "connection refused"},
+	}
+
+	t.Run("formats text output", func(t *testing.T) {
+		output, err := commanders.FormatAgents(agents, "text")
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(output), "\n")
+		if len(lines) != 5 {
+			t.Fatalf("got %d lines want 5 in output %q", len(lines), output)
+		}
+
+		expected := [][]string{
+			{"Host", "Version", "Uptime", "State Directory", "Binary Path", "Data Directory", "Available"},
+			{"sdw1", "1.0.0", "1m30s", "/home/gpadmin/.gpupgrade", "/usr/local/bin/gpupgrade", "/data/dbfast1/seg1", "2.048 MB"},
+			{"/data/dbfast2/seg2", "512 KB"},
+			{"/data/dbfast3/seg3", "error: no such file or directory"},
+			{"sdw2", "error: connection refused"},
+		}
+
+		for i, fields := range expected {
+			for _, field := range fields {
+				if !strings.Contains(lines[i], field) {
+					t.Errorf("expected line %q to contain %q", lines[i], field)
+				}
+			}
+		}
+
+		if strings.Contains(lines[2], "sdw1") {
+			t.Errorf("expected line %q to only show the agent on its first data directory", lines[2])
+		}
+	})
+
+	t.Run("formats json output", func(t *testing.T) {
+		output, err := commanders.FormatAgents(agents, "json")
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		var actual []map[string]interface{}
+		err = json.Unmarshal([]byte(output), &actual)
+		if err != nil {
+			t.Fatalf("unmarshal %q: %v", output, err)
+		}
+
+		if len(actual) != 2 {
+			t.Fatalf("got %d agents want 2", len(actual))
+		}
+
+		if actual[0]["host"] != "sdw1" || actual[0]["version"] != "1.0.0" || actual[0]["binary_path"] != "/usr/local/bin/gpupgrade" {
+			t.Errorf("unexpected agent %v", actual[0])
+		}
+
+		diskSpace := actual[0]["disk_space"].([]interface{})[1].(map[string]interface{})
+		if diskSpace["dir"] != "/data/dbfast2/seg2" || diskSpace["available_kb"] != float64(512) {
+			t.Errorf("unexpected disk space %v", diskSpace)
+		}
+
+		diskSpace = actual[0]["disk_space"].([]interface{})[2].(map[string]interface{})
+		if diskSpace["dir"] != "/data/dbfast3/seg3" || diskSpace["error"] != "no such file or directory" {
+			t.Errorf("unexpected disk space %v", diskSpace)
+		}
+
+		if actual[1]["host"] != "sdw2" || actual[1]["error"] != "connection refused" {
+			t.Errorf("unexpected agent %v", actual[1])
+		}
+	})
+
+	t.Run("errors on an invalid format", func(t *testing.T) {
+		_, err := commanders.FormatAgents(agents, "xml")
+		if err == nil {
+			t.Errorf("expected an error")
+		}
+	})
+}
@@ -0,0 +1,665 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commanders
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	sigar "github.com/cloudfoundry/gosigar"
+	"golang.org/x/sys/unix"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/agent/launcher"
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/upgrade"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/disk"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+const (
+	VersionCheck          = "version compatibility"
+	EnvironmentCheck      = "environment"
+	DiskSpaceCheck        = "disk space"
+	ActiveConnectionCheck = "active connections"
+	SegmentHealthCheck    = "segment health"
+	PortCheck             = "port availability"
+	TablespaceCheck       = "tablespace layout"
+)
+
+// CheckOptions are the parameters of the pre-flight checks, which match those
+// of initialize.
+type CheckOptions struct {
+	SourceGPHome  string
+	TargetGPHome  string
+	SourcePort    int
+	Mode          idl.Mode
+	DiskFreeRatio float64
+	Ports         []int
+	Launcher      launcher.Launcher
+}
+
+// CheckResult is the outcome of a single check. It passed when Err is nil.
+type CheckResult struct {
+	Name string
+	Err  error
+}
+
+func (r CheckResult) Status() string {
+	var skipped SkippedCheckErr
+	switch {
+	case r.Err == nil:
+		return "passed"
+	case errors.As(r.Err, &skipped):
+		return "skipped"
+	default:
+		return "failed"
+	}
+}
+
+// SkippedCheckErr is returned by checks that cannot run in the current
+// environment such as when the agent launcher cannot run commands on hosts.
+type SkippedCheckErr struct {
+	Reason string
+}
+
+func (s SkippedCheckErr) Error() string {
+	return s.Reason
+}
+
+type CheckReport struct {
+	Time    time.Time
+	Options CheckOptions
+	Results []CheckResult
+}
+
+// Failed returns whether any check failed. Skipped checks are not failures.
+func (r CheckReport) Failed() bool {
+	for _, result := range r.Results {
+		if result.Status() == "failed" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r CheckReport) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "gpupgrade check report %s\n\n", r.Time.Format(time.RFC1123))
+
+	var t tabwriter.Writer
+	t.Init(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(&t, "Source GPHOME:\t%s\n", r.Options.SourceGPHome)
+	fmt.Fprintf(&t, "Target GPHOME:\t%s\n", r.Options.TargetGPHome)
+	fmt.Fprintf(&t, "Source port:\t%d\n", r.Options.SourcePort)
+	fmt.Fprintf(&t, "Mode:\t%s\n", r.Options.Mode)
+	fmt.Fprintf(&t, "Disk free ratio:\t%g\n", r.Options.DiskFreeRatio)
+	t.Flush()
+	b.WriteString("\n")
+
+	t.Init(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(&t, "Check\tResult")
+	for _, result := range r.Results {
+		fmt.Fprintf(&t, "%s\t%s\n", result.Name, result.Status())
+	}
+	t.Flush()
+
+	counts := make(map[string]int)
+	for _, result := range r.Results {
+		counts[result.Status()]++
+
+		if result.Err == nil {
+			continue
+		}
+
+		fmt.Fprintf(&b, "\n%s %s:\n%s\n", result.Name, result.Status(), strings.TrimSpace(result.Err.Error()))
+
+		var nextActions utils.NextActionErr
+		if errors.As(result.Err, &nextActions) {
+			b.WriteString(nextActions.Help() + "\n")
+		}
+	}
+
+	fmt.Fprintf(&b, "\n%d passed, %d failed, %d skipped\n", counts["passed"], counts["failed"], counts["skipped"])
+	return b.String()
+}
+
+// WriteCheckReport writes the report to a timestamped file in the directory
+// and returns its path.
+func WriteCheckReport(dir string, report CheckReport) (string, error) {
+	path := filepath.Join(dir, fmt.Sprintf("check_%s.txt", report.Time.Format("20060102T150405")))
+
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return "", xerrors.Errorf("create report directory: %w", err)
+	}
+
+	err = utils.AtomicallyWrite(path, []byte(report.String()))
+	if err != nil {
+		return "", xerrors.Errorf("write check report: %w", err)
+	}
+
+	return path, nil
+}
+
+// RunChecks runs the read-only pre-flight checks against the source cluster
+// and target installation without creating the state directory. Checks that
+// need the source cluster fail when it cannot be queried.
+func RunChecks(opts CheckOptions) CheckReport {
+	report := CheckReport{Time: time.Now(), Options: opts}
+
+	run := func(name string, check func() error) {
+		log.Printf("Running %s check", name)
+		err := check()
+		if err != nil {
+			log.Printf("%s check: %v", name, err)
+		}
+
+		report.Results = append(report.Results, CheckResult{Name: name, Err: err})
+	}
+
+	run(VersionCheck, func() error {
+		return greenplum.VerifyCompatibleGPDBVersions(opts.SourceGPHome, opts.TargetGPHome)
+	})
+
+	db, source, tablespaces, err := querySource(opts)
+	if err != nil {
+		for _, name := range []string{EnvironmentCheck, DiskSpaceCheck, ActiveConnectionCheck, SegmentHealthCheck, PortCheck, TablespaceCheck} {
+			report.Results = append(report.Results, CheckResult{Name: name, Err: err})
+		}
+
+		return report
+	}
+	defer func() {
+		if cErr := db.Close(); cErr != nil {
+			log.Printf("closing connection to the source cluster: %v", cErr)
+		}
+	}()
+
+	run(EnvironmentCheck, func() error {
+		err := hub.CheckEnvironment(opts.Launcher, append(hub.AgentHosts(source), source.CoordinatorHostname()), opts.SourceGPHome, opts.TargetGPHome)
+		if errors.Is(err, launcher.ErrCommandsNotSupported) {
+			return SkippedCheckErr{Reason: launcher.ErrCommandsNotSupported.Error()}
+		}
+
+		return err
+	})
+
+	run(DiskSpaceCheck, func() error {
+		return CheckDiskSpace(opts.Launcher, source, tablespaces, opts.DiskFreeRatio)
+	})
+
+	run(ActiveConnectionCheck, func() error {
+		return greenplum.QueryPgStatActivity(db, source)
+	})
+
+	run(SegmentHealthCheck, func() error {
+		return greenplum.CheckSegments(db, source)
+	})
+
+	run(PortCheck, func() error {
+		version, err := greenplum.Version(opts.TargetGPHome)
+		if err != nil {
+			return err
+		}
+
+		intermediate, err := config.GenerateIntermediateCluster(source, opts.Ports, upgrade.NewID(), version, opts.TargetGPHome)
+		if err != nil {
+			return err
+		}
+
+		return CheckPorts(opts.Launcher, source, intermediate)
+	})
+
+	run(TablespaceCheck, func() error {
+		return CheckTablespaceLayout(source, tablespaces)
+	})
+
+	return report
+}
+
+func querySource(opts CheckOptions) (*sql.DB, *greenplum.Cluster, greenplum.Tablespaces, error) {
+	db, err := bootstrapConnectionFunc(idl.ClusterDestination_source, opts.SourceGPHome, opts.SourcePort)
+	if err != nil {
+		return nil, nil, nil, xerrors.Errorf("connect to the source cluster: %w", err)
+	}
+
+	// Use a single connection such that active connections other than our
+	// own are found.
+	db.SetMaxOpenConns(1)
+
+	source, err := greenplum.ClusterFromDB(db, opts.SourceGPHome, idl.ClusterDestination_source)
+	if err != nil {
+		return nil, nil, nil, errorlist.Append(xerrors.Errorf("query the source cluster: %w", err), db.Close())
+	}
+
+	tablespaces, err := greenplum.QueryTablespaces(db, source.Version)
+	if err != nil {
+		return nil, nil, nil, errorlist.Append(xerrors.Errorf("query the source cluster: %w", err), db.Close())
+	}
+
+	return db, &source, tablespaces, nil
+}
+
+// CheckDiskSpace checks the space available to the data directories and
+// tablespaces of each host. The coordinator host is checked locally and the
+// other hosts using df through the launcher since the agents may not be
+// running.
+func CheckDiskSpace(l launcher.Launcher, source *greenplum.Cluster, tablespaces greenplum.Tablespaces, diskFreeRatio float64) error {
+	dirsByHost := make(map[string][]string)
+	for _, seg := range source.SelectSegments(func(seg *greenplum.SegConfig) bool { return true }) {
+		dirsByHost[seg.Hostname] = append(dirsByHost[seg.Hostname], seg.DataDir)
+		dirsByHost[seg.Hostname] = append(dirsByHost[seg.Hostname], tablespaces[int32(seg.DbID)].UserDefinedTablespacesLocations()...)
+	}
+
+	coordinatorHost := source.CoordinatorHostname()
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	var err error
+	var unsupported []string
+	totalUsage := make(map[disk.FilesystemHost]*idl.CheckDiskSpaceReply_DiskUsage)
+
+	for host, dirs := range dirsByHost {
+		host, dirs := host, dirs
+		sort.Strings(dirs)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var usages disk.FileSystemDiskUsage
+			var uErr error
+			if host == coordinatorHost {
+				usages, uErr = disk.CheckUsage(step.DevNullStream, disk.Local, diskFreeRatio, dirs...)
+			} else {
+				usages, uErr = remoteDiskUsage(l, host, diskFreeRatio, dirs)
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if errors.Is(uErr, launcher.ErrCommandsNotSupported) {
+				unsupported = append(unsupported, host)
+				return
+			}
+
+			if uErr != nil {
+				err = errorlist.Append(err, uErr)
+				return
+			}
+
+			for _, usage := range usages {
+				totalUsage[disk.FilesystemHost{Filesystem: usage.GetFs(), Host: usage.GetHost()}] = usage
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if err != nil {
+		return err
+	}
+
+	if len(totalUsage) > 0 {
+		return disk.NewSpaceUsageError(totalUsage)
+	}
+
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		return SkippedCheckErr{Reason: fmt.Sprintf("Only the coordinator host was checked. Hosts %s were not checked since %v.",
+			strings.Join(unsupported, ", "), launcher.ErrCommandsNotSupported)}
+	}
+
+	return nil
+}
+
+// remoteDiskUsage checks the directories of the host with disk.CheckUsage
+// using the output of df.
+func remoteDiskUsage(l launcher.Launcher, host string, diskFreeRatio float64, dirs []string) (disk.FileSystemDiskUsage, error) {
+	output, err := l.Output(host, "df -Pk "+utils.ShellJoin(dirs...))
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := parseDf(host, dirs, output)
+	if err != nil {
+		return nil, err
+	}
+
+	usages, err := disk.CheckUsage(step.DevNullStream, d, diskFreeRatio, dirs...)
+	if err != nil {
+		return nil, xerrors.Errorf("check disk usage on host %s: %w", host, err)
+	}
+
+	// disk.CheckUsage reports the local hostname.
+	for _, usage := range usages {
+		usage.Host = host
+	}
+
+	return usages, nil
+}
+
+// dfDisk implements disk.Disk for the directories of a remote host using the
+// output of df. Each filesystem is identified by the position of its mount
+// point since the device IDs are not reported.
+type dfDisk struct {
+	mounts  []string                         // mount points in the order reported
+	mountOf map[string]string                // mount point of each directory
+	usages  map[string]sigar.FileSystemUsage // usage of each directory
+}
+
+// parseDf parses the POSIX output format of df which has a line for each
+// directory reporting the used and available space in kilobytes like
+// disk.Local.
+func parseDf(host string, dirs []string, output []byte) (dfDisk, error) {
+	d := dfDisk{
+		mountOf: make(map[string]string),
+		usages:  make(map[string]sigar.FileSystemUsage),
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(lines) != len(dirs)+1 {
+		return dfDisk{}, xerrors.Errorf("unexpected df output %q on host %s", output, host)
+	}
+
+	for i, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) < 6 {
+			return dfDisk{}, xerrors.Errorf("unexpected df output %q on host %s", line, host)
+		}
+
+		total, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return dfDisk{}, xerrors.Errorf("parse total space of df output %q on host %s: %w", line, host, err)
+		}
+
+		used, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			return dfDisk{}, xerrors.Errorf("parse used space of df output %q on host %s: %w", line, host, err)
+		}
+
+		available, err := strconv.ParseUint(fields[3], 10, 64)
+		if err != nil {
+			return dfDisk{}, xerrors.Errorf("parse available space of df output %q on host %s: %w", line, host, err)
+		}
+
+		mount := strings.Join(fields[5:], " ")
+		if _, ok := d.index(mount); !ok {
+			d.mounts = append(d.mounts, mount)
+		}
+
+		d.mountOf[dirs[i]] = mount
+		d.usages[dirs[i]] = sigar.FileSystemUsage{Total: total, Used: used, Avail: available}
+	}
+
+	return d, nil
+}
+
+func (d dfDisk) index(mount string) (int, bool) {
+	for i, m := range d.mounts {
+		if m == mount {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+func (d dfDisk) Filesystems() (sigar.FileSystemList, error) {
+	var list sigar.FileSystemList
+	for _, mount := range d.mounts {
+		list.List = append(list.List, sigar.FileSystem{DirName: mount})
+	}
+
+	return list, nil
+}
+
+func (d dfDisk) Usage(path string) (sigar.FileSystemUsage, error) {
+	usage, ok := d.usages[path]
+	if !ok {
+		return sigar.FileSystemUsage{}, xerrors.Errorf("no df output for %s", path)
+	}
+
+	return usage, nil
+}
+
+func (d dfDisk) Stat(path string) (*unix.Stat_t, error) {
+	mount, ok := d.mountOf[path]
+	if !ok {
+		mount = path
+	}
+
+	i, ok := d.index(mount)
+	if !ok {
+		return nil, xerrors.Errorf("no df output for %s", path)
+	}
+
+	stat := new(unix.Stat_t)
+	setDev(&stat.Dev, i+1)
+	return stat, nil
+}
+
+// setDev sets the device ID whose type differs across platforms.
+func setDev[T ~int32 | ~uint64](dev *T, id int) {
+	*dev = T(id)
+}
+
+// CheckPorts ensures the ports of the intermediate cluster do not overlap with
+// the source cluster and can be listened on. The ports of the coordinator host
+// are checked locally, while those of the other hosts are checked using the
+// listening sockets reported by ss through the agent launcher since the agents
+// are not yet running. Hosts are skipped when the launcher cannot run commands.
+func CheckPorts(l launcher.Launcher, source *greenplum.Cluster, intermediate *greenplum.Cluster) error {
+	err := config.EnsureTempPortRangeDoesNotOverlapWithSourceClusterPorts(source, intermediate)
+	if err != nil {
+		return err
+	}
+
+	portsByHost := make(map[string][]int)
+	for _, seg := range intermediate.SelectSegments(func(seg *greenplum.SegConfig) bool { return true }) {
+		portsByHost[seg.Hostname] = append(portsByHost[seg.Hostname], seg.Port)
+	}
+
+	coordinatorHost := intermediate.CoordinatorHostname()
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	var unsupported []string
+	var conflicts []portConflict
+
+	for host, ports := range portsByHost {
+		host, ports := host, ports
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var unavailable map[int]error
+			var uErr error
+			if host == coordinatorHost {
+				unavailable = utils.UnavailablePorts(ports)
+			} else {
+				unavailable, uErr = remoteUnavailablePorts(l, host, ports)
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if errors.Is(uErr, launcher.ErrCommandsNotSupported) {
+				unsupported = append(unsupported, host)
+				return
+			}
+
+			if uErr != nil {
+				err = errorlist.Append(err, uErr)
+				return
+			}
+
+			for port, pErr := range unavailable {
+				conflicts = append(conflicts, portConflict{host: host, port: port, err: pErr})
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if err != nil {
+		return err
+	}
+
+	if len(conflicts) > 0 {
+		sort.Slice(conflicts, func(i, j int) bool {
+			if conflicts[i].host == conflicts[j].host {
+				return conflicts[i].port < conflicts[j].port
+			}
+
+			return conflicts[i].host < conflicts[j].host
+		})
+
+		for _, conflict := range conflicts {
+			err = errorlist.Append(err, xerrors.Errorf("port %d on host %s is unavailable: %w", conflict.port, conflict.host, conflict.err))
+		}
+
+		return utils.NewNextActionErr(err, "Stop the processes using the ports or set temp_port_range to ports that are available on all hosts.")
+	}
+
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		return SkippedCheckErr{Reason: fmt.Sprintf("Only the coordinator host was checked. Hosts %s were not checked since %v.",
+			strings.Join(unsupported, ", "), launcher.ErrCommandsNotSupported)}
+	}
+
+	return nil
+}
+
+type portConflict struct {
+	host string
+	port int
+	err  error
+}
+
+// remoteUnavailablePorts returns the ports of the host that are in use by a
+// listening socket using the output of ss.
+func remoteUnavailablePorts(l launcher.Launcher, host string, ports []int) (map[int]error, error) {
+	output, err := l.Output(host, "ss -tln")
+	if err != nil {
+		return nil, err
+	}
+
+	listening, err := parseSs(host, output)
+	if err != nil {
+		return nil, err
+	}
+
+	unavailable := make(map[int]error)
+	for _, port := range ports {
+		if address, ok := listening[port]; ok {
+			unavailable[port] = xerrors.Errorf("in use by a socket listening on %s", address)
+		}
+	}
+
+	return unavailable, nil
+}
+
+// parseSs returns the local address of each listening port from the output of
+// "ss -tln" such as:
+//
+//	State  Recv-Q Send-Q Local Address:Port  Peer Address:Port
+//	LISTEN 0      128          0.0.0.0:22         0.0.0.0:*
+//	LISTEN 0      128             [::]:22            [::]:*
+func parseSs(host string, output []byte) (map[int]string, error) {
+	listening := make(map[int]string)
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			return nil, xerrors.Errorf("unexpected ss output %q on host %s", line, host)
+		}
+
+		address := fields[3]
+		i := strings.LastIndex(address, ":")
+		if i < 0 {
+			return nil, xerrors.Errorf("unexpected ss output %q on host %s", line, host)
+		}
+
+		port, err := strconv.Atoi(address[i+1:])
+		if err != nil {
+			return nil, xerrors.Errorf("parse port of ss output %q on host %s: %w", line, host, err)
+		}
+
+		if _, ok := listening[port]; !ok {
+			listening[port] = address
+		}
+	}
+
+	return listening, nil
+}
+
+// CheckTablespaceLayout ensures that the user defined tablespaces have
+// absolute locations outside of the data directories on their host, since the
+// data directories are copied and renamed during the upgrade.
+func CheckTablespaceLayout(source *greenplum.Cluster, tablespaces greenplum.Tablespaces) error {
+	segments := source.SelectSegments(func(seg *greenplum.SegConfig) bool { return true })
+	sort.Sort(segments)
+
+	var err error
+	for _, seg := range segments {
+		var locations []string
+		for _, tablespace := range tablespaces[int32(seg.DbID)] {
+			if tablespace.GetUserDefined() {
+				locations = append(locations, tablespace.GetLocation())
+			}
+		}
+		sort.Strings(locations)
+
+		for _, location := range locations {
+			if !filepath.IsAbs(location) {
+				err = errorlist.Append(err, xerrors.Errorf("tablespace location %q of dbid %d on host %s is not an absolute path", location, seg.DbID, seg.Hostname))
+				continue
+			}
+
+			for _, other := range segments {
+				if other.Hostname != seg.Hostname || !isWithin(location, other.DataDir) {
+					continue
+				}
+
+				err = errorlist.Append(err, xerrors.Errorf("tablespace location %q of dbid %d on host %s is within data directory %q", location, seg.DbID, seg.Hostname, other.DataDir))
+			}
+		}
+	}
+
+	if err != nil {
+		return utils.NewNextActionErr(err, "Move the tablespaces outside of the data directories before upgrading.")
+	}
+
+	return nil
+}
+
+func isWithin(path string, dir string) bool {
+	rel, err := filepath.Rel(filepath.Clean(dir), filepath.Clean(path))
+	if err != nil {
+		return false
+	}
+
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
@@ -0,0 +1,440 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commanders_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/agent/launcher"
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/disk"
+)
+
+func TestCheckReport(t *testing.T) {
+	report := commanders.CheckReport{
+		Time: time.Date(2023, 1, 2, 3, 4, 5, 0, time.Local),
+		Options: commanders.CheckOptions{
+			SourceGPHome:  "/usr/local/gpdb5",
+			TargetGPHome:  "/usr/local/gpdb6",
+			SourcePort:    15432,
+			Mode:          idl.Mode_link,
+			DiskFreeRatio: 0.2,
+		},
+		Results: []commanders.CheckResult{
+			{Name: commanders.VersionCheck},
+			{Name: commanders.EnvironmentCheck, Err: commanders.SkippedCheckErr{Reason: "agent launcher does not support running commands on hosts"}},
+			{Name: commanders.ActiveConnectionCheck, Err: utils.NewNextActionErr(errors.New("Found 1 active connections"), "Please close all database connections before proceeding.")},
+		},
+	}
+
+	t.Run("reports the status of each check", func(t *testing.T) {
+		expected := []string{"passed", "skipped", "failed"}
+		for i, result := range report.Results {
+			if result.Status() != expected[i] {
+				t.Errorf("got status %q for %s want %q", result.Status(), result.Name, expected[i])
+			}
+		}
+
+		if !report.Failed() {
+			t.Error("expected report to have failed")
+		}
+
+		passed := commanders.CheckReport{Results: report.Results[:2]}
+		if passed.Failed() {
+			t.Error("expected report with skipped checks to not have failed")
+		}
+	})
+
+	t.Run("renders the results along with failures and their next actions", func(t *testing.T) {
+		actual := report.String()
+
+		for _, expected := range []string{
+			"Source GPHOME:    /usr/local/gpdb5",
+			"Mode:             link",
+			"Check                  Result",
+			"version compatibility  passed",
+			"environment            skipped",
+			"active connections     failed",
+			"active connections failed:\nFound 1 active connections",
+			"NEXT ACTIONS\n------------\nPlease close all database connections before proceeding.",
+			"1 passed, 1 failed, 1 skipped",
+		} {
+			if !strings.Contains(actual, expected) {
+				t.Errorf("expected report %q to contain %q", actual, expected)
+			}
+		}
+	})
+
+	t.Run("writes the report to a timestamped file", func(t *testing.T) {
+		dir := filepath.Join(testutils.GetTempDir(t, ""), "gpAdminLogs")
+		defer testutils.MustRemoveAll(t, filepath.Dir(dir))
+
+		path, err := commanders.WriteCheckReport(dir, report)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := filepath.Join(dir, "check_20230102T030405.txt")
+		if path != expected {
+			t.Errorf("got path %q want %q", path, expected)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != report.String() {
+			t.Errorf("got contents %q want %q", contents, report.String())
+		}
+	})
+}
+
+func TestRunChecks(t *testing.T) {
+	t.Run("fails the checks that need the source cluster when it cannot be queried", func(t *testing.T) {
+		expected := errors.New("connection refused")
+		commanders.SetBootstrapConnectionFunction(func(destination idl.ClusterDestination, gphome string, port int) (*sql.DB, error) {
+			return nil, expected
+		})
+		defer commanders.ResetBootstrapConnectionFunction()
+
+		report := commanders.RunChecks(commanders.CheckOptions{
+			SourceGPHome: "/does/not/exist/source",
+			TargetGPHome: "/does/not/exist/target",
+			Launcher:     launcher.Running{},
+		})
+
+		names := []string{
+			commanders.VersionCheck,
+			commanders.EnvironmentCheck,
+			commanders.DiskSpaceCheck,
+			commanders.ActiveConnectionCheck,
+			commanders.SegmentHealthCheck,
+			commanders.PortCheck,
+			commanders.TablespaceCheck,
+		}
+
+		if len(report.Results) != len(names) {
+			t.Fatalf("got %d results want %d", len(report.Results), len(names))
+		}
+
+		for i, result := range report.Results {
+			if result.Name != names[i] {
+				t.Errorf("got check %q want %q", result.Name, names[i])
+			}
+
+			if result.Status() != "failed" {
+				t.Errorf("got status %q for %s want failed", result.Status(), result.Name)
+			}
+
+			if i > 0 && !errors.Is(result.Err, expected) {
+				t.Errorf("got error %#v for %s want %#v", result.Err, result.Name, expected)
+			}
+		}
+	})
+}
+
+func TestCheckDiskSpace(t *testing.T) {
+	coordinatorDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, coordinatorDir)
+
+	primaryDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, primaryDir)
+
+	source := greenplum.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Port: 15432, Hostname: "coordinator", DataDir: coordinatorDir, Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 2, Port: 25432, Hostname: "sdw1", DataDir: primaryDir, Role: greenplum.PrimaryRole},
+	})
+
+	t.Run("passes when there is enough disk space on each host", func(t *testing.T) {
+		err := commanders.CheckDiskSpace(launcher.Local{}, source, nil, 0)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+
+	t.Run("errors with the usage of each host without enough disk space", func(t *testing.T) {
+		err := commanders.CheckDiskSpace(launcher.Local{}, source, nil, 1)
+
+		var usageErr *disk.SpaceUsageErr
+		if !errors.As(err, &usageErr) {
+			t.Fatalf("got error %#v want %T", err, usageErr)
+		}
+
+		hostname, err := os.Hostname()
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		rows := usageErr.Table()
+		if len(rows) != 3 {
+			t.Fatalf("got rows %q want a row for each host", rows)
+		}
+
+		hosts := []string{rows[1][0], rows[2][0]}
+		if !(contains(hosts, hostname) && contains(hosts, "sdw1")) {
+			t.Errorf("got hosts %q want %q and %q", hosts, hostname, "sdw1")
+		}
+	})
+
+	t.Run("checks directories with spaces on other hosts", func(t *testing.T) {
+		dir := filepath.Join(primaryDir, "my primary")
+		testutils.MustCreateDir(t, dir)
+
+		source := greenplum.MustCreateCluster(t, greenplum.SegConfigs{
+			{ContentID: -1, DbID: 1, Port: 15432, Hostname: "coordinator", DataDir: coordinatorDir, Role: greenplum.PrimaryRole},
+			{ContentID: 0, DbID: 2, Port: 25432, Hostname: "sdw1", DataDir: dir, Role: greenplum.PrimaryRole},
+		})
+
+		err := commanders.CheckDiskSpace(launcher.Local{}, source, nil, 1)
+
+		var usageErr *disk.SpaceUsageErr
+		if !errors.As(err, &usageErr) {
+			t.Fatalf("got error %#v want %T", err, usageErr)
+		}
+
+		rows := usageErr.Table()
+		hosts := []string{rows[1][0], rows[2][0]}
+		if !contains(hosts, "sdw1") {
+			t.Errorf("got hosts %q want %q", hosts, "sdw1")
+		}
+	})
+
+	t.Run("skips hosts when the launcher cannot run commands", func(t *testing.T) {
+		err := commanders.CheckDiskSpace(launcher.Running{}, source, nil, 0)
+
+		var skipped commanders.SkippedCheckErr
+		if !errors.As(err, &skipped) {
+			t.Fatalf("got error %#v want %T", err, skipped)
+		}
+
+		if !strings.Contains(skipped.Reason, "Hosts sdw1 were not checked") {
+			t.Errorf("got reason %q", skipped.Reason)
+		}
+	})
+
+	t.Run("errors when df fails", func(t *testing.T) {
+		source := greenplum.MustCreateCluster(t, greenplum.SegConfigs{
+			{ContentID: -1, DbID: 1, Port: 15432, Hostname: "coordinator", DataDir: coordinatorDir, Role: greenplum.PrimaryRole},
+			{ContentID: 0, DbID: 2, Port: 25432, Hostname: "sdw1", DataDir: filepath.Join(primaryDir, "does", "not", "exist"), Role: greenplum.PrimaryRole},
+		})
+
+		err := commanders.CheckDiskSpace(launcher.Local{}, source, nil, 0)
+		if err == nil || !strings.Contains(err.Error(), "df -Pk") {
+			t.Errorf("got error %v want it to contain %q", err, "df -Pk")
+		}
+	})
+}
+
+func TestCheckPorts(t *testing.T) {
+	source := greenplum.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Port: 15432, Hostname: "localhost", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+	})
+
+	t.Run("passes when the ports are available", func(t *testing.T) {
+		intermediate := greenplum.MustCreateCluster(t, greenplum.SegConfigs{
+			{ContentID: -1, DbID: 1, Port: testutils.MustGetPort(t), Hostname: "localhost", DataDir: "/data/qddir/seg.AAAAAAAAAAM.-1", Role: greenplum.PrimaryRole},
+		})
+
+		err := commanders.CheckPorts(launcher.Local{}, source, intermediate)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+
+	t.Run("errors when a port is in use", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "localhost:0")
+		if err != nil {
+			t.Fatalf("listen: %v", err)
+		}
+		defer listener.Close()
+
+		port := listener.Addr().(*net.TCPAddr).Port
+		intermediate := greenplum.MustCreateCluster(t, greenplum.SegConfigs{
+			{ContentID: -1, DbID: 1, Port: port, Hostname: "localhost", DataDir: "/data/qddir/seg.AAAAAAAAAAM.-1", Role: greenplum.PrimaryRole},
+		})
+
+		err = commanders.CheckPorts(launcher.Local{}, source, intermediate)
+
+		var nextActionsErr utils.NextActionErr
+		if !errors.As(err, &nextActionsErr) {
+			t.Fatalf("got error %#v want %T", err, nextActionsErr)
+		}
+
+		expected := fmt.Sprintf("port %d on host localhost is unavailable", port)
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("got error %q want it to contain %q", err, expected)
+		}
+	})
+
+	t.Run("errors when a port on another host is in use", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "localhost:0")
+		if err != nil {
+			t.Fatalf("listen: %v", err)
+		}
+		defer listener.Close()
+
+		port := listener.Addr().(*net.TCPAddr).Port
+		intermediate := greenplum.MustCreateCluster(t, greenplum.SegConfigs{
+			{ContentID: -1, DbID: 1, Port: testutils.MustGetPort(t), Hostname: "localhost", DataDir: "/data/qddir/seg.AAAAAAAAAAM.-1", Role: greenplum.PrimaryRole},
+			{ContentID: 0, DbID: 2, Port: port, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.AAAAAAAAAAM.0", Role: greenplum.PrimaryRole},
+		})
+
+		err = commanders.CheckPorts(launcher.Local{}, source, intermediate)
+
+		var nextActionsErr utils.NextActionErr
+		if !errors.As(err, &nextActionsErr) {
+			t.Fatalf("got error %#v want %T", err, nextActionsErr)
+		}
+
+		expected := fmt.Sprintf("port %d on host sdw1 is unavailable: in use by a socket listening on 127.0.0.1:%d", port, port)
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("got error %q want it to contain %q", err, expected)
+		}
+	})
+
+	t.Run("passes when the ports on other hosts are available", func(t *testing.T) {
+		intermediate := greenplum.MustCreateCluster(t, greenplum.SegConfigs{
+			{ContentID: -1, DbID: 1, Port: testutils.MustGetPort(t), Hostname: "localhost", DataDir: "/data/qddir/seg.AAAAAAAAAAM.-1", Role: greenplum.PrimaryRole},
+			{ContentID: 0, DbID: 2, Port: testutils.MustGetPort(t), Hostname: "sdw1", DataDir: "/data/dbfast1/seg.AAAAAAAAAAM.0", Role: greenplum.PrimaryRole},
+		})
+
+		err := commanders.CheckPorts(launcher.Local{}, source, intermediate)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+
+	t.Run("skips hosts when the launcher cannot run commands", func(t *testing.T) {
+		intermediate := greenplum.MustCreateCluster(t, greenplum.SegConfigs{
+			{ContentID: -1, DbID: 1, Port: testutils.MustGetPort(t), Hostname: "localhost", DataDir: "/data/qddir/seg.AAAAAAAAAAM.-1", Role: greenplum.PrimaryRole},
+			{ContentID: 0, DbID: 2, Port: 50434, Hostname: "sdw2", DataDir: "/data/dbfast2/seg.AAAAAAAAAAM.0", Role: greenplum.PrimaryRole},
+			{ContentID: 1, DbID: 3, Port: 50433, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.AAAAAAAAAAM.1", Role: greenplum.PrimaryRole},
+		})
+
+		err := commanders.CheckPorts(launcher.Running{}, source, intermediate)
+
+		var skipped commanders.SkippedCheckErr
+		if !errors.As(err, &skipped) {
+			t.Fatalf("got error %#v want %T", err, skipped)
+		}
+
+		if !strings.Contains(skipped.Reason, "Hosts sdw1, sdw2 were not checked") {
+			t.Errorf("got reason %q", skipped.Reason)
+		}
+	})
+
+	t.Run("errors when ss fails", func(t *testing.T) {
+		intermediate := greenplum.MustCreateCluster(t, greenplum.SegConfigs{
+			{ContentID: -1, DbID: 1, Port: testutils.MustGetPort(t), Hostname: "localhost", DataDir: "/data/qddir/seg.AAAAAAAAAAM.-1", Role: greenplum.PrimaryRole},
+			{ContentID: 0, DbID: 2, Port: 50434, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.AAAAAAAAAAM.0", Role: greenplum.PrimaryRole},
+		})
+
+		expected := errors.New("permission denied")
+		err := commanders.CheckPorts(failingLauncher{err: expected}, source, intermediate)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+
+	t.Run("errors when the ports overlap with the source cluster", func(t *testing.T) {
+		intermediate := greenplum.MustCreateCluster(t, greenplum.SegConfigs{
+			{ContentID: -1, DbID: 1, Port: 15432, Hostname: "localhost", DataDir: "/data/qddir/seg.AAAAAAAAAAM.-1", Role: greenplum.PrimaryRole},
+		})
+
+		err := commanders.CheckPorts(launcher.Local{}, source, intermediate)
+		if !errors.Is(err, config.ErrInvalidTempPortRange) {
+			t.Errorf("got error %#v want %#v", err, config.ErrInvalidTempPortRange)
+		}
+	})
+}
+
+func TestCheckTablespaceLayout(t *testing.T) {
+	source := greenplum.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Port: 15432, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 2, Port: 25432, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 3, Port: 25433, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Role: greenplum.MirrorRole},
+	})
+
+	t.Run("passes when tablespaces are outside the data directories", func(t *testing.T) {
+		tablespaces := greenplum.Tablespaces{
+			1: {1663: {Location: "/data/qddir/seg-1", UserDefined: false}, 16384: {Location: "/tablespaces/qddir/16384", UserDefined: true}},
+			2: {16384: {Location: "/tablespaces/dbfast1/16384", UserDefined: true}},
+			// the location is within a data directory of another host
+			3: {16384: {Location: "/data/dbfast1/seg1/tablespace", UserDefined: true}},
+		}
+
+		err := commanders.CheckTablespaceLayout(source, tablespaces)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+
+	t.Run("errors when tablespaces are within data directories or relative", func(t *testing.T) {
+		tablespaces := greenplum.Tablespaces{
+			1: {16384: {Location: "/data/qddir/seg-1/tablespaces/16384", UserDefined: true}},
+			2: {16384: {Location: "tablespaces/16384", UserDefined: true}},
+			3: {16384: {Location: "/data/dbfast_mirror1/seg1", UserDefined: true}},
+		}
+
+		err := commanders.CheckTablespaceLayout(source, tablespaces)
+
+		var nextActionsErr utils.NextActionErr
+		if !errors.As(err, &nextActionsErr) {
+			t.Fatalf("got error %#v want %T", err, nextActionsErr)
+		}
+
+		for _, expected := range []string{
+			`tablespace location "/data/qddir/seg-1/tablespaces/16384" of dbid 1 on host coordinator is within data directory "/data/qddir/seg-1"`,
+			`tablespace location "tablespaces/16384" of dbid 2 on host sdw1 is not an absolute path`,
+			`tablespace location "/data/dbfast_mirror1/seg1" of dbid 3 on host sdw2 is within data directory "/data/dbfast_mirror1/seg1"`,
+		} {
+			if !strings.Contains(err.Error(), expected) {
+				t.Errorf("got error %q want it to contain %q", err, expected)
+			}
+		}
+	})
+
+	t.Run("does not treat sibling directories with a common prefix as within", func(t *testing.T) {
+		tablespaces := greenplum.Tablespaces{
+			2: {16384: {Location: "/data/dbfast1/seg10/16384", UserDefined: true}},
+		}
+
+		err := commanders.CheckTablespaceLayout(source, tablespaces)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+}
+
+func contains(list []string, item string) bool {
+	for _, l := range list {
+		if l == item {
+			return true
+		}
+	}
+
+	return false
+}
+
+type failingLauncher struct {
+	err error
+}
+
+func (f failingLauncher) Start(host string, agentArgs []string) error {
+	return f.err
+}
+
+func (f failingLauncher) Output(host string, command string) ([]byte, error) {
+	return nil, f.err
+}
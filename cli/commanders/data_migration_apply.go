@@ -26,6 +26,15 @@ import (
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
 )
 
+// appliedScriptsMarker precedes the output of each script directory in the
+// apply log such that the applied scripts can be listed in the upgrade report.
+const appliedScriptsMarker = "-- gpupgrade applied data migration scripts in "
+
+type scriptDirOutput struct {
+	scriptDir string
+	output    []byte
+}
+
 func ApplyDataMigrationScripts(nonInteractive bool, gphome string, port int, logDir string, currentScriptDirFS fs.FS, currentScriptDir string, phase idl.Step) error {
 	_, err := currentScriptDirFS.Open(phase.String())
 	if err != nil {
@@ -63,7 +72,7 @@ func ApplyDataMigrationScripts(nonInteractive bool, gphome string, port int, log
 	progressBar := mpb.New()
 	var wg sync.WaitGroup
 	errChan := make(chan error, len(scriptDirsToRun))
-	outputChan := make(chan []byte, len(scriptDirsToRun))
+	outputChan := make(chan scriptDirOutput, len(scriptDirsToRun))
 
 	fmt.Printf("\nApplying data migration scripts...\n")
 	for _, scriptDir := range scriptDirsToRun {
@@ -90,7 +99,7 @@ func ApplyDataMigrationScripts(nonInteractive bool, gphome string, port int, log
 				return
 			}
 
-			outputChan <- output
+			outputChan <- scriptDirOutput{scriptDir: scriptDir, output: output}
 		}(gphome, port, scriptDir, bar)
 	}
 
@@ -108,17 +117,18 @@ func ApplyDataMigrationScripts(nonInteractive bool, gphome string, port int, log
 		return errs
 	}
 
-	for output := range outputChan {
-		log.Println(string(output))
+	for result := range outputChan {
+		log.Println(string(result.output))
 
-		_, err = file.Write(output)
+		_, err = file.WriteString(appliedScriptsMarker + result.scriptDir + "\n")
 		if err != nil {
 			return err
 		}
-	}
 
-	if phase == idl.Step_stats {
-		fmt.Print(color.YellowString("\nTo receive an upgrade time estimate send the stats output:\n%s\n", utils.Bold.Sprint(filepath.Join(logDir, "apply_"+phase.String()+".log"))))
+		_, err = file.Write(result.output)
+		if err != nil {
+			return err
+		}
 	}
 
 	fmt.Printf(`
@@ -47,7 +47,7 @@ func TestApplyDataMigrationScripts(t *testing.T) {
 		}
 	})
 
-	t.Run("prints stats specific message for stats phase", func(t *testing.T) {
+	t.Run("applies the stats phase scripts", func(t *testing.T) {
 		d := BufferStandardDescriptors(t)
 
 		resetStdin := testutils.SetStdin(t, "a\n")
@@ -77,13 +77,23 @@ func TestApplyDataMigrationScripts(t *testing.T) {
 			t.Errorf("unexpected stderr %#v", string(stderr))
 		}
 
-		expected := "To receive an upgrade time estimate send the stats output"
+		expected := "Applying data migration scripts..."
 		actual := string(stdout)
 		if !strings.Contains(actual, expected) {
 			t.Errorf("expected output %#v to contain %#v", actual, expected)
 			t.Logf("actual:   %#v", actual)
 			t.Logf("expected: %#v", expected)
 		}
+
+		applied, err := commanders.ReadAppliedDataMigrationScripts(logDir)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expectedScripts := []commanders.AppliedScripts{{Phase: idl.Step_stats, Scripts: []string{"generate_stats"}}}
+		if !reflect.DeepEqual(applied, expectedScripts) {
+			t.Errorf("got applied scripts %+v want %+v", applied, expectedScripts)
+		}
 	})
 
 	t.Run("does not error when prompt returns skipped", func(t *testing.T) {
@@ -0,0 +1,494 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commanders
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum/connection"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/disk"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// The rates below are conservative assumptions of typical hardware used to
+// estimate the upgrade duration.
+const (
+	// restoreRate is the catalog objects pg_upgrade dumps and restores per
+	// second for each job.
+	restoreRate = 50
+
+	// linkRate is the catalog objects pg_upgrade links per second for each
+	// job.
+	linkRate = 1000
+
+	// copyRate is the KB copied per second on each host.
+	copyRate = 200 * 1024
+
+	// transferRate is the KB transferred over the network per second to each
+	// host.
+	transferRate = 100 * 1024
+
+	// analyzeRate is the catalog objects analyzed per second.
+	analyzeRate = 100
+
+	// catalogObjectSize is the approximate size in KB of each catalog object
+	// used to estimate the size of the coordinator.
+	catalogObjectSize = 16
+
+	// substepOverhead is the time to start and stop the clusters within a
+	// substep.
+	substepOverhead = time.Minute
+)
+
+// UpgradeStats are the cluster and database characteristics that drive the
+// duration of an upgrade.
+type UpgradeStats struct {
+	Hosts     int
+	Primaries int
+	Mirrors   int  // including the standby
+	Standby   bool // whether the cluster has a standby coordinator
+	Databases int
+	Size      uint64 // in KB across all databases
+	Objects   uint64 // relations, functions, and types across all databases
+}
+
+// objectColumns are the columns of the database stats counted as catalog
+// objects.
+var objectColumns = []string{"ordinarytables", "indextables", "toasttables", "sequences", "views", "udfs", "types"}
+
+var separatorRegex = regexp.MustCompile(`^-+(\+-+)*$`)
+
+// ParseStatsLog parses the psql output of the cluster_and_database_stats data
+// migration scripts. Since the log is appended to each time the scripts are
+// applied, later results for a host or database replace earlier ones.
+func ParseStatsLog(r io.Reader) (UpgradeStats, error) {
+	primaries := make(map[string]int)
+	mirrors := make(map[string]int)
+	standby := make(map[string]int)
+	databases := make(map[string]map[string]uint64)
+
+	var columns []string
+	var database string
+	var previous string
+	inRows := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case separatorRegex.MatchString(line) && previous != "":
+			columns = splitRow(strings.ToLower(previous))
+			inRows = true
+
+		case inRows && strings.HasPrefix(line, "(") && strings.HasSuffix(line, ")"):
+			inRows = false
+
+		case inRows:
+			values := splitRow(line)
+			if len(values) != len(columns) {
+				return UpgradeStats{}, xerrors.Errorf("parsing stats row %q with columns %q", line, columns)
+			}
+
+			switch {
+			case len(columns) == 2 && columns[0] == "hostname":
+				count, err := strconv.Atoi(values[1])
+				if err != nil {
+					return UpgradeStats{}, xerrors.Errorf("parsing %s of host %s: %w", columns[1], values[0], err)
+				}
+
+				switch columns[1] {
+				case "primaries":
+					primaries[values[0]] = count
+				case "standby":
+					standby[values[0]] = count
+				default:
+					mirrors[values[0]] = count
+				}
+
+			case columns[0] == "current_database":
+				database = values[0]
+				databases[database] = make(map[string]uint64)
+
+			case database == "":
+				// ignore database stats that are not preceded by their database
+
+			case columns[0] == "databasesize":
+				size, err := parsePrettySize(values[0])
+				if err != nil {
+					return UpgradeStats{}, xerrors.Errorf("parsing size of database %s: %w", database, err)
+				}
+
+				databases[database][columns[0]] = size
+
+			default:
+				count, err := strconv.ParseUint(values[0], 10, 64)
+				if err != nil {
+					// not a count such as the stats of other scripts
+					continue
+				}
+
+				databases[database][columns[0]] = count
+			}
+		}
+
+		previous = line
+	}
+
+	if err := scanner.Err(); err != nil {
+		return UpgradeStats{}, xerrors.Errorf("reading stats: %w", err)
+	}
+
+	if len(primaries) == 0 {
+		return UpgradeStats{}, errors.New("no cluster statistics found")
+	}
+
+	hosts := make(map[string]bool)
+	var stats UpgradeStats
+	for host, count := range primaries {
+		hosts[host] = true
+		stats.Primaries += count
+	}
+
+	for host, count := range mirrors {
+		hosts[host] = true
+		stats.Mirrors += count
+	}
+
+	for _, count := range standby {
+		stats.Standby = stats.Standby || count > 0
+	}
+
+	stats.Hosts = len(hosts)
+	stats.Databases = len(databases)
+
+	for _, values := range databases {
+		stats.Size += values["databasesize"]
+		for _, column := range objectColumns {
+			stats.Objects += values[column]
+		}
+	}
+
+	return stats, nil
+}
+
+func splitRow(line string) []string {
+	var values []string
+	for _, value := range strings.Split(line, "|") {
+		values = append(values, strings.TrimSpace(value))
+	}
+
+	return values
+}
+
+// parsePrettySize parses the output of pg_size_pretty into KB.
+func parsePrettySize(input string) (uint64, error) {
+	fields := strings.Fields(input)
+	if len(fields) != 2 {
+		return 0, xerrors.Errorf("invalid size %q", input)
+	}
+
+	value, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return 0, xerrors.Errorf("invalid size %q: %w", input, err)
+	}
+
+	switch strings.ToLower(fields[1]) {
+	case "bytes":
+		return value / 1024, nil
+	case "kb":
+		return value, nil
+	case "mb":
+		return value << 10, nil
+	case "gb":
+		return value << 20, nil
+	case "tb":
+		return value << 30, nil
+	case "pb":
+		return value << 40, nil
+	}
+
+	return 0, xerrors.Errorf("invalid size unit %q", input)
+}
+
+// QueryUpgradeStats queries the catalog directly for when the stats data
+// migration scripts have not been applied. Since the catalog of each database
+// is separate the objects are counted in each database that allows
+// connections using connect.
+func QueryUpgradeStats(db *sql.DB, connect func(database string) (*sql.DB, error)) (UpgradeStats, error) {
+	var stats UpgradeStats
+
+	row := db.QueryRow(`SELECT COUNT(DISTINCT hostname),
+	SUM(CASE WHEN role = 'p' THEN 1 ELSE 0 END),
+	SUM(CASE WHEN role = 'm' THEN 1 ELSE 0 END),
+	SUM(CASE WHEN role = 'm' AND content = -1 THEN 1 ELSE 0 END) > 0
+FROM pg_catalog.gp_segment_configuration;`)
+	if err := row.Scan(&stats.Hosts, &stats.Primaries, &stats.Mirrors, &stats.Standby); err != nil {
+		return UpgradeStats{}, xerrors.Errorf("querying segments: %w", err)
+	}
+
+	var size uint64
+	row = db.QueryRow(`SELECT COUNT(*), COALESCE(SUM(pg_database_size(datname)), 0) FROM pg_catalog.pg_database WHERE datname != 'template0';`)
+	if err := row.Scan(&stats.Databases, &size); err != nil {
+		return UpgradeStats{}, xerrors.Errorf("querying databases: %w", err)
+	}
+
+	stats.Size = size / 1024
+
+	databases, err := queryConnectableDatabases(db)
+	if err != nil {
+		return UpgradeStats{}, err
+	}
+
+	for _, database := range databases {
+		objects, err := queryCatalogObjects(database, connect)
+		if err != nil {
+			return UpgradeStats{}, err
+		}
+
+		stats.Objects += objects
+	}
+
+	return stats, nil
+}
+
+func queryConnectableDatabases(db *sql.DB) ([]string, error) {
+	rows, err := db.Query(`SELECT datname FROM pg_catalog.pg_database WHERE datallowconn ORDER BY datname;`)
+	if err != nil {
+		return nil, xerrors.Errorf("querying databases: %w", err)
+	}
+	defer rows.Close()
+
+	var databases []string
+	for rows.Next() {
+		var database string
+		if err := rows.Scan(&database); err != nil {
+			return nil, xerrors.Errorf("querying databases: %w", err)
+		}
+
+		databases = append(databases, database)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, xerrors.Errorf("querying databases: %w", err)
+	}
+
+	return databases, nil
+}
+
+// catalogObjectsQuery counts the user defined relations, functions, and types
+// of a database excluding the system namespaces.
+const catalogObjectsQuery = `SELECT
+	(SELECT COUNT(*) FROM pg_catalog.pg_class c, pg_catalog.pg_namespace n WHERE c.relnamespace = n.oid AND c.relkind IN ('r', 'i', 'S', 'v') AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast', 'gp_toolkit')) +
+	(SELECT COUNT(*) FROM pg_catalog.pg_proc p, pg_catalog.pg_namespace n WHERE p.pronamespace = n.oid AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast', 'gp_toolkit')) +
+	(SELECT COUNT(*) FROM pg_catalog.pg_type t, pg_catalog.pg_namespace n WHERE t.typnamespace = n.oid AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast', 'gp_toolkit'));`
+
+func queryCatalogObjects(database string, connect func(database string) (*sql.DB, error)) (objects uint64, err error) {
+	db, err := connect(database)
+	if err != nil {
+		return 0, xerrors.Errorf("connect to database %s: %w", database, err)
+	}
+	defer func() {
+		if cErr := db.Close(); cErr != nil {
+			err = errorlist.Append(err, cErr)
+		}
+	}()
+
+	if err := db.QueryRow(catalogObjectsQuery).Scan(&objects); err != nil {
+		return 0, xerrors.Errorf("querying catalog objects of database %s: %w", database, err)
+	}
+
+	return objects, nil
+}
+
+type SubstepEstimate struct {
+	Substep  idl.Substep
+	Duration time.Duration
+}
+
+type StepEstimate struct {
+	Step     idl.Step
+	Substeps []SubstepEstimate
+}
+
+func (s StepEstimate) Duration() time.Duration {
+	var total time.Duration
+	for _, substep := range s.Substeps {
+		total += substep.Duration
+	}
+
+	return total
+}
+
+type UpgradeEstimate struct {
+	Stats         UpgradeStats
+	Mode          idl.Mode
+	PgUpgradeJobs uint
+	Steps         []StepEstimate
+}
+
+// EstimateUpgrade estimates the duration of the substeps of execute and
+// finalize that depend on the size of the cluster. pg_upgrade restores the
+// catalog objects of the coordinator using a job per database. The primaries
+// on each host then either copy or link their data. Finalize transfers the
+// data of each host to the mirrors and analyzes the target cluster.
+func EstimateUpgrade(stats UpgradeStats, mode idl.Mode, pgUpgradeJobs uint) UpgradeEstimate {
+	// pg_upgrade runs a job per database.
+	jobs := int(pgUpgradeJobs)
+	if stats.Databases < jobs {
+		jobs = stats.Databases
+	}
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	// The coordinator host usually does not have data.
+	dataHosts := stats.Hosts - 1
+	if dataHosts < 1 {
+		dataHosts = 1
+	}
+	dataPerHost := stats.Size / uint64(dataHosts)
+	catalogSize := stats.Objects * catalogObjectSize
+
+	upgradePrimaries := seconds(float64(stats.Objects) / float64(linkRate*jobs))
+	if mode == idl.Mode_copy {
+		upgradePrimaries = seconds(float64(dataPerHost) / copyRate)
+	}
+
+	execute := StepEstimate{Step: idl.Step_execute, Substeps: []SubstepEstimate{
+		{idl.Substep_upgrade_master, substepOverhead + seconds(float64(stats.Objects)/float64(restoreRate*jobs))},
+		{idl.Substep_copy_master, seconds(float64(catalogSize) / transferRate)},
+		{idl.Substep_upgrade_primaries, substepOverhead + upgradePrimaries},
+		{idl.Substep_start_target_cluster, substepOverhead},
+	}}
+
+	segmentMirrors := stats.Mirrors
+	if stats.Standby {
+		segmentMirrors--
+	}
+
+	finalize := StepEstimate{Step: idl.Step_finalize}
+	if segmentMirrors > 0 {
+		finalize.Substeps = append(finalize.Substeps,
+			SubstepEstimate{idl.Substep_upgrade_mirrors, substepOverhead + seconds(float64(dataPerHost)/transferRate)})
+	}
+	if stats.Standby {
+		finalize.Substeps = append(finalize.Substeps,
+			SubstepEstimate{idl.Substep_upgrade_standby, substepOverhead + seconds(float64(catalogSize)/transferRate)})
+	}
+	finalize.Substeps = append(finalize.Substeps,
+		SubstepEstimate{idl.Substep_start_target_cluster, substepOverhead},
+		SubstepEstimate{idl.Substep_analyze_target_cluster, seconds(float64(stats.Objects) / analyzeRate)},
+	)
+
+	for _, s := range []*StepEstimate{&execute, &finalize} {
+		for i := range s.Substeps {
+			s.Substeps[i].Duration = roundUpToMinute(s.Substeps[i].Duration)
+		}
+	}
+
+	return UpgradeEstimate{
+		Stats:         stats,
+		Mode:          mode,
+		PgUpgradeJobs: pgUpgradeJobs,
+		Steps:         []StepEstimate{execute, finalize},
+	}
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+func roundUpToMinute(d time.Duration) time.Duration {
+	return (d + time.Minute - 1).Truncate(time.Minute)
+}
+
+func (e UpgradeEstimate) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Estimated upgrade duration in %s mode with %d pg_upgrade jobs based on\n", e.Mode, e.PgUpgradeJobs)
+	fmt.Fprintf(&b, "%d hosts, %d primaries, %d mirrors, %d databases, %s of data, and %d catalog objects:\n\n",
+		e.Stats.Hosts, e.Stats.Primaries, e.Stats.Mirrors, e.Stats.Databases, disk.FormatBytes(e.Stats.Size), e.Stats.Objects)
+
+	var t tabwriter.Writer
+	t.Init(&b, 0, 0, 2, ' ', 0)
+
+	for _, step := range e.Steps {
+		fmt.Fprintf(&t, "%s\t%s\n", step.Step, step.Duration())
+		for _, substep := range step.Substeps {
+			fmt.Fprintf(&t, "  %s\t%s\n", SubstepDescriptions[substep.Substep].HelpText, substep.Duration)
+		}
+	}
+
+	t.Flush()
+
+	b.WriteString("\nThe estimate assumes typical disk and network throughput. Actual durations vary\n")
+	b.WriteString("with the hardware and the characteristics of the databases.\n")
+
+	return b.String()
+}
+
+// EstimateUpgradeDuration estimates the upgrade duration from the output of
+// the stats data migration scripts in the log directory. When they have not
+// been applied the catalog of the cluster is queried directly.
+func EstimateUpgradeDuration(gphome string, port int, logDir string, mode idl.Mode, pgUpgradeJobs uint) (UpgradeEstimate, error) {
+	var stats UpgradeStats
+
+	contents, err := utils.System.ReadFile(filepath.Join(logDir, "apply_"+idl.Step_stats.String()+".log"))
+	switch {
+	case err == nil:
+		stats, err = ParseStatsLog(bytes.NewReader(contents))
+	case errors.Is(err, fs.ErrNotExist):
+		stats, err = queryUpgradeStats(gphome, port)
+	}
+	if err != nil {
+		return UpgradeEstimate{}, err
+	}
+
+	return EstimateUpgrade(stats, mode, pgUpgradeJobs), nil
+}
+
+func queryUpgradeStats(gphome string, port int) (stats UpgradeStats, err error) {
+	db, err := bootstrapConnectionFunc(idl.ClusterDestination_source, gphome, port)
+	if err != nil {
+		return UpgradeStats{}, err
+	}
+	defer func() {
+		if cErr := db.Close(); cErr != nil {
+			err = errorlist.Append(err, cErr)
+		}
+	}()
+
+	return QueryUpgradeStats(db, func(database string) (*sql.DB, error) {
+		return databaseConnectionFunc(idl.ClusterDestination_source, gphome, port, database)
+	})
+}
+
+var databaseConnectionFunc = connection.BootstrapDatabase
+
+// XXX: for internal testing only
+func SetDatabaseConnectionFunction(connectionFunc func(destination idl.ClusterDestination, gphome string, port int, database string) (*sql.DB, error)) {
+	databaseConnectionFunc = connectionFunc
+}
+
+// XXX: for internal testing only
+func ResetDatabaseConnectionFunction() {
+	databaseConnectionFunc = connection.BootstrapDatabase
+}
@@ -0,0 +1,505 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commanders_test
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+const statsLog = `
+SELECT hostname, COUNT(dbid) AS Primaries FROM pg_catalog.gp_segment_configuration WHERE role='p' GROUP BY hostname;
+ hostname | primaries
+----------+-----------
+ mdw      |         1
+ sdw1     |         2
+ sdw2     |         2
+(3 rows)
+
+SELECT hostname, COUNT(dbid) AS Mirrors FROM pg_catalog.gp_segment_configuration WHERE role='m' GROUP BY hostname;
+ hostname | mirrors
+----------+---------
+ sdw1     |       2
+ sdw2     |       2
+(2 rows)
+
+SELECT current_database();
+ current_database
+------------------
+ postgres
+(1 row)
+
+SELECT COUNT(*) AS InstalledExtensions FROM pg_catalog.pg_extension;
+ installedextensions
+---------------------
+                   1
+(1 row)
+
+SELECT pg_size_pretty(pg_database_size(current_database())) AS DatabaseSize;
+ databasesize
+--------------
+ 64 MB
+(1 row)
+
+SELECT COUNT(*) AS OrdinaryTables FROM pg_catalog.pg_class WHERE RELKIND='r';
+ ordinarytables
+----------------
+            100
+(1 row)
+
+SELECT COUNT(*) AS IndexTables FROM pg_catalog.pg_class WHERE RELKIND='i';
+ indextables
+-------------
+          50
+(1 row)
+
+SELECT current_database();
+ current_database
+------------------
+ sales
+(1 row)
+
+SELECT pg_size_pretty(pg_database_size(current_database())) AS DatabaseSize;
+ databasesize
+--------------
+ 2 GB
+(1 row)
+
+SELECT COUNT(*) AS OrdinaryTables FROM pg_catalog.pg_class WHERE RELKIND='r';
+ ordinarytables
+----------------
+           1000
+(1 row)
+
+SELECT COUNT(*) AS Views FROM pg_catalog.pg_class WHERE RELKIND='v';
+ views
+-------
+    10
+(1 row)
+
+SELECT COUNT(*) AS Views FROM pg_catalog.pg_views;
+ views
+-------
+    12
+(1 row)
+`
+
+func TestParseStatsLog(t *testing.T) {
+	t.Run("parses the cluster and database stats", func(t *testing.T) {
+		stats, err := commanders.ParseStatsLog(strings.NewReader(statsLog))
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := commanders.UpgradeStats{
+			Hosts:     3,
+			Primaries: 5,
+			Mirrors:   4,
+			Databases: 2,
+			Size:      64<<10 + 2<<20,
+			Objects:   100 + 50 + 1000 + 12,
+		}
+		if !reflect.DeepEqual(stats, expected) {
+			t.Errorf("got %+v want %+v", stats, expected)
+		}
+	})
+
+	t.Run("parses the standby", func(t *testing.T) {
+		log := `
+SELECT hostname, COUNT(dbid) AS Primaries FROM pg_catalog.gp_segment_configuration WHERE role='p' GROUP BY hostname;
+ hostname | primaries
+----------+-----------
+ mdw      |         1
+ sdw1     |         2
+(2 rows)
+
+SELECT hostname, COUNT(dbid) AS Mirrors FROM pg_catalog.gp_segment_configuration WHERE role='m' GROUP BY hostname;
+ hostname | mirrors
+----------+---------
+ smdw     |       1
+(1 row)
+
+SELECT hostname, COUNT(dbid) AS Standby FROM pg_catalog.gp_segment_configuration WHERE role='m' AND content=-1 GROUP BY hostname;
+ hostname | standby
+----------+---------
+ smdw     |       1
+(1 row)
+`
+		stats, err := commanders.ParseStatsLog(strings.NewReader(log))
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := commanders.UpgradeStats{Hosts: 3, Primaries: 3, Mirrors: 1, Standby: true}
+		if !reflect.DeepEqual(stats, expected) {
+			t.Errorf("got %+v want %+v", stats, expected)
+		}
+	})
+
+	t.Run("uses the latest stats when the scripts were applied more than once", func(t *testing.T) {
+		stats, err := commanders.ParseStatsLog(strings.NewReader(statsLog + statsLog))
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if stats.Databases != 2 || stats.Primaries != 5 || stats.Objects != 1162 {
+			t.Errorf("got %+v want the stats of a single run", stats)
+		}
+	})
+
+	t.Run("parses the units of pg_size_pretty", func(t *testing.T) {
+		cases := map[string]uint64{
+			"8192 bytes": 8,
+			"12 kB":      12,
+			"3 MB":       3 << 10,
+			"4 TB":       4 << 30,
+		}
+
+		for size, expected := range cases {
+			log := `
+SELECT hostname, COUNT(dbid) AS Primaries FROM pg_catalog.gp_segment_configuration WHERE role='p' GROUP BY hostname;
+ hostname | primaries
+----------+-----------
+ mdw      |         1
+(1 row)
+
+ current_database
+------------------
+ postgres
+(1 row)
+
+ databasesize
+--------------
+ ` + size + `
+(1 row)
+`
+			stats, err := commanders.ParseStatsLog(strings.NewReader(log))
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if stats.Size != expected {
+				t.Errorf("got size %d for %q want %d", stats.Size, size, expected)
+			}
+		}
+	})
+
+	t.Run("errors when there are no cluster stats", func(t *testing.T) {
+		_, err := commanders.ParseStatsLog(strings.NewReader("SELECT 1;\n"))
+		expected := "no cluster statistics found"
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %v want %q", err, expected)
+		}
+	})
+
+	t.Run("errors when a database size is invalid", func(t *testing.T) {
+		log := strings.Replace(statsLog, "64 MB", "64 furlongs", 1)
+
+		_, err := commanders.ParseStatsLog(strings.NewReader(log))
+		expected := `parsing size of database postgres: invalid size unit "64 furlongs"`
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %v want %q", err, expected)
+		}
+	})
+}
+
+func TestQueryUpgradeStats(t *testing.T) {
+	t.Run("queries the cluster and database stats", func(t *testing.T) {
+		db, mock := sqlmockNew(t)
+		defer db.Close()
+
+		expectUpgradeStatsQueries(mock)
+		connect := connectDatabases(t, map[string]uint64{"postgres": 1000, "template1": 200})
+
+		stats, err := commanders.QueryUpgradeStats(db, connect)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := commanders.UpgradeStats{Hosts: 3, Primaries: 5, Mirrors: 4, Standby: true, Databases: 2, Size: 2 << 20, Objects: 1200}
+		if !reflect.DeepEqual(stats, expected) {
+			t.Errorf("got %+v want %+v", stats, expected)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("%v", err)
+		}
+	})
+
+	t.Run("errors when querying fails", func(t *testing.T) {
+		db, mock := sqlmockNew(t)
+		defer db.Close()
+
+		expected := errors.New("permission denied")
+		mock.ExpectQuery("SELECT COUNT\\(DISTINCT hostname\\)").WillReturnError(expected)
+
+		_, err := commanders.QueryUpgradeStats(db, nil)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+
+	t.Run("errors when connecting to a database fails", func(t *testing.T) {
+		db, mock := sqlmockNew(t)
+		defer db.Close()
+
+		expectUpgradeStatsQueries(mock)
+
+		expected := errors.New("database is not accepting connections")
+		_, err := commanders.QueryUpgradeStats(db, func(database string) (*sql.DB, error) {
+			return nil, expected
+		})
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+
+		if err == nil || !strings.Contains(err.Error(), "connect to database postgres") {
+			t.Errorf("got error %v want it to name the database", err)
+		}
+	})
+}
+
+func TestEstimateUpgrade(t *testing.T) {
+	stats := commanders.UpgradeStats{
+		Hosts:     3,
+		Primaries: 5,
+		Mirrors:   5,
+		Standby:   true,
+		Databases: 2,
+		Size:      200 * 1024 * 1024, // 200 GB
+		Objects:   30000,
+	}
+
+	t.Run("estimates copy mode based on the size of the data", func(t *testing.T) {
+		estimate := commanders.EstimateUpgrade(stats, idl.Mode_copy, 4)
+
+		expected := []commanders.StepEstimate{
+			{Step: idl.Step_execute, Substeps: []commanders.SubstepEstimate{
+				{Substep: idl.Substep_upgrade_master, Duration: 6 * time.Minute},
+				{Substep: idl.Substep_copy_master, Duration: time.Minute},
+				{Substep: idl.Substep_upgrade_primaries, Duration: 10 * time.Minute},
+				{Substep: idl.Substep_start_target_cluster, Duration: time.Minute},
+			}},
+			{Step: idl.Step_finalize, Substeps: []commanders.SubstepEstimate{
+				{Substep: idl.Substep_upgrade_mirrors, Duration: 19 * time.Minute},
+				{Substep: idl.Substep_upgrade_standby, Duration: 2 * time.Minute},
+				{Substep: idl.Substep_start_target_cluster, Duration: time.Minute},
+				{Substep: idl.Substep_analyze_target_cluster, Duration: 5 * time.Minute},
+			}},
+		}
+		if !reflect.DeepEqual(estimate.Steps, expected) {
+			t.Errorf("got %+v want %+v", estimate.Steps, expected)
+		}
+
+		if estimate.Steps[0].Duration() != 18*time.Minute {
+			t.Errorf("got execute duration %s want %s", estimate.Steps[0].Duration(), 18*time.Minute)
+		}
+	})
+
+	t.Run("estimates link mode based on the catalog objects", func(t *testing.T) {
+		estimate := commanders.EstimateUpgrade(stats, idl.Mode_link, 4)
+
+		execute := estimate.Steps[0]
+		if execute.Substeps[2].Substep != idl.Substep_upgrade_primaries || execute.Substeps[2].Duration != 2*time.Minute {
+			t.Errorf("got %+v want upgrade primaries to take %s", execute.Substeps[2], 2*time.Minute)
+		}
+	})
+
+	t.Run("uses at most a pg_upgrade job per database", func(t *testing.T) {
+		single := commanders.EstimateUpgrade(stats, idl.Mode_link, 1)
+		many := commanders.EstimateUpgrade(stats, idl.Mode_link, 16)
+		two := commanders.EstimateUpgrade(stats, idl.Mode_link, 2)
+
+		if !reflect.DeepEqual(many.Steps, two.Steps) {
+			t.Errorf("got %+v want %+v", many.Steps, two.Steps)
+		}
+
+		if single.Steps[0].Duration() <= two.Steps[0].Duration() {
+			t.Errorf("expected a single job to take longer than %s got %s", two.Steps[0].Duration(), single.Steps[0].Duration())
+		}
+	})
+
+	t.Run("does not estimate upgrading mirrors without mirrors", func(t *testing.T) {
+		stats := stats
+		stats.Mirrors = 0
+		stats.Standby = false
+
+		estimate := commanders.EstimateUpgrade(stats, idl.Mode_copy, 4)
+		for _, substep := range estimate.Steps[1].Substeps {
+			if substep.Substep == idl.Substep_upgrade_mirrors || substep.Substep == idl.Substep_upgrade_standby {
+				t.Errorf("unexpected substep %s", substep.Substep)
+			}
+		}
+	})
+
+	t.Run("estimates upgrading the standby without mirrors", func(t *testing.T) {
+		stats := stats
+		stats.Mirrors = 1
+
+		estimate := commanders.EstimateUpgrade(stats, idl.Mode_copy, 4)
+
+		expected := []commanders.SubstepEstimate{
+			{Substep: idl.Substep_upgrade_standby, Duration: 2 * time.Minute},
+			{Substep: idl.Substep_start_target_cluster, Duration: time.Minute},
+			{Substep: idl.Substep_analyze_target_cluster, Duration: 5 * time.Minute},
+		}
+		if !reflect.DeepEqual(estimate.Steps[1].Substeps, expected) {
+			t.Errorf("got %+v want %+v", estimate.Steps[1].Substeps, expected)
+		}
+	})
+
+	t.Run("does not estimate upgrading the standby without a standby", func(t *testing.T) {
+		stats := stats
+		stats.Mirrors = 4
+		stats.Standby = false
+
+		estimate := commanders.EstimateUpgrade(stats, idl.Mode_copy, 4)
+		for _, substep := range estimate.Steps[1].Substeps {
+			if substep.Substep == idl.Substep_upgrade_standby {
+				t.Errorf("unexpected substep %s", substep.Substep)
+			}
+		}
+
+		if estimate.Steps[1].Substeps[0].Substep != idl.Substep_upgrade_mirrors {
+			t.Errorf("got %+v want upgrading the mirrors to be estimated", estimate.Steps[1].Substeps)
+		}
+	})
+
+	t.Run("renders the estimate of each step", func(t *testing.T) {
+		actual := commanders.EstimateUpgrade(stats, idl.Mode_copy, 4).String()
+
+		for _, expected := range []string{
+			"Estimated upgrade duration in copy mode with 4 pg_upgrade jobs",
+			"3 hosts, 5 primaries, 5 mirrors, 2 databases",
+			"execute",
+			"  Upgrade primary segments",
+			"finalize",
+			"  Upgrade mirror segments",
+		} {
+			if !strings.Contains(actual, expected) {
+				t.Errorf("expected %q to contain %q", actual, expected)
+			}
+		}
+	})
+}
+
+func TestEstimateUpgradeDuration(t *testing.T) {
+	t.Run("estimates from the stats log in the log directory", func(t *testing.T) {
+		logDir := t.TempDir()
+		testutils.MustWriteToFile(t, filepath.Join(logDir, "apply_stats.log"), statsLog)
+
+		estimate, err := commanders.EstimateUpgradeDuration("", 0, logDir, idl.Mode_link, 4)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if estimate.Stats.Hosts != 3 || estimate.Mode != idl.Mode_link || estimate.PgUpgradeJobs != 4 {
+			t.Errorf("got %+v want an estimate from the stats log", estimate)
+		}
+	})
+
+	t.Run("queries the cluster when the stats scripts have not been applied", func(t *testing.T) {
+		db, mock := sqlmockNew(t)
+		expectUpgradeStatsQueries(mock)
+		mock.ExpectClose()
+
+		commanders.SetBootstrapConnectionFunction(func(destination idl.ClusterDestination, gphome string, port int) (*sql.DB, error) {
+			return db, nil
+		})
+		defer commanders.ResetBootstrapConnectionFunction()
+
+		connect := connectDatabases(t, map[string]uint64{"postgres": 1000, "template1": 200})
+		commanders.SetDatabaseConnectionFunction(func(destination idl.ClusterDestination, gphome string, port int, database string) (*sql.DB, error) {
+			return connect(database)
+		})
+		defer commanders.ResetDatabaseConnectionFunction()
+
+		estimate, err := commanders.EstimateUpgradeDuration("", 0, t.TempDir(), idl.Mode_copy, 4)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if estimate.Stats.Objects != 1200 {
+			t.Errorf("got %+v want an estimate from the queried stats", estimate)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("%v", err)
+		}
+	})
+
+	t.Run("errors when reading the stats log fails", func(t *testing.T) {
+		logDir := t.TempDir()
+		path := filepath.Join(logDir, "apply_stats.log")
+		testutils.MustWriteToFile(t, path, statsLog)
+
+		err := os.Chmod(path, 0000)
+		if err != nil {
+			t.Fatalf("chmod: %v", err)
+		}
+
+		_, err = commanders.EstimateUpgradeDuration("", 0, logDir, idl.Mode_copy, 4)
+		if os.Geteuid() != 0 && !errors.Is(err, os.ErrPermission) {
+			t.Errorf("got error %#v want %#v", err, os.ErrPermission)
+		}
+	})
+}
+
+func sqlmockNew(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("couldn't create sqlmock: %v", err)
+	}
+
+	return db, mock
+}
+
+func expectUpgradeStatsQueries(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("SELECT COUNT\\(DISTINCT hostname\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"count", "primaries", "mirrors", "standby"}).AddRow(3, 5, 4, true))
+	mock.ExpectQuery("FROM pg_catalog.pg_database").
+		WillReturnRows(sqlmock.NewRows([]string{"count", "size"}).AddRow(2, 2<<30))
+	mock.ExpectQuery("WHERE datallowconn").
+		WillReturnRows(sqlmock.NewRows([]string{"datname"}).AddRow("postgres").AddRow("template1"))
+}
+
+// connectDatabases returns a connection to each database that reports its
+// number of catalog objects.
+func connectDatabases(t *testing.T, objects map[string]uint64) func(database string) (*sql.DB, error) {
+	t.Helper()
+
+	return func(database string) (*sql.DB, error) {
+		count, ok := objects[database]
+		if !ok {
+			t.Errorf("unexpected connection to database %s", database)
+		}
+
+		db, mock := sqlmockNew(t)
+		mock.ExpectQuery("FROM pg_catalog.pg_class c, pg_catalog.pg_namespace n").
+			WillReturnRows(sqlmock.NewRows([]string{"objects"}).AddRow(count))
+		mock.ExpectClose()
+
+		t.Cleanup(func() {
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("database %s: %v", database, err)
+			}
+		})
+
+		return db, nil
+	}
+}
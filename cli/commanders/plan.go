@@ -0,0 +1,46 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commanders
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+
+	"github.com/greenplum-db/gpupgrade/step"
+)
+
+// FormatPlan renders the substeps a step would run during a dry run along
+// with the hosts, directories, and ports each substep touches.
+func FormatPlan(plan *step.Plan) string {
+	var output strings.Builder
+
+	title := cases.Title(language.English).String(plan.Step.String())
+	output.WriteString(fmt.Sprintf("%s dry run. No changes will be made.\n\n", title))
+
+	for _, substep := range plan.Substeps {
+		indicator := "[WILL RUN]"
+		if !substep.WillRun {
+			indicator = "[WILL SKIP]"
+		}
+
+		output.WriteString(fmt.Sprintf("%-67s%-13s\n", substepDescription(substep.Substep), indicator))
+
+		if substep.Reason != "" {
+			output.WriteString("    (" + substep.Reason + ")\n")
+		}
+
+		if !substep.WillRun {
+			continue
+		}
+
+		for _, detail := range substep.Details {
+			output.WriteString("    " + detail + "\n")
+		}
+	}
+
+	return output.String()
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commanders_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+)
+
+func TestFormatPlan(t *testing.T) {
+	plan := &step.Plan{
+		Step: idl.Step_finalize,
+		Substeps: []*step.PlannedSubstep{
+			{Substep: idl.Substep_upgrade_mirrors, WillRun: true, Reason: "link mode with mirrors using rsync", Details: []string{"rsync sdw1:/data/primary to sdw2:50435 /data/mirror"}},
+			{Substep: idl.Substep_upgrade_mirrors, WillRun: false, Reason: "not copy mode with mirrors using gpaddmirrors", Details: []string{"add mirror sdw2:50435 /data/mirror"}},
+			{Substep: idl.Substep_update_data_directories, WillRun: true, Details: []string{"sdw1: /data/primary -> /data/primary.old"}},
+		},
+	}
+
+	description := commanders.SubstepDescriptions[idl.Substep_upgrade_mirrors].OutputText
+	expected := "Finalize dry run. No changes will be made.\n\n"
+	expected += fmt.Sprintf("%-67s%-13s\n", description, "[WILL RUN]")
+	expected += "    (link mode with mirrors using rsync)\n"
+	expected += "    rsync sdw1:/data/primary to sdw2:50435 /data/mirror\n"
+	expected += fmt.Sprintf("%-67s%-13s\n", description, "[WILL SKIP]")
+	expected += "    (not copy mode with mirrors using gpaddmirrors)\n"
+	expected += fmt.Sprintf("%-67s%-13s\n", commanders.SubstepDescriptions[idl.Substep_update_data_directories].OutputText, "[WILL RUN]")
+	expected += "    sdw1: /data/primary -> /data/primary.old\n"
+
+	actual := commanders.FormatPlan(plan)
+	if actual != expected {
+		t.Errorf("got %q want %q", actual, expected)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commanders
+
+import (
+	"fmt"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+// lineWidth matches the width of a formatted substep status such that
+// segment lines can be overwritten in place.
+const lineWidth = 80
+
+// segmentProgress tracks the per-host and per-segment progress of pg_upgrade
+// such that long running substeps like upgrade_primaries show which segments
+// are still running rather than a single status for hours.
+type segmentProgress struct {
+	hosts    map[string]*hostProgress
+	segments map[string]idl.Status
+	total    int
+	done     int
+}
+
+type hostProgress struct {
+	total int
+	done  int
+}
+
+func newSegmentProgress() *segmentProgress {
+	return &segmentProgress{
+		hosts:    make(map[string]*hostProgress),
+		segments: make(map[string]idl.Status),
+	}
+}
+
+// update returns the line to display along with whether the line is final
+// rather than to be overwritten by the next update. An empty line means there
+// is nothing to display.
+func (p *segmentProgress) update(progress *idl.SegmentProgress) (string, bool) {
+	host, ok := p.hosts[progress.GetHost()]
+	if !ok {
+		host = &hostProgress{}
+		p.hosts[progress.GetHost()] = host
+	}
+
+	key := fmt.Sprintf("%s/%d", progress.GetHost(), progress.GetContentID())
+	previous, ok := p.segments[key]
+	if !ok {
+		host.total++
+		p.total++
+	}
+	p.segments[key] = progress.GetStatus()
+
+	switch progress.GetStatus() {
+	case idl.Status_running:
+		if progress.GetProgress() == "" {
+			return "", false
+		}
+
+		line := fmt.Sprintf("  %s content %d: %s", progress.GetHost(), progress.GetContentID(), progress.GetProgress())
+		return fmt.Sprintf("%-*s", lineWidth, truncate(line, lineWidth)), false
+
+	case idl.Status_complete, idl.Status_failed, idl.Status_skipped:
+		if !isFinal(previous) {
+			host.done++
+			p.done++
+		}
+
+		description := fmt.Sprintf("  %s content %d (%d/%d on host, %d/%d total)",
+			progress.GetHost(), progress.GetContentID(), host.done, host.total, p.done, p.total)
+		return Format(description, progress.GetStatus()), true
+	}
+
+	return "", false
+}
+
+// isFinal returns whether the segment is no longer running. Skipped segments
+// completed during a previous run.
+func isFinal(status idl.Status) bool {
+	return status == idl.Status_complete || status == idl.Status_failed || status == idl.Status_skipped
+}
+
+func truncate(line string, width int) string {
+	runes := []rune(line)
+	if len(runes) <= width {
+		return line
+	}
+
+	return string(runes[:width])
+}
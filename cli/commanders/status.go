@@ -0,0 +1,148 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commanders
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+const noStepsRunText = "No gpupgrade steps have been run. To start an upgrade run \"gpupgrade initialize\".\n"
+
+func GetStatus(client idl.CliToHubClient) ([]*idl.StepStatus, error) {
+	reply, err := client.GetStatus(context.Background(), &idl.GetStatusRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return reply.GetSteps(), nil
+}
+
+// FormatStepStatuses renders the step statuses in either "text" or "json"
+// format.
+func FormatStepStatuses(steps []*idl.StepStatus, format string) (string, error) {
+	switch format {
+	case "", "text":
+		return formatStepStatusesText(steps), nil
+	case "json":
+		return formatStepStatusesJSON(steps)
+	}
+
+	return "", xerrors.Errorf("invalid format %q. Expected either \"text\" or \"json\".", format)
+}
+
+func formatStepStatusesText(steps []*idl.StepStatus) string {
+	if len(steps) == 0 {
+		return noStepsRunText
+	}
+
+	var output strings.Builder
+	for _, step := range steps {
+		title := cases.Title(language.English).String(step.GetStep().String())
+		output.WriteString(formatLine(title, step.GetStatus()) + "\n")
+
+		for _, substep := range step.GetSubsteps() {
+			output.WriteString(formatLine("  "+substepDescription(substep.GetSubstep()), substep.GetStatus()) + "\n")
+
+			if substep.GetStartTime() != nil {
+				details := "    started " + substep.GetStartTime().AsTime().Local().Format(time.RFC3339)
+				if substep.GetDuration() != nil {
+					details += " took " + substep.GetDuration().AsDuration().Round(time.Millisecond).String()
+				}
+				output.WriteString(details + "\n")
+			}
+
+			if substep.GetError() != "" {
+				output.WriteString("    error: " + substep.GetError() + "\n")
+			}
+		}
+
+		output.WriteString("\n")
+	}
+
+	return output.String()
+}
+
+// formatLine is similar to Format but handles steps and substeps that have
+// not yet reported a status.
+func formatLine(description string, status idl.Status) string {
+	if _, ok := indicators[status]; !ok {
+		return fmt.Sprintf("%-67s%-13s", description, "[NOT STARTED]")
+	}
+
+	return Format(description, status)
+}
+
+func substepDescription(substep idl.Substep) string {
+	text, ok := SubstepDescriptions[substep]
+	if !ok {
+		return substep.String()
+	}
+
+	return text.OutputText
+}
+
+type stepStatusJSON struct {
+	Step     string              `json:"step"`
+	Status   string              `json:"status"`
+	Substeps []substepStatusJSON `json:"substeps"`
+}
+
+type substepStatusJSON struct {
+	Substep     string     `json:"substep"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	StartTime   *time.Time `json:"start_time,omitempty"`
+	Duration    string     `json:"duration,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+func formatStepStatusesJSON(steps []*idl.StepStatus) (string, error) {
+	output := make([]stepStatusJSON, 0, len(steps))
+	for _, step := range steps {
+		stepJSON := stepStatusJSON{
+			Step:     step.GetStep().String(),
+			Status:   step.GetStatus().String(),
+			Substeps: make([]substepStatusJSON, 0, len(step.GetSubsteps())),
+		}
+
+		for _, substep := range step.GetSubsteps() {
+			substepJSON := substepStatusJSON{
+				Substep:     substep.GetSubstep().String(),
+				Description: substepDescription(substep.GetSubstep()),
+				Status:      substep.GetStatus().String(),
+				Error:       substep.GetError(),
+			}
+
+			if substep.GetStartTime() != nil {
+				startTime := substep.GetStartTime().AsTime()
+				substepJSON.StartTime = &startTime
+			}
+
+			if substep.GetDuration() != nil {
+				substepJSON.Duration = substep.GetDuration().AsDuration().String()
+			}
+
+			stepJSON.Substeps = append(stepJSON.Substeps, substepJSON)
+		}
+
+		output = append(output, stepJSON)
+	}
+
+	data, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", xerrors.Errorf("marshal status: %w", err)
+	}
+
+	return string(data) + "\n", nil
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commanders_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+)
+
+func TestGetStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("returns the steps from the hub", func(t *testing.T) {
+		expected := []*idl.StepStatus{{Step: idl.Step_initialize, Status: idl.Status_complete}}
+
+		client := mock_idl.NewMockCliToHubClient(ctrl)
+		client.EXPECT().GetStatus(gomock.Any(), &idl.GetStatusRequest{}).Return(&idl.GetStatusReply{Steps: expected}, nil)
+
+		steps, err := commanders.GetStatus(client)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		if !reflect.DeepEqual(steps, expected) {
+			t.Errorf("got %v want %v", steps, expected)
+		}
+	})
+
+	t.Run("returns errors from the hub", func(t *testing.T) {
+		expected := errors.New("permission denied")
+
+		client := mock_idl.NewMockCliToHubClient(ctrl)
+		client.EXPECT().GetStatus(gomock.Any(), gomock.Any()).Return(nil, expected)
+
+		_, err := commanders.GetStatus(client)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+}
+
+func TestFormatStepStatuses(t *testing.T) {
+	startTime := time.Date(2023, time.March, 1, 10, 30, 0, 0, time.UTC)
+
+	steps := []*idl.StepStatus{
+		{Step: idl.Step_initialize, Status: idl.Status_complete, Substeps: []*idl.SubstepDetails{
+			{Substep: idl.Substep_check_upgrade, Status: idl.Status_complete, StartTime: timestamppb.New(startTime), Duration: durationpb.New(90 * time.Second)},
+		}},
+		{Step: idl.Step_execute, Status: idl.Status_failed, Substeps: []*idl.SubstepDetails{
+			{Substep: idl.Substep_upgrade_master, Status: idl.Status_failed, Error: "pg_upgrade failed"},
+		}},
+	}
+
+	t.Run("formats text output", func(t *testing.T) {
+		output, err := commanders.FormatStepStatuses(steps, "text")
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := []string{
+			commanders.Format("Initialize", idl.Status_complete),
+			commanders.Format("  "+commanders.SubstepDescriptions[idl.Substep_check_upgrade].OutputText, idl.Status_complete),
+			"took 1m30s",
+			commanders.Format("Execute", idl.Status_failed),
+			commanders.Format("  "+commanders.SubstepDescriptions[idl.Substep_upgrade_master].OutputText, idl.Status_failed),
+			"error: pg_upgrade failed",
+		}
+
+		for _, line := range expected {
+			if !strings.Contains(output, line) {
+				t.Errorf("expected output %q to contain %q", output, line)
+			}
+		}
+	})
+
+	t.Run("formats steps that have not reported a status", func(t *testing.T) {
+		output, err := commanders.FormatStepStatuses([]*idl.StepStatus{{Step: idl.Step_initialize}}, "text")
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if !strings.Contains(output, "[NOT STARTED]") {
+			t.Errorf("expected output %q to contain %q", output, "[NOT STARTED]")
+		}
+	})
+
+	t.Run("formats text output when no steps have been run", func(t *testing.T) {
+		output, err := commanders.FormatStepStatuses(nil, "text")
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if !strings.Contains(output, "No gpupgrade steps have been run") {
+			t.Errorf("unexpected output %q", output)
+		}
+	})
+
+	t.Run("formats json output", func(t *testing.T) {
+		output, err := commanders.FormatStepStatuses(steps, "json")
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		var actual []map[string]interface{}
+		err = json.Unmarshal([]byte(output), &actual)
+		if err != nil {
+			t.Fatalf("unmarshal %q: %v", output, err)
+		}
+
+		if len(actual) != 2 {
+			t.Fatalf("got %d steps want 2", len(actual))
+		}
+
+		if actual[0]["step"] != "initialize" || actual[0]["status"] != "complete" {
+			t.Errorf("unexpected step %v", actual[0])
+		}
+
+		substep := actual[0]["substeps"].([]interface{})[0].(map[string]interface{})
+		if substep["substep"] != "check_upgrade" || substep["start_time"] != "2023-03-01T10:30:00Z" || substep["duration"] != "1m30s" {
+			t.Errorf("unexpected substep %v", substep)
+		}
+
+		substep = actual[1]["substeps"].([]interface{})[0].(map[string]interface{})
+		if substep["error"] != "pg_upgrade failed" {
+			t.Errorf("unexpected substep %v", substep)
+		}
+	})
+
+	t.Run("errors on an invalid format", func(t *testing.T) {
+		_, err := commanders.FormatStepStatuses(steps, "xml")
+		if err == nil {
+			t.Errorf("expected an error")
+		}
+	})
+}
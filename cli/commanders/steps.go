@@ -124,6 +124,11 @@ func UILoop(stream receiver, verbose bool) (*idl.Response, error) {
 	var lastStep idl.Substep
 	var err error
 
+	// Segment progress is shown below the substep status. The current line
+	// is a segment line which is overwritten until the segment finishes.
+	progress := newSegmentProgress()
+	var segmentLine, overwriteSegmentLine bool
+
 	for {
 		var msg *idl.Message
 		msg, err = stream.Recv()
@@ -148,7 +153,11 @@ func UILoop(stream receiver, verbose bool) (*idl.Response, error) {
 			// current step. (This behavior is switched off in verbose mode,
 			// because it interferes with the output stream.)
 			if !verbose {
-				if lastStep == idl.Substep_unknown_substep {
+				if segmentLine && overwriteSegmentLine {
+					fmt.Print("\r")
+				} else if segmentLine {
+					fmt.Println()
+				} else if lastStep == idl.Substep_unknown_substep {
 					// This is the first call, so we don't need to "terminate"
 					// the previous line at all.
 				} else if x.Status.Step == lastStep {
@@ -158,12 +167,44 @@ func UILoop(stream receiver, verbose bool) (*idl.Response, error) {
 				}
 			}
 			lastStep = x.Status.Step
+			segmentLine = false
 
 			fmt.Print(FormatStatus(x.Status))
 			if verbose {
 				fmt.Println()
 			}
 
+		case *idl.Message_SegmentProgress:
+			// In verbose mode the pg_upgrade output is already shown.
+			line, final := progress.update(x.SegmentProgress)
+			if verbose || line == "" {
+				continue
+			}
+
+			if segmentLine && overwriteSegmentLine {
+				fmt.Print("\r")
+			} else {
+				fmt.Println()
+			}
+
+			fmt.Print(line)
+			segmentLine = true
+			overwriteSegmentLine = !final
+
+		case *idl.Message_AgentLost:
+			// Shown in both modes since the substep is failing due to it.
+			line := fmt.Sprintf("  agent on host %s lost", x.AgentLost.GetHost())
+			if verbose {
+				fmt.Println(line)
+				continue
+			}
+
+			// Keep the last segment line rather than overwriting it.
+			fmt.Println()
+			fmt.Print(line)
+			segmentLine = true
+			overwriteSegmentLine = false
+
 		case *idl.Message_Response:
 			response = x.Response
 
@@ -5,7 +5,9 @@ package commanders_test
 
 import (
 	"errors"
+	"fmt"
 	"io"
+	"strings"
 	"testing"
 
 	"google.golang.org/grpc/codes"
@@ -223,6 +225,153 @@ func TestUILoop(t *testing.T) {
 		}
 	})
 
+	t.Run("shows per-segment progress below the substep status in non-verbose mode", func(t *testing.T) {
+		running := &idl.SubstepStatus{Step: idl.Substep_upgrade_primaries, Status: idl.Status_running}
+		failed := &idl.SubstepStatus{Step: idl.Substep_upgrade_primaries, Status: idl.Status_failed}
+
+		msgs := msgStream{
+			{Contents: &idl.Message_Status{Status: running}},
+			{Contents: &idl.Message_SegmentProgress{SegmentProgress: &idl.SegmentProgress{Host: "sdw1", ContentID: 0, Status: idl.Status_running}}},
+			{Contents: &idl.Message_SegmentProgress{SegmentProgress: &idl.SegmentProgress{Host: "sdw1", ContentID: 1, Status: idl.Status_running}}},
+			{Contents: &idl.Message_SegmentProgress{SegmentProgress: &idl.SegmentProgress{Host: "sdw2", ContentID: 2, Status: idl.Status_running}}},
+			{Contents: &idl.Message_SegmentProgress{SegmentProgress: &idl.SegmentProgress{Host: "sdw1", ContentID: 0, Status: idl.Status_running, Progress: "copying 1/2"}}},
+			{Contents: &idl.Message_SegmentProgress{SegmentProgress: &idl.SegmentProgress{Host: "sdw1", ContentID: 0, Status: idl.Status_complete}}},
+			{Contents: &idl.Message_SegmentProgress{SegmentProgress: &idl.SegmentProgress{Host: "sdw2", ContentID: 2, Status: idl.Status_running, Progress: "copying 1/2"}}},
+			{Contents: &idl.Message_SegmentProgress{SegmentProgress: &idl.SegmentProgress{Host: "sdw2", ContentID: 2, Status: idl.Status_failed}}},
+			{Contents: &idl.Message_SegmentProgress{SegmentProgress: &idl.SegmentProgress{Host: "sdw1", ContentID: 1, Status: idl.Status_running, Progress: "copying 2/2"}}},
+			{Contents: &idl.Message_Status{Status: failed}},
+		}
+
+		expected := commanders.FormatStatus(running) + "\n"
+		expected += fmt.Sprintf("%-80s", "  sdw1 content 0: copying 1/2") + "\r"
+		expected += commanders.Format("  sdw1 content 0 (1/2 on host, 1/3 total)", idl.Status_complete) + "\n"
+		expected += fmt.Sprintf("%-80s", "  sdw2 content 2: copying 1/2") + "\r"
+		expected += commanders.Format("  sdw2 content 2 (1/1 on host, 2/3 total)", idl.Status_failed) + "\n"
+		expected += fmt.Sprintf("%-80s", "  sdw1 content 1: copying 2/2") + "\r"
+		expected += commanders.FormatStatus(failed) + "\n"
+
+		d := BufferStandardDescriptors(t)
+		defer d.Close()
+
+		_, err := commanders.UILoop(&msgs, false)
+		if err != nil {
+			t.Errorf("UILoop() returned %#v", err)
+		}
+
+		actualOut, actualErr := d.Collect()
+
+		if len(actualErr) != 0 {
+			t.Errorf("unexpected stderr %#v", string(actualErr))
+		}
+
+		actual := string(actualOut)
+		if actual != expected {
+			t.Errorf("output %q want %q", actual, expected)
+		}
+	})
+
+	t.Run("shows lost agents below the substep status", func(t *testing.T) {
+		running := &idl.SubstepStatus{Step: idl.Substep_upgrade_primaries, Status: idl.Status_running}
+		failed := &idl.SubstepStatus{Step: idl.Substep_upgrade_primaries, Status: idl.Status_failed}
+
+		msgs := msgStream{
+			{Contents: &idl.Message_Status{Status: running}},
+			{Contents: &idl.Message_SegmentProgress{SegmentProgress: &idl.SegmentProgress{Host: "sdw1", ContentID: 0, Status: idl.Status_running, Progress: "copying 1/2"}}},
+			{Contents: &idl.Message_AgentLost{AgentLost: &idl.AgentLost{Host: "sdw1"}}},
+			{Contents: &idl.Message_Status{Status: failed}},
+		}
+
+		expected := commanders.FormatStatus(running) + "\n"
+		expected += fmt.Sprintf("%-80s", "  sdw1 content 0: copying 1/2") + "\n"
+		expected += "  agent on host sdw1 lost" + "\n"
+		expected += commanders.FormatStatus(failed) + "\n"
+
+		d := BufferStandardDescriptors(t)
+		defer d.Close()
+
+		_, err := commanders.UILoop(&msgs, false)
+		if err != nil {
+			t.Errorf("UILoop() returned %#v", err)
+		}
+
+		actualOut, _ := d.Collect()
+
+		actual := string(actualOut)
+		if actual != expected {
+			t.Errorf("output %q want %q", actual, expected)
+		}
+	})
+
+	t.Run("truncates long segment progress to the line width", func(t *testing.T) {
+		msgs := msgStream{
+			{Contents: &idl.Message_SegmentProgress{SegmentProgress: &idl.SegmentProgress{
+				Host: "sdw1", ContentID: 0, Status: idl.Status_running, Progress: strings.Repeat("x", 100),
+			}}},
+		}
+
+		d := BufferStandardDescriptors(t)
+		defer d.Close()
+
+		_, err := commanders.UILoop(&msgs, false)
+		if err != nil {
+			t.Errorf("UILoop() returned %#v", err)
+		}
+
+		actualOut, _ := d.Collect()
+
+		lines := strings.Split(strings.Trim(string(actualOut), "\n"), "\n")
+		if len(lines) != 1 || len(lines[0]) != 80 {
+			t.Errorf("got %q want a single line of 80 characters", actualOut)
+		}
+	})
+
+	t.Run("shows segments completed by a previous run as skipped", func(t *testing.T) {
+		msgs := msgStream{
+			{Contents: &idl.Message_SegmentProgress{SegmentProgress: &idl.SegmentProgress{Host: "sdw1", ContentID: 0, Status: idl.Status_skipped}}},
+			{Contents: &idl.Message_SegmentProgress{SegmentProgress: &idl.SegmentProgress{Host: "sdw1", ContentID: 1, Status: idl.Status_running}}},
+			{Contents: &idl.Message_SegmentProgress{SegmentProgress: &idl.SegmentProgress{Host: "sdw1", ContentID: 1, Status: idl.Status_complete}}},
+		}
+
+		expected := "\n" + commanders.Format("  sdw1 content 0 (1/1 on host, 1/1 total)", idl.Status_skipped) + "\n"
+		expected += commanders.Format("  sdw1 content 1 (2/2 on host, 2/2 total)", idl.Status_complete) + "\n"
+
+		d := BufferStandardDescriptors(t)
+		defer d.Close()
+
+		_, err := commanders.UILoop(&msgs, false)
+		if err != nil {
+			t.Errorf("UILoop() returned %#v", err)
+		}
+
+		actualOut, _ := d.Collect()
+
+		actual := string(actualOut)
+		if actual != expected {
+			t.Errorf("output %q want %q", actual, expected)
+		}
+	})
+
+	t.Run("ignores segment progress in verbose mode", func(t *testing.T) {
+		msgs := msgStream{
+			{Contents: &idl.Message_SegmentProgress{SegmentProgress: &idl.SegmentProgress{
+				Host: "sdw1", ContentID: 0, Status: idl.Status_complete,
+			}}},
+		}
+
+		d := BufferStandardDescriptors(t)
+		defer d.Close()
+
+		_, err := commanders.UILoop(&msgs, true)
+		if err != nil {
+			t.Errorf("UILoop() returned %#v", err)
+		}
+
+		actualOut, _ := d.Collect()
+		if len(actualOut) != 0 {
+			t.Errorf("unexpected stdout %q", actualOut)
+		}
+	})
+
 	t.Run("processes responses successfully", func(t *testing.T) {
 		cases := []struct {
 			name     string
@@ -33,6 +33,7 @@ var SubstepDescriptions = map[idl.Substep]substepText{
 	idl.Substep_create_backupdirs:                                             substepText{"Creating internal backup directories on the segments...", "Create internal backup directories on the segments"},
 	idl.Substep_check_disk_space:                                              substepText{"Checking disk space...", "Check disk space"},
 	idl.Substep_generate_target_config:                                        substepText{"Generating target cluster configuration...", "Generate target cluster configuration"},
+	idl.Substep_check_target_cluster_ports:                                    substepText{"Checking target cluster ports are available...", "Check target cluster ports are available"},
 	idl.Substep_init_target_cluster:                                           substepText{"Creating target cluster...", "Create target cluster"},
 	idl.Substep_setting_dynamic_library_path_on_target_cluster:                substepText{"Setting dynamic library path on target cluster...", "Set dynamic library path on target cluster"},
 	idl.Substep_shutdown_target_cluster:                                       substepText{"Stopping target cluster...", "Stop target cluster"},
@@ -68,6 +69,7 @@ var SubstepDescriptions = map[idl.Substep]substepText{
 	idl.Substep_execute_initialize_data_migration_scripts:                     substepText{"Executing initialize data migration SQL scripts...", "Executed initialize data migration SQL scripts"},
 	idl.Substep_execute_finalize_data_migration_scripts:                       substepText{"Executing finalize data migration SQL scripts...", "Executed finalize data migration SQL scripts"},
 	idl.Substep_execute_revert_data_migration_scripts:                         substepText{"Executing revert data migration SQL scripts...", "Executed revert data migration SQL scripts"},
+	idl.Substep_write_upgrade_report:                                          substepText{"Writing upgrade report...", "Write upgrade report"},
 	idl.Substep_analyze_target_cluster:                                        substepText{"Analyzing target cluster...", "Analyze target cluster"},
 	idl.Substep_ensure_gpupgrade_agents_are_running:                           substepText{"Ensuring gpupgrade agent processes are running...", "Ensure gpupgrade agent processes are running"},
 	idl.Substep_verify_gpdb_versions:                                          substepText{"Verifying source and target cluster versions...", "Verify source and target cluster versions"},
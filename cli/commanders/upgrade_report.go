@@ -0,0 +1,550 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commanders
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+const (
+	UpgradeReportMarkdownFile = "upgrade_report.md"
+	UpgradeReportHTMLFile     = "upgrade_report.html"
+)
+
+// AppliedScripts are the data migration script directories applied during a
+// phase.
+type AppliedScripts struct {
+	Phase   idl.Step
+	Scripts []string
+}
+
+// SegmentStatus is a row of gp_segment_configuration.
+type SegmentStatus struct {
+	DbID          int
+	ContentID     int
+	Role          string
+	PreferredRole string
+	Mode          string
+	Status        string
+	Port          int
+	Hostname      string
+	Address       string
+	DataDir       string
+}
+
+// UpgradeReport summarizes a finalized or reverted upgrade for change
+// management records.
+type UpgradeReport struct {
+	Step          idl.Step
+	Time          time.Time
+	UpgradeID     string
+	Mode          idl.Mode
+	SourceVersion string
+	SourceGPHome  string
+	TargetVersion string
+	TargetGPHome  string
+
+	// Before is the topology of the source cluster and After is that of the
+	// cluster left running, which is the target cluster after finalize and
+	// the source cluster after revert.
+	Before greenplum.SegConfigs
+	After  greenplum.SegConfigs
+
+	Steps                []*idl.StepStatus
+	Scripts              []AppliedScripts
+	Warnings             []string
+	SegmentConfiguration []SegmentStatus
+}
+
+// NewUpgradeReport gathers the report from the configuration, the substep
+// status in the state directory, the apply logs in the log archive directory,
+// and gp_segment_configuration of the running cluster. Sections that cannot be
+// gathered are reported as warnings rather than errors such that a report is
+// always written.
+func NewUpgradeReport(stepName idl.Step, conf *config.Config, stateDir string, logArchiveDir string) UpgradeReport {
+	report := UpgradeReport{
+		Step:      stepName,
+		Time:      time.Now(),
+		UpgradeID: conf.UpgradeID,
+		Mode:      conf.Mode,
+	}
+
+	if conf.Source != nil {
+		report.SourceVersion = conf.Source.Version.String()
+		report.SourceGPHome = conf.Source.GPHome
+		report.Before = topology(conf.Source)
+	}
+
+	if conf.Target != nil {
+		report.TargetVersion = conf.Target.Version.String()
+		report.TargetGPHome = conf.Target.GPHome
+	}
+
+	running := conf.Source
+	if stepName == idl.Step_finalize {
+		running = conf.Target
+	}
+
+	if running != nil {
+		report.After = topology(running)
+	}
+
+	steps, err := step.LoadStatus(stateDir)
+	if err != nil {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("Unable to read the substep status: %v", err))
+	}
+	report.Steps = steps
+
+	warnings, err := failedRuns(stateDir, steps)
+	if err != nil {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("Unable to read the failed substeps: %v", err))
+	}
+	report.Warnings = append(report.Warnings, warnings...)
+
+	scripts, err := ReadAppliedDataMigrationScripts(logArchiveDir)
+	if err != nil {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("Unable to read the applied data migration scripts: %v", err))
+	}
+	report.Scripts = scripts
+
+	if running == nil {
+		return report
+	}
+
+	segments, err := querySegmentConfiguration(running)
+	if err != nil {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("Unable to query gp_segment_configuration of the %s cluster: %v", running.Destination, err))
+	}
+	report.SegmentConfiguration = segments
+
+	for _, seg := range segments {
+		if seg.Status != "u" {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("Segment with dbid %d and content %d on %s is down.", seg.DbID, seg.ContentID, seg.Hostname))
+		}
+
+		if seg.Role != seg.PreferredRole {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("Segment with dbid %d and content %d on %s is not in its preferred role.", seg.DbID, seg.ContentID, seg.Hostname))
+		}
+	}
+
+	return report
+}
+
+func topology(cluster *greenplum.Cluster) greenplum.SegConfigs {
+	segments := cluster.SelectSegments(func(*greenplum.SegConfig) bool { return true })
+	sort.Sort(segments)
+	return segments
+}
+
+// failedRuns returns a warning for each failed run of the substeps including
+// earlier runs that were retried.
+func failedRuns(stateDir string, steps []*idl.StepStatus) ([]string, error) {
+	store := step.NewSubstepStoreUsingFile(filepath.Join(stateDir, step.SubstepsFileName))
+
+	var warnings []string
+	for _, stepStatus := range steps {
+		entries, err := store.ReadEntries(stepStatus.GetStep())
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil, nil
+			}
+
+			return nil, err
+		}
+
+		for _, substep := range stepStatus.GetSubsteps() {
+			entry, ok := entries[substep.GetSubstep().String()]
+			if !ok {
+				continue
+			}
+
+			runs := append(entry.History, entry.SubstepRun)
+			for _, run := range runs {
+				if run.Status.Status != idl.Status_failed {
+					continue
+				}
+
+				warning := fmt.Sprintf("%s substep %q failed", stepStatus.GetStep(), substepDescription(substep.GetSubstep()))
+				if run.StartTime != nil {
+					warning += " at " + run.StartTime.Local().Format(time.RFC3339)
+				}
+				if run.Error != "" {
+					warning += ": " + run.Error
+				}
+
+				warnings = append(warnings, warning)
+			}
+		}
+	}
+
+	return warnings, nil
+}
+
+// ReadAppliedDataMigrationScripts returns the data migration script
+// directories applied during each phase from the apply logs in the log
+// directory.
+func ReadAppliedDataMigrationScripts(logDir string) ([]AppliedScripts, error) {
+	var applied []AppliedScripts
+	for _, phase := range MigrationScriptPhases {
+		contents, err := utils.System.ReadFile(filepath.Join(logDir, "apply_"+phase.String()+".log"))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		scripts := AppliedScripts{Phase: phase}
+		seen := make(map[string]bool)
+
+		for _, line := range strings.Split(string(contents), "\n") {
+			scriptDir := strings.TrimPrefix(line, appliedScriptsMarker)
+			if scriptDir == line || seen[scriptDir] {
+				continue
+			}
+
+			seen[scriptDir] = true
+			scripts.Scripts = append(scripts.Scripts, filepath.Base(scriptDir))
+		}
+
+		if len(scripts.Scripts) > 0 {
+			applied = append(applied, scripts)
+		}
+	}
+
+	return applied, nil
+}
+
+func querySegmentConfiguration(cluster *greenplum.Cluster) (segments []SegmentStatus, err error) {
+	db, err := bootstrapConnectionFunc(cluster.Destination, cluster.GPHome, cluster.CoordinatorPort())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cErr := db.Close(); cErr != nil {
+			err = errorlist.Append(err, cErr)
+		}
+	}()
+
+	return QuerySegmentConfiguration(db, cluster.Version)
+}
+
+// QuerySegmentConfiguration returns gp_segment_configuration including the
+// status and mode of each segment.
+func QuerySegmentConfiguration(db *sql.DB, version semver.Version) ([]SegmentStatus, error) {
+	query := `
+SELECT
+	dbid,
+	content,
+	role,
+	preferred_role,
+	mode,
+	status,
+	port,
+	hostname,
+	address,
+	datadir
+FROM gp_segment_configuration
+ORDER BY content, role;`
+
+	if version.Major == 5 {
+		query = `
+SELECT
+	s.dbid,
+	s.content,
+	s.role,
+	s.preferred_role,
+	s.mode,
+	s.status,
+	s.port,
+	s.hostname,
+	s.address,
+	e.fselocation
+FROM gp_segment_configuration s
+JOIN pg_filespace_entry e ON s.dbid = e.fsedbid
+JOIN pg_filespace f ON e.fsefsoid = f.oid
+WHERE f.fsname = 'pg_system'
+ORDER BY s.content, s.role;`
+	}
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, xerrors.Errorf("querying gp_segment_configuration: %w", err)
+	}
+	defer rows.Close()
+
+	var segments []SegmentStatus
+	for rows.Next() {
+		var seg SegmentStatus
+		err := rows.Scan(&seg.DbID, &seg.ContentID, &seg.Role, &seg.PreferredRole, &seg.Mode, &seg.Status,
+			&seg.Port, &seg.Hostname, &seg.Address, &seg.DataDir)
+		if err != nil {
+			return nil, xerrors.Errorf("scanning gp_segment_configuration: %w", err)
+		}
+
+		segments = append(segments, seg)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, xerrors.Errorf("iterating gp_segment_configuration rows: %w", err)
+	}
+
+	return segments, nil
+}
+
+// reportSection is rendered as a table when it has a header, otherwise as a
+// list of items. Empty is shown when there is neither.
+type reportSection struct {
+	Title  string
+	Header []string
+	Rows   [][]string
+	Items  []string
+	Empty  string
+}
+
+func (r UpgradeReport) title() string {
+	return fmt.Sprintf("gpupgrade %s report", r.Step)
+}
+
+func (r UpgradeReport) sections() []reportSection {
+	summary := reportSection{
+		Title:  "Summary",
+		Header: []string{"Field", "Value"},
+		Rows: [][]string{
+			{"Step", r.Step.String()},
+			{"Completed", r.Time.Format(time.RFC1123)},
+			{"Upgrade ID", r.UpgradeID},
+			{"Mode", r.Mode.String()},
+			{"Source version", r.SourceVersion},
+			{"Source GPHOME", r.SourceGPHome},
+			{"Target version", r.TargetVersion},
+			{"Target GPHOME", r.TargetGPHome},
+		},
+	}
+
+	sections := []reportSection{
+		summary,
+		topologySection("Cluster topology before", r.Before),
+		topologySection("Cluster topology after", r.After),
+	}
+
+	for _, stepStatus := range r.Steps {
+		section := reportSection{
+			Title:  cases.Title(language.English).String(stepStatus.GetStep().String()) + " substep durations",
+			Header: []string{"Substep", "Status", "Started", "Duration"},
+		}
+
+		for _, substep := range stepStatus.GetSubsteps() {
+			if substep.GetSubstep() == idl.Substep_write_upgrade_report {
+				continue
+			}
+
+			var started, duration string
+			if substep.GetStartTime() != nil {
+				started = substep.GetStartTime().AsTime().Local().Format(time.RFC3339)
+			}
+			if substep.GetDuration() != nil {
+				duration = substep.GetDuration().AsDuration().Round(time.Millisecond).String()
+			}
+
+			section.Rows = append(section.Rows, []string{substepDescription(substep.GetSubstep()), substep.GetStatus().String(), started, duration})
+		}
+
+		sections = append(sections, section)
+	}
+
+	scripts := reportSection{
+		Title:  "Data migration scripts applied",
+		Header: []string{"Phase", "Scripts"},
+		Empty:  "No data migration scripts were applied.",
+	}
+	for _, applied := range r.Scripts {
+		scripts.Rows = append(scripts.Rows, []string{applied.Phase.String(), strings.Join(applied.Scripts, ", ")})
+	}
+	sections = append(sections, scripts)
+
+	sections = append(sections, reportSection{
+		Title: "Warnings",
+		Items: r.Warnings,
+		Empty: "None.",
+	})
+
+	segments := reportSection{
+		Title:  "gp_segment_configuration",
+		Header: []string{"dbid", "content", "role", "preferred_role", "mode", "status", "port", "hostname", "address", "datadir"},
+		Empty:  "Unavailable.",
+	}
+	for _, seg := range r.SegmentConfiguration {
+		segments.Rows = append(segments.Rows, []string{
+			strconv.Itoa(seg.DbID), strconv.Itoa(seg.ContentID), seg.Role, seg.PreferredRole, seg.Mode, seg.Status,
+			strconv.Itoa(seg.Port), seg.Hostname, seg.Address, seg.DataDir,
+		})
+	}
+	sections = append(sections, segments)
+
+	return sections
+}
+
+func topologySection(title string, segments greenplum.SegConfigs) reportSection {
+	section := reportSection{
+		Title:  title,
+		Header: []string{"Role", "Content", "DbID", "Hostname", "Port", "Data directory"},
+		Empty:  "Unavailable.",
+	}
+
+	for _, seg := range segments {
+		seg := seg
+		section.Rows = append(section.Rows, []string{
+			segmentRole(&seg), strconv.Itoa(seg.ContentID), strconv.Itoa(seg.DbID), seg.Hostname, strconv.Itoa(seg.Port), seg.DataDir,
+		})
+	}
+
+	return section
+}
+
+func segmentRole(seg *greenplum.SegConfig) string {
+	switch {
+	case seg.IsCoordinator():
+		return "master"
+	case seg.IsStandby():
+		return "standby"
+	case seg.IsPrimary():
+		return "primary"
+	default:
+		return "mirror"
+	}
+}
+
+// Markdown renders the report as Markdown.
+func (r UpgradeReport) Markdown() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n", r.title())
+	for _, section := range r.sections() {
+		fmt.Fprintf(&b, "\n## %s\n\n", section.Title)
+
+		switch {
+		case len(section.Header) > 0 && (len(section.Rows) > 0 || section.Empty == ""):
+			b.WriteString(markdownRow(section.Header))
+			b.WriteString("|" + strings.Repeat(" --- |", len(section.Header)) + "\n")
+			for _, row := range section.Rows {
+				b.WriteString(markdownRow(row))
+			}
+		case len(section.Items) > 0:
+			for _, item := range section.Items {
+				fmt.Fprintf(&b, "- %s\n", markdownEscaper.Replace(item))
+			}
+		default:
+			b.WriteString(section.Empty + "\n")
+		}
+	}
+
+	return b.String()
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\n", "<br>")
+
+func markdownRow(cells []string) string {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = markdownEscaper.Replace(cell)
+	}
+
+	return "| " + strings.Join(escaped, " | ") + " |\n"
+}
+
+var upgradeReportHTML = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #eee; }
+td { white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{- range .Sections}}
+<h2>{{.Title}}</h2>
+{{- if and .Header (or .Rows (not .Empty))}}
+<table>
+<tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr>
+{{- range .Rows}}
+<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</table>
+{{- else if .Items}}
+<ul>
+{{- range .Items}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- else}}
+<p>{{.Empty}}</p>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// HTML renders the report as an HTML page.
+func (r UpgradeReport) HTML() (string, error) {
+	var b strings.Builder
+
+	err := upgradeReportHTML.Execute(&b, struct {
+		Title    string
+		Sections []reportSection
+	}{r.title(), r.sections()})
+	if err != nil {
+		return "", xerrors.Errorf("render upgrade report: %w", err)
+	}
+
+	return b.String(), nil
+}
+
+// WriteUpgradeReport writes the report as Markdown and HTML to the directory.
+func WriteUpgradeReport(dir string, report UpgradeReport) error {
+	page, err := report.HTML()
+	if err != nil {
+		return err
+	}
+
+	err = utils.AtomicallyWrite(filepath.Join(dir, UpgradeReportMarkdownFile), []byte(report.Markdown()))
+	if err != nil {
+		return xerrors.Errorf("write upgrade report: %w", err)
+	}
+
+	err = utils.AtomicallyWrite(filepath.Join(dir, UpgradeReportHTMLFile), []byte(page))
+	if err != nil {
+		return xerrors.Errorf("write upgrade report: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,374 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commanders_test
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/blang/semver/v4"
+
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+var segmentConfigurationColumns = []string{"dbid", "content", "role", "preferred_role", "mode", "status", "port", "hostname", "address", "datadir"}
+
+func TestReadAppliedDataMigrationScripts(t *testing.T) {
+	t.Run("reads the script directories applied during each phase", func(t *testing.T) {
+		logDir := t.TempDir()
+		testutils.MustWriteToFile(t, filepath.Join(logDir, "apply_initialize.log"), `
+-- gpupgrade applied data migration scripts in /home/gpadmin/scripts/current/initialize/unique_primary_foreign_key_constraint
+DROP INDEX idx;
+-- gpupgrade applied data migration scripts in /home/gpadmin/scripts/current/initialize/parent_partitions_with_seg_entries
+TRUNCATE parent;
+-- gpupgrade applied data migration scripts in /home/gpadmin/scripts/current/initialize/unique_primary_foreign_key_constraint
+DROP INDEX idx;
+`)
+		testutils.MustWriteToFile(t, filepath.Join(logDir, "apply_stats.log"), statsLog)
+		testutils.MustWriteToFile(t, filepath.Join(logDir, "apply_finalize.log"), `
+-- gpupgrade applied data migration scripts in /home/gpadmin/scripts/current/finalize/recreate_indexes
+CREATE INDEX idx ON t(a);
+`)
+
+		applied, err := commanders.ReadAppliedDataMigrationScripts(logDir)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := []commanders.AppliedScripts{
+			{Phase: idl.Step_initialize, Scripts: []string{"unique_primary_foreign_key_constraint", "parent_partitions_with_seg_entries"}},
+			{Phase: idl.Step_finalize, Scripts: []string{"recreate_indexes"}},
+		}
+		if !reflect.DeepEqual(applied, expected) {
+			t.Errorf("got %+v want %+v", applied, expected)
+		}
+	})
+
+	t.Run("returns nothing when no scripts were applied", func(t *testing.T) {
+		applied, err := commanders.ReadAppliedDataMigrationScripts(t.TempDir())
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if applied != nil {
+			t.Errorf("got %+v want nil", applied)
+		}
+	})
+
+	t.Run("errors when reading an apply log fails", func(t *testing.T) {
+		logDir := t.TempDir()
+		err := os.Mkdir(filepath.Join(logDir, "apply_revert.log"), 0700)
+		if err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+
+		_, err = commanders.ReadAppliedDataMigrationScripts(logDir)
+		if err == nil {
+			t.Errorf("expected an error")
+		}
+	})
+}
+
+func TestQuerySegmentConfiguration(t *testing.T) {
+	t.Run("queries the status of each segment", func(t *testing.T) {
+		db, mock := sqlmockNew(t)
+		defer db.Close()
+
+		mock.ExpectQuery("FROM gp_segment_configuration").
+			WillReturnRows(sqlmock.NewRows(segmentConfigurationColumns).
+				AddRow(1, -1, "p", "p", "n", "u", 5432, "mdw", "mdw", "/data/qddir/seg-1").
+				AddRow(2, 0, "m", "p", "s", "d", 6000, "sdw1", "sdw1", "/data/dbfast1/seg0"))
+
+		segments, err := commanders.QuerySegmentConfiguration(db, semver.MustParse("6.20.0"))
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := []commanders.SegmentStatus{
+			{DbID: 1, ContentID: -1, Role: "p", PreferredRole: "p", Mode: "n", Status: "u", Port: 5432, Hostname: "mdw", Address: "mdw", DataDir: "/data/qddir/seg-1"},
+			{DbID: 2, ContentID: 0, Role: "m", PreferredRole: "p", Mode: "s", Status: "d", Port: 6000, Hostname: "sdw1", Address: "sdw1", DataDir: "/data/dbfast1/seg0"},
+		}
+		if !reflect.DeepEqual(segments, expected) {
+			t.Errorf("got %+v want %+v", segments, expected)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("%v", err)
+		}
+	})
+
+	t.Run("queries the data directories from the filespaces on 5X", func(t *testing.T) {
+		db, mock := sqlmockNew(t)
+		defer db.Close()
+
+		mock.ExpectQuery("JOIN pg_filespace_entry").
+			WillReturnRows(sqlmock.NewRows(segmentConfigurationColumns).
+				AddRow(1, -1, "p", "p", "s", "u", 5432, "mdw", "mdw", "/data/qddir/seg-1"))
+
+		_, err := commanders.QuerySegmentConfiguration(db, semver.MustParse("5.29.10"))
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("%v", err)
+		}
+	})
+
+	t.Run("errors when querying fails", func(t *testing.T) {
+		db, mock := sqlmockNew(t)
+		defer db.Close()
+
+		expected := errors.New("permission denied")
+		mock.ExpectQuery("FROM gp_segment_configuration").WillReturnError(expected)
+
+		_, err := commanders.QuerySegmentConfiguration(db, semver.MustParse("6.20.0"))
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+}
+
+func TestNewUpgradeReport(t *testing.T) {
+	source := greenplum.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Port: 15432, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 2, Port: 25432, Hostname: "sdw1", DataDir: "/data/dbfast1/seg0", Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 3, Port: 25433, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg0", Role: greenplum.MirrorRole},
+	})
+	source.Destination = idl.ClusterDestination_source
+	source.Version = semver.MustParse("6.20.0")
+	source.GPHome = "/usr/local/gpdb6"
+
+	target := greenplum.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Port: 15432, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 2, Port: 25432, Hostname: "sdw1", DataDir: "/data/dbfast1/seg0", Role: greenplum.PrimaryRole},
+	})
+	target.Destination = idl.ClusterDestination_target
+	target.Version = semver.MustParse("7.0.0")
+	target.GPHome = "/usr/local/gpdb7"
+
+	conf := &config.Config{Source: source, Target: target, Mode: idl.Mode_link, UpgradeID: "ABC123"}
+
+	stateDir := t.TempDir()
+	testutils.MustWriteToFile(t, filepath.Join(stateDir, step.SubstepsFileName), "{}")
+	store := step.NewSubstepStoreUsingFile(filepath.Join(stateDir, step.SubstepsFileName))
+	mustWriteRun(t, store, idl.Step_execute, idl.Substep_upgrade_primaries, errors.New("pg_upgrade failed"))
+	mustWriteRun(t, store, idl.Step_execute, idl.Substep_upgrade_primaries, nil)
+	mustWriteRun(t, store, idl.Step_finalize, idl.Substep_upgrade_mirrors, nil)
+
+	logDir := t.TempDir()
+	testutils.MustWriteToFile(t, filepath.Join(logDir, "apply_initialize.log"),
+		"-- gpupgrade applied data migration scripts in /scripts/current/initialize/unique_primary_foreign_key_constraint\n")
+
+	t.Run("reports the upgrade after finalize", func(t *testing.T) {
+		db, mock := sqlmockNew(t)
+		mock.ExpectQuery("FROM gp_segment_configuration").
+			WillReturnRows(sqlmock.NewRows(segmentConfigurationColumns).
+				AddRow(1, -1, "p", "p", "n", "u", 15432, "mdw", "mdw", "/data/qddir/seg-1").
+				AddRow(2, 0, "p", "p", "s", "u", 25432, "sdw1", "sdw1", "/data/dbfast1/seg0").
+				AddRow(3, 0, "m", "m", "s", "d", 25433, "sdw2", "sdw2", "/data/dbfast_mirror1/seg0"))
+		mock.ExpectClose()
+
+		var destination idl.ClusterDestination
+		var port int
+		commanders.SetBootstrapConnectionFunction(func(d idl.ClusterDestination, gphome string, p int) (*sql.DB, error) {
+			destination, port = d, p
+			return db, nil
+		})
+		defer commanders.ResetBootstrapConnectionFunction()
+
+		report := commanders.NewUpgradeReport(idl.Step_finalize, conf, stateDir, logDir)
+
+		if destination != idl.ClusterDestination_target || port != 15432 {
+			t.Errorf("got connection to %s cluster on port %d want the target cluster on port %d", destination, port, 15432)
+		}
+
+		if report.UpgradeID != "ABC123" || report.Mode != idl.Mode_link {
+			t.Errorf("got upgrade ID %q and mode %s", report.UpgradeID, report.Mode)
+		}
+
+		if report.SourceVersion != "6.20.0" || report.TargetVersion != "7.0.0" {
+			t.Errorf("got source version %q and target version %q", report.SourceVersion, report.TargetVersion)
+		}
+
+		if len(report.Before) != 3 || len(report.After) != 2 {
+			t.Errorf("got %d segments before and %d after want 3 and 2", len(report.Before), len(report.After))
+		}
+
+		if len(report.Steps) != 2 || report.Steps[0].GetStep() != idl.Step_execute || report.Steps[1].GetStep() != idl.Step_finalize {
+			t.Errorf("got steps %+v want execute and finalize", report.Steps)
+		}
+
+		expectedScripts := []commanders.AppliedScripts{{Phase: idl.Step_initialize, Scripts: []string{"unique_primary_foreign_key_constraint"}}}
+		if !reflect.DeepEqual(report.Scripts, expectedScripts) {
+			t.Errorf("got scripts %+v want %+v", report.Scripts, expectedScripts)
+		}
+
+		if len(report.SegmentConfiguration) != 3 {
+			t.Errorf("got %d segments want 3", len(report.SegmentConfiguration))
+		}
+
+		if len(report.Warnings) != 2 {
+			t.Fatalf("got warnings %q want 2", report.Warnings)
+		}
+
+		if !strings.HasPrefix(report.Warnings[0], `execute substep "Upgrading primary segments..." failed at `) ||
+			!strings.HasSuffix(report.Warnings[0], ": pg_upgrade failed") {
+			t.Errorf("got warning %q want the failed run of upgrade primaries", report.Warnings[0])
+		}
+
+		expected := "Segment with dbid 3 and content 0 on sdw2 is down."
+		if report.Warnings[1] != expected {
+			t.Errorf("got warning %q want %q", report.Warnings[1], expected)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("%v", err)
+		}
+	})
+
+	t.Run("reports the source cluster after revert and warns when it cannot be queried", func(t *testing.T) {
+		var destination idl.ClusterDestination
+		commanders.SetBootstrapConnectionFunction(func(d idl.ClusterDestination, gphome string, port int) (*sql.DB, error) {
+			destination = d
+			return nil, errors.New("connection refused")
+		})
+		defer commanders.ResetBootstrapConnectionFunction()
+
+		report := commanders.NewUpgradeReport(idl.Step_revert, conf, stateDir, logDir)
+
+		if destination != idl.ClusterDestination_source {
+			t.Errorf("got connection to %s cluster want %s", destination, idl.ClusterDestination_source)
+		}
+
+		if !reflect.DeepEqual(report.After, report.Before) {
+			t.Errorf("got topology after %+v want %+v", report.After, report.Before)
+		}
+
+		expected := "Unable to query gp_segment_configuration of the source cluster: connection refused"
+		if report.Warnings[len(report.Warnings)-1] != expected {
+			t.Errorf("got warnings %q want %q", report.Warnings, expected)
+		}
+	})
+}
+
+func TestUpgradeReport(t *testing.T) {
+	report := commanders.UpgradeReport{
+		Step:          idl.Step_finalize,
+		Time:          time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+		UpgradeID:     "ABC123",
+		Mode:          idl.Mode_copy,
+		SourceVersion: "6.20.0",
+		TargetVersion: "7.0.0",
+		Before: greenplum.SegConfigs{
+			{ContentID: -1, DbID: 1, Port: 15432, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+			{ContentID: -1, DbID: 2, Port: 16432, Hostname: "smdw", DataDir: "/data/standby", Role: greenplum.MirrorRole},
+		},
+		Warnings: []string{"a <b>warning</b> | with a pipe"},
+	}
+
+	t.Run("renders Markdown", func(t *testing.T) {
+		actual := report.Markdown()
+
+		for _, expected := range []string{
+			"# gpupgrade finalize report\n",
+			"| Target version | 7.0.0 |\n",
+			"## Cluster topology before\n\n| Role | Content | DbID | Hostname | Port | Data directory |\n",
+			"| master | -1 | 1 | mdw | 15432 | /data/qddir/seg-1 |\n",
+			"| standby | -1 | 2 | smdw | 16432 | /data/standby |\n",
+			"## Cluster topology after\n\nUnavailable.\n",
+			"## Data migration scripts applied\n\nNo data migration scripts were applied.\n",
+			"- a <b>warning</b> \\| with a pipe\n",
+			"## gp_segment_configuration\n\nUnavailable.\n",
+		} {
+			if !strings.Contains(actual, expected) {
+				t.Errorf("expected %q to contain %q", actual, expected)
+			}
+		}
+	})
+
+	t.Run("renders HTML", func(t *testing.T) {
+		actual, err := report.HTML()
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		for _, expected := range []string{
+			"<title>gpupgrade finalize report</title>",
+			"<tr><td>Mode</td><td>copy</td></tr>",
+			"<tr><td>master</td><td>-1</td><td>1</td><td>mdw</td><td>15432</td><td>/data/qddir/seg-1</td></tr>",
+			"<li>a &lt;b&gt;warning&lt;/b&gt; | with a pipe</li>",
+			"<p>No data migration scripts were applied.</p>",
+		} {
+			if !strings.Contains(actual, expected) {
+				t.Errorf("expected %q to contain %q", actual, expected)
+			}
+		}
+	})
+}
+
+func TestWriteUpgradeReport(t *testing.T) {
+	t.Run("writes the Markdown and HTML reports to the directory", func(t *testing.T) {
+		dir := t.TempDir()
+		report := commanders.UpgradeReport{Step: idl.Step_revert}
+
+		err := commanders.WriteUpgradeReport(dir, report)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		markdown := testutils.MustReadFile(t, filepath.Join(dir, commanders.UpgradeReportMarkdownFile))
+		if markdown != report.Markdown() {
+			t.Errorf("got %q want %q", markdown, report.Markdown())
+		}
+
+		page := testutils.MustReadFile(t, filepath.Join(dir, commanders.UpgradeReportHTMLFile))
+		if !strings.Contains(page, "<h1>gpupgrade revert report</h1>") {
+			t.Errorf("expected %q to contain the title", page)
+		}
+	})
+
+	t.Run("errors when the directory does not exist", func(t *testing.T) {
+		err := commanders.WriteUpgradeReport(filepath.Join(t.TempDir(), "missing"), commanders.UpgradeReport{})
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("got error %#v want %#v", err, os.ErrNotExist)
+		}
+	})
+}
+
+func mustWriteRun(t *testing.T, store *step.SubstepFileStore, stepName idl.Step, substep idl.Substep, runErr error) {
+	t.Helper()
+
+	err := store.Write(stepName, substep, idl.Status_running)
+	if err != nil {
+		t.Fatalf("Write() returned error %#v", err)
+	}
+
+	status := idl.Status_complete
+	if runErr != nil {
+		status = idl.Status_failed
+	}
+
+	err = store.Write(stepName, substep, status)
+	if err != nil {
+		t.Fatalf("Write() returned error %#v", err)
+	}
+
+	err = store.WriteRun(stepName, substep, time.Second, runErr)
+	if err != nil {
+		t.Fatalf("WriteRun() returned error %#v", err)
+	}
+}
@@ -4,19 +4,32 @@
 package commands
 
 import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+
+	"github.com/kballard/go-shellquote"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/xerrors"
 
 	"github.com/greenplum-db/gpupgrade/agent"
+	"github.com/greenplum-db/gpupgrade/agent/launcher"
 	"github.com/greenplum-db/gpupgrade/upgrade"
 	"github.com/greenplum-db/gpupgrade/utils"
 	"github.com/greenplum-db/gpupgrade/utils/daemon"
 	"github.com/greenplum-db/gpupgrade/utils/logger"
+	"github.com/greenplum-db/gpupgrade/utils/mtls"
 )
 
 func Agent() *cobra.Command {
 	var agentPort int
+	var listenAddress string
 	var stateDir string
 	var shouldDaemonize bool
+	var tlsConfig mtls.AgentConfig
 
 	var cmd = &cobra.Command{
 		Use:    "agent",
@@ -25,20 +38,124 @@ func Agent() *cobra.Command {
 		Hidden: true,
 		Args:   cobra.MaximumNArgs(0), // no positional args allowed
 		RunE: func(cmd *cobra.Command, args []string) error {
+			err := parseAgentArgsEnv(cmd.Flags())
+			if err != nil {
+				return err
+			}
+
 			logger.Initialize("agent")
 			defer logger.WritePanics()
 
+			agent.Version = VersionString("oneline")
 			agentServer := agent.New()
 
 			// blocking call
-			return agentServer.Start(agentPort, stateDir, shouldDaemonize)
+			return agentServer.Start(listenAddress, agentPort, stateDir, tlsConfig, shouldDaemonize)
 		},
 	}
 
 	cmd.Flags().IntVar(&agentPort, "port", upgrade.DefaultAgentPort, "the port to listen for commands on")
+	cmd.Flags().StringVar(&listenAddress, "listen-address", "", "the address to listen for commands on. Defaults to all interfaces.")
 	cmd.Flags().StringVar(&stateDir, "state-directory", utils.GetStateDir(), "Agent state directory")
+	cmd.Flags().StringVar(&tlsConfig.CACertificate, "tls-ca-certificate", "", "the CA certificate used to verify the hub")
+	cmd.Flags().StringVar(&tlsConfig.Certificate, "tls-certificate", "", "the agent certificate")
+	cmd.Flags().StringVar(&tlsConfig.Key, "tls-key", "", "the agent private key")
+	cmd.Flags().StringVar(&tlsConfig.HubFingerprint, "tls-hub-fingerprint", "", "the SHA-256 fingerprint of the only client certificate to accept")
 
 	daemon.MakeDaemonizable(cmd, &shouldDaemonize)
 
+	cmd.AddCommand(installUnit())
+
+	return cmd
+}
+
+// parseAgentArgsEnv parses the agent arguments set in the environment file of
+// the agent unit, which take precedence over the command line.
+func parseAgentArgsEnv(flags *pflag.FlagSet) error {
+	env := os.Getenv(agent.AgentArgsEnv)
+	if env == "" {
+		return nil
+	}
+
+	args, err := shellquote.Split(env)
+	if err != nil {
+		return xerrors.Errorf("parse %s: %w", agent.AgentArgsEnv, err)
+	}
+
+	err = flags.Parse(args)
+	if err != nil {
+		return xerrors.Errorf("parse %s: %w", agent.AgentArgsEnv, err)
+	}
+
+	return nil
+}
+
+func installUnit() *cobra.Command {
+	var agentPort int
+	var listenAddress string
+	var stateDir string
+	var unitUser string
+	var directory string
+	var environmentFile string
+	var tlsConfig mtls.AgentConfig
+
+	var cmd = &cobra.Command{
+		Use:   "install-unit",
+		Short: "install systemd units for the agent",
+		Long: `install a systemd unit for the agent and a socket unit which starts it
+when the hub connects, such that the agent is supervised without the hub
+running commands on the host. The agent arguments are written to the
+environment file of the unit. Use with agent_launcher systemd.`,
+		Args: cobra.MaximumNArgs(0), // no positional args allowed
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if unitUser == "" {
+				current, err := user.Current()
+				if err != nil {
+					return err
+				}
+
+				unitUser = current.Username
+			}
+
+			agentArgs := []string{"--port", strconv.Itoa(agentPort), "--state-directory", stateDir}
+			if listenAddress != "" {
+				agentArgs = append(agentArgs, "--listen-address", listenAddress)
+			}
+
+			if tlsConfig.Enabled() {
+				agentArgs = append(agentArgs,
+					"--tls-ca-certificate", tlsConfig.CACertificate,
+					"--tls-certificate", tlsConfig.Certificate,
+					"--tls-key", tlsConfig.Key,
+					"--tls-hub-fingerprint", tlsConfig.HubFingerprint)
+			}
+
+			paths, err := agent.InstallUnit(directory, environmentFile, unitUser, listenAddress, agentPort, agentArgs)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf(`Installed %s
+
+To start the agent when the hub connects run:
+  systemctl daemon-reload
+  systemctl enable --now %s
+`, strings.Join(paths, ", "), launcher.DefaultSocket)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&agentPort, "port", upgrade.DefaultAgentPort, "the port to listen for commands on")
+	cmd.Flags().StringVar(&listenAddress, "listen-address", "", "the address to listen for commands on. Defaults to all interfaces.")
+	cmd.Flags().StringVar(&stateDir, "state-directory", utils.GetStateDir(), "Agent state directory")
+	cmd.Flags().StringVar(&tlsConfig.CACertificate, "tls-ca-certificate", "", "the CA certificate used to verify the hub")
+	cmd.Flags().StringVar(&tlsConfig.Certificate, "tls-certificate", "", "the agent certificate")
+	cmd.Flags().StringVar(&tlsConfig.Key, "tls-key", "", "the agent private key")
+	cmd.Flags().StringVar(&tlsConfig.HubFingerprint, "tls-hub-fingerprint", "", "the SHA-256 fingerprint of the only client certificate to accept")
+	cmd.Flags().StringVar(&unitUser, "user", "", "the user to run the agent as. Defaults to the current user.")
+	cmd.Flags().StringVar(&directory, "directory", "/etc/systemd/system", "the directory to write the units to")
+	cmd.Flags().StringVar(&environmentFile, "environment-file", "/etc/gpupgrade/agent.env", "the file to write the agent arguments to")
+
 	return cmd
 }
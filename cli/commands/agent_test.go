@@ -0,0 +1,68 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/agent"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestParseAgentArgsEnv(t *testing.T) {
+	t.Run("parses the agent arguments from the environment", func(t *testing.T) {
+		resetEnv := testutils.SetEnv(t, agent.AgentArgsEnv, `'--port' '1234' '--tls-key' '/etc/my certs/agent.key'`)
+		defer resetEnv()
+
+		cmd := Agent()
+		err := parseAgentArgsEnv(cmd.Flags())
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		port, err := cmd.Flags().GetInt("port")
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if port != 1234 {
+			t.Errorf("got port %d want %d", port, 1234)
+		}
+
+		key, err := cmd.Flags().GetString("tls-key")
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := "/etc/my certs/agent.key"
+		if key != expected {
+			t.Errorf("got key %q want %q", key, expected)
+		}
+	})
+
+	t.Run("does nothing when the environment variable is not set", func(t *testing.T) {
+		resetEnv := testutils.SetEnv(t, agent.AgentArgsEnv, "")
+		defer resetEnv()
+
+		cmd := Agent()
+		err := parseAgentArgsEnv(cmd.Flags())
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if cmd.Flags().Changed("port") {
+			t.Errorf("expected port to be unchanged")
+		}
+	})
+
+	t.Run("errors when the arguments cannot be split", func(t *testing.T) {
+		resetEnv := testutils.SetEnv(t, agent.AgentArgsEnv, `--tls-key '/etc/agent.key`)
+		defer resetEnv()
+
+		err := parseAgentArgsEnv(Agent().Flags())
+		if err == nil {
+			t.Errorf("expected error got nil")
+		}
+	})
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+)
+
+func agents() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "agents",
+		Short: "shows the gpupgrade agent on each host",
+		Long:  AgentsHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectToHub()
+			if err != nil {
+				return err
+			}
+
+			agents, err := commanders.GetAgents(client)
+			if err != nil {
+				return err
+			}
+
+			output, err := commanders.FormatAgents(agents, format)
+			if err != nil {
+				return err
+			}
+
+			fmt.Print(output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "text", `specify the output format as either "text" or "json"`)
+
+	return addHelpToCommand(cmd, AgentsHelp)
+}
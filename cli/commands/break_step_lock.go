@@ -0,0 +1,41 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+func breakStepLock() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "break-step-lock",
+		Short: "cancels the running step and unlocks it such that another step can run",
+		Long:  BreakStepLockHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := connectToHub()
+			if err != nil {
+				return err
+			}
+
+			reply, err := client.BreakStepLock(context.Background(), &idl.BreakStepLockRequest{})
+			if err != nil {
+				return err
+			}
+
+			lock := reply.GetLock()
+			fmt.Printf("Broke the lock on step %s held by %s since %s.\n",
+				lock.GetStep(), hub.FormatStepOwner(lock), lock.GetStartTime().AsTime().Local().Format(time.RFC1123))
+			return nil
+		},
+	}
+
+	return addHelpToCommand(cmd, BreakStepLockHelp)
+}
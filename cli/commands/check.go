@@ -0,0 +1,181 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/agent/launcher"
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+func check() *cobra.Command {
+	var file string
+	var sourceGPHome, targetGPHome string
+	var sourcePort int
+	var mode string
+	var diskFreeRatio float64
+	var ports string
+	var agentLauncher string
+
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "runs read-only pre-upgrade checks",
+		Long:  CheckHelp,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			isAnyDevModeFlagSet := cmd.Flag("source-gphome").Changed ||
+				cmd.Flag("target-gphome").Changed ||
+				cmd.Flag("source-master-port").Changed
+
+			// If no required flags are set then return help.
+			if !cmd.Flag("file").Changed && !isAnyDevModeFlagSet {
+				fmt.Println(CheckHelp)
+				cmd.SilenceErrors = true // silence Quit error message below
+				return step.Quit         // exit early and don't call RunE
+			}
+
+			if cmd.Flag("file").Changed {
+				var err error
+				cmd.Flags().Visit(func(flag *pflag.Flag) {
+					if flag.Name != "file" {
+						err = errors.New("The file flag cannot be used with any other flag.")
+					}
+				})
+				return err
+			}
+
+			for _, f := range []string{"source-gphome", "target-gphome", "source-master-port"} {
+				cmd.MarkFlagRequired(f) //nolint
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if cmd.Flag("file").Changed {
+				configFile, err := os.Open(file)
+				if err != nil {
+					return err
+				}
+				defer func() {
+					if cErr := configFile.Close(); cErr != nil {
+						err = errorlist.Append(err, cErr)
+					}
+				}()
+
+				flags, err := ParseConfig(configFile)
+				if err != nil {
+					return xerrors.Errorf("in file %q: %w", file, err)
+				}
+
+				err = addCheckFlags(cmd, flags)
+				if err != nil {
+					return err
+				}
+			}
+
+			mode, err := parseMode(mode)
+			if err != nil {
+				return err
+			}
+
+			// if diskFreeRatio is not explicitly set, use the defaults of initialize
+			if !cmd.Flag("disk-free-ratio").Changed {
+				diskFreeRatio = 0.2
+				if mode == idl.Mode_copy {
+					diskFreeRatio = 0.6
+				}
+			}
+
+			if diskFreeRatio < 0.0 || diskFreeRatio > 1.0 {
+				// Match Cobra's option-error format.
+				return fmt.Errorf(
+					`invalid argument %g for "--disk-free-ratio" flag: value must be between 0.0 and 1.0`,
+					diskFreeRatio,
+				)
+			}
+
+			parsedPorts, err := ParsePorts(ports)
+			if err != nil {
+				return err
+			}
+
+			l, err := launcher.New(agentLauncher)
+			if err != nil {
+				return err
+			}
+
+			logdir, err := utils.GetLogDir()
+			if err != nil {
+				return err
+			}
+
+			// If we got here, the args are okay and the user doesn't need a usage
+			// dump on failure.
+			cmd.SilenceUsage = true
+
+			report := commanders.RunChecks(commanders.CheckOptions{
+				SourceGPHome:  sourceGPHome,
+				TargetGPHome:  targetGPHome,
+				SourcePort:    sourcePort,
+				Mode:          mode,
+				DiskFreeRatio: diskFreeRatio,
+				Ports:         parsedPorts,
+				Launcher:      l,
+			})
+
+			fmt.Print(report)
+
+			path, err := commanders.WriteCheckReport(logdir, report)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("\nThe report has been written to %s\n", path)
+
+			if report.Failed() {
+				return errors.New("One or more checks failed. Resolve the failures and re-run gpupgrade check.")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "the configuration file to use")
+	cmd.Flags().IntVar(&sourcePort, "source-master-port", 0, "master port for source gpdb cluster")
+	cmd.Flags().StringVar(&sourceGPHome, "source-gphome", "", "path for the source Greenplum installation")
+	cmd.Flags().StringVar(&targetGPHome, "target-gphome", "", "path for the target Greenplum installation")
+	cmd.Flags().StringVar(&mode, "mode", "copy", "performs upgrade in either copy or link mode. Default is copy.")
+	cmd.Flags().Float64Var(&diskFreeRatio, "disk-free-ratio", 0.60, "percentage of disk space that must be available (from 0.0 - 1.0)")
+	cmd.Flags().StringVar(&ports, "temp-port-range", "50432-65535", "set of ports to use when initializing the target cluster")
+	cmd.Flags().StringVar(&agentLauncher, "agent-launcher", launcher.SSHLauncher, "how commands are run on the hosts. Either ssh, systemd, or running. Defaults to ssh.")
+
+	return addHelpToCommand(cmd, CheckHelp)
+}
+
+// addCheckFlags sets the flags of check from the configuration file of
+// initialize. Parameters that only apply to initialize are ignored.
+func addCheckFlags(cmd *cobra.Command, flags map[string]string) error {
+	initializeCmd := initialize()
+
+	checkFlags := make(map[string]string)
+	for name, value := range flags {
+		if cmd.Flag(name) == nil && initializeCmd.Flag(name) != nil {
+			continue
+		}
+
+		checkFlags[name] = value
+	}
+
+	return addFlags(cmd, checkFlags)
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestCheckFlags(t *testing.T) {
+	t.Run("the flags of check are those of initialize such that they share the configuration file", func(t *testing.T) {
+		initializeCmd := initialize()
+
+		check().Flags().VisitAll(func(flag *pflag.Flag) {
+			if initializeCmd.Flag(flag.Name) == nil {
+				t.Errorf("check flag %q is not an initialize flag", flag.Name)
+			}
+		})
+	})
+}
+
+func TestAddCheckFlags(t *testing.T) {
+	t.Run("sets the check flags and ignores parameters only used by initialize", func(t *testing.T) {
+		cmd := check()
+
+		flags := map[string]string{
+			"source-gphome":      "/usr/local/gpdb5",
+			"source-master-port": "15432",
+			"hub-port":           "7527",
+			"pg-upgrade-jobs":    "8",
+		}
+
+		err := addCheckFlags(cmd, flags)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		for _, name := range []string{"source-gphome", "source-master-port"} {
+			flag := cmd.Flag(name)
+			if !flag.Changed || flag.Value.String() != flags[name] {
+				t.Errorf("got flag %q set to %q want %q", name, flag.Value.String(), flags[name])
+			}
+		}
+	})
+
+	t.Run("errors on unknown parameters", func(t *testing.T) {
+		err := addCheckFlags(check(), map[string]string{"unknown": "value"})
+
+		expected := `The configuration parameter "unknown" was not found`
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("got error %v want it to contain %q", err, expected)
+		}
+	})
+}
@@ -35,7 +35,9 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -46,13 +48,16 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	grpcStatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	"github.com/greenplum-db/gpupgrade/cli/commanders"
 	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/hub"
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/step"
 	"github.com/greenplum-db/gpupgrade/upgrade"
 	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/mtls"
 )
 
 func BuildRootCommand() *cobra.Command {
@@ -79,12 +84,17 @@ func BuildRootCommand() *cobra.Command {
 	root.AddCommand(version())
 	root.AddCommand(dataMigrationGenerate())
 	root.AddCommand(dataMigrationApply())
+	root.AddCommand(check())
 	root.AddCommand(initialize())
 	root.AddCommand(execute())
 	root.AddCommand(finalize())
 	root.AddCommand(revert())
+	root.AddCommand(status())
+	root.AddCommand(agents())
+	root.AddCommand(breakStepLock())
+	root.AddCommand(recoverSubstep())
 	root.AddCommand(restartServices)
-	root.AddCommand(killServices)
+	root.AddCommand(killServices())
 	root.AddCommand(Agent())
 	root.AddCommand(Hub())
 
@@ -204,33 +214,44 @@ var restartServices = &cobra.Command{
 	},
 }
 
-var killServices = &cobra.Command{
-	Use:   "kill-services",
-	Short: "Abruptly stops the hub and agents that are currently running.",
-	Long: "Abruptly stops the hub and agents that are currently running.\n" +
-		"Return if no hub is running, which may leave spurious agents running.",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		running, err := commanders.IsHubRunning()
-		if err != nil {
-			return xerrors.Errorf("is hub running: %w", err)
-		}
+func killServices() *cobra.Command {
+	var wait time.Duration
+	var force bool
 
-		if !running {
-			// FIXME: Returning early if the hub is not running, means that we
-			//  cannot kill spurious agents.
-			// We cannot simply start the hub in order to kill spurious agents
-			// since this requires initialize to have been run and the source
-			// cluster config to exist. The main use case for kill-services is
-			// at the start of BATS testing where we do not want to make any
-			// assumption about the state of the cluster or environment.
-			return nil
-		}
+	cmd := &cobra.Command{
+		Use:   "kill-services",
+		Short: "Abruptly stops the hub and agents that are currently running.",
+		Long: "Abruptly stops the hub and agents that are currently running.\n" +
+			"Return if no hub is running, which may leave spurious agents running.\n" +
+			"Refuses to stop the hub while a step is running unless --force is used.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			running, err := commanders.IsHubRunning()
+			if err != nil {
+				return xerrors.Errorf("is hub running: %w", err)
+			}
 
-		return stopHubAndAgents()
-	},
+			if !running {
+				// FIXME: Returning early if the hub is not running, means that we
+				//  cannot kill spurious agents.
+				// We cannot simply start the hub in order to kill spurious agents
+				// since this requires initialize to have been run and the source
+				// cluster config to exist. The main use case for kill-services is
+				// at the start of BATS testing where we do not want to make any
+				// assumption about the state of the cluster or environment.
+				return nil
+			}
+
+			return stopHubAndAgents(wait, force)
+		},
+	}
+
+	cmd.Flags().DurationVar(&wait, "wait", 0, "how long to wait for a running step to finish before stopping, such as 10m")
+	cmd.Flags().BoolVar(&force, "force", false, "stop even if a step is still running, which fails the interrupted substeps")
+
+	return cmd
 }
 
-func stopHubAndAgents() error {
+func stopHubAndAgents(wait time.Duration, force bool) error {
 	port, err := hubPort()
 	if err != nil {
 		return xerrors.Errorf("hub port: %w", err)
@@ -241,7 +262,10 @@ func stopHubAndAgents() error {
 		return err
 	}
 
-	_, err = client.StopServices(context.Background(), &idl.StopServicesRequest{})
+	_, err = client.StopServices(context.Background(), &idl.StopServicesRequest{
+		Wait:  durationpb.New(wait),
+		Force: force,
+	})
 	if err != nil {
 		errCode := grpcStatus.Code(err)
 		errMsg := grpcStatus.Convert(err).Message()
@@ -274,9 +298,30 @@ func connectToHubOnPort(port int) (idl.CliToHubClient, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), connTimeout())
 	defer cancel()
 
+	tlsConfig, err := hubTLSConfig()
+	if err != nil {
+		return nil, xerrors.Errorf("hub TLS configuration: %w", err)
+	}
+
+	credentials, err := tlsConfig.CLIDialOption()
+	if err != nil {
+		return nil, err
+	}
+
+	host, err := hubHost()
+	if err != nil {
+		return nil, err
+	}
+
+	// Identify this process to the hub as the owner of the steps it runs.
+	stepOwner, err := hub.StepOwnerInterceptor()
+	if err != nil {
+		return nil, err
+	}
+
 	// Attempt a connection.
-	address := "localhost:" + strconv.Itoa(port)
-	conn, err := grpc.DialContext(ctx, address, grpc.WithInsecure(), grpc.WithBlock())
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	conn, err := grpc.DialContext(ctx, address, credentials, grpc.WithBlock(), grpc.WithStreamInterceptor(stepOwner))
 	if err != nil {
 		err = xerrors.Errorf("connecting to hub on port %d: %w", port, err)
 		if ctx.Err() == context.DeadlineExceeded {
@@ -330,3 +375,82 @@ func hubPort() (int, error) {
 
 	return conf.HubPort, nil
 }
+
+// hubTLSConfig reads the gpupgrade persisted configuration for the TLS
+// certificates used to connect to the hub. If the configuration does not exist
+// TLS is disabled.
+func hubTLSConfig() (mtls.Config, error) {
+	conf, err := config.Read()
+	var pathError *os.PathError
+	if xerrors.As(err, &pathError) {
+		return mtls.Config{}, nil
+	}
+
+	if err != nil {
+		return mtls.Config{}, xerrors.Errorf("read config: %w", err)
+	}
+
+	return conf.TLS, nil
+}
+
+// hubHost reads the gpupgrade persisted configuration for the address the hub
+// listens on and returns the host to connect to. If the configuration does not
+// exist, or the hub listens on all interfaces, localhost is returned.
+func hubHost() (string, error) {
+	conf, err := config.Read()
+	var pathError *os.PathError
+	if xerrors.As(err, &pathError) {
+		return "localhost", nil
+	}
+
+	if err != nil {
+		return "", xerrors.Errorf("read config: %w", err)
+	}
+
+	ip := net.ParseIP(conf.HubListenAddress)
+	if conf.HubListenAddress == "" || (ip != nil && ip.IsUnspecified()) {
+		return "localhost", nil
+	}
+
+	return conf.HubListenAddress, nil
+}
+
+// writeUpgradeReport writes the upgrade report to the log archive directory.
+// It runs before the state directory is deleted since the report is built from
+// the persisted configuration and substep status. Failing to write the report
+// only prints a warning since it should not fail the step after the cluster
+// has been finalized or reverted.
+func writeUpgradeReport(stepName idl.Step, logArchiveDir string) {
+	err := writeUpgradeReportFile(stepName, logArchiveDir)
+	if err != nil {
+		log.Printf("warning: failed to write the upgrade report: %v", err)
+		fmt.Printf("\nWarning: failed to write the upgrade report: %v\n", err)
+	}
+}
+
+// upgradeReportText returns the completion text listing the upgrade report, or
+// nothing when the report was not written. The report is checked for on disk
+// rather than remembered from writing it since a resumed step skips writing
+// an already written report.
+func upgradeReportText(logArchiveDir string) string {
+	htmlPath := filepath.Join(logArchiveDir, commanders.UpgradeReportHTMLFile)
+	markdownPath := filepath.Join(logArchiveDir, commanders.UpgradeReportMarkdownFile)
+
+	for _, path := range []string{htmlPath, markdownPath} {
+		if _, err := os.Stat(path); err != nil {
+			return ""
+		}
+	}
+
+	return fmt.Sprintf("\n\nThe upgrade report can be found in\n%s\n%s", htmlPath, markdownPath)
+}
+
+func writeUpgradeReportFile(stepName idl.Step, logArchiveDir string) error {
+	conf, err := config.Read()
+	if err != nil {
+		return xerrors.Errorf("read config: %w", err)
+	}
+
+	report := commanders.NewUpgradeReport(stepName, conf, utils.GetStateDir(), logArchiveDir)
+	return commanders.WriteUpgradeReport(logArchiveDir, report)
+}
@@ -4,13 +4,21 @@
 package commands
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
 	"github.com/greenplum-db/gpupgrade/config"
 	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/testutils"
 	"github.com/greenplum-db/gpupgrade/testutils/testlog"
 	"github.com/greenplum-db/gpupgrade/upgrade"
+	"github.com/greenplum-db/gpupgrade/utils/mtls"
 )
 
 func TestGetHubPort(t *testing.T) {
@@ -74,3 +82,179 @@ func TestGetHubPort(t *testing.T) {
 	})
 
 }
+
+func TestHubTLSConfig(t *testing.T) {
+	testlog.SetupTestLogger()
+
+	t.Run("pulls the TLS configuration from the stored config", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+		defer resetEnv()
+
+		expected := mtls.Config{
+			CACertificate:    "/certs/ca.crt",
+			CLICertificate:   "/certs/cli.crt",
+			CLIKey:           "/certs/cli.key",
+			HubCertificate:   "/certs/hub.crt",
+			HubKey:           "/certs/hub.key",
+			AgentCertificate: "/certs/agent.crt",
+			AgentKey:         "/certs/agent.key",
+		}
+		hubServer := hub.New(&config.Config{TLS: expected})
+		err := hubServer.Config.Write()
+		if err != nil {
+			t.Errorf("got unexpected error %#v", err)
+		}
+
+		tlsConfig, err := hubTLSConfig()
+		if err != nil {
+			t.Errorf("unexpected err %#v", err)
+		}
+
+		if tlsConfig != expected {
+			t.Errorf("got %+v expected %+v", tlsConfig, expected)
+		}
+	})
+
+	t.Run("disables TLS if the config file does not exist", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+		defer resetEnv()
+
+		testutils.PathMustNotExist(t, config.GetConfigFile())
+
+		tlsConfig, err := hubTLSConfig()
+		if err != nil {
+			t.Errorf("unexpected err %#v", err)
+		}
+
+		if tlsConfig.Enabled() {
+			t.Errorf("expected TLS to be disabled got %+v", tlsConfig)
+		}
+	})
+}
+
+func TestHubHost(t *testing.T) {
+	testlog.SetupTestLogger()
+
+	cases := []struct {
+		name          string
+		listenAddress string
+		expected      string
+	}{
+		{name: "uses localhost when listening on all interfaces", listenAddress: "", expected: "localhost"},
+		{name: "uses localhost when listening on the unspecified IPv4 address", listenAddress: "0.0.0.0", expected: "localhost"},
+		{name: "uses localhost when listening on the unspecified IPv6 address", listenAddress: "::", expected: "localhost"},
+		{name: "uses the listen address when listening on a specific interface", listenAddress: "10.0.0.1", expected: "10.0.0.1"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			stateDir := testutils.GetTempDir(t, "")
+			defer testutils.MustRemoveAll(t, stateDir)
+
+			resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+			defer resetEnv()
+
+			hubServer := hub.New(&config.Config{HubListenAddress: c.listenAddress})
+			err := hubServer.Config.Write()
+			if err != nil {
+				t.Errorf("got unexpected error %#v", err)
+			}
+
+			host, err := hubHost()
+			if err != nil {
+				t.Errorf("unexpected err %#v", err)
+			}
+
+			if host != c.expected {
+				t.Errorf("got %q expected %q", host, c.expected)
+			}
+		})
+	}
+
+	t.Run("uses localhost if the config file does not exist", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+		defer resetEnv()
+
+		host, err := hubHost()
+		if err != nil {
+			t.Errorf("unexpected err %#v", err)
+		}
+
+		if host != "localhost" {
+			t.Errorf("got %q expected %q", host, "localhost")
+		}
+	})
+}
+
+func TestWriteUpgradeReport(t *testing.T) {
+	t.Run("prints a warning rather than failing when the report cannot be written", func(t *testing.T) {
+		logs := testlog.SetupTestLogger()
+
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		// there is no config file in the state directory
+		resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+		defer resetEnv()
+
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		stdout := os.Stdout
+		os.Stdout = w
+		writeUpgradeReport(idl.Step_finalize, stateDir)
+		os.Stdout = stdout
+		w.Close()
+
+		output, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := "Warning: failed to write the upgrade report: read config"
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("got output %q want it to contain %q", output, expected)
+		}
+
+		testlog.VerifyLogContains(t, logs, "warning: failed to write the upgrade report: read config")
+	})
+}
+
+func TestUpgradeReportText(t *testing.T) {
+	t.Run("lists the upgrade report when it was written", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		testutils.MustWriteToFile(t, filepath.Join(dir, commanders.UpgradeReportHTMLFile), "")
+		testutils.MustWriteToFile(t, filepath.Join(dir, commanders.UpgradeReportMarkdownFile), "")
+
+		text := upgradeReportText(dir)
+
+		expected := fmt.Sprintf("\n\nThe upgrade report can be found in\n%s\n%s",
+			filepath.Join(dir, commanders.UpgradeReportHTMLFile), filepath.Join(dir, commanders.UpgradeReportMarkdownFile))
+		if text != expected {
+			t.Errorf("got %q want %q", text, expected)
+		}
+	})
+
+	t.Run("leaves out the upgrade report when it was not written", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		text := upgradeReportText(dir)
+		if text != "" {
+			t.Errorf("got %q want no upgrade report", text)
+		}
+	})
+}
@@ -13,17 +13,32 @@ You are about to initialize a major-version upgrade of Greenplum.
 gpupgrade log files can be found on all hosts in %s
 
 gpupgrade initialize will use these values from %s
-source_master_port:   %d
-source_gphome:        %s
-target_gphome:        %s
-mode:                 %s
-disk_free_ratio:      %.1f
-pg_upgrade_jobs:      %d
-use_hba_hostnames:    %t
-dynamic_library_path: %s
-temp_port_range:      %s
-hub_port:             %d
-agent_port:           %d
+source_master_port:    %d
+source_gphome:         %s
+target_gphome:         %s
+mode:                  %s
+disk_free_ratio:       %.1f
+measure_disk_space:    %t
+pg_upgrade_jobs:       %d
+max_parallel_segments: %d
+max_parallel_hosts:    %d
+agent_rpc_timeouts:    %s
+agent_launcher:        %s
+use_hba_hostnames:     %t
+dynamic_library_path:  %s
+temp_port_range:       %s
+hub_port:              %d
+agent_port:            %d
+hub_listen_address:    %s
+agent_listen_address:  %s
+metrics_port:          %d
+tls_ca_certificate:    %s
+tls_cli_certificate:   %s
+tls_cli_key:           %s
+tls_hub_certificate:   %s
+tls_hub_key:           %s
+tls_agent_certificate: %s
+tls_agent_key:         %s
 
 You will still have the opportunity to revert the cluster to its original state 
 after this step.
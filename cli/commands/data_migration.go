@@ -5,6 +5,7 @@ package commands
 
 import (
 	"fmt"
+	"log"
 	"path/filepath"
 	"strings"
 
@@ -58,6 +59,8 @@ func dataMigrationApply() *cobra.Command {
 	var port int
 	var inputDir string
 	var phase string
+	var mode string
+	var pgUpgradeJobs uint
 
 	logDir, err := utils.GetLogDir()
 	if err != nil {
@@ -76,12 +79,21 @@ func dataMigrationApply() *cobra.Command {
 				return err
 			}
 
+			parsedMode, err := parseMode(mode)
+			if err != nil {
+				return err
+			}
+
 			currentDir := filepath.Join(filepath.Clean(inputDir), "current")
 			err = commanders.ApplyDataMigrationScripts(nonInteractive, filepath.Clean(gphome), port, logDir, utils.System.DirFS(currentDir), currentDir, parsedPhase)
 			if err != nil {
 				return err
 			}
 
+			if parsedPhase == idl.Step_stats {
+				printUpgradeEstimate(filepath.Clean(gphome), port, logDir, parsedMode, pgUpgradeJobs)
+			}
+
 			return nil
 		},
 	}
@@ -92,6 +104,8 @@ func dataMigrationApply() *cobra.Command {
 	dataMigrationExecutor.Flags().IntVar(&port, "port", 0, "master port for Greenplum cluster")
 	dataMigrationExecutor.Flags().StringVar(&inputDir, "input-dir", inputDir, "path to the generated data migration SQL files. Defaults to $HOME/gpAdminLogs/gpupgrade/data-migration-scripts")
 	dataMigrationExecutor.Flags().StringVar(&phase, "phase", "", `data migration phase. Either "pre-initialize", "post-finalize", "post-revert", or "stats".`)
+	dataMigrationExecutor.Flags().StringVar(&mode, "mode", "copy", "the upgrade mode used to estimate the upgrade duration for the stats phase. Either copy or link. Default is copy.")
+	dataMigrationExecutor.Flags().UintVar(&pgUpgradeJobs, "pg-upgrade-jobs", 4, "the pg_upgrade jobs used to estimate the upgrade duration for the stats phase. Defaults to 4.")
 
 	return addHelpToCommand(dataMigrationExecutor, applyHelp)
 }
@@ -107,3 +121,16 @@ func parsePhase(input string) (idl.Step, error) {
 
 	return idl.Step_unknown_step, fmt.Errorf("Invalid phase %q. Please specify either %s.", input, commanders.MigrationScriptPhases)
 }
+
+// printUpgradeEstimate prints the estimated upgrade duration. Since the
+// estimate is informational, failing to estimate does not fail the command.
+func printUpgradeEstimate(gphome string, port int, logDir string, mode idl.Mode, pgUpgradeJobs uint) {
+	estimate, err := commanders.EstimateUpgradeDuration(gphome, port, logDir, mode, pgUpgradeJobs)
+	if err != nil {
+		log.Printf("estimating upgrade duration: %v", err)
+		fmt.Printf("\nUnable to estimate the upgrade duration: %v\n", err)
+		return
+	}
+
+	fmt.Printf("\n%s\n", estimate)
+}
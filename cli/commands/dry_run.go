@@ -0,0 +1,77 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+// printDryRun prints the substeps a step would run without running them. The plan
+// is built from the saved configuration and substep status so neither the hub
+// nor agents need to be running, and nothing is written to the state directory.
+func printDryRun(stepName idl.Step, parentBackupDirs string) error {
+	conf, err := config.Read()
+	if err != nil {
+		return err
+	}
+
+	stateDir := utils.GetStateDir()
+
+	var plan *step.Plan
+	switch stepName {
+	case idl.Step_execute:
+		plan, err = hub.ExecutePlan(conf, stateDir, parentBackupDirs)
+	case idl.Step_finalize:
+		plan, err = hub.FinalizePlan(conf, stateDir)
+	case idl.Step_revert:
+		plan, err = hub.RevertPlan(conf, stateDir)
+	default:
+		return fmt.Errorf("dry run is not supported for %s", stepName)
+	}
+	if err != nil {
+		return err
+	}
+
+	// The CLI records its substeps in the same store as the hub.
+	cliPlan := step.NewPlan(stepName, step.NewSubstepStoreUsingFile(filepath.Join(stateDir, step.SubstepsFileName)))
+	switch stepName {
+	case idl.Step_finalize:
+		finalizePlan(cliPlan, stateDir)
+	case idl.Step_revert:
+		revertPlan(cliPlan, stateDir)
+	}
+
+	if err := cliPlan.Err(); err != nil {
+		return err
+	}
+
+	plan.Substeps = append(plan.Substeps, cliPlan.Substeps...)
+
+	fmt.Print(commanders.FormatPlan(plan))
+	return nil
+}
+
+// finalizePlan and revertPlan mirror the substeps the CLI runs in finalize
+// and revert after the hub has finished.
+func finalizePlan(plan *step.Plan, stateDir string) {
+	plan.Run(idl.Substep_stop_hub_and_agents, "stop hub and agents")
+	plan.AlwaysRun(idl.Substep_execute_finalize_data_migration_scripts, "prompt to apply finalize data migration scripts")
+	plan.Run(idl.Substep_write_upgrade_report, "write the upgrade report to the log archive directory")
+	plan.Run(idl.Substep_delete_master_statedir, "delete "+stateDir)
+}
+
+func revertPlan(plan *step.Plan, stateDir string) {
+	plan.Run(idl.Substep_stop_hub_and_agents, "stop hub and agents")
+	plan.AlwaysRun(idl.Substep_execute_revert_data_migration_scripts, "prompt to apply revert data migration scripts")
+	plan.Run(idl.Substep_write_upgrade_report, "write the upgrade report to the log archive directory")
+	plan.Run(idl.Substep_delete_master_statedir, "delete "+stateDir)
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestPlansMatchSteps(t *testing.T) {
+	cases := []struct {
+		file     string
+		stepFunc string
+		planFunc string
+	}{
+		{file: "finalize.go", stepFunc: "finalize", planFunc: "finalizePlan"},
+		{file: "revert.go", stepFunc: "revert", planFunc: "revertPlan"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.planFunc+" runs the same substeps as "+c.stepFunc, func(t *testing.T) {
+			expected := testutils.MustGetSubstepCalls(t, c.file, c.stepFunc)
+			if len(expected) == 0 {
+				t.Fatalf("found no substeps in %s", c.stepFunc)
+			}
+
+			planned := testutils.MustGetSubstepCalls(t, "dry_run.go", c.planFunc)
+			if !reflect.DeepEqual(planned, expected) {
+				t.Errorf("%s plans substeps\n%q\nbut %s runs\n%q", c.planFunc, planned, c.stepFunc, expected)
+			}
+		})
+	}
+}
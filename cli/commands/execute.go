@@ -25,6 +25,7 @@ func execute() *cobra.Command {
 	var pgUpgradeVerbose bool
 	var skipPgUpgradeChecks bool
 	var nonInteractive bool
+	var dryRun bool
 	var parentBackupDirs string
 
 	cmd := &cobra.Command{
@@ -39,6 +40,10 @@ func execute() *cobra.Command {
 				return fmt.Errorf("expected --verbose when using --pg-upgrade-verbose")
 			}
 
+			if dryRun {
+				return printDryRun(idl.Step_execute, parentBackupDirs)
+			}
+
 			conf, err := config.Read()
 			if err != nil {
 				return err
@@ -117,6 +122,7 @@ To return the cluster to its original state, run "gpupgrade revert".`,
 	cmd.Flags().MarkHidden("skip-pg-upgrade-checks") //nolint
 	cmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "do not prompt for confirmation to proceed")
 	cmd.Flags().MarkHidden("non-interactive") //nolint
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the substeps that would run and the hosts, directories, and ports they touch without making any changes")
 	cmd.Flags().StringVar(&parentBackupDirs, "parent-backup-dirs", "", "parent directories on each host to internally store the backup of the coordinator data directory and user defined coordinator tablespaces."+
 		"Defaults to the parent directory of each primary data directory on each primary host."+
 		"To specify a single directory across all hosts set a single directory such as /dir."+
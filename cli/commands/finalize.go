@@ -23,6 +23,7 @@ import (
 func finalize() *cobra.Command {
 	var verbose bool
 	var nonInteractive bool
+	var dryRun bool
 
 	cmd := &cobra.Command{
 		Use:   "finalize",
@@ -31,6 +32,10 @@ func finalize() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			var response idl.FinalizeResponse
 
+			if dryRun {
+				return printDryRun(idl.Step_finalize, "")
+			}
+
 			logdir, err := utils.GetLogDir()
 			if err != nil {
 				return err
@@ -65,8 +70,8 @@ func finalize() *cobra.Command {
 			})
 
 			st.Run(idl.Substep_stop_hub_and_agents, func(streams step.OutStreams) error {
-				return stopHubAndAgents()
-			})
+				return stopHubAndAgents(0, false)
+			}, step.Idempotent())
 
 			st.AlwaysRun(idl.Substep_execute_finalize_data_migration_scripts, func(streams step.OutStreams) error {
 				if nonInteractive {
@@ -81,13 +86,18 @@ func finalize() *cobra.Command {
 					response.GetLogArchiveDirectory(), utils.System.DirFS(currentDir), currentDir, idl.Step_finalize)
 			})
 
+			st.Run(idl.Substep_write_upgrade_report, func(streams step.OutStreams) error {
+				writeUpgradeReport(idl.Step_finalize, response.GetLogArchiveDirectory())
+				return nil
+			}, step.Idempotent())
+
 			st.Run(idl.Substep_delete_master_statedir, func(streams step.OutStreams) error {
 				// Removing the state directory removes the step status file.
 				// Disable the store so the step framework does not try to write
 				// to a non-existent status file.
 				st.DisableStore()
 				return upgrade.DeleteDirectories([]string{utils.GetStateDir()}, upgrade.StateDirectoryFiles, streams)
-			})
+			}, step.Idempotent())
 
 			return st.Complete(fmt.Sprintf(`
 Finalize completed successfully.
@@ -103,7 +113,7 @@ All source cluster data directories end in "%s".
 MASTER_DATA_DIRECTORY=%s
 
 The gpupgrade logs can be found on the master and segment hosts in
-%s
+%s%s
 
 NEXT ACTIONS
 ------------
@@ -124,6 +134,7 @@ If you have not already, execute the “%s” data migration scripts with
 				fmt.Sprintf("%s.<contentID>%s", response.GetUpgradeID(), upgrade.OldSuffix),
 				response.GetArchivedSourceCoordinatorDataDirectory(),
 				response.GetLogArchiveDirectory(),
+				upgradeReportText(response.GetLogArchiveDirectory()),
 				filepath.Join(response.GetTarget().GetGpHome(), "greenplum_path.sh"),
 				filepath.Join(filepath.Dir(response.GetTarget().GetGpHome()), "greenplum-db"), response.GetTarget().GetGpHome(),
 				filepath.Join(response.GetTarget().GetGpHome(), "greenplum_path.sh"),
@@ -138,5 +149,6 @@ If you have not already, execute the “%s” data migration scripts with
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "print the output stream from all substeps")
 	cmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "do not prompt for confirmation to proceed")
 	cmd.Flags().MarkHidden("non-interactive") //nolint
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the substeps that would run and the hosts, directories, and ports they touch without making any changes")
 	return addHelpToCommand(cmd, FinalizeHelp)
 }
@@ -24,6 +24,7 @@ var InitializeHelp string
 var ExecuteHelp string
 var FinalizeHelp string
 var RevertHelp string
+var CheckHelp string
 var GlobalHelp string
 var Help map[idl.Step]string
 
@@ -40,12 +41,13 @@ func init() {
 		idl.Substep_execute_initialize_data_migration_scripts,
 		idl.Substep_start_hub,
 		idl.Substep_saving_source_cluster_config,
-		idl.Substep_verify_gpupgrade_is_installed_across_all_hosts,
 		idl.Substep_start_agents,
+		idl.Substep_verify_gpupgrade_is_installed_across_all_hosts,
 		idl.Substep_check_environment,
 		idl.Substep_create_backupdirs,
 		idl.Substep_check_disk_space,
 		idl.Substep_generate_target_config,
+		idl.Substep_check_target_cluster_ports,
 		idl.Substep_init_target_cluster,
 		idl.Substep_setting_dynamic_library_path_on_target_cluster,
 		idl.Substep_shutdown_target_cluster,
@@ -83,6 +85,7 @@ func init() {
 		idl.Substep_delete_segment_statedirs,
 		idl.Substep_stop_hub_and_agents,
 		idl.Substep_execute_finalize_data_migration_scripts,
+		idl.Substep_write_upgrade_report,
 	}
 
 	revertSubsteps = commanders.Substeps{
@@ -99,12 +102,14 @@ func init() {
 		idl.Substep_delete_segment_statedirs,
 		idl.Substep_stop_hub_and_agents,
 		idl.Substep_execute_revert_data_migration_scripts,
+		idl.Substep_write_upgrade_report,
 	}
 
 	InitializeHelp = fmt.Sprintf(initializeHelpText, cases.Title(language.English).String(idl.Step_initialize.String()), initializeSubsteps, logDir)
 	ExecuteHelp = fmt.Sprintf(executeHelpText, cases.Title(language.English).String(idl.Step_execute.String()), executeSubsteps, logDir)
 	FinalizeHelp = fmt.Sprintf(finalizeHelpText, cases.Title(language.English).String(idl.Step_finalize.String()), finalizeSubsteps, logDir)
 	RevertHelp = fmt.Sprintf(revertHelpText, cases.Title(language.English).String(idl.Step_revert.String()), revertSubsteps, logDir)
+	CheckHelp = fmt.Sprintf(checkHelpText, logDir)
 	GlobalHelp = fmt.Sprintf(globalHelpText, logDir)
 
 	Help = map[idl.Step]string{
@@ -154,6 +159,8 @@ Optional Flags:
   -h, --help                 displays help output for execute
   -v, --verbose              outputs detailed logs for execute
       --pg-upgrade-verbose   execute pg_upgrade with verbose internal logging. Requires the verbose flag.
+      --dry-run              prints the substeps that would run, and the hosts, directories, and 
+                             ports they touch without making any changes.
       --parent-backup-dir    The parent directory location used internally to store the backup of the 
                              master data directory and user defined master tablespaces. Defaults to the 
                              parent directory of the master data directory such as /data given 
@@ -176,11 +183,16 @@ Optional Flags:
 
   -h, --help      displays help output for finalize
   -v, --verbose   outputs detailed logs for finalize
+      --dry-run   prints the substeps that would run, and the hosts, directories, 
+                  and ports they touch without making any changes.
 
 NOTE: After running finalize, you must execute data migration scripts. 
 Refer to documentation for instructions.
 
 gpupgrade log files can be found on all hosts in %s
+
+An upgrade report in HTML and Markdown is written to the archived log
+directory on the master host.
 `
 const revertHelpText = `
 Returns the cluster to its original state.
@@ -195,11 +207,16 @@ Optional Flags:
 
   -h, --help      displays help output for revert
   -v, --verbose   outputs detailed logs for revert
+      --dry-run   prints the substeps that would run, and the hosts, directories, 
+                  and ports they touch without making any changes.
 
 NOTE: After running revert, you must execute data migration scripts. 
 Refer to documentation for instructions.
 
 Archived gpupgrade log files can be found on all hosts in %s-<upgradeID>-<timestamp>
+
+An upgrade report in HTML and Markdown is written to the archived log
+directory on the master host.
 `
 const generateHelp = `
 Generates data migration SQL scripts to resolve catalog inconsistencies between 
@@ -240,8 +257,12 @@ Required Flags:
 
 Optional Flags:
 
-  --input-dir    path to the generated data migration SQL files. 
-                 Defaults to $HOME/gpAdminLogs/gpupgrade/data-migration-scripts
+  --input-dir        path to the generated data migration SQL files. 
+                     Defaults to $HOME/gpAdminLogs/gpupgrade/data-migration-scripts
+  --mode             the upgrade mode used to estimate the upgrade duration for 
+                     the stats phase. Either copy or link. Defaults to copy.
+  --pg-upgrade-jobs  the pg_upgrade jobs used to estimate the upgrade duration 
+                     for the stats phase. Defaults to 4.
 `
 const ConfigHelp = `
 The config subcommand allows one to view configuration parameters only after 
@@ -263,6 +284,94 @@ Optional Flags:
 Example:
   gpupgrade config show --target-datadir
 `
+const RecoverHelp = `
+Recovers a substep that was left running after the hub was interrupted, such
+as after a crash or host reboot. The side effects of the substep are checked
+to determine whether it completed, and its status is reset to either complete
+or failed so the step can be run again.
+
+Usage: gpupgrade recover --substep <substep>
+
+Required Flags:
+
+  --substep       the name of the substep to recover such as
+                  update_data_directories
+
+Optional Flags:
+
+  -h, --help      displays help output for recover
+`
+const StatusHelp = `
+Shows the status of each gpupgrade step and its substeps including when each
+substep started, how long it took, and the last error if it failed.
+When the hub is not running the status is read from the state directory.
+
+Usage: gpupgrade status
+
+Optional Flags:
+
+  -h, --help      displays help output for status
+      --format    specify the output format as either "text" or "json".
+                  Default is text.
+`
+
+const AgentsHelp = `
+Shows the gpupgrade agent on each host including its version, uptime, state
+directory, binary path, and the free disk space under each data directory.
+Requires the hub to be running.
+
+Usage: gpupgrade agents
+
+Optional Flags:
+
+  -h, --help      displays help output for agents
+      --format    specify the output format as either "text" or "json".
+                  Default is text.
+`
+
+const BreakStepLockHelp = `
+Cancels the running step and unlocks it such that another step can run. The
+hub runs a single step at a time, so use this when the step was left running
+such as when its gpupgrade session is stuck or gone. Any substep still
+running is canceled, and the lock is released once the step returns or after
+30 seconds.
+
+Usage: gpupgrade break-step-lock
+
+Optional Flags:
+
+  -h, --help      displays help output for break-step-lock
+`
+
+const checkHelpText = `
+Runs read-only pre-upgrade checks and reports whether each passed. Run it
+before initialize to find problems ahead of the downtime window. It does not
+start the hub or agents and does not create the state directory, so it can be
+run any number of times.
+
+The following checks are run:
+ - version compatibility of the source and target Greenplum installations
+ - environment of each host does not contain the Greenplum installations
+ - disk space of the data directories and tablespaces on each host
+ - active connections to the source cluster
+ - segment health such that all segments are up, in their preferred roles,
+   and synchronized
+ - port availability of the temp_port_range on each host
+ - tablespace layout such that tablespaces are outside the data directories
+
+The report is written to %s
+
+Usage: gpupgrade check --file <path/to/config_file>
+
+Required Flags:
+
+  -f, --file      config file containing upgrade parameters
+                  (e.g. gpupgrade_config)
+
+Optional Flags:
+
+  -h, --help      displays help output for check
+`
 
 const globalHelpText = `
 gpupgrade performs an in-place cluster upgrade to the next major version.
@@ -281,6 +390,9 @@ Required Commands:
 
 Optional Commands:
 
+  check           runs read-only pre-upgrade checks without preparing
+                  the cluster for upgrade
+
   revert          returns the cluster to its original state
                   Note: revert cannot be used after gpupgrade finalize
 
@@ -294,6 +406,16 @@ Optional Commands:
                   useful for getting the target cluster data directory
                   and port in order to start or connect to the target cluster.
 
+  status          shows the status of each step and substep
+
+  recover         recovers a substep that was left running after the hub
+                  was interrupted
+
+  agents          shows the gpupgrade agent on each host
+
+  break-step-lock cancels the running step and unlocks it such that
+                  another step can run
+
 Optional Flags:
 
   -h, --help      displays help output for gpupgrade
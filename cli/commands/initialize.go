@@ -19,16 +19,19 @@ import (
 	"golang.org/x/text/language"
 	"golang.org/x/xerrors"
 
+	"github.com/greenplum-db/gpupgrade/agent/launcher"
 	"github.com/greenplum-db/gpupgrade/cli/clistep"
 	"github.com/greenplum-db/gpupgrade/cli/commanders"
 	"github.com/greenplum-db/gpupgrade/config"
 	"github.com/greenplum-db/gpupgrade/greenplum"
 	"github.com/greenplum-db/gpupgrade/greenplum/connection"
+	"github.com/greenplum-db/gpupgrade/hub"
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/step"
 	"github.com/greenplum-db/gpupgrade/upgrade"
 	"github.com/greenplum-db/gpupgrade/utils"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+	"github.com/greenplum-db/gpupgrade/utils/mtls"
 )
 
 func initialize() *cobra.Command {
@@ -38,19 +41,28 @@ func initialize() *cobra.Command {
 	var sourcePort int
 	var hubPort int
 	var agentPort int
+	var hubListenAddress string
+	var agentListenAddress string
+	var metricsPort int
 	var parentBackupDirs string
 	var diskFreeRatio float64
+	var measureDiskSpace bool
 	var stopBeforeClusterCreation bool
 	var verbose bool
 	var pgUpgradeVerbose bool
 	var skipVersionCheck bool
 	var skipPgUpgradeChecks bool
 	var pgUpgradeJobs uint
+	var maxParallelSegments uint
+	var maxParallelHosts uint
+	var agentRPCTimeouts string
+	var agentLauncher string
 	var ports string
 	var mode string
 	var useHbaHostnames bool
 	var dynamicLibraryPath string
 	var dataMigrationSeedDir string
+	var tlsConfig mtls.Config
 
 	subInit := &cobra.Command{
 		Use:   "initialize",
@@ -137,6 +149,10 @@ func initialize() *cobra.Command {
 				}
 			}
 
+			if err := tlsConfig.Validate(); err != nil {
+				return err
+			}
+
 			if diskFreeRatio < 0.0 || diskFreeRatio > 1.0 {
 				// Match Cobra's option-error format.
 				return fmt.Errorf(
@@ -170,7 +186,8 @@ func initialize() *cobra.Command {
 			confirmationText := fmt.Sprintf(initializeConfirmationText,
 				cases.Title(language.English).String(idl.Step_initialize.String()),
 				initializeSubsteps, logdir, configPath,
-				sourcePort, sourceGPHome, targetGPHome, mode, diskFreeRatio, pgUpgradeJobs, useHbaHostnames, dynamicLibraryPath, ports, hubPort, agentPort)
+				sourcePort, sourceGPHome, targetGPHome, mode, diskFreeRatio, measureDiskSpace, pgUpgradeJobs, maxParallelSegments, maxParallelHosts, agentRPCTimeouts, agentLauncher, useHbaHostnames, dynamicLibraryPath, ports, hubPort, agentPort, hubListenAddress, agentListenAddress, metricsPort,
+				tlsConfig.CACertificate, tlsConfig.CLICertificate, tlsConfig.CLIKey, tlsConfig.HubCertificate, tlsConfig.HubKey, tlsConfig.AgentCertificate, tlsConfig.AgentKey)
 
 			log.Print(confirmationText)
 
@@ -181,7 +198,7 @@ func initialize() *cobra.Command {
 
 			st.RunConditionally(idl.Substep_verify_gpdb_versions, !skipVersionCheck, func(streams step.OutStreams) error {
 				return greenplum.VerifyCompatibleGPDBVersions(sourceGPHome, targetGPHome)
-			})
+			}, step.Idempotent())
 
 			st.Run(idl.Substep_saving_source_cluster_config, func(streams step.OutStreams) error {
 				parsedPorts, err := ParsePorts(ports)
@@ -189,6 +206,16 @@ func initialize() *cobra.Command {
 					return err
 				}
 
+				parsedAgentRPCTimeouts, err := hub.ParseRPCTimeouts(agentRPCTimeouts)
+				if err != nil {
+					return err
+				}
+
+				_, err = launcher.New(agentLauncher)
+				if err != nil {
+					return err
+				}
+
 				db, err := connection.Bootstrap(idl.ClusterDestination_source, sourceGPHome, sourcePort)
 				if err != nil {
 					return err
@@ -198,13 +225,25 @@ func initialize() *cobra.Command {
 						err = errorlist.Append(err, cErr)
 					}
 				}()
-				config, err := config.Create(
-					db, hubPort, agentPort,
-					filepath.Clean(sourceGPHome),
-					filepath.Clean(targetGPHome),
-					mode, useHbaHostnames, parsedPorts, pgUpgradeJobs,
-					parentBackupDirs,
-				)
+				config, err := config.Create(db, config.CreateOptions{
+					HubPort:             hubPort,
+					AgentPort:           agentPort,
+					HubListenAddress:    hubListenAddress,
+					AgentListenAddress:  agentListenAddress,
+					MetricsPort:         metricsPort,
+					SourceGPHome:        filepath.Clean(sourceGPHome),
+					TargetGPHome:        filepath.Clean(targetGPHome),
+					Mode:                mode,
+					UseHbaHostnames:     useHbaHostnames,
+					Ports:               parsedPorts,
+					PgUpgradeJobs:       pgUpgradeJobs,
+					MaxParallelSegments: maxParallelSegments,
+					MaxParallelHosts:    maxParallelHosts,
+					AgentRPCTimeouts:    parsedAgentRPCTimeouts,
+					AgentLauncher:       agentLauncher,
+					ParentBackupDirs:    parentBackupDirs,
+					TLS:                 tlsConfig,
+				})
 				if err != nil {
 					return err
 				}
@@ -231,7 +270,7 @@ func initialize() *cobra.Command {
 
 				return commanders.GenerateDataMigrationScripts(nonInteractive, sourceGPHome, sourcePort,
 					filepath.Clean(dataMigrationSeedDir), generatedScriptsOutputDir, utils.System.DirFS(generatedScriptsOutputDir))
-			})
+			}, step.Idempotent())
 
 			st.AlwaysRun(idl.Substep_execute_stats_data_migration_scripts, func(streams step.OutStreams) error {
 				if nonInteractive {
@@ -242,8 +281,14 @@ func initialize() *cobra.Command {
 				fmt.Println()
 
 				currentDir := filepath.Join(generatedScriptsOutputDir, "current")
-				return commanders.ApplyDataMigrationScripts(nonInteractive, sourceGPHome, sourcePort,
+				err = commanders.ApplyDataMigrationScripts(nonInteractive, sourceGPHome, sourcePort,
 					logdir, utils.System.DirFS(currentDir), currentDir, idl.Step_stats)
+				if err != nil {
+					return err
+				}
+
+				printUpgradeEstimate(sourceGPHome, sourcePort, logdir, mode, pgUpgradeJobs)
+				return nil
 			})
 
 			st.AlwaysRun(idl.Substep_execute_initialize_data_migration_scripts, func(streams step.OutStreams) error {
@@ -275,6 +320,7 @@ func initialize() *cobra.Command {
 				request := &idl.InitializeRequest{
 					DiskFreeRatio:    diskFreeRatio,
 					ParentBackupDirs: parentBackupDirs,
+					MeasureDiskSpace: measureDiskSpace,
 				}
 				err = commanders.Initialize(client, request, verbose)
 				if err != nil {
@@ -326,6 +372,10 @@ To return the cluster to its original state, run "gpupgrade revert".`,
 	subInit.Flags().BoolVar(&skipPgUpgradeChecks, "skip-pg-upgrade-checks", false, "skips pg_upgrade checks")
 	subInit.Flags().MarkHidden("skip-pg-upgrade-checks") //nolint
 	subInit.Flags().UintVar(&pgUpgradeJobs, "pg-upgrade-jobs", 4, "databases to upgrade in parallel based on the number of specified threads. Defaults to 4.")
+	subInit.Flags().UintVar(&maxParallelSegments, "max-parallel-segments", 0, "primary segments to upgrade in parallel on each host. Defaults to all primary segments on the host.")
+	subInit.Flags().UintVar(&maxParallelHosts, "max-parallel-hosts", 0, "hosts to upgrade primary segments on in parallel. Defaults to all hosts.")
+	subInit.Flags().StringVar(&agentRPCTimeouts, "agent-rpc-timeouts", "", "overrides the timeout of agent RPCs in the form \"RPC=duration\" such as \"DeleteDataDirectories=30m,RsyncDataDirectories=4h\".")
+	subInit.Flags().StringVar(&agentLauncher, "agent-launcher", launcher.SSHLauncher, "how the hub starts the agents and runs commands on their hosts. Either ssh, systemd, or running. Defaults to ssh.")
 	subInit.Flags().StringVarP(&file, "file", "f", "", "the configuration file to use")
 	subInit.Flags().BoolVar(&nonInteractive, "non-interactive", false, "do not prompt for confirmation to proceed")
 	subInit.Flags().MarkHidden("non-interactive") //nolint
@@ -338,11 +388,22 @@ To return the cluster to its original state, run "gpupgrade revert".`,
 		"To specify a single directory across all hosts set /dir."+
 		"To specify different directories for each host use the form \"host1:/dir1,host2:/dir2,host3:/dir3\" where the first host must be the coordinator.")
 	subInit.Flags().Float64Var(&diskFreeRatio, "disk-free-ratio", 0.60, "percentage of disk space that must be available (from 0.0 - 1.0)")
+	subInit.Flags().BoolVar(&measureDiskSpace, "measure-disk-space", false, "check disk space against the measured size of the data directories and tablespaces rather than the disk-free-ratio")
 	subInit.Flags().BoolVar(&useHbaHostnames, "use-hba-hostnames", false, "use hostnames in pg_hba.conf")
 	subInit.Flags().StringVar(&dynamicLibraryPath, "dynamic-library-path", upgrade.DefaultDynamicLibraryPath, "sets the dynamic_library_path GUC to correctly find extensions installed outside their default location. Defaults to '$dynamic_library_path'.")
 	subInit.Flags().StringVar(&ports, "temp-port-range", "50432-65535", "set of ports to use when initializing the target cluster")
 	subInit.Flags().IntVar(&hubPort, "hub-port", upgrade.DefaultHubPort, "the port gpupgrade hub uses to listen for commands on")
 	subInit.Flags().IntVar(&agentPort, "agent-port", upgrade.DefaultAgentPort, "the port gpupgrade agent uses to listen for commands on")
+	subInit.Flags().StringVar(&hubListenAddress, "hub-listen-address", "", "the address gpupgrade hub listens on. Defaults to all interfaces.")
+	subInit.Flags().StringVar(&agentListenAddress, "agent-listen-address", "", "the address gpupgrade agents listen on, either a single address or \"host1:addr1,host2:addr2\". Defaults to all interfaces.")
+	subInit.Flags().IntVar(&metricsPort, "metrics-port", 0, "the port gpupgrade hub serves Prometheus metrics on at /metrics. Disabled when 0.")
+	subInit.Flags().StringVar(&tlsConfig.CACertificate, "tls-ca-certificate", "", "the CA certificate that signed the CLI, hub, and agent certificates. Enables mutual TLS between the CLI, hub, and agents.")
+	subInit.Flags().StringVar(&tlsConfig.CLICertificate, "tls-cli-certificate", "", "the CLI certificate which is the only client certificate the hub accepts")
+	subInit.Flags().StringVar(&tlsConfig.CLIKey, "tls-cli-key", "", "the CLI private key")
+	subInit.Flags().StringVar(&tlsConfig.HubCertificate, "tls-hub-certificate", "", "the hub certificate which must be valid for localhost or the hub listen address")
+	subInit.Flags().StringVar(&tlsConfig.HubKey, "tls-hub-key", "", "the hub private key")
+	subInit.Flags().StringVar(&tlsConfig.AgentCertificate, "tls-agent-certificate", "", "the agent certificate path on all hosts which must be valid for each hostname")
+	subInit.Flags().StringVar(&tlsConfig.AgentKey, "tls-agent-key", "", "the agent private key path on all hosts")
 	subInit.Flags().BoolVar(&stopBeforeClusterCreation, "stop-before-cluster-creation", false, "only run up to pre-init")
 	subInit.Flags().MarkHidden("stop-before-cluster-creation") //nolint
 	subInit.Flags().BoolVar(&skipVersionCheck, "skip-version-check", false, "disable source and target version check")
@@ -0,0 +1,49 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+func recoverSubstep() *cobra.Command {
+	var substepName string
+
+	cmd := &cobra.Command{
+		Use:   "recover",
+		Short: "recovers a substep that was left running after the hub was interrupted",
+		Long:  RecoverHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			value, ok := idl.Substep_value[substepName]
+			if !ok || idl.Substep(value) == idl.Substep_unknown_substep || idl.Substep(value) == idl.Substep_step_status {
+				return xerrors.Errorf("invalid substep %q", substepName)
+			}
+
+			client, err := connectToHub()
+			if err != nil {
+				return err
+			}
+
+			reply, err := client.Recover(context.Background(), &idl.RecoverRequest{Substep: idl.Substep(value)})
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Marked %s substep %s as %s.\n", reply.GetStep(), substepName, reply.GetStatus())
+			fmt.Printf("To continue run \"gpupgrade %s\".\n", reply.GetStep())
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&substepName, "substep", "", "the substep to recover such as update_data_directories")
+	cmd.MarkFlagRequired("substep") //nolint
+
+	return addHelpToCommand(cmd, RecoverHelp)
+}
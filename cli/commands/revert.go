@@ -23,6 +23,7 @@ import (
 func revert() *cobra.Command {
 	var verbose bool
 	var nonInteractive bool
+	var dryRun bool
 
 	cmd := &cobra.Command{
 		Use:   "revert",
@@ -31,6 +32,10 @@ func revert() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			var response idl.RevertResponse
 
+			if dryRun {
+				return printDryRun(idl.Step_revert, "")
+			}
+
 			logdir, err := utils.GetLogDir()
 			if err != nil {
 				return err
@@ -65,8 +70,8 @@ func revert() *cobra.Command {
 			})
 
 			st.Run(idl.Substep_stop_hub_and_agents, func(streams step.OutStreams) error {
-				return stopHubAndAgents()
-			})
+				return stopHubAndAgents(0, false)
+			}, step.Idempotent())
 
 			st.AlwaysRun(idl.Substep_execute_revert_data_migration_scripts, func(streams step.OutStreams) error {
 				if nonInteractive {
@@ -81,13 +86,18 @@ func revert() *cobra.Command {
 					response.GetLogArchiveDirectory(), utils.System.DirFS(currentDir), currentDir, idl.Step_revert)
 			})
 
+			st.Run(idl.Substep_write_upgrade_report, func(streams step.OutStreams) error {
+				writeUpgradeReport(idl.Step_revert, response.GetLogArchiveDirectory())
+				return nil
+			}, step.Idempotent())
+
 			st.Run(idl.Substep_delete_master_statedir, func(streams step.OutStreams) error {
 				// Removing the state directory removes the step status file.
 				// Disable the store so the step framework does not try to write
 				// to a non-existent status file.
 				st.DisableStore()
 				return upgrade.DeleteDirectories([]string{utils.GetStateDir()}, upgrade.StateDirectoryFiles, streams)
-			})
+			}, step.Idempotent())
 
 			return st.Complete(fmt.Sprintf(`
 Revert completed successfully.
@@ -98,7 +108,7 @@ export MASTER_DATA_DIRECTORY=%s
 export PGPORT=%d
 
 The gpupgrade logs can be found on the master and segment hosts in
-%s
+%s%s
 
 NEXT ACTIONS
 ------------
@@ -109,6 +119,7 @@ To restart the upgrade, run "gpupgrade initialize" again.`,
 				response.GetSource().GetVersion(),
 				filepath.Join(response.GetSource().GetGpHome(), "greenplum_path.sh"), response.GetSource().GetCoordinator().GetDataDir(), response.GetSource().GetCoordinator().GetPort(),
 				response.GetLogArchiveDirectory(),
+				upgradeReportText(response.GetLogArchiveDirectory()),
 				idl.Step_revert,
 				response.GetSource().GetGpHome(), response.GetSource().GetCoordinator().GetPort(), filepath.Join(response.GetLogArchiveDirectory(), "data-migration-scripts"), idl.Step_revert))
 		},
@@ -117,6 +128,7 @@ To restart the upgrade, run "gpupgrade initialize" again.`,
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "print the output stream from all substeps")
 	cmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "do not prompt for confirmation to proceed")
 	cmd.Flags().MarkHidden("non-interactive") //nolint
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the substeps that would run and the hosts, directories, and ports they touch without making any changes")
 
 	return addHelpToCommand(cmd, RevertHelp)
 }
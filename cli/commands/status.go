@@ -0,0 +1,66 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/greenplum-db/gpupgrade/cli/clistep"
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+func status() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "shows the status of each step and substep",
+		Long:  StatusHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			steps, err := getStatus()
+			if err != nil {
+				return err
+			}
+
+			output, err := commanders.FormatStepStatuses(steps, format)
+			if err != nil {
+				return err
+			}
+
+			fmt.Print(output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "text", `specify the output format as either "text" or "json"`)
+
+	return addHelpToCommand(cmd, StatusHelp)
+}
+
+// getStatus asks the hub for the current status. If the hub is not running,
+// such as before initialize or after a step has stopped it, the status is
+// read directly from the state directory.
+func getStatus() ([]*idl.StepStatus, error) {
+	client, err := connectToHub()
+	if err == nil {
+		var steps []*idl.StepStatus
+		steps, err = commanders.GetStatus(client)
+		if err == nil {
+			return steps, nil
+		}
+	}
+
+	log.Printf("unable to get status from hub, reading from state directory: %v", err)
+	stateDir := utils.GetStateDir()
+	stepStore := clistep.NewStepStoreUsingFile(filepath.Join(stateDir, clistep.StepsFileName))
+	substepStore := step.NewSubstepStoreUsingFile(filepath.Join(stateDir, step.SubstepsFileName))
+	return step.LoadStatusFromStores(stepStore, substepStore)
+}
@@ -13,10 +13,12 @@ import (
 	"golang.org/x/xerrors"
 
 	"github.com/greenplum-db/gpupgrade/config/backupdir"
+	"github.com/greenplum-db/gpupgrade/config/listenaddress"
 	"github.com/greenplum-db/gpupgrade/greenplum"
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/upgrade"
 	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/mtls"
 )
 
 const ConfigFileName = "config.json"
@@ -55,6 +57,33 @@ type Config struct {
 	UseHbaHostnames bool
 	UpgradeID       string
 	PgUpgradeJobs   uint
+
+	// HubListenAddress is the address the hub binds to, and
+	// AgentListenAddresses the address each agent host binds to. When empty
+	// they listen on all interfaces.
+	HubListenAddress     string
+	AgentListenAddresses listenaddress.AgentListenAddresses
+
+	// MetricsPort is the port on which the hub serves Prometheus metrics at
+	// /metrics. Metrics are disabled when zero.
+	MetricsPort int
+
+	// MaxParallelSegments limits how many primary segments each agent
+	// upgrades at a time, and MaxParallelHosts limits how many hosts upgrade
+	// at a time. There is no limit when zero.
+	MaxParallelSegments uint
+	MaxParallelHosts    uint
+
+	// AgentRPCTimeouts overrides the default deadline of agent RPCs by name.
+	AgentRPCTimeouts map[string]time.Duration
+
+	// AgentLauncher names how the hub starts the agents and runs commands on
+	// their hosts such as ssh or systemd. It defaults to ssh when empty.
+	AgentLauncher string
+
+	// TLS contains the certificates used to secure connections between the
+	// CLI, hub, and agents. TLS is disabled when empty.
+	TLS mtls.Config
 }
 
 func (conf *Config) Write() error {
@@ -85,8 +114,32 @@ func GetConfigFile() string {
 	return filepath.Join(utils.GetStateDir(), ConfigFileName)
 }
 
-func Create(db *sql.DB, hubPort int, agentPort int, sourceGPHome string, targetGPHome string, mode idl.Mode, useHbaHostnames bool, ports []int, pgUpgradeJobs uint, parentBackupDirs string) (Config, error) {
-	source, err := greenplum.ClusterFromDB(db, sourceGPHome, idl.ClusterDestination_source)
+// CreateOptions are the parameters of initialize used to create the
+// configuration. AgentListenAddress and ParentBackupDirs are parsed against
+// the source cluster, and Ports is the temporary port range of the
+// intermediate cluster.
+type CreateOptions struct {
+	HubPort             int
+	AgentPort           int
+	HubListenAddress    string
+	AgentListenAddress  string
+	MetricsPort         int
+	SourceGPHome        string
+	TargetGPHome        string
+	Mode                idl.Mode
+	UseHbaHostnames     bool
+	Ports               []int
+	PgUpgradeJobs       uint
+	MaxParallelSegments uint
+	MaxParallelHosts    uint
+	AgentRPCTimeouts    map[string]time.Duration
+	AgentLauncher       string
+	ParentBackupDirs    string
+	TLS                 mtls.Config
+}
+
+func Create(db *sql.DB, opts CreateOptions) (Config, error) {
+	source, err := greenplum.ClusterFromDB(db, opts.SourceGPHome, idl.ClusterDestination_source)
 	if err != nil {
 		return Config{}, xerrors.Errorf("retrieve source configuration: %w", err)
 	}
@@ -97,19 +150,31 @@ func Create(db *sql.DB, hubPort int, agentPort int, sourceGPHome string, targetG
 		return Config{}, err
 	}
 
-	targetVersion, err := greenplum.Version(targetGPHome)
+	targetVersion, err := greenplum.Version(opts.TargetGPHome)
 	if err != nil {
 		return Config{}, err
 	}
 
 	config := Config{}
-	config.HubPort = hubPort
-	config.AgentPort = agentPort
-	config.Mode = mode
-	config.UseHbaHostnames = useHbaHostnames
+	config.HubPort = opts.HubPort
+	config.AgentPort = opts.AgentPort
+	config.HubListenAddress = opts.HubListenAddress
+	config.MetricsPort = opts.MetricsPort
+	config.Mode = opts.Mode
+	config.UseHbaHostnames = opts.UseHbaHostnames
 	config.UpgradeID = upgrade.NewID()
-	config.PgUpgradeJobs = pgUpgradeJobs
-	config.BackupDirs, err = backupdir.ParseParentBackupDirs(parentBackupDirs, source)
+	config.PgUpgradeJobs = opts.PgUpgradeJobs
+	config.MaxParallelSegments = opts.MaxParallelSegments
+	config.MaxParallelHosts = opts.MaxParallelHosts
+	config.AgentRPCTimeouts = opts.AgentRPCTimeouts
+	config.AgentLauncher = opts.AgentLauncher
+	config.TLS = opts.TLS
+	config.BackupDirs, err = backupdir.ParseParentBackupDirs(opts.ParentBackupDirs, source)
+	if err != nil {
+		return Config{}, err
+	}
+
+	config.AgentListenAddresses, err = listenaddress.ParseAgentListenAddresses(opts.AgentListenAddress, source)
 	if err != nil {
 		return Config{}, err
 	}
@@ -119,10 +184,10 @@ func Create(db *sql.DB, hubPort int, agentPort int, sourceGPHome string, targetG
 
 	config.Target = &target
 	config.Target.Destination = idl.ClusterDestination_target
-	config.Target.GPHome = targetGPHome
+	config.Target.GPHome = opts.TargetGPHome
 	config.Target.Version = targetVersion
 
-	config.Intermediate, err = GenerateIntermediateCluster(config.Source, ports, config.UpgradeID, config.Target.Version, config.Target.GPHome)
+	config.Intermediate, err = GenerateIntermediateCluster(config.Source, opts.Ports, config.UpgradeID, config.Target.Version, config.Target.GPHome)
 	if err != nil {
 		return Config{}, err
 	}
@@ -7,16 +7,19 @@ import (
 	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/blang/semver/v4"
 
 	"github.com/greenplum-db/gpupgrade/cli/commands"
 	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/config/listenaddress"
 	"github.com/greenplum-db/gpupgrade/greenplum"
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/testutils"
 	"github.com/greenplum-db/gpupgrade/testutils/exectest"
+	"github.com/greenplum-db/gpupgrade/utils/mtls"
 )
 
 func TestConfig(t *testing.T) {
@@ -76,15 +79,51 @@ func TestCreate(t *testing.T) {
 
 	const hubPort = 9999
 	const agentPort = 8888
+	const hubListenAddress = "localhost"
+	const agentListenAddress = "10.0.0.1"
+	const metricsPort = 9187
 	const mode = idl.Mode_link
 	const useHbaHostnames = false
 	const parentBackupDirs = ""
 	const pgUpgradeJobs = 1
+	const maxParallelSegments = 2
+	const maxParallelHosts = 3
+	agentRPCTimeouts := map[string]time.Duration{"DeleteDataDirectories": 30 * time.Minute}
+	agentLauncher := "systemd"
+	tlsConfig := mtls.Config{
+		CACertificate:    "/certs/ca.crt",
+		CLICertificate:   "/certs/cli.crt",
+		CLIKey:           "/certs/cli.key",
+		HubCertificate:   "/certs/hub.crt",
+		HubKey:           "/certs/hub.key",
+		AgentCertificate: "/certs/agent.crt",
+		AgentKey:         "/certs/agent.key",
+	}
 	ports, err := commands.ParsePorts("50432-65535")
 	if err != nil {
 		t.Fatal(err)
 	}
 
+	opts := config.CreateOptions{
+		HubPort:             hubPort,
+		AgentPort:           agentPort,
+		HubListenAddress:    hubListenAddress,
+		AgentListenAddress:  agentListenAddress,
+		MetricsPort:         metricsPort,
+		SourceGPHome:        source.GPHome,
+		TargetGPHome:        targetGPHome,
+		Mode:                mode,
+		UseHbaHostnames:     useHbaHostnames,
+		Ports:               ports,
+		PgUpgradeJobs:       pgUpgradeJobs,
+		MaxParallelSegments: maxParallelSegments,
+		MaxParallelHosts:    maxParallelHosts,
+		AgentRPCTimeouts:    agentRPCTimeouts,
+		AgentLauncher:       agentLauncher,
+		ParentBackupDirs:    parentBackupDirs,
+		TLS:                 tlsConfig,
+	}
+
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("couldn't create sqlmock: %v", err)
@@ -100,7 +139,7 @@ func TestCreate(t *testing.T) {
 			expectPgStatReplicationToReturn(mock)
 			expectPgTablespace(mock)
 
-			conf, err := config.Create(db, hubPort, agentPort, source.GPHome, targetGPHome, mode, useHbaHostnames, ports, pgUpgradeJobs, parentBackupDirs)
+			conf, err := config.Create(db, opts)
 			if err != nil {
 				t.Fatalf("unexpected error %#v", err)
 			}
@@ -126,7 +165,7 @@ func TestCreate(t *testing.T) {
 			expectPgStatReplicationToReturn(mock)
 			expectPgTablespace(mock)
 
-			conf, err := config.Create(db, hubPort, agentPort, source.GPHome, targetGPHome, mode, useHbaHostnames, ports, pgUpgradeJobs, parentBackupDirs)
+			conf, err := config.Create(db, opts)
 			if err != nil {
 				t.Fatalf("unexpected error %#v", err)
 			}
@@ -151,7 +190,7 @@ func TestCreate(t *testing.T) {
 			expectPgStatReplicationToReturn(mock)
 			expectPgTablespace(mock)
 
-			conf, err := config.Create(db, hubPort, agentPort, source.GPHome, targetGPHome, mode, useHbaHostnames, ports, pgUpgradeJobs, parentBackupDirs)
+			conf, err := config.Create(db, opts)
 			if err != nil {
 				t.Fatalf("unexpected error %#v", err)
 			}
@@ -168,7 +207,7 @@ func TestCreate(t *testing.T) {
 		expectPgStatReplicationToReturn(mock)
 		expectPgTablespace(mock)
 
-		conf, err := config.Create(db, hubPort, agentPort, source.GPHome, targetGPHome, mode, useHbaHostnames, ports, pgUpgradeJobs, parentBackupDirs)
+		conf, err := config.Create(db, opts)
 		if err != nil {
 			t.Fatalf("unexpected error %#v", err)
 		}
@@ -181,6 +220,23 @@ func TestCreate(t *testing.T) {
 			t.Errorf("got %d want %d", conf.AgentPort, agentPort)
 		}
 
+		if conf.HubListenAddress != hubListenAddress {
+			t.Errorf("got %q want %q", conf.HubListenAddress, hubListenAddress)
+		}
+
+		expectedAddresses := listenaddress.AgentListenAddresses{"standby": agentListenAddress, "sdw1": agentListenAddress, "sdw2": agentListenAddress}
+		if !reflect.DeepEqual(conf.AgentListenAddresses, expectedAddresses) {
+			t.Errorf("got %q want %q", conf.AgentListenAddresses, expectedAddresses)
+		}
+
+		if conf.MetricsPort != metricsPort {
+			t.Errorf("got %d want %d", conf.MetricsPort, metricsPort)
+		}
+
+		if conf.TLS != tlsConfig {
+			t.Errorf("got %+v want %+v", conf.TLS, tlsConfig)
+		}
+
 		if conf.Source.GPHome != source.GPHome {
 			t.Errorf("got %s want %s", conf.Source.GPHome, source.GPHome)
 		}
@@ -214,6 +270,22 @@ func TestCreate(t *testing.T) {
 			t.Errorf("got %d want %d", conf.PgUpgradeJobs, pgUpgradeJobs)
 		}
 
+		if conf.MaxParallelSegments != maxParallelSegments {
+			t.Errorf("got %d want %d", conf.MaxParallelSegments, maxParallelSegments)
+		}
+
+		if conf.MaxParallelHosts != maxParallelHosts {
+			t.Errorf("got %d want %d", conf.MaxParallelHosts, maxParallelHosts)
+		}
+
+		if !reflect.DeepEqual(conf.AgentRPCTimeouts, agentRPCTimeouts) {
+			t.Errorf("got %v want %v", conf.AgentRPCTimeouts, agentRPCTimeouts)
+		}
+
+		if conf.AgentLauncher != agentLauncher {
+			t.Errorf("got %q want %q", conf.AgentLauncher, agentLauncher)
+		}
+
 		if conf.UpgradeID == "" {
 			t.Errorf("expected non-empty UpgradeID")
 		}
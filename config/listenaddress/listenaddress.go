@@ -0,0 +1,124 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package listenaddress
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+)
+
+// AgentListenAddresses maps each agent host to the address its agent listens
+// on. Agents on hosts without an entry listen on all interfaces.
+type AgentListenAddresses map[string]string
+
+// DialAddress returns the host:port to dial the agent on host. Agents bound to
+// a listen address are only reachable on that address, so it is used in place
+// of the hostname when one is configured. Wildcard addresses such as 0.0.0.0
+// are reachable through the hostname.
+func (a AgentListenAddresses) DialAddress(host string, port int) string {
+	if address, ok := a[host]; ok && !isUnspecified(address) {
+		host = address
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+func isUnspecified(address string) bool {
+	ip := net.ParseIP(address)
+	return ip != nil && ip.IsUnspecified()
+}
+
+// ParseAgentListenAddresses parses either a single address or multiple
+// host:address pairs. To specify a single address across all agent hosts set a
+// single address such as 10.0.0.1, which requires a name or address valid on
+// each host. To specify different addresses for each host use the form
+// "host1:addr1,host2:addr2". Defaults to listening on all interfaces.
+func ParseAgentListenAddresses(input string, cluster greenplum.Cluster) (AgentListenAddresses, error) {
+	input = strings.TrimSpace(input)
+	addresses := make(AgentListenAddresses)
+
+	if input == "" {
+		return addresses, nil
+	}
+
+	hosts := agentHosts(cluster)
+
+	// parse a single address across all hosts, which may be an IPv6 address
+	// containing colons
+	if !strings.Contains(input, ",") && (!strings.Contains(input, ":") || net.ParseIP(input) != nil) {
+		for _, host := range hosts {
+			addresses[host] = input
+		}
+
+		return addresses, nil
+	}
+
+	// parse an address for each host
+	for _, pair := range strings.Split(input, ",") {
+		host, address, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		host = strings.TrimSpace(host)
+		address = strings.TrimSpace(address)
+		if !ok || host == "" || address == "" {
+			return nil, fmt.Errorf("expected host:address pairs but got %q when parsing agent_listen_address", pair)
+		}
+
+		addresses[host] = address
+	}
+
+	// ensure all hosts have been specified when multiple addresses are specified
+	var missingHosts []string
+	for _, host := range hosts {
+		if _, ok := addresses[host]; !ok {
+			missingHosts = append(missingHosts, host)
+		}
+	}
+
+	if len(missingHosts) > 0 {
+		return nil, newMissingHostInAgentListenAddressError(input, missingHosts)
+	}
+
+	return addresses, nil
+}
+
+// agentHosts returns the sorted hosts that run an agent, which are all hosts
+// with a segment other than the coordinator.
+func agentHosts(cluster greenplum.Cluster) []string {
+	unique := make(map[string]bool)
+	for _, seg := range cluster.SelectSegments(func(seg *greenplum.SegConfig) bool { return !seg.IsCoordinator() }) {
+		unique[seg.Hostname] = true
+	}
+
+	var hosts []string
+	for host := range unique {
+		hosts = append(hosts, host)
+	}
+
+	sort.Strings(hosts)
+	return hosts
+}
+
+var ErrMissingHostInAgentListenAddress = errors.New("missing host in agent listen address")
+
+type MissingHostInAgentListenAddressError struct {
+	Input        string
+	MissingHosts []string
+}
+
+func newMissingHostInAgentListenAddressError(input string, missingHosts []string) *MissingHostInAgentListenAddressError {
+	return &MissingHostInAgentListenAddressError{Input: input, MissingHosts: missingHosts}
+}
+
+func (m *MissingHostInAgentListenAddressError) Error() string {
+	return fmt.Sprintf("expected host %q to be specified in %q when parsing agent_listen_address", m.MissingHosts, m.Input)
+}
+
+func (m *MissingHostInAgentListenAddressError) Is(err error) bool {
+	return err == ErrMissingHostInAgentListenAddress
+}
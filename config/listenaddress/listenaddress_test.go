@@ -0,0 +1,142 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package listenaddress_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/config/listenaddress"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+)
+
+func TestParseAgentListenAddresses(t *testing.T) {
+	source, err := greenplum.NewCluster(greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "cdw", DataDir: "/data/coordinator/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "scdw", DataDir: "/data/standby/seg-1", Port: 16432, Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data1/primaries/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data2/mirrors/seg1", Port: 25434, Role: greenplum.MirrorRole},
+		{DbID: 5, ContentID: 1, Hostname: "sdw2", DataDir: "/data2/primaries/seg2", Port: 25435, Role: greenplum.PrimaryRole},
+		{DbID: 6, ContentID: 1, Hostname: "sdw1", DataDir: "/data1/mirrors/seg2", Port: 25436, Role: greenplum.MirrorRole},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	cases := []struct {
+		name     string
+		input    string
+		expected listenaddress.AgentListenAddresses
+	}{
+		{
+			name:     "defaults to listening on all interfaces",
+			input:    "",
+			expected: listenaddress.AgentListenAddresses{},
+		},
+		{
+			name:     "parses a single address",
+			input:    "localhost",
+			expected: listenaddress.AgentListenAddresses{"scdw": "localhost", "sdw1": "localhost", "sdw2": "localhost"},
+		},
+		{
+			name:     "parses a single IPv6 address",
+			input:    "::1",
+			expected: listenaddress.AgentListenAddresses{"scdw": "::1", "sdw1": "::1", "sdw2": "::1"},
+		},
+		{
+			name:     "parses multiple hosts and addresses with spaces",
+			input:    " scdw:10.0.0.1,  sdw1 : 10.0.0.2 , sdw2:fe80::2 ",
+			expected: listenaddress.AgentListenAddresses{"scdw": "10.0.0.1", "sdw1": "10.0.0.2", "sdw2": "fe80::2"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			addresses, err := listenaddress.ParseAgentListenAddresses(c.input, source)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if !reflect.DeepEqual(addresses, c.expected) {
+				t.Errorf("got %v want %v", addresses, c.expected)
+			}
+		})
+	}
+
+	t.Run("errors when failing to specify agent hosts", func(t *testing.T) {
+		input := "sdw1:10.0.0.2"
+		_, err := listenaddress.ParseAgentListenAddresses(input, source)
+
+		var missingHostErr *listenaddress.MissingHostInAgentListenAddressError
+		if !errors.As(err, &missingHostErr) {
+			t.Fatalf("got %T, want %T", err, missingHostErr)
+		}
+
+		if !errors.Is(err, listenaddress.ErrMissingHostInAgentListenAddress) {
+			t.Errorf("expected error %#v to be %#v", err, listenaddress.ErrMissingHostInAgentListenAddress)
+		}
+
+		expected := []string{"scdw", "sdw2"}
+		if missingHostErr.Input != input || !reflect.DeepEqual(missingHostErr.MissingHosts, expected) {
+			t.Errorf("got input %q missing hosts %q want %q and %q", missingHostErr.Input, missingHostErr.MissingHosts, input, expected)
+		}
+	})
+
+	t.Run("errors when a pair is missing the address", func(t *testing.T) {
+		_, err := listenaddress.ParseAgentListenAddresses("scdw:10.0.0.1,sdw1,sdw2:10.0.0.3", source)
+		if err == nil {
+			t.Errorf("expected an error")
+		}
+	})
+}
+
+func TestDialAddress(t *testing.T) {
+	addresses := listenaddress.AgentListenAddresses{"sdw1": "10.0.0.1", "sdw2": "fe80::2", "sdw3": "0.0.0.0"}
+
+	cases := []struct {
+		name     string
+		host     string
+		expected string
+	}{
+		{
+			name:     "dials the listen address of the host",
+			host:     "sdw1",
+			expected: "10.0.0.1:6416",
+		},
+		{
+			name:     "dials an IPv6 listen address",
+			host:     "sdw2",
+			expected: "[fe80::2]:6416",
+		},
+		{
+			name:     "dials the hostname when listening on all interfaces",
+			host:     "sdw3",
+			expected: "sdw3:6416",
+		},
+		{
+			name:     "dials the hostname when no listen address is configured",
+			host:     "sdw4",
+			expected: "sdw4:6416",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			address := addresses.DialAddress(c.host, 6416)
+			if address != c.expected {
+				t.Errorf("got %q want %q", address, c.expected)
+			}
+		})
+	}
+
+	t.Run("dials the hostname when there are no listen addresses", func(t *testing.T) {
+		var addresses listenaddress.AgentListenAddresses
+
+		address := addresses.DialAddress("sdw1", 6416)
+		if address != "sdw1:6416" {
+			t.Errorf("got %q want %q", address, "sdw1:6416")
+		}
+	})
+}
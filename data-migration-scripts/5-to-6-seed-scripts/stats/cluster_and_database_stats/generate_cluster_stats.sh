@@ -7,5 +7,6 @@ cat << 'EOF'
 -- Cluster Statistics
 SELECT hostname, COUNT(dbid) AS Primaries FROM pg_catalog.gp_segment_configuration WHERE role='p' GROUP BY hostname;
 SELECT hostname, COUNT(dbid) AS Mirrors FROM pg_catalog.gp_segment_configuration WHERE role='m' GROUP BY hostname;
+SELECT hostname, COUNT(dbid) AS Standby FROM pg_catalog.gp_segment_configuration WHERE role='m' AND content=-1 GROUP BY hostname;
 
 EOF
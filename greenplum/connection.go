@@ -6,6 +6,7 @@ package greenplum
 import (
 	"fmt"
 	"log"
+	"net/url"
 
 	_ "github.com/jackc/pgx/v4"        // used indirectly as the database driver "pgx"
 	_ "github.com/jackc/pgx/v4/stdlib" // used indirectly as the database driver "pgx"
@@ -19,7 +20,12 @@ func (c *Cluster) Connection(options ...Option) string {
 		port = opts.port
 	}
 
-	connURI := fmt.Sprintf("postgresql://localhost:%d/template1?search_path=", port)
+	database := "template1"
+	if opts.database != "" {
+		database = opts.database
+	}
+
+	connURI := fmt.Sprintf("postgresql://localhost:%d/%s?search_path=", port, url.PathEscape(database))
 
 	if opts.utilityMode {
 		mode := "&gp_role=utility"
@@ -47,6 +53,13 @@ func Port(port int) Option {
 	}
 }
 
+// Database defaults to template1
+func Database(name string) Option {
+	return func(options *optionList) {
+		options.database = name
+	}
+}
+
 func UtilityMode() Option {
 	return func(options *optionList) {
 		options.utilityMode = true
@@ -61,6 +74,7 @@ func AllowSystemTableMods() Option {
 
 type optionList struct {
 	port                 int
+	database             string
 	utilityMode          bool
 	allowSystemTableMods bool
 }
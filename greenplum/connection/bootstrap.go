@@ -14,6 +14,16 @@ import (
 // function on the cluster object. However, Bootstrap is useful for when a
 // cluster object does not exist and a database connection is needed.
 func Bootstrap(destination idl.ClusterDestination, gphome string, port int) (*sql.DB, error) {
+	return bootstrap(destination, gphome, greenplum.Port(port))
+}
+
+// BootstrapDatabase is like Bootstrap but connects to the database rather
+// than template1.
+func BootstrapDatabase(destination idl.ClusterDestination, gphome string, port int, database string) (*sql.DB, error) {
+	return bootstrap(destination, gphome, greenplum.Port(port), greenplum.Database(database))
+}
+
+func bootstrap(destination idl.ClusterDestination, gphome string, options ...greenplum.Option) (*sql.DB, error) {
 	cluster, err := greenplum.NewCluster([]greenplum.SegConfig{})
 	if err != nil {
 		return nil, err
@@ -26,7 +36,7 @@ func Bootstrap(destination idl.ClusterDestination, gphome string, port int) (*sq
 		return nil, err
 	}
 
-	conn := cluster.Connection([]greenplum.Option{greenplum.Port(port)}...)
+	conn := cluster.Connection(options...)
 	db, err := sql.Open("pgx", conn)
 	if err != nil {
 		return nil, err
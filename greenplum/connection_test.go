@@ -35,6 +35,14 @@ func TestConnection(t *testing.T) {
 			},
 			"postgresql://localhost:12345/template1?search_path=",
 		},
+		{
+			"uses specified database",
+			semver.MustParse("6.0.0"),
+			[]greenplum.Option{
+				greenplum.Database("my db"),
+			},
+			"postgresql://localhost:15432/my%20db?search_path=",
+		},
 		{
 			"uses correct utility mode parameter when connecting to a 5X cluster",
 			semver.MustParse("5.0.0"),
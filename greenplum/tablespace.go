@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"strconv"
 
+	"github.com/blang/semver/v4"
 	"golang.org/x/xerrors"
 
 	"github.com/greenplum-db/gpupgrade/idl"
@@ -76,7 +77,11 @@ func GetCoordinatorTablespaceLocation(basePath string, oid int) string {
 }
 
 func GetTablespaceTuples(db *sql.DB) (TablespaceTuples, error) {
-	rows, err := db.Query(tablespacesQuery)
+	return queryTablespaceTuples(db, tablespacesQuery)
+}
+
+func queryTablespaceTuples(db *sql.DB, query string) (TablespaceTuples, error) {
+	rows, err := db.Query(query)
 	if err != nil {
 		return nil, err
 	}
@@ -144,6 +149,38 @@ func boolToStrInt(b bool) string {
 	return "0"
 }
 
+// tablespaceLocationsQuery returns the user defined tablespaces of each
+// segment since Greenplum 6 and later no longer have filespaces. Mirrors share
+// the location of their primary.
+const tablespaceLocationsQuery = `
+	SELECT
+		c.dbid,
+		t.oid,
+		t.spcname,
+		l.tblspc_loc,
+		1 as userdefined
+	FROM pg_tablespace t
+		CROSS JOIN LATERAL gp_tablespace_location(t.oid) l
+		JOIN gp_segment_configuration c ON c.content = l.gp_segment_id
+	WHERE t.spcname NOT IN ('pg_default', 'pg_global')
+	ORDER BY c.dbid, t.oid;`
+
+// QueryTablespaces returns the tablespaces of the cluster without writing the
+// tablespace mapping file.
+func QueryTablespaces(db *sql.DB, version semver.Version) (Tablespaces, error) {
+	query := tablespacesQuery
+	if version.Major > 5 {
+		query = tablespaceLocationsQuery
+	}
+
+	tuples, err := queryTablespaceTuples(db, query)
+	if err != nil {
+		return nil, xerrors.Errorf("querying tablespaces: %w", err)
+	}
+
+	return NewTablespaces(tuples), nil
+}
+
 // main function which does the following:
 // 1. query the database to get tablespace information
 // 2. write the tablespace information to a file
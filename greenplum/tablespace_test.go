@@ -5,6 +5,7 @@ package greenplum_test
 
 import (
 	"bytes"
+	"fmt"
 	"os"
 	"reflect"
 	"strings"
@@ -103,6 +104,63 @@ func TestGetTablespaces(t *testing.T) {
 	}
 }
 
+func TestQueryTablespaces(t *testing.T) {
+	cases := []struct {
+		version semver.Version
+		query   string
+	}{
+		{version: semver.MustParse("5.0.0"), query: "fselocation"},
+		{version: semver.MustParse("6.0.0"), query: "gp_tablespace_location"},
+	}
+
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("queries the tablespaces of a %s cluster", c.version), func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("couldn't create sqlmock: %v", err)
+			}
+			defer testutils.FinishMock(mock, t)
+			defer db.Close()
+
+			rows := sqlmock.NewRows([]string{"dbid", "oid", "name", "location", "userdefined"})
+			rows.AddRow(1, 16384, "my_tablespace", "/tmp/coordinator_tablespace", 1)
+			rows.AddRow(2, 16384, "my_tablespace", "/tmp/primary_tablespace", 1)
+
+			mock.ExpectQuery(c.query).WillReturnRows(rows)
+
+			actual, err := greenplum.QueryTablespaces(db, c.version)
+			if err != nil {
+				t.Errorf("returned error %+v", err)
+			}
+
+			expected := greenplum.Tablespaces{
+				1: {16384: {Location: "/tmp/coordinator_tablespace", UserDefined: true}},
+				2: {16384: {Location: "/tmp/primary_tablespace", UserDefined: true}},
+			}
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("got %+v, want %+v", actual, expected)
+			}
+		})
+	}
+
+	t.Run("errors when the query fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("couldn't create sqlmock: %v", err)
+		}
+		defer testutils.FinishMock(mock, t)
+		defer db.Close()
+
+		expected := errors.New("permission denied")
+		mock.ExpectQuery("SELECT").WillReturnError(expected)
+
+		_, err = greenplum.QueryTablespaces(db, semver.MustParse("6.0.0"))
+		if !errors.Is(err, expected) {
+			t.Errorf("returned %#v want %#v", err, expected)
+		}
+	})
+}
+
 func TestNewTablespaces(t *testing.T) {
 	cases := []struct {
 		name     string
@@ -9,23 +9,14 @@ import (
 	"time"
 
 	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/utils"
 )
 
 func WaitForSegments(db *sql.DB, timeout time.Duration, cluster *Cluster) error {
 	startTime := time.Now()
 	for {
-		if cluster.Version.Major > 5 {
-			rows, err := db.Query("SELECT gp_request_fts_probe_scan();")
-			if err != nil {
-				return xerrors.Errorf("requesting gp_request_fts_probe_scan: %w", err)
-			}
-
-			if err := rows.Close(); err != nil {
-				return xerrors.Errorf("closing rows for gp_request_fts_probe_scan: %w", err)
-			}
-		}
-
-		ready, err := areSegmentsReady(db, cluster)
+		ready, err := probeSegments(db, cluster)
 		if err != nil {
 			return err
 		}
@@ -42,6 +33,39 @@ func WaitForSegments(db *sql.DB, timeout time.Duration, cluster *Cluster) error
 	}
 }
 
+// CheckSegments errors unless all segments are up, in their preferred roles,
+// and synchronized. Unlike WaitForSegments it checks only once.
+func CheckSegments(db *sql.DB, cluster *Cluster) error {
+	ready, err := probeSegments(db, cluster)
+	if err != nil {
+		return err
+	}
+
+	if !ready {
+		return utils.NewNextActionErr(
+			xerrors.New("not all segments are up, in their preferred roles, and synchronized"),
+			`Run "gpstate -e" for details. Recover any down segments with "gprecoverseg" and rebalance
+them with "gprecoverseg -r".`)
+	}
+
+	return nil
+}
+
+func probeSegments(db *sql.DB, cluster *Cluster) (bool, error) {
+	if cluster.Version.Major > 5 {
+		rows, err := db.Query("SELECT gp_request_fts_probe_scan();")
+		if err != nil {
+			return false, xerrors.Errorf("requesting gp_request_fts_probe_scan: %w", err)
+		}
+
+		if err := rows.Close(); err != nil {
+			return false, xerrors.Errorf("closing rows for gp_request_fts_probe_scan: %w", err)
+		}
+	}
+
+	return areSegmentsReady(db, cluster)
+}
+
 func areSegmentsReady(db *sql.DB, cluster *Cluster) (bool, error) {
 	var segments int
 
@@ -4,6 +4,7 @@
 package greenplum_test
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 
 	"github.com/greenplum-db/gpupgrade/greenplum"
 	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
 )
 
 func TestWaitForSegments(t *testing.T) {
@@ -162,6 +164,59 @@ func TestWaitForSegments(t *testing.T) {
 	})
 }
 
+func TestCheckSegments(t *testing.T) {
+	target := MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby", Port: 16432, Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+	})
+	target.Version = semver.MustParse("6.0.0")
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("couldn't create sqlmock: %v", err)
+	}
+	defer testutils.FinishMock(mock, t)
+
+	t.Run("succeeds when all segments are ready", func(t *testing.T) {
+		expectFtsProbe(mock)
+		expectGpSegmentConfigurationToReturn(mock, 2)
+		expectPgStatReplicationToReturn(mock, 1, target.Version)
+
+		err := greenplum.CheckSegments(db, target)
+		if err != nil {
+			t.Errorf("unexpected error: %#v", err)
+		}
+	})
+
+	t.Run("errors without waiting when segments are not ready", func(t *testing.T) {
+		expectFtsProbe(mock)
+		expectGpSegmentConfigurationToReturn(mock, 1)
+
+		err := greenplum.CheckSegments(db, target)
+		var nextActionsErr utils.NextActionErr
+		if !errors.As(err, &nextActionsErr) {
+			t.Fatalf("got type %T want %T", err, nextActionsErr)
+		}
+
+		expected := "not all segments are up, in their preferred roles, and synchronized"
+		if err.Error() != expected {
+			t.Errorf("got error %q want %q", err, expected)
+		}
+	})
+
+	t.Run("errors when querying fails", func(t *testing.T) {
+		expected := errors.New("permission denied")
+		mock.ExpectQuery(`SELECT gp_request_fts_probe_scan\(\);`).WillReturnError(expected)
+
+		err := greenplum.CheckSegments(db, target)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+}
+
 func expectFtsProbe(mock sqlmock.Sqlmock) {
 	mock.ExpectQuery(`SELECT gp_request_fts_probe_scan\(\);`).
 		WillReturnRows(sqlmock.NewRows([]string{"gp_request_fts_probe_scan"}).AddRow("t"))
@@ -12,7 +12,7 @@ import (
 	"github.com/greenplum-db/gpupgrade/utils"
 )
 
-func AddReplicationEntriesOnPrimaries(agentConns []*idl.Connection, intermediate *greenplum.Cluster, useHbaHostnames bool) error {
+func AddReplicationEntriesOnPrimaries(ctx context.Context, agentConns []*idl.Connection, intermediate *greenplum.Cluster, useHbaHostnames bool) error {
 	user, err := utils.System.Current()
 	if err != nil {
 		return err
@@ -47,11 +47,11 @@ func AddReplicationEntriesOnPrimaries(agentConns []*idl.Connection, intermediate
 		}
 
 		req := &idl.AddReplicationEntriesRequest{Entries: entries}
-		_, err := conn.AgentClient.AddReplicationEntries(context.Background(), req)
+		_, err := conn.AgentClient.AddReplicationEntries(ctx, req)
 		return err
 	}
 
-	return ExecuteRPC(agentConns, request)
+	return ExecuteRPC(ctx, agentConns, request)
 }
 
 // getIpAddresses returns a list of ip addresses with CIDR notation for use in
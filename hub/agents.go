@@ -0,0 +1,122 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/upgrade"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+func (s *Server) GetAgents(ctx context.Context, req *idl.GetAgentsRequest) (*idl.GetAgentsReply, error) {
+	agentConns, err := s.AgentConns()
+	if err != nil {
+		return &idl.GetAgentsReply{}, xerrors.Errorf("get agents: %w", err)
+	}
+
+	agents, err := GetAgentsInfo(ctx, agentConns, s.Source)
+	if err != nil {
+		return &idl.GetAgentsReply{}, xerrors.Errorf("get agents: %w", err)
+	}
+
+	return &idl.GetAgentsReply{Agents: agents}, nil
+}
+
+// GetAgentsInfo returns the information reported by each agent sorted by
+// host, including the free disk space under the data directories of the
+// cluster on that host. Agents that fail to respond are returned with their
+// error rather than failing the request.
+func GetAgentsInfo(ctx context.Context, agentConns []*idl.Connection, cluster *greenplum.Cluster) ([]*idl.AgentStatus, error) {
+	var mutex sync.Mutex
+	var agents []*idl.AgentStatus
+
+	request := func(conn *idl.Connection) error {
+		var dataDirs []string
+		if cluster != nil {
+			segments := cluster.SelectSegments(func(seg *greenplum.SegConfig) bool {
+				return seg.IsOnHost(conn.Hostname)
+			})
+
+			for _, seg := range segments {
+				dataDirs = append(dataDirs, seg.DataDir)
+			}
+			sort.Strings(dataDirs)
+		}
+
+		status := &idl.AgentStatus{Host: conn.Hostname}
+		reply, err := conn.AgentClient.GetInfo(ctx, &idl.GetInfoRequest{DataDirs: dataDirs})
+		if err != nil {
+			status.Error = err.Error()
+		} else {
+			status.Info = reply.GetInfo()
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		agents = append(agents, status)
+
+		return nil
+	}
+
+	err := ExecuteRPC(ctx, agentConns, request)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(agents, func(i, j int) bool {
+		return agents[i].GetHost() < agents[j].GetHost()
+	})
+
+	return agents, nil
+}
+
+// EnsureAgentVersionsMatch errors when the gpupgrade version reported by any
+// agent differs from that of the hub.
+func EnsureAgentVersionsMatch(ctx context.Context, agentConns []*idl.Connection) error {
+	hubVersion, err := upgrade.LocalVersion()
+	if err != nil {
+		return xerrors.Errorf("hub version: %w", err)
+	}
+	hubVersion = strings.TrimSpace(hubVersion)
+
+	agents, err := GetAgentsInfo(ctx, agentConns, nil)
+	if err != nil {
+		return err
+	}
+
+	mismatched := make(upgrade.MismatchedVersions)
+	for _, agent := range agents {
+		if agent.GetError() != "" {
+			err = errorlist.Append(err, xerrors.Errorf("agent version on host %s: %s", agent.GetHost(), agent.GetError()))
+			continue
+		}
+
+		version := agent.GetInfo().GetVersion()
+		if version != hubVersion {
+			mismatched[version] = append(mismatched[version], agent.GetHost())
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(mismatched) == 0 {
+		return nil
+	}
+
+	return xerrors.Errorf(`gpupgrade version mismatch between gpupgrade hub and agent hosts.
+    Hub version: %q
+
+    Mismatched Agents:
+    %s`, hubVersion, mismatched)
+}
@@ -0,0 +1,211 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/testutils/exectest"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+	"github.com/greenplum-db/gpupgrade/upgrade"
+)
+
+func TestGetAgentsInfo(t *testing.T) {
+	testlog.SetupTestLogger()
+
+	cluster := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 2, Hostname: "sdw1", DataDir: "/data/dbfast2/seg1", Role: greenplum.PrimaryRole},
+		{ContentID: 1, DbID: 3, Hostname: "sdw1", DataDir: "/data/dbfast1/seg2", Role: greenplum.PrimaryRole},
+		{ContentID: 2, DbID: 4, Hostname: "sdw2", DataDir: "/data/dbfast1/seg3", Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 5, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Role: greenplum.MirrorRole},
+	})
+
+	t.Run("requests the information of each agent sorted by host", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1Info := &idl.AgentInfo{Version: "1.0.0", Hostname: "sdw1"}
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().GetInfo(
+			gomock.Any(),
+			&idl.GetInfoRequest{DataDirs: []string{"/data/dbfast1/seg2", "/data/dbfast2/seg1"}},
+		).Return(&idl.GetInfoReply{Info: sdw1Info}, nil)
+
+		sdw2Info := &idl.AgentInfo{Version: "1.0.0", Hostname: "sdw2"}
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().GetInfo(
+			gomock.Any(),
+			&idl.GetInfoRequest{DataDirs: []string{"/data/dbfast1/seg3", "/data/dbfast_mirror1/seg1"}},
+		).Return(&idl.GetInfoReply{Info: sdw2Info}, nil)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw2, Hostname: "sdw2"},
+			{AgentClient: sdw1, Hostname: "sdw1"},
+		}
+
+		agents, err := hub.GetAgentsInfo(context.Background(), agentConns, cluster)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := []*idl.AgentStatus{
+			{Host: "sdw1", Info: sdw1Info},
+			{Host: "sdw2", Info: sdw2Info},
+		}
+		if !reflect.DeepEqual(agents, expected) {
+			t.Errorf("got agents %v want %v", agents, expected)
+		}
+	})
+
+	t.Run("does not request any data directories without a cluster", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().GetInfo(
+			gomock.Any(),
+			&idl.GetInfoRequest{},
+		).Return(&idl.GetInfoReply{Info: &idl.AgentInfo{}}, nil)
+
+		agentConns := []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}
+
+		_, err := hub.GetAgentsInfo(context.Background(), agentConns, nil)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+
+	t.Run("returns the error of agents that fail to respond", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1Info := &idl.AgentInfo{Version: "1.0.0", Hostname: "sdw1"}
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().GetInfo(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(&idl.GetInfoReply{Info: sdw1Info}, nil)
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().GetInfo(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(nil, errors.New("connection refused"))
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		agents, err := hub.GetAgentsInfo(context.Background(), agentConns, cluster)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := []*idl.AgentStatus{
+			{Host: "sdw1", Info: sdw1Info},
+			{Host: "sdw2", Error: "connection refused"},
+		}
+		if !reflect.DeepEqual(agents, expected) {
+			t.Errorf("got agents %v want %v", agents, expected)
+		}
+	})
+}
+
+func TestEnsureAgentVersionsMatch(t *testing.T) {
+	testlog.SetupTestLogger()
+
+	upgrade.SetLocalVersionCommand(exectest.NewCommand(hub.GpupgradeVersionMain))
+	defer upgrade.ResetLocalVersionCommand()
+
+	agentWithVersion := func(ctrl *gomock.Controller, version string) *mock_idl.MockAgentClient {
+		client := mock_idl.NewMockAgentClient(ctrl)
+		client.EXPECT().GetInfo(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(&idl.GetInfoReply{Info: &idl.AgentInfo{Version: version}}, nil)
+
+		return client
+	}
+
+	t.Run("succeeds when the agent versions match the hub", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		agentConns := []*idl.Connection{
+			{AgentClient: agentWithVersion(ctrl, hub.GpupgradeVersionMainOutput), Hostname: "sdw1"},
+			{AgentClient: agentWithVersion(ctrl, hub.GpupgradeVersionMainOutput), Hostname: "sdw2"},
+		}
+
+		err := hub.EnsureAgentVersionsMatch(context.Background(), agentConns)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+
+	t.Run("errors listing the agents whose version does not match the hub", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		agentConns := []*idl.Connection{
+			{AgentClient: agentWithVersion(ctrl, hub.GpupgradeVersionMainOutput), Hostname: "sdw1"},
+			{AgentClient: agentWithVersion(ctrl, "Version: 0.9.0"), Hostname: "sdw2"},
+		}
+
+		err := hub.EnsureAgentVersionsMatch(context.Background(), agentConns)
+
+		expected := upgrade.MismatchedVersions{"Version: 0.9.0": []string{"sdw2"}}.String()
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("got error %v want it to contain %q", err, expected)
+		}
+
+		if strings.Contains(err.Error(), "sdw1") {
+			t.Errorf("got error %q want it to not contain the matching host sdw1", err)
+		}
+	})
+
+	t.Run("errors when an agent fails to report its version", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().GetInfo(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(nil, errors.New("connection refused"))
+
+		agentConns := []*idl.Connection{
+			{AgentClient: agentWithVersion(ctrl, hub.GpupgradeVersionMainOutput), Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		err := hub.EnsureAgentVersionsMatch(context.Background(), agentConns)
+
+		expected := "agent version on host sdw2: connection refused"
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %v want %q", err, expected)
+		}
+	})
+
+	t.Run("errors when getting the hub version fails", func(t *testing.T) {
+		upgrade.SetLocalVersionCommand(exectest.NewCommand(hub.Failure))
+		defer upgrade.SetLocalVersionCommand(exectest.NewCommand(hub.GpupgradeVersionMain))
+
+		err := hub.EnsureAgentVersionsMatch(context.Background(), nil)
+		if err == nil || !strings.HasPrefix(err.Error(), "hub version") {
+			t.Errorf("got error %v want a hub version error", err)
+		}
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"sort"
 	"sync"
 
+	"github.com/greenplum-db/gpupgrade/config/backupdir"
 	"github.com/greenplum-db/gpupgrade/greenplum"
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/step"
@@ -16,8 +17,10 @@ import (
 )
 
 var checkDiskUsage = disk.CheckUsage
+var checkDiskRequirements = disk.CheckRequirements
+var dirSize = disk.DirSize
 
-func CheckDiskSpace(streams step.OutStreams, agentConns []*idl.Connection, diskFreeRatio float64, source *greenplum.Cluster, sourceTablespaces greenplum.Tablespaces) error {
+func CheckDiskSpace(ctx context.Context, streams step.OutStreams, agentConns []*idl.Connection, diskFreeRatio float64, source *greenplum.Cluster, sourceTablespaces greenplum.Tablespaces) error {
 	var wg sync.WaitGroup
 	errs := make(chan error, len(agentConns)+1)
 	usagesChan := make(chan disk.FileSystemDiskUsage, len(agentConns)+1)
@@ -35,7 +38,7 @@ func CheckDiskSpace(streams step.OutStreams, agentConns []*idl.Connection, diskF
 		usagesChan <- usage
 	}()
 
-	checkDiskSpaceOnStandbyAndSegments(agentConns, errs, usagesChan, diskFreeRatio, source, sourceTablespaces)
+	checkDiskSpaceOnStandbyAndSegments(ctx, agentConns, errs, usagesChan, diskFreeRatio, source, sourceTablespaces)
 
 	wg.Wait()
 	close(errs)
@@ -51,11 +54,126 @@ func CheckDiskSpace(streams step.OutStreams, agentConns []*idl.Connection, diskF
 		return err
 	}
 
-	// combine disk space usage across all hosts and return an usage error
+	var usages []disk.FileSystemDiskUsage
+	for usage := range usagesChan {
+		usages = append(usages, usage)
+	}
+
+	return spaceUsageError(usages)
+}
+
+// CheckMeasuredDiskSpace checks the disk space required by the upgrade based on
+// the measured size of the data directories and tablespaces rather than a ratio
+// of the filesystem size. Copy mode requires the size of each data directory
+// and tablespace since they are copied. Link mode requires the size of the
+// coordinator data directory for each data directory since their catalog is
+// created from it, while the user data is linked. Both modes require the size
+// of the coordinator data directory and tablespaces in each backup directory.
+func CheckMeasuredDiskSpace(ctx context.Context, streams step.OutStreams, agentConns []*idl.Connection, mode idl.Mode, source *greenplum.Cluster, sourceTablespaces greenplum.Tablespaces, backupDirs backupdir.BackupDirs) error {
+	coordinatorSize, err := dirSize(ctx, source.CoordinatorDataDir())
+	if err != nil {
+		return err
+	}
+
+	backupSize := coordinatorSize
+	for _, location := range sourceTablespaces.GetCoordinatorTablespaces().UserDefinedTablespacesLocations() {
+		size, err := dirSize(ctx, location)
+		if err != nil {
+			return err
+		}
+
+		backupSize += size
+	}
+
+	requirements := func(host string) []*idl.DiskSpaceRequirement {
+		segments := source.SelectSegments(func(seg *greenplum.SegConfig) bool {
+			return seg.IsOnHost(host)
+		})
+		sort.Sort(segments)
+
+		var requirements []*idl.DiskSpaceRequirement
+		for _, seg := range segments {
+			if mode == idl.Mode_link {
+				requirements = append(requirements, &idl.DiskSpaceRequirement{Dir: seg.DataDir, Required: coordinatorSize})
+				continue
+			}
+
+			requirements = append(requirements, &idl.DiskSpaceRequirement{Dir: seg.DataDir, Measure: true})
+			for _, location := range sourceTablespaces[int32(seg.DbID)].UserDefinedTablespacesLocations() {
+				requirements = append(requirements, &idl.DiskSpaceRequirement{Dir: location, Measure: true})
+			}
+		}
+
+		if host == source.CoordinatorHostname() {
+			requirements = append(requirements, &idl.DiskSpaceRequirement{Dir: backupDirs.CoordinatorBackupDir, Required: coordinatorSize})
+		}
+
+		if backupDir, ok := backupDirs.AgentHostsToBackupDir[host]; ok {
+			requirements = append(requirements, &idl.DiskSpaceRequirement{Dir: backupDir, Required: backupSize})
+		}
+
+		return requirements
+	}
+
+	var mutex sync.Mutex
+	var usages []disk.FileSystemDiskUsage
+
+	agentHosts := make(map[string]bool)
+	for _, conn := range agentConns {
+		agentHosts[conn.Hostname] = true
+	}
+
+	// check disk space on the coordinator host when no agent runs there
+	coordinatorHost := source.CoordinatorHostname()
+	if !agentHosts[coordinatorHost] {
+		usage, err := checkDiskRequirements(ctx, streams, disk.Local, requirements(coordinatorHost)...)
+		if err != nil {
+			return err
+		}
+
+		usages = append(usages, usage)
+	}
+
+	request := func(conn *idl.Connection) error {
+		req := &idl.CheckSegmentDiskSpaceRequest{Requirements: requirements(conn.Hostname)}
+		if len(req.GetRequirements()) == 0 {
+			return nil
+		}
+
+		// Measuring takes as long as the data is large, so it has its own
+		// policy without a deadline or retries.
+		rpcCtx := ctx
+		if mode == idl.Mode_copy {
+			rpcCtx = withRPCPolicy(ctx, measureDiskSpacePolicy)
+		}
+
+		reply, err := conn.AgentClient.CheckDiskSpace(rpcCtx, req)
+		if err != nil {
+			return err
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		usages = append(usages, reply.GetUsage())
+
+		return nil
+	}
+
+	err = ExecuteRPC(ctx, agentConns, request)
+	if err != nil {
+		return err
+	}
+
+	return spaceUsageError(usages)
+}
+
+// spaceUsageError combines the disk space usage across all hosts and returns a
+// usage error when any filesystem does not have enough space.
+func spaceUsageError(usages []disk.FileSystemDiskUsage) error {
 	totalUsage := make(map[disk.FilesystemHost]*idl.CheckDiskSpaceReply_DiskUsage)
-	for usages := range usagesChan {
-		for _, usage := range usages {
-			totalUsage[disk.FilesystemHost{Filesystem: usage.GetFs(), Host: usage.GetHost()}] = usage
+	for _, usage := range usages {
+		for _, u := range usage {
+			totalUsage[disk.FilesystemHost{Filesystem: u.GetFs(), Host: u.GetHost()}] = u
 		}
 	}
 
@@ -66,7 +184,7 @@ func CheckDiskSpace(streams step.OutStreams, agentConns []*idl.Connection, diskF
 	return nil
 }
 
-func checkDiskSpaceOnStandbyAndSegments(agentConns []*idl.Connection, errs chan<- error, usages chan<- disk.FileSystemDiskUsage, diskFreeRatio float64, source *greenplum.Cluster, sourceTablespaces greenplum.Tablespaces) {
+func checkDiskSpaceOnStandbyAndSegments(ctx context.Context, agentConns []*idl.Connection, errs chan<- error, usages chan<- disk.FileSystemDiskUsage, diskFreeRatio float64, source *greenplum.Cluster, sourceTablespaces greenplum.Tablespaces) {
 	var wg sync.WaitGroup
 
 	for _, conn := range agentConns {
@@ -95,7 +213,7 @@ func checkDiskSpaceOnStandbyAndSegments(agentConns []*idl.Connection, errs chan<
 				Dirs:          dirs,
 			}
 
-			reply, err := conn.AgentClient.CheckDiskSpace(context.Background(), req)
+			reply, err := conn.AgentClient.CheckDiskSpace(ctx, req)
 			errs <- err
 			if reply != nil {
 				usages <- reply.GetUsage()
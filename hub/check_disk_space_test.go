@@ -4,6 +4,7 @@
 package hub_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
@@ -13,6 +14,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"golang.org/x/xerrors"
 
+	"github.com/greenplum-db/gpupgrade/config/backupdir"
 	"github.com/greenplum-db/gpupgrade/greenplum"
 	"github.com/greenplum-db/gpupgrade/hub"
 	"github.com/greenplum-db/gpupgrade/idl"
@@ -49,7 +51,7 @@ func TestCheckDiskSpace_OnCoordinator(t *testing.T) {
 		hub.SetCheckDiskUsage(CoordinatorHostCheckDiskUsagePasses)
 		defer hub.ResetCheckDiskUsage()
 
-		err := hub.CheckDiskSpace(step.DevNullStream, []*idl.Connection{}, 0, source, tablespaces)
+		err := hub.CheckDiskSpace(context.Background(), step.DevNullStream, []*idl.Connection{}, 0, source, tablespaces)
 		if err != nil {
 			t.Errorf("unexpected error %#v", err)
 		}
@@ -60,7 +62,7 @@ func TestCheckDiskSpace_OnCoordinator(t *testing.T) {
 		hub.SetCheckDiskUsage(CoordinatorHostErrorsWith(expected))
 		defer hub.ResetCheckDiskUsage()
 
-		err := hub.CheckDiskSpace(step.DevNullStream, []*idl.Connection{}, 0, source, tablespaces)
+		err := hub.CheckDiskSpace(context.Background(), step.DevNullStream, []*idl.Connection{}, 0, source, tablespaces)
 		if !errors.Is(err, expected) {
 			t.Errorf("got error %#v, want %#v", err, expected)
 		}
@@ -76,7 +78,7 @@ func TestCheckDiskSpace_OnCoordinator(t *testing.T) {
 		hub.SetCheckDiskUsage(CoordinatorHostReturnsUsage(disk.FileSystemDiskUsage{&usage}))
 		defer hub.ResetCheckDiskUsage()
 
-		err := hub.CheckDiskSpace(step.DevNullStream, []*idl.Connection{}, 0, source, tablespaces)
+		err := hub.CheckDiskSpace(context.Background(), step.DevNullStream, []*idl.Connection{}, 0, source, tablespaces)
 		expected := disk.NewSpaceUsageErrorFromUsage(usage)
 		if !reflect.DeepEqual(err, expected) {
 			t.Errorf("returned %v want %v", err, expected)
@@ -138,7 +140,7 @@ func TestCheckDiskSpace_OnSegments(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.CheckDiskSpace(step.DevNullStream, agentConns, diskFreeRatio, source, tablespaces)
+		err := hub.CheckDiskSpace(context.Background(), step.DevNullStream, agentConns, diskFreeRatio, source, tablespaces)
 		if err != nil {
 			t.Errorf("unexpected error %#v", err)
 		}
@@ -159,7 +161,7 @@ func TestCheckDiskSpace_OnSegments(t *testing.T) {
 			{AgentClient: failedClient, Hostname: "sdw1"},
 		}
 
-		err := hub.CheckDiskSpace(step.DevNullStream, agentConns, 0, source, tablespaces)
+		err := hub.CheckDiskSpace(context.Background(), step.DevNullStream, agentConns, 0, source, tablespaces)
 		if !errors.Is(err, expected) {
 			t.Errorf("got error %#v, want %#v", err, expected)
 		}
@@ -186,7 +188,7 @@ func TestCheckDiskSpace_OnSegments(t *testing.T) {
 			{AgentClient: failedClient, Hostname: "smdw"},
 		}
 
-		err := hub.CheckDiskSpace(step.DevNullStream, agentConns, 0, source, tablespaces)
+		err := hub.CheckDiskSpace(context.Background(), step.DevNullStream, agentConns, 0, source, tablespaces)
 		expected := disk.NewSpaceUsageErrorFromUsage(usage)
 		if !reflect.DeepEqual(err, expected) {
 			t.Errorf("returned %v want %v", err, expected)
@@ -247,7 +249,7 @@ func TestCheckDiskSpace_OnSegments(t *testing.T) {
 			{DbID: 6, ContentID: 1, Hostname: "mirror", DataDir: "/data/dbfast_mirror2/seg2", Role: greenplum.MirrorRole},
 		})
 
-		err := hub.CheckDiskSpace(step.DevNullStream, agentConns, 0, sourceCluster, tablespaces)
+		err := hub.CheckDiskSpace(context.Background(), step.DevNullStream, agentConns, 0, sourceCluster, tablespaces)
 		expected := [][]string{
 			{"Hostname", "Filesystem", "Shortfall", "Available", "Required"},
 			{"mirror", "/data", disk.FormatBytes(2024), disk.FormatBytes(2024), disk.FormatBytes(4048)},
@@ -283,7 +285,7 @@ func TestCheckDiskSpace_OnSegments(t *testing.T) {
 			{ContentID: -1, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
 		})
 
-		err := hub.CheckDiskSpace(step.DevNullStream, agentConns, 0, coordinatorOnlyCluster, tablespaces)
+		err := hub.CheckDiskSpace(context.Background(), step.DevNullStream, agentConns, 0, coordinatorOnlyCluster, tablespaces)
 		if err != nil {
 			t.Errorf("unexpected error %#v", err)
 		}
@@ -317,3 +319,238 @@ func (r reqCheckDiskMatcher) Matches(x interface{}) bool {
 func (r reqCheckDiskMatcher) String() string {
 	return fmt.Sprintf("is equivalent to %v", r.expected)
 }
+
+func TestCheckMeasuredDiskSpace(t *testing.T) {
+	source := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "smdw", DataDir: "/data/standby", Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast/seg1", Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Role: greenplum.MirrorRole},
+		{DbID: 5, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast/seg2", Role: greenplum.PrimaryRole},
+		{DbID: 6, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast_mirror2/seg2", Role: greenplum.MirrorRole},
+	})
+
+	tablespaces := testutils.CreateTablespaces()
+
+	backupDirs := backupdir.BackupDirs{
+		CoordinatorBackupDir: "/data/.gpupgrade",
+		AgentHostsToBackupDir: backupdir.AgentHostsToBackupDir{
+			"smdw": "/data/.gpupgrade",
+			"sdw1": "/data/dbfast/.gpupgrade",
+			"sdw2": "/data/dbfast/.gpupgrade",
+		},
+	}
+
+	hub.SetDirSize(func(ctx context.Context, path string) (uint64, error) {
+		sizes := map[string]uint64{
+			"/data/qddir/seg-1":          100,
+			"/tmp/user_ts/m/qddir/16384": 20,
+		}
+
+		size, ok := sizes[path]
+		if !ok {
+			return 0, fmt.Errorf("unexpected path %q", path)
+		}
+
+		return size, nil
+	})
+	defer hub.ResetDirSize()
+
+	cases := []struct {
+		name        string
+		mode        idl.Mode
+		coordinator []*idl.DiskSpaceRequirement
+		agents      map[string][]*idl.DiskSpaceRequirement
+	}{
+		{
+			name: "copy mode requires the measured size of each data directory and tablespace",
+			mode: idl.Mode_copy,
+			coordinator: []*idl.DiskSpaceRequirement{
+				{Dir: "/data/qddir/seg-1", Measure: true},
+				{Dir: "/tmp/user_ts/m/qddir/16384", Measure: true},
+				{Dir: "/data/.gpupgrade", Required: 100},
+			},
+			agents: map[string][]*idl.DiskSpaceRequirement{
+				"smdw": {
+					{Dir: "/data/standby", Measure: true},
+					{Dir: "/tmp/user_ts/m/standby/16384", Measure: true},
+					{Dir: "/data/.gpupgrade", Required: 120},
+				},
+				"sdw1": {
+					{Dir: "/data/dbfast/seg1", Measure: true},
+					{Dir: "/tmp/user_ts/p1/16384", Measure: true},
+					{Dir: "/data/dbfast_mirror2/seg2", Measure: true},
+					{Dir: "/tmp/user_ts/m2/16384", Measure: true},
+					{Dir: "/data/dbfast/.gpupgrade", Required: 120},
+				},
+				"sdw2": {
+					{Dir: "/data/dbfast_mirror1/seg1", Measure: true},
+					{Dir: "/tmp/user_ts/m1/16384", Measure: true},
+					{Dir: "/data/dbfast/seg2", Measure: true},
+					{Dir: "/tmp/user_ts/p2/16384", Measure: true},
+					{Dir: "/data/dbfast/.gpupgrade", Required: 120},
+				},
+			},
+		},
+		{
+			name: "link mode requires the size of the coordinator for each data directory and backup directory",
+			mode: idl.Mode_link,
+			coordinator: []*idl.DiskSpaceRequirement{
+				{Dir: "/data/qddir/seg-1", Required: 100},
+				{Dir: "/data/.gpupgrade", Required: 100},
+			},
+			agents: map[string][]*idl.DiskSpaceRequirement{
+				"smdw": {
+					{Dir: "/data/standby", Required: 100},
+					{Dir: "/data/.gpupgrade", Required: 120},
+				},
+				"sdw1": {
+					{Dir: "/data/dbfast/seg1", Required: 100},
+					{Dir: "/data/dbfast_mirror2/seg2", Required: 100},
+					{Dir: "/data/dbfast/.gpupgrade", Required: 120},
+				},
+				"sdw2": {
+					{Dir: "/data/dbfast_mirror1/seg1", Required: 100},
+					{Dir: "/data/dbfast/seg2", Required: 100},
+					{Dir: "/data/dbfast/.gpupgrade", Required: 120},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			hub.SetCheckDiskRequirements(func(ctx context.Context, streams step.OutStreams, d disk.Disk, requirements ...*idl.DiskSpaceRequirement) (disk.FileSystemDiskUsage, error) {
+				if !reflect.DeepEqual(requirements, c.coordinator) {
+					t.Errorf("got coordinator requirements %v want %v", requirements, c.coordinator)
+				}
+
+				return nil, nil
+			})
+			defer hub.ResetCheckDiskRequirements()
+
+			var agentConns []*idl.Connection
+			for _, host := range []string{"smdw", "sdw1", "sdw2"} {
+				client := mock_idl.NewMockAgentClient(ctrl)
+				client.EXPECT().CheckDiskSpace(
+					gomock.Any(),
+					&idl.CheckSegmentDiskSpaceRequest{Requirements: c.agents[host]},
+				).Return(&idl.CheckDiskSpaceReply{}, nil)
+
+				agentConns = append(agentConns, &idl.Connection{AgentClient: client, Hostname: host})
+			}
+
+			err := hub.CheckMeasuredDiskSpace(context.Background(), step.DevNullStream, agentConns, c.mode, source, tablespaces, backupDirs)
+			if err != nil {
+				t.Errorf("unexpected error %#v", err)
+			}
+		})
+	}
+
+	t.Run("checks the coordinator host using its agent when one runs there", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		hub.SetCheckDiskRequirements(func(ctx context.Context, streams step.OutStreams, d disk.Disk, requirements ...*idl.DiskSpaceRequirement) (disk.FileSystemDiskUsage, error) {
+			t.Errorf("unexpected check of the coordinator host")
+			return nil, nil
+		})
+		defer hub.ResetCheckDiskRequirements()
+
+		mdw := mock_idl.NewMockAgentClient(ctrl)
+		mdw.EXPECT().CheckDiskSpace(
+			gomock.Any(),
+			&idl.CheckSegmentDiskSpaceRequest{Requirements: []*idl.DiskSpaceRequirement{
+				{Dir: "/data/qddir/seg-1", Required: 100},
+				{Dir: "/data/.gpupgrade", Required: 100},
+			}},
+		).Return(&idl.CheckDiskSpaceReply{}, nil)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: mdw, Hostname: "mdw"},
+		}
+
+		err := hub.CheckMeasuredDiskSpace(context.Background(), step.DevNullStream, agentConns, idl.Mode_link, source, tablespaces, backupDirs)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+
+	t.Run("combines the usage across all hosts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mdwUsage := &idl.CheckDiskSpaceReply_DiskUsage{Fs: "/data", Host: "mdw", Available: 100, Required: 200}
+		hub.SetCheckDiskRequirements(func(ctx context.Context, streams step.OutStreams, d disk.Disk, requirements ...*idl.DiskSpaceRequirement) (disk.FileSystemDiskUsage, error) {
+			return disk.FileSystemDiskUsage{mdwUsage}, nil
+		})
+		defer hub.ResetCheckDiskRequirements()
+
+		sdw1Usage := &idl.CheckDiskSpaceReply_DiskUsage{Fs: "/data", Host: "sdw1", Available: 1000, Required: 3000}
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().CheckDiskSpace(gomock.Any(), gomock.Any()).
+			Return(&idl.CheckDiskSpaceReply{Usage: disk.FileSystemDiskUsage{sdw1Usage}}, nil)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+		}
+
+		err := hub.CheckMeasuredDiskSpace(context.Background(), step.DevNullStream, agentConns, idl.Mode_copy, source, tablespaces, backupDirs)
+
+		var spaceUsageErr *disk.SpaceUsageErr
+		if !errors.As(err, &spaceUsageErr) {
+			t.Fatalf("got type %T want %T", err, spaceUsageErr)
+		}
+
+		expected := [][]string{
+			{"Hostname", "Filesystem", "Shortfall", "Available", "Required"},
+			{"mdw", "/data", disk.FormatBytes(100), disk.FormatBytes(100), disk.FormatBytes(200)},
+			{"sdw1", "/data", disk.FormatBytes(2000), disk.FormatBytes(1000), disk.FormatBytes(3000)},
+		}
+		if !reflect.DeepEqual(spaceUsageErr.Table(), expected) {
+			t.Errorf("returned %v want %v", spaceUsageErr.Table(), expected)
+		}
+	})
+
+	t.Run("errors when measuring the coordinator fails", func(t *testing.T) {
+		expected := errors.New("permission denied")
+		hub.SetDirSize(func(ctx context.Context, path string) (uint64, error) {
+			return 0, expected
+		})
+
+		err := hub.CheckMeasuredDiskSpace(context.Background(), step.DevNullStream, nil, idl.Mode_copy, source, tablespaces, backupDirs)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+
+	t.Run("errors when checking disk space on a host fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		hub.SetDirSize(func(ctx context.Context, path string) (uint64, error) {
+			return 100, nil
+		})
+
+		hub.SetCheckDiskRequirements(func(ctx context.Context, streams step.OutStreams, d disk.Disk, requirements ...*idl.DiskSpaceRequirement) (disk.FileSystemDiskUsage, error) {
+			return nil, nil
+		})
+		defer hub.ResetCheckDiskRequirements()
+
+		expected := errors.New("permission denied")
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().CheckDiskSpace(gomock.Any(), gomock.Any()).Return(nil, expected)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+		}
+
+		err := hub.CheckMeasuredDiskSpace(context.Background(), step.DevNullStream, agentConns, idl.Mode_copy, source, tablespaces, backupDirs)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+}
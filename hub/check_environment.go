@@ -4,20 +4,19 @@
 package hub
 
 import (
+	"errors"
 	"fmt"
-	"log"
-	"os/exec"
 	"strings"
 	"sync"
 
 	"golang.org/x/xerrors"
 
-	"github.com/greenplum-db/gpupgrade/testutils/exectest"
+	"github.com/greenplum-db/gpupgrade/agent/launcher"
 	"github.com/greenplum-db/gpupgrade/utils"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
 )
 
-func CheckEnvironment(agentHostsIncludingCoordinator []string, sourceGphome string, intermediateGphome string) error {
+func CheckEnvironment(l launcher.Launcher, agentHostsIncludingCoordinator []string, sourceGphome string, intermediateGphome string) error {
 	errs := make(chan error, len(agentHostsIncludingCoordinator))
 	var wg sync.WaitGroup
 
@@ -26,7 +25,7 @@ func CheckEnvironment(agentHostsIncludingCoordinator []string, sourceGphome stri
 		go func(host string, sourceGphome string, targetGphome string) {
 			defer wg.Done()
 
-			errs <- CheckEnvironmentOnSegment(host, sourceGphome, targetGphome)
+			errs <- CheckEnvironmentOnSegment(l, host, sourceGphome, targetGphome)
 		}(host, sourceGphome, intermediateGphome)
 	}
 
@@ -35,6 +34,12 @@ func CheckEnvironment(agentHostsIncludingCoordinator []string, sourceGphome stri
 
 	var err error
 	for e := range errs {
+		// Every host uses the same launcher, so when it cannot run commands
+		// the check cannot be done on any host.
+		if errors.Is(e, launcher.ErrCommandsNotSupported) {
+			return xerrors.Errorf("check environment: %w", launcher.ErrCommandsNotSupported)
+		}
+
 		err = errorlist.Append(err, e)
 	}
 
@@ -47,59 +52,26 @@ in .bashrc or .bash_profile. In a fresh shell re-run gpupgrade.`
 	return nil
 }
 
-var pathCommand = exec.Command
-var ldLibraryPathCommand = exec.Command
-
-// XXX: for internal testing only
-func SetPathCommand(command exectest.Command) {
-	pathCommand = command
-}
-
-func SetLdLibraryPathCommand(command exectest.Command) {
-	ldLibraryPathCommand = command
-}
-
-// XXX: for internal testing only
-func ResetPathCommand() {
-	pathCommand = exec.Command
-}
-
-func ResetLdLibraryPathCommand() {
-	ldLibraryPathCommand = exec.Command
-}
-
 // CheckEnvironmentOnSegment ensures that multiple versions of Greenplum
-// environments are not mixed. Use ssh instead of gRPC since our utilities like
-// gpinitsystem, gpstart, gpstop, etc. use ssh internally. This checks up front
-// for the following error as described here:
+// environments are not mixed. Use the launcher such as ssh instead of gRPC
+// since our utilities like gpinitsystem, gpstart, gpstop, etc. use ssh
+// internally. This checks up front for the following error as described here:
 // https://web.archive.org/web/20220506055918/https://groups.google.com/a/greenplum.org/g/gpdb-dev/c/JN-YwjCCReY/m/0L9wBOvlAQAJ
-func CheckEnvironmentOnSegment(host string, sourceGphome string, targetGphome string) error {
-	// check $PATH
-	cmd := pathCommand("ssh", "-q", host, "echo", "$PATH")
-	log.Printf("Executing: %q", cmd.String())
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return xerrors.Errorf("%q failed with %q: %w", cmd.String(), string(output), err)
-	}
-
-	log.Printf("Output: %q", output)
-	path := string(output)
-	if strings.Contains(path, sourceGphome) || strings.Contains(path, targetGphome) {
-		return fmt.Errorf("on host %s PATH contains GPHOME", host)
-	}
-
-	// check $LD_LIBRARY_PATH
-	cmd = ldLibraryPathCommand("ssh", "-q", host, "echo", "$LD_LIBRARY_PATH")
-	log.Printf("Executing: %q", cmd.String())
-	output, err = cmd.CombinedOutput()
-	if err != nil {
-		return xerrors.Errorf("%q failed with %q: %w", cmd.String(), string(output), err)
-	}
-
-	log.Printf("Output: %q", output)
-	ldLibraryPath := string(output)
-	if strings.Contains(ldLibraryPath, sourceGphome) || strings.Contains(ldLibraryPath, targetGphome) {
-		return fmt.Errorf("on host %s LD_LIBRARY_PATH contains GPHOME", host)
+//
+// An error wrapping launcher.ErrCommandsNotSupported is returned when the
+// launcher cannot run commands on the host such that callers report the check
+// as skipped rather than passed.
+func CheckEnvironmentOnSegment(l launcher.Launcher, host string, sourceGphome string, targetGphome string) error {
+	for _, variable := range []string{"PATH", "LD_LIBRARY_PATH"} {
+		output, err := l.Output(host, "echo $"+variable)
+		if err != nil {
+			return xerrors.Errorf("check environment on host %s: %w", host, err)
+		}
+
+		value := string(output)
+		if strings.Contains(value, sourceGphome) || strings.Contains(value, targetGphome) {
+			return fmt.Errorf("on host %s %s contains GPHOME", host, variable)
+		}
 	}
 
 	return nil
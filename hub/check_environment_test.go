@@ -7,13 +7,15 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 
+	"github.com/greenplum-db/gpupgrade/agent/launcher"
 	"github.com/greenplum-db/gpupgrade/greenplum"
 	"github.com/greenplum-db/gpupgrade/hub"
 	"github.com/greenplum-db/gpupgrade/testutils"
-	"github.com/greenplum-db/gpupgrade/testutils/exectest"
 	"github.com/greenplum-db/gpupgrade/testutils/testlog"
 	"github.com/greenplum-db/gpupgrade/utils"
 )
@@ -42,26 +44,39 @@ func TestCheckEnvironmentOnSegments(t *testing.T) {
 	intermediate.GPHome = "/usr/local/greenplum-db-target"
 
 	t.Run("checks environment on segments", func(t *testing.T) {
-		hub.SetPathCommand(exectest.NewCommand(hub.PathMain))
-		defer hub.ResetPathCommand()
+		l := &fakeLauncher{outputs: map[string]string{
+			"echo $PATH":            "/usr/local/bin:/usr/bin",
+			"echo $LD_LIBRARY_PATH": "",
+		}}
 
-		hub.SetLdLibraryPathCommand(exectest.NewCommand(hub.LdLibraryPathMain))
-		defer hub.ResetLdLibraryPathCommand()
-
-		err := hub.CheckEnvironment(append(hub.AgentHosts(source), source.CoordinatorHostname()), source.GPHome, intermediate.GPHome)
+		hosts := append(hub.AgentHosts(source), source.CoordinatorHostname())
+		err := hub.CheckEnvironment(l, hosts, source.GPHome, intermediate.GPHome)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
+
+		sort.Strings(hosts)
+		if !reflect.DeepEqual(l.commandHosts(), hosts) {
+			t.Errorf("got commands run on %q want %q", l.commandHosts(), hosts)
+		}
 	})
 
-	t.Run("returns error when failing to check PATH on segments", func(t *testing.T) {
-		hub.SetPathCommand(exectest.NewCommand(hub.Failure))
-		defer hub.ResetPathCommand()
+	t.Run("errors rather than passing when the launcher cannot run commands", func(t *testing.T) {
+		err := hub.CheckEnvironment(launcher.Running{}, append(hub.AgentHosts(source), source.CoordinatorHostname()), source.GPHome, intermediate.GPHome)
+		if !errors.Is(err, launcher.ErrCommandsNotSupported) {
+			t.Errorf("got error %#v want %#v", err, launcher.ErrCommandsNotSupported)
+		}
 
-		hub.SetLdLibraryPathCommand(exectest.NewCommand(hub.LdLibraryPathMain))
-		defer hub.ResetLdLibraryPathCommand()
+		var nextActionErr utils.NextActionErr
+		if errors.As(err, &nextActionErr) {
+			t.Errorf("got next action %q want none since the environment was not checked", nextActionErr.NextAction)
+		}
+	})
 
-		err := hub.CheckEnvironment(append(hub.AgentHosts(source), source.CoordinatorHostname()), source.GPHome, intermediate.GPHome)
+	t.Run("returns error when failing to check PATH on segments", func(t *testing.T) {
+		l := &fakeLauncher{outputErrs: map[string]error{"echo $PATH": errors.New("permission denied")}}
+
+		err := hub.CheckEnvironment(l, append(hub.AgentHosts(source), source.CoordinatorHostname()), source.GPHome, intermediate.GPHome)
 		var expected utils.NextActionErr
 		if !errors.As(err, &expected) {
 			t.Fatalf("got type %T, want type %T", err, expected)
@@ -73,13 +88,12 @@ func TestCheckEnvironmentOnSegments(t *testing.T) {
 	})
 
 	t.Run("returns error when failing to check LD_LIBRARY_PATH on segments", func(t *testing.T) {
-		hub.SetPathCommand(exectest.NewCommand(hub.PathMain))
-		defer hub.ResetPathCommand()
-
-		hub.SetLdLibraryPathCommand(exectest.NewCommand(hub.Failure))
-		defer hub.ResetLdLibraryPathCommand()
+		l := &fakeLauncher{
+			outputs:    map[string]string{"echo $PATH": "/usr/local/bin:/usr/bin"},
+			outputErrs: map[string]error{"echo $LD_LIBRARY_PATH": errors.New("permission denied")},
+		}
 
-		err := hub.CheckEnvironment(append(hub.AgentHosts(source), source.CoordinatorHostname()), source.GPHome, intermediate.GPHome)
+		err := hub.CheckEnvironment(l, append(hub.AgentHosts(source), source.CoordinatorHostname()), source.GPHome, intermediate.GPHome)
 		var expected utils.NextActionErr
 		if !errors.As(err, &expected) {
 			t.Fatalf("got type %T, want type %T", err, expected)
@@ -118,12 +132,6 @@ func TestCheckEnvironmentOnSegment(t *testing.T) {
 			return host, nil
 		}
 
-		hub.SetPathCommand(exectest.NewCommand(hub.PathMain))
-		defer hub.ResetPathCommand()
-
-		hub.SetLdLibraryPathCommand(exectest.NewCommand(hub.LdLibraryPathMain))
-		defer hub.ResetLdLibraryPathCommand()
-
 		for _, c := range cases {
 			t.Run(c.name, func(t *testing.T) {
 				resetPath := testutils.SetEnv(t, "PATH", c.path)
@@ -132,7 +140,7 @@ func TestCheckEnvironmentOnSegment(t *testing.T) {
 				resetLDLibraryPath := testutils.SetEnv(t, "LD_LIBRARY_PATH", c.ldLibraryPath)
 				defer resetLDLibraryPath()
 
-				err := hub.CheckEnvironmentOnSegment(host, sourceGphome, intermediateGphome)
+				err := hub.CheckEnvironmentOnSegment(launcher.Local{}, host, sourceGphome, intermediateGphome)
 				if err != nil {
 					t.Errorf("unexpected error %#v", err)
 				}
@@ -174,12 +182,6 @@ func TestCheckEnvironmentOnSegment(t *testing.T) {
 			return host, nil
 		}
 
-		hub.SetPathCommand(exectest.NewCommand(hub.PathMain))
-		defer hub.ResetPathCommand()
-
-		hub.SetLdLibraryPathCommand(exectest.NewCommand(hub.LdLibraryPathMain))
-		defer hub.ResetLdLibraryPathCommand()
-
 		for _, c := range errorCases {
 			t.Run(c.name, func(t *testing.T) {
 				resetPath := testutils.SetEnv(t, "PATH", c.path)
@@ -188,7 +190,7 @@ func TestCheckEnvironmentOnSegment(t *testing.T) {
 				resetLDLibraryPath := testutils.SetEnv(t, "LD_LIBRARY_PATH", c.ldLibraryPath)
 				defer resetLDLibraryPath()
 
-				err := hub.CheckEnvironmentOnSegment(host, sourceGphome, intermediateGphome)
+				err := hub.CheckEnvironmentOnSegment(launcher.Local{}, host, sourceGphome, intermediateGphome)
 				if !strings.Contains(err.Error(), c.expected) {
 					t.Errorf("got %+v, want %+v", err, c.expected)
 				}
@@ -196,3 +198,56 @@ func TestCheckEnvironmentOnSegment(t *testing.T) {
 		}
 	})
 }
+
+// fakeLauncher records the agents it starts and the hosts it runs commands on,
+// and returns the output or error of each command.
+type fakeLauncher struct {
+	mutex      sync.Mutex
+	started    map[string][]string
+	startErr   error
+	outputs    map[string]string
+	outputErrs map[string]error
+	hosts      map[string]bool
+}
+
+func (l *fakeLauncher) Start(host string, agentArgs []string) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.startErr != nil {
+		return l.startErr
+	}
+
+	if l.started == nil {
+		l.started = make(map[string][]string)
+	}
+	l.started[host] = agentArgs
+
+	return nil
+}
+
+func (l *fakeLauncher) Output(host string, command string) ([]byte, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.hosts == nil {
+		l.hosts = make(map[string]bool)
+	}
+	l.hosts[host] = true
+
+	if err, ok := l.outputErrs[command]; ok {
+		return nil, err
+	}
+
+	return []byte(l.outputs[command]), nil
+}
+
+func (l *fakeLauncher) commandHosts() []string {
+	var hosts []string
+	for host := range l.hosts {
+		hosts = append(hosts, host)
+	}
+
+	sort.Strings(hosts)
+	return hosts
+}
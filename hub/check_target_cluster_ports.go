@@ -0,0 +1,101 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// CheckTargetClusterPorts ensures the ports assigned to the intermediate
+// cluster from the temp_port_range can be listened on by each host, since
+// otherwise gpinitsystem fails partway through when another process already
+// holds a port. The agents check the ports of their host, and the hub checks
+// those of the coordinator host when no agent runs there.
+func CheckTargetClusterPorts(ctx context.Context, agentConns []*idl.Connection, intermediate *greenplum.Cluster) error {
+	portsByHost := make(map[string][]int)
+	for _, seg := range intermediate.SelectSegments(func(seg *greenplum.SegConfig) bool { return true }) {
+		portsByHost[seg.Hostname] = append(portsByHost[seg.Hostname], seg.Port)
+	}
+
+	var mutex sync.Mutex
+	var conflicts []portConflict
+
+	checkedHosts := make(map[string]bool)
+	for _, conn := range agentConns {
+		checkedHosts[conn.Hostname] = true
+	}
+
+	coordinatorHost := intermediate.CoordinatorHostname()
+	if !checkedHosts[coordinatorHost] {
+		for port, err := range utils.UnavailablePorts(portsByHost[coordinatorHost]) {
+			conflicts = append(conflicts, portConflict{host: coordinatorHost, port: port, err: err.Error()})
+		}
+	}
+
+	request := func(conn *idl.Connection) error {
+		var ports []int32
+		for _, port := range portsByHost[conn.Hostname] {
+			ports = append(ports, int32(port))
+		}
+
+		if len(ports) == 0 {
+			return nil
+		}
+
+		reply, err := conn.AgentClient.CheckPorts(ctx, &idl.CheckPortsRequest{Ports: ports})
+		if err != nil {
+			return err
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		for _, unavailable := range reply.GetUnavailable() {
+			conflicts = append(conflicts, portConflict{host: conn.Hostname, port: int(unavailable.GetPort()), err: unavailable.GetError()})
+		}
+
+		return nil
+	}
+
+	err := ExecuteRPC(ctx, agentConns, request)
+	if err != nil {
+		return err
+	}
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		if conflicts[i].host == conflicts[j].host {
+			return conflicts[i].port < conflicts[j].port
+		}
+
+		return conflicts[i].host < conflicts[j].host
+	})
+
+	for _, conflict := range conflicts {
+		err = errorlist.Append(err, xerrors.Errorf("port %d on host %s is unavailable: %s", conflict.port, conflict.host, conflict.err))
+	}
+
+	nextAction := `Stop the processes using the ports and re-run "gpupgrade initialize".
+Otherwise run "gpupgrade revert" and re-run "gpupgrade initialize" with a
+temp_port_range that is available on all hosts.`
+	return utils.NewNextActionErr(err, nextAction)
+}
+
+type portConflict struct {
+	host string
+	port int
+	err  string
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+func TestCheckTargetClusterPorts(t *testing.T) {
+	coordinatorPort := freePort(t)
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Port: coordinatorPort, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "smdw", DataDir: "/data/standby", Port: 50433, Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast/seg1", Port: 50434, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 50435, Role: greenplum.MirrorRole},
+		{DbID: 5, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast/seg2", Port: 50436, Role: greenplum.PrimaryRole},
+		{DbID: 6, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast_mirror2/seg2", Port: 50437, Role: greenplum.MirrorRole},
+	})
+
+	t.Run("checks the ports of each host and the coordinator host locally", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		smdw := mock_idl.NewMockAgentClient(ctrl)
+		smdw.EXPECT().CheckPorts(
+			gomock.Any(),
+			equivalentCheckPortsRequest(&idl.CheckPortsRequest{Ports: []int32{50433}}),
+		).Return(&idl.CheckPortsReply{}, nil)
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().CheckPorts(
+			gomock.Any(),
+			equivalentCheckPortsRequest(&idl.CheckPortsRequest{Ports: []int32{50434, 50437}}),
+		).Return(&idl.CheckPortsReply{}, nil)
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().CheckPorts(
+			gomock.Any(),
+			equivalentCheckPortsRequest(&idl.CheckPortsRequest{Ports: []int32{50435, 50436}}),
+		).Return(&idl.CheckPortsReply{}, nil)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: smdw, Hostname: "smdw"},
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		err := hub.CheckTargetClusterPorts(context.Background(), agentConns, intermediate)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+
+	t.Run("does not check the coordinator host locally when an agent runs on it", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		listener := listen(t, coordinatorPort)
+		defer listener.Close()
+
+		mdw := mock_idl.NewMockAgentClient(ctrl)
+		mdw.EXPECT().CheckPorts(
+			gomock.Any(),
+			equivalentCheckPortsRequest(&idl.CheckPortsRequest{Ports: []int32{int32(coordinatorPort)}}),
+		).Return(&idl.CheckPortsReply{}, nil)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: mdw, Hostname: "mdw"},
+		}
+
+		err := hub.CheckTargetClusterPorts(context.Background(), agentConns, intermediate)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+
+	t.Run("reports the unavailable ports of each host", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		listener := listen(t, coordinatorPort)
+		defer listener.Close()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().CheckPorts(gomock.Any(), gomock.Any()).Return(&idl.CheckPortsReply{
+			Unavailable: []*idl.CheckPortsReply_UnavailablePort{
+				{Port: 50434, Error: "address already in use"},
+				{Port: 50437, Error: "permission denied"},
+			},
+		}, nil)
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().CheckPorts(gomock.Any(), gomock.Any()).Return(&idl.CheckPortsReply{
+			Unavailable: []*idl.CheckPortsReply_UnavailablePort{
+				{Port: 50436, Error: "address already in use"},
+			},
+		}, nil)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw2, Hostname: "sdw2"},
+			{AgentClient: sdw1, Hostname: "sdw1"},
+		}
+
+		err := hub.CheckTargetClusterPorts(context.Background(), agentConns, intermediate)
+
+		var nextActionErr utils.NextActionErr
+		if !errors.As(err, &nextActionErr) {
+			t.Fatalf("got type %T want %T", err, nextActionErr)
+		}
+
+		var errs errorlist.Errors
+		if !errors.As(nextActionErr.Err, &errs) {
+			t.Fatalf("got type %T want %T", nextActionErr.Err, errs)
+		}
+
+		var actual []string
+		for _, err := range errs {
+			actual = append(actual, err.Error())
+		}
+
+		expected := []string{
+			fmt.Sprintf("port %d on host mdw is unavailable", coordinatorPort),
+			"port 50434 on host sdw1 is unavailable: address already in use",
+			"port 50437 on host sdw1 is unavailable: permission denied",
+			"port 50436 on host sdw2 is unavailable: address already in use",
+		}
+
+		if len(actual) != len(expected) {
+			t.Fatalf("got %q want %q", actual, expected)
+		}
+
+		for i := range expected {
+			if !strings.HasPrefix(actual[i], expected[i]) {
+				t.Errorf("got error %q want it to start with %q", actual[i], expected[i])
+			}
+		}
+	})
+
+	t.Run("errors when checking the ports of a host fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expected := errors.New("permission denied")
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().CheckPorts(gomock.Any(), gomock.Any()).Return(nil, expected)
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().CheckPorts(gomock.Any(), gomock.Any()).Return(&idl.CheckPortsReply{}, nil)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		err := hub.CheckTargetClusterPorts(context.Background(), agentConns, intermediate)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+}
+
+func freePort(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func listen(t *testing.T, port int) net.Listener {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	return listener
+}
+
+// equivalentCheckPortsRequest is a Matcher that can handle differences in
+// order of the ports in the request.
+func equivalentCheckPortsRequest(req *idl.CheckPortsRequest) gomock.Matcher {
+	return checkPortsRequestMatcher{req}
+}
+
+type checkPortsRequestMatcher struct {
+	expected *idl.CheckPortsRequest
+}
+
+func (m checkPortsRequestMatcher) Matches(x interface{}) bool {
+	actual, ok := x.(*idl.CheckPortsRequest)
+	if !ok {
+		return false
+	}
+
+	return reflect.DeepEqual(sortedPorts(actual.GetPorts()), sortedPorts(m.expected.GetPorts()))
+}
+
+func (m checkPortsRequestMatcher) String() string {
+	return fmt.Sprintf("is equivalent to %v", m.expected)
+}
+
+func sortedPorts(ports []int32) []int32 {
+	sorted := append([]int32(nil), ports...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return sorted
+}
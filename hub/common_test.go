@@ -5,6 +5,7 @@ package hub
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"testing"
@@ -20,14 +21,15 @@ func init() {
 	ResetExecCommand()
 	rsync.ResetRsyncCommand()
 	ResetCheckDiskUsage()
+	ResetCheckDiskRequirements()
+	ResetDirSize()
 
 	exectest.RegisterMains(
 		Success,
 		Failure,
-		PathMain,
-		LdLibraryPathMain,
 		StreamingMain,
 		EnvironmentMain,
+		GpupgradeVersionMain,
 	)
 }
 
@@ -38,12 +40,11 @@ func Failure() {
 	os.Exit(1)
 }
 
-func PathMain() {
-	fmt.Println(os.Getenv("PATH"))
-}
+const GpupgradeVersionMainOutput = "Version: 1.0.0 Commit: 83aaa4 Release: Enterprise"
 
-func LdLibraryPathMain() {
-	fmt.Println(os.Getenv("LD_LIBRARY_PATH"))
+// Prints the version the way gpupgrade version --format oneline does.
+func GpupgradeVersionMain() {
+	fmt.Println(GpupgradeVersionMainOutput)
 }
 
 const StreamingMainStdout = "expected\nstdout\n"
@@ -84,6 +85,22 @@ func ResetCheckDiskUsage() {
 	checkDiskUsage = disk.CheckUsage
 }
 
+func SetCheckDiskRequirements(requirementsFunc disk.CheckRequirementsType) {
+	checkDiskRequirements = requirementsFunc
+}
+
+func ResetCheckDiskRequirements() {
+	checkDiskRequirements = disk.CheckRequirements
+}
+
+func SetDirSize(sizeFunc func(ctx context.Context, path string) (uint64, error)) {
+	dirSize = sizeFunc
+}
+
+func ResetDirSize() {
+	dirSize = disk.DirSize
+}
+
 // MustCreateCluster creates a utils.Cluster and calls t.Fatalf() if there is
 // any error.
 func MustCreateCluster(t *testing.T, segments greenplum.SegConfigs) *greenplum.Cluster {
@@ -5,6 +5,7 @@ package hub
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"os"
 	"path/filepath"
@@ -27,7 +28,7 @@ type Result struct {
 	err    error
 }
 
-func Copy(streams step.OutStreams, sourceDirs []string, agentHostsToBackupDir backupdir.AgentHostsToBackupDir) error {
+func Copy(ctx context.Context, streams step.OutStreams, sourceDirs []string, agentHostsToBackupDir backupdir.AgentHostsToBackupDir) error {
 	/*
 	 * Copy the directories once per host.
 	 */
@@ -49,9 +50,10 @@ func Copy(streams step.OutStreams, sourceDirs []string, agentHostsToBackupDir ba
 				rsync.WithDestination(backupDir),
 				rsync.WithOptions("--archive", "--compress", "--delete", "--stats"),
 				rsync.WithStream(stream),
+				rsync.WithContext(ctx),
 			}
 
-			err := rsync.Rsync(options...)
+			err := runRsync(options...)
 			if err != nil {
 				err = xerrors.Errorf("copying source %q to destination %q on host %s: %w", sourceDirs, backupDir, hostname, err)
 			}
@@ -82,7 +84,7 @@ func Copy(streams step.OutStreams, sourceDirs []string, agentHostsToBackupDir ba
 	return errs
 }
 
-func CopyCoordinatorDataDir(streams step.OutStreams, coordinatorDataDir string, agentHostsToBackupDir backupdir.AgentHostsToBackupDir) error {
+func CopyCoordinatorDataDir(ctx context.Context, streams step.OutStreams, coordinatorDataDir string, agentHostsToBackupDir backupdir.AgentHostsToBackupDir) error {
 	// Make sure sourceDir ends with a trailing slash so that rsync will
 	// transfer the directory contents and not the directory itself.
 	source := []string{filepath.Clean(coordinatorDataDir) + string(filepath.Separator)}
@@ -92,10 +94,10 @@ func CopyCoordinatorDataDir(streams step.OutStreams, coordinatorDataDir string,
 		destinationHostToBackupDir[host] = utils.GetCoordinatorPostUpgradeBackupDir(backupDir)
 	}
 
-	return Copy(streams, source, destinationHostToBackupDir)
+	return Copy(ctx, streams, source, destinationHostToBackupDir)
 }
 
-func CopyCoordinatorTablespaces(streams step.OutStreams, sourceVersion semver.Version, tablespaces greenplum.Tablespaces, agentHostsToBackupDir backupdir.AgentHostsToBackupDir) error {
+func CopyCoordinatorTablespaces(ctx context.Context, streams step.OutStreams, sourceVersion semver.Version, tablespaces greenplum.Tablespaces, agentHostsToBackupDir backupdir.AgentHostsToBackupDir) error {
 	if tablespaces == nil && sourceVersion.Major != 5 {
 		return nil
 	}
@@ -115,5 +117,5 @@ func CopyCoordinatorTablespaces(streams step.OutStreams, sourceVersion semver.Ve
 		destinationHostToBackupDir[host] = utils.GetTablespaceBackupDir(backupDir) + string(os.PathSeparator)
 	}
 
-	return Copy(streams, sourcePaths, destinationHostToBackupDir)
+	return Copy(ctx, streams, sourcePaths, destinationHostToBackupDir)
 }
@@ -4,6 +4,7 @@
 package hub_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -74,7 +75,7 @@ func TestCopy(t *testing.T) {
 		rsync.SetRsyncCommand(cmd)
 		defer rsync.ResetRsyncCommand()
 
-		err := hub.Copy(step.DevNullStream, sourceDirs, backupDirs.AgentHostsToBackupDir)
+		err := hub.Copy(context.Background(), step.DevNullStream, sourceDirs, backupDirs.AgentHostsToBackupDir)
 		if err != nil {
 			t.Errorf("copying data directory: %+v", err)
 		}
@@ -101,7 +102,7 @@ func TestCopy(t *testing.T) {
 		rsync.SetRsyncCommand(cmd)
 		defer rsync.ResetRsyncCommand()
 
-		err := hub.Copy(step.DevNullStream, sourceDirs, backupDirs.AgentHostsToBackupDir)
+		err := hub.Copy(context.Background(), step.DevNullStream, sourceDirs, backupDirs.AgentHostsToBackupDir)
 		if err != nil {
 			t.Errorf("copying directory: %+v", err)
 		}
@@ -128,7 +129,7 @@ func TestCopy(t *testing.T) {
 		rsync.SetRsyncCommand(exectest.NewCommand(hub.StreamingMain))
 		defer rsync.ResetRsyncCommand()
 
-		err := hub.Copy(streams, []string{""}, backupDirs.AgentHostsToBackupDir)
+		err := hub.Copy(context.Background(), streams, []string{""}, backupDirs.AgentHostsToBackupDir)
 
 		var errs errorlist.Errors
 		if !errors.As(err, &errs) {
@@ -153,7 +154,7 @@ func TestCopy(t *testing.T) {
 		rsync.SetRsyncCommand(exectest.NewCommand(RsyncFailure))
 		defer rsync.ResetRsyncCommand()
 
-		err := hub.Copy(buffer, []string{"data/coordinator"}, backupDirs.AgentHostsToBackupDir)
+		err := hub.Copy(context.Background(), buffer, []string{"data/coordinator"}, backupDirs.AgentHostsToBackupDir)
 
 		var errs errorlist.Errors
 		if !errors.As(err, &errs) {
@@ -211,7 +212,7 @@ func TestCopyCoordinatorDataDir(t *testing.T) {
 		rsync.SetRsyncCommand(cmd)
 		defer rsync.ResetRsyncCommand()
 
-		err := hub.CopyCoordinatorDataDir(step.DevNullStream, intermediate.CoordinatorDataDir(), backupDirs.AgentHostsToBackupDir)
+		err := hub.CopyCoordinatorDataDir(context.Background(), step.DevNullStream, intermediate.CoordinatorDataDir(), backupDirs.AgentHostsToBackupDir)
 		if err != nil {
 			t.Errorf("copying coordinator data directory: %+v", err)
 		}
@@ -291,7 +292,7 @@ func TestCopyCoordinatorTablespaces(t *testing.T) {
 		rsync.SetRsyncCommand(cmd)
 		defer rsync.ResetRsyncCommand()
 
-		err := hub.CopyCoordinatorTablespaces(step.DevNullStream, semver.MustParse("5.0.0"), Tablespaces, backupDirs.AgentHostsToBackupDir)
+		err := hub.CopyCoordinatorTablespaces(context.Background(), step.DevNullStream, semver.MustParse("5.0.0"), Tablespaces, backupDirs.AgentHostsToBackupDir)
 		if err != nil {
 			t.Errorf("copying coordinator tablespace directories and mapping file: %+v", err)
 		}
@@ -323,7 +324,7 @@ func TestCopyCoordinatorTablespaces(t *testing.T) {
 		rsync.SetRsyncCommand(cmd)
 		defer rsync.ResetRsyncCommand()
 
-		err := hub.CopyCoordinatorTablespaces(step.DevNullStream, semver.MustParse("5.0.0"), nil, backupDirs.AgentHostsToBackupDir)
+		err := hub.CopyCoordinatorTablespaces(context.Background(), step.DevNullStream, semver.MustParse("5.0.0"), nil, backupDirs.AgentHostsToBackupDir)
 		if err != nil {
 			t.Errorf("got %+v, want nil", err)
 		}
@@ -355,7 +356,7 @@ func TestCopyCoordinatorTablespaces(t *testing.T) {
 		rsync.SetRsyncCommand(cmd)
 		defer rsync.ResetRsyncCommand()
 
-		err := hub.CopyCoordinatorTablespaces(step.DevNullStream, semver.MustParse("6.0.0"), Tablespaces, backupDirs.AgentHostsToBackupDir)
+		err := hub.CopyCoordinatorTablespaces(context.Background(), step.DevNullStream, semver.MustParse("6.0.0"), Tablespaces, backupDirs.AgentHostsToBackupDir)
 		if err != nil {
 			t.Errorf("copying coordinator tablespace directories and mapping file: %+v", err)
 		}
@@ -387,7 +388,7 @@ func TestCopyCoordinatorTablespaces(t *testing.T) {
 		rsync.SetRsyncCommand(cmd)
 		defer rsync.ResetRsyncCommand()
 
-		err := hub.CopyCoordinatorTablespaces(step.DevNullStream, semver.MustParse("6.0.0"), nil, backupDirs.AgentHostsToBackupDir)
+		err := hub.CopyCoordinatorTablespaces(context.Background(), step.DevNullStream, semver.MustParse("6.0.0"), nil, backupDirs.AgentHostsToBackupDir)
 		if err != nil {
 			t.Errorf("copying coordinator tablespace directories and mapping file: %+v", err)
 		}
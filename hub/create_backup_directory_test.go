@@ -4,6 +4,7 @@
 package hub_test
 
 import (
+	"context"
 	"errors"
 	"os"
 	"path/filepath"
@@ -44,7 +45,7 @@ func TestCreateBackupDirectories(t *testing.T) {
 	t.Run("errors when failing to write to stdout", func(t *testing.T) {
 		streams := testutils.FailingStreams{Err: errors.New("e")}
 
-		err := hub.CreateBackupDirectories(streams, nil, backupDirs)
+		err := hub.CreateBackupDirectories(context.Background(), streams, nil, backupDirs)
 		if !errors.Is(err, streams.Err) {
 			t.Errorf("returned error %#v, want %#v", err, streams.Err)
 		}
@@ -63,7 +64,7 @@ func TestCreateBackupDirectories(t *testing.T) {
 		}
 		defer utils.ResetSystemFunctions()
 
-		err := hub.CreateBackupDirectories(step.DevNullStream, nil, backupDirs)
+		err := hub.CreateBackupDirectories(context.Background(), step.DevNullStream, nil, backupDirs)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
@@ -80,7 +81,7 @@ func TestCreateBackupDirectories(t *testing.T) {
 		}
 		defer utils.ResetSystemFunctions()
 
-		err := hub.CreateBackupDirectories(step.DevNullStream, nil, backupDirs)
+		err := hub.CreateBackupDirectories(context.Background(), step.DevNullStream, nil, backupDirs)
 		if !errors.Is(err, expected) {
 			t.Errorf("got error %#v, want %#v", err, expected)
 		}
@@ -121,7 +122,7 @@ func TestCreateBackupDirectories(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.CreateBackupDirectories(step.DevNullStream, agentConns, backupDirs)
+		err := hub.CreateBackupDirectories(context.Background(), step.DevNullStream, agentConns, backupDirs)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
@@ -168,7 +169,7 @@ func TestCreateBackupDirectories(t *testing.T) {
 			{AgentClient: sdw3, Hostname: "sdw3"},
 		}
 
-		err := hub.CreateBackupDirectories(step.DevNullStream, agentConns, backupDirs)
+		err := hub.CreateBackupDirectories(context.Background(), step.DevNullStream, agentConns, backupDirs)
 		var errs errorlist.Errors
 		if !xerrors.As(err, &errs) {
 			t.Fatalf("error %#v does not contain type %T", err, errs)
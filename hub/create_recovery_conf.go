@@ -11,7 +11,7 @@ import (
 	"github.com/greenplum-db/gpupgrade/utils"
 )
 
-func CreateRecoveryConfOnSegments(agentConns []*idl.Connection, intermediate *greenplum.Cluster) error {
+func CreateRecoveryConfOnSegments(ctx context.Context, agentConns []*idl.Connection, intermediate *greenplum.Cluster) error {
 	user, err := utils.System.Current()
 	if err != nil {
 		return err
@@ -37,9 +37,9 @@ func CreateRecoveryConfOnSegments(agentConns []*idl.Connection, intermediate *gr
 		}
 
 		req := &idl.CreateRecoveryConfRequest{Connections: connReqs}
-		_, err := conn.AgentClient.CreateRecoveryConf(context.Background(), req)
+		_, err := conn.AgentClient.CreateRecoveryConf(ctx, req)
 		return err
 	}
 
-	return ExecuteRPC(agentConns, request)
+	return ExecuteRPC(ctx, agentConns, request)
 }
@@ -12,7 +12,7 @@ import (
 	"github.com/greenplum-db/gpupgrade/upgrade"
 )
 
-func DeleteBackupDirectories(streams step.OutStreams, agentConns []*idl.Connection, backupDirs backupdir.BackupDirs) error {
+func DeleteBackupDirectories(ctx context.Context, streams step.OutStreams, agentConns []*idl.Connection, backupDirs backupdir.BackupDirs) error {
 	err := upgrade.DeleteDirectories([]string{backupDirs.CoordinatorBackupDir}, []string{}, streams)
 	if err != nil {
 		return err
@@ -24,9 +24,9 @@ func DeleteBackupDirectories(streams step.OutStreams, agentConns []*idl.Connecti
 		}
 
 		req := &idl.DeleteBackupDirectoryRequest{BackupDir: backupDirs.AgentHostsToBackupDir[conn.Hostname]}
-		_, err := conn.AgentClient.DeleteBackupDirectory(context.Background(), req)
+		_, err := conn.AgentClient.DeleteBackupDirectory(ctx, req)
 		return err
 	}
 
-	return ExecuteRPC(agentConns, request)
+	return ExecuteRPC(ctx, agentConns, request)
 }
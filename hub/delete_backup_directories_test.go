@@ -4,6 +4,7 @@
 package hub_test
 
 import (
+	"context"
 	"errors"
 	"os"
 	"testing"
@@ -46,7 +47,7 @@ func TestDeleteBackupDirectories(t *testing.T) {
 		backupDirs := backupdir.BackupDirs{}
 		backupDirs.CoordinatorBackupDir = coordinatorBackupDir
 
-		err := hub.DeleteBackupDirectories(step.DevNullStream, nil, backupDirs)
+		err := hub.DeleteBackupDirectories(context.Background(), step.DevNullStream, nil, backupDirs)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
@@ -61,7 +62,7 @@ func TestDeleteBackupDirectories(t *testing.T) {
 		}
 		defer utils.ResetSystemFunctions()
 
-		err := hub.DeleteBackupDirectories(step.DevNullStream, nil, backupdir.BackupDirs{})
+		err := hub.DeleteBackupDirectories(context.Background(), step.DevNullStream, nil, backupdir.BackupDirs{})
 		if !errors.Is(err, expected) {
 			t.Errorf("got error %#v want %#v", err, expected)
 		}
@@ -98,7 +99,7 @@ func TestDeleteBackupDirectories(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.DeleteBackupDirectories(step.DevNullStream, agentConns, backupDirs)
+		err := hub.DeleteBackupDirectories(context.Background(), step.DevNullStream, agentConns, backupDirs)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
@@ -141,7 +142,7 @@ func TestDeleteBackupDirectories(t *testing.T) {
 			{AgentClient: sdw3, Hostname: "sdw3"},
 		}
 
-		err := hub.DeleteBackupDirectories(step.DevNullStream, agentConns, backupDirs)
+		err := hub.DeleteBackupDirectories(context.Background(), step.DevNullStream, agentConns, backupDirs)
 		var errs errorlist.Errors
 		if !xerrors.As(err, &errs) {
 			t.Fatalf("error %#v does not contain type %T", err, errs)
@@ -14,7 +14,7 @@ import (
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
 )
 
-func DeleteCoordinatorAndPrimaryDataDirectories(streams step.OutStreams, agentConns []*idl.Connection, intermediate *greenplum.Cluster) error {
+func DeleteCoordinatorAndPrimaryDataDirectories(ctx context.Context, streams step.OutStreams, agentConns []*idl.Connection, intermediate *greenplum.Cluster) error {
 	coordinatorErr := make(chan error)
 	go func() {
 		coordinatorErr <- upgrade.DeleteDirectories([]string{intermediate.CoordinatorDataDir()}, upgrade.PostgresFiles, streams)
@@ -23,13 +23,13 @@ func DeleteCoordinatorAndPrimaryDataDirectories(streams step.OutStreams, agentCo
 	intermediateSegs := intermediate.SelectSegments(func(seg *greenplum.SegConfig) bool {
 		return seg.IsPrimary()
 	})
-	err := deleteDataDirectories(agentConns, intermediateSegs)
+	err := deleteDataDirectories(ctx, agentConns, intermediateSegs)
 	err = errorlist.Append(err, <-coordinatorErr)
 
 	return err
 }
 
-func deleteDataDirectories(agentConns []*idl.Connection, segConfigs greenplum.SegConfigs) error {
+func deleteDataDirectories(ctx context.Context, agentConns []*idl.Connection, segConfigs greenplum.SegConfigs) error {
 	request := func(conn *idl.Connection) error {
 
 		segs := segConfigs.Select(func(seg *greenplum.SegConfig) bool {
@@ -47,14 +47,14 @@ func deleteDataDirectories(agentConns []*idl.Connection, segConfigs greenplum.Se
 			req.Datadirs = append(req.Datadirs, datadir)
 		}
 
-		_, err := conn.AgentClient.DeleteDataDirectories(context.Background(), req)
+		_, err := conn.AgentClient.DeleteDataDirectories(ctx, req)
 		return err
 	}
 
-	return ExecuteRPC(agentConns, request)
+	return ExecuteRPC(ctx, agentConns, request)
 }
 
-func DeleteTargetTablespaces(streams step.OutStreams, agentConns []*idl.Connection, target *greenplum.Cluster, intermediateCatalogVersion string, sourceTablespaces greenplum.Tablespaces) error {
+func DeleteTargetTablespaces(ctx context.Context, streams step.OutStreams, agentConns []*idl.Connection, target *greenplum.Cluster, intermediateCatalogVersion string, sourceTablespaces greenplum.Tablespaces) error {
 	var wg sync.WaitGroup
 	errs := make(chan error, 2)
 
@@ -64,7 +64,7 @@ func DeleteTargetTablespaces(streams step.OutStreams, agentConns []*idl.Connecti
 		errs <- DeleteTargetTablespacesOnCoordinator(streams, target, sourceTablespaces.GetCoordinatorTablespaces(), intermediateCatalogVersion)
 	}()
 
-	errs <- DeleteTargetTablespacesOnPrimaries(agentConns, target, sourceTablespaces, intermediateCatalogVersion)
+	errs <- DeleteTargetTablespacesOnPrimaries(ctx, agentConns, target, sourceTablespaces, intermediateCatalogVersion)
 
 	wg.Wait()
 	close(errs)
@@ -91,7 +91,7 @@ func DeleteTargetTablespacesOnCoordinator(streams step.OutStreams, target *green
 	return upgrade.DeleteTablespaceDirectories(streams, dirs)
 }
 
-func DeleteTargetTablespacesOnPrimaries(agentConns []*idl.Connection, target *greenplum.Cluster, tablespaces greenplum.Tablespaces, catalogVersion string) error {
+func DeleteTargetTablespacesOnPrimaries(ctx context.Context, agentConns []*idl.Connection, target *greenplum.Cluster, tablespaces greenplum.Tablespaces, catalogVersion string) error {
 	request := func(conn *idl.Connection) error {
 		if target == nil {
 			return nil
@@ -119,9 +119,9 @@ func DeleteTargetTablespacesOnPrimaries(agentConns []*idl.Connection, target *gr
 		}
 
 		req := &idl.DeleteTablespaceRequest{Dirs: dirs}
-		_, err := conn.AgentClient.DeleteTablespaceDirectories(context.Background(), req)
+		_, err := conn.AgentClient.DeleteTablespaceDirectories(ctx, req)
 		return err
 	}
 
-	return ExecuteRPC(agentConns, request)
+	return ExecuteRPC(ctx, agentConns, request)
 }
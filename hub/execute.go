@@ -16,7 +16,13 @@ import (
 )
 
 func (s *Server) Execute(req *idl.ExecuteRequest, stream idl.CliToHub_ExecuteServer) (err error) {
-	st, err := step.Begin(idl.Step_execute, stream)
+	ctx, unlock, err := s.lockStep(stream.Context(), idl.Step_execute)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	st, err := step.Begin(ctx, idl.Step_execute, stream)
 	if err != nil {
 		return err
 	}
@@ -32,7 +38,12 @@ func (s *Server) Execute(req *idl.ExecuteRequest, stream idl.CliToHub_ExecuteSer
 	}()
 
 	st.AlwaysRun(idl.Substep_ensure_gpupgrade_agents_are_running, func(_ step.OutStreams) error {
-		_, err := RestartAgents(context.Background(), nil, AgentHosts(s.Source), s.AgentPort, utils.GetStateDir())
+		l, err := s.Launcher()
+		if err != nil {
+			return err
+		}
+
+		_, err = RestartAgents(ctx, l, nil, AgentHosts(s.Source), s.AgentPort, s.AgentListenAddresses, utils.GetStateDir(), s.TLS)
 		if err != nil {
 			return err
 		}
@@ -43,11 +54,11 @@ func (s *Server) Execute(req *idl.ExecuteRequest, stream idl.CliToHub_ExecuteSer
 		}
 
 		return nil
-	})
+	}, step.Idempotent())
 
 	st.AlwaysRun(idl.Substep_check_active_connections_on_source_cluster, func(streams step.OutStreams) error {
 		return s.Source.CheckActiveConnections(streams)
-	})
+	}, step.Idempotent())
 
 	// We do not always run this cluster synchronization check
 	// because checking requires the source cluster to be available
@@ -59,14 +70,14 @@ func (s *Server) Execute(req *idl.ExecuteRequest, stream idl.CliToHub_ExecuteSer
 		}
 
 		return s.Source.WaitForClusterToBeReady()
-	})
+	}, step.Idempotent())
 
 	st.AlwaysRun(idl.Substep_shutdown_source_cluster, func(streams step.OutStreams) error {
 		return s.Source.Stop(streams)
-	})
+	}, step.Idempotent())
 
 	st.Run(idl.Substep_upgrade_master, func(streams step.OutStreams) error {
-		return UpgradeCoordinator(streams, s.BackupDirs.CoordinatorBackupDir, req.GetPgUpgradeVerbose(), req.GetSkipPgUpgradeChecks(), s.PgUpgradeJobs, s.Source, s.Intermediate, idl.PgOptions_upgrade, s.Mode)
+		return UpgradeCoordinator(ctx, streams, s.BackupDirs.CoordinatorBackupDir, req.GetPgUpgradeVerbose(), req.GetSkipPgUpgradeChecks(), s.PgUpgradeJobs, s.Source, s.Intermediate, idl.PgOptions_upgrade, s.Mode)
 	})
 
 	st.Run(idl.Substep_copy_master, func(streams step.OutStreams) error {
@@ -75,7 +86,7 @@ func (s *Server) Execute(req *idl.ExecuteRequest, stream idl.CliToHub_ExecuteSer
 		// to set the backup directory where it is used without needing to
 		// revert and re-run initialize and execute.
 		if req.GetParentBackupDirs() != "" {
-			err = DeleteBackupDirectories(streams, s.agentConns, s.BackupDirs)
+			err = DeleteBackupDirectories(ctx, streams, s.agentConns, s.BackupDirs)
 			if err != nil {
 				return err
 			}
@@ -90,7 +101,7 @@ func (s *Server) Execute(req *idl.ExecuteRequest, stream idl.CliToHub_ExecuteSer
 				return fmt.Errorf("save backup directories: %w", err)
 			}
 
-			err = CreateBackupDirectories(streams, s.agentConns, s.BackupDirs)
+			err = CreateBackupDirectories(ctx, streams, s.agentConns, s.BackupDirs)
 			if err != nil {
 				return err
 			}
@@ -111,12 +122,12 @@ single directory such as /dir. To specify different directories for each host
 use the form "host1:/dir1,host2:/dir2,host3:/dir3" where the first host must be 
 the master.`
 
-		err := CopyCoordinatorDataDir(streams, s.Intermediate.CoordinatorDataDir(), s.BackupDirs.AgentHostsToBackupDir)
+		err := CopyCoordinatorDataDir(ctx, streams, s.Intermediate.CoordinatorDataDir(), s.BackupDirs.AgentHostsToBackupDir)
 		if err != nil {
 			return utils.NewNextActionErr(err, nextAction)
 		}
 
-		err = CopyCoordinatorTablespaces(streams, s.Source.Version, s.Source.Tablespaces, s.BackupDirs.AgentHostsToBackupDir)
+		err = CopyCoordinatorTablespaces(ctx, streams, s.Source.Version, s.Source.Tablespaces, s.BackupDirs.AgentHostsToBackupDir)
 		if err != nil {
 			return utils.NewNextActionErr(err, nextAction)
 		}
@@ -125,12 +136,19 @@ the master.`
 	})
 
 	st.Run(idl.Substep_upgrade_primaries, func(streams step.OutStreams) error {
-		return UpgradePrimaries(s.agentConns, s.BackupDirs.AgentHostsToBackupDir, req.GetPgUpgradeVerbose(), req.GetSkipPgUpgradeChecks(), s.PgUpgradeJobs, s.Source, s.Intermediate, idl.PgOptions_upgrade, s.Mode)
+		store, err := NewSegmentFileStore()
+		if err != nil {
+			return err
+		}
+
+		return MonitorAgents(ctx, streams, s.agentConns, func(ctx context.Context) error {
+			return UpgradePrimaries(ctx, streams, store, s.agentConns, s.BackupDirs.AgentHostsToBackupDir, req.GetPgUpgradeVerbose(), req.GetSkipPgUpgradeChecks(), s.PgUpgradeJobs, s.MaxParallelSegments, s.MaxParallelHosts, s.Source, s.Intermediate, idl.PgOptions_upgrade, s.Mode)
+		})
 	})
 
 	st.AlwaysRun(idl.Substep_start_target_cluster, func(streams step.OutStreams) error {
 		return s.Intermediate.Start(streams)
-	})
+	}, step.Idempotent())
 
 	message := &idl.Message{Contents: &idl.Message_Response{Response: &idl.Response{Contents: &idl.Response_ExecuteResponse{
 		ExecuteResponse: &idl.ExecuteResponse{
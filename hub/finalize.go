@@ -17,7 +17,13 @@ import (
 )
 
 func (s *Server) Finalize(req *idl.FinalizeRequest, stream idl.CliToHub_FinalizeServer) (err error) {
-	st, err := step.Begin(idl.Step_finalize, stream)
+	ctx, unlock, err := s.lockStep(stream.Context(), idl.Step_finalize)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	st, err := step.Begin(ctx, idl.Step_finalize, stream)
 	if err != nil {
 		return err
 	}
@@ -33,7 +39,12 @@ func (s *Server) Finalize(req *idl.FinalizeRequest, stream idl.CliToHub_Finalize
 	}()
 
 	st.AlwaysRun(idl.Substep_ensure_gpupgrade_agents_are_running, func(_ step.OutStreams) error {
-		_, err := RestartAgents(context.Background(), nil, AgentHosts(s.Source), s.AgentPort, utils.GetStateDir())
+		l, err := s.Launcher()
+		if err != nil {
+			return err
+		}
+
+		_, err = RestartAgents(ctx, l, nil, AgentHosts(s.Source), s.AgentPort, s.AgentListenAddresses, utils.GetStateDir(), s.TLS)
 		if err != nil {
 			return err
 		}
@@ -44,14 +55,16 @@ func (s *Server) Finalize(req *idl.FinalizeRequest, stream idl.CliToHub_Finalize
 		}
 
 		return nil
-	})
+	}, step.Idempotent())
 
 	st.AlwaysRun(idl.Substep_check_active_connections_on_target_cluster, func(streams step.OutStreams) error {
 		return s.Intermediate.CheckActiveConnections(streams)
-	})
+	}, step.Idempotent())
 
 	st.RunConditionally(idl.Substep_upgrade_mirrors, s.Source.HasMirrors() && s.Mode == idl.Mode_link, func(streams step.OutStreams) error {
-		return UpgradeMirrorsUsingRsync(s.agentConns, s.Source, s.Intermediate, s.UseHbaHostnames)
+		return MonitorAgents(ctx, streams, s.agentConns, func(ctx context.Context) error {
+			return UpgradeMirrorsUsingRsync(ctx, s.agentConns, s.Source, s.Intermediate, s.UseHbaHostnames)
+		})
 	})
 
 	st.RunConditionally(idl.Substep_upgrade_mirrors, s.Source.HasMirrors() && s.Mode != idl.Mode_link, func(streams step.OutStreams) error {
@@ -64,11 +77,11 @@ func (s *Server) Finalize(req *idl.FinalizeRequest, stream idl.CliToHub_Finalize
 
 	st.Run(idl.Substep_wait_for_cluster_to_be_ready_after_adding_mirrors_and_standby, func(streams step.OutStreams) error {
 		return s.Intermediate.WaitForClusterToBeReady()
-	})
+	}, step.Idempotent())
 
 	st.AlwaysRun(idl.Substep_shutdown_target_cluster, func(streams step.OutStreams) error {
 		return s.Intermediate.Stop(streams)
-	})
+	}, step.Idempotent())
 
 	st.Run(idl.Substep_update_target_catalog, func(streams step.OutStreams) error {
 		if err := s.Intermediate.StartCoordinatorOnly(streams); err != nil {
@@ -83,11 +96,11 @@ func (s *Server) Finalize(req *idl.FinalizeRequest, stream idl.CliToHub_Finalize
 	})
 
 	st.Run(idl.Substep_update_data_directories, func(_ step.OutStreams) error {
-		return RenameDataDirectories(s.agentConns, s.Source, s.Intermediate)
+		return RenameDataDirectories(ctx, s.agentConns, s.Source, s.Intermediate)
 	})
 
 	st.Run(idl.Substep_update_target_conf_files, func(streams step.OutStreams) error {
-		return UpdateConfFiles(s.agentConns, streams,
+		return UpdateConfFiles(ctx, s.agentConns, streams,
 			s.Target.Version,
 			s.Intermediate,
 			s.Target,
@@ -96,11 +109,11 @@ func (s *Server) Finalize(req *idl.FinalizeRequest, stream idl.CliToHub_Finalize
 
 	st.AlwaysRun(idl.Substep_start_target_cluster, func(streams step.OutStreams) error {
 		return s.Target.Start(streams)
-	})
+	}, step.Idempotent())
 
 	st.AlwaysRun(idl.Substep_wait_for_cluster_to_be_ready_after_updating_catalog, func(streams step.OutStreams) error {
 		return s.Target.WaitForClusterToBeReady()
-	})
+	}, step.Idempotent())
 
 	st.Run(idl.Substep_analyze_target_cluster, func(streams step.OutStreams) error {
 		upgradeDir, err := utils.GetPgUpgradeDir(s.Target.Coordinator().Role, int32(s.Target.Coordinator().ContentID))
@@ -109,7 +122,7 @@ func (s *Server) Finalize(req *idl.FinalizeRequest, stream idl.CliToHub_Finalize
 		}
 
 		return s.Target.RunCmd(streams, filepath.Join(upgradeDir, "analyze_new_cluster.sh"))
-	})
+	}, step.Idempotent())
 
 	var logArchiveDir string
 	st.AlwaysRun(idl.Substep_archive_log_directories, func(_ step.OutStreams) error {
@@ -119,16 +132,16 @@ func (s *Server) Finalize(req *idl.FinalizeRequest, stream idl.CliToHub_Finalize
 		}
 
 		logArchiveDir = GetLogArchiveDir(logDir, s.UpgradeID, time.Now())
-		return ArchiveLogDirectories(logDir, logArchiveDir, s.agentConns, s.Config.Target.CoordinatorHostname())
-	})
+		return ArchiveLogDirectories(ctx, logDir, logArchiveDir, s.agentConns, s.Config.Target.CoordinatorHostname())
+	}, step.Idempotent())
 
 	st.Run(idl.Substep_delete_backupdir, func(streams step.OutStreams) error {
-		return DeleteBackupDirectories(streams, s.agentConns, s.BackupDirs)
-	})
+		return DeleteBackupDirectories(ctx, streams, s.agentConns, s.BackupDirs)
+	}, step.Idempotent())
 
 	st.AlwaysRun(idl.Substep_delete_segment_statedirs, func(_ step.OutStreams) error {
-		return DeleteStateDirectories(s.agentConns, s.Source.CoordinatorHostname())
-	})
+		return DeleteStateDirectories(ctx, s.agentConns, s.Source.CoordinatorHostname())
+	}, step.Idempotent())
 
 	message := &idl.Message{Contents: &idl.Message_Response{Response: &idl.Response{Contents: &idl.Response_FinalizeResponse{
 		FinalizeResponse: &idl.FinalizeResponse{
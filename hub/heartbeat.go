@@ -0,0 +1,141 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+)
+
+const (
+	defaultHeartbeatInterval = 10 * time.Second
+	defaultHeartbeatTimeout  = 30 * time.Second
+)
+
+// heartbeatInterval is how often agents send heartbeats. An agent is lost when
+// no heartbeat is received within heartbeatTimeout.
+var heartbeatInterval = defaultHeartbeatInterval
+var heartbeatTimeout = defaultHeartbeatTimeout
+
+func SetHeartbeat(interval time.Duration, timeout time.Duration) {
+	heartbeatInterval = interval
+	heartbeatTimeout = timeout
+}
+
+func ResetHeartbeat() {
+	heartbeatInterval = defaultHeartbeatInterval
+	heartbeatTimeout = defaultHeartbeatTimeout
+}
+
+// MonitorAgents runs f while monitoring the heartbeats of the agents. When an
+// agent is lost the context passed to f is canceled such that long running
+// substeps fail fast rather than waiting on a host that may have rebooted, and
+// the client is notified.
+func MonitorAgents(ctx context.Context, streams step.OutStreams, agentConns []*idl.Connection, f func(context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+
+	type lostAgent struct {
+		host string
+		err  error
+	}
+
+	lost := make(chan lostAgent, len(agentConns))
+	for _, conn := range agentConns {
+		conn := conn
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := monitorAgent(ctx, conn)
+			if err == nil || ctx.Err() != nil {
+				return
+			}
+
+			log.Printf("agent on host %s lost: %v", conn.Hostname, err)
+			lost <- lostAgent{host: conn.Hostname, err: err}
+			cancel()
+		}()
+	}
+
+	err := f(ctx)
+	if err == nil {
+		return nil
+	}
+
+	select {
+	case agent := <-lost:
+		step.SendAgentLost(streams, &idl.AgentLost{Host: agent.host})
+		return xerrors.Errorf("agent on host %s lost: %w", agent.host, agent.err)
+	default:
+		return err
+	}
+}
+
+// monitorAgent returns an error once the agent stops sending heartbeats, and
+// nil once the context is canceled.
+func monitorAgent(ctx context.Context, conn *idl.Connection) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := conn.AgentClient.Heartbeat(ctx, &idl.HeartbeatRequest{Interval: durationpb.New(heartbeatInterval)})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		return err
+	}
+
+	heartbeats := make(chan error)
+	go func() {
+		for {
+			_, err := stream.Recv()
+
+			select {
+			case heartbeats <- err:
+			case <-ctx.Done():
+				return
+			}
+
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	timer := time.NewTimer(heartbeatTimeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case err := <-heartbeats:
+			if err != nil {
+				return xerrors.Errorf("heartbeat stream: %w", err)
+			}
+
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(heartbeatTimeout)
+
+		case <-timer.C:
+			return xerrors.Errorf("no heartbeat received in %s", heartbeatTimeout)
+
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
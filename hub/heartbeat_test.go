@@ -0,0 +1,186 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+)
+
+func TestMonitorAgents(t *testing.T) {
+	testlog.SetupTestLogger()
+
+	hub.SetHeartbeat(10*time.Millisecond, 100*time.Millisecond)
+	defer hub.ResetHeartbeat()
+
+	// agentWithHeartbeats returns an agent that sends the number of heartbeats
+	// and then either returns the error or stops sending heartbeats until the
+	// test finishes.
+	agentWithHeartbeats := func(t *testing.T, ctrl *gomock.Controller, heartbeats int, err error) *mock_idl.MockAgentClient {
+		done := make(chan struct{})
+		t.Cleanup(func() { close(done) })
+
+		stream := mock_idl.NewMockAgent_HeartbeatClient(ctrl)
+		stream.EXPECT().Recv().Return(&idl.HeartbeatReply{}, nil).Times(heartbeats)
+		stream.EXPECT().Recv().DoAndReturn(func() (*idl.HeartbeatReply, error) {
+			if err != nil {
+				return nil, err
+			}
+
+			<-done
+			return nil, context.Canceled
+		}).AnyTimes()
+
+		client := mock_idl.NewMockAgentClient(ctrl)
+		client.EXPECT().Heartbeat(
+			gomock.Any(),
+			&idl.HeartbeatRequest{Interval: durationpb.New(10 * time.Millisecond)},
+		).Return(stream, nil)
+
+		return client
+	}
+
+	// healthyAgent sends heartbeats until the test finishes.
+	healthyAgent := func(t *testing.T, ctrl *gomock.Controller) *mock_idl.MockAgentClient {
+		done := make(chan struct{})
+		t.Cleanup(func() { close(done) })
+
+		stream := mock_idl.NewMockAgent_HeartbeatClient(ctrl)
+		stream.EXPECT().Recv().DoAndReturn(func() (*idl.HeartbeatReply, error) {
+			select {
+			case <-time.After(10 * time.Millisecond):
+				return &idl.HeartbeatReply{}, nil
+			case <-done:
+				return nil, context.Canceled
+			}
+		}).AnyTimes()
+
+		client := mock_idl.NewMockAgentClient(ctrl)
+		client.EXPECT().Heartbeat(gomock.Any(), gomock.Any()).Return(stream, nil).AnyTimes()
+
+		return client
+	}
+
+	waitForCancel := func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(10 * time.Second):
+			return errors.New("the context was not canceled")
+		}
+	}
+
+	t.Run("returns the result of the function while the agents are alive", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		agentConns := []*idl.Connection{
+			{AgentClient: healthyAgent(t, ctrl), Hostname: "sdw1"},
+			{AgentClient: healthyAgent(t, ctrl), Hostname: "sdw2"},
+		}
+
+		streams := &agentLostStreams{}
+		err := hub.MonitorAgents(context.Background(), streams, agentConns, func(ctx context.Context) error {
+			time.Sleep(200 * time.Millisecond)
+			return ctx.Err()
+		})
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		expected := errors.New("permission denied")
+		err = hub.MonitorAgents(context.Background(), streams, agentConns, func(ctx context.Context) error {
+			return expected
+		})
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+
+		if len(streams.lost) != 0 {
+			t.Errorf("got lost agents %v want none", streams.lost)
+		}
+	})
+
+	t.Run("fails fast and notifies the client when an agent stops sending heartbeats", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		agentConns := []*idl.Connection{
+			{AgentClient: healthyAgent(t, ctrl), Hostname: "sdw1"},
+			{AgentClient: agentWithHeartbeats(t, ctrl, 2, nil), Hostname: "sdw2"},
+		}
+
+		streams := &agentLostStreams{}
+		err := hub.MonitorAgents(context.Background(), streams, agentConns, waitForCancel)
+
+		expected := "agent on host sdw2 lost: no heartbeat received in 100ms"
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %v want %q", err, expected)
+		}
+
+		expectedLost := []*idl.AgentLost{{Host: "sdw2"}}
+		if !reflect.DeepEqual(streams.lost, expectedLost) {
+			t.Errorf("got lost agents %v want %v", streams.lost, expectedLost)
+		}
+	})
+
+	t.Run("fails fast when the heartbeat stream of an agent breaks", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		streamErr := errors.New("connection reset by peer")
+		agentConns := []*idl.Connection{
+			{AgentClient: agentWithHeartbeats(t, ctrl, 1, streamErr), Hostname: "sdw1"},
+		}
+
+		streams := &agentLostStreams{}
+		err := hub.MonitorAgents(context.Background(), streams, agentConns, waitForCancel)
+		if !errors.Is(err, streamErr) {
+			t.Errorf("got error %#v want %#v", err, streamErr)
+		}
+
+		expectedLost := []*idl.AgentLost{{Host: "sdw1"}}
+		if !reflect.DeepEqual(streams.lost, expectedLost) {
+			t.Errorf("got lost agents %v want %v", streams.lost, expectedLost)
+		}
+	})
+
+	t.Run("fails fast when the heartbeat stream of an agent cannot be opened", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expected := errors.New("connection refused")
+		client := mock_idl.NewMockAgentClient(ctrl)
+		client.EXPECT().Heartbeat(gomock.Any(), gomock.Any()).Return(nil, expected)
+
+		agentConns := []*idl.Connection{{AgentClient: client, Hostname: "sdw1"}}
+
+		err := hub.MonitorAgents(context.Background(), step.DevNullStream, agentConns, waitForCancel)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+}
+
+// agentLostStreams records the lost agents the client is notified of.
+type agentLostStreams struct {
+	step.BufferedStreams
+	lost []*idl.AgentLost
+}
+
+func (s *agentLostStreams) SendAgentLost(lost *idl.AgentLost) {
+	s.lost = append(s.lost, lost)
+}
@@ -6,42 +6,26 @@ package hub_test
 import (
 	"context"
 	"errors"
-	"fmt"
 	"log"
 	"net"
-	"os"
-	"os/exec"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/test/bufconn"
 
 	"github.com/greenplum-db/gpupgrade/agent"
+	"github.com/greenplum-db/gpupgrade/config/listenaddress"
 	"github.com/greenplum-db/gpupgrade/hub"
 	"github.com/greenplum-db/gpupgrade/idl"
-	"github.com/greenplum-db/gpupgrade/testutils"
-	"github.com/greenplum-db/gpupgrade/testutils/exectest"
 	"github.com/greenplum-db/gpupgrade/testutils/testlog"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+	"github.com/greenplum-db/gpupgrade/utils/mtls"
 )
 
-func gpupgrade_agent() {
-}
-
-func gpupgrade_agent_Errors() {
-	os.Stderr.WriteString("could not find state-directory")
-	os.Exit(1)
-}
-
-func init() {
-	exectest.RegisterMains(
-		gpupgrade_agent,
-		gpupgrade_agent_Errors,
-	)
-}
-
 func TestRestartAgent(t *testing.T) {
 	testlog.SetupTestLogger()
 	listener := bufconn.Listen(1024 * 1024)
@@ -60,21 +44,22 @@ func TestRestartAgent(t *testing.T) {
 	stateDir := "/not/existent/directory"
 	ctx := context.Background()
 
-	hub.SetExecCommand(exectest.NewCommand(gpupgrade_agent))
-	defer hub.ResetExecCommand()
-
 	t.Run("does not start running agents", func(t *testing.T) {
 		dialer := func(ctx context.Context, address string) (net.Conn, error) {
 			return listener.Dial()
 		}
 
-		restartedHosts, err := hub.RestartAgents(ctx, dialer, hostnames, port, stateDir)
+		l := &fakeLauncher{}
+		restartedHosts, err := hub.RestartAgents(ctx, l, dialer, hostnames, port, nil, stateDir, mtls.Config{})
 		if err != nil {
 			t.Errorf("returned %#v", err)
 		}
 		if len(restartedHosts) != 0 {
 			t.Errorf("restarted hosts %v", restartedHosts)
 		}
+		if len(l.started) != 0 {
+			t.Errorf("started agents on %v", l.started)
+		}
 	})
 
 	t.Run("only restarts down agents", func(t *testing.T) {
@@ -88,7 +73,7 @@ func TestRestartAgent(t *testing.T) {
 			return listener.Dial()
 		}
 
-		restartedHosts, err := hub.RestartAgents(ctx, dialer, hostnames, port, stateDir)
+		restartedHosts, err := hub.RestartAgents(ctx, &fakeLauncher{}, dialer, hostnames, port, nil, stateDir, mtls.Config{})
 		if err != nil {
 			t.Errorf("returned %#v", err)
 		}
@@ -102,16 +87,16 @@ func TestRestartAgent(t *testing.T) {
 		}
 	})
 
-	t.Run("returns an error when gpupgrade agent fails", func(t *testing.T) {
-		hub.SetExecCommand(exectest.NewCommand(gpupgrade_agent_Errors))
+	t.Run("returns an error when starting the agents fails", func(t *testing.T) {
+		expected := errors.New("could not find state-directory")
 
-		// we fail all connections here so that RestartAgents will run the
-		//  (error producing) gpupgrade_agent_Errors
+		// we fail all connections here so that RestartAgents will start the
+		// agents using the failing launcher
 		dialer := func(ctx context.Context, address string) (net.Conn, error) {
 			return nil, immediateFailure{}
 		}
 
-		restartedHosts, err := hub.RestartAgents(ctx, dialer, hostnames, port, stateDir)
+		restartedHosts, err := hub.RestartAgents(ctx, &fakeLauncher{startErr: expected}, dialer, hostnames, port, nil, stateDir, mtls.Config{})
 		if err == nil {
 			t.Errorf("expected restart agents to fail")
 		}
@@ -125,10 +110,9 @@ func TestRestartAgent(t *testing.T) {
 			t.Errorf("expected 2 errors, got %d", len(errs))
 		}
 
-		var exitErr *exec.ExitError
 		for _, err := range errs {
-			if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
-				t.Errorf("expected exit code: 1 but got: %#v", err)
+			if !errors.Is(err, expected) {
+				t.Errorf("got error %#v want %#v", err, expected)
 			}
 		}
 
@@ -140,32 +124,78 @@ func TestRestartAgent(t *testing.T) {
 	t.Run("starts agents with correct args including specified port and state directory", func(t *testing.T) {
 		host := "host1"
 
-		execCmd := exectest.NewCommandWithVerifier(gpupgrade_agent, func(name string, args ...string) {
-			if name != "ssh" {
-				t.Errorf("RestartAgents invoked with %q want ssh", name)
+		dialer := func(ctx context.Context, address string) (net.Conn, error) {
+			if strings.HasPrefix(address, host) { // fail connection attempts to host
+				return nil, immediateFailure{}
 			}
 
-			cmd := fmt.Sprintf("bash -c \"%s/gpupgrade agent --daemonize --port %d --state-directory %s\"", testutils.MustGetExecutablePath(t), port, stateDir)
-			expected := []string{host, cmd}
-			if !reflect.DeepEqual(args, expected) {
-				t.Errorf("got %q want %q", args, expected)
-			}
-		})
-		hub.SetExecCommand(execCmd)
-		defer hub.ResetExecCommand()
+			return listener.Dial()
+		}
+
+		l := &fakeLauncher{}
+		_, err := hub.RestartAgents(ctx, l, dialer, hostnames, port, nil, stateDir, mtls.Config{})
+		if err != nil {
+			t.Errorf("unexpected errr %#v", err)
+		}
+
+		expected := map[string][]string{host: {"--port", "1234", "--state-directory", stateDir}}
+		if !reflect.DeepEqual(l.started, expected) {
+			t.Errorf("got %q want %q", l.started, expected)
+		}
+	})
+
+	t.Run("starts agents with the listen address when specified", func(t *testing.T) {
+		host := "host1"
+		listenAddresses := listenaddress.AgentListenAddresses{host: "10.0.0.1", "host2": "10.0.0.2"}
 
 		dialer := func(ctx context.Context, address string) (net.Conn, error) {
-			if strings.HasPrefix(address, host) { // fail connection attempts to host
+			if strings.HasPrefix(address, "10.0.0.1") { // fail connection attempts to host
 				return nil, immediateFailure{}
 			}
 
 			return listener.Dial()
 		}
 
-		_, err := hub.RestartAgents(ctx, dialer, hostnames, port, stateDir)
+		l := &fakeLauncher{}
+		_, err := hub.RestartAgents(ctx, l, dialer, hostnames, port, listenAddresses, stateDir, mtls.Config{})
 		if err != nil {
 			t.Errorf("unexpected errr %#v", err)
 		}
+
+		expected := map[string][]string{host: {"--port", "1234", "--state-directory", stateDir, "--listen-address", "10.0.0.1"}}
+		if !reflect.DeepEqual(l.started, expected) {
+			t.Errorf("got %q want %q", l.started, expected)
+		}
+	})
+
+	t.Run("dials agents on their listen address when specified", func(t *testing.T) {
+		listenAddresses := listenaddress.AgentListenAddresses{"host1": "10.0.0.1"}
+
+		var mutex sync.Mutex
+		var dialed []string
+		dialer := func(ctx context.Context, address string) (net.Conn, error) {
+			mutex.Lock()
+			dialed = append(dialed, address)
+			mutex.Unlock()
+
+			return listener.Dial()
+		}
+
+		l := &fakeLauncher{}
+		restartedHosts, err := hub.RestartAgents(ctx, l, dialer, hostnames, port, listenAddresses, stateDir, mtls.Config{})
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		if len(restartedHosts) != 0 {
+			t.Errorf("restarted hosts %v", restartedHosts)
+		}
+
+		sort.Strings(dialed)
+		expected := []string{"10.0.0.1:1234", "host2:1234"}
+		if !reflect.DeepEqual(dialed, expected) {
+			t.Errorf("dialed %q want %q", dialed, expected)
+		}
 	})
 }
 
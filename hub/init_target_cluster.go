@@ -5,6 +5,7 @@ package hub
 
 import (
 	"bufio"
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
@@ -56,7 +57,7 @@ func (s *Server) writeConf(db *sql.DB) error {
 	return WriteInitsystemFile(gpinitsystemConfig, utils.GetInitsystemConfig())
 }
 
-func (s *Server) RemoveIntermediateCluster(streams step.OutStreams) error {
+func (s *Server) RemoveIntermediateCluster(ctx context.Context, streams step.OutStreams) error {
 	if reflect.DeepEqual(s.Intermediate, greenplum.Cluster{}) {
 		return nil
 	}
@@ -69,7 +70,7 @@ func (s *Server) RemoveIntermediateCluster(streams step.OutStreams) error {
 		return err
 	}
 
-	err := DeleteCoordinatorAndPrimaryDataDirectories(streams, s.agentConns, s.Intermediate)
+	err := DeleteCoordinatorAndPrimaryDataDirectories(ctx, streams, s.agentConns, s.Intermediate)
 	if err != nil {
 		return xerrors.Errorf("deleting target cluster data directories: %w", err)
 	}
@@ -4,9 +4,11 @@
 package hub
 
 import (
-	"context"
+	"errors"
+	"fmt"
 	"log"
 
+	"github.com/greenplum-db/gpupgrade/agent/launcher"
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/step"
 	"github.com/greenplum-db/gpupgrade/upgrade"
@@ -15,7 +17,13 @@ import (
 )
 
 func (s *Server) Initialize(req *idl.InitializeRequest, stream idl.CliToHub_InitializeServer) (err error) {
-	st, err := step.Begin(idl.Step_initialize, stream)
+	ctx, unlock, err := s.lockStep(stream.Context(), idl.Step_initialize)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	st, err := step.Begin(ctx, idl.Step_initialize, stream)
 	if err != nil {
 		return err
 	}
@@ -30,13 +38,13 @@ func (s *Server) Initialize(req *idl.InitializeRequest, stream idl.CliToHub_Init
 		}
 	}()
 
-	// Since the agents might not be up if gpupgrade is not properly installed, check it early on using ssh.
-	st.Run(idl.Substep_verify_gpupgrade_is_installed_across_all_hosts, func(streams step.OutStreams) error {
-		return upgrade.EnsureGpupgradeVersionsMatch(AgentHosts(s.Source))
-	})
-
 	st.AlwaysRun(idl.Substep_start_agents, func(_ step.OutStreams) error {
-		_, err := RestartAgents(context.Background(), nil, AgentHosts(s.Source), s.AgentPort, utils.GetStateDir())
+		l, err := s.Launcher()
+		if err != nil {
+			return err
+		}
+
+		_, err = RestartAgents(ctx, l, nil, AgentHosts(s.Source), s.AgentPort, s.AgentListenAddresses, utils.GetStateDir(), s.TLS)
 		if err != nil {
 			return err
 		}
@@ -47,14 +55,29 @@ func (s *Server) Initialize(req *idl.InitializeRequest, stream idl.CliToHub_Init
 		}
 
 		return nil
-	})
+	}, step.Idempotent())
+
+	st.Run(idl.Substep_verify_gpupgrade_is_installed_across_all_hosts, func(streams step.OutStreams) error {
+		return EnsureAgentVersionsMatch(ctx, s.agentConns)
+	}, step.Idempotent())
 
 	st.AlwaysRun(idl.Substep_check_environment, func(streams step.OutStreams) error {
-		return CheckEnvironment(append(AgentHosts(s.Source), s.Source.CoordinatorHostname()), s.Source.GPHome, s.Intermediate.GPHome)
-	})
+		l, err := s.Launcher()
+		if err != nil {
+			return err
+		}
+
+		err = CheckEnvironment(l, append(AgentHosts(s.Source), s.Source.CoordinatorHostname()), s.Source.GPHome, s.Intermediate.GPHome)
+		if errors.Is(err, launcher.ErrCommandsNotSupported) {
+			fmt.Fprintf(streams.Stdout(), "Skipping checking that PATH and LD_LIBRARY_PATH do not contain GPHOME on the hosts since agent_launcher %q does not run commands on them.\n", s.AgentLauncher)
+			return step.Skip
+		}
+
+		return err
+	}, step.Idempotent())
 
 	st.Run(idl.Substep_create_backupdirs, func(streams step.OutStreams) error {
-		err = CreateBackupDirectories(streams, s.agentConns, s.BackupDirs)
+		err = CreateBackupDirectories(ctx, streams, s.agentConns, s.BackupDirs)
 		if err != nil {
 			nextAction := `1. Run "gpupgrade revert"
 
@@ -79,17 +102,27 @@ the master.
 		// The following CheckDiskSpace encapsulates the backup directory since
 		// it usually is on the same filesystem as the data directories.
 		return nil
-	})
+	}, step.Idempotent())
 
-	st.RunConditionally(idl.Substep_check_disk_space, req.GetDiskFreeRatio() > 0, func(streams step.OutStreams) error {
-		return CheckDiskSpace(streams, s.agentConns, req.GetDiskFreeRatio(), s.Source, s.Source.Tablespaces)
-	})
+	st.RunConditionally(idl.Substep_check_disk_space, req.GetMeasureDiskSpace() || req.GetDiskFreeRatio() > 0, func(streams step.OutStreams) error {
+		if req.GetMeasureDiskSpace() {
+			return CheckMeasuredDiskSpace(ctx, streams, s.agentConns, s.Mode, s.Source, s.Source.Tablespaces, s.BackupDirs)
+		}
+
+		return CheckDiskSpace(ctx, streams, s.agentConns, req.GetDiskFreeRatio(), s.Source, s.Source.Tablespaces)
+	}, step.Idempotent())
 
 	return st.Err()
 }
 
 func (s *Server) InitializeCreateCluster(req *idl.InitializeCreateClusterRequest, stream idl.CliToHub_InitializeCreateClusterServer) (err error) {
-	st, err := step.Begin(idl.Step_initialize, stream)
+	ctx, unlock, err := s.lockStep(stream.Context(), idl.Step_initialize)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	st, err := step.Begin(ctx, idl.Step_initialize, stream)
 	if err != nil {
 		return err
 	}
@@ -106,10 +139,21 @@ func (s *Server) InitializeCreateCluster(req *idl.InitializeCreateClusterRequest
 
 	st.Run(idl.Substep_generate_target_config, func(_ step.OutStreams) error {
 		return s.GenerateInitsystemConfig(s.Source)
-	})
+	}, step.Idempotent())
+
+	// Only check the ports before the target cluster is created since it holds
+	// the ports afterwards.
+	st.Run(idl.Substep_check_target_cluster_ports, func(_ step.OutStreams) error {
+		agentConns, err := s.AgentConns()
+		if err != nil {
+			return err
+		}
+
+		return CheckTargetClusterPorts(ctx, agentConns, s.Intermediate)
+	}, step.Idempotent())
 
 	st.Run(idl.Substep_init_target_cluster, func(stream step.OutStreams) error {
-		err := s.RemoveIntermediateCluster(stream)
+		err := s.RemoveIntermediateCluster(ctx, stream)
 		if err != nil {
 			return err
 		}
@@ -137,18 +181,18 @@ func (s *Server) InitializeCreateCluster(req *idl.InitializeCreateClusterRequest
 
 	st.AlwaysRun(idl.Substep_shutdown_target_cluster, func(stream step.OutStreams) error {
 		return s.Intermediate.Stop(stream)
-	})
+	}, step.Idempotent())
 
 	st.Run(idl.Substep_backup_target_master, func(stream step.OutStreams) error {
 		sourceDir := s.Intermediate.CoordinatorDataDir()
 		targetDir := utils.GetCoordinatorPreUpgradeBackupDir(s.BackupDirs.CoordinatorBackupDir)
 
-		return RsyncCoordinatorDataDir(stream, sourceDir, targetDir)
+		return RsyncCoordinatorDataDir(ctx, stream, sourceDir, targetDir)
 	})
 
 	st.AlwaysRun(idl.Substep_initialize_wait_for_cluster_to_be_ready, func(streams step.OutStreams) error {
 		return s.Source.WaitForClusterToBeReady()
-	})
+	}, step.Idempotent())
 
 	st.AlwaysRun(idl.Substep_check_upgrade, func(stream step.OutStreams) error {
 		if req.GetSkipPgUpgradeChecks() {
@@ -156,12 +200,17 @@ func (s *Server) InitializeCreateCluster(req *idl.InitializeCreateClusterRequest
 			return nil
 		}
 
-		if err := UpgradeCoordinator(stream, s.BackupDirs.CoordinatorBackupDir, req.GetPgUpgradeVerbose(), req.GetSkipPgUpgradeChecks(), s.PgUpgradeJobs, s.Source, s.Intermediate, idl.PgOptions_check, s.Mode); err != nil {
+		if err := UpgradeCoordinator(ctx, stream, s.BackupDirs.CoordinatorBackupDir, req.GetPgUpgradeVerbose(), req.GetSkipPgUpgradeChecks(), s.PgUpgradeJobs, s.Source, s.Intermediate, idl.PgOptions_check, s.Mode); err != nil {
 			return err
 		}
 
-		return UpgradePrimaries(s.agentConns, s.BackupDirs.AgentHostsToBackupDir, req.GetPgUpgradeVerbose(), req.GetSkipPgUpgradeChecks(), s.PgUpgradeJobs, s.Source, s.Intermediate, idl.PgOptions_check, s.Mode)
-	})
+		store, err := NewSegmentFileStore()
+		if err != nil {
+			return err
+		}
+
+		return UpgradePrimaries(ctx, stream, store, s.agentConns, s.BackupDirs.AgentHostsToBackupDir, req.GetPgUpgradeVerbose(), req.GetSkipPgUpgradeChecks(), s.PgUpgradeJobs, s.MaxParallelSegments, s.MaxParallelHosts, s.Source, s.Intermediate, idl.PgOptions_check, s.Mode)
+	}, step.Idempotent())
 
 	message := &idl.Message{Contents: &idl.Message_Response{Response: &idl.Response{Contents: &idl.Response_InitializeResponse{
 		InitializeResponse: &idl.InitializeResponse{
@@ -0,0 +1,88 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/metrics"
+	"github.com/greenplum-db/gpupgrade/utils/rsync"
+)
+
+var agentRPCDurationMetric = metrics.Factory.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "gpupgrade_agent_rpc_duration_seconds",
+	Help:    "Latency in seconds of each RPC method from the hub to each agent.",
+	Buckets: metrics.DefaultBuckets,
+}, []string{"host", "method"})
+
+var agentRPCErrorsMetric = metrics.Factory.NewCounterVec(prometheus.CounterOpts{
+	Name: "gpupgrade_agent_rpc_errors_total",
+	Help: "Number of RPCs of each method from the hub to each agent that returned an error.",
+}, []string{"host", "method"})
+
+var pgUpgradeDurationMetric = metrics.Factory.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "gpupgrade_pg_upgrade_duration_seconds",
+	Help: "Duration in seconds of the most recent pg_upgrade run for each segment.",
+}, []string{"host", "content", "action"})
+
+var rsyncDurationMetric = metrics.Factory.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "gpupgrade_rsync_duration_seconds",
+	Help:    "Duration in seconds of rsync runs on each host.",
+	Buckets: metrics.DefaultBuckets,
+}, []string{"host"})
+
+var rsyncTransferredBytesMetric = metrics.Factory.NewCounterVec(prometheus.CounterOpts{
+	Name: "gpupgrade_rsync_transferred_bytes_total",
+	Help: "Total size in bytes of files transferred by rsync on each host. Only rsync runs using --stats are counted.",
+}, []string{"host"})
+
+// recordAgentRPC records each attempt of the RPC such as "CheckDiskSpace"
+// made by the agent client interceptors.
+func recordAgentRPC(host string, method string, duration time.Duration, err error) {
+	agentRPCDurationMetric.WithLabelValues(host, method).Observe(duration.Seconds())
+	if err != nil {
+		agentRPCErrorsMetric.WithLabelValues(host, method).Inc()
+	}
+}
+
+func recordPgUpgrade(host string, contentID int32, action idl.PgOptions_Action, duration time.Duration) {
+	pgUpgradeDurationMetric.WithLabelValues(host, strconv.Itoa(int(contentID)), action.String()).Set(duration.Seconds())
+}
+
+func recordRsyncStats(host string, stats rsync.Stats) {
+	rsyncDurationMetric.WithLabelValues(host).Observe(stats.Duration.Seconds())
+	rsyncTransferredBytesMetric.WithLabelValues(host).Add(float64(stats.TransferredBytes))
+}
+
+// recordAgentRsyncStats records rsync statistics relayed from an agent since
+// only the hub serves metrics.
+func recordAgentRsyncStats(host string, stats []*idl.RsyncStats) {
+	for _, s := range stats {
+		recordRsyncStats(host, rsync.Stats{
+			TransferredBytes: s.GetTransferredBytes(),
+			Duration:         time.Duration(s.GetDurationSeconds() * float64(time.Second)),
+		})
+	}
+}
+
+// runRsync runs rsync on the hub host and records its statistics.
+func runRsync(options ...rsync.Option) error {
+	var stats rsync.Stats
+	err := rsync.Rsync(append(options, rsync.WithStats(&stats))...)
+
+	host, hostErr := utils.System.Hostname()
+	if hostErr != nil {
+		log.Printf("get hostname for rsync metrics: %v", hostErr)
+		return err
+	}
+
+	recordRsyncStats(host, stats)
+	return err
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/golang/mock/gomock"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/utils/metrics"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Run("records the pg_upgrade runtime of each segment returned by the agent", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		source := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "metrics-sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		})
+
+		intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 60432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "metrics-sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 60434, Role: greenplum.PrimaryRole},
+		})
+		intermediate.Version = semver.MustParse("6.0.0")
+
+		client := mock_idl.NewMockAgentClient(ctrl)
+		client.EXPECT().UpgradePrimaries(gomock.Any(), gomock.Any()).Return(mockUpgradePrimariesStream(ctrl,
+			&idl.UpgradePrimariesReply{Contents: &idl.UpgradePrimariesReply_Result_{
+				Result: &idl.UpgradePrimariesReply_Result{ContentID: 0, DurationSeconds: 42.5},
+			}},
+		), nil)
+
+		agentConns := []*idl.Connection{{AgentClient: client, Hostname: "metrics-sdw1"}}
+
+		err := hub.UpgradePrimaries(context.Background(), step.DevNullStream, newSegmentStore(), agentConns, map[string]string{"metrics-sdw1": "/data/.gpupgrade"}, false, false, 1, 0, 0, source, intermediate, idl.PgOptions_upgrade, idl.Mode_link)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := `gpupgrade_pg_upgrade_duration_seconds{action="upgrade",content="0",host="metrics-sdw1"} 42.5` + "\n"
+		output := mustWriteMetrics(t)
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected metrics to contain %q got %q", expected, output)
+		}
+	})
+
+	t.Run("records rsync statistics returned by the agent", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		source := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "metrics-sdw3", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+			{DbID: 3, ContentID: 0, Hostname: "metrics-sdw4", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+		})
+
+		client := mock_idl.NewMockAgentClient(ctrl)
+		client.EXPECT().RsyncDataDirectories(gomock.Any(), gomock.Any()).Return(&idl.RsyncReply{
+			Stats: []*idl.RsyncStats{{TransferredBytes: 2048, DurationSeconds: 3}},
+		}, nil)
+
+		agentConns := []*idl.Connection{{AgentClient: client, Hostname: "metrics-sdw3"}}
+
+		err := hub.RsyncMirrorDataDirsOnSegments(context.Background(), agentConns, source, source)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		output := mustWriteMetrics(t)
+
+		expected := []string{
+			`gpupgrade_rsync_transferred_bytes_total{host="metrics-sdw3"} 2048`,
+			`gpupgrade_rsync_duration_seconds_sum{host="metrics-sdw3"} 3`,
+		}
+		for _, line := range expected {
+			if !strings.Contains(output, line+"\n") {
+				t.Errorf("expected metrics to contain %q got %q", line, output)
+			}
+		}
+	})
+}
+
+func mustWriteMetrics(t *testing.T) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := metrics.Write(&buf); err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	return buf.String()
+}
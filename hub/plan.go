@@ -0,0 +1,341 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/config/backupdir"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/upgrade"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+// The plans below mirror the substeps of Execute, Finalize, and Revert
+// including the conditions under which they run. Any change to those steps
+// should be reflected here so that "--dry-run" remains accurate.
+// TestPlansMatchSteps fails when they diverge.
+
+// ExecutePlan returns the substeps execute would run without running them.
+func ExecutePlan(conf *config.Config, stateDir string, parentBackupDirs string) (*step.Plan, error) {
+	if conf.Source == nil || conf.Intermediate == nil {
+		return nil, missingClusterErr(idl.Step_execute)
+	}
+
+	backupDirs := conf.BackupDirs
+	if parentBackupDirs != "" {
+		var err error
+		backupDirs, err = backupdir.ParseParentBackupDirs(parentBackupDirs, *conf.Source)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	plan := step.NewPlan(idl.Step_execute, planStore(stateDir))
+
+	plan.AlwaysRun(idl.Substep_ensure_gpupgrade_agents_are_running, agentsDetail(conf))
+	plan.AlwaysRun(idl.Substep_check_active_connections_on_source_cluster, "source coordinator "+segmentDetail(conf.Source.Coordinator()))
+	plan.Run(idl.Substep_wait_for_cluster_to_be_ready_before_upgrade_master, "start source cluster "+segmentDetail(conf.Source.Coordinator()))
+	plan.AlwaysRun(idl.Substep_shutdown_source_cluster, "stop source cluster "+segmentDetail(conf.Source.Coordinator()))
+
+	plan.Run(idl.Substep_upgrade_master,
+		fmt.Sprintf("pg_upgrade in %s mode with %d jobs", conf.Mode, conf.PgUpgradeJobs),
+		"source "+segmentDetail(conf.Source.Coordinator()),
+		"target "+segmentDetail(conf.Intermediate.Coordinator()),
+		"backup directory "+backupDirs.CoordinatorBackupDir,
+	)
+
+	var copyDetails []string
+	for _, host := range sortedHosts(backupDirs.AgentHostsToBackupDir) {
+		copyDetails = append(copyDetails, fmt.Sprintf("copy %s to %s:%s", conf.Intermediate.CoordinatorDataDir(), host, backupDirs.AgentHostsToBackupDir[host]))
+	}
+	for _, location := range conf.Source.Tablespaces.GetCoordinatorTablespaces().UserDefinedTablespacesLocations() {
+		copyDetails = append(copyDetails, fmt.Sprintf("copy coordinator tablespace %s to all hosts", location))
+	}
+	plan.Run(idl.Substep_copy_master, copyDetails...)
+
+	var primaryDetails []string
+	for _, seg := range sortedSegments(conf.Source.Primaries.ExcludingCoordinator()) {
+		target := conf.Intermediate.Primaries[seg.ContentID]
+		primaryDetails = append(primaryDetails, fmt.Sprintf("%s: %s -> %s port %d", seg.Hostname, seg.DataDir, target.DataDir, target.Port))
+	}
+	plan.Run(idl.Substep_upgrade_primaries, primaryDetails...)
+
+	plan.AlwaysRun(idl.Substep_start_target_cluster, "start intermediate cluster "+segmentDetail(conf.Intermediate.Coordinator()))
+
+	return plan, plan.Err()
+}
+
+// FinalizePlan returns the substeps finalize would run without running them.
+func FinalizePlan(conf *config.Config, stateDir string) (*step.Plan, error) {
+	if conf.Source == nil || conf.Intermediate == nil || conf.Target == nil {
+		return nil, missingClusterErr(idl.Step_finalize)
+	}
+
+	plan := step.NewPlan(idl.Step_finalize, planStore(stateDir))
+
+	plan.AlwaysRun(idl.Substep_ensure_gpupgrade_agents_are_running, agentsDetail(conf))
+	plan.AlwaysRun(idl.Substep_check_active_connections_on_target_cluster, "intermediate coordinator "+segmentDetail(conf.Intermediate.Coordinator()))
+
+	var rsyncDetails, addMirrorDetails []string
+	for _, mirror := range sortedSegments(conf.Intermediate.Mirrors.ExcludingStandby()) {
+		primary := conf.Intermediate.Primaries[mirror.ContentID]
+		rsyncDetails = append(rsyncDetails, fmt.Sprintf("rsync %s:%s to %s", primary.Hostname, primary.DataDir, segmentDetail(mirror)))
+		addMirrorDetails = append(addMirrorDetails, "add mirror "+segmentDetail(mirror))
+	}
+	plan.RunConditionally(idl.Substep_upgrade_mirrors, conf.Source.HasMirrors() && conf.Mode == idl.Mode_link, "link mode with mirrors using rsync", rsyncDetails...)
+	plan.RunConditionally(idl.Substep_upgrade_mirrors, conf.Source.HasMirrors() && conf.Mode != idl.Mode_link, "copy mode with mirrors using gpaddmirrors", addMirrorDetails...)
+
+	var standbyDetails []string
+	if conf.Intermediate.HasStandby() {
+		standbyDetails = append(standbyDetails, "add standby "+segmentDetail(conf.Intermediate.Standby()))
+	}
+	plan.RunConditionally(idl.Substep_upgrade_standby, conf.Source.HasStandby(), "source cluster has a standby", standbyDetails...)
+
+	plan.Run(idl.Substep_wait_for_cluster_to_be_ready_after_adding_mirrors_and_standby)
+	plan.AlwaysRun(idl.Substep_shutdown_target_cluster, "stop intermediate cluster "+segmentDetail(conf.Intermediate.Coordinator()))
+	plan.Run(idl.Substep_update_target_catalog, fmt.Sprintf("update gp_segment_configuration of coordinator %s", segmentDetail(conf.Intermediate.Coordinator())))
+
+	var renameDetails []string
+	renameDetails = append(renameDetails, renameDetail(conf.Intermediate.CoordinatorHostname(), conf.Source.CoordinatorDataDir(), conf.Intermediate.CoordinatorDataDir()))
+	for _, seg := range sortedSegments(conf.Source.Primaries.ExcludingCoordinator()) {
+		renameDetails = append(renameDetails, renameDetail(seg.Hostname, seg.DataDir, conf.Intermediate.Primaries[seg.ContentID].DataDir))
+	}
+	for _, seg := range sortedSegments(conf.Source.Mirrors.ExcludingStandby()) {
+		renameDetails = append(renameDetails, renameDetail(seg.Hostname, seg.DataDir, conf.Intermediate.Mirrors[seg.ContentID].DataDir))
+	}
+	plan.Run(idl.Substep_update_data_directories, renameDetails...)
+
+	var confDetails []string
+	for _, seg := range sortedSegments(conf.Target.Primaries) {
+		intermediate := conf.Intermediate.Primaries[seg.ContentID]
+		confDetails = append(confDetails, fmt.Sprintf("%s: %s port %d -> %d", seg.Hostname, seg.DataDir, intermediate.Port, seg.Port))
+	}
+	plan.Run(idl.Substep_update_target_conf_files, confDetails...)
+
+	plan.AlwaysRun(idl.Substep_start_target_cluster, "start target cluster "+segmentDetail(conf.Target.Coordinator()))
+	plan.AlwaysRun(idl.Substep_wait_for_cluster_to_be_ready_after_updating_catalog)
+	plan.Run(idl.Substep_analyze_target_cluster, "target coordinator "+segmentDetail(conf.Target.Coordinator()))
+	plan.AlwaysRun(idl.Substep_archive_log_directories, hostsDetail("archive logs on", allHosts(conf.Source)))
+	plan.Run(idl.Substep_delete_backupdir, backupDirsDetails(conf.BackupDirs)...)
+	plan.AlwaysRun(idl.Substep_delete_segment_statedirs, hostsDetail("delete state directories on", AgentHosts(conf.Source)))
+
+	return plan, plan.Err()
+}
+
+// RevertPlan returns the substeps revert would run without running them.
+func RevertPlan(conf *config.Config, stateDir string) (*step.Plan, error) {
+	if conf.Source == nil {
+		return nil, missingClusterErr(idl.Step_revert)
+	}
+
+	store := planStore(stateDir)
+
+	hasExecuteStarted, err := planHasStarted(store, idl.Step_execute)
+	if err != nil {
+		return nil, err
+	}
+
+	if !conf.Source.HasAllMirrorsAndStandby() && (conf.Mode == idl.Mode_link) && hasExecuteStarted {
+		return nil, errors.New(`The source cluster does not have standby and/or mirrors and is being upgraded in link mode. Execute has started.
+Cannot revert and restore the source cluster. Please contact support.`)
+	}
+
+	configCreated, err := planHasStatus(store, idl.Step_initialize, idl.Substep_saving_source_cluster_config, hasCompleted)
+	if err != nil {
+		return nil, err
+	}
+
+	agentsStarted, err := planHasStatus(store, idl.Step_initialize, idl.Substep_start_agents, hasCompleted)
+	if err != nil {
+		return nil, err
+	}
+
+	primariesUpgraded, err := planHasStatus(store, idl.Step_execute, idl.Substep_upgrade_primaries, hasRun)
+	if err != nil {
+		return nil, err
+	}
+
+	shouldHandle5XMirrorFailure := conf.Source.Version.Major == 5 && conf.Mode != idl.Mode_link && primariesUpgraded
+
+	var intermediateDetails, tablespaceDetails []string
+	if conf.Intermediate != nil {
+		intermediateDetails = append(intermediateDetails, "intermediate coordinator "+segmentDetail(conf.Intermediate.Coordinator()))
+
+		var deleteDetails []string
+		for _, seg := range sortedSegments(conf.Intermediate.Primaries) {
+			deleteDetails = append(deleteDetails, "delete "+segmentDetail(seg))
+		}
+		intermediateDetails = append(intermediateDetails, deleteDetails...)
+	}
+
+	for _, tablespaces := range conf.Source.Tablespaces {
+		tablespaceDetails = append(tablespaceDetails, tablespaces.UserDefinedTablespacesLocations()...)
+	}
+	sort.Strings(tablespaceDetails)
+
+	var sourceDetails []string
+	for _, seg := range sortedSegments(conf.Source.Primaries) {
+		sourceDetails = append(sourceDetails, segmentDetail(seg))
+	}
+
+	plan := step.NewPlan(idl.Step_revert, store)
+
+	configCondition := "initialize saved the source cluster configuration"
+	plan.RunConditionally(idl.Substep_ensure_gpupgrade_agents_are_running, configCreated && agentsStarted, "initialize started the agents", agentsDetail(conf))
+
+	var checkDetails, shutdownDetails, deleteDetails []string
+	if len(intermediateDetails) > 0 {
+		checkDetails = intermediateDetails[:1]
+		shutdownDetails = []string{"stop " + intermediateDetails[0]}
+		deleteDetails = intermediateDetails[1:]
+	}
+	plan.RunConditionally(idl.Substep_check_active_connections_on_target_cluster, configCreated, configCondition, checkDetails...)
+	plan.RunConditionally(idl.Substep_shutdown_target_cluster, configCreated, configCondition, shutdownDetails...)
+	plan.RunConditionally(idl.Substep_delete_target_cluster_datadirs, configCreated, configCondition, deleteDetails...)
+	plan.RunConditionally(idl.Substep_delete_tablespaces, configCreated, configCondition, tablespaceDetails...)
+	plan.RunConditionally(idl.Substep_restore_pgcontrol, configCreated && conf.Mode == idl.Mode_link, "link mode", sourceDetails...)
+	plan.RunConditionally(idl.Substep_restore_source_cluster, configCreated && conf.Mode == idl.Mode_link && conf.Source.HasAllMirrorsAndStandby(),
+		"link mode with mirrors and standby", restoreSourceDetails(conf.Source)...)
+	plan.RunConditionally(idl.Substep_start_source_cluster, configCreated, configCondition, "start source cluster "+segmentDetail(conf.Source.Coordinator()))
+	plan.RunConditionally(idl.Substep_recoverseg_source_cluster, configCreated && shouldHandle5XMirrorFailure, "5X source cluster upgraded in copy mode", "recover source cluster mirrors")
+	plan.AlwaysRun(idl.Substep_archive_log_directories, hostsDetail("archive logs on", allHosts(conf.Source)))
+	plan.RunConditionally(idl.Substep_delete_backupdir, configCreated, configCondition, backupDirsDetails(conf.BackupDirs)...)
+	plan.AlwaysRun(idl.Substep_delete_segment_statedirs, hostsDetail("delete state directories on", AgentHosts(conf.Source)))
+
+	return plan, plan.Err()
+}
+
+// missingClusterErr is returned when the configuration lacks a cluster the
+// step needs, such as after initialize failed before creating the target
+// cluster.
+func missingClusterErr(stepName idl.Step) error {
+	return utils.NewNextActionErr(
+		fmt.Errorf("Unable to plan %s since the configuration does not contain the clusters it needs.", stepName),
+		`Run "gpupgrade initialize" to completion before planning this step.`)
+}
+
+// planStore reads the substep status without creating the status file as
+// NewSubstepFileStore does, so that planning has no side effects.
+func planStore(stateDir string) *step.SubstepFileStore {
+	return step.NewSubstepStoreUsingFile(filepath.Join(stateDir, step.SubstepsFileName))
+}
+
+func planHasStarted(store *step.SubstepFileStore, stepName idl.Step) (bool, error) {
+	substeps, err := store.ReadStep(stepName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+
+	return substeps != nil, err
+}
+
+// planHasStatus is similar to step.HasCompleted and step.HasRun but treats a
+// missing status file as no substeps having run.
+func planHasStatus(store step.SubstepStore, stepName idl.Step, substep idl.Substep, check func(status idl.Status) bool) (bool, error) {
+	status, err := store.Read(stepName, substep)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return check(status), nil
+}
+
+func hasCompleted(status idl.Status) bool {
+	return status == idl.Status_complete
+}
+
+func hasRun(status idl.Status) bool {
+	return status != idl.Status_unknown_status
+}
+
+func agentsDetail(conf *config.Config) string {
+	return hostsDetail(fmt.Sprintf("agents on port %d on", conf.AgentPort), AgentHosts(conf.Source))
+}
+
+func hostsDetail(prefix string, hosts []string) string {
+	sort.Strings(hosts)
+	return prefix + " " + strings.Join(hosts, ", ")
+}
+
+func segmentDetail(seg greenplum.SegConfig) string {
+	return fmt.Sprintf("%s:%d %s", seg.Hostname, seg.Port, seg.DataDir)
+}
+
+func renameDetail(host string, source string, target string) string {
+	return fmt.Sprintf("%s: %s -> %s, %s -> %s", host, source, source+upgrade.OldSuffix, target, source)
+}
+
+func restoreSourceDetails(source *greenplum.Cluster) []string {
+	var details []string
+	if source.HasStandby() {
+		details = append(details, fmt.Sprintf("rsync %s to %s", segmentDetail(source.Standby()), segmentDetail(source.Coordinator())))
+	}
+
+	for _, seg := range sortedSegments(source.Mirrors.ExcludingStandby()) {
+		primary := source.Primaries[seg.ContentID]
+		details = append(details, fmt.Sprintf("rsync %s to %s", segmentDetail(seg), segmentDetail(primary)))
+	}
+
+	return details
+}
+
+func backupDirsDetails(backupDirs backupdir.BackupDirs) []string {
+	var details []string
+	if backupDirs.CoordinatorBackupDir != "" {
+		details = append(details, "delete coordinator "+backupDirs.CoordinatorBackupDir)
+	}
+
+	for _, host := range sortedHosts(backupDirs.AgentHostsToBackupDir) {
+		details = append(details, fmt.Sprintf("delete %s:%s", host, backupDirs.AgentHostsToBackupDir[host]))
+	}
+
+	return details
+}
+
+func allHosts(cluster *greenplum.Cluster) []string {
+	hosts := AgentHosts(cluster)
+	for _, host := range hosts {
+		if host == cluster.CoordinatorHostname() {
+			return hosts
+		}
+	}
+
+	return append(hosts, cluster.CoordinatorHostname())
+}
+
+func sortedSegments(segs greenplum.ContentToSegConfig) greenplum.SegConfigs {
+	var sorted greenplum.SegConfigs
+	for _, seg := range segs {
+		sorted = append(sorted, seg)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ContentID < sorted[j].ContentID
+	})
+
+	return sorted
+}
+
+func sortedHosts(agentHostsToBackupDir backupdir.AgentHostsToBackupDir) []string {
+	hosts := make([]string, 0, len(agentHostsToBackupDir))
+	for host := range agentHostsToBackupDir {
+		hosts = append(hosts, host)
+	}
+
+	sort.Strings(hosts)
+	return hosts
+}
@@ -0,0 +1,322 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/config/backupdir"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+func TestPlan(t *testing.T) {
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	path := filepath.Join(stateDir, step.SubstepsFileName)
+
+	source := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Port: 15432, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+		{ContentID: -1, DbID: 2, Port: 16432, Hostname: "standby", DataDir: "/data/standby", Role: greenplum.MirrorRole},
+		{ContentID: 0, DbID: 3, Port: 25432, Hostname: "sdw1", DataDir: "/data/dbfast1/seg0", Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 4, Port: 25433, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg0", Role: greenplum.MirrorRole},
+	})
+	source.Version = semver.MustParse("6.20.0")
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Port: 50432, Hostname: "coordinator", DataDir: "/data/qddir/seg.AAAAAAAAAAA.-1", Role: greenplum.PrimaryRole},
+		{ContentID: -1, DbID: 2, Port: 50433, Hostname: "standby", DataDir: "/data/standby.AAAAAAAAAAA", Role: greenplum.MirrorRole},
+		{ContentID: 0, DbID: 3, Port: 50434, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.AAAAAAAAAAA.0", Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 4, Port: 50435, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg.AAAAAAAAAAA.0", Role: greenplum.MirrorRole},
+	})
+
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Port: 15432, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+		{ContentID: -1, DbID: 2, Port: 16432, Hostname: "standby", DataDir: "/data/standby", Role: greenplum.MirrorRole},
+		{ContentID: 0, DbID: 3, Port: 25432, Hostname: "sdw1", DataDir: "/data/dbfast1/seg0", Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 4, Port: 25433, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg0", Role: greenplum.MirrorRole},
+	})
+
+	newConfig := func(mode idl.Mode) *config.Config {
+		return &config.Config{
+			Source:       source,
+			Intermediate: intermediate,
+			Target:       target,
+			Mode:         mode,
+			AgentPort:    6416,
+			BackupDirs: backupdir.BackupDirs{
+				CoordinatorBackupDir:  "/data/.gpupgrade",
+				AgentHostsToBackupDir: backupdir.AgentHostsToBackupDir{"sdw1": "/data/dbfast1/.gpupgrade", "sdw2": "/data/dbfast_mirror1/.gpupgrade"},
+			},
+		}
+	}
+
+	planned := func(t *testing.T, plan *step.Plan, substep idl.Substep) []*step.PlannedSubstep {
+		t.Helper()
+
+		var substeps []*step.PlannedSubstep
+		for _, s := range plan.Substeps {
+			if s.Substep == substep {
+				substeps = append(substeps, s)
+			}
+		}
+
+		if len(substeps) == 0 {
+			t.Fatalf("expected plan to contain %s", substep)
+		}
+
+		return substeps
+	}
+
+	t.Run("execute plans each substep with the segments it touches", func(t *testing.T) {
+		plan, err := hub.ExecutePlan(newConfig(idl.Mode_link), stateDir, "")
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		var substeps []idl.Substep
+		for _, s := range plan.Substeps {
+			substeps = append(substeps, s.Substep)
+			if !s.WillRun {
+				t.Errorf("expected %s to run", s.Substep)
+			}
+		}
+
+		expected := []idl.Substep{
+			idl.Substep_ensure_gpupgrade_agents_are_running,
+			idl.Substep_check_active_connections_on_source_cluster,
+			idl.Substep_wait_for_cluster_to_be_ready_before_upgrade_master,
+			idl.Substep_shutdown_source_cluster,
+			idl.Substep_upgrade_master,
+			idl.Substep_copy_master,
+			idl.Substep_upgrade_primaries,
+			idl.Substep_start_target_cluster,
+		}
+		if !reflect.DeepEqual(substeps, expected) {
+			t.Errorf("got substeps %v want %v", substeps, expected)
+		}
+
+		agents := planned(t, plan, idl.Substep_ensure_gpupgrade_agents_are_running)[0]
+		if !reflect.DeepEqual(agents.Details, []string{"agents on port 6416 on sdw1, sdw2, standby"}) {
+			t.Errorf("got details %q", agents.Details)
+		}
+
+		primaries := planned(t, plan, idl.Substep_upgrade_primaries)[0]
+		expectedDetails := []string{"sdw1: /data/dbfast1/seg0 -> /data/dbfast1/seg.AAAAAAAAAAA.0 port 50434"}
+		if !reflect.DeepEqual(primaries.Details, expectedDetails) {
+			t.Errorf("got details %q want %q", primaries.Details, expectedDetails)
+		}
+
+		copyMaster := planned(t, plan, idl.Substep_copy_master)[0]
+		expectedDetails = []string{
+			"copy /data/qddir/seg.AAAAAAAAAAA.-1 to sdw1:/data/dbfast1/.gpupgrade",
+			"copy /data/qddir/seg.AAAAAAAAAAA.-1 to sdw2:/data/dbfast_mirror1/.gpupgrade",
+		}
+		if !reflect.DeepEqual(copyMaster.Details, expectedDetails) {
+			t.Errorf("got details %q want %q", copyMaster.Details, expectedDetails)
+		}
+	})
+
+	t.Run("execute uses the parent backup directories flag", func(t *testing.T) {
+		plan, err := hub.ExecutePlan(newConfig(idl.Mode_link), stateDir, "/backup")
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		copyMaster := planned(t, plan, idl.Substep_copy_master)[0]
+		for _, detail := range copyMaster.Details {
+			if !strings.Contains(detail, ":/backup/") {
+				t.Errorf("got detail %q want backup directory under /backup", detail)
+			}
+		}
+	})
+
+	t.Run("execute skips completed substeps", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, "{}")
+		defer testutils.MustRemoveAll(t, path)
+
+		err := step.NewSubstepStoreUsingFile(path).Write(idl.Step_execute, idl.Substep_upgrade_master, idl.Status_complete)
+		if err != nil {
+			t.Fatalf("Write() returned error %#v", err)
+		}
+
+		plan, err := hub.ExecutePlan(newConfig(idl.Mode_link), stateDir, "")
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		upgradeMaster := planned(t, plan, idl.Substep_upgrade_master)[0]
+		if upgradeMaster.WillRun {
+			t.Errorf("expected upgrade_master to be skipped")
+		}
+	})
+
+	t.Run("finalize upgrades mirrors using rsync in link mode", func(t *testing.T) {
+		plan, err := hub.FinalizePlan(newConfig(idl.Mode_link), stateDir)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		mirrors := planned(t, plan, idl.Substep_upgrade_mirrors)
+		if len(mirrors) != 2 || !mirrors[0].WillRun || mirrors[1].WillRun {
+			t.Fatalf("got %+v want only the rsync branch to run", mirrors)
+		}
+
+		expectedDetails := []string{"rsync sdw1:/data/dbfast1/seg.AAAAAAAAAAA.0 to sdw2:50435 /data/dbfast_mirror1/seg.AAAAAAAAAAA.0"}
+		if !reflect.DeepEqual(mirrors[0].Details, expectedDetails) {
+			t.Errorf("got details %q want %q", mirrors[0].Details, expectedDetails)
+		}
+
+		standby := planned(t, plan, idl.Substep_upgrade_standby)[0]
+		if !standby.WillRun {
+			t.Errorf("expected upgrade_standby to run")
+		}
+
+		rename := planned(t, plan, idl.Substep_update_data_directories)[0]
+		expectedDetails = []string{
+			"coordinator: /data/qddir/seg-1 -> /data/qddir/seg-1.old, /data/qddir/seg.AAAAAAAAAAA.-1 -> /data/qddir/seg-1",
+			"sdw1: /data/dbfast1/seg0 -> /data/dbfast1/seg0.old, /data/dbfast1/seg.AAAAAAAAAAA.0 -> /data/dbfast1/seg0",
+			"sdw2: /data/dbfast_mirror1/seg0 -> /data/dbfast_mirror1/seg0.old, /data/dbfast_mirror1/seg.AAAAAAAAAAA.0 -> /data/dbfast_mirror1/seg0",
+		}
+		if !reflect.DeepEqual(rename.Details, expectedDetails) {
+			t.Errorf("got details %q want %q", rename.Details, expectedDetails)
+		}
+	})
+
+	t.Run("finalize upgrades mirrors using gpaddmirrors in copy mode", func(t *testing.T) {
+		plan, err := hub.FinalizePlan(newConfig(idl.Mode_copy), stateDir)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		mirrors := planned(t, plan, idl.Substep_upgrade_mirrors)
+		if len(mirrors) != 2 || mirrors[0].WillRun || !mirrors[1].WillRun {
+			t.Errorf("got %+v want only the gpaddmirrors branch to run", mirrors)
+		}
+	})
+
+	t.Run("revert only archives logs and deletes state directories when initialize did not save the config", func(t *testing.T) {
+		plan, err := hub.RevertPlan(newConfig(idl.Mode_link), stateDir)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		for _, s := range plan.Substeps {
+			shouldRun := s.Substep == idl.Substep_archive_log_directories || s.Substep == idl.Substep_delete_segment_statedirs
+			if s.WillRun != shouldRun {
+				t.Errorf("got %s will run %t want %t", s.Substep, s.WillRun, shouldRun)
+			}
+		}
+	})
+
+	t.Run("revert restores pg_control and the source cluster in link mode", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, "{}")
+		defer testutils.MustRemoveAll(t, path)
+
+		store := step.NewSubstepStoreUsingFile(path)
+		for _, substep := range []idl.Substep{idl.Substep_saving_source_cluster_config, idl.Substep_start_agents} {
+			if err := store.Write(idl.Step_initialize, substep, idl.Status_complete); err != nil {
+				t.Fatalf("Write() returned error %#v", err)
+			}
+		}
+
+		plan, err := hub.RevertPlan(newConfig(idl.Mode_link), stateDir)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		for _, substep := range []idl.Substep{idl.Substep_ensure_gpupgrade_agents_are_running, idl.Substep_restore_pgcontrol, idl.Substep_restore_source_cluster, idl.Substep_start_source_cluster} {
+			if !planned(t, plan, substep)[0].WillRun {
+				t.Errorf("expected %s to run", substep)
+			}
+		}
+
+		recoverseg := planned(t, plan, idl.Substep_recoverseg_source_cluster)[0]
+		if recoverseg.WillRun {
+			t.Errorf("expected recoverseg_source_cluster to not run")
+		}
+	})
+
+	t.Run("errors when the configuration lacks the clusters a step needs", func(t *testing.T) {
+		conf := newConfig(idl.Mode_link)
+		conf.Intermediate = nil
+		conf.Target = nil
+
+		_, err := hub.ExecutePlan(conf, stateDir, "")
+		var nextActionErr utils.NextActionErr
+		if !errors.As(err, &nextActionErr) {
+			t.Errorf("got error %#v want %T", err, nextActionErr)
+		}
+
+		_, err = hub.FinalizePlan(conf, stateDir)
+		if !errors.As(err, &nextActionErr) {
+			t.Errorf("got error %#v want %T", err, nextActionErr)
+		}
+
+		conf.Source = nil
+		_, err = hub.RevertPlan(conf, stateDir)
+		if !errors.As(err, &nextActionErr) {
+			t.Errorf("got error %#v want %T", err, nextActionErr)
+		}
+	})
+
+	t.Run("revert errors in link mode without mirrors and standby once execute has started", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, "{}")
+		defer testutils.MustRemoveAll(t, path)
+
+		err := step.NewSubstepStoreUsingFile(path).Write(idl.Step_execute, idl.Substep_upgrade_master, idl.Status_failed)
+		if err != nil {
+			t.Fatalf("Write() returned error %#v", err)
+		}
+
+		conf := newConfig(idl.Mode_link)
+		conf.Source = hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{ContentID: -1, DbID: 1, Port: 15432, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+			{ContentID: 0, DbID: 2, Port: 25432, Hostname: "sdw1", DataDir: "/data/dbfast1/seg0", Role: greenplum.PrimaryRole},
+		})
+
+		_, err = hub.RevertPlan(conf, stateDir)
+		if err == nil || !strings.Contains(err.Error(), "Cannot revert") {
+			t.Errorf("got error %#v want cannot revert error", err)
+		}
+	})
+}
+
+func TestPlansMatchSteps(t *testing.T) {
+	cases := []struct {
+		file     string
+		stepFunc string
+		planFunc string
+	}{
+		{file: "execute.go", stepFunc: "Execute", planFunc: "ExecutePlan"},
+		{file: "finalize.go", stepFunc: "Finalize", planFunc: "FinalizePlan"},
+		{file: "revert.go", stepFunc: "Revert", planFunc: "RevertPlan"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.planFunc+" runs the same substeps as "+c.stepFunc, func(t *testing.T) {
+			expected := testutils.MustGetSubstepCalls(t, c.file, c.stepFunc)
+			if len(expected) == 0 {
+				t.Fatalf("found no substeps in %s", c.stepFunc)
+			}
+
+			planned := testutils.MustGetSubstepCalls(t, "plan.go", c.planFunc)
+			if !reflect.DeepEqual(planned, expected) {
+				t.Errorf("%s plans substeps\n%q\nbut %s runs\n%q", c.planFunc, planned, c.stepFunc, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path/filepath"
+	"sync/atomic"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/upgrade"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+var AlreadyRenamed = upgrade.AlreadyRenamed
+
+// recoveryChecks inspect the side effects of a substep that was interrupted
+// while running and return true if the substep completed its work.
+var recoveryChecks = map[idl.Substep]func(ctx context.Context, s *Server) (bool, error){
+	idl.Substep_update_data_directories: func(ctx context.Context, s *Server) (bool, error) {
+		agentConns, err := s.AgentConns()
+		if err != nil {
+			return false, err
+		}
+
+		return DataDirectoriesRenamed(ctx, agentConns, s.Source, s.Intermediate)
+	},
+}
+
+// Recover resets the status of a substep left running after the hub or CLI
+// was interrupted. If the substep's side effects can be checked it is marked
+// complete or failed accordingly. Otherwise, substeps whose interrupted run
+// was idempotent are marked failed so they are re-run. The step is locked
+// while recovering so that the substep cannot be run at the same time.
+func (s *Server) Recover(ctx context.Context, req *idl.RecoverRequest) (*idl.RecoverReply, error) {
+	substep := req.GetSubstep()
+
+	store, stepName, err := findRunningSubstep(substepStores(utils.GetStateDir()), substep)
+	if err != nil {
+		return &idl.RecoverReply{}, err
+	}
+
+	ctx, unlock, err := s.lockStep(ctx, stepName)
+	if err != nil {
+		return &idl.RecoverReply{}, err
+	}
+	defer unlock()
+
+	// Read the substep again now that the step is locked since a step may have
+	// finished running it in the meantime.
+	run, err := store.ReadRun(stepName, substep)
+	if err != nil {
+		return &idl.RecoverReply{}, err
+	}
+
+	if run.Status.Status != idl.Status_running {
+		return &idl.RecoverReply{}, notRunningErr(substep)
+	}
+
+	status := idl.Status_failed
+	check, ok := recoveryChecks[substep]
+	switch {
+	case ok:
+		completed, err := check(ctx, s)
+		if err != nil {
+			return &idl.RecoverReply{}, xerrors.Errorf("checking substep %s: %w", substep, err)
+		}
+
+		if completed {
+			status = idl.Status_complete
+		}
+
+	case !run.Idempotent:
+		return &idl.RecoverReply{}, utils.NewNextActionErr(
+			fmt.Errorf("Unable to recover substep %s since it is not idempotent and its side effects cannot be checked.", substep),
+			"Manual intervention needed to cleanup. Please contact support.")
+	}
+
+	log.Printf("recovering %s substep %s by marking it %s", stepName, substep, status)
+	err = store.Write(stepName, substep, status)
+	if err != nil {
+		return &idl.RecoverReply{}, err
+	}
+
+	return &idl.RecoverReply{Step: stepName, Status: status}, nil
+}
+
+// substepStores returns the hub's substep store and the CLI's step store such
+// that substeps run by either can be recovered. Neither file is created.
+func substepStores(stateDir string) []*step.SubstepFileStore {
+	return []*step.SubstepFileStore{
+		step.NewSubstepStoreUsingFile(filepath.Join(stateDir, step.SubstepsFileName)),
+		step.NewSubstepStoreUsingFile(filepath.Join(stateDir, step.StepsFileName)),
+	}
+}
+
+// findRunningSubstep returns the store and step in which the substep is
+// running.
+func findRunningSubstep(stores []*step.SubstepFileStore, substep idl.Substep) (*step.SubstepFileStore, idl.Step, error) {
+	for _, store := range stores {
+		stepName, err := runningStep(store, substep)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return nil, idl.Step_unknown_step, err
+		}
+
+		if stepName != idl.Step_unknown_step {
+			return store, stepName, nil
+		}
+	}
+
+	return nil, idl.Step_unknown_step, notRunningErr(substep)
+}
+
+// runningStep returns the step in which the substep is running, or
+// Step_unknown_step when it is not running.
+func runningStep(substepStore step.SubstepStore, substep idl.Substep) (idl.Step, error) {
+	for _, stepName := range step.Steps {
+		status, err := substepStore.Read(stepName, substep)
+		if err != nil {
+			return idl.Step_unknown_step, err
+		}
+
+		if status == idl.Status_running {
+			return stepName, nil
+		}
+	}
+
+	return idl.Step_unknown_step, nil
+}
+
+func notRunningErr(substep idl.Substep) error {
+	return xerrors.Errorf("Substep %s is not running and does not need to be recovered.", substep)
+}
+
+// DataDirectoriesRenamed returns true when the coordinator and all segment
+// data directories have been renamed by update_data_directories.
+func DataDirectoriesRenamed(ctx context.Context, agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster) (bool, error) {
+	target := intermediate.CoordinatorDataDir()
+	renamed, err := AlreadyRenamed(target, target+upgrade.OldSuffix)
+	if err != nil {
+		return false, err
+	}
+
+	if !renamed {
+		return false, nil
+	}
+
+	renameMap := getRenameMap(source, intermediate)
+
+	var notRenamed int32
+	request := func(conn *idl.Connection) error {
+		if len(renameMap[conn.Hostname]) == 0 {
+			return nil
+		}
+
+		req := &idl.RenameDirectoriesRequest{Dirs: renameMap[conn.Hostname]}
+		reply, err := conn.AgentClient.AlreadyRenamedDirectories(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		if !reply.GetRenamed() {
+			atomic.AddInt32(&notRenamed, 1)
+		}
+
+		return nil
+	}
+
+	err = ExecuteRPC(ctx, agentConns, request)
+	if err != nil {
+		return false, err
+	}
+
+	return notRenamed == 0, nil
+}
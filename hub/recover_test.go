@@ -0,0 +1,302 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+	"github.com/greenplum-db/gpupgrade/upgrade"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+func TestRecover(t *testing.T) {
+	testlog.SetupTestLogger()
+
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+	defer resetEnv()
+
+	path := filepath.Join(stateDir, step.SubstepsFileName)
+	substepStore := step.NewSubstepStoreUsingFile(path)
+
+	source := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Port: 15432, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+	})
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Port: 50432, Hostname: "coordinator", DataDir: "/data/qddir/seg.AAAAAAAAAAA.-1", Role: greenplum.PrimaryRole},
+	})
+	server := hub.New(&config.Config{Source: source, Intermediate: intermediate})
+
+	writeStatus := func(t *testing.T, stepName idl.Step, substep idl.Substep, status idl.Status) {
+		t.Helper()
+
+		if err := substepStore.Write(stepName, substep, status); err != nil {
+			t.Fatalf("Write() returned error %#v", err)
+		}
+	}
+
+	startRun := func(t *testing.T, store *step.SubstepFileStore, stepName idl.Step, substep idl.Substep, idempotent bool) {
+		t.Helper()
+
+		if err := store.Start(stepName, substep, idempotent); err != nil {
+			t.Fatalf("Start() returned error %#v", err)
+		}
+	}
+
+	readStatus := func(t *testing.T, stepName idl.Step, substep idl.Substep) idl.Status {
+		t.Helper()
+
+		status, err := substepStore.Read(stepName, substep)
+		if err != nil {
+			t.Fatalf("Read() returned error %#v", err)
+		}
+
+		return status
+	}
+
+	t.Run("marks a running idempotent substep as failed", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, "{}")
+		startRun(t, substepStore, idl.Step_execute, idl.Substep_shutdown_source_cluster, true)
+
+		reply, err := server.Recover(context.Background(), &idl.RecoverRequest{Substep: idl.Substep_shutdown_source_cluster})
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if reply.GetStep() != idl.Step_execute || reply.GetStatus() != idl.Status_failed {
+			t.Errorf("got %v want step %v status %v", reply, idl.Step_execute, idl.Status_failed)
+		}
+
+		status := readStatus(t, idl.Step_execute, idl.Substep_shutdown_source_cluster)
+		if status != idl.Status_failed {
+			t.Errorf("got status %v want %v", status, idl.Status_failed)
+		}
+	})
+
+	t.Run("recovers a running substep recorded in the CLI step store", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, "{}")
+
+		stepsPath := filepath.Join(stateDir, step.StepsFileName)
+		testutils.MustWriteToFile(t, stepsPath, "{}")
+		defer testutils.MustRemoveAll(t, stepsPath)
+
+		stepStore := step.NewSubstepStoreUsingFile(stepsPath)
+		startRun(t, stepStore, idl.Step_finalize, idl.Substep_stop_hub_and_agents, true)
+
+		reply, err := server.Recover(context.Background(), &idl.RecoverRequest{Substep: idl.Substep_stop_hub_and_agents})
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if reply.GetStep() != idl.Step_finalize || reply.GetStatus() != idl.Status_failed {
+			t.Errorf("got %v want step %v status %v", reply, idl.Step_finalize, idl.Status_failed)
+		}
+
+		status, err := stepStore.Read(idl.Step_finalize, idl.Substep_stop_hub_and_agents)
+		if err != nil {
+			t.Fatalf("Read() returned error %#v", err)
+		}
+
+		if status != idl.Status_failed {
+			t.Errorf("got status %v want %v", status, idl.Status_failed)
+		}
+	})
+
+	t.Run("marks a running substep as failed when its side effects were not applied", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, "{}")
+		writeStatus(t, idl.Step_finalize, idl.Substep_update_data_directories, idl.Status_running)
+
+		hub.AlreadyRenamed = func(src, dst string) (bool, error) {
+			return false, nil
+		}
+		defer func() {
+			hub.AlreadyRenamed = upgrade.AlreadyRenamed
+		}()
+
+		reply, err := server.Recover(context.Background(), &idl.RecoverRequest{Substep: idl.Substep_update_data_directories})
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if reply.GetStatus() != idl.Status_failed {
+			t.Errorf("got status %v want %v", reply.GetStatus(), idl.Status_failed)
+		}
+
+		status := readStatus(t, idl.Step_finalize, idl.Substep_update_data_directories)
+		if status != idl.Status_failed {
+			t.Errorf("got status %v want %v", status, idl.Status_failed)
+		}
+	})
+
+	t.Run("errors when the substep is not running", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, "{}")
+		writeStatus(t, idl.Step_execute, idl.Substep_upgrade_master, idl.Status_failed)
+
+		_, err := server.Recover(context.Background(), &idl.RecoverRequest{Substep: idl.Substep_upgrade_master})
+		if err == nil || !strings.Contains(err.Error(), "is not running") {
+			t.Errorf("got error %#v want not running error", err)
+		}
+
+		status := readStatus(t, idl.Step_execute, idl.Substep_upgrade_master)
+		if status != idl.Status_failed {
+			t.Errorf("got status %v want %v", status, idl.Status_failed)
+		}
+	})
+
+	t.Run("errors when the substep is not idempotent and cannot be checked", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, "{}")
+		startRun(t, substepStore, idl.Step_execute, idl.Substep_upgrade_master, false)
+
+		_, err := server.Recover(context.Background(), &idl.RecoverRequest{Substep: idl.Substep_upgrade_master})
+		var nextActionErr utils.NextActionErr
+		if !errors.As(err, &nextActionErr) {
+			t.Errorf("got error %#v want %T", err, nextActionErr)
+		}
+
+		status := readStatus(t, idl.Step_execute, idl.Substep_upgrade_master)
+		if status != idl.Status_running {
+			t.Errorf("got status %v want %v", status, idl.Status_running)
+		}
+	})
+}
+
+func TestDataDirectoriesRenamed(t *testing.T) {
+	testlog.SetupTestLogger()
+
+	source := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Port: 15432, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 2, Port: 25432, Hostname: "sdw1", DataDir: "/data/dbfast1/seg0", Role: greenplum.PrimaryRole},
+		{ContentID: 1, DbID: 3, Port: 25433, Hostname: "sdw2", DataDir: "/data/dbfast2/seg1", Role: greenplum.PrimaryRole},
+	})
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Port: 50432, Hostname: "coordinator", DataDir: "/data/qddir/seg.AAAAAAAAAAA.-1", Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 2, Port: 50434, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.AAAAAAAAAAA.0", Role: greenplum.PrimaryRole},
+		{ContentID: 1, DbID: 3, Port: 50435, Hostname: "sdw2", DataDir: "/data/dbfast2/seg.AAAAAAAAAAA.1", Role: greenplum.PrimaryRole},
+	})
+
+	defer func() {
+		hub.AlreadyRenamed = upgrade.AlreadyRenamed
+	}()
+
+	t.Run("returns false without contacting the agents when the coordinator has not been renamed", func(t *testing.T) {
+		hub.AlreadyRenamed = func(src, dst string) (bool, error) {
+			if src != "/data/qddir/seg.AAAAAAAAAAA.-1" || dst != "/data/qddir/seg.AAAAAAAAAAA.-1.old" {
+				t.Errorf("got src %q dst %q", src, dst)
+			}
+
+			return false, nil
+		}
+
+		renamed, err := hub.DataDirectoriesRenamed(context.Background(), nil, source, intermediate)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		if renamed {
+			t.Errorf("expected data directories to not be renamed")
+		}
+	})
+
+	t.Run("returns true when the coordinator and all segments have been renamed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		hub.AlreadyRenamed = func(src, dst string) (bool, error) {
+			return true, nil
+		}
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().AlreadyRenamedDirectories(gomock.Any(), &idl.RenameDirectoriesRequest{Dirs: []*idl.RenameDirectories{
+			{Source: "/data/dbfast1/seg0", Target: "/data/dbfast1/seg.AAAAAAAAAAA.0"},
+		}}).Return(&idl.AlreadyRenamedDirectoriesReply{Renamed: true}, nil)
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().AlreadyRenamedDirectories(gomock.Any(), &idl.RenameDirectoriesRequest{Dirs: []*idl.RenameDirectories{
+			{Source: "/data/dbfast2/seg1", Target: "/data/dbfast2/seg.AAAAAAAAAAA.1"},
+		}}).Return(&idl.AlreadyRenamedDirectoriesReply{Renamed: true}, nil)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		renamed, err := hub.DataDirectoriesRenamed(context.Background(), agentConns, source, intermediate)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		if !renamed {
+			t.Errorf("expected data directories to be renamed")
+		}
+	})
+
+	t.Run("returns false when a segment has not been renamed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		hub.AlreadyRenamed = func(src, dst string) (bool, error) {
+			return true, nil
+		}
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().AlreadyRenamedDirectories(gomock.Any(), gomock.Any()).
+			Return(&idl.AlreadyRenamedDirectoriesReply{Renamed: true}, nil)
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().AlreadyRenamedDirectories(gomock.Any(), gomock.Any()).
+			Return(&idl.AlreadyRenamedDirectoriesReply{Renamed: false}, nil)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		renamed, err := hub.DataDirectoriesRenamed(context.Background(), agentConns, source, intermediate)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		if renamed {
+			t.Errorf("expected data directories to not be renamed")
+		}
+	})
+
+	t.Run("returns agent errors", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		hub.AlreadyRenamed = func(src, dst string) (bool, error) {
+			return true, nil
+		}
+
+		expected := errors.New("permission denied")
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().AlreadyRenamedDirectories(gomock.Any(), gomock.Any()).
+			Return(nil, expected)
+
+		agentConns := []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}
+
+		_, err := hub.DataDirectoriesRenamed(context.Background(), agentConns, source, intermediate)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+}
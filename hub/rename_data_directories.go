@@ -17,7 +17,7 @@ var RenameDirectories = upgrade.RenameDirectories
 
 type RenameMap = map[string][]*idl.RenameDirectories
 
-func RenameDataDirectories(agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster) error {
+func RenameDataDirectories(ctx context.Context, agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster) error {
 	src := source.CoordinatorDataDir()
 	dst := intermediate.CoordinatorDataDir()
 	if err := RenameDirectories(src, dst); err != nil {
@@ -25,7 +25,7 @@ func RenameDataDirectories(agentConns []*idl.Connection, source *greenplum.Clust
 	}
 
 	renameMap := getRenameMap(source, intermediate)
-	if err := RenameSegmentDataDirs(agentConns, renameMap); err != nil {
+	if err := RenameSegmentDataDirs(ctx, agentConns, renameMap); err != nil {
 		return xerrors.Errorf("renaming segment data directories: %w", err)
 	}
 
@@ -63,16 +63,16 @@ func getRenameMap(source *greenplum.Cluster, intermediate *greenplum.Cluster) Re
 
 // e.g. for source /data/dbfast1/demoDataDir0 becomes /data/dbfast1/demoDataDir0_old
 // e.g. for target /data/dbfast1/demoDataDir0_123ABC becomes /data/dbfast1/demoDataDir0
-func RenameSegmentDataDirs(agentConns []*idl.Connection, renames RenameMap) error {
+func RenameSegmentDataDirs(ctx context.Context, agentConns []*idl.Connection, renames RenameMap) error {
 	request := func(conn *idl.Connection) error {
 		if len(renames[conn.Hostname]) == 0 {
 			return nil
 		}
 
 		req := &idl.RenameDirectoriesRequest{Dirs: renames[conn.Hostname]}
-		_, err := conn.AgentClient.RenameDirectories(context.Background(), req)
+		_, err := conn.AgentClient.RenameDirectories(ctx, req)
 		return err
 	}
 
-	return ExecuteRPC(agentConns, request)
+	return ExecuteRPC(ctx, agentConns, request)
 }
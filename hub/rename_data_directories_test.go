@@ -4,6 +4,7 @@
 package hub_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
@@ -89,7 +90,7 @@ func TestRenameSegmentDataDirs(t *testing.T) {
 			{AgentClient: client3, Hostname: "standby"},
 		}
 
-		err := hub.RenameSegmentDataDirs(agentConns, m)
+		err := hub.RenameSegmentDataDirs(context.Background(), agentConns, m)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
@@ -117,7 +118,7 @@ func TestRenameSegmentDataDirs(t *testing.T) {
 			{AgentClient: failedClient, Hostname: "sdw2"},
 		}
 
-		err := hub.RenameSegmentDataDirs(agentConns, m)
+		err := hub.RenameSegmentDataDirs(context.Background(), agentConns, m)
 
 		if !errors.Is(err, expected) {
 			t.Errorf("got error %#v, want %#v", err, expected)
@@ -188,7 +189,7 @@ func TestUpdateDataDirectories(t *testing.T) {
 			}
 		}()
 
-		err := hub.RenameDataDirectories(nil, conf.Source, conf.Intermediate)
+		err := hub.RenameDataDirectories(context.Background(), nil, conf.Source, conf.Intermediate)
 		if err != nil {
 			t.Errorf("UpdateDataDirectories() returned error: %+v", err)
 		}
@@ -207,7 +208,7 @@ func TestUpdateDataDirectories(t *testing.T) {
 			}
 		}()
 
-		err := hub.RenameDataDirectories(nil, conf.Source, conf.Intermediate)
+		err := hub.RenameDataDirectories(context.Background(), nil, conf.Source, conf.Intermediate)
 		if !errors.Is(err, expected) {
 			t.Errorf("got %#v want %#v", err, expected)
 		}
@@ -264,7 +265,7 @@ func TestUpdateDataDirectories(t *testing.T) {
 			{AgentClient: standby, Hostname: "standby"},
 		}
 
-		err := hub.RenameDataDirectories(agentConns, conf.Source, conf.Intermediate)
+		err := hub.RenameDataDirectories(context.Background(), agentConns, conf.Source, conf.Intermediate)
 		if err != nil {
 			t.Errorf("RenameDataDirectories() returned error: %+v", err)
 		}
@@ -320,7 +321,7 @@ func TestUpdateDataDirectories(t *testing.T) {
 			{AgentClient: standby, Hostname: "standby"},
 		}
 
-		err := hub.RenameDataDirectories(agentConns, conf.Source, conf.Intermediate)
+		err := hub.RenameDataDirectories(context.Background(), agentConns, conf.Source, conf.Intermediate)
 		if err != nil {
 			t.Errorf("RenameDataDirectories() returned error: %+v", err)
 		}
@@ -23,17 +23,17 @@ var Excludes = []string{
 	"gp_dbid", "postgresql.conf", "backup_label.old", "postmaster.pid", "recovery.conf",
 }
 
-func RsyncCoordinatorAndPrimaries(stream step.OutStreams, agentConns []*idl.Connection, source *greenplum.Cluster) error {
+func RsyncCoordinatorAndPrimaries(ctx context.Context, stream step.OutStreams, agentConns []*idl.Connection, source *greenplum.Cluster) error {
 	var wg sync.WaitGroup
 	errs := make(chan error, 2)
 
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		errs <- RsyncCoordinator(stream, source.Standby(), source.Coordinator())
+		errs <- RsyncCoordinator(ctx, stream, source.Standby(), source.Coordinator())
 	}()
 
-	errs <- RsyncPrimaries(agentConns, source)
+	errs <- RsyncPrimaries(ctx, agentConns, source)
 
 	wg.Wait()
 	close(errs)
@@ -46,17 +46,17 @@ func RsyncCoordinatorAndPrimaries(stream step.OutStreams, agentConns []*idl.Conn
 	return err
 }
 
-func RsyncCoordinatorAndPrimariesTablespaces(stream step.OutStreams, agentConns []*idl.Connection, source *greenplum.Cluster) error {
+func RsyncCoordinatorAndPrimariesTablespaces(ctx context.Context, stream step.OutStreams, agentConns []*idl.Connection, source *greenplum.Cluster) error {
 	var wg sync.WaitGroup
 	errs := make(chan error, 2)
 
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		errs <- RsyncCoordinatorTablespaces(stream, source.StandbyHostname(), source.Tablespaces[int32(source.Coordinator().DbID)], source.Tablespaces[int32(source.Standby().DbID)])
+		errs <- RsyncCoordinatorTablespaces(ctx, stream, source.StandbyHostname(), source.Tablespaces[int32(source.Coordinator().DbID)], source.Tablespaces[int32(source.Standby().DbID)])
 	}()
 
-	errs <- RsyncPrimariesTablespaces(agentConns, source, source.Tablespaces)
+	errs <- RsyncPrimariesTablespaces(ctx, agentConns, source, source.Tablespaces)
 
 	wg.Wait()
 	close(errs)
@@ -78,7 +78,7 @@ func Recoverseg(stream step.OutStreams, cluster *greenplum.Cluster, useHbaHostna
 	return cluster.RunGreenplumCmd(stream, "gprecoverseg", args...)
 }
 
-func RsyncCoordinator(stream step.OutStreams, standby greenplum.SegConfig, coordinator greenplum.SegConfig) error {
+func RsyncCoordinator(ctx context.Context, stream step.OutStreams, standby greenplum.SegConfig, coordinator greenplum.SegConfig) error {
 	opts := []rsync.Option{
 		rsync.WithSources(standby.DataDir + string(os.PathSeparator)),
 		rsync.WithSourceHost(standby.Hostname),
@@ -86,12 +86,13 @@ func RsyncCoordinator(stream step.OutStreams, standby greenplum.SegConfig, coord
 		rsync.WithOptions(Options...),
 		rsync.WithExcludedFiles(Excludes...),
 		rsync.WithStream(stream),
+		rsync.WithContext(ctx),
 	}
 
-	return rsync.Rsync(opts...)
+	return runRsync(opts...)
 }
 
-func RsyncCoordinatorTablespaces(stream step.OutStreams, standbyHostname string, coordinatorTablespaces greenplum.SegmentTablespaces, standbyTablespaces greenplum.SegmentTablespaces) error {
+func RsyncCoordinatorTablespaces(ctx context.Context, stream step.OutStreams, standbyHostname string, coordinatorTablespaces greenplum.SegmentTablespaces, standbyTablespaces greenplum.SegmentTablespaces) error {
 	for oid, coordinatorTsInfo := range coordinatorTablespaces {
 		if !coordinatorTsInfo.GetUserDefined() {
 			continue
@@ -103,9 +104,10 @@ func RsyncCoordinatorTablespaces(stream step.OutStreams, standbyHostname string,
 			rsync.WithDestination(coordinatorTsInfo.GetLocation()),
 			rsync.WithOptions(Options...),
 			rsync.WithStream(stream),
+			rsync.WithContext(ctx),
 		}
 
-		err := rsync.Rsync(opts...)
+		err := runRsync(opts...)
 		if err != nil {
 			return err
 		}
@@ -114,7 +116,7 @@ func RsyncCoordinatorTablespaces(stream step.OutStreams, standbyHostname string,
 	return nil
 }
 
-func RsyncPrimaries(agentConns []*idl.Connection, source *greenplum.Cluster) error {
+func RsyncPrimaries(ctx context.Context, agentConns []*idl.Connection, source *greenplum.Cluster) error {
 	request := func(conn *idl.Connection) error {
 		mirrors := source.SelectSegments(func(seg *greenplum.SegConfig) bool {
 			return seg.IsOnHost(conn.Hostname) && !seg.IsStandby() && seg.IsMirror()
@@ -137,14 +139,15 @@ func RsyncPrimaries(agentConns []*idl.Connection, source *greenplum.Cluster) err
 		}
 
 		req := &idl.RsyncRequest{Options: opts}
-		_, err := conn.AgentClient.RsyncDataDirectories(context.Background(), req)
+		reply, err := conn.AgentClient.RsyncDataDirectories(ctx, req)
+		recordAgentRsyncStats(conn.Hostname, reply.GetStats())
 		return err
 	}
 
-	return ExecuteRPC(agentConns, request)
+	return ExecuteRPC(ctx, agentConns, request)
 }
 
-func RsyncPrimariesTablespaces(agentConns []*idl.Connection, source *greenplum.Cluster, tablespaces greenplum.Tablespaces) error {
+func RsyncPrimariesTablespaces(ctx context.Context, agentConns []*idl.Connection, source *greenplum.Cluster, tablespaces greenplum.Tablespaces) error {
 	request := func(conn *idl.Connection) error {
 		mirrors := source.SelectSegments(func(seg *greenplum.SegConfig) bool {
 			return seg.IsOnHost(conn.Hostname) && !seg.IsStandby() && seg.IsMirror()
@@ -177,14 +180,15 @@ func RsyncPrimariesTablespaces(agentConns []*idl.Connection, source *greenplum.C
 		}
 
 		req := &idl.RsyncRequest{Options: opts}
-		_, err := conn.AgentClient.RsyncTablespaceDirectories(context.Background(), req)
+		reply, err := conn.AgentClient.RsyncTablespaceDirectories(ctx, req)
+		recordAgentRsyncStats(conn.Hostname, reply.GetStats())
 		return err
 	}
 
-	return ExecuteRPC(agentConns, request)
+	return ExecuteRPC(ctx, agentConns, request)
 }
 
-func RestoreCoordinatorAndPrimariesPgControl(streams step.OutStreams, agentConns []*idl.Connection, source *greenplum.Cluster) error {
+func RestoreCoordinatorAndPrimariesPgControl(ctx context.Context, streams step.OutStreams, agentConns []*idl.Connection, source *greenplum.Cluster) error {
 	var wg sync.WaitGroup
 	errs := make(chan error, 2)
 
@@ -194,7 +198,7 @@ func RestoreCoordinatorAndPrimariesPgControl(streams step.OutStreams, agentConns
 		errs <- upgrade.RestorePgControl(source.CoordinatorDataDir(), streams)
 	}()
 
-	errs <- restorePrimariesPgControl(agentConns, source)
+	errs <- restorePrimariesPgControl(ctx, agentConns, source)
 
 	wg.Wait()
 	close(errs)
@@ -207,7 +211,7 @@ func RestoreCoordinatorAndPrimariesPgControl(streams step.OutStreams, agentConns
 	return err
 }
 
-func restorePrimariesPgControl(agentConns []*idl.Connection, source *greenplum.Cluster) error {
+func restorePrimariesPgControl(ctx context.Context, agentConns []*idl.Connection, source *greenplum.Cluster) error {
 	request := func(conn *idl.Connection) error {
 		primaries := source.SelectSegments(func(seg *greenplum.SegConfig) bool {
 			return seg.IsOnHost(conn.Hostname) && !seg.IsStandby() && seg.IsPrimary()
@@ -226,9 +230,9 @@ func restorePrimariesPgControl(agentConns []*idl.Connection, source *greenplum.C
 			Datadirs: dataDirs,
 		}
 
-		_, err := conn.AgentClient.RestorePrimariesPgControl(context.Background(), req)
+		_, err := conn.AgentClient.RestorePrimariesPgControl(ctx, req)
 		return err
 	}
 
-	return ExecuteRPC(agentConns, request)
+	return ExecuteRPC(ctx, agentConns, request)
 }
@@ -4,6 +4,7 @@
 package hub_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -76,7 +77,7 @@ func TestRsyncCoordinatorAndPrimaries(t *testing.T) {
 			}
 		}))
 
-		err := hub.RsyncCoordinator(&testutils.DevNullWithClose{}, cluster.Standby(), cluster.Coordinator())
+		err := hub.RsyncCoordinator(context.Background(), &testutils.DevNullWithClose{}, cluster.Standby(), cluster.Coordinator())
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
@@ -107,7 +108,7 @@ func TestRsyncCoordinatorAndPrimaries(t *testing.T) {
 			}
 		}))
 
-		err := hub.RsyncCoordinatorTablespaces(&testutils.DevNullWithClose{}, cluster.StandbyHostname(), tablespaces[int32(cluster.Coordinator().DbID)], tablespaces[int32(cluster.Standby().DbID)])
+		err := hub.RsyncCoordinatorTablespaces(context.Background(), &testutils.DevNullWithClose{}, cluster.StandbyHostname(), tablespaces[int32(cluster.Coordinator().DbID)], tablespaces[int32(cluster.Standby().DbID)])
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
@@ -153,7 +154,7 @@ func TestRsyncCoordinatorAndPrimaries(t *testing.T) {
 			{AgentClient: standby, Hostname: "standby"},
 		}
 
-		err := hub.RsyncPrimaries(agentConns, cluster)
+		err := hub.RsyncPrimaries(context.Background(), agentConns, cluster)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
@@ -199,7 +200,7 @@ func TestRsyncCoordinatorAndPrimaries(t *testing.T) {
 			{AgentClient: standby, Hostname: "standby"},
 		}
 
-		err := hub.RsyncPrimariesTablespaces(agentConns, cluster, tablespaces)
+		err := hub.RsyncPrimariesTablespaces(context.Background(), agentConns, cluster, tablespaces)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
@@ -209,7 +210,7 @@ func TestRsyncCoordinatorAndPrimaries(t *testing.T) {
 		rsync.SetRsyncCommand(exectest.NewCommand(hub.Failure))
 		defer rsync.ResetRsyncCommand()
 
-		err := hub.RsyncCoordinator(&testutils.DevNullWithClose{}, cluster.Standby(), cluster.Coordinator())
+		err := hub.RsyncCoordinator(context.Background(), &testutils.DevNullWithClose{}, cluster.Standby(), cluster.Coordinator())
 		if err == nil {
 			t.Error("unexpected nil error")
 		}
@@ -219,7 +220,7 @@ func TestRsyncCoordinatorAndPrimaries(t *testing.T) {
 		rsync.SetRsyncCommand(exectest.NewCommand(hub.Failure))
 		defer rsync.ResetRsyncCommand()
 
-		err := hub.RsyncCoordinatorTablespaces(&testutils.DevNullWithClose{}, cluster.CoordinatorHostname(), tablespaces[int32(greenplum.CoordinatorDbid)], tablespaces[int32(cluster.Standby().DbID)])
+		err := hub.RsyncCoordinatorTablespaces(context.Background(), &testutils.DevNullWithClose{}, cluster.CoordinatorHostname(), tablespaces[int32(greenplum.CoordinatorDbid)], tablespaces[int32(cluster.Standby().DbID)])
 		if err == nil {
 			t.Error("unexpected nil error")
 		}
@@ -255,7 +256,7 @@ func TestRsyncCoordinatorAndPrimaries(t *testing.T) {
 			{AgentClient: failedClient, Hostname: "msdw2"},
 		}
 
-		err := hub.RsyncPrimaries(agentConns, cluster)
+		err := hub.RsyncPrimaries(context.Background(), agentConns, cluster)
 
 		if !errors.Is(err, expected) {
 			t.Errorf("got error %#v, want %#v", err, expected)
@@ -284,7 +285,7 @@ func TestRsyncCoordinatorAndPrimaries(t *testing.T) {
 			{AgentClient: failedClient, Hostname: "msdw2"},
 		}
 
-		err := hub.RsyncPrimariesTablespaces(agentConns, cluster, tablespaces)
+		err := hub.RsyncPrimariesTablespaces(context.Background(), agentConns, cluster, tablespaces)
 
 		if !errors.Is(err, expected) {
 			t.Errorf("got error %#v, want %#v", err, expected)
@@ -333,7 +334,7 @@ func TestRestoreCoordinatorAndPrimariesPgControl(t *testing.T) {
 			{AgentClient: failedClient, Hostname: "sdw2"},
 		}
 
-		err := hub.RestoreCoordinatorAndPrimariesPgControl(step.DevNullStream, agentConns, cluster)
+		err := hub.RestoreCoordinatorAndPrimariesPgControl(context.Background(), step.DevNullStream, agentConns, cluster)
 
 		var errs errorlist.Errors
 		if !errors.As(err, &errs) {
@@ -402,7 +403,7 @@ func TestRestoreCoordinatorAndPrimariesPgControl(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err = hub.RestoreCoordinatorAndPrimariesPgControl(step.DevNullStream, agentConns, cluster)
+		err = hub.RestoreCoordinatorAndPrimariesPgControl(context.Background(), step.DevNullStream, agentConns, cluster)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
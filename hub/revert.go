@@ -19,7 +19,13 @@ import (
 )
 
 func (s *Server) Revert(_ *idl.RevertRequest, stream idl.CliToHub_RevertServer) (err error) {
-	st, err := step.Begin(idl.Step_revert, stream)
+	ctx, unlock, err := s.lockStep(stream.Context(), idl.Step_revert)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	st, err := step.Begin(ctx, idl.Step_revert, stream)
 	if err != nil {
 		return err
 	}
@@ -57,7 +63,12 @@ Cannot revert and restore the source cluster. Please contact support.`)
 	}
 
 	st.RunConditionally(idl.Substep_ensure_gpupgrade_agents_are_running, configCreated && agentsStarted, func(_ step.OutStreams) error {
-		_, err := RestartAgents(context.Background(), nil, AgentHosts(s.Source), s.AgentPort, utils.GetStateDir())
+		l, err := s.Launcher()
+		if err != nil {
+			return err
+		}
+
+		_, err = RestartAgents(ctx, l, nil, AgentHosts(s.Source), s.AgentPort, s.AgentListenAddresses, utils.GetStateDir(), s.TLS)
 		if err != nil {
 			return err
 		}
@@ -68,35 +79,37 @@ Cannot revert and restore the source cluster. Please contact support.`)
 		}
 
 		return nil
-	})
+	}, step.Idempotent())
 
 	st.RunConditionally(idl.Substep_check_active_connections_on_target_cluster, configCreated, func(streams step.OutStreams) error {
 		return s.Intermediate.CheckActiveConnections(streams)
-	})
+	}, step.Idempotent())
 
 	st.RunConditionally(idl.Substep_shutdown_target_cluster, configCreated, func(streams step.OutStreams) error {
 		return s.Intermediate.Stop(streams)
-	})
+	}, step.Idempotent())
 
 	st.RunConditionally(idl.Substep_delete_target_cluster_datadirs, configCreated, func(streams step.OutStreams) error {
-		return DeleteCoordinatorAndPrimaryDataDirectories(streams, s.agentConns, s.Intermediate)
-	})
+		return DeleteCoordinatorAndPrimaryDataDirectories(ctx, streams, s.agentConns, s.Intermediate)
+	}, step.Idempotent())
 
 	st.RunConditionally(idl.Substep_delete_tablespaces, configCreated, func(streams step.OutStreams) error {
-		return DeleteTargetTablespaces(streams, s.agentConns, s.Config.Intermediate, s.Intermediate.CatalogVersion, s.Source.Tablespaces)
-	})
+		return DeleteTargetTablespaces(ctx, streams, s.agentConns, s.Config.Intermediate, s.Intermediate.CatalogVersion, s.Source.Tablespaces)
+	}, step.Idempotent())
 
 	// See "Reverting to old cluster" from https://www.postgresql.org/docs/9.4/pgupgrade.html
 	st.RunConditionally(idl.Substep_restore_pgcontrol, configCreated && s.Mode == idl.Mode_link, func(streams step.OutStreams) error {
-		return RestoreCoordinatorAndPrimariesPgControl(streams, s.agentConns, s.Source)
+		return RestoreCoordinatorAndPrimariesPgControl(ctx, streams, s.agentConns, s.Source)
 	})
 
 	st.RunConditionally(idl.Substep_restore_source_cluster, configCreated && s.Mode == idl.Mode_link && s.Source.HasAllMirrorsAndStandby(), func(stream step.OutStreams) error {
-		if err := RsyncCoordinatorAndPrimaries(stream, s.agentConns, s.Source); err != nil {
-			return err
-		}
+		return MonitorAgents(ctx, stream, s.agentConns, func(ctx context.Context) error {
+			if err := RsyncCoordinatorAndPrimaries(ctx, stream, s.agentConns, s.Source); err != nil {
+				return err
+			}
 
-		return RsyncCoordinatorAndPrimariesTablespaces(stream, s.agentConns, s.Source)
+			return RsyncCoordinatorAndPrimariesTablespaces(ctx, stream, s.agentConns, s.Source)
+		})
 	})
 
 	primariesUpgraded, err := step.HasRun(idl.Step_execute, idl.Substep_upgrade_primaries)
@@ -141,16 +154,16 @@ Cannot revert and restore the source cluster. Please contact support.`)
 		}
 
 		logArchiveDir = GetLogArchiveDir(logDir, s.UpgradeID, time.Now())
-		return ArchiveLogDirectories(logDir, logArchiveDir, s.agentConns, s.Config.Source.CoordinatorHostname())
-	})
+		return ArchiveLogDirectories(ctx, logDir, logArchiveDir, s.agentConns, s.Config.Source.CoordinatorHostname())
+	}, step.Idempotent())
 
 	st.RunConditionally(idl.Substep_delete_backupdir, configCreated, func(streams step.OutStreams) error {
-		return DeleteBackupDirectories(streams, s.agentConns, s.BackupDirs)
-	})
+		return DeleteBackupDirectories(ctx, streams, s.agentConns, s.BackupDirs)
+	}, step.Idempotent())
 
 	st.AlwaysRun(idl.Substep_delete_segment_statedirs, func(_ step.OutStreams) error {
-		return DeleteStateDirectories(s.agentConns, s.Source.CoordinatorHostname())
-	})
+		return DeleteStateDirectories(ctx, s.agentConns, s.Source.CoordinatorHostname())
+	}, step.Idempotent())
 
 	message := &idl.Message{Contents: &idl.Message_Response{Response: &idl.Response{Contents: &idl.Response_RevertResponse{
 		RevertResponse: &idl.RevertResponse{
@@ -4,15 +4,38 @@
 package hub
 
 import (
+	"context"
+	"io"
+	"path"
 	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
 
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+	"github.com/greenplum-db/gpupgrade/utils/logger"
 )
 
-func ExecuteRPC(agentConns []*idl.Connection, executeRequest func(conn *idl.Connection) error) error {
+// ExecuteRPC executes the request on all agents concurrently. The request
+// should issue its RPCs using ctx such that they are canceled along with ctx.
+func ExecuteRPC(ctx context.Context, agentConns []*idl.Connection, executeRequest func(conn *idl.Connection) error) error {
+	return ExecuteRPCWithLimit(ctx, agentConns, 0, executeRequest)
+}
+
+// ExecuteRPCWithLimit executes the request on at most maxHosts agents at a
+// time, or all agents when zero. Requests that have not started when ctx is
+// canceled are not executed.
+func ExecuteRPCWithLimit(ctx context.Context, agentConns []*idl.Connection, maxHosts uint, executeRequest func(conn *idl.Connection) error) error {
+	limit := len(agentConns)
+	if maxHosts > 0 && int(maxHosts) < limit {
+		limit = int(maxHosts)
+	}
+
 	var wg sync.WaitGroup
 	errs := make(chan error, len(agentConns))
+	semaphore := make(chan struct{}, limit)
 
 	for _, conn := range agentConns {
 		conn := conn
@@ -21,8 +44,20 @@ func ExecuteRPC(agentConns []*idl.Connection, executeRequest func(conn *idl.Conn
 		go func() {
 			defer wg.Done()
 
-			err := executeRequest(conn)
-			errs <- err
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				errs <- xerrors.Errorf("host %s: %w", conn.Hostname, ctx.Err())
+				return
+			}
+
+			if err := ctx.Err(); err != nil {
+				errs <- xerrors.Errorf("host %s: %w", conn.Hostname, err)
+				return
+			}
+
+			errs <- executeRequest(conn)
 		}()
 	}
 
@@ -36,3 +71,72 @@ func ExecuteRPC(agentConns []*idl.Connection, executeRequest func(conn *idl.Conn
 
 	return err
 }
+
+// logAgentRPCEvents records each RPC to the agent on host in the event log.
+func logAgentRPCEvents(host string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		logger.LogEvent(logger.Event{Type: logger.AgentRPCIssued, Host: host, Method: method})
+
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		logAgentRPCReturned(host, method, start, err)
+
+		return err
+	}
+}
+
+// logAgentStreamEvents records each streaming RPC to the agent on host in the
+// event log. The RPC has returned once the stream ends.
+func logAgentStreamEvents(host string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		logger.LogEvent(logger.Event{Type: logger.AgentRPCIssued, Host: host, Method: method})
+
+		start := time.Now()
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			logAgentRPCReturned(host, method, start, err)
+			return nil, err
+		}
+
+		return &eventClientStream{ClientStream: stream, host: host, method: method, start: start}, nil
+	}
+}
+
+type eventClientStream struct {
+	grpc.ClientStream
+	host   string
+	method string
+	start  time.Time
+	once   sync.Once
+}
+
+func (s *eventClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.once.Do(func() {
+			if err == io.EOF {
+				logAgentRPCReturned(s.host, s.method, s.start, nil)
+				return
+			}
+
+			logAgentRPCReturned(s.host, s.method, s.start, err)
+		})
+	}
+
+	return err
+}
+
+// logAgentRPCReturned records the returned RPC in the event log and the agent
+// RPC metrics.
+func logAgentRPCReturned(host string, method string, start time.Time, err error) {
+	duration := time.Since(start)
+	logger.LogEvent(logger.Event{
+		Type:            logger.AgentRPCReturned,
+		Host:            host,
+		Method:          method,
+		DurationSeconds: duration.Seconds(),
+		Error:           logger.ErrorString(err),
+	})
+
+	recordAgentRPC(host, path.Base(method), duration, err)
+}
@@ -0,0 +1,196 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcStatus "google.golang.org/grpc/status"
+)
+
+// RPCPolicy is the deadline and retry policy of an agent RPC.
+type RPCPolicy struct {
+	// Timeout is the deadline of each attempt. There is no deadline when
+	// zero.
+	Timeout time.Duration
+
+	// Retries is the number of additional attempts made when an attempt times
+	// out or the agent is unavailable. Only idempotent RPCs are retried.
+	Retries int
+
+	// Backoff is the wait before the first retry, which doubles after each
+	// retry.
+	Backoff time.Duration
+}
+
+// defaultRPCPolicies contains the policy of every unary agent RPC by name.
+// Rsyncing data directories can legitimately take hours depending on the
+// size of the cluster, so it has no deadline unless one is configured.
+// MeasureDiskSpace is the policy of CheckDiskSpace when it measures the size
+// of the data, which depends on the size of the cluster and is too slow to
+// retry.
+var defaultRPCPolicies = map[string]RPCPolicy{
+	"CheckDiskSpace":              {Timeout: 5 * time.Minute, Retries: 3, Backoff: time.Second},
+	"MeasureDiskSpace":            {},
+	"CheckPorts":                  {Timeout: 5 * time.Minute, Retries: 3, Backoff: time.Second},
+	"CreateBackupDirectory":       {Timeout: 5 * time.Minute, Retries: 3, Backoff: time.Second},
+	"ArchiveLogDirectory":         {Timeout: 10 * time.Minute, Retries: 3, Backoff: time.Second},
+	"RenameDirectories":           {Timeout: 10 * time.Minute},
+	"AlreadyRenamedDirectories":   {Timeout: 5 * time.Minute},
+	"StopAgent":                   {Timeout: time.Minute},
+	"DeleteDataDirectories":       {Timeout: time.Hour},
+	"DeleteBackupDirectory":       {Timeout: time.Hour},
+	"DeleteStateDirectory":        {Timeout: 10 * time.Minute},
+	"DeleteTablespaceDirectories": {Timeout: time.Hour},
+	"RsyncDataDirectories":        {},
+	"RsyncTablespaceDirectories":  {},
+	"RestorePrimariesPgControl":   {Timeout: 10 * time.Minute},
+	"UpdateConfiguration":         {Timeout: 10 * time.Minute},
+	"RenameTablespaces":           {Timeout: 10 * time.Minute},
+	"CreateRecoveryConf":          {Timeout: 10 * time.Minute},
+	"AddReplicationEntries":       {Timeout: 10 * time.Minute},
+	"GetInfo":                     {Timeout: time.Minute, Retries: 3, Backoff: time.Second},
+}
+
+var rpcPolicies = defaultRPCPolicies
+
+func SetRPCPolicies(policies map[string]RPCPolicy) {
+	rpcPolicies = policies
+}
+
+func ResetRPCPolicies() {
+	rpcPolicies = defaultRPCPolicies
+}
+
+// measureDiskSpacePolicy names the policy of CheckDiskSpace when it measures
+// the size of the data.
+const measureDiskSpacePolicy = "MeasureDiskSpace"
+
+type rpcPolicyKey struct{}
+
+// withRPCPolicy applies the named policy rather than the policy of the RPC to
+// the agent RPCs made with the context.
+func withRPCPolicy(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, rpcPolicyKey{}, name)
+}
+
+// ParseRPCTimeouts parses a comma separated list of agent RPC timeouts such
+// as "DeleteDataDirectories=30m,RsyncDataDirectories=4h".
+func ParseRPCTimeouts(val string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration)
+
+	if val == "" {
+		return timeouts, nil
+	}
+
+	for _, entry := range strings.Split(val, ",") {
+		parts := strings.Split(entry, "=")
+		if len(parts) != 2 {
+			return nil, xerrors.Errorf("failed to parse agent RPC timeout %q. Expected the form RPC=duration such as DeleteDataDirectories=30m.", entry)
+		}
+
+		name := strings.TrimSpace(parts[0])
+		if _, ok := defaultRPCPolicies[name]; !ok {
+			return nil, xerrors.Errorf("unknown agent RPC %q. Expected one of %s.", name, strings.Join(rpcNames(), ", "))
+		}
+
+		timeout, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, xerrors.Errorf("failed to parse timeout of agent RPC %s: %w", name, err)
+		}
+
+		if timeout < 0 {
+			return nil, xerrors.Errorf("invalid negative timeout %s for agent RPC %s", timeout, name)
+		}
+
+		timeouts[name] = timeout
+	}
+
+	return timeouts, nil
+}
+
+func rpcNames() []string {
+	var names []string
+	for name := range defaultRPCPolicies {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// applyRPCPolicies enforces the policy of each unary RPC to the agent on host.
+// The timeouts override the default deadline of the named RPCs. Errors name
+// the host such that a hung host can be identified.
+func applyRPCPolicies(host string, timeouts map[string]time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		name := path.Base(method)
+		if policyName, ok := ctx.Value(rpcPolicyKey{}).(string); ok {
+			name = policyName
+		}
+
+		policy := rpcPolicies[name]
+		if timeout, ok := timeouts[name]; ok {
+			policy.Timeout = timeout
+		}
+
+		backoff := policy.Backoff
+		var err error
+		for attempt := 0; attempt <= policy.Retries; attempt++ {
+			if attempt > 0 {
+				select {
+				case <-time.After(backoff):
+				case <-ctx.Done():
+					return xerrors.Errorf("%s on host %s: %w", name, host, err)
+				}
+
+				backoff *= 2
+			}
+
+			err = invokeWithTimeout(ctx, policy.Timeout, method, req, reply, cc, invoker, opts...)
+			if err == nil {
+				return nil
+			}
+
+			if ctx.Err() != nil || !isTransient(err) {
+				break
+			}
+		}
+
+		if grpcStatus.Code(err) == codes.DeadlineExceeded && ctx.Err() == nil {
+			return xerrors.Errorf("%s on host %s timed out after %s: %w", name, host, policy.Timeout, err)
+		}
+
+		return err
+	}
+}
+
+func invokeWithTimeout(ctx context.Context, timeout time.Duration, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if timeout == 0 {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// isTransient returns whether the attempt may succeed when retried, such as
+// when it timed out or the agent was briefly unreachable.
+func isTransient(err error) bool {
+	switch grpcStatus.Code(err) {
+	case codes.DeadlineExceeded, codes.Unavailable:
+		return true
+	default:
+		return false
+	}
+}
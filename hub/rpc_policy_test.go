@@ -0,0 +1,217 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/config/backupdir"
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/testutils/mock_agent"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+	"github.com/greenplum-db/gpupgrade/utils/disk"
+)
+
+func TestRPCPolicies(t *testing.T) {
+	testlog.SetupTestLogger()
+
+	source := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Port: 15432, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 2, Port: 25432, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Role: greenplum.PrimaryRole},
+	})
+
+	agentServer, dialer, agentPort := mock_agent.NewMockAgentServer()
+	defer agentServer.Stop()
+
+	hub.SetgRPCDialer(dialer)
+	defer hub.ResetgRPCDialer()
+
+	conf := &config.Config{
+		Source:           source,
+		Target:           source,
+		Intermediate:     &greenplum.Cluster{},
+		HubPort:          testutils.MustGetPort(t),
+		AgentPort:        agentPort,
+		Mode:             idl.Mode_copy,
+		AgentRPCTimeouts: map[string]time.Duration{"DeleteDataDirectories": 50 * time.Millisecond},
+	}
+
+	hubServer := hub.New(conf)
+	defer hubServer.Stop(true)
+
+	agentConns, err := hubServer.AgentConns()
+	if err != nil {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+	conn := agentConns[0]
+
+	t.Run("times out an RPC using the configured timeout and names the host", func(t *testing.T) {
+		agentServer.Delays <- time.Hour
+		calls := agentServer.NumberOfCalls()
+
+		_, err := conn.AgentClient.DeleteDataDirectories(context.Background(), &idl.DeleteDataDirectoriesRequest{})
+
+		expected := "DeleteDataDirectories on host sdw1 timed out after 50ms"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("got error %v want it to contain %q", err, expected)
+		}
+
+		// RPCs that are not idempotent are not retried.
+		if agentServer.NumberOfCalls()-calls != 1 {
+			t.Errorf("got %d calls want 1", agentServer.NumberOfCalls()-calls)
+		}
+	})
+
+	t.Run("retries an idempotent RPC that timed out", func(t *testing.T) {
+		hub.SetRPCPolicies(map[string]hub.RPCPolicy{
+			"CheckDiskSpace": {Timeout: 50 * time.Millisecond, Retries: 2, Backoff: 10 * time.Millisecond},
+		})
+		defer hub.ResetRPCPolicies()
+
+		agentServer.Delays <- time.Hour
+		calls := agentServer.NumberOfCalls()
+
+		_, err := conn.AgentClient.CheckDiskSpace(context.Background(), &idl.CheckSegmentDiskSpaceRequest{})
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		if agentServer.NumberOfCalls()-calls != 2 {
+			t.Errorf("got %d calls want 2", agentServer.NumberOfCalls()-calls)
+		}
+	})
+
+	t.Run("errors naming the host when all retries of an idempotent RPC time out", func(t *testing.T) {
+		hub.SetRPCPolicies(map[string]hub.RPCPolicy{
+			"CheckDiskSpace": {Timeout: 50 * time.Millisecond, Retries: 2, Backoff: 10 * time.Millisecond},
+		})
+		defer hub.ResetRPCPolicies()
+
+		for i := 0; i < 3; i++ {
+			agentServer.Delays <- time.Hour
+		}
+		calls := agentServer.NumberOfCalls()
+
+		_, err := conn.AgentClient.CheckDiskSpace(context.Background(), &idl.CheckSegmentDiskSpaceRequest{})
+
+		expected := "CheckDiskSpace on host sdw1 timed out after 50ms"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("got error %v want it to contain %q", err, expected)
+		}
+
+		if agentServer.NumberOfCalls()-calls != 3 {
+			t.Errorf("got %d calls want 3", agentServer.NumberOfCalls()-calls)
+		}
+	})
+
+	t.Run("measures disk space using its own policy", func(t *testing.T) {
+		hub.SetRPCPolicies(map[string]hub.RPCPolicy{
+			"CheckDiskSpace":   {Timeout: 50 * time.Millisecond, Retries: 2, Backoff: 10 * time.Millisecond},
+			"MeasureDiskSpace": {},
+		})
+		defer hub.ResetRPCPolicies()
+
+		hub.SetDirSize(func(ctx context.Context, path string) (uint64, error) {
+			return 1, nil
+		})
+		defer hub.ResetDirSize()
+
+		hub.SetCheckDiskRequirements(func(ctx context.Context, streams step.OutStreams, d disk.Disk, requirements ...*idl.DiskSpaceRequirement) (disk.FileSystemDiskUsage, error) {
+			return nil, nil
+		})
+		defer hub.ResetCheckDiskRequirements()
+
+		agentServer.Delays <- 200 * time.Millisecond
+		calls := agentServer.NumberOfCalls()
+
+		err := hub.CheckMeasuredDiskSpace(context.Background(), step.DevNullStream, agentConns, idl.Mode_copy, source, nil, backupdir.BackupDirs{})
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		if agentServer.NumberOfCalls()-calls != 1 {
+			t.Errorf("got %d calls want 1", agentServer.NumberOfCalls()-calls)
+		}
+	})
+
+	t.Run("does not retry once the context is canceled", func(t *testing.T) {
+		hub.SetRPCPolicies(map[string]hub.RPCPolicy{
+			"CheckDiskSpace": {Retries: 2, Backoff: 10 * time.Millisecond},
+		})
+		defer hub.ResetRPCPolicies()
+
+		agentServer.Delays <- time.Hour
+		calls := agentServer.NumberOfCalls()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			cancel()
+		}()
+
+		_, err := conn.AgentClient.CheckDiskSpace(ctx, &idl.CheckSegmentDiskSpaceRequest{})
+		if err == nil {
+			t.Error("expected an error")
+		}
+
+		if agentServer.NumberOfCalls()-calls != 1 {
+			t.Errorf("got %d calls want 1", agentServer.NumberOfCalls()-calls)
+		}
+	})
+}
+
+func TestParseRPCTimeouts(t *testing.T) {
+	t.Run("parses timeouts", func(t *testing.T) {
+		cases := []struct {
+			input    string
+			expected map[string]time.Duration
+		}{
+			{"", map[string]time.Duration{}},
+			{"DeleteDataDirectories=30m", map[string]time.Duration{"DeleteDataDirectories": 30 * time.Minute}},
+			{"DeleteDataDirectories=30m, RsyncDataDirectories=4h", map[string]time.Duration{
+				"DeleteDataDirectories": 30 * time.Minute,
+				"RsyncDataDirectories":  4 * time.Hour,
+			}},
+			{"CheckDiskSpace=0s", map[string]time.Duration{"CheckDiskSpace": 0}},
+		}
+
+		for _, c := range cases {
+			actual, err := hub.ParseRPCTimeouts(c.input)
+			if err != nil {
+				t.Errorf("ParseRPCTimeouts(%q) returned error %#v", c.input, err)
+			}
+
+			if !reflect.DeepEqual(actual, c.expected) {
+				t.Errorf("ParseRPCTimeouts(%q) returned %v, want %v", c.input, actual, c.expected)
+			}
+		}
+	})
+
+	t.Run("errors when parsing fails", func(t *testing.T) {
+		cases := []string{
+			"DeleteDataDirectories",
+			"DeleteDataDirectories=30m=1h",
+			"DeleteDataDirectories=soon",
+			"DeleteDataDirectories=-1m",
+			"UnknownRPC=1m",
+			"UpgradePrimaries=1h",
+		}
+
+		for _, c := range cases {
+			actual, err := hub.ParseRPCTimeouts(c)
+			if err == nil {
+				t.Errorf("ParseRPCTimeouts(%q) returned %v instead of an error", c, actual)
+			}
+		}
+	})
+}
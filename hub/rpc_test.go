@@ -4,13 +4,18 @@
 package hub_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"reflect"
 	"sort"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/greenplum-db/gpupgrade/hub"
 	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
 )
 
 func TestExecuteRPC(t *testing.T) {
@@ -26,7 +31,7 @@ func TestExecuteRPC(t *testing.T) {
 			return nil
 		}
 
-		err := hub.ExecuteRPC(agentConns, request)
+		err := hub.ExecuteRPC(context.Background(), agentConns, request)
 		if err != nil {
 			t.Errorf("ExecuteRPC returned error %+v", err)
 		}
@@ -60,10 +65,142 @@ func TestExecuteRPC(t *testing.T) {
 			return nil
 		}
 
-		err := hub.ExecuteRPC(agentConns, request)
+		err := hub.ExecuteRPC(context.Background(), agentConns, request)
 
 		if !errors.Is(err, expected) {
 			t.Errorf("got error %#v, want %#v", err, expected)
 		}
 	})
+
+	t.Run("does not execute requests once the context is canceled", func(t *testing.T) {
+		agentConns := []*idl.Connection{
+			{Hostname: "mdw"},
+			{Hostname: "sdw"},
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var called bool
+		request := func(conn *idl.Connection) error {
+			called = true
+			return nil
+		}
+
+		err := hub.ExecuteRPC(ctx, agentConns, request)
+
+		var errs errorlist.Errors
+		if !errors.As(err, &errs) {
+			t.Fatalf("error %#v does not contain type %T", err, errs)
+		}
+
+		for _, err := range errs {
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("got error %#v, want %#v", err, context.Canceled)
+			}
+		}
+
+		if called {
+			t.Error("expected request to not be executed")
+		}
+	})
+
+	t.Run("does not execute waiting requests once the context is canceled", func(t *testing.T) {
+		agentConns := []*idl.Connection{
+			{Hostname: "sdw1"},
+			{Hostname: "sdw2"},
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var mutex sync.Mutex
+		var hosts []string
+		request := func(conn *idl.Connection) error {
+			mutex.Lock()
+			hosts = append(hosts, conn.Hostname)
+			mutex.Unlock()
+
+			// The first request is interrupted while the second waits for it.
+			cancel()
+			return ctx.Err()
+		}
+
+		err := hub.ExecuteRPCWithLimit(ctx, agentConns, 1, request)
+
+		var errs errorlist.Errors
+		if !errors.As(err, &errs) {
+			t.Fatalf("error %#v does not contain type %T", err, errs)
+		}
+
+		for _, err := range errs {
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("got error %#v, want %#v", err, context.Canceled)
+			}
+		}
+
+		if len(hosts) != 1 {
+			t.Errorf("executed requests on %v, want only one host", hosts)
+		}
+	})
+}
+
+func TestExecuteRPCWithLimit(t *testing.T) {
+	agentConns := []*idl.Connection{
+		{Hostname: "sdw1"},
+		{Hostname: "sdw2"},
+		{Hostname: "sdw3"},
+		{Hostname: "sdw4"},
+	}
+
+	cases := []struct {
+		name     string
+		maxHosts uint
+		expected int
+	}{
+		{name: "executes requests on at most the maximum number of hosts concurrently", maxHosts: 2, expected: 2},
+		{name: "executes requests on all hosts concurrently when there is no maximum", maxHosts: 0, expected: 4},
+		{name: "executes requests on all hosts concurrently when the maximum exceeds the hosts", maxHosts: 10, expected: 4},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var mutex sync.Mutex
+			var running, maxRunning int
+			var wg sync.WaitGroup
+			wg.Add(c.expected)
+
+			request := func(conn *idl.Connection) error {
+				mutex.Lock()
+				running++
+				if running > maxRunning {
+					maxRunning = running
+				}
+				mutex.Unlock()
+
+				// Only the first batch of requests waits for each other such
+				// that they are all running at once.
+				if conn.Hostname <= fmt.Sprintf("sdw%d", c.expected) {
+					wg.Done()
+					wg.Wait()
+				}
+				time.Sleep(10 * time.Millisecond)
+
+				mutex.Lock()
+				running--
+				mutex.Unlock()
+
+				return nil
+			}
+
+			err := hub.ExecuteRPCWithLimit(context.Background(), agentConns, c.maxHosts, request)
+			if err != nil {
+				t.Errorf("ExecuteRPCWithLimit returned error %+v", err)
+			}
+
+			if maxRunning != c.expected {
+				t.Errorf("got %d concurrent requests want %d", maxRunning, c.expected)
+			}
+		})
+	}
 }
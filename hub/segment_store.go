@@ -0,0 +1,109 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"sync"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+const SegmentsFileName = "segments.json"
+
+// SegmentStore tracks the pg_upgrade status of each primary segment such that
+// a retry of upgrade_primaries only re-processes the segments that failed.
+type SegmentStore interface {
+	Read(action idl.PgOptions_Action) (map[int32]idl.Status, error)
+	Write(action idl.PgOptions_Action, contentID int32, status idl.Status) error
+}
+
+// SegmentFileStore implements SegmentStore by providing persistent storage on
+// disk in the state directory.
+type SegmentFileStore struct {
+	path  string
+	mutex sync.Mutex
+}
+
+func NewSegmentFileStore() (*SegmentFileStore, error) {
+	path, err := utils.GetJSONFile(utils.GetStateDir(), SegmentsFileName)
+	if err != nil {
+		return &SegmentFileStore{}, xerrors.Errorf("read %q: %w", SegmentsFileName, err)
+	}
+
+	return &SegmentFileStore{path: path}, nil
+}
+
+func NewSegmentStoreUsingFile(path string) *SegmentFileStore {
+	return &SegmentFileStore{path: path}
+}
+
+type segmentMap = map[string]map[string]step.PrettyStatus
+
+func (f *SegmentFileStore) load() (segmentMap, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var segments segmentMap
+	err = json.Unmarshal(data, &segments)
+	if err != nil {
+		return nil, err
+	}
+
+	return segments, nil
+}
+
+func (f *SegmentFileStore) Read(action idl.PgOptions_Action) (map[int32]idl.Status, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	segments, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make(map[int32]idl.Status)
+	for content, status := range segments[action.String()] {
+		contentID, err := strconv.Atoi(content)
+		if err != nil {
+			return nil, xerrors.Errorf("parse content id %q: %w", content, err)
+		}
+
+		statuses[int32(contentID)] = status.Status
+	}
+
+	return statuses, nil
+}
+
+// Write atomically updates the status of the segment. Since each host is
+// upgraded concurrently writes are serialized.
+func (f *SegmentFileStore) Write(action idl.PgOptions_Action, contentID int32, status idl.Status) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	segments, err := f.load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := segments[action.String()]; !ok {
+		segments[action.String()] = make(map[string]step.PrettyStatus)
+	}
+	segments[action.String()][strconv.Itoa(int(contentID))] = step.PrettyStatus{Status: status}
+
+	data, err := json.MarshalIndent(segments, "", "  ") // pretty print JSON
+	if err != nil {
+		return err
+	}
+
+	return utils.AtomicallyWrite(f.path, data)
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestSegmentFileStore(t *testing.T) {
+	tmpDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, tmpDir)
+
+	path := filepath.Join(tmpDir, hub.SegmentsFileName)
+	store := hub.NewSegmentStoreUsingFile(path)
+
+	t.Run("Read returns errors when failing to read", func(t *testing.T) {
+		_, err := store.Read(idl.PgOptions_upgrade)
+		if !os.IsNotExist(err) {
+			t.Errorf("returned error %#v, want ErrNotExist", err)
+		}
+	})
+
+	t.Run("Write returns errors when failing to read", func(t *testing.T) {
+		err := store.Write(idl.PgOptions_upgrade, 0, idl.Status_complete)
+		if !os.IsNotExist(err) {
+			t.Errorf("returned error %#v, want ErrNotExist", err)
+		}
+	})
+
+	t.Run("reads the statuses that were written for the action", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, "{}")
+
+		writes := []struct {
+			action    idl.PgOptions_Action
+			contentID int32
+			status    idl.Status
+		}{
+			{action: idl.PgOptions_upgrade, contentID: 0, status: idl.Status_failed},
+			{action: idl.PgOptions_upgrade, contentID: 0, status: idl.Status_complete},
+			{action: idl.PgOptions_upgrade, contentID: 1, status: idl.Status_failed},
+			{action: idl.PgOptions_check, contentID: 2, status: idl.Status_complete},
+		}
+
+		for _, w := range writes {
+			err := store.Write(w.action, w.contentID, w.status)
+			if err != nil {
+				t.Fatalf("Write(%v, %d, %v) returned error %#v", w.action, w.contentID, w.status, err)
+			}
+		}
+
+		statuses, err := store.Read(idl.PgOptions_upgrade)
+		if err != nil {
+			t.Fatalf("Read() returned error %#v", err)
+		}
+
+		expected := map[int32]idl.Status{0: idl.Status_complete, 1: idl.Status_failed}
+		if !reflect.DeepEqual(statuses, expected) {
+			t.Errorf("read %v, want %v", statuses, expected)
+		}
+	})
+
+	t.Run("persists human readable statuses", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, "{}")
+
+		err := store.Write(idl.PgOptions_upgrade, 3, idl.Status_complete)
+		if err != nil {
+			t.Fatalf("Write() returned error %#v", err)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		expected := "{\n  \"upgrade\": {\n    \"3\": \"complete\"\n  }\n}"
+		if contents != expected {
+			t.Errorf("got %q want %q", contents, expected)
+		}
+	})
+
+	t.Run("reads no statuses when nothing has been written", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, "{}")
+
+		statuses, err := store.Read(idl.PgOptions_upgrade)
+		if err != nil {
+			t.Fatalf("Read() returned error %#v", err)
+		}
+
+		if len(statuses) != 0 {
+			t.Errorf("read %v, want no statuses", statuses)
+		}
+	})
+}
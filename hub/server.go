@@ -5,9 +5,11 @@ package hub
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"strconv"
 	"strings"
@@ -22,13 +24,17 @@ import (
 	"google.golang.org/grpc/reflection"
 	grpcStatus "google.golang.org/grpc/status"
 
+	"github.com/greenplum-db/gpupgrade/agent/launcher"
 	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/config/listenaddress"
 	"github.com/greenplum-db/gpupgrade/greenplum"
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/utils"
 	"github.com/greenplum-db/gpupgrade/utils/daemon"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
 	"github.com/greenplum-db/gpupgrade/utils/logger"
+	"github.com/greenplum-db/gpupgrade/utils/metrics"
+	"github.com/greenplum-db/gpupgrade/utils/mtls"
 )
 
 var DialTimeout = 3 * time.Second
@@ -44,6 +50,9 @@ type Server struct {
 	gRPCserver *grpc.Server
 	listener   net.Listener
 
+	// metricsServer serves Prometheus metrics when a metrics port is set.
+	metricsServer *http.Server
+
 	// This is used both as a channel to communicate from Start() to
 	// Stop() to indicate to Stop() that it can finally terminate
 	// and also as a flag to communicate from Stop() to Start() that
@@ -52,42 +61,63 @@ type Server struct {
 	// Note that when used as a flag, nil value means that Stop() has
 	// been called.
 	stopped chan struct{}
+
+	// stepLock ensures a single step runs at a time, and that the hub is not
+	// stopped while it is in progress.
+	stepLock *stepLock
 }
 
 func New(conf *config.Config) *Server {
 	return &Server{
-		Config:  conf,
-		stopped: make(chan struct{}, 1),
+		Config:   conf,
+		stopped:  make(chan struct{}, 1),
+		stepLock: newStepLock(),
 	}
 }
 
 func (s *Server) Start(port int, daemonize bool) error {
-	listener, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+	opts, err := s.TLS.HubServerOptions()
+	if err != nil {
+		return err
+	}
+
+	address := net.JoinHostPort(s.HubListenAddress, strconv.Itoa(port))
+	listener, err := net.Listen("tcp", address)
 	if err != nil {
-		return fmt.Errorf("listen on port %d: %w", port, err)
+		return fmt.Errorf("listen on %q: %w", address, err)
 	}
 
 	interceptor := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
 		defer logger.WritePanics()
 		return handler(ctx, req)
 	}
-	gRPCserver := grpc.NewServer(grpc.UnaryInterceptor(interceptor))
+	gRPCserver := grpc.NewServer(append(opts, grpc.UnaryInterceptor(interceptor))...)
+
+	metricsServer, err := s.startMetricsServer()
+	if err != nil {
+		listener.Close()
+		return err
+	}
 
 	s.mutex.Lock()
 	if s.stopped == nil {
 		// Stop() has already been called; return without serving.
 		s.mutex.Unlock()
+		if metricsServer != nil {
+			metricsServer.Close()
+		}
 		return ErrHubStopped
 	}
 	s.gRPCserver = gRPCserver
 	s.listener = listener
+	s.metricsServer = metricsServer
 	s.mutex.Unlock()
 
 	idl.RegisterCliToHubServer(gRPCserver, s)
 	reflection.Register(gRPCserver)
 
 	if daemonize {
-		fmt.Printf("Hub started on port %d with pid %d\n", port, os.Getpid())
+		fmt.Printf("Hub started on %s with pid %d\n", address, os.Getpid())
 		daemon.Daemonize()
 	}
 
@@ -96,21 +126,99 @@ func (s *Server) Start(port int, daemonize bool) error {
 		return fmt.Errorf("hub gRPC Serve: %w", err)
 	}
 
+	// A step still running was interrupted by forcibly stopping the hub.
+	if err := s.stepLock.failInterrupted(interruptedStepTimeout); err != nil {
+		log.Printf("failing interrupted substeps: %v", err)
+	}
+
 	// inform Stop() that is it is OK to stop now
 	s.stopped <- struct{}{}
 	return nil
 }
 
+// startMetricsServer serves Prometheus metrics on the metrics port. It returns
+// nil when metrics are disabled. When TLS is enabled scrapers must present a
+// certificate signed by the CA.
+func (s *Server) startMetricsServer() (*http.Server, error) {
+	if s.MetricsPort == 0 {
+		return nil, nil
+	}
+
+	tlsConfig, err := s.TLS.MetricsTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	address := net.JoinHostPort(s.HubListenAddress, strconv.Itoa(s.MetricsPort))
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("listen for metrics on %q: %w", address, err)
+	}
+
+	// Serve metrics over TLS with the hub certificate when TLS is enabled.
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	server := &http.Server{Handler: mux}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("serve metrics: %v", err)
+		}
+	}()
+
+	return server, nil
+}
+
+// StopServices stops the agents and hub. It waits for any running step to
+// finish, and unless forced errors when the step is still running after the
+// wait.
 func (s *Server) StopServices(ctx context.Context, in *idl.StopServicesRequest) (*idl.StopServicesReply, error) {
-	err := s.StopAgents()
+	err := s.stepLock.drain(in.GetWait().AsDuration(), in.GetForce())
+	if err != nil {
+		return &idl.StopServicesReply{}, err
+	}
+
+	err = s.StopAgents()
 	if err != nil {
 		log.Printf("stop agents: %v", err)
 	}
 
-	defer s.Stop(false)
+	// Stop once this request has finished since gracefully stopping waits for
+	// in-flight requests. When forced there is no need to wait any longer for
+	// the running steps.
+	timeout := gracefulStopTimeout
+	if in.GetForce() {
+		timeout = 0
+	}
+
+	go s.stop(false, timeout)
 	return &idl.StopServicesReply{}, nil
 }
 
+// lockStep locks the step for the client until the returned function is
+// called. The returned context is canceled when the client goes away or the
+// lock is broken. It errors when another step is running or the hub is
+// stopping.
+func (s *Server) lockStep(ctx context.Context, name idl.Step) (context.Context, func(), error) {
+	return s.stepLock.acquire(ctx, stepOwner(ctx, name))
+}
+
+// BreakStepLock cancels the running step and unlocks it such that another
+// step can run. This is for when the client running the step is stuck or
+// gone.
+func (s *Server) BreakStepLock(ctx context.Context, in *idl.BreakStepLockRequest) (*idl.BreakStepLockReply, error) {
+	owner, err := s.stepLock.breakLock(breakStepLockTimeout)
+	if err != nil {
+		return &idl.BreakStepLockReply{}, err
+	}
+
+	return &idl.BreakStepLockReply{Lock: owner}, nil
+}
+
 // TODO: Add unit tests which is currently tricky due to h.AgentConns() mutating global state
 func (s *Server) StopAgents() error {
 	request := func(conn *idl.Connection) error {
@@ -135,10 +243,14 @@ func (s *Server) StopAgents() error {
 	if err != nil {
 		return err
 	}
-	return ExecuteRPC(s.agentConns, request)
+	return ExecuteRPC(context.Background(), s.agentConns, request)
 }
 
 func (s *Server) Stop(closeAgentConns bool) {
+	s.stop(closeAgentConns, gracefulStopTimeout)
+}
+
+func (s *Server) stop(closeAgentConns bool, timeout time.Duration) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
@@ -147,8 +259,14 @@ func (s *Server) Stop(closeAgentConns bool) {
 		s.closeAgentConns()
 	}
 
+	if s.metricsServer != nil {
+		if err := s.metricsServer.Close(); err != nil {
+			log.Printf("stop metrics server: %v", err)
+		}
+	}
+
 	if s.gRPCserver != nil {
-		s.gRPCserver.Stop()
+		gracefulStop(s.gRPCserver, timeout)
 		<-s.stopped // block until it is OK to stop
 	}
 
@@ -157,8 +275,52 @@ func (s *Server) Stop(closeAgentConns bool) {
 	s.stopped = nil
 }
 
+var gracefulStopTimeout = 30 * time.Second
+
+// breakStepLockTimeout is how long to wait for a step canceled by breaking its
+// lock to return before allowing another step to run.
+var breakStepLockTimeout = 30 * time.Second
+
+// interruptedStepTimeout is how long to wait for a step interrupted by
+// forcibly stopping the hub to return before marking its substeps failed.
+var interruptedStepTimeout = 10 * time.Second
+
+// XXX: for internal testing only
+func SetGracefulStopTimeout(timeout time.Duration) {
+	gracefulStopTimeout = timeout
+}
+
+// XXX: for internal testing only
+func ResetGracefulStopTimeout() {
+	gracefulStopTimeout = 30 * time.Second
+}
+
+// gracefulStop stops the server once the in-flight requests have finished,
+// and forcibly stops it if they have not finished within the timeout, such as
+// when a step is interrupted.
+func gracefulStop(server *grpc.Server, timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		log.Printf("in-flight requests did not finish within %s. Stopping the hub.", timeout)
+		server.Stop()
+		<-done
+	}
+}
+
 func (s *Server) RestartAgents(ctx context.Context, in *idl.RestartAgentsRequest) (*idl.RestartAgentsReply, error) {
-	restartedHosts, err := RestartAgents(ctx, nil, AgentHosts(s.Source), s.AgentPort, utils.GetStateDir())
+	l, err := s.Launcher()
+	if err != nil {
+		return &idl.RestartAgentsReply{}, err
+	}
+
+	restartedHosts, err := RestartAgents(ctx, l, nil, AgentHosts(s.Source), s.AgentPort, s.AgentListenAddresses, utils.GetStateDir(), s.TLS)
 	if err != nil {
 		return &idl.RestartAgentsReply{}, err
 	}
@@ -172,10 +334,23 @@ func (s *Server) RestartAgents(ctx context.Context, in *idl.RestartAgentsRequest
 }
 
 func RestartAgents(ctx context.Context,
+	l launcher.Launcher,
 	dialer func(context.Context, string) (net.Conn, error),
 	hostnames []string,
 	port int,
-	stateDir string) ([]string, error) {
+	listenAddresses listenaddress.AgentListenAddresses,
+	stateDir string,
+	tlsConfig mtls.Config) ([]string, error) {
+
+	credentials, err := tlsConfig.HubDialOption()
+	if err != nil {
+		return nil, err
+	}
+
+	tlsArgs, err := tlsConfig.AgentArgs()
+	if err != nil {
+		return nil, err
+	}
 
 	var wg sync.WaitGroup
 	restartedHosts := make(chan string, len(hostnames))
@@ -186,11 +361,11 @@ func RestartAgents(ctx context.Context,
 		go func(host string) {
 			defer wg.Done()
 
-			address := host + ":" + strconv.Itoa(port)
+			address := listenAddresses.DialAddress(host, port)
 			timeoutCtx, cancelFunc := context.WithTimeout(ctx, 3*time.Second)
 			opts := []grpc.DialOption{
 				grpc.WithBlock(),
-				grpc.WithInsecure(),
+				credentials,
 				grpc.FailOnNonTempDialError(true),
 			}
 			if dialer != nil {
@@ -209,20 +384,18 @@ func RestartAgents(ctx context.Context,
 			log.Printf("failed to dial agent on %s: %v", host, err)
 			log.Printf("starting agent on %s", host)
 
-			path, err := utils.GetGpupgradePath()
-			if err != nil {
-				errs <- err
-				return
+			agentArgs := []string{"--port", strconv.Itoa(port), "--state-directory", stateDir}
+			if address, ok := listenAddresses[host]; ok {
+				agentArgs = append(agentArgs, "--listen-address", address)
 			}
-			cmd := ExecCommand("ssh", host,
-				fmt.Sprintf("bash -c \"%s agent --daemonize --port %d --state-directory %s\"", path, port, stateDir))
-			stdout, err := cmd.Output()
+			agentArgs = append(agentArgs, tlsArgs...)
+
+			err = l.Start(host, agentArgs)
 			if err != nil {
 				errs <- err
 				return
 			}
 
-			log.Print(string(stdout))
 			restartedHosts <- host
 		}(host)
 	}
@@ -236,7 +409,6 @@ func RestartAgents(ctx context.Context,
 		hosts = append(hosts, h)
 	}
 
-	var err error
 	for e := range errs {
 		err = errorlist.Append(err, e)
 	}
@@ -244,6 +416,26 @@ func RestartAgents(ctx context.Context,
 	return hosts, err
 }
 
+// newLauncher returns the agent launcher chosen by the configuration.
+var newLauncher = launcher.New
+
+// XXX: for internal testing only
+func SetLauncher(l launcher.Launcher) {
+	newLauncher = func(string) (launcher.Launcher, error) {
+		return l, nil
+	}
+}
+
+// XXX: for internal testing only
+func ResetLauncher() {
+	newLauncher = launcher.New
+}
+
+// Launcher returns the agent launcher chosen by the configuration.
+func (s *Server) Launcher() (launcher.Launcher, error) {
+	return newLauncher(s.AgentLauncher)
+}
+
 var gRPCDialer = grpc.DialContext
 
 func SetgRPCDialer(dialer func(ctx context.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error)) {
@@ -272,12 +464,19 @@ func (s *Server) AgentConns() ([]*idl.Connection, error) {
 		return s.agentConns, nil
 	}
 
+	credentials, err := s.TLS.HubDialOption()
+	if err != nil {
+		return nil, err
+	}
+
 	hostnames := AgentHosts(s.Source)
 	for _, host := range hostnames {
 		ctx, cancelFunc := context.WithTimeout(context.Background(), DialTimeout)
 		conn, err := gRPCDialer(ctx,
-			host+":"+strconv.Itoa(s.AgentPort),
-			grpc.WithInsecure(), grpc.WithBlock())
+			s.AgentListenAddresses.DialAddress(host, s.AgentPort),
+			credentials, grpc.WithBlock(),
+			grpc.WithChainUnaryInterceptor(applyRPCPolicies(host, s.AgentRPCTimeouts), logAgentRPCEvents(host)),
+			grpc.WithStreamInterceptor(logAgentStreamEvents(host)))
 		if err != nil {
 			cancelFunc()
 			return nil, xerrors.Errorf("agent connections: %w", err)
@@ -7,9 +7,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
+	"net/http"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -19,12 +22,14 @@ import (
 	"google.golang.org/grpc/connectivity"
 
 	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/config/listenaddress"
 	"github.com/greenplum-db/gpupgrade/greenplum"
 	"github.com/greenplum-db/gpupgrade/hub"
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/testutils"
 	"github.com/greenplum-db/gpupgrade/testutils/mock_agent"
 	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+	"github.com/greenplum-db/gpupgrade/utils/logger"
 )
 
 const timeout = 1 * time.Second
@@ -84,7 +89,7 @@ func TestHubStart(t *testing.T) {
 
 		select {
 		case err := <-errChan:
-			expected := fmt.Sprintf("listen on port %d: listen tcp :%d: bind: address already in use", portInUse, portInUse)
+			expected := fmt.Sprintf("listen on \":%d\": listen tcp :%d: bind: address already in use", portInUse, portInUse)
 			if err != nil && !strings.Contains(err.Error(), expected) {
 				t.Errorf("got error %#v want %#v", err, expected)
 			}
@@ -95,6 +100,88 @@ func TestHubStart(t *testing.T) {
 		}
 	})
 
+	t.Run("start listens on the configured listen address", func(t *testing.T) {
+		port := testutils.MustGetPort(t)
+		hubServer := hub.New(&config.Config{HubListenAddress: "192.0.2.1"})
+
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- hubServer.Start(port, false)
+		}()
+
+		// 192.0.2.0/24 is reserved for documentation and is never assigned to
+		// a local interface.
+		select {
+		case err := <-errChan:
+			expected := fmt.Sprintf("listen on \"192.0.2.1:%d\"", port)
+			if err == nil || !strings.Contains(err.Error(), expected) {
+				t.Errorf("got error %#v want %q", err, expected)
+			}
+		case <-time.After(timeout):
+			hubServer.Stop(false)
+			t.Error("timeout exceeded")
+		}
+	})
+
+	t.Run("serves metrics when a metrics port is set", func(t *testing.T) {
+		port := testutils.MustGetPort(t)
+		metricsPort := testutils.MustGetPort(t)
+		hubServer := hub.New(&config.Config{HubListenAddress: "localhost", MetricsPort: metricsPort})
+
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- hubServer.Start(port, false)
+		}()
+		defer hubServer.Stop(false)
+
+		url := fmt.Sprintf("http://localhost:%d/metrics", metricsPort)
+		var resp *http.Response
+		var err error
+		for start := time.Now(); time.Since(start) < timeout; time.Sleep(10 * time.Millisecond) {
+			resp, err = http.Get(url)
+			if err == nil {
+				break
+			}
+		}
+		if err != nil {
+			t.Fatalf("get %s: %v", url, err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := "# TYPE gpupgrade_agent_rpc_duration_seconds histogram\n"
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("expected metrics to contain %q got %q", expected, body)
+		}
+	})
+
+	t.Run("start returns an error when the metrics port is in use", func(t *testing.T) {
+		portInUse, closeListener := mustListen(t)
+		defer closeListener()
+
+		hubServer := hub.New(&config.Config{MetricsPort: portInUse})
+
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- hubServer.Start(testutils.MustGetPort(t), false)
+		}()
+
+		select {
+		case err := <-errChan:
+			expected := fmt.Sprintf("listen for metrics on \":%d\"", portInUse)
+			if err == nil || !strings.Contains(err.Error(), expected) {
+				t.Errorf("got error %#v want %q", err, expected)
+			}
+		case <-time.After(timeout):
+			hubServer.Stop(false)
+			t.Error("timeout exceeded")
+		}
+	})
+
 	// This is inherently testing a race. It will give false successes instead
 	// of false failures, so DO NOT ignore transient failures in this test!
 	t.Run("will return from Start() if Stop is called concurrently", func(t *testing.T) {
@@ -119,6 +206,173 @@ func TestHubStart(t *testing.T) {
 	})
 }
 
+func TestHubStop(t *testing.T) {
+	testlog.SetupTestLogger()
+
+	source := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Port: 15432, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 2, Port: 25432, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Role: greenplum.PrimaryRole},
+	})
+
+	agentServer, dialer, agentPort := mock_agent.NewMockAgentServer()
+	defer agentServer.Stop()
+
+	hub.SetgRPCDialer(dialer)
+	defer hub.ResetgRPCDialer()
+
+	// startHub starts the hub and returns a client connected to it along
+	// with a channel receiving the result of Start.
+	startHub := func(t *testing.T) (*hub.Server, idl.CliToHubClient, chan error) {
+		t.Helper()
+
+		conf := &config.Config{
+			Source:           source,
+			Target:           source,
+			Intermediate:     &greenplum.Cluster{},
+			HubListenAddress: "localhost",
+			HubPort:          testutils.MustGetPort(t),
+			AgentPort:        agentPort,
+			Mode:             idl.Mode_copy,
+		}
+
+		hubServer := hub.New(conf)
+
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- hubServer.Start(conf.HubPort, false)
+		}()
+
+		// Connect to the agents up front such that requests do not contend
+		// with stopping the hub for the agent connections.
+		_, err := hubServer.AgentConns()
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		conn, err := grpc.DialContext(ctx, net.JoinHostPort("localhost", strconv.Itoa(conf.HubPort)), grpc.WithInsecure(), grpc.WithBlock())
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+		t.Cleanup(func() { conn.Close() })
+
+		return hubServer, idl.NewCliToHubClient(conn), errChan
+	}
+
+	t.Run("waits for in-flight requests to finish", func(t *testing.T) {
+		hubServer, client, errChan := startHub(t)
+
+		agentServer.Delays <- 200 * time.Millisecond
+
+		type result struct {
+			reply *idl.GetAgentsReply
+			err   error
+		}
+
+		results := make(chan result, 1)
+		go func() {
+			reply, err := client.GetAgents(context.Background(), &idl.GetAgentsRequest{})
+			results <- result{reply, err}
+		}()
+
+		// Give the request time to start before stopping.
+		time.Sleep(50 * time.Millisecond)
+		hubServer.Stop(false)
+
+		select {
+		case result := <-results:
+			if result.err != nil {
+				t.Errorf("unexpected error: %#v", result.err)
+			}
+
+			agents := result.reply.GetAgents()
+			if len(agents) != 1 || agents[0].GetError() != "" {
+				t.Errorf("got agents %v want the agent on sdw1 without error", agents)
+			}
+		case <-time.After(timeout):
+			t.Error("timeout exceeded")
+		}
+
+		select {
+		case err := <-errChan:
+			if err != nil {
+				t.Errorf("unexpected error: %#v", err)
+			}
+		case <-time.After(timeout):
+			t.Error("timeout exceeded")
+		}
+	})
+
+	t.Run("stops when in-flight requests do not finish within the timeout", func(t *testing.T) {
+		hub.SetGracefulStopTimeout(50 * time.Millisecond)
+		defer hub.ResetGracefulStopTimeout()
+
+		hubServer, client, errChan := startHub(t)
+
+		agentServer.Delays <- time.Hour
+
+		replyErr := make(chan error, 1)
+		go func() {
+			_, err := client.GetAgents(context.Background(), &idl.GetAgentsRequest{})
+			replyErr <- err
+		}()
+
+		time.Sleep(50 * time.Millisecond)
+		hubServer.Stop(false)
+
+		select {
+		case err := <-errChan:
+			if err != nil {
+				t.Errorf("unexpected error: %#v", err)
+			}
+		case <-time.After(timeout):
+			t.Error("timeout exceeded")
+		}
+
+		select {
+		case err := <-replyErr:
+			if err == nil {
+				t.Error("expected the interrupted request to fail")
+			}
+		case <-time.After(timeout):
+			t.Error("timeout exceeded")
+		}
+	})
+
+	t.Run("StopServices stops the hub when no steps are running", func(t *testing.T) {
+		_, client, errChan := startHub(t)
+
+		_, err := client.StopServices(context.Background(), &idl.StopServicesRequest{})
+		if err != nil {
+			t.Errorf("unexpected error: %#v", err)
+		}
+
+		select {
+		case err := <-errChan:
+			if err != nil {
+				t.Errorf("unexpected error: %#v", err)
+			}
+		case <-time.After(timeout):
+			t.Error("timeout exceeded")
+		}
+	})
+}
+
+func TestBreakStepLock(t *testing.T) {
+	testlog.SetupTestLogger()
+
+	t.Run("errors when no step is running", func(t *testing.T) {
+		hubServer := hub.New(&config.Config{})
+
+		_, err := hubServer.BreakStepLock(context.Background(), &idl.BreakStepLockRequest{})
+		if !errors.Is(err, hub.ErrNoStepLock) {
+			t.Errorf("got error %#v want %#v", err, hub.ErrNoStepLock)
+		}
+	})
+}
+
 // getTcpListener returns a net.Listener and a function to close the listener
 // for use in a defer.
 func getTcpListener(t *testing.T) (net.Listener, func()) {
@@ -253,6 +507,123 @@ func TestAgentConns(t *testing.T) {
 		}
 	})
 
+	t.Run("records agent RPCs in the event log", func(t *testing.T) {
+		output, reset := testlog.SetupTestEvents()
+		defer reset()
+
+		hubServer := hub.New(conf)
+		defer hubServer.Stop(true)
+
+		agentConns, err := hubServer.AgentConns()
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		conn := agentConns[0]
+		_, err = conn.AgentClient.CheckDiskSpace(context.Background(), &idl.CheckSegmentDiskSpaceRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		stream, err := conn.AgentClient.UpgradePrimaries(context.Background(), &idl.UpgradePrimariesRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		_, err = stream.Recv()
+		if err != io.EOF {
+			t.Fatalf("got error %#v want io.EOF", err)
+		}
+
+		events := testlog.ParseEvents(t, output)
+		if len(events) != 4 {
+			t.Fatalf("got %d events want 4", len(events))
+		}
+
+		expected := []struct {
+			eventType logger.EventType
+			method    string
+		}{
+			{logger.AgentRPCIssued, "/idl.Agent/CheckDiskSpace"},
+			{logger.AgentRPCReturned, "/idl.Agent/CheckDiskSpace"},
+			{logger.AgentRPCIssued, "/idl.Agent/UpgradePrimaries"},
+			{logger.AgentRPCReturned, "/idl.Agent/UpgradePrimaries"},
+		}
+		for i, event := range events {
+			if event.Type != expected[i].eventType || event.Host != conn.Hostname || event.Method != expected[i].method || event.Error != "" {
+				t.Errorf("got %+v want %s event for host %q and method %s", event, expected[i].eventType, conn.Hostname, expected[i].method)
+			}
+		}
+	})
+
+	t.Run("records agent RPC latency and errors per host and method", func(t *testing.T) {
+		hubServer := hub.New(conf)
+		defer hubServer.Stop(true)
+
+		agentConns, err := hubServer.AgentConns()
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		conn := agentConns[0]
+		_, err = conn.AgentClient.CheckDiskSpace(context.Background(), &idl.CheckSegmentDiskSpaceRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		agentServer.Err <- errors.New("permission denied")
+		stream, err := conn.AgentClient.UpgradePrimaries(context.Background(), &idl.UpgradePrimariesRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		_, err = stream.Recv()
+		if err == nil || err == io.EOF {
+			t.Fatalf("got error %#v want the agent error", err)
+		}
+
+		output := mustWriteMetrics(t)
+
+		for _, method := range []string{"CheckDiskSpace", "UpgradePrimaries"} {
+			expected := fmt.Sprintf(`gpupgrade_agent_rpc_duration_seconds_count{host=%q,method=%q}`, conn.Hostname, method)
+			if !strings.Contains(output, expected) {
+				t.Errorf("expected metrics to contain %q got %q", expected, output)
+			}
+		}
+
+		expected := fmt.Sprintf(`gpupgrade_agent_rpc_errors_total{host=%q,method="UpgradePrimaries"}`, conn.Hostname)
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected metrics to contain %q got %q", expected, output)
+		}
+	})
+
+	t.Run("dials agents bound to a listen address on that address", func(t *testing.T) {
+		var targets []string
+		hub.SetgRPCDialer(func(ctx context.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+			targets = append(targets, target)
+			return dialer(ctx, target, opts...)
+		})
+		defer hub.SetgRPCDialer(dialer)
+
+		conf := *conf
+		conf.AgentListenAddresses = listenaddress.AgentListenAddresses{"sdw1": "10.0.0.1", "sdw2": "10.0.0.2"}
+
+		hubServer := hub.New(&conf)
+		defer hubServer.Stop(true)
+
+		_, err := hubServer.AgentConns()
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		sort.Strings(targets)
+		port := strconv.Itoa(agentPort)
+		expected := []string{"10.0.0.1:" + port, "10.0.0.2:" + port, "sdw1-mirror:" + port, "sdw2-mirror:" + port, "standby:" + port}
+		if !reflect.DeepEqual(targets, expected) {
+			t.Errorf("dialed %q want %q", targets, expected)
+		}
+	})
+
 	t.Run("returns an error if any connections have non-ready states when first dialing", func(t *testing.T) {
 		expected := errors.New("ahh!")
 		hub.SetgRPCDialer(func(ctx context.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
@@ -0,0 +1,23 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+func (s *Server) GetStatus(ctx context.Context, req *idl.GetStatusRequest) (*idl.GetStatusReply, error) {
+	steps, err := step.LoadStatus(utils.GetStateDir())
+	if err != nil {
+		return &idl.GetStatusReply{}, xerrors.Errorf("get status: %w", err)
+	}
+
+	return &idl.GetStatusReply{Steps: steps}, nil
+}
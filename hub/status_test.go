@@ -0,0 +1,75 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestGetStatus(t *testing.T) {
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+	defer resetEnv()
+
+	server := hub.New(&config.Config{})
+
+	t.Run("returns no steps when nothing has been run", func(t *testing.T) {
+		reply, err := server.GetStatus(context.Background(), &idl.GetStatusRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if len(reply.GetSteps()) != 0 {
+			t.Errorf("got %v want no steps", reply.GetSteps())
+		}
+	})
+
+	t.Run("returns the status of each substep", func(t *testing.T) {
+		path := filepath.Join(stateDir, step.SubstepsFileName)
+		testutils.MustWriteToFile(t, path, "{}")
+
+		err := step.NewSubstepStoreUsingFile(path).Write(idl.Step_initialize, idl.Substep_check_environment, idl.Status_running)
+		if err != nil {
+			t.Fatalf("Write() returned error %#v", err)
+		}
+
+		reply, err := server.GetStatus(context.Background(), &idl.GetStatusRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		steps := reply.GetSteps()
+		if len(steps) != 1 || steps[0].GetStep() != idl.Step_initialize || len(steps[0].GetSubsteps()) != 1 {
+			t.Fatalf("got %v want a single initialize substep", steps)
+		}
+
+		substep := steps[0].GetSubsteps()[0]
+		if substep.GetSubstep() != idl.Substep_check_environment || substep.GetStatus() != idl.Status_running {
+			t.Errorf("got %v want %v %v", substep, idl.Substep_check_environment, idl.Status_running)
+		}
+
+		if substep.GetStartTime() == nil {
+			t.Errorf("expected start time to be set")
+		}
+	})
+
+	t.Run("returns an error when the status cannot be read", func(t *testing.T) {
+		testutils.MustWriteToFile(t, filepath.Join(stateDir, step.StepsFileName), "not json")
+
+		_, err := server.GetStatus(context.Background(), &idl.GetStatusRequest{})
+		if err == nil {
+			t.Errorf("expected an error")
+		}
+	})
+}
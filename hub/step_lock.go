@@ -0,0 +1,303 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	grpcStatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+var ErrHubStopping = grpcStatus.Error(codes.Unavailable, "hub is stopping")
+
+var ErrNoStepLock = grpcStatus.Error(codes.NotFound, "no step is running")
+
+// Metadata keys identifying the client that runs a step.
+const (
+	StepOwnerUserKey = "gpupgrade-user"
+	StepOwnerPIDKey  = "gpupgrade-pid"
+	StepOwnerHostKey = "gpupgrade-host"
+)
+
+// StepOwnerInterceptor identifies the current process as the owner of the
+// steps it runs on the hub.
+func StepOwnerInterceptor() (grpc.StreamClientInterceptor, error) {
+	currentUser, err := utils.System.Current()
+	if err != nil {
+		return nil, xerrors.Errorf("current user: %w", err)
+	}
+
+	host, err := utils.System.Hostname()
+	if err != nil {
+		return nil, xerrors.Errorf("hostname: %w", err)
+	}
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx = metadata.AppendToOutgoingContext(ctx,
+			StepOwnerUserKey, currentUser.Username,
+			StepOwnerPIDKey, strconv.Itoa(os.Getpid()),
+			StepOwnerHostKey, host)
+
+		return streamer(ctx, desc, cc, method, opts...)
+	}, nil
+}
+
+// stepLock ensures the hub runs a single step at a time, and that the hub is
+// not stopped out from under the running step.
+type stepLock struct {
+	mutex    sync.Mutex
+	owner    *idl.StepLock      // nil when unlocked
+	cancel   context.CancelFunc // cancels the running step when broken
+	done     chan struct{}      // closed when the running step returns
+	released chan struct{}      // closed when unlocked
+	stopping bool               // set once the hub is stopping to refuse new steps
+}
+
+func newStepLock() *stepLock {
+	released := make(chan struct{})
+	close(released)
+
+	return &stepLock{released: released}
+}
+
+// acquire locks the step for the owner until the returned function is
+// called. The returned context is canceled when the lock is broken. It errors
+// when another step is running or the hub is stopping.
+func (l *stepLock) acquire(ctx context.Context, owner *idl.StepLock) (context.Context, func(), error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.stopping {
+		return nil, nil, ErrHubStopping
+	}
+
+	if l.owner != nil {
+		return nil, nil, lockedErr(l.owner)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	l.owner = owner
+	l.cancel = cancel
+	l.done = done
+	l.released = make(chan struct{})
+
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			cancel()
+			close(done)
+			l.release(owner)
+		})
+	}, nil
+}
+
+// release unlocks the step if it is still held by the owner rather than
+// having been broken.
+func (l *stepLock) release(owner *idl.StepLock) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.owner != owner {
+		return
+	}
+
+	l.owner = nil
+	l.cancel = nil
+	l.done = nil
+	close(l.released)
+}
+
+// running returns the owner of the running step, or nil when no step is
+// running.
+func (l *stepLock) running() *idl.StepLock {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.owner
+}
+
+// breakLock cancels the running step and unlocks it such that another step
+// can run. This is for when the owner of the lock is stuck or gone. To avoid
+// running two steps at once it waits up to the timeout for the canceled step
+// to return before handing over the lock.
+func (l *stepLock) breakLock(timeout time.Duration) (*idl.StepLock, error) {
+	l.mutex.Lock()
+	owner := l.owner
+	if owner == nil {
+		l.mutex.Unlock()
+		return nil, ErrNoStepLock
+	}
+
+	log.Printf("breaking the lock on step %s held by %s", owner.GetStep(), FormatStepOwner(owner))
+
+	l.cancel()
+	done := l.done
+	l.mutex.Unlock()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		log.Printf("step %s is still running %s after canceling it, unlocking it anyway", owner.GetStep(), timeout)
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	// The step unlocked itself when it returned.
+	if l.owner != owner {
+		return owner, nil
+	}
+
+	l.owner = nil
+	l.cancel = nil
+	l.done = nil
+	close(l.released)
+
+	return owner, nil
+}
+
+// drain refuses new steps and waits up to the timeout for the running step to
+// finish. Unless forced, it errors and accepts new steps again when the step
+// is still running after the timeout.
+func (l *stepLock) drain(timeout time.Duration, force bool) error {
+	l.mutex.Lock()
+	l.stopping = true
+	released := l.released
+	l.mutex.Unlock()
+
+	select {
+	case <-released:
+		return nil
+	case <-time.After(timeout):
+	}
+
+	owner := l.running()
+	if owner == nil {
+		return nil
+	}
+
+	if force {
+		log.Printf("stopping the hub while running %s", owner.GetStep())
+		return nil
+	}
+
+	l.mutex.Lock()
+	l.stopping = false
+	l.mutex.Unlock()
+
+	return grpcStatus.Errorf(codes.FailedPrecondition,
+		"cannot stop the hub while running %s. Wait for it to finish, use --wait to wait for it, or use --force to interrupt it.",
+		owner.GetStep())
+}
+
+// failInterrupted marks any substeps still running in the running step as
+// failed since stopping the hub interrupted them. To avoid racing the writes
+// of the step to the substep store it first waits up to the timeout for the
+// step to return, and leaves the substeps as is when it has not.
+func (l *stepLock) failInterrupted(timeout time.Duration) error {
+	l.mutex.Lock()
+	owner := l.owner
+	done := l.done
+	l.mutex.Unlock()
+
+	if owner == nil {
+		return nil
+	}
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		log.Printf("not marking the substeps of %s failed since it is still running after %s", owner.GetStep(), timeout)
+		return nil
+	}
+
+	store, err := step.NewSubstepFileStore()
+	if err != nil {
+		return err
+	}
+
+	failed, err := store.FailRunning(owner.GetStep(), xerrors.New("interrupted by stopping the hub"))
+	if err != nil {
+		return xerrors.Errorf("fail interrupted substeps of %s: %w", owner.GetStep(), err)
+	}
+
+	for _, substep := range failed {
+		log.Printf("marked substep %s of %s failed since stopping the hub interrupted it", substep, owner.GetStep())
+	}
+
+	return nil
+}
+
+// stepOwner returns the owner of the step from the metadata sent by the
+// client. The host defaults to the address of the client.
+func stepOwner(ctx context.Context, name idl.Step) *idl.StepLock {
+	owner := &idl.StepLock{
+		Step:      name,
+		StartTime: timestamppb.Now(),
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	value := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	owner.User = value(StepOwnerUserKey)
+	owner.Host = value(StepOwnerHostKey)
+
+	pid, err := strconv.Atoi(value(StepOwnerPIDKey))
+	if err == nil {
+		owner.Pid = int32(pid)
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && owner.GetHost() == "" {
+		owner.Host = p.Addr.String()
+	}
+
+	return owner
+}
+
+func lockedErr(owner *idl.StepLock) error {
+	statusErr := grpcStatus.New(codes.FailedPrecondition, fmt.Sprintf("step %s is already running since %s by %s",
+		owner.GetStep(), owner.GetStartTime().AsTime().Local().Format(time.RFC1123), FormatStepOwner(owner)))
+
+	statusErr, err := statusErr.WithDetails(&idl.NextActions{
+		NextActions: `Wait for the step to finish. If it is no longer running break the lock with "gpupgrade break-step-lock".`,
+	})
+	if err != nil {
+		return grpcStatus.Error(codes.FailedPrecondition, statusErr.Message())
+	}
+
+	return statusErr.Err()
+}
+
+// FormatStepOwner describes who holds the step lock such as "gpadmin (pid
+// 1234 on host mdw)".
+func FormatStepOwner(owner *idl.StepLock) string {
+	user := owner.GetUser()
+	if user == "" {
+		user = "unknown user"
+	}
+
+	return fmt.Sprintf("%s (pid %d on host %s)", user, owner.GetPid(), owner.GetHost())
+}
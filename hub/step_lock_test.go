@@ -0,0 +1,476 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	grpcStatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/greenplum-db/gpupgrade/config"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+func TestStepLock(t *testing.T) {
+	testlog.SetupTestLogger()
+
+	owner := func(name idl.Step) *idl.StepLock {
+		return &idl.StepLock{
+			Step:      name,
+			User:      "gpadmin",
+			Pid:       1234,
+			Host:      "mdw",
+			StartTime: timestamppb.New(time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)),
+		}
+	}
+
+	t.Run("refuses to run a second step while a step is running", func(t *testing.T) {
+		lock := newStepLock()
+
+		_, unlock, err := lock.acquire(context.Background(), owner(idl.Step_execute))
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+		defer unlock()
+
+		_, _, err = lock.acquire(context.Background(), owner(idl.Step_revert))
+		if grpcStatus.Code(err) != codes.FailedPrecondition {
+			t.Errorf("got error code %v want %v", grpcStatus.Code(err), codes.FailedPrecondition)
+		}
+
+		startTime := owner(idl.Step_execute).GetStartTime().AsTime().Local().Format(time.RFC1123)
+		expected := "step execute is already running since " + startTime + " by gpadmin (pid 1234 on host mdw)"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("got error %v want it to contain %q", err, expected)
+		}
+
+		details := grpcStatus.Convert(err).Details()
+		if len(details) != 1 {
+			t.Fatalf("got details %v want next actions", details)
+		}
+
+		nextActions, ok := details[0].(*idl.NextActions)
+		if !ok || !strings.Contains(nextActions.GetNextActions(), "gpupgrade break-step-lock") {
+			t.Errorf("got details %v want next actions to break the lock", details)
+		}
+	})
+
+	t.Run("allows another step once the running step is unlocked", func(t *testing.T) {
+		lock := newStepLock()
+
+		ctx, unlock, err := lock.acquire(context.Background(), owner(idl.Step_execute))
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		unlock()
+		unlock()
+
+		if ctx.Err() == nil {
+			t.Error("expected the step context to be canceled once unlocked")
+		}
+
+		if lock.running() != nil {
+			t.Errorf("got running step %v want none", lock.running())
+		}
+
+		_, unlock, err = lock.acquire(context.Background(), owner(idl.Step_revert))
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+		unlock()
+	})
+
+	t.Run("breaking the lock cancels the running step and allows another step", func(t *testing.T) {
+		lock := newStepLock()
+
+		ctx, unlock, err := lock.acquire(context.Background(), owner(idl.Step_execute))
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		// The step does not return so the lock is handed over after the
+		// timeout.
+		broken, err := lock.breakLock(0)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if broken.GetStep() != idl.Step_execute {
+			t.Errorf("got broken lock %v want the execute step", broken)
+		}
+
+		if ctx.Err() == nil {
+			t.Error("expected the step context to be canceled")
+		}
+
+		_, unlockRevert, err := lock.acquire(context.Background(), owner(idl.Step_revert))
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		// Unlocking the broken step does not unlock the new step.
+		unlock()
+		if lock.running().GetStep() != idl.Step_revert {
+			t.Errorf("got running step %v want the revert step", lock.running())
+		}
+
+		unlockRevert()
+	})
+
+	t.Run("breaking the lock waits for the canceled step to return", func(t *testing.T) {
+		lock := newStepLock()
+
+		ctx, unlock, err := lock.acquire(context.Background(), owner(idl.Step_execute))
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		var returned int32
+		go func() {
+			<-ctx.Done()
+			time.Sleep(10 * time.Millisecond)
+			atomic.StoreInt32(&returned, 1)
+			unlock()
+		}()
+
+		_, err = lock.breakLock(time.Minute)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if atomic.LoadInt32(&returned) != 1 {
+			t.Error("expected the step to have returned before the lock was broken")
+		}
+
+		if lock.running() != nil {
+			t.Errorf("got running step %v want none", lock.running())
+		}
+
+		_, unlock, err = lock.acquire(context.Background(), owner(idl.Step_revert))
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+		unlock()
+	})
+
+	t.Run("breaking the lock errors when no step is running", func(t *testing.T) {
+		_, err := newStepLock().breakLock(time.Minute)
+		if !errors.Is(err, ErrNoStepLock) {
+			t.Errorf("got error %#v want %#v", err, ErrNoStepLock)
+		}
+	})
+
+	t.Run("drain returns immediately when no step is running and refuses new steps", func(t *testing.T) {
+		lock := newStepLock()
+
+		err := lock.drain(time.Hour, false)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		_, _, err = lock.acquire(context.Background(), owner(idl.Step_finalize))
+		if !errors.Is(err, ErrHubStopping) {
+			t.Errorf("got error %#v want %#v", err, ErrHubStopping)
+		}
+	})
+
+	t.Run("drain waits for the running step to finish", func(t *testing.T) {
+		lock := newStepLock()
+
+		_, unlock, err := lock.acquire(context.Background(), owner(idl.Step_execute))
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			unlock()
+		}()
+
+		err = lock.drain(time.Minute, false)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		if lock.running() != nil {
+			t.Errorf("got running step %v want none", lock.running())
+		}
+	})
+
+	t.Run("drain refuses to stop when a step is still running and accepts new steps again", func(t *testing.T) {
+		lock := newStepLock()
+
+		_, unlock, err := lock.acquire(context.Background(), owner(idl.Step_execute))
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		err = lock.drain(10*time.Millisecond, false)
+		if grpcStatus.Code(err) != codes.FailedPrecondition {
+			t.Errorf("got error code %v want %v", grpcStatus.Code(err), codes.FailedPrecondition)
+		}
+
+		expected := "cannot stop the hub while running execute."
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("got error %v want it to contain %q", err, expected)
+		}
+
+		unlock()
+
+		_, unlock, err = lock.acquire(context.Background(), owner(idl.Step_finalize))
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+		unlock()
+	})
+
+	t.Run("drain stops when forced even though a step is still running", func(t *testing.T) {
+		lock := newStepLock()
+
+		_, unlock, err := lock.acquire(context.Background(), owner(idl.Step_execute))
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+		defer unlock()
+
+		err = lock.drain(0, true)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		_, _, err = lock.acquire(context.Background(), owner(idl.Step_finalize))
+		if !errors.Is(err, ErrHubStopping) {
+			t.Errorf("got error %#v want %#v", err, ErrHubStopping)
+		}
+	})
+
+	t.Run("failInterrupted marks the running substeps of the running step failed", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer os.RemoveAll(stateDir)
+
+		resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+		defer resetEnv()
+
+		store, err := step.NewSubstepFileStore()
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		err = store.Write(idl.Step_execute, idl.Substep_upgrade_primaries, idl.Status_running)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		err = store.Write(idl.Step_initialize, idl.Substep_init_target_cluster, idl.Status_running)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		lock := newStepLock()
+		_, unlock, err := lock.acquire(context.Background(), owner(idl.Step_execute))
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			unlock()
+		}()
+
+		err = lock.failInterrupted(time.Minute)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		status, err := store.Read(idl.Step_execute, idl.Substep_upgrade_primaries)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if status != idl.Status_failed {
+			t.Errorf("got status %v want %v", status, idl.Status_failed)
+		}
+
+		// Only the substeps of the running step were interrupted.
+		status, err = store.Read(idl.Step_initialize, idl.Substep_init_target_cluster)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if status != idl.Status_running {
+			t.Errorf("got status %v want %v", status, idl.Status_running)
+		}
+	})
+
+	t.Run("failInterrupted leaves the substeps when the step is still running after the timeout", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer os.RemoveAll(stateDir)
+
+		resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+		defer resetEnv()
+
+		store, err := step.NewSubstepFileStore()
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		err = store.Write(idl.Step_execute, idl.Substep_upgrade_primaries, idl.Status_running)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		lock := newStepLock()
+		_, unlock, err := lock.acquire(context.Background(), owner(idl.Step_execute))
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+		defer unlock()
+
+		err = lock.failInterrupted(0)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		status, err := store.Read(idl.Step_execute, idl.Substep_upgrade_primaries)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if status != idl.Status_running {
+			t.Errorf("got status %v want %v", status, idl.Status_running)
+		}
+	})
+
+	t.Run("recover refuses while a step is running", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer os.RemoveAll(stateDir)
+
+		resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", stateDir)
+		defer resetEnv()
+
+		store, err := step.NewSubstepFileStore()
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		err = store.Start(idl.Step_execute, idl.Substep_shutdown_source_cluster, true)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		server := New(&config.Config{})
+		_, unlock, err := server.stepLock.acquire(context.Background(), owner(idl.Step_execute))
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+		defer unlock()
+
+		_, err = server.Recover(context.Background(), &idl.RecoverRequest{Substep: idl.Substep_shutdown_source_cluster})
+		if grpcStatus.Code(err) != codes.FailedPrecondition {
+			t.Errorf("got error %#v want code %v", err, codes.FailedPrecondition)
+		}
+
+		status, err := store.Read(idl.Step_execute, idl.Substep_shutdown_source_cluster)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if status != idl.Status_running {
+			t.Errorf("got status %v want %v", status, idl.Status_running)
+		}
+	})
+
+	t.Run("failInterrupted does nothing when no step is running", func(t *testing.T) {
+		resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", "/does/not/exist")
+		defer resetEnv()
+
+		err := newStepLock().failInterrupted(time.Minute)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+}
+
+func TestStepOwner(t *testing.T) {
+	t.Run("identifies the owner from the client metadata", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+			StepOwnerUserKey, "gpadmin",
+			StepOwnerPIDKey, "1234",
+			StepOwnerHostKey, "mdw",
+		))
+
+		owner := stepOwner(ctx, idl.Step_execute)
+		if owner.GetStep() != idl.Step_execute || owner.GetUser() != "gpadmin" || owner.GetPid() != 1234 || owner.GetHost() != "mdw" {
+			t.Errorf("got owner %v", owner)
+		}
+
+		if owner.GetStartTime() == nil {
+			t.Error("expected a start time")
+		}
+	})
+
+	t.Run("defaults the host to the client address", func(t *testing.T) {
+		addr := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5432}
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+
+		owner := stepOwner(ctx, idl.Step_execute)
+		if owner.GetHost() != addr.String() {
+			t.Errorf("got host %q want %q", owner.GetHost(), addr.String())
+		}
+
+		if FormatStepOwner(owner) != "unknown user (pid 0 on host 10.0.0.1:5432)" {
+			t.Errorf("got owner %q", FormatStepOwner(owner))
+		}
+	})
+}
+
+func TestStepOwnerInterceptor(t *testing.T) {
+	utils.System.Hostname = func() (string, error) {
+		return "mdw", nil
+	}
+	utils.System.Current = func() (*user.User, error) {
+		return &user.User{Username: "gpadmin"}, nil
+	}
+	defer utils.ResetSystemFunctions()
+
+	interceptor, err := StepOwnerInterceptor()
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	var md metadata.MD
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		md, _ = metadata.FromOutgoingContext(ctx)
+		return nil, nil
+	}
+
+	_, err = interceptor(context.Background(), &grpc.StreamDesc{}, nil, "/idl.CliToHub/Execute", streamer)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	owner := stepOwner(metadata.NewIncomingContext(context.Background(), md), idl.Step_execute)
+	if owner.GetUser() != "gpadmin" || owner.GetHost() != "mdw" || owner.GetPid() != int32(os.Getpid()) {
+		t.Errorf("got owner %v want gpadmin with pid %s on mdw", owner, strconv.Itoa(os.Getpid()))
+	}
+}
@@ -4,6 +4,7 @@
 package hub_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os/exec"
@@ -103,7 +104,7 @@ func TestUpdatePostgresqlConfOnSegments(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.UpdatePostgresqlConfOnSegments(agentConns, intermediate, target)
+		err := hub.UpdatePostgresqlConfOnSegments(context.Background(), agentConns, intermediate, target)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
@@ -144,7 +145,7 @@ func TestUpdatePostgresqlConfOnSegments(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.UpdatePostgresqlConfOnSegments(agentConns, intermediate, target)
+		err := hub.UpdatePostgresqlConfOnSegments(context.Background(), agentConns, intermediate, target)
 		var errs errorlist.Errors
 		if !xerrors.As(err, &errs) {
 			t.Fatalf("error %#v does not contain type %T", err, errs)
@@ -250,7 +251,7 @@ func TestUpdateRecoveryConfOnSegments(t *testing.T) {
 				{AgentClient: sdw2, Hostname: "sdw2"},
 			}
 
-			err := hub.UpdateRecoveryConfOnSegments(agentConns, c.version, intermediate, target)
+			err := hub.UpdateRecoveryConfOnSegments(context.Background(), agentConns, c.version, intermediate, target)
 			if err != nil {
 				t.Errorf("unexpected err %#v", err)
 			}
@@ -292,7 +293,7 @@ func TestUpdateRecoveryConfOnSegments(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.UpdateRecoveryConfOnSegments(agentConns, semver.MustParse("6.0.0"), intermediate, target)
+		err := hub.UpdateRecoveryConfOnSegments(context.Background(), agentConns, semver.MustParse("6.0.0"), intermediate, target)
 		var errs errorlist.Errors
 		if !xerrors.As(err, &errs) {
 			t.Fatalf("error %#v does not contain type %T", err, errs)
@@ -358,7 +359,7 @@ func TestUpdateInternalAutoConfOnMirrors(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.UpdateInternalAutoConfOnMirrors(agentConns, intermediate)
+		err := hub.UpdateInternalAutoConfOnMirrors(context.Background(), agentConns, intermediate)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
@@ -393,7 +394,7 @@ func TestUpdateInternalAutoConfOnMirrors(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.UpdateInternalAutoConfOnMirrors(agentConns, intermediate)
+		err := hub.UpdateInternalAutoConfOnMirrors(context.Background(), agentConns, intermediate)
 		if !errors.Is(err, expected) {
 			t.Errorf("got error %#v, want %#v", err, expected)
 		}
@@ -4,10 +4,12 @@
 package hub
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
+	"time"
 
 	"golang.org/x/xerrors"
 
@@ -20,7 +22,7 @@ import (
 	"github.com/greenplum-db/gpupgrade/utils/rsync"
 )
 
-func UpgradeCoordinator(streams step.OutStreams, backupDir string, pgUpgradeVerbose bool, skipPgUpgradeChecks bool, pgUpgradeJobs uint, source *greenplum.Cluster, intermediate *greenplum.Cluster, action idl.PgOptions_Action, mode idl.Mode) error {
+func UpgradeCoordinator(ctx context.Context, streams step.OutStreams, backupDir string, pgUpgradeVerbose bool, skipPgUpgradeChecks bool, pgUpgradeJobs uint, source *greenplum.Cluster, intermediate *greenplum.Cluster, action idl.PgOptions_Action, mode idl.Mode) error {
 	oldOptions := ""
 	// When upgrading from 5 the coordinator must be provided with its standby's dbid to allow WAL to sync.
 	if source.Version.Major == 5 && source.HasStandby() {
@@ -49,12 +51,14 @@ func UpgradeCoordinator(streams step.OutStreams, backupDir string, pgUpgradeVerb
 		NewDBID:             strconv.Itoa(intermediate.Coordinator().DbID),
 	}
 
-	err := RsyncCoordinatorDataDir(streams, utils.GetCoordinatorPreUpgradeBackupDir(backupDir), intermediate.CoordinatorDataDir())
+	err := RsyncCoordinatorDataDir(ctx, streams, utils.GetCoordinatorPreUpgradeBackupDir(backupDir), intermediate.CoordinatorDataDir())
 	if err != nil {
 		return err
 	}
 
-	err = upgrade.Run(streams.Stdout(), streams.Stderr(), opts)
+	start := time.Now()
+	err = upgrade.Run(ctx, streams.Stdout(), streams.Stderr(), opts)
+	recordPgUpgrade(intermediate.CoordinatorHostname(), opts.GetContentID(), action, time.Since(start))
 	if err != nil {
 		if opts.Action != idl.PgOptions_check {
 			return xerrors.Errorf("%s master: %v", action, err)
@@ -89,7 +93,7 @@ PGPORT=%d`, dir,
 	return nil
 }
 
-func RsyncCoordinatorDataDir(stream step.OutStreams, sourceDir, targetDir string) error {
+func RsyncCoordinatorDataDir(ctx context.Context, stream step.OutStreams, sourceDir, targetDir string) error {
 	sourceDirRsync := filepath.Clean(sourceDir) + string(os.PathSeparator)
 
 	options := []rsync.Option{
@@ -98,9 +102,10 @@ func RsyncCoordinatorDataDir(stream step.OutStreams, sourceDir, targetDir string
 		rsync.WithOptions("--archive", "--delete"),
 		rsync.WithExcludedFiles("pg_log/*"),
 		rsync.WithStream(stream),
+		rsync.WithContext(ctx),
 	}
 
-	err := rsync.Rsync(options...)
+	err := runRsync(options...)
 	if err != nil {
 		return xerrors.Errorf("rsync %q to %q: %w", sourceDirRsync, targetDir, err)
 	}
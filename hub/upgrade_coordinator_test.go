@@ -4,6 +4,7 @@
 package hub_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -120,7 +121,7 @@ func TestUpgradeCoordinator(t *testing.T) {
 		defer rsync.ResetRsyncCommand()
 
 		streams := new(step.BufferedStreams)
-		err := hub.UpgradeCoordinator(streams, backupDirs.CoordinatorBackupDir, false, false, 1, source, intermediate, idl.PgOptions_check, idl.Mode_copy)
+		err := hub.UpgradeCoordinator(context.Background(), streams, backupDirs.CoordinatorBackupDir, false, false, 1, source, intermediate, idl.PgOptions_check, idl.Mode_copy)
 		if err != nil {
 			t.Fatalf("unexpected error %+v", err)
 		}
@@ -150,7 +151,7 @@ func TestUpgradeCoordinator(t *testing.T) {
 
 		source.Version = semver.MustParse("5.28.0")
 
-		err := hub.UpgradeCoordinator(step.DevNullStream, backupDirs.CoordinatorBackupDir, false, false, 1, source, intermediate, idl.PgOptions_check, idl.Mode_copy)
+		err := hub.UpgradeCoordinator(context.Background(), step.DevNullStream, backupDirs.CoordinatorBackupDir, false, false, 1, source, intermediate, idl.PgOptions_check, idl.Mode_copy)
 		if err != nil {
 			t.Fatalf("unexpected error %+v", err)
 		}
@@ -171,7 +172,7 @@ func TestUpgradeCoordinator(t *testing.T) {
 
 		source.Version = semver.MustParse("6.10.0")
 
-		err := hub.UpgradeCoordinator(step.DevNullStream, backupDirs.CoordinatorBackupDir, false, false, 1, source, intermediate, idl.PgOptions_check, idl.Mode_copy)
+		err := hub.UpgradeCoordinator(context.Background(), step.DevNullStream, backupDirs.CoordinatorBackupDir, false, false, 1, source, intermediate, idl.PgOptions_check, idl.Mode_copy)
 		if err != nil {
 			t.Fatalf("unexpected error %+v", err)
 		}
@@ -187,7 +188,7 @@ func TestUpgradeCoordinator(t *testing.T) {
 		}))
 		defer rsync.ResetRsyncCommand()
 
-		err := hub.UpgradeCoordinator(step.DevNullStream, backupDirs.CoordinatorBackupDir, false, false, 1, source, intermediate, idl.PgOptions_check, idl.Mode_copy)
+		err := hub.UpgradeCoordinator(context.Background(), step.DevNullStream, backupDirs.CoordinatorBackupDir, false, false, 1, source, intermediate, idl.PgOptions_check, idl.Mode_copy)
 		if err != nil {
 			t.Fatalf("unexpected error %+v", err)
 		}
@@ -204,7 +205,7 @@ func TestUpgradeCoordinator(t *testing.T) {
 		rsync.SetRsyncCommand(exectest.NewCommand(hub.Failure))
 		defer rsync.ResetRsyncCommand()
 
-		err := hub.UpgradeCoordinator(step.DevNullStream, backupDirs.CoordinatorBackupDir, false, false, 1, source, intermediate, idl.PgOptions_upgrade, idl.Mode_copy)
+		err := hub.UpgradeCoordinator(context.Background(), step.DevNullStream, backupDirs.CoordinatorBackupDir, false, false, 1, source, intermediate, idl.PgOptions_upgrade, idl.Mode_copy)
 		var actual *exec.ExitError
 		if !errors.As(err, &actual) {
 			t.Fatalf("got %#v want ExitError", err)
@@ -255,7 +256,7 @@ func TestUpgradeCoordinator(t *testing.T) {
 		}))
 		defer rsync.ResetRsyncCommand()
 
-		err := hub.UpgradeCoordinator(step.DevNullStream, backupDirs.CoordinatorBackupDir, false, false, 1, source, intermediate, idl.PgOptions_upgrade, idl.Mode_copy)
+		err := hub.UpgradeCoordinator(context.Background(), step.DevNullStream, backupDirs.CoordinatorBackupDir, false, false, 1, source, intermediate, idl.PgOptions_upgrade, idl.Mode_copy)
 		if err != nil {
 			t.Fatalf("unexpected error %+v", err)
 		}
@@ -268,7 +269,7 @@ func TestUpgradeCoordinator(t *testing.T) {
 		upgrade.SetPgUpgradeCommand(exectest.NewCommand(hub.Failure))
 		defer upgrade.ResetPgUpgradeCommand()
 
-		err := hub.UpgradeCoordinator(new(step.BufferedStreams), backupDirs.CoordinatorBackupDir, false, false, 1, source, intermediate, idl.PgOptions_upgrade, idl.Mode_copy)
+		err := hub.UpgradeCoordinator(context.Background(), new(step.BufferedStreams), backupDirs.CoordinatorBackupDir, false, false, 1, source, intermediate, idl.PgOptions_upgrade, idl.Mode_copy)
 		expected := "upgrade master: exit status 1"
 		if err.Error() != expected {
 			t.Errorf("got %q want %q", err.Error(), expected)
@@ -298,7 +299,7 @@ func TestUpgradeCoordinator(t *testing.T) {
 		upgrade.SetPgUpgradeCommand(exectest.NewCommand(PgCheckFailure))
 		defer upgrade.ResetPgUpgradeCommand()
 
-		err := hub.UpgradeCoordinator(new(step.BufferedStreams), backupDirs.CoordinatorBackupDir, false, false, 1, source, intermediate, idl.PgOptions_check, idl.Mode_copy)
+		err := hub.UpgradeCoordinator(context.Background(), new(step.BufferedStreams), backupDirs.CoordinatorBackupDir, false, false, 1, source, intermediate, idl.PgOptions_check, idl.Mode_copy)
 		var nextActionsErr utils.NextActionErr
 		if !errors.As(err, &nextActionsErr) {
 			t.Fatalf("got type %T want %T", err, nextActionsErr)
@@ -328,7 +329,7 @@ func TestUpgradeCoordinator(t *testing.T) {
 		upgrade.SetPgUpgradeCommand(exectest.NewCommand(BlindlyWritingMain))
 		defer upgrade.ResetPgUpgradeCommand()
 
-		err := hub.UpgradeCoordinator(testutils.FailingStreams{Err: errors.New("write failed")}, backupDirs.CoordinatorBackupDir, false, false, 1, source, intermediate, idl.PgOptions_upgrade, idl.Mode_copy)
+		err := hub.UpgradeCoordinator(context.Background(), testutils.FailingStreams{Err: errors.New("write failed")}, backupDirs.CoordinatorBackupDir, false, false, 1, source, intermediate, idl.PgOptions_upgrade, idl.Mode_copy)
 		expected := "upgrade master: write failed"
 		if err.Error() != expected {
 			t.Errorf("got %q want %q", err.Error(), expected)
@@ -342,7 +343,7 @@ func TestRsyncCoordinatorDir(t *testing.T) {
 		defer rsync.ResetRsyncCommand()
 
 		stream := new(step.BufferedStreams)
-		err := hub.RsyncCoordinatorDataDir(stream, "", "")
+		err := hub.RsyncCoordinatorDataDir(context.Background(), stream, "", "")
 
 		if err != nil {
 			t.Errorf("returned: %+v", err)
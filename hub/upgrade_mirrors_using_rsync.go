@@ -16,7 +16,7 @@ import (
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
 )
 
-func UpgradeMirrorsUsingRsync(agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster, useHbaHostnames bool) error {
+func UpgradeMirrorsUsingRsync(ctx context.Context, agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster, useHbaHostnames bool) error {
 	db, err := sql.Open("pgx", intermediate.Connection())
 	if err != nil {
 		return err
@@ -35,27 +35,27 @@ func UpgradeMirrorsUsingRsync(agentConns []*idl.Connection, source *greenplum.Cl
 		return err
 	}
 
-	if err := RsyncMirrorDataDirsOnSegments(agentConns, source, intermediate); err != nil {
+	if err := RsyncMirrorDataDirsOnSegments(ctx, agentConns, source, intermediate); err != nil {
 		return err
 	}
 
-	if err := RsyncMirrorTablespacesOnSegments(agentConns, source, intermediate); err != nil {
+	if err := RsyncMirrorTablespacesOnSegments(ctx, agentConns, source, intermediate); err != nil {
 		return err
 	}
 
-	if err := RenameMirrorTablespacesOnSegments(agentConns, source, intermediate); err != nil {
+	if err := RenameMirrorTablespacesOnSegments(ctx, agentConns, source, intermediate); err != nil {
 		return err
 	}
 
-	if err := CreateRecoveryConfOnSegments(agentConns, intermediate); err != nil {
+	if err := CreateRecoveryConfOnSegments(ctx, agentConns, intermediate); err != nil {
 		return err
 	}
 
-	if err := AddReplicationEntriesOnPrimaries(agentConns, intermediate, useHbaHostnames); err != nil {
+	if err := AddReplicationEntriesOnPrimaries(ctx, agentConns, intermediate, useHbaHostnames); err != nil {
 		return err
 	}
 
-	if err := UpdateInternalAutoConfOnMirrors(agentConns, intermediate); err != nil {
+	if err := UpdateInternalAutoConfOnMirrors(ctx, agentConns, intermediate); err != nil {
 		return err
 	}
 
@@ -78,7 +78,7 @@ func UpgradeMirrorsUsingRsync(agentConns []*idl.Connection, source *greenplum.Cl
 	return nil
 }
 
-func RsyncMirrorDataDirsOnSegments(agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster) error {
+func RsyncMirrorDataDirsOnSegments(ctx context.Context, agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster) error {
 	request := func(conn *idl.Connection) error {
 		sourcePrimaries := source.SelectSegments(func(seg *greenplum.SegConfig) bool {
 			return seg.IsOnHost(conn.Hostname) && !seg.IsCoordinator() && seg.IsPrimary()
@@ -95,21 +95,22 @@ func RsyncMirrorDataDirsOnSegments(agentConns []*idl.Connection, source *greenpl
 				Sources:         []string{sourcePrimary.DataDir, intermediatePrimary.DataDir},
 				Destination:     filepath.Dir(intermediateMirror.DataDir), // FIXME: Do we really want filepath.Dir here
 				DestinationHost: intermediateMirror.Hostname,
-				Options:         []string{"--archive", "--delete", "--hard-links", "--size-only", "--no-inc-recursive"},
+				Options:         []string{"--archive", "--delete", "--hard-links", "--size-only", "--no-inc-recursive", "--stats"},
 			}
 
 			opts = append(opts, opt)
 		}
 
 		req := &idl.RsyncRequest{Options: opts}
-		_, err := conn.AgentClient.RsyncDataDirectories(context.Background(), req)
+		reply, err := conn.AgentClient.RsyncDataDirectories(ctx, req)
+		recordAgentRsyncStats(conn.Hostname, reply.GetStats())
 		return err
 	}
 
-	return ExecuteRPC(agentConns, request)
+	return ExecuteRPC(ctx, agentConns, request)
 }
 
-func RsyncMirrorTablespacesOnSegments(agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster) error {
+func RsyncMirrorTablespacesOnSegments(ctx context.Context, agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster) error {
 	request := func(conn *idl.Connection) error {
 		sourcePrimaries := source.SelectSegments(func(seg *greenplum.SegConfig) bool {
 			return seg.IsOnHost(conn.Hostname) && !seg.IsCoordinator() && seg.IsPrimary()
@@ -132,21 +133,22 @@ func RsyncMirrorTablespacesOnSegments(agentConns []*idl.Connection, source *gree
 					Sources:         []string{sourcePrimaryTsLocation},
 					Destination:     sourceMirrorTsLocation,
 					DestinationHost: intermediateMirror.Hostname,
-					Options:         []string{"--archive", "--delete", "--hard-links", "--size-only", "--no-inc-recursive"},
+					Options:         []string{"--archive", "--delete", "--hard-links", "--size-only", "--no-inc-recursive", "--stats"},
 				}
 
 				opts = append(opts, opt)
 			}
 		}
 
-		_, err := conn.AgentClient.RsyncTablespaceDirectories(context.Background(), &idl.RsyncRequest{Options: opts})
+		reply, err := conn.AgentClient.RsyncTablespaceDirectories(ctx, &idl.RsyncRequest{Options: opts})
+		recordAgentRsyncStats(conn.Hostname, reply.GetStats())
 		return err
 	}
 
-	return ExecuteRPC(agentConns, request)
+	return ExecuteRPC(ctx, agentConns, request)
 }
 
-func RenameMirrorTablespacesOnSegments(agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster) error {
+func RenameMirrorTablespacesOnSegments(ctx context.Context, agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster) error {
 	request := func(conn *idl.Connection) error {
 		intermediateMirrors := intermediate.SelectSegments(func(seg *greenplum.SegConfig) bool {
 			return seg.IsOnHost(conn.Hostname) && !seg.IsStandby() && seg.IsMirror()
@@ -177,9 +179,9 @@ func RenameMirrorTablespacesOnSegments(agentConns []*idl.Connection, source *gre
 			}
 		}
 
-		_, err := conn.AgentClient.RenameTablespaces(context.Background(), &idl.RenameTablespacesRequest{RenamePairs: pairs})
+		_, err := conn.AgentClient.RenameTablespaces(ctx, &idl.RenameTablespacesRequest{RenamePairs: pairs})
 		return err
 	}
 
-	return ExecuteRPC(agentConns, request)
+	return ExecuteRPC(ctx, agentConns, request)
 }
@@ -5,24 +5,57 @@ package hub
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"time"
 
 	"golang.org/x/xerrors"
 
 	"github.com/greenplum-db/gpupgrade/config/backupdir"
 	"github.com/greenplum-db/gpupgrade/greenplum"
 	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
 )
 
-func UpgradePrimaries(agentConns []*idl.Connection, agentHostToBackupDir backupdir.AgentHostsToBackupDir, pgUpgradeVerbose bool, skipPgUpgradeChecks bool, pgUpgradeJobs uint, source *greenplum.Cluster, intermediate *greenplum.Cluster, action idl.PgOptions_Action, mode idl.Mode) error {
+// UpgradePrimaries runs pg_upgrade on all primary segments. When upgrading,
+// the status of each segment is recorded in the store such that a retry only
+// re-processes the segments that did not complete, which are reported as
+// skipped. At most maxParallelHosts hosts upgrade maxParallelSegments
+// segments each at a time, where zero is unlimited.
+func UpgradePrimaries(ctx context.Context, streams step.OutStreams, store SegmentStore, agentConns []*idl.Connection, agentHostToBackupDir backupdir.AgentHostsToBackupDir, pgUpgradeVerbose bool, skipPgUpgradeChecks bool, pgUpgradeJobs uint, maxParallelSegments uint, maxParallelHosts uint, source *greenplum.Cluster, intermediate *greenplum.Cluster, action idl.PgOptions_Action, mode idl.Mode) error {
+	// Checks are always re-run since they do not modify the segments, and
+	// since a segment that passed may no longer pass.
+	statuses := make(map[int32]idl.Status)
+	if action == idl.PgOptions_upgrade {
+		var err error
+		statuses, err = store.Read(action)
+		if err != nil {
+			return xerrors.Errorf("read segment statuses: %w", err)
+		}
+	}
+
 	request := func(conn *idl.Connection) error {
 		intermediatePrimaries := intermediate.SelectSegments(func(seg *greenplum.SegConfig) bool {
 			return seg.IsOnHost(conn.Hostname) && seg.IsPrimary() && !seg.IsCoordinator()
 		})
+		// Upgrade the segments in a stable order such that their progress is
+		// reported consistently.
+		sort.Sort(intermediatePrimaries)
 
 		var opts []*idl.PgOptions
 		for _, intermediatePrimary := range intermediatePrimaries {
+			if statuses[int32(intermediatePrimary.ContentID)] == idl.Status_complete {
+				step.SendSegmentProgress(streams, &idl.SegmentProgress{
+					Host:      conn.Hostname,
+					ContentID: int32(intermediatePrimary.ContentID),
+					Status:    idl.Status_skipped,
+				})
+				continue
+			}
+
 			sourcePrimary := source.Primaries[intermediatePrimary.ContentID]
 
 			opt := &idl.PgOptions{
@@ -50,14 +83,84 @@ func UpgradePrimaries(agentConns []*idl.Connection, agentHostToBackupDir backupd
 			opts = append(opts, opt)
 		}
 
-		req := &idl.UpgradePrimariesRequest{Action: action, Opts: opts}
-		_, err := conn.AgentClient.UpgradePrimaries(context.Background(), req)
+		if len(opts) == 0 {
+			return nil
+		}
+
+		for _, opt := range opts {
+			step.SendSegmentProgress(streams, &idl.SegmentProgress{
+				Host:      conn.Hostname,
+				ContentID: opt.GetContentID(),
+				Status:    idl.Status_running,
+			})
+		}
+
+		req := &idl.UpgradePrimariesRequest{Action: action, Opts: opts, MaxConcurrency: uint32(maxParallelSegments)}
+		stream, err := conn.AgentClient.UpgradePrimaries(ctx, req)
 		if err != nil {
 			return xerrors.Errorf("%s primary segment on host %s: %w", action, conn.Hostname, err)
 		}
 
+		for {
+			var reply *idl.UpgradePrimariesReply
+			reply, err = stream.Recv()
+			if err != nil {
+				break
+			}
+
+			if err := relayUpgradePrimariesReply(streams, store, conn.Hostname, action, reply); err != nil {
+				return err
+			}
+		}
+
+		if err != io.EOF {
+			return xerrors.Errorf("%s primary segment on host %s: %w", action, conn.Hostname, err)
+		}
+
 		return nil
 	}
 
-	return ExecuteRPC(agentConns, request)
+	return ExecuteRPCWithLimit(ctx, agentConns, maxParallelHosts, request)
+}
+
+// relayUpgradePrimariesReply writes the pg_upgrade output of a segment and
+// sends its progress to the client. When upgrading the result of the segment
+// is recorded in the store.
+func relayUpgradePrimariesReply(streams step.OutStreams, store SegmentStore, host string, action idl.PgOptions_Action, reply *idl.UpgradePrimariesReply) error {
+	switch x := reply.GetContents().(type) {
+	case *idl.UpgradePrimariesReply_Progress_:
+		_, err := fmt.Fprintf(streams.Stdout(), "%s content %d: %s\n", host, x.Progress.GetContentID(), x.Progress.GetLine())
+		if err != nil {
+			return err
+		}
+
+		step.SendSegmentProgress(streams, &idl.SegmentProgress{
+			Host:      host,
+			ContentID: x.Progress.GetContentID(),
+			Status:    idl.Status_running,
+			Progress:  x.Progress.GetLine(),
+		})
+
+	case *idl.UpgradePrimariesReply_Result_:
+		status := idl.Status_complete
+		if x.Result.GetError() != "" {
+			status = idl.Status_failed
+		} else {
+			recordPgUpgrade(host, x.Result.GetContentID(), action, time.Duration(x.Result.GetDurationSeconds()*float64(time.Second)))
+		}
+
+		if action == idl.PgOptions_upgrade {
+			if err := store.Write(action, x.Result.GetContentID(), status); err != nil {
+				return xerrors.Errorf("write status of content %d: %w", x.Result.GetContentID(), err)
+			}
+		}
+
+		step.SendSegmentProgress(streams, &idl.SegmentProgress{
+			Host:      host,
+			ContentID: x.Result.GetContentID(),
+			Status:    status,
+		})
+	}
+
+	return nil
 }
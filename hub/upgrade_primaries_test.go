@@ -4,22 +4,28 @@
 package hub_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"reflect"
 	"sort"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/blang/semver/v4"
 	"github.com/golang/mock/gomock"
 	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
 
 	"github.com/greenplum-db/gpupgrade/config/backupdir"
 	"github.com/greenplum-db/gpupgrade/greenplum"
 	"github.com/greenplum-db/gpupgrade/hub"
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/step"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
 )
 
@@ -117,7 +123,7 @@ func TestUpgradePrimaries(t *testing.T) {
 					},
 				},
 			}),
-		).Return(&idl.UpgradePrimariesReply{}, nil)
+		).Return(mockUpgradePrimariesStream(ctrl), nil)
 
 		sdw2 := mock_idl.NewMockAgentClient(ctrl)
 		sdw2.EXPECT().UpgradePrimaries(
@@ -169,14 +175,14 @@ func TestUpgradePrimaries(t *testing.T) {
 					},
 				},
 			}),
-		).Return(&idl.UpgradePrimariesReply{}, nil)
+		).Return(mockUpgradePrimariesStream(ctrl), nil)
 
 		agentConns := []*idl.Connection{
 			{AgentClient: sdw1, Hostname: "sdw1"},
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.UpgradePrimaries(agentConns, backupDirs.AgentHostsToBackupDir, true, true, 1, source, intermediate, idl.PgOptions_check, idl.Mode_copy)
+		err := hub.UpgradePrimaries(context.Background(), step.DevNullStream, newSegmentStore(), agentConns, backupDirs.AgentHostsToBackupDir, true, true, 1, 0, 0, source, intermediate, idl.PgOptions_check, idl.Mode_copy)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
@@ -222,7 +228,7 @@ func TestUpgradePrimaries(t *testing.T) {
 				{AgentClient: sdw2, Hostname: "sdw2"},
 			}
 
-			err := hub.UpgradePrimaries(agentConns, backupDirs.AgentHostsToBackupDir, false, false, 1, source, intermediate, c.Action, idl.Mode_link)
+			err := hub.UpgradePrimaries(context.Background(), step.DevNullStream, newSegmentStore(), agentConns, backupDirs.AgentHostsToBackupDir, false, false, 1, 0, 0, source, intermediate, c.Action, idl.Mode_link)
 			var errs errorlist.Errors
 			if !xerrors.As(err, &errs) {
 				t.Fatalf("error %#v does not contain type %T", err, errs)
@@ -249,6 +255,411 @@ func TestUpgradePrimaries(t *testing.T) {
 	}
 }
 
+func TestUpgradePrimariesConcurrency(t *testing.T) {
+	source := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg2", Port: 25434, Role: greenplum.PrimaryRole},
+	})
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 60432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 60434, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg.HqtFHX54y0o.2", Port: 60435, Role: greenplum.PrimaryRole},
+	})
+	intermediate.Version = semver.MustParse("6.0.0")
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var mutex sync.Mutex
+	var running, maxRunning int
+
+	agentConns := []*idl.Connection{}
+	for _, host := range []string{"sdw1", "sdw2"} {
+		client := mock_idl.NewMockAgentClient(ctrl)
+		client.EXPECT().UpgradePrimaries(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, req *idl.UpgradePrimariesRequest, opts ...grpc.CallOption) (idl.Agent_UpgradePrimariesClient, error) {
+				if req.GetMaxConcurrency() != 3 {
+					t.Errorf("got max concurrency %d want 3", req.GetMaxConcurrency())
+				}
+
+				mutex.Lock()
+				running++
+				if running > maxRunning {
+					maxRunning = running
+				}
+				mutex.Unlock()
+
+				time.Sleep(10 * time.Millisecond)
+
+				mutex.Lock()
+				running--
+				mutex.Unlock()
+
+				return mockUpgradePrimariesStream(ctrl), nil
+			})
+
+		agentConns = append(agentConns, &idl.Connection{AgentClient: client, Hostname: host})
+	}
+
+	backupDirs := map[string]string{"sdw1": "/data/.gpupgrade", "sdw2": "/data/.gpupgrade"}
+	err := hub.UpgradePrimaries(context.Background(), step.DevNullStream, newSegmentStore(), agentConns, backupDirs, false, false, 1, 3, 1, source, intermediate, idl.PgOptions_upgrade, idl.Mode_link)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if maxRunning != 1 {
+		t.Errorf("got %d hosts upgrading concurrently want 1", maxRunning)
+	}
+}
+
+func TestUpgradePrimariesProgress(t *testing.T) {
+	source := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast2/seg2", Port: 25434, Role: greenplum.PrimaryRole},
+	})
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 60432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 60434, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast2/seg.HqtFHX54y0o.2", Port: 60435, Role: greenplum.PrimaryRole},
+	})
+	intermediate.Version = semver.MustParse("6.0.0")
+
+	t.Run("relays pg_upgrade output and the progress of each segment", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mock_idl.NewMockAgentClient(ctrl)
+		client.EXPECT().UpgradePrimaries(gomock.Any(), gomock.Any()).Return(mockUpgradePrimariesStream(ctrl,
+			&idl.UpgradePrimariesReply{Contents: &idl.UpgradePrimariesReply_Progress_{
+				Progress: &idl.UpgradePrimariesReply_Progress{ContentID: 0, Line: "copying 1/2"},
+			}},
+			&idl.UpgradePrimariesReply{Contents: &idl.UpgradePrimariesReply_Result_{
+				Result: &idl.UpgradePrimariesReply_Result{ContentID: 0, DurationSeconds: 1},
+			}},
+			&idl.UpgradePrimariesReply{Contents: &idl.UpgradePrimariesReply_Result_{
+				Result: &idl.UpgradePrimariesReply_Result{ContentID: 1, DurationSeconds: 1, Error: "exit status 1"},
+			}},
+		), nil)
+
+		streams := &progressStreams{}
+		agentConns := []*idl.Connection{{AgentClient: client, Hostname: "sdw1"}}
+
+		err := hub.UpgradePrimaries(context.Background(), streams, newSegmentStore(), agentConns, map[string]string{"sdw1": "/data/.gpupgrade"}, false, false, 1, 0, 0, source, intermediate, idl.PgOptions_upgrade, idl.Mode_link)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expectedOutput := "sdw1 content 0: copying 1/2\n"
+		if streams.StdoutBuf.String() != expectedOutput {
+			t.Errorf("got stdout %q want %q", streams.StdoutBuf.String(), expectedOutput)
+		}
+
+		expected := []*idl.SegmentProgress{
+			{Host: "sdw1", ContentID: 0, Status: idl.Status_running},
+			{Host: "sdw1", ContentID: 1, Status: idl.Status_running},
+			{Host: "sdw1", ContentID: 0, Status: idl.Status_running, Progress: "copying 1/2"},
+			{Host: "sdw1", ContentID: 0, Status: idl.Status_complete},
+			{Host: "sdw1", ContentID: 1, Status: idl.Status_failed},
+		}
+		if !reflect.DeepEqual(streams.progress, expected) {
+			t.Errorf("got progress %v want %v", streams.progress, expected)
+		}
+	})
+
+	t.Run("errors when receiving from the stream fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expected := errors.New("connection lost")
+		stream := mock_idl.NewMockAgent_UpgradePrimariesClient(ctrl)
+		stream.EXPECT().Recv().Return(nil, expected)
+
+		client := mock_idl.NewMockAgentClient(ctrl)
+		client.EXPECT().UpgradePrimaries(gomock.Any(), gomock.Any()).Return(stream, nil)
+
+		agentConns := []*idl.Connection{{AgentClient: client, Hostname: "sdw1"}}
+
+		err := hub.UpgradePrimaries(context.Background(), step.DevNullStream, newSegmentStore(), agentConns, map[string]string{"sdw1": "/data/.gpupgrade"}, false, false, 1, 0, 0, source, intermediate, idl.PgOptions_upgrade, idl.Mode_link)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+
+		expectedMsg := "upgrade primary segment on host sdw1: connection lost"
+		if err == nil || err.Error() != expectedMsg {
+			t.Errorf("got error %q want %q", err, expectedMsg)
+		}
+	})
+}
+
+func TestUpgradePrimariesResume(t *testing.T) {
+	source := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast2/seg2", Port: 25434, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 2, Hostname: "sdw2", DataDir: "/data/dbfast3/seg3", Port: 25435, Role: greenplum.PrimaryRole},
+	})
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 60432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 60434, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast2/seg.HqtFHX54y0o.2", Port: 60435, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 2, Hostname: "sdw2", DataDir: "/data/dbfast3/seg.HqtFHX54y0o.3", Port: 60436, Role: greenplum.PrimaryRole},
+	})
+	intermediate.Version = semver.MustParse("6.0.0")
+
+	backupDirs := map[string]string{"sdw1": "/data/.gpupgrade", "sdw2": "/data/.gpupgrade"}
+
+	t.Run("records the status of each segment", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpgradePrimaries(gomock.Any(), gomock.Any()).Return(mockUpgradePrimariesStream(ctrl,
+			&idl.UpgradePrimariesReply{Contents: &idl.UpgradePrimariesReply_Result_{
+				Result: &idl.UpgradePrimariesReply_Result{ContentID: 0},
+			}},
+			&idl.UpgradePrimariesReply{Contents: &idl.UpgradePrimariesReply_Result_{
+				Result: &idl.UpgradePrimariesReply_Result{ContentID: 1, Error: "exit status 1"},
+			}},
+		), nil)
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().UpgradePrimaries(gomock.Any(), gomock.Any()).Return(mockUpgradePrimariesStream(ctrl,
+			&idl.UpgradePrimariesReply{Contents: &idl.UpgradePrimariesReply_Result_{
+				Result: &idl.UpgradePrimariesReply_Result{ContentID: 2},
+			}},
+		), nil)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		store := newSegmentStore()
+		err := hub.UpgradePrimaries(context.Background(), step.DevNullStream, store, agentConns, backupDirs, false, false, 1, 0, 0, source, intermediate, idl.PgOptions_upgrade, idl.Mode_link)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := map[int32]idl.Status{0: idl.Status_complete, 1: idl.Status_failed, 2: idl.Status_complete}
+		if !reflect.DeepEqual(store.statuses[idl.PgOptions_upgrade], expected) {
+			t.Errorf("got statuses %v want %v", store.statuses[idl.PgOptions_upgrade], expected)
+		}
+	})
+
+	t.Run("only upgrades segments that did not complete and reports the rest as skipped", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpgradePrimaries(gomock.Any(), upgradePrimariesContents(1)).Return(mockUpgradePrimariesStream(ctrl,
+			&idl.UpgradePrimariesReply{Contents: &idl.UpgradePrimariesReply_Result_{
+				Result: &idl.UpgradePrimariesReply_Result{ContentID: 1},
+			}},
+		), nil)
+
+		// sdw2 has no remaining segments and is not called
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		store := newSegmentStore()
+		store.statuses[idl.PgOptions_upgrade] = map[int32]idl.Status{0: idl.Status_complete, 1: idl.Status_failed, 2: idl.Status_complete}
+
+		streams := &progressStreams{}
+		err := hub.UpgradePrimaries(context.Background(), streams, store, agentConns, backupDirs, false, false, 1, 0, 0, source, intermediate, idl.PgOptions_upgrade, idl.Mode_link)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		var skipped []int32
+		for _, progress := range streams.progress {
+			if progress.GetStatus() == idl.Status_skipped {
+				skipped = append(skipped, progress.GetContentID())
+			}
+		}
+		sort.Slice(skipped, func(i, j int) bool { return skipped[i] < skipped[j] })
+
+		expectedSkipped := []int32{0, 2}
+		if !reflect.DeepEqual(skipped, expectedSkipped) {
+			t.Errorf("got skipped contents %v want %v", skipped, expectedSkipped)
+		}
+
+		expected := map[int32]idl.Status{0: idl.Status_complete, 1: idl.Status_complete, 2: idl.Status_complete}
+		if !reflect.DeepEqual(store.statuses[idl.PgOptions_upgrade], expected) {
+			t.Errorf("got statuses %v want %v", store.statuses[idl.PgOptions_upgrade], expected)
+		}
+	})
+
+	t.Run("always re-runs checks", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpgradePrimaries(gomock.Any(), upgradePrimariesContents(0, 1)).Return(mockUpgradePrimariesStream(ctrl), nil)
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().UpgradePrimaries(gomock.Any(), upgradePrimariesContents(2)).Return(mockUpgradePrimariesStream(ctrl), nil)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		store := newSegmentStore()
+		store.statuses[idl.PgOptions_check] = map[int32]idl.Status{0: idl.Status_complete, 1: idl.Status_complete, 2: idl.Status_complete}
+		store.readErr = errors.New("checks should not read the store")
+
+		err := hub.UpgradePrimaries(context.Background(), step.DevNullStream, store, agentConns, backupDirs, false, false, 1, 0, 0, source, intermediate, idl.PgOptions_check, idl.Mode_link)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+	})
+
+	t.Run("errors when reading the store fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		agentConns := []*idl.Connection{
+			{AgentClient: mock_idl.NewMockAgentClient(ctrl), Hostname: "sdw1"},
+		}
+
+		store := newSegmentStore()
+		store.readErr = os.ErrPermission
+
+		err := hub.UpgradePrimaries(context.Background(), step.DevNullStream, store, agentConns, backupDirs, false, false, 1, 0, 0, source, intermediate, idl.PgOptions_upgrade, idl.Mode_link)
+		if !errors.Is(err, os.ErrPermission) {
+			t.Errorf("got error %#v want %#v", err, os.ErrPermission)
+		}
+	})
+
+	t.Run("errors when writing the store fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stream := mock_idl.NewMockAgent_UpgradePrimariesClient(ctrl)
+		stream.EXPECT().Recv().Return(&idl.UpgradePrimariesReply{Contents: &idl.UpgradePrimariesReply_Result_{
+			Result: &idl.UpgradePrimariesReply_Result{ContentID: 0},
+		}}, nil)
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpgradePrimaries(gomock.Any(), gomock.Any()).Return(stream, nil)
+
+		agentConns := []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}
+
+		store := newSegmentStore()
+		store.writeErr = os.ErrPermission
+
+		err := hub.UpgradePrimaries(context.Background(), step.DevNullStream, store, agentConns, backupDirs, false, false, 1, 0, 0, source, intermediate, idl.PgOptions_upgrade, idl.Mode_link)
+		if !errors.Is(err, os.ErrPermission) {
+			t.Errorf("got error %#v want %#v", err, os.ErrPermission)
+		}
+	})
+}
+
+// upgradePrimariesContents matches an UpgradePrimariesRequest for exactly
+// the given content IDs.
+func upgradePrimariesContents(contentIDs ...int32) gomock.Matcher {
+	return contentsMatcher(contentIDs)
+}
+
+type contentsMatcher []int32
+
+func (m contentsMatcher) Matches(x interface{}) bool {
+	req, ok := x.(*idl.UpgradePrimariesRequest)
+	if !ok {
+		return false
+	}
+
+	var actual []int32
+	for _, opt := range req.GetOpts() {
+		actual = append(actual, opt.GetContentID())
+	}
+	sort.Slice(actual, func(i, j int) bool { return actual[i] < actual[j] })
+
+	return reflect.DeepEqual(actual, []int32(m))
+}
+
+func (m contentsMatcher) String() string {
+	return fmt.Sprintf("has contents %v", []int32(m))
+}
+
+// mockUpgradePrimariesStream returns a stream which receives the replies
+// followed by io.EOF.
+func mockUpgradePrimariesStream(ctrl *gomock.Controller, replies ...*idl.UpgradePrimariesReply) *mock_idl.MockAgent_UpgradePrimariesClient {
+	stream := mock_idl.NewMockAgent_UpgradePrimariesClient(ctrl)
+
+	var calls []*gomock.Call
+	for _, reply := range replies {
+		calls = append(calls, stream.EXPECT().Recv().Return(reply, nil))
+	}
+	calls = append(calls, stream.EXPECT().Recv().Return(nil, io.EOF))
+	gomock.InOrder(calls...)
+
+	return stream
+}
+
+// progressStreams records the segment progress sent to the client.
+type progressStreams struct {
+	step.BufferedStreams
+	mutex    sync.Mutex
+	progress []*idl.SegmentProgress
+}
+
+func (s *progressStreams) SendSegmentProgress(progress *idl.SegmentProgress) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.progress = append(s.progress, progress)
+}
+
+// segmentStore is an in-memory SegmentStore.
+type segmentStore struct {
+	mutex    sync.Mutex
+	statuses map[idl.PgOptions_Action]map[int32]idl.Status
+	readErr  error
+	writeErr error
+}
+
+func newSegmentStore() *segmentStore {
+	return &segmentStore{statuses: make(map[idl.PgOptions_Action]map[int32]idl.Status)}
+}
+
+func (s *segmentStore) Read(action idl.PgOptions_Action) (map[int32]idl.Status, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	statuses := make(map[int32]idl.Status)
+	for contentID, status := range s.statuses[action] {
+		statuses[contentID] = status
+	}
+
+	return statuses, s.readErr
+}
+
+func (s *segmentStore) Write(action idl.PgOptions_Action, contentID int32, status idl.Status) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.writeErr != nil {
+		return s.writeErr
+	}
+
+	if _, ok := s.statuses[action]; !ok {
+		s.statuses[action] = make(map[int32]idl.Status)
+	}
+	s.statuses[action][contentID] = status
+
+	return nil
+}
+
 // equivalentUpgradePrimariesRequest is a Matcher that can handle differences in order between
 // two instances of DeleteTablespaceRequest.Dirs
 func equivalentUpgradePrimariesRequest(req *idl.UpgradePrimariesRequest) gomock.Matcher {
@@ -10,6 +10,8 @@ import (
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	math "math"
 )
 
@@ -114,6 +116,8 @@ const (
 	Substep_verify_gpupgrade_is_installed_across_all_hosts                Substep = 47
 	Substep_initialize_wait_for_cluster_to_be_ready                       Substep = 48
 	Substep_wait_for_cluster_to_be_ready_before_upgrade_master            Substep = 49
+	Substep_check_target_cluster_ports                                    Substep = 50
+	Substep_write_upgrade_report                                          Substep = 51
 )
 
 var Substep_name = map[int32]string{
@@ -167,6 +171,8 @@ var Substep_name = map[int32]string{
 	47: "verify_gpupgrade_is_installed_across_all_hosts",
 	48: "initialize_wait_for_cluster_to_be_ready",
 	49: "wait_for_cluster_to_be_ready_before_upgrade_master",
+	50: "check_target_cluster_ports",
+	51: "write_upgrade_report",
 }
 
 var Substep_value = map[string]int32{
@@ -220,6 +226,8 @@ var Substep_value = map[string]int32{
 	"verify_gpupgrade_is_installed_across_all_hosts":                47,
 	"initialize_wait_for_cluster_to_be_ready":                       48,
 	"wait_for_cluster_to_be_ready_before_upgrade_master":            49,
+	"check_target_cluster_ports":                                    50,
+	"write_upgrade_report":                                          51,
 }
 
 func (x Substep) String() string {
@@ -292,12 +300,16 @@ func (x Chunk_Type) String() string {
 }
 
 func (Chunk_Type) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{12, 0}
+	return fileDescriptor_631e66a01873be02, []int{24, 0}
 }
 
 type InitializeRequest struct {
-	DiskFreeRatio        float64  `protobuf:"fixed64,1,opt,name=diskFreeRatio,proto3" json:"diskFreeRatio,omitempty"`
-	ParentBackupDirs     string   `protobuf:"bytes,2,opt,name=parentBackupDirs,proto3" json:"parentBackupDirs,omitempty"`
+	DiskFreeRatio    float64 `protobuf:"fixed64,1,opt,name=diskFreeRatio,proto3" json:"diskFreeRatio,omitempty"`
+	ParentBackupDirs string  `protobuf:"bytes,2,opt,name=parentBackupDirs,proto3" json:"parentBackupDirs,omitempty"`
+	// measureDiskSpace checks the disk space required by the upgrade based on
+	// the size of the data directories and tablespaces rather than the
+	// diskFreeRatio.
+	MeasureDiskSpace     bool     `protobuf:"varint,3,opt,name=measureDiskSpace,proto3" json:"measureDiskSpace,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -342,6 +354,13 @@ func (m *InitializeRequest) GetParentBackupDirs() string {
 	return ""
 }
 
+func (m *InitializeRequest) GetMeasureDiskSpace() bool {
+	if m != nil {
+		return m.MeasureDiskSpace
+	}
+	return false
+}
+
 type InitializeCreateClusterRequest struct {
 	DynamicLibraryPath   string   `protobuf:"bytes,1,opt,name=dynamicLibraryPath,proto3" json:"dynamicLibraryPath,omitempty"`
 	PgUpgradeVerbose     bool     `protobuf:"varint,2,opt,name=pgUpgradeVerbose,proto3" json:"pgUpgradeVerbose,omitempty"`
@@ -585,6 +604,11 @@ func (m *RestartAgentsReply) GetAgentHosts() []string {
 }
 
 type StopServicesRequest struct {
+	// wait is how long to wait for a running step to finish before stopping.
+	Wait *durationpb.Duration `protobuf:"bytes,1,opt,name=wait,proto3" json:"wait,omitempty"`
+	// force stops the hub even when a step is still running, which fails the
+	// interrupted substeps.
+	Force                bool     `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -615,6 +639,20 @@ func (m *StopServicesRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_StopServicesRequest proto.InternalMessageInfo
 
+func (m *StopServicesRequest) GetWait() *durationpb.Duration {
+	if m != nil {
+		return m.Wait
+	}
+	return nil
+}
+
+func (m *StopServicesRequest) GetForce() bool {
+	if m != nil {
+		return m.Force
+	}
+	return false
+}
+
 type StopServicesReply struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -646,6 +684,149 @@ func (m *StopServicesReply) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_StopServicesReply proto.InternalMessageInfo
 
+// StepLock is held by the hub while it runs a step such that only one step
+// runs at a time. It identifies the client that started the step.
+type StepLock struct {
+	Step                 Step                   `protobuf:"varint,1,opt,name=step,proto3,enum=idl.Step" json:"step,omitempty"`
+	User                 string                 `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Pid                  int32                  `protobuf:"varint,3,opt,name=pid,proto3" json:"pid,omitempty"`
+	Host                 string                 `protobuf:"bytes,4,opt,name=host,proto3" json:"host,omitempty"`
+	StartTime            *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=startTime,proto3" json:"startTime,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *StepLock) Reset()         { *m = StepLock{} }
+func (m *StepLock) String() string { return proto.CompactTextString(m) }
+func (*StepLock) ProtoMessage()    {}
+func (*StepLock) Descriptor() ([]byte, []int) {
+	return fileDescriptor_631e66a01873be02, []int{9}
+}
+
+func (m *StepLock) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StepLock.Unmarshal(m, b)
+}
+func (m *StepLock) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StepLock.Marshal(b, m, deterministic)
+}
+func (m *StepLock) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StepLock.Merge(m, src)
+}
+func (m *StepLock) XXX_Size() int {
+	return xxx_messageInfo_StepLock.Size(m)
+}
+func (m *StepLock) XXX_DiscardUnknown() {
+	xxx_messageInfo_StepLock.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StepLock proto.InternalMessageInfo
+
+func (m *StepLock) GetStep() Step {
+	if m != nil {
+		return m.Step
+	}
+	return Step_unknown_step
+}
+
+func (m *StepLock) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *StepLock) GetPid() int32 {
+	if m != nil {
+		return m.Pid
+	}
+	return 0
+}
+
+func (m *StepLock) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *StepLock) GetStartTime() *timestamppb.Timestamp {
+	if m != nil {
+		return m.StartTime
+	}
+	return nil
+}
+
+type BreakStepLockRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BreakStepLockRequest) Reset()         { *m = BreakStepLockRequest{} }
+func (m *BreakStepLockRequest) String() string { return proto.CompactTextString(m) }
+func (*BreakStepLockRequest) ProtoMessage()    {}
+func (*BreakStepLockRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_631e66a01873be02, []int{10}
+}
+
+func (m *BreakStepLockRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BreakStepLockRequest.Unmarshal(m, b)
+}
+func (m *BreakStepLockRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BreakStepLockRequest.Marshal(b, m, deterministic)
+}
+func (m *BreakStepLockRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BreakStepLockRequest.Merge(m, src)
+}
+func (m *BreakStepLockRequest) XXX_Size() int {
+	return xxx_messageInfo_BreakStepLockRequest.Size(m)
+}
+func (m *BreakStepLockRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BreakStepLockRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BreakStepLockRequest proto.InternalMessageInfo
+
+type BreakStepLockReply struct {
+	Lock                 *StepLock `protobuf:"bytes,1,opt,name=lock,proto3" json:"lock,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *BreakStepLockReply) Reset()         { *m = BreakStepLockReply{} }
+func (m *BreakStepLockReply) String() string { return proto.CompactTextString(m) }
+func (*BreakStepLockReply) ProtoMessage()    {}
+func (*BreakStepLockReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_631e66a01873be02, []int{11}
+}
+
+func (m *BreakStepLockReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BreakStepLockReply.Unmarshal(m, b)
+}
+func (m *BreakStepLockReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BreakStepLockReply.Marshal(b, m, deterministic)
+}
+func (m *BreakStepLockReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BreakStepLockReply.Merge(m, src)
+}
+func (m *BreakStepLockReply) XXX_Size() int {
+	return xxx_messageInfo_BreakStepLockReply.Size(m)
+}
+func (m *BreakStepLockReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_BreakStepLockReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BreakStepLockReply proto.InternalMessageInfo
+
+func (m *BreakStepLockReply) GetLock() *StepLock {
+	if m != nil {
+		return m.Lock
+	}
+	return nil
+}
+
 type SubstepStatus struct {
 	Step                 Substep  `protobuf:"varint,1,opt,name=step,proto3,enum=idl.Substep" json:"step,omitempty"`
 	Status               Status   `protobuf:"varint,2,opt,name=status,proto3,enum=idl.Status" json:"status,omitempty"`
@@ -658,7 +839,7 @@ func (m *SubstepStatus) Reset()         { *m = SubstepStatus{} }
 func (m *SubstepStatus) String() string { return proto.CompactTextString(m) }
 func (*SubstepStatus) ProtoMessage()    {}
 func (*SubstepStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{9}
+	return fileDescriptor_631e66a01873be02, []int{12}
 }
 
 func (m *SubstepStatus) XXX_Unmarshal(b []byte) error {
@@ -693,6 +874,415 @@ func (m *SubstepStatus) GetStatus() Status {
 	return Status_unknown_status
 }
 
+type GetStatusRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStatusRequest) Reset()         { *m = GetStatusRequest{} }
+func (m *GetStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*GetStatusRequest) ProtoMessage()    {}
+func (*GetStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_631e66a01873be02, []int{13}
+}
+
+func (m *GetStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStatusRequest.Unmarshal(m, b)
+}
+func (m *GetStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *GetStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStatusRequest.Merge(m, src)
+}
+func (m *GetStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_GetStatusRequest.Size(m)
+}
+func (m *GetStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStatusRequest proto.InternalMessageInfo
+
+type GetStatusReply struct {
+	Steps                []*StepStatus `protobuf:"bytes,1,rep,name=steps,proto3" json:"steps,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *GetStatusReply) Reset()         { *m = GetStatusReply{} }
+func (m *GetStatusReply) String() string { return proto.CompactTextString(m) }
+func (*GetStatusReply) ProtoMessage()    {}
+func (*GetStatusReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_631e66a01873be02, []int{14}
+}
+
+func (m *GetStatusReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStatusReply.Unmarshal(m, b)
+}
+func (m *GetStatusReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStatusReply.Marshal(b, m, deterministic)
+}
+func (m *GetStatusReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStatusReply.Merge(m, src)
+}
+func (m *GetStatusReply) XXX_Size() int {
+	return xxx_messageInfo_GetStatusReply.Size(m)
+}
+func (m *GetStatusReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStatusReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStatusReply proto.InternalMessageInfo
+
+func (m *GetStatusReply) GetSteps() []*StepStatus {
+	if m != nil {
+		return m.Steps
+	}
+	return nil
+}
+
+type GetAgentsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetAgentsRequest) Reset()         { *m = GetAgentsRequest{} }
+func (m *GetAgentsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetAgentsRequest) ProtoMessage()    {}
+func (*GetAgentsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_631e66a01873be02, []int{15}
+}
+
+func (m *GetAgentsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetAgentsRequest.Unmarshal(m, b)
+}
+func (m *GetAgentsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetAgentsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetAgentsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetAgentsRequest.Merge(m, src)
+}
+func (m *GetAgentsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetAgentsRequest.Size(m)
+}
+func (m *GetAgentsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetAgentsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetAgentsRequest proto.InternalMessageInfo
+
+type GetAgentsReply struct {
+	Agents               []*AgentStatus `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *GetAgentsReply) Reset()         { *m = GetAgentsReply{} }
+func (m *GetAgentsReply) String() string { return proto.CompactTextString(m) }
+func (*GetAgentsReply) ProtoMessage()    {}
+func (*GetAgentsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_631e66a01873be02, []int{16}
+}
+
+func (m *GetAgentsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetAgentsReply.Unmarshal(m, b)
+}
+func (m *GetAgentsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetAgentsReply.Marshal(b, m, deterministic)
+}
+func (m *GetAgentsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetAgentsReply.Merge(m, src)
+}
+func (m *GetAgentsReply) XXX_Size() int {
+	return xxx_messageInfo_GetAgentsReply.Size(m)
+}
+func (m *GetAgentsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetAgentsReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetAgentsReply proto.InternalMessageInfo
+
+func (m *GetAgentsReply) GetAgents() []*AgentStatus {
+	if m != nil {
+		return m.Agents
+	}
+	return nil
+}
+
+// AgentStatus is the information reported by the agent on host, or the
+// error when it could not be reached.
+type AgentStatus struct {
+	Host                 string     `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Info                 *AgentInfo `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
+	Error                string     `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *AgentStatus) Reset()         { *m = AgentStatus{} }
+func (m *AgentStatus) String() string { return proto.CompactTextString(m) }
+func (*AgentStatus) ProtoMessage()    {}
+func (*AgentStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_631e66a01873be02, []int{17}
+}
+
+func (m *AgentStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentStatus.Unmarshal(m, b)
+}
+func (m *AgentStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentStatus.Marshal(b, m, deterministic)
+}
+func (m *AgentStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentStatus.Merge(m, src)
+}
+func (m *AgentStatus) XXX_Size() int {
+	return xxx_messageInfo_AgentStatus.Size(m)
+}
+func (m *AgentStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentStatus proto.InternalMessageInfo
+
+func (m *AgentStatus) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *AgentStatus) GetInfo() *AgentInfo {
+	if m != nil {
+		return m.Info
+	}
+	return nil
+}
+
+func (m *AgentStatus) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type StepStatus struct {
+	Step                 Step              `protobuf:"varint,1,opt,name=step,proto3,enum=idl.Step" json:"step,omitempty"`
+	Status               Status            `protobuf:"varint,2,opt,name=status,proto3,enum=idl.Status" json:"status,omitempty"`
+	Substeps             []*SubstepDetails `protobuf:"bytes,3,rep,name=substeps,proto3" json:"substeps,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *StepStatus) Reset()         { *m = StepStatus{} }
+func (m *StepStatus) String() string { return proto.CompactTextString(m) }
+func (*StepStatus) ProtoMessage()    {}
+func (*StepStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_631e66a01873be02, []int{18}
+}
+
+func (m *StepStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StepStatus.Unmarshal(m, b)
+}
+func (m *StepStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StepStatus.Marshal(b, m, deterministic)
+}
+func (m *StepStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StepStatus.Merge(m, src)
+}
+func (m *StepStatus) XXX_Size() int {
+	return xxx_messageInfo_StepStatus.Size(m)
+}
+func (m *StepStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_StepStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StepStatus proto.InternalMessageInfo
+
+func (m *StepStatus) GetStep() Step {
+	if m != nil {
+		return m.Step
+	}
+	return Step_unknown_step
+}
+
+func (m *StepStatus) GetStatus() Status {
+	if m != nil {
+		return m.Status
+	}
+	return Status_unknown_status
+}
+
+func (m *StepStatus) GetSubsteps() []*SubstepDetails {
+	if m != nil {
+		return m.Substeps
+	}
+	return nil
+}
+
+type SubstepDetails struct {
+	Substep              Substep                `protobuf:"varint,1,opt,name=substep,proto3,enum=idl.Substep" json:"substep,omitempty"`
+	Status               Status                 `protobuf:"varint,2,opt,name=status,proto3,enum=idl.Status" json:"status,omitempty"`
+	StartTime            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=startTime,proto3" json:"startTime,omitempty"`
+	Duration             *durationpb.Duration   `protobuf:"bytes,4,opt,name=duration,proto3" json:"duration,omitempty"`
+	Error                string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *SubstepDetails) Reset()         { *m = SubstepDetails{} }
+func (m *SubstepDetails) String() string { return proto.CompactTextString(m) }
+func (*SubstepDetails) ProtoMessage()    {}
+func (*SubstepDetails) Descriptor() ([]byte, []int) {
+	return fileDescriptor_631e66a01873be02, []int{19}
+}
+
+func (m *SubstepDetails) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubstepDetails.Unmarshal(m, b)
+}
+func (m *SubstepDetails) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubstepDetails.Marshal(b, m, deterministic)
+}
+func (m *SubstepDetails) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubstepDetails.Merge(m, src)
+}
+func (m *SubstepDetails) XXX_Size() int {
+	return xxx_messageInfo_SubstepDetails.Size(m)
+}
+func (m *SubstepDetails) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubstepDetails.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubstepDetails proto.InternalMessageInfo
+
+func (m *SubstepDetails) GetSubstep() Substep {
+	if m != nil {
+		return m.Substep
+	}
+	return Substep_unknown_substep
+}
+
+func (m *SubstepDetails) GetStatus() Status {
+	if m != nil {
+		return m.Status
+	}
+	return Status_unknown_status
+}
+
+func (m *SubstepDetails) GetStartTime() *timestamppb.Timestamp {
+	if m != nil {
+		return m.StartTime
+	}
+	return nil
+}
+
+func (m *SubstepDetails) GetDuration() *durationpb.Duration {
+	if m != nil {
+		return m.Duration
+	}
+	return nil
+}
+
+func (m *SubstepDetails) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type RecoverRequest struct {
+	Substep              Substep  `protobuf:"varint,1,opt,name=substep,proto3,enum=idl.Substep" json:"substep,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RecoverRequest) Reset()         { *m = RecoverRequest{} }
+func (m *RecoverRequest) String() string { return proto.CompactTextString(m) }
+func (*RecoverRequest) ProtoMessage()    {}
+func (*RecoverRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_631e66a01873be02, []int{20}
+}
+
+func (m *RecoverRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RecoverRequest.Unmarshal(m, b)
+}
+func (m *RecoverRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RecoverRequest.Marshal(b, m, deterministic)
+}
+func (m *RecoverRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RecoverRequest.Merge(m, src)
+}
+func (m *RecoverRequest) XXX_Size() int {
+	return xxx_messageInfo_RecoverRequest.Size(m)
+}
+func (m *RecoverRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RecoverRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RecoverRequest proto.InternalMessageInfo
+
+func (m *RecoverRequest) GetSubstep() Substep {
+	if m != nil {
+		return m.Substep
+	}
+	return Substep_unknown_substep
+}
+
+type RecoverReply struct {
+	Step                 Step     `protobuf:"varint,1,opt,name=step,proto3,enum=idl.Step" json:"step,omitempty"`
+	Status               Status   `protobuf:"varint,2,opt,name=status,proto3,enum=idl.Status" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RecoverReply) Reset()         { *m = RecoverReply{} }
+func (m *RecoverReply) String() string { return proto.CompactTextString(m) }
+func (*RecoverReply) ProtoMessage()    {}
+func (*RecoverReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_631e66a01873be02, []int{21}
+}
+
+func (m *RecoverReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RecoverReply.Unmarshal(m, b)
+}
+func (m *RecoverReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RecoverReply.Marshal(b, m, deterministic)
+}
+func (m *RecoverReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RecoverReply.Merge(m, src)
+}
+func (m *RecoverReply) XXX_Size() int {
+	return xxx_messageInfo_RecoverReply.Size(m)
+}
+func (m *RecoverReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_RecoverReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RecoverReply proto.InternalMessageInfo
+
+func (m *RecoverReply) GetStep() Step {
+	if m != nil {
+		return m.Step
+	}
+	return Step_unknown_step
+}
+
+func (m *RecoverReply) GetStatus() Status {
+	if m != nil {
+		return m.Status
+	}
+	return Status_unknown_status
+}
+
 type PrepareInitClusterRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -703,7 +1293,7 @@ func (m *PrepareInitClusterRequest) Reset()         { *m = PrepareInitClusterReq
 func (m *PrepareInitClusterRequest) String() string { return proto.CompactTextString(m) }
 func (*PrepareInitClusterRequest) ProtoMessage()    {}
 func (*PrepareInitClusterRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{10}
+	return fileDescriptor_631e66a01873be02, []int{22}
 }
 
 func (m *PrepareInitClusterRequest) XXX_Unmarshal(b []byte) error {
@@ -734,7 +1324,7 @@ func (m *PrepareInitClusterReply) Reset()         { *m = PrepareInitClusterReply
 func (m *PrepareInitClusterReply) String() string { return proto.CompactTextString(m) }
 func (*PrepareInitClusterReply) ProtoMessage()    {}
 func (*PrepareInitClusterReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{11}
+	return fileDescriptor_631e66a01873be02, []int{23}
 }
 
 func (m *PrepareInitClusterReply) XXX_Unmarshal(b []byte) error {
@@ -767,7 +1357,7 @@ func (m *Chunk) Reset()         { *m = Chunk{} }
 func (m *Chunk) String() string { return proto.CompactTextString(m) }
 func (*Chunk) ProtoMessage()    {}
 func (*Chunk) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{12}
+	return fileDescriptor_631e66a01873be02, []int{24}
 }
 
 func (m *Chunk) XXX_Unmarshal(b []byte) error {
@@ -802,12 +1392,120 @@ func (m *Chunk) GetType() Chunk_Type {
 	return Chunk_unknown
 }
 
+// SegmentProgress reports the progress of pg_upgrade for a single segment. A
+// running status with an empty progress line is sent when the segment starts.
+type SegmentProgress struct {
+	Host                 string   `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	ContentID            int32    `protobuf:"varint,2,opt,name=contentID,proto3" json:"contentID,omitempty"`
+	Status               Status   `protobuf:"varint,3,opt,name=status,proto3,enum=idl.Status" json:"status,omitempty"`
+	Progress             string   `protobuf:"bytes,4,opt,name=progress,proto3" json:"progress,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SegmentProgress) Reset()         { *m = SegmentProgress{} }
+func (m *SegmentProgress) String() string { return proto.CompactTextString(m) }
+func (*SegmentProgress) ProtoMessage()    {}
+func (*SegmentProgress) Descriptor() ([]byte, []int) {
+	return fileDescriptor_631e66a01873be02, []int{25}
+}
+
+func (m *SegmentProgress) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SegmentProgress.Unmarshal(m, b)
+}
+func (m *SegmentProgress) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SegmentProgress.Marshal(b, m, deterministic)
+}
+func (m *SegmentProgress) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SegmentProgress.Merge(m, src)
+}
+func (m *SegmentProgress) XXX_Size() int {
+	return xxx_messageInfo_SegmentProgress.Size(m)
+}
+func (m *SegmentProgress) XXX_DiscardUnknown() {
+	xxx_messageInfo_SegmentProgress.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SegmentProgress proto.InternalMessageInfo
+
+func (m *SegmentProgress) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *SegmentProgress) GetContentID() int32 {
+	if m != nil {
+		return m.ContentID
+	}
+	return 0
+}
+
+func (m *SegmentProgress) GetStatus() Status {
+	if m != nil {
+		return m.Status
+	}
+	return Status_unknown_status
+}
+
+func (m *SegmentProgress) GetProgress() string {
+	if m != nil {
+		return m.Progress
+	}
+	return ""
+}
+
+// AgentLost reports that the agent on a host stopped sending heartbeats
+// while a substep was running.
+type AgentLost struct {
+	Host                 string   `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AgentLost) Reset()         { *m = AgentLost{} }
+func (m *AgentLost) String() string { return proto.CompactTextString(m) }
+func (*AgentLost) ProtoMessage()    {}
+func (*AgentLost) Descriptor() ([]byte, []int) {
+	return fileDescriptor_631e66a01873be02, []int{26}
+}
+
+func (m *AgentLost) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentLost.Unmarshal(m, b)
+}
+func (m *AgentLost) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentLost.Marshal(b, m, deterministic)
+}
+func (m *AgentLost) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentLost.Merge(m, src)
+}
+func (m *AgentLost) XXX_Size() int {
+	return xxx_messageInfo_AgentLost.Size(m)
+}
+func (m *AgentLost) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentLost.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentLost proto.InternalMessageInfo
+
+func (m *AgentLost) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
 type Message struct {
 	// Types that are valid to be assigned to Contents:
 	//
 	//	*Message_Chunk
 	//	*Message_Status
 	//	*Message_Response
+	//	*Message_SegmentProgress
+	//	*Message_AgentLost
 	Contents             isMessage_Contents `protobuf_oneof:"contents"`
 	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
 	XXX_unrecognized     []byte             `json:"-"`
@@ -818,7 +1516,7 @@ func (m *Message) Reset()         { *m = Message{} }
 func (m *Message) String() string { return proto.CompactTextString(m) }
 func (*Message) ProtoMessage()    {}
 func (*Message) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{13}
+	return fileDescriptor_631e66a01873be02, []int{27}
 }
 
 func (m *Message) XXX_Unmarshal(b []byte) error {
@@ -855,12 +1553,24 @@ type Message_Response struct {
 	Response *Response `protobuf:"bytes,3,opt,name=response,proto3,oneof"`
 }
 
+type Message_SegmentProgress struct {
+	SegmentProgress *SegmentProgress `protobuf:"bytes,4,opt,name=segmentProgress,proto3,oneof"`
+}
+
+type Message_AgentLost struct {
+	AgentLost *AgentLost `protobuf:"bytes,5,opt,name=agentLost,proto3,oneof"`
+}
+
 func (*Message_Chunk) isMessage_Contents() {}
 
 func (*Message_Status) isMessage_Contents() {}
 
 func (*Message_Response) isMessage_Contents() {}
 
+func (*Message_SegmentProgress) isMessage_Contents() {}
+
+func (*Message_AgentLost) isMessage_Contents() {}
+
 func (m *Message) GetContents() isMessage_Contents {
 	if m != nil {
 		return m.Contents
@@ -889,12 +1599,28 @@ func (m *Message) GetResponse() *Response {
 	return nil
 }
 
+func (m *Message) GetSegmentProgress() *SegmentProgress {
+	if x, ok := m.GetContents().(*Message_SegmentProgress); ok {
+		return x.SegmentProgress
+	}
+	return nil
+}
+
+func (m *Message) GetAgentLost() *AgentLost {
+	if x, ok := m.GetContents().(*Message_AgentLost); ok {
+		return x.AgentLost
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*Message) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
 		(*Message_Chunk)(nil),
 		(*Message_Status)(nil),
 		(*Message_Response)(nil),
+		(*Message_SegmentProgress)(nil),
+		(*Message_AgentLost)(nil),
 	}
 }
 
@@ -915,7 +1641,7 @@ func (m *Response) Reset()         { *m = Response{} }
 func (m *Response) String() string { return proto.CompactTextString(m) }
 func (*Response) ProtoMessage()    {}
 func (*Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{14}
+	return fileDescriptor_631e66a01873be02, []int{28}
 }
 
 func (m *Response) XXX_Unmarshal(b []byte) error {
@@ -1020,7 +1746,7 @@ func (m *InitializeResponse) Reset()         { *m = InitializeResponse{} }
 func (m *InitializeResponse) String() string { return proto.CompactTextString(m) }
 func (*InitializeResponse) ProtoMessage()    {}
 func (*InitializeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{15}
+	return fileDescriptor_631e66a01873be02, []int{29}
 }
 
 func (m *InitializeResponse) XXX_Unmarshal(b []byte) error {
@@ -1059,7 +1785,7 @@ func (m *ExecuteResponse) Reset()         { *m = ExecuteResponse{} }
 func (m *ExecuteResponse) String() string { return proto.CompactTextString(m) }
 func (*ExecuteResponse) ProtoMessage()    {}
 func (*ExecuteResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{16}
+	return fileDescriptor_631e66a01873be02, []int{30}
 }
 
 func (m *ExecuteResponse) XXX_Unmarshal(b []byte) error {
@@ -1101,7 +1827,7 @@ func (m *FinalizeResponse) Reset()         { *m = FinalizeResponse{} }
 func (m *FinalizeResponse) String() string { return proto.CompactTextString(m) }
 func (*FinalizeResponse) ProtoMessage()    {}
 func (*FinalizeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{17}
+	return fileDescriptor_631e66a01873be02, []int{31}
 }
 
 func (m *FinalizeResponse) XXX_Unmarshal(b []byte) error {
@@ -1162,7 +1888,7 @@ func (m *RevertResponse) Reset()         { *m = RevertResponse{} }
 func (m *RevertResponse) String() string { return proto.CompactTextString(m) }
 func (*RevertResponse) ProtoMessage()    {}
 func (*RevertResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{18}
+	return fileDescriptor_631e66a01873be02, []int{32}
 }
 
 func (m *RevertResponse) XXX_Unmarshal(b []byte) error {
@@ -1208,7 +1934,7 @@ func (m *GetConfigRequest) Reset()         { *m = GetConfigRequest{} }
 func (m *GetConfigRequest) String() string { return proto.CompactTextString(m) }
 func (*GetConfigRequest) ProtoMessage()    {}
 func (*GetConfigRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{19}
+	return fileDescriptor_631e66a01873be02, []int{33}
 }
 
 func (m *GetConfigRequest) XXX_Unmarshal(b []byte) error {
@@ -1247,7 +1973,7 @@ func (m *GetConfigReply) Reset()         { *m = GetConfigReply{} }
 func (m *GetConfigReply) String() string { return proto.CompactTextString(m) }
 func (*GetConfigReply) ProtoMessage()    {}
 func (*GetConfigReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{20}
+	return fileDescriptor_631e66a01873be02, []int{34}
 }
 
 func (m *GetConfigReply) XXX_Unmarshal(b []byte) error {
@@ -1288,7 +2014,7 @@ func (m *NextActions) Reset()         { *m = NextActions{} }
 func (m *NextActions) String() string { return proto.CompactTextString(m) }
 func (*NextActions) ProtoMessage()    {}
 func (*NextActions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{21}
+	return fileDescriptor_631e66a01873be02, []int{35}
 }
 
 func (m *NextActions) XXX_Unmarshal(b []byte) error {
@@ -1330,10 +2056,24 @@ func init() {
 	proto.RegisterType((*RestartAgentsReply)(nil), "idl.RestartAgentsReply")
 	proto.RegisterType((*StopServicesRequest)(nil), "idl.StopServicesRequest")
 	proto.RegisterType((*StopServicesReply)(nil), "idl.StopServicesReply")
+	proto.RegisterType((*StepLock)(nil), "idl.StepLock")
+	proto.RegisterType((*BreakStepLockRequest)(nil), "idl.BreakStepLockRequest")
+	proto.RegisterType((*BreakStepLockReply)(nil), "idl.BreakStepLockReply")
 	proto.RegisterType((*SubstepStatus)(nil), "idl.SubstepStatus")
+	proto.RegisterType((*GetStatusRequest)(nil), "idl.GetStatusRequest")
+	proto.RegisterType((*GetStatusReply)(nil), "idl.GetStatusReply")
+	proto.RegisterType((*GetAgentsRequest)(nil), "idl.GetAgentsRequest")
+	proto.RegisterType((*GetAgentsReply)(nil), "idl.GetAgentsReply")
+	proto.RegisterType((*AgentStatus)(nil), "idl.AgentStatus")
+	proto.RegisterType((*StepStatus)(nil), "idl.StepStatus")
+	proto.RegisterType((*SubstepDetails)(nil), "idl.SubstepDetails")
+	proto.RegisterType((*RecoverRequest)(nil), "idl.RecoverRequest")
+	proto.RegisterType((*RecoverReply)(nil), "idl.RecoverReply")
 	proto.RegisterType((*PrepareInitClusterRequest)(nil), "idl.PrepareInitClusterRequest")
 	proto.RegisterType((*PrepareInitClusterReply)(nil), "idl.PrepareInitClusterReply")
 	proto.RegisterType((*Chunk)(nil), "idl.Chunk")
+	proto.RegisterType((*SegmentProgress)(nil), "idl.SegmentProgress")
+	proto.RegisterType((*AgentLost)(nil), "idl.AgentLost")
 	proto.RegisterType((*Message)(nil), "idl.Message")
 	proto.RegisterType((*Response)(nil), "idl.Response")
 	proto.RegisterType((*InitializeResponse)(nil), "idl.InitializeResponse")
@@ -1348,115 +2088,148 @@ func init() {
 func init() { proto.RegisterFile("cli_to_hub.proto", fileDescriptor_631e66a01873be02) }
 
 var fileDescriptor_631e66a01873be02 = []byte{
-	// 1727 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x58, 0xcd, 0x92, 0x1b, 0xb7,
-	0xf1, 0x27, 0xf7, 0x93, 0x6c, 0xee, 0x72, 0xb1, 0xcd, 0xfd, 0x96, 0x2c, 0xef, 0x7f, 0x56, 0x96,
-	0x56, 0x2b, 0x9b, 0xd6, 0x9f, 0x4e, 0xd9, 0xc9, 0xc1, 0x55, 0x59, 0xad, 0xa4, 0x50, 0x55, 0xb6,
-	0x4b, 0x35, 0xeb, 0xe8, 0xe0, 0xcb, 0x14, 0x38, 0x03, 0x92, 0xa8, 0x1d, 0x0e, 0x46, 0x00, 0x66,
-	0x6d, 0xfa, 0x39, 0x72, 0xca, 0x0b, 0xe4, 0x96, 0x53, 0x1e, 0x28, 0x8f, 0x90, 0x47, 0x48, 0x01,
-	0x83, 0xe1, 0xc7, 0x90, 0x74, 0xe4, 0x1b, 0xa7, 0xbb, 0xf1, 0x43, 0x77, 0xa3, 0xfb, 0x87, 0x06,
-	0x81, 0x84, 0x31, 0x0f, 0xb4, 0x08, 0x86, 0x59, 0xaf, 0x9d, 0x4a, 0xa1, 0x05, 0xae, 0xf3, 0x28,
-	0x3e, 0xdb, 0x09, 0xc5, 0x68, 0x24, 0x92, 0x5c, 0xe4, 0x31, 0xd8, 0x7f, 0x9b, 0x70, 0xcd, 0x69,
-	0xcc, 0x7f, 0x65, 0x3e, 0xfb, 0x90, 0x31, 0xa5, 0xf1, 0x31, 0xec, 0x46, 0x5c, 0xdd, 0xbd, 0x91,
-	0x8c, 0xf9, 0x54, 0x73, 0x71, 0x52, 0x3d, 0xaf, 0x5e, 0x56, 0xfd, 0x79, 0x21, 0x5e, 0x01, 0x49,
-	0xa9, 0x64, 0x89, 0x7e, 0x49, 0xc3, 0xbb, 0x2c, 0x7d, 0xc5, 0xa5, 0x3a, 0x59, 0x3b, 0xaf, 0x5e,
-	0xd6, 0xfd, 0x05, 0xb9, 0xf7, 0xcf, 0x2a, 0x3c, 0x9a, 0xee, 0x73, 0x23, 0x19, 0xd5, 0xec, 0x26,
-	0xce, 0x94, 0x66, 0xb2, 0xd8, 0xb4, 0x0d, 0x18, 0x8d, 0x13, 0x3a, 0xe2, 0xe1, 0x77, 0xbc, 0x27,
-	0xa9, 0x1c, 0xbf, 0xa3, 0x7a, 0x68, 0x77, 0xae, 0xfb, 0x4b, 0x34, 0x76, 0xfb, 0xc1, 0x5f, 0xd3,
-	0x81, 0xa4, 0x11, 0x7b, 0xcf, 0x64, 0x4f, 0x28, 0x66, 0xb7, 0xaf, 0xf9, 0x0b, 0x72, 0x7c, 0x01,
-	0x2d, 0x75, 0xc7, 0xd3, 0x77, 0x85, 0xfc, 0x66, 0xc8, 0xc2, 0x3b, 0x75, 0xb2, 0x6e, 0xcd, 0x97,
-	0xa9, 0xbc, 0xbf, 0x57, 0xa1, 0xf9, 0xfa, 0x17, 0x16, 0x66, 0x7a, 0x92, 0x95, 0x65, 0x1b, 0x56,
-	0x7f, 0xdf, 0x86, 0x6b, 0x2b, 0x37, 0x5c, 0x9a, 0xcd, 0xf5, 0x15, 0xd9, 0xdc, 0x87, 0xbd, 0x37,
-	0x3c, 0x99, 0x3d, 0x32, 0x6f, 0x0f, 0x76, 0x7d, 0x76, 0xcf, 0xa4, 0x2e, 0x04, 0x47, 0x70, 0xe0,
-	0x33, 0xa5, 0xa9, 0xd4, 0xd7, 0x03, 0x96, 0x68, 0x55, 0xc8, 0xff, 0x00, 0x58, 0x92, 0xa7, 0xf1,
-	0x18, 0x1f, 0x01, 0x50, 0xf3, 0xd9, 0x15, 0x4a, 0xab, 0x93, 0xea, 0xf9, 0xfa, 0x65, 0xdd, 0x9f,
-	0x91, 0x78, 0x87, 0xd0, 0xba, 0xd5, 0x22, 0xbd, 0x65, 0xf2, 0x9e, 0x87, 0x6c, 0x02, 0xd6, 0x82,
-	0xfd, 0x79, 0x71, 0x1a, 0x8f, 0xbd, 0xf7, 0xb0, 0x7b, 0x9b, 0xf5, 0x94, 0x66, 0xe9, 0xad, 0xa6,
-	0x3a, 0x53, 0x78, 0x0e, 0x1b, 0xe6, 0xcb, 0x26, 0xab, 0xd9, 0xd9, 0x69, 0xf3, 0x28, 0x6e, 0x3b,
-	0x0b, 0xdf, 0x6a, 0xf0, 0x02, 0xb6, 0x94, 0xb5, 0xb5, 0x19, 0x6a, 0x76, 0x1a, 0xb9, 0x8d, 0x15,
-	0xf9, 0x4e, 0xe5, 0x3d, 0x80, 0xd3, 0x77, 0x92, 0x99, 0x64, 0x98, 0x4a, 0x9a, 0xaf, 0x1e, 0xef,
-	0x14, 0x8e, 0x97, 0x29, 0x8d, 0x3f, 0x1f, 0x60, 0xf3, 0x66, 0x98, 0x25, 0x77, 0x78, 0x04, 0x5b,
-	0xbd, 0xac, 0xdf, 0x67, 0xd2, 0x7a, 0xb2, 0xe3, 0xbb, 0x2f, 0xbc, 0x80, 0x0d, 0x3d, 0x4e, 0x99,
-	0xdb, 0x7b, 0xcf, 0xee, 0x6d, 0x57, 0xb4, 0x7f, 0x1c, 0xa7, 0xcc, 0xb7, 0x4a, 0xef, 0x39, 0x6c,
-	0x98, 0x2f, 0x6c, 0xc0, 0x76, 0x96, 0xdc, 0x25, 0xe2, 0xe7, 0x84, 0x54, 0x10, 0x8c, 0xdf, 0x91,
-	0xc8, 0x34, 0xa9, 0xba, 0xdf, 0x4c, 0x4a, 0xb2, 0xe6, 0xfd, 0xad, 0x0a, 0xdb, 0xdf, 0x33, 0xa5,
-	0xe8, 0x80, 0xa1, 0x07, 0x9b, 0xa1, 0x01, 0xb3, 0x9b, 0x36, 0x3a, 0x30, 0x85, 0xef, 0x56, 0xfc,
-	0x5c, 0x85, 0x9f, 0xcf, 0xc5, 0xdf, 0xe8, 0xe0, 0x6c, 0x8e, 0xf2, 0x34, 0x74, 0x2b, 0x45, 0x22,
-	0xf0, 0x39, 0xd4, 0x24, 0x53, 0xa9, 0x48, 0x14, 0xb3, 0x25, 0xd2, 0xe8, 0xec, 0x5a, 0x7b, 0xdf,
-	0x09, 0xbb, 0x15, 0x7f, 0x62, 0xf0, 0x12, 0xa0, 0x16, 0x8a, 0x44, 0x9b, 0xa3, 0xf6, 0xfe, 0xb1,
-	0x06, 0xb5, 0xc2, 0x08, 0xdf, 0x02, 0xf2, 0x99, 0xce, 0x9f, 0xc3, 0x3b, 0xb6, 0x78, 0x6f, 0x17,
-	0xd4, 0xdd, 0x8a, 0xbf, 0x64, 0x11, 0xfe, 0x19, 0xf6, 0x58, 0xd1, 0x2b, 0x0e, 0x67, 0xc3, 0xe2,
-	0x1c, 0x58, 0x9c, 0xd7, 0xf3, 0xba, 0x6e, 0xc5, 0x2f, 0x9b, 0xe3, 0x0d, 0x90, 0xfe, 0xa4, 0xa2,
-	0x1d, 0xc4, 0xa6, 0x85, 0x38, 0xb4, 0x10, 0x6f, 0x4a, 0xca, 0x6e, 0xc5, 0x5f, 0x58, 0x80, 0xdf,
-	0x42, 0x53, 0xba, 0x1e, 0x70, 0x10, 0x5b, 0x16, 0xa2, 0xe5, 0xb2, 0x33, 0xab, 0xea, 0x56, 0xfc,
-	0x92, 0xf1, 0x5c, 0xa6, 0x7e, 0x00, 0x5c, 0x8c, 0x1e, 0xff, 0x08, 0xc7, 0x5d, 0xaa, 0xae, 0xe3,
-	0xf8, 0x7b, 0x2e, 0xa5, 0x90, 0xea, 0x3a, 0x89, 0x6e, 0x35, 0x4d, 0xa2, 0xde, 0xd8, 0x11, 0xc1,
-	0x2a, 0xb5, 0xf7, 0x0d, 0xec, 0x95, 0xb2, 0x80, 0x8f, 0x61, 0x4b, 0x53, 0x39, 0x60, 0xda, 0x15,
-	0x46, 0xde, 0x17, 0x45, 0xe5, 0x3a, 0x9d, 0xf7, 0xef, 0x2a, 0x90, 0x72, 0xf0, 0x1f, 0xb7, 0xd4,
-	0x70, 0xd0, 0x77, 0x62, 0x70, 0x2d, 0xc3, 0x21, 0xbf, 0x67, 0xaf, 0xb8, 0x64, 0xa1, 0x16, 0x72,
-	0xec, 0x28, 0x7a, 0x99, 0x0a, 0xdf, 0xc3, 0x13, 0x27, 0x8b, 0x6e, 0x45, 0x26, 0x43, 0x76, 0x23,
-	0x84, 0x8c, 0x78, 0x42, 0xb5, 0x90, 0xaf, 0xa8, 0xa6, 0x53, 0x90, 0x9c, 0x99, 0x3e, 0xd2, 0x1a,
-	0x1f, 0x42, 0xdd, 0x91, 0xdd, 0xdb, 0x57, 0xb6, 0x32, 0xea, 0xfe, 0x54, 0xe0, 0x0d, 0xa1, 0x39,
-	0x7f, 0x36, 0x26, 0x3e, 0x65, 0x11, 0x97, 0xc7, 0x97, 0xeb, 0x7e, 0x7f, 0x7c, 0xde, 0x13, 0x20,
-	0x7f, 0x61, 0xfa, 0x46, 0x24, 0x7d, 0x3e, 0x28, 0x58, 0x1d, 0x61, 0x23, 0xa1, 0x23, 0xe6, 0x2e,
-	0x1a, 0xfb, 0xdb, 0x7b, 0x02, 0xcd, 0x19, 0x3b, 0xc3, 0x8f, 0x07, 0xb0, 0x79, 0x4f, 0xe3, 0xac,
-	0x30, 0xcb, 0x3f, 0xbc, 0x2f, 0xa1, 0xf1, 0x03, 0xfb, 0x45, 0x5f, 0x87, 0x9a, 0x8b, 0xc4, 0xf0,
-	0x5c, 0x23, 0x99, 0x7e, 0x3a, 0xd3, 0x59, 0xd1, 0xd5, 0x4f, 0xb0, 0x71, 0x6b, 0xf8, 0x8e, 0xc0,
-	0x8e, 0x23, 0x91, 0xc0, 0x74, 0x38, 0xa9, 0x60, 0x13, 0x60, 0xda, 0x58, 0xa4, 0x6a, 0x68, 0xc6,
-	0xf5, 0x08, 0x59, 0xc3, 0x1d, 0xa8, 0x15, 0xc5, 0x4e, 0xd6, 0x0d, 0xd1, 0xe4, 0x95, 0x4b, 0x36,
-	0xb0, 0x0e, 0x9b, 0x86, 0x14, 0x14, 0xd9, 0xbc, 0xfa, 0xd7, 0x0e, 0x6c, 0x3b, 0xc6, 0xc0, 0x16,
-	0xec, 0x4d, 0xf0, 0x73, 0x11, 0xa9, 0xe0, 0x39, 0x3c, 0x54, 0xf4, 0x9e, 0x27, 0x83, 0x20, 0x4f,
-	0x60, 0x10, 0xe6, 0x09, 0x0d, 0x42, 0x1b, 0x28, 0xa9, 0xe2, 0x2e, 0xd4, 0xed, 0xcd, 0x60, 0x46,
-	0x06, 0xb2, 0x66, 0xbc, 0xcc, 0x3f, 0xed, 0x45, 0xa0, 0xc8, 0x3a, 0x1e, 0xc2, 0x7e, 0x68, 0xae,
-	0xab, 0x80, 0x25, 0xf7, 0x5c, 0x8a, 0x64, 0xc4, 0x12, 0xe3, 0xc5, 0x01, 0x90, 0x5c, 0x6c, 0x06,
-	0x84, 0x40, 0xa5, 0x34, 0x64, 0x64, 0x13, 0xcf, 0xe0, 0x68, 0xc0, 0x12, 0x26, 0xa9, 0x66, 0x41,
-	0x5e, 0x92, 0xc5, 0x4e, 0x5b, 0x78, 0x0c, 0x2d, 0x13, 0xee, 0x44, 0x9e, 0x7b, 0x42, 0xb6, 0xf1,
-	0x01, 0x1c, 0xab, 0x61, 0xa6, 0x23, 0xe3, 0x7a, 0x49, 0x59, 0xc3, 0x13, 0x38, 0xe8, 0xd9, 0x5b,
-	0xb0, 0x50, 0x8d, 0xa8, 0xd5, 0xd4, 0x71, 0x1f, 0x76, 0x73, 0x0f, 0xb2, 0xbc, 0xac, 0x08, 0xcc,
-	0x21, 0xcd, 0x07, 0x4c, 0x1a, 0x88, 0xd0, 0x74, 0x96, 0x05, 0xc6, 0x0e, 0xee, 0x41, 0x23, 0x14,
-	0xe9, 0xb8, 0x10, 0xec, 0x9a, 0x68, 0x0b, 0xa3, 0x54, 0xf2, 0x11, 0x95, 0x9c, 0x29, 0xd2, 0x34,
-	0x5e, 0xe4, 0x69, 0x29, 0xf9, 0xb7, 0x87, 0xa7, 0x70, 0x98, 0xa5, 0xd1, 0x6c, 0xbc, 0x54, 0xd3,
-	0x58, 0x0c, 0x08, 0x31, 0xde, 0x38, 0x55, 0x44, 0x35, 0x0d, 0x22, 0x57, 0x93, 0x06, 0x71, 0x1f,
-	0x1f, 0xc2, 0x49, 0x69, 0x9d, 0x48, 0xfa, 0x41, 0x9f, 0xc7, 0x4c, 0x11, 0xb4, 0x87, 0xe9, 0xdc,
-	0x50, 0x39, 0x9d, 0x90, 0xd6, 0xac, 0x70, 0x94, 0xb3, 0x0d, 0x39, 0xc0, 0x23, 0xc0, 0x88, 0xc5,
-	0xcc, 0xe2, 0xf4, 0x62, 0x66, 0x0f, 0x42, 0x91, 0x43, 0xf4, 0xe0, 0xd1, 0x44, 0x3e, 0xeb, 0xb2,
-	0xf5, 0x25, 0xe2, 0x52, 0x91, 0x23, 0xe3, 0x83, 0xb3, 0x51, 0x6c, 0x60, 0xce, 0xd5, 0x6c, 0xa6,
-	0x99, 0xd5, 0x1e, 0x9b, 0xf3, 0x52, 0x5a, 0xa4, 0xa6, 0x30, 0x02, 0x9a, 0x44, 0x45, 0x45, 0x9c,
-	0x98, 0x43, 0x76, 0xcb, 0xf2, 0xb4, 0x4d, 0x56, 0x91, 0x53, 0x13, 0x33, 0xcd, 0x5b, 0x30, 0x88,
-	0xc5, 0x60, 0x2e, 0xe6, 0x33, 0xb3, 0x50, 0x32, 0xa5, 0x85, 0x64, 0xe5, 0xd3, 0x79, 0x30, 0xcd,
-	0x70, 0x49, 0xf3, 0xd0, 0x1c, 0x49, 0xb1, 0x2a, 0x1d, 0x18, 0xb6, 0x96, 0x22, 0x26, 0x9f, 0xe0,
-	0x27, 0x70, 0x2a, 0x59, 0x28, 0xee, 0x99, 0x54, 0xac, 0x5c, 0xde, 0xe4, 0x91, 0x39, 0x59, 0xd3,
-	0x03, 0x41, 0x7e, 0x7f, 0x92, 0x4f, 0xf1, 0x1a, 0xbe, 0xfd, 0x99, 0x72, 0x1d, 0xf4, 0x85, 0x9c,
-	0xe4, 0x42, 0x8b, 0xa0, 0xc7, 0x02, 0xc9, 0x68, 0x34, 0x0e, 0x68, 0xdf, 0x48, 0x68, 0x14, 0x99,
-	0x6e, 0x71, 0xf9, 0xb5, 0x71, 0x17, 0x07, 0x70, 0x8e, 0xdf, 0xc0, 0x57, 0x1f, 0x01, 0x61, 0x8f,
-	0xd5, 0x80, 0x14, 0x95, 0xf0, 0x7f, 0xd8, 0x81, 0xb6, 0x62, 0xda, 0x0a, 0xdd, 0x54, 0x1b, 0xc4,
-	0xf9, 0x58, 0x1b, 0xa4, 0x54, 0x0f, 0x03, 0xb1, 0x50, 0xf8, 0x1e, 0xb6, 0xe1, 0x2a, 0x2f, 0x6f,
-	0x1a, 0x6a, 0x93, 0xce, 0x50, 0x24, 0x09, 0xcb, 0x39, 0xc5, 0xd8, 0x97, 0x02, 0xbe, 0xf8, 0x5f,
-	0xf6, 0x25, 0xfc, 0xc7, 0x78, 0x01, 0x9f, 0x4e, 0x5a, 0xd5, 0xd6, 0xe7, 0x88, 0x0f, 0xa4, 0x19,
-	0xf2, 0x93, 0x40, 0x85, 0x92, 0xa7, 0x5a, 0x91, 0xcf, 0xf0, 0x12, 0x1e, 0x3b, 0x4a, 0xb2, 0x89,
-	0x54, 0xab, 0x2c, 0x9f, 0xe0, 0x17, 0xf0, 0xac, 0xb0, 0x9c, 0x92, 0xda, 0x2a, 0xf3, 0xa7, 0xf8,
-	0x1c, 0x9e, 0x16, 0xe6, 0x05, 0xcd, 0xad, 0x32, 0xbe, 0xc4, 0x67, 0xf0, 0x59, 0x61, 0x9c, 0xb3,
-	0xe0, 0x2a, 0xd3, 0x67, 0x96, 0xad, 0xec, 0x4b, 0x23, 0xc8, 0x59, 0xc3, 0xd6, 0xf2, 0x95, 0x61,
-	0x2b, 0x57, 0xb2, 0x13, 0x31, 0x79, 0x6e, 0xea, 0x91, 0x26, 0x34, 0x1e, 0xff, 0x5a, 0x6e, 0x12,
-	0xf2, 0x39, 0x3e, 0x85, 0x0b, 0x96, 0xa8, 0x4c, 0xb2, 0x60, 0x90, 0x16, 0x5d, 0x97, 0x77, 0x40,
-	0x40, 0x25, 0x0b, 0x64, 0x96, 0x24, 0x3c, 0x19, 0x90, 0x2f, 0x4c, 0xe1, 0xde, 0x33, 0xc9, 0xfb,
-	0xe3, 0x60, 0x90, 0x46, 0xbd, 0xc0, 0xd4, 0xa3, 0xc9, 0x39, 0x69, 0x9b, 0x53, 0x9f, 0x68, 0x0a,
-	0x08, 0xae, 0x02, 0x9e, 0x28, 0x4d, 0xe3, 0x98, 0x45, 0x01, 0x0d, 0xa5, 0x50, 0x2a, 0xa0, 0x71,
-	0x1c, 0x0c, 0xcd, 0xc8, 0x4d, 0xbe, 0x34, 0x79, 0x99, 0x49, 0xdf, 0x6f, 0x55, 0x1b, 0x79, 0x81,
-	0x5f, 0x43, 0xe7, 0x37, 0xeb, 0xb1, 0xc7, 0xfa, 0xa6, 0x6b, 0x4a, 0xac, 0xf7, 0xff, 0x57, 0x3f,
-	0xc1, 0x96, 0x1b, 0xd3, 0x0d, 0x27, 0x4e, 0x2e, 0x25, 0xdb, 0x28, 0x15, 0x73, 0x0d, 0x15, 0xd1,
-	0x55, 0xcd, 0x35, 0x14, 0x8a, 0x51, 0x6a, 0x52, 0x47, 0xd6, 0xcc, 0x35, 0xd4, 0xa7, 0x3c, 0x66,
-	0x11, 0x59, 0x37, 0x66, 0xe6, 0x4d, 0x93, 0xb2, 0x88, 0x6c, 0x60, 0x0d, 0x36, 0x3e, 0x64, 0x5c,
-	0x93, 0xcd, 0xce, 0x7f, 0xd6, 0xa1, 0x76, 0x13, 0xf3, 0x1f, 0x45, 0x37, 0xeb, 0xe1, 0xd7, 0x00,
-	0xd3, 0x91, 0x0a, 0x8f, 0x16, 0x26, 0x4c, 0x7b, 0x1d, 0x9f, 0xe5, 0x57, 0xbd, 0x9b, 0x9d, 0xbd,
-	0xca, 0x8b, 0x2a, 0xbe, 0x83, 0xe3, 0x15, 0x2f, 0x47, 0xbc, 0x28, 0x81, 0x2c, 0x7b, 0x57, 0x2e,
-	0x41, 0x7c, 0x01, 0xdb, 0x6e, 0x18, 0xc3, 0xd6, 0xfc, 0x80, 0xba, 0x6a, 0x45, 0x07, 0x6a, 0xc5,
-	0x10, 0x86, 0x07, 0xa5, 0x81, 0x74, 0xd5, 0x9a, 0x36, 0x6c, 0xe5, 0x63, 0x0d, 0xe2, 0xdc, 0xfc,
-	0xb9, 0xca, 0xfe, 0x4f, 0x50, 0x9f, 0x0c, 0x1d, 0x98, 0x4f, 0xbd, 0xe5, 0x61, 0xe5, 0xac, 0x55,
-	0x16, 0x9b, 0xf7, 0x4d, 0x05, 0x5f, 0x9b, 0xc7, 0xdf, 0xcc, 0x9b, 0x0e, 0x4f, 0x8b, 0xf7, 0xc0,
-	0xc2, 0xfb, 0xef, 0xec, 0x78, 0x99, 0x2a, 0x87, 0x79, 0x09, 0x3b, 0xb3, 0xaf, 0x39, 0x3c, 0x71,
-	0xaf, 0xb0, 0x85, 0x77, 0xdf, 0xd9, 0xd1, 0x12, 0x8d, 0xc5, 0xe8, 0x6d, 0xd9, 0xbf, 0x15, 0xbe,
-	0xfa, 0x6f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x69, 0x43, 0xc4, 0x72, 0x7d, 0x10, 0x00, 0x00,
+	// 2253 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0x4b, 0x6f, 0xdc, 0xc8,
+	0xf1, 0x9f, 0x91, 0x66, 0xa4, 0x51, 0x49, 0x1a, 0xb5, 0x5a, 0xb2, 0x34, 0x1a, 0xdb, 0xb2, 0x96,
+	0xf2, 0x43, 0x96, 0xd7, 0x63, 0xff, 0xe5, 0x7f, 0xd6, 0x4e, 0x80, 0x05, 0x22, 0x4b, 0x76, 0x64,
+	0xc0, 0xbb, 0x30, 0x28, 0xc7, 0x07, 0xe7, 0x40, 0xf4, 0x90, 0x3d, 0x23, 0x42, 0x1c, 0x36, 0xdd,
+	0xdd, 0x94, 0x77, 0xf6, 0x16, 0x20, 0xc8, 0x3d, 0xd7, 0x00, 0x39, 0xe7, 0x96, 0xcf, 0x14, 0x04,
+	0x39, 0xe7, 0x33, 0x04, 0xfd, 0x20, 0x87, 0xa4, 0x46, 0x6b, 0x19, 0xc8, 0x8d, 0xac, 0xaa, 0xae,
+	0xae, 0xc7, 0xaf, 0xaa, 0xab, 0x1b, 0x90, 0x1f, 0x85, 0x9e, 0x64, 0xde, 0x59, 0xda, 0xef, 0x25,
+	0x9c, 0x49, 0x86, 0x67, 0xc3, 0x20, 0xea, 0x2e, 0xf9, 0x6c, 0x34, 0x62, 0xb1, 0x21, 0x75, 0xb7,
+	0x87, 0x8c, 0x0d, 0x23, 0xfa, 0x44, 0xff, 0xf5, 0xd3, 0xc1, 0x93, 0x20, 0xe5, 0x44, 0x86, 0x39,
+	0xff, 0x4e, 0x95, 0x2f, 0xc3, 0x11, 0x15, 0x92, 0x8c, 0x12, 0x23, 0xe0, 0xfc, 0xa5, 0x0e, 0xab,
+	0x6f, 0xe2, 0x50, 0x86, 0x24, 0x0a, 0x7f, 0xa6, 0x2e, 0xfd, 0x94, 0x52, 0x21, 0xf1, 0x5d, 0x58,
+	0x0e, 0x42, 0x71, 0xfe, 0x9a, 0x53, 0xea, 0x2a, 0x75, 0x9d, 0xfa, 0x4e, 0x7d, 0xaf, 0xee, 0x96,
+	0x89, 0x78, 0x1f, 0x50, 0x42, 0x38, 0x8d, 0xe5, 0x4b, 0xe2, 0x9f, 0xa7, 0xc9, 0x71, 0xc8, 0x45,
+	0x67, 0x66, 0xa7, 0xbe, 0xb7, 0xe0, 0x5e, 0xa2, 0x2b, 0xd9, 0x11, 0x25, 0x22, 0xe5, 0xf4, 0x38,
+	0x14, 0xe7, 0xa7, 0x09, 0xf1, 0x69, 0x67, 0x76, 0xa7, 0xbe, 0xd7, 0x72, 0x2f, 0xd1, 0x9d, 0x7f,
+	0xd4, 0x61, 0x7b, 0x62, 0xd3, 0x11, 0xa7, 0x44, 0xd2, 0xa3, 0x28, 0x15, 0x92, 0xf2, 0xcc, 0xc0,
+	0x1e, 0xe0, 0x60, 0x1c, 0x93, 0x51, 0xe8, 0xbf, 0x0d, 0xfb, 0x9c, 0xf0, 0xf1, 0x3b, 0x22, 0xcf,
+	0xb4, 0x95, 0x0b, 0xee, 0x14, 0x8e, 0x36, 0x75, 0xf8, 0xfb, 0x64, 0xc8, 0x49, 0x40, 0x3f, 0x50,
+	0xde, 0x67, 0x82, 0x6a, 0x53, 0x5b, 0xee, 0x25, 0x3a, 0x7e, 0x0a, 0x6b, 0xe2, 0x3c, 0x4c, 0xde,
+	0x65, 0xf4, 0xa3, 0x33, 0xea, 0x9f, 0x0b, 0x6b, 0xed, 0x34, 0x96, 0xf3, 0xd7, 0x3a, 0xb4, 0x5f,
+	0xfd, 0x44, 0xfd, 0x54, 0xe6, 0x11, 0x9c, 0xb6, 0x61, 0xfd, 0xeb, 0x36, 0x9c, 0xb9, 0x72, 0xc3,
+	0xa9, 0x91, 0x9f, 0x9d, 0x1e, 0x79, 0x67, 0x15, 0x56, 0x5e, 0x87, 0x71, 0x31, 0xbd, 0xce, 0x0a,
+	0x2c, 0xbb, 0xf4, 0x82, 0x72, 0x99, 0x11, 0x36, 0x60, 0xdd, 0x55, 0xb0, 0xe0, 0xf2, 0x70, 0x48,
+	0x63, 0x29, 0x32, 0xfa, 0xff, 0x03, 0xae, 0xd0, 0x93, 0x68, 0x8c, 0xb7, 0x01, 0x88, 0xfa, 0x3d,
+	0x61, 0x42, 0x8a, 0x4e, 0x7d, 0x67, 0x76, 0x6f, 0xc1, 0x2d, 0x50, 0x9c, 0x8f, 0xb0, 0x76, 0x2a,
+	0x59, 0x72, 0x4a, 0xf9, 0x45, 0xe8, 0xd3, 0x4c, 0x19, 0x7e, 0x0c, 0x8d, 0xcf, 0x24, 0x94, 0x3a,
+	0x0c, 0x8b, 0x07, 0x5b, 0x3d, 0x03, 0xcd, 0x5e, 0x06, 0xcd, 0xde, 0xb1, 0x85, 0xae, 0xab, 0xc5,
+	0xf0, 0x3a, 0x34, 0x07, 0x8c, 0xfb, 0x59, 0x9e, 0xcc, 0x8f, 0xb3, 0x06, 0xab, 0x65, 0xdd, 0x49,
+	0x34, 0x76, 0xfe, 0x56, 0x87, 0xd6, 0xa9, 0xa4, 0xc9, 0x5b, 0xe6, 0x9f, 0xe3, 0xdb, 0xd0, 0x10,
+	0x92, 0x26, 0x7a, 0x9b, 0xf6, 0xc1, 0x42, 0x2f, 0x0c, 0xa2, 0x9e, 0x62, 0xba, 0x9a, 0x8c, 0x31,
+	0x34, 0x52, 0x41, 0xb9, 0x05, 0xaa, 0xfe, 0xc6, 0x08, 0x66, 0x93, 0x30, 0xd0, 0x11, 0x6c, 0xba,
+	0xea, 0x53, 0x49, 0x9d, 0x31, 0x21, 0x3b, 0x0d, 0x23, 0xa5, 0xbe, 0xf1, 0x0b, 0x58, 0xd0, 0xa1,
+	0x78, 0x1f, 0x8e, 0x68, 0xa7, 0xa9, 0x9d, 0xe8, 0x5e, 0x72, 0xe2, 0x7d, 0x56, 0x5f, 0xee, 0x44,
+	0x58, 0x85, 0xf7, 0x25, 0xa7, 0xe4, 0x3c, 0xb3, 0x31, 0x0b, 0xef, 0x73, 0xc0, 0x15, 0xba, 0x0a,
+	0xef, 0x37, 0xd0, 0x88, 0x98, 0x7f, 0x6e, 0xe3, 0xb4, 0x9c, 0x3b, 0xa0, 0x25, 0x34, 0xcb, 0xf9,
+	0x00, 0xcb, 0xa7, 0x69, 0x5f, 0xf9, 0x73, 0x2a, 0x89, 0x4c, 0x05, 0xde, 0x29, 0x39, 0xbd, 0x64,
+	0xd6, 0x18, 0x09, 0xeb, 0xf7, 0x2e, 0xcc, 0x09, 0x2d, 0xab, 0x3d, 0x6f, 0x1f, 0x2c, 0x5a, 0xbd,
+	0x8a, 0xe4, 0x5a, 0x96, 0x83, 0x01, 0xfd, 0x8e, 0x4a, 0x4b, 0xcc, 0x8d, 0x6c, 0x17, 0x68, 0xca,
+	0xc0, 0x7b, 0xd0, 0x54, 0x2a, 0x4d, 0xea, 0x17, 0x0f, 0x56, 0x72, 0x0b, 0xad, 0x90, 0xe1, 0x5a,
+	0x65, 0x65, 0x40, 0xfd, 0x06, 0xda, 0x05, 0x9a, 0x52, 0xb6, 0x07, 0x73, 0x1a, 0x3a, 0x99, 0x36,
+	0xa4, 0xb5, 0x69, 0x89, 0xcc, 0x38, 0xc3, 0x77, 0xfe, 0x00, 0x8b, 0x05, 0x72, 0x9e, 0xa2, 0x7a,
+	0x21, 0x45, 0x0e, 0x34, 0xc2, 0x78, 0xc0, 0xb4, 0x8b, 0x8b, 0x07, 0xed, 0x89, 0xaa, 0x37, 0xf1,
+	0x80, 0xb9, 0x9a, 0xa7, 0x70, 0x45, 0x39, 0x67, 0xdc, 0x16, 0x8c, 0xf9, 0x71, 0xfe, 0x58, 0x07,
+	0x98, 0xb8, 0xf0, 0x25, 0x10, 0x5d, 0x27, 0x98, 0xf8, 0x09, 0xb4, 0x84, 0x49, 0x81, 0x2a, 0x4e,
+	0xe5, 0xdb, 0x5a, 0x31, 0x2f, 0xc7, 0x54, 0x92, 0x30, 0x12, 0x6e, 0x2e, 0xe4, 0xfc, 0xbb, 0x0e,
+	0xed, 0x32, 0x13, 0xdf, 0x87, 0x79, 0xcb, 0x9e, 0x9a, 0xda, 0x8c, 0x79, 0x3d, 0x83, 0x4a, 0x00,
+	0x9e, 0xfd, 0x0a, 0x00, 0xe3, 0x5f, 0x41, 0x2b, 0x3b, 0x58, 0x3a, 0x8d, 0x2f, 0x95, 0x6f, 0x2e,
+	0x3a, 0x09, 0x75, 0xb3, 0x18, 0xea, 0x17, 0xd0, 0x76, 0xa9, 0xcf, 0x2e, 0x26, 0xdd, 0xfc, 0x9a,
+	0x5e, 0x3a, 0x2e, 0x2c, 0xe5, 0x2b, 0x15, 0x76, 0xfe, 0x07, 0x59, 0x72, 0x6e, 0xc2, 0xd6, 0x3b,
+	0x4e, 0x55, 0xd7, 0x54, 0x47, 0x4e, 0xf9, 0x98, 0x71, 0xb6, 0x60, 0x73, 0x1a, 0x53, 0xf5, 0x9c,
+	0x4f, 0xd0, 0x3c, 0x3a, 0x4b, 0xe3, 0x73, 0xbc, 0x01, 0x73, 0xfd, 0x74, 0x30, 0xa0, 0x5c, 0x9b,
+	0xb1, 0xe4, 0xda, 0x3f, 0xbc, 0x0b, 0x0d, 0x39, 0x4e, 0xa8, 0xdd, 0xdb, 0x14, 0x89, 0x5e, 0xd1,
+	0x7b, 0x3f, 0x4e, 0xa8, 0xab, 0x99, 0xce, 0x23, 0x68, 0xa8, 0x3f, 0xbc, 0x08, 0xf3, 0x69, 0x7c,
+	0x1e, 0xb3, 0xcf, 0x31, 0xaa, 0x61, 0x50, 0x76, 0x07, 0x2c, 0x95, 0xa8, 0x6e, 0xbf, 0x29, 0xe7,
+	0x68, 0xc6, 0xf9, 0x53, 0x1d, 0x56, 0x4e, 0xe9, 0x70, 0x44, 0x63, 0xf9, 0x8e, 0xb3, 0x21, 0xa7,
+	0x62, 0x7a, 0x15, 0xdc, 0x82, 0x05, 0x9f, 0xc5, 0x52, 0xc1, 0xfe, 0x58, 0x6f, 0xdf, 0x74, 0x27,
+	0x84, 0x42, 0x54, 0x66, 0xaf, 0x86, 0x4a, 0x17, 0x5a, 0x89, 0xdd, 0xc2, 0xf6, 0xc0, 0xfc, 0xdf,
+	0xb9, 0x03, 0x0b, 0xba, 0xa6, 0xde, 0xaa, 0xbd, 0xa6, 0xec, 0xef, 0xfc, 0x79, 0x06, 0xe6, 0x7f,
+	0xa0, 0x42, 0x90, 0x21, 0xc5, 0x0e, 0x34, 0x7d, 0xe5, 0xb4, 0xed, 0x66, 0x30, 0x09, 0xc3, 0x49,
+	0xcd, 0x35, 0x2c, 0xfc, 0x6d, 0x29, 0x4f, 0x8b, 0x07, 0xb8, 0x98, 0x7d, 0x63, 0xd8, 0x49, 0x2d,
+	0x37, 0xed, 0x11, 0xb4, 0x38, 0x15, 0x09, 0x8b, 0x45, 0x06, 0x62, 0xd3, 0x22, 0x5d, 0x4b, 0x3c,
+	0xa9, 0xb9, 0xb9, 0x00, 0xfe, 0x2d, 0xac, 0x88, 0x72, 0xc4, 0x2c, 0x7e, 0xd7, 0xcd, 0x1e, 0x65,
+	0xde, 0x49, 0xcd, 0xad, 0x8a, 0xe3, 0x1e, 0x2c, 0x90, 0xcc, 0xdb, 0x4e, 0xb3, 0xda, 0x57, 0x14,
+	0xf5, 0xa4, 0xe6, 0x4e, 0x44, 0x5e, 0x02, 0xb4, 0x6c, 0xac, 0x85, 0xf3, 0xf7, 0x19, 0x68, 0x65,
+	0x66, 0xe1, 0x37, 0x80, 0xc3, 0xc2, 0xa0, 0x55, 0xf2, 0x60, 0x53, 0x6b, 0x7c, 0x73, 0x89, 0x7d,
+	0x52, 0x73, 0xa7, 0x2c, 0x52, 0x5e, 0xd1, 0x6c, 0xdc, 0xb0, 0x7a, 0x8a, 0x5e, 0xbd, 0x2a, 0xf3,
+	0x94, 0x57, 0x15, 0x71, 0x7c, 0x04, 0x68, 0x90, 0x0f, 0x05, 0x56, 0x85, 0x71, 0xee, 0x86, 0x56,
+	0xf1, 0xba, 0xc2, 0x3c, 0xa9, 0xb9, 0x97, 0x16, 0xe0, 0xef, 0xa1, 0xcd, 0xed, 0x18, 0x61, 0x55,
+	0xcc, 0xed, 0xd4, 0xf3, 0x36, 0xe7, 0x96, 0x58, 0x27, 0x35, 0xb7, 0x22, 0x5c, 0x8a, 0xd4, 0x8f,
+	0x80, 0x2f, 0x7b, 0x8f, 0x5f, 0xc0, 0xe6, 0x09, 0x11, 0x87, 0x51, 0xf4, 0x43, 0xa8, 0x3a, 0x87,
+	0x38, 0x8c, 0x83, 0x53, 0x49, 0xe2, 0xa0, 0x3f, 0xb6, 0xb3, 0xd4, 0x55, 0x6c, 0xe7, 0x39, 0xac,
+	0x54, 0xa2, 0x80, 0xef, 0xc2, 0x9c, 0x24, 0x7c, 0x48, 0xb3, 0x01, 0xc4, 0xf4, 0x98, 0xac, 0xa6,
+	0x2d, 0xcf, 0xf9, 0x67, 0x1d, 0x50, 0xd5, 0xf9, 0xeb, 0x2d, 0x55, 0x63, 0xdc, 0x5b, 0x36, 0x3c,
+	0xe4, 0xfe, 0x59, 0x78, 0x41, 0x8f, 0x43, 0x4e, 0x7d, 0xc9, 0xf8, 0xd8, 0x0e, 0x1a, 0xd3, 0x58,
+	0xf8, 0x03, 0xdc, 0xb7, 0xb4, 0xe0, 0x94, 0xa5, 0xdc, 0xa7, 0x47, 0x8c, 0xf1, 0x20, 0x8c, 0x89,
+	0x64, 0xfc, 0x98, 0x48, 0x32, 0x51, 0x62, 0xce, 0xaa, 0x6b, 0x4a, 0xab, 0x06, 0x60, 0xe7, 0xc5,
+	0x37, 0xc7, 0xb6, 0x7c, 0x27, 0x04, 0xe7, 0x4c, 0xf5, 0xdf, 0x62, 0x26, 0x94, 0x7f, 0x42, 0x6b,
+	0x9c, 0xee, 0x9f, 0xe1, 0x7d, 0xbd, 0x7f, 0xce, 0x7d, 0x3d, 0x01, 0x1c, 0xb1, 0x78, 0x10, 0x0e,
+	0xb3, 0x5e, 0x8f, 0xa1, 0x11, 0x93, 0x11, 0xcd, 0x1a, 0x86, 0xfa, 0x76, 0xee, 0x43, 0xbb, 0x20,
+	0xa7, 0x3a, 0xfb, 0x3a, 0x34, 0x2f, 0x48, 0x94, 0x66, 0x62, 0xe6, 0xc7, 0x79, 0x02, 0x8b, 0x3f,
+	0xd2, 0x9f, 0xe4, 0xa1, 0xaf, 0x4e, 0x17, 0x35, 0xf4, 0x2c, 0xc6, 0x93, 0x5f, 0x2b, 0x5a, 0x24,
+	0xed, 0x7f, 0x84, 0x86, 0x3a, 0x0f, 0x30, 0x82, 0x25, 0xdb, 0x5e, 0x3d, 0x21, 0x69, 0x82, 0x6a,
+	0xb8, 0x0d, 0x30, 0x29, 0x2c, 0x54, 0x57, 0x0d, 0xd8, 0xd6, 0x08, 0x9a, 0xc1, 0x4b, 0xd0, 0xca,
+	0xc0, 0x8e, 0x66, 0x55, 0x0b, 0x36, 0xc8, 0x45, 0x0d, 0xbc, 0xa0, 0x46, 0x1f, 0x22, 0x05, 0x6a,
+	0xee, 0xff, 0x67, 0x09, 0xe6, 0x6d, 0x8f, 0xc2, 0x6b, 0xb0, 0x92, 0xeb, 0x37, 0x24, 0x54, 0xc3,
+	0x3b, 0x70, 0x4b, 0x90, 0x8b, 0x30, 0x1e, 0x7a, 0x26, 0x80, 0x9e, 0x6f, 0x02, 0xea, 0xf9, 0xda,
+	0x51, 0x54, 0xc7, 0xcb, 0xf6, 0x40, 0x56, 0x77, 0x3c, 0x34, 0xa3, 0xac, 0x34, 0xbf, 0x66, 0xe0,
+	0x41, 0xb3, 0xf8, 0x06, 0xac, 0xfa, 0x6a, 0xe2, 0xf7, 0x68, 0x7c, 0x11, 0x72, 0x16, 0xab, 0xce,
+	0x84, 0x1a, 0x78, 0x1d, 0x90, 0x21, 0xab, 0xfb, 0x98, 0x27, 0xd4, 0xa5, 0x09, 0x35, 0x71, 0x17,
+	0x36, 0x86, 0x34, 0xa6, 0x9c, 0x48, 0xea, 0x19, 0x48, 0x66, 0x3b, 0xcd, 0xe1, 0x4d, 0x58, 0x53,
+	0xee, 0xe6, 0x74, 0x63, 0x09, 0x9a, 0xc7, 0x37, 0x61, 0x53, 0x9c, 0xa5, 0x32, 0x50, 0xa6, 0x57,
+	0x98, 0x2d, 0xdc, 0x81, 0xf5, 0xbe, 0xbe, 0x48, 0x64, 0xac, 0x11, 0xd1, 0x9c, 0x05, 0xbc, 0x0a,
+	0xcb, 0xc6, 0x82, 0xd4, 0xc0, 0x0a, 0x41, 0x49, 0x53, 0xd9, 0x61, 0xb4, 0x88, 0x31, 0xb4, 0xad,
+	0x64, 0xa6, 0x63, 0x09, 0xaf, 0xc0, 0xa2, 0xcf, 0x92, 0x71, 0x46, 0x58, 0x56, 0xde, 0x66, 0x42,
+	0x09, 0x0f, 0x47, 0x84, 0x87, 0x54, 0xa0, 0xb6, 0xb2, 0xc2, 0x84, 0xa5, 0x62, 0xdf, 0x0a, 0xde,
+	0x82, 0x1b, 0x69, 0x12, 0x14, 0xfd, 0x25, 0x92, 0x44, 0x6c, 0x88, 0x90, 0xb2, 0xc6, 0xb2, 0x02,
+	0x22, 0x89, 0x17, 0x58, 0x4c, 0x2a, 0x8d, 0xab, 0xf8, 0x16, 0x74, 0x2a, 0xeb, 0x58, 0x3c, 0xf0,
+	0x06, 0x61, 0x44, 0x05, 0xc2, 0x3a, 0x99, 0xd6, 0x0c, 0x61, 0xda, 0x09, 0x5a, 0x2b, 0x12, 0x47,
+	0xa6, 0xdb, 0xa0, 0x75, 0xbc, 0x01, 0x38, 0xa0, 0x11, 0xd5, 0x7a, 0xfa, 0x11, 0xd5, 0x89, 0x10,
+	0xe8, 0x06, 0x76, 0x60, 0x3b, 0xa7, 0x17, 0x4d, 0xd6, 0xb6, 0x04, 0x21, 0x17, 0x68, 0x43, 0xd9,
+	0x60, 0x65, 0xec, 0x89, 0xa3, 0x36, 0x93, 0x54, 0x73, 0x37, 0x55, 0xbe, 0x84, 0x64, 0x89, 0x02,
+	0x86, 0x47, 0xe2, 0x20, 0x43, 0x44, 0x47, 0x25, 0xd9, 0x2e, 0x33, 0x61, 0xcb, 0x57, 0xa1, 0x2d,
+	0xe5, 0x33, 0x31, 0x25, 0xe8, 0x45, 0x6c, 0x58, 0xf2, 0xb9, 0xab, 0x16, 0x72, 0x2a, 0x24, 0xe3,
+	0xb4, 0x9a, 0x9d, 0x9b, 0x93, 0x08, 0x57, 0x38, 0xb7, 0x54, 0x4a, 0xb2, 0x55, 0xc9, 0x50, 0x75,
+	0x6b, 0xce, 0x22, 0x74, 0x1b, 0xdf, 0x86, 0x2d, 0x6e, 0x06, 0x31, 0x41, 0xab, 0xf0, 0x46, 0xdb,
+	0x2a, 0xb3, 0xaa, 0x06, 0x3c, 0x73, 0x62, 0xa3, 0x3b, 0xf8, 0x10, 0xbe, 0x57, 0x77, 0x3a, 0x6f,
+	0xc0, 0x78, 0x1e, 0x0b, 0xc9, 0xbc, 0x3e, 0xf5, 0x38, 0x25, 0xc1, 0xd8, 0x23, 0x03, 0x45, 0x21,
+	0x41, 0xa0, 0xaa, 0xc5, 0xc6, 0x57, 0xfb, 0x9d, 0x25, 0x60, 0x07, 0x3f, 0x87, 0x67, 0xd7, 0x50,
+	0xa1, 0xd3, 0xaa, 0x94, 0x64, 0x48, 0xf8, 0x06, 0x1f, 0x40, 0x4f, 0x50, 0xa9, 0x89, 0xf6, 0x61,
+	0xc0, 0x8b, 0xcc, 0xcb, 0x80, 0x97, 0x10, 0x79, 0xe6, 0xb1, 0x4b, 0xc0, 0x77, 0x70, 0x0f, 0xf6,
+	0x0d, 0xbc, 0x89, 0x2f, 0x55, 0x38, 0x7d, 0x16, 0xc7, 0xd4, 0xf4, 0x14, 0x25, 0x5f, 0x71, 0x78,
+	0xf7, 0x4b, 0xf2, 0x15, 0xfd, 0x77, 0xf1, 0x2e, 0xdc, 0xc9, 0x4b, 0x55, 0xe3, 0x73, 0x14, 0x0e,
+	0xcd, 0xcc, 0xec, 0x09, 0x9f, 0x87, 0x89, 0x14, 0xe8, 0x1e, 0xde, 0x83, 0xbb, 0xb6, 0x25, 0xe9,
+	0x40, 0x8a, 0xab, 0x24, 0xef, 0xe3, 0xc7, 0xf0, 0x30, 0x93, 0x9c, 0x34, 0xb5, 0xab, 0xc4, 0x1f,
+	0xe0, 0x47, 0xf0, 0x20, 0x13, 0xcf, 0xda, 0xdc, 0x55, 0xc2, 0x7b, 0xf8, 0x21, 0xdc, 0xcb, 0x84,
+	0x4d, 0x17, 0xbc, 0x4a, 0xf4, 0xa1, 0xee, 0x56, 0xfa, 0xb1, 0xc6, 0x33, 0x5d, 0x43, 0x63, 0x79,
+	0x5f, 0x75, 0x2b, 0x0b, 0xd9, 0x9c, 0x8c, 0x1e, 0x29, 0x3c, 0x92, 0x98, 0x44, 0xe3, 0x9f, 0xab,
+	0x45, 0x82, 0xbe, 0xc5, 0x0f, 0x60, 0x97, 0xc6, 0x22, 0xe5, 0xd4, 0x1b, 0x26, 0x59, 0xd5, 0x99,
+	0x0a, 0xf0, 0x08, 0xa7, 0x1e, 0x4f, 0xe3, 0x38, 0x8c, 0x87, 0xe8, 0xb1, 0x02, 0xee, 0x05, 0xe5,
+	0xe1, 0x60, 0xec, 0x0d, 0x93, 0xa0, 0xef, 0x29, 0x3c, 0xaa, 0x98, 0xa3, 0x9e, 0xca, 0x7a, 0xce,
+	0xc9, 0x54, 0x84, 0xc2, 0x0b, 0x63, 0x21, 0x49, 0x14, 0xd1, 0xc0, 0x23, 0x3e, 0x67, 0x42, 0x78,
+	0x24, 0x8a, 0x3c, 0x35, 0xb4, 0x0a, 0xf4, 0x44, 0xc5, 0xa5, 0x10, 0xbe, 0x5f, 0x42, 0x1b, 0x7a,
+	0x8a, 0xbf, 0x83, 0x83, 0x5f, 0xc4, 0x63, 0x9f, 0x0e, 0x54, 0xd5, 0x54, 0xba, 0xde, 0xff, 0xe1,
+	0x6d, 0xe8, 0x1a, 0xa8, 0x54, 0x5a, 0x43, 0xc2, 0xb8, 0x14, 0xe8, 0x40, 0xb9, 0xf4, 0x99, 0x87,
+	0x72, 0xb2, 0x92, 0x53, 0xc5, 0x42, 0xcf, 0xf6, 0x3f, 0xc2, 0x5c, 0x7e, 0xf5, 0x6d, 0x4f, 0x8e,
+	0x33, 0x5d, 0x62, 0x35, 0x75, 0x80, 0x65, 0x71, 0xa9, 0xab, 0x03, 0xcc, 0x67, 0xa3, 0x44, 0x05,
+	0x1d, 0xcd, 0xa8, 0x03, 0x6c, 0x40, 0xc2, 0x88, 0x06, 0x68, 0x56, 0x89, 0xa9, 0x07, 0xa5, 0x84,
+	0x06, 0xa8, 0x81, 0x5b, 0xd0, 0xf8, 0x94, 0x86, 0x12, 0x35, 0x0f, 0xfe, 0xd5, 0x84, 0xd6, 0x51,
+	0x14, 0xbe, 0x67, 0x27, 0x69, 0x1f, 0x7f, 0x07, 0x30, 0x19, 0xc6, 0xf0, 0xc6, 0xa5, 0xd9, 0x54,
+	0x1f, 0xe4, 0x5d, 0x33, 0x24, 0xd8, 0x39, 0xdf, 0xa9, 0x3d, 0xad, 0xe3, 0x77, 0xb0, 0x79, 0xc5,
+	0xb3, 0x1d, 0xde, 0xad, 0x28, 0x99, 0xf6, 0xa8, 0x37, 0x45, 0xe3, 0x53, 0x98, 0xb7, 0x63, 0x1c,
+	0x5e, 0x2b, 0x8f, 0xb6, 0x57, 0xad, 0x38, 0x80, 0x56, 0x36, 0xbe, 0xe1, 0xf5, 0xca, 0x28, 0x7b,
+	0xd5, 0x9a, 0x1e, 0xcc, 0x99, 0x81, 0x08, 0xe3, 0xd2, 0xe4, 0x7a, 0x95, 0xfc, 0xaf, 0x61, 0x21,
+	0x1f, 0x57, 0xb0, 0x99, 0x97, 0xab, 0x63, 0x4e, 0x77, 0xad, 0x4a, 0x56, 0x77, 0xc6, 0x1a, 0x7e,
+	0xa5, 0x5e, 0xde, 0x0a, 0x0f, 0x6a, 0x78, 0xcb, 0xee, 0x78, 0xf9, 0xf1, 0xad, 0xbb, 0x39, 0x8d,
+	0x65, 0xd4, 0xbc, 0x84, 0xa5, 0xe2, 0x2b, 0x18, 0xee, 0xd8, 0x3b, 0xdc, 0xa5, 0x47, 0xb7, 0xee,
+	0xc6, 0x14, 0x8e, 0xd1, 0x61, 0xbc, 0xb0, 0x88, 0xca, 0xbd, 0x28, 0xbd, 0xfd, 0x74, 0xd7, 0xaa,
+	0x64, 0xb3, 0xf4, 0x19, 0xcc, 0xdb, 0x7b, 0x38, 0xce, 0x66, 0xfd, 0xe2, 0x7d, 0xbe, 0xbb, 0x5a,
+	0x26, 0x16, 0xf7, 0xb3, 0x6e, 0xe7, 0xfb, 0x95, 0x5d, 0x5e, 0xab, 0x92, 0xf3, 0xa8, 0x95, 0xde,
+	0xc9, 0x6c, 0xd4, 0xa6, 0xbd, 0xa9, 0x75, 0x37, 0xa7, 0xb1, 0xb4, 0x9a, 0xfe, 0x9c, 0x7e, 0xab,
+	0x78, 0xf6, 0xdf, 0x01, 0x00, 0x97, 0x6b, 0x17, 0xf6, 0x5a, 0x17, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1479,6 +2252,10 @@ type CliToHubClient interface {
 	GetConfig(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (*GetConfigReply, error)
 	RestartAgents(ctx context.Context, in *RestartAgentsRequest, opts ...grpc.CallOption) (*RestartAgentsReply, error)
 	StopServices(ctx context.Context, in *StopServicesRequest, opts ...grpc.CallOption) (*StopServicesReply, error)
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusReply, error)
+	Recover(ctx context.Context, in *RecoverRequest, opts ...grpc.CallOption) (*RecoverReply, error)
+	GetAgents(ctx context.Context, in *GetAgentsRequest, opts ...grpc.CallOption) (*GetAgentsReply, error)
+	BreakStepLock(ctx context.Context, in *BreakStepLockRequest, opts ...grpc.CallOption) (*BreakStepLockReply, error)
 }
 
 type cliToHubClient struct {
@@ -1676,6 +2453,42 @@ func (c *cliToHubClient) StopServices(ctx context.Context, in *StopServicesReque
 	return out, nil
 }
 
+func (c *cliToHubClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusReply, error) {
+	out := new(GetStatusReply)
+	err := c.cc.Invoke(ctx, "/idl.CliToHub/GetStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cliToHubClient) Recover(ctx context.Context, in *RecoverRequest, opts ...grpc.CallOption) (*RecoverReply, error) {
+	out := new(RecoverReply)
+	err := c.cc.Invoke(ctx, "/idl.CliToHub/Recover", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cliToHubClient) GetAgents(ctx context.Context, in *GetAgentsRequest, opts ...grpc.CallOption) (*GetAgentsReply, error) {
+	out := new(GetAgentsReply)
+	err := c.cc.Invoke(ctx, "/idl.CliToHub/GetAgents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cliToHubClient) BreakStepLock(ctx context.Context, in *BreakStepLockRequest, opts ...grpc.CallOption) (*BreakStepLockReply, error) {
+	out := new(BreakStepLockReply)
+	err := c.cc.Invoke(ctx, "/idl.CliToHub/BreakStepLock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CliToHubServer is the server API for CliToHub service.
 type CliToHubServer interface {
 	Initialize(*InitializeRequest, CliToHub_InitializeServer) error
@@ -1686,6 +2499,10 @@ type CliToHubServer interface {
 	GetConfig(context.Context, *GetConfigRequest) (*GetConfigReply, error)
 	RestartAgents(context.Context, *RestartAgentsRequest) (*RestartAgentsReply, error)
 	StopServices(context.Context, *StopServicesRequest) (*StopServicesReply, error)
+	GetStatus(context.Context, *GetStatusRequest) (*GetStatusReply, error)
+	Recover(context.Context, *RecoverRequest) (*RecoverReply, error)
+	GetAgents(context.Context, *GetAgentsRequest) (*GetAgentsReply, error)
+	BreakStepLock(context.Context, *BreakStepLockRequest) (*BreakStepLockReply, error)
 }
 
 // UnimplementedCliToHubServer can be embedded to have forward compatible implementations.
@@ -1716,6 +2533,18 @@ func (*UnimplementedCliToHubServer) RestartAgents(ctx context.Context, req *Rest
 func (*UnimplementedCliToHubServer) StopServices(ctx context.Context, req *StopServicesRequest) (*StopServicesReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method StopServices not implemented")
 }
+func (*UnimplementedCliToHubServer) GetStatus(ctx context.Context, req *GetStatusRequest) (*GetStatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (*UnimplementedCliToHubServer) Recover(ctx context.Context, req *RecoverRequest) (*RecoverReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Recover not implemented")
+}
+func (*UnimplementedCliToHubServer) GetAgents(ctx context.Context, req *GetAgentsRequest) (*GetAgentsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAgents not implemented")
+}
+func (*UnimplementedCliToHubServer) BreakStepLock(ctx context.Context, req *BreakStepLockRequest) (*BreakStepLockReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BreakStepLock not implemented")
+}
 
 func RegisterCliToHubServer(s *grpc.Server, srv CliToHubServer) {
 	s.RegisterService(&_CliToHub_serviceDesc, srv)
@@ -1880,6 +2709,78 @@ func _CliToHub_StopServices_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CliToHub_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.CliToHub/GetStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CliToHub_Recover_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecoverRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).Recover(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.CliToHub/Recover",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).Recover(ctx, req.(*RecoverRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CliToHub_GetAgents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAgentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).GetAgents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.CliToHub/GetAgents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).GetAgents(ctx, req.(*GetAgentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CliToHub_BreakStepLock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BreakStepLockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).BreakStepLock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.CliToHub/BreakStepLock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).BreakStepLock(ctx, req.(*BreakStepLockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _CliToHub_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "idl.CliToHub",
 	HandlerType: (*CliToHubServer)(nil),
@@ -1896,6 +2797,22 @@ var _CliToHub_serviceDesc = grpc.ServiceDesc{
 			MethodName: "StopServices",
 			Handler:    _CliToHub_StopServices_Handler,
 		},
+		{
+			MethodName: "GetStatus",
+			Handler:    _CliToHub_GetStatus_Handler,
+		},
+		{
+			MethodName: "Recover",
+			Handler:    _CliToHub_Recover_Handler,
+		},
+		{
+			MethodName: "GetAgents",
+			Handler:    _CliToHub_GetAgents_Handler,
+		},
+		{
+			MethodName: "BreakStepLock",
+			Handler:    _CliToHub_BreakStepLock_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
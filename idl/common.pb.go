@@ -249,39 +249,183 @@ func (m *Cluster) GetCoordinator() *Segment {
 	return nil
 }
 
+type AgentInfo struct {
+	Version              string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Hostname             string                 `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	UptimeSeconds        float64                `protobuf:"fixed64,3,opt,name=uptimeSeconds,proto3" json:"uptimeSeconds,omitempty"`
+	StateDir             string                 `protobuf:"bytes,4,opt,name=stateDir,proto3" json:"stateDir,omitempty"`
+	BinaryPath           string                 `protobuf:"bytes,5,opt,name=binaryPath,proto3" json:"binaryPath,omitempty"`
+	DiskSpace            []*AgentInfo_DiskSpace `protobuf:"bytes,6,rep,name=diskSpace,proto3" json:"diskSpace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *AgentInfo) Reset()         { *m = AgentInfo{} }
+func (m *AgentInfo) String() string { return proto.CompactTextString(m) }
+func (*AgentInfo) ProtoMessage()    {}
+func (*AgentInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_555bd8c177793206, []int{2}
+}
+
+func (m *AgentInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentInfo.Unmarshal(m, b)
+}
+func (m *AgentInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentInfo.Marshal(b, m, deterministic)
+}
+func (m *AgentInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentInfo.Merge(m, src)
+}
+func (m *AgentInfo) XXX_Size() int {
+	return xxx_messageInfo_AgentInfo.Size(m)
+}
+func (m *AgentInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentInfo proto.InternalMessageInfo
+
+func (m *AgentInfo) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *AgentInfo) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *AgentInfo) GetUptimeSeconds() float64 {
+	if m != nil {
+		return m.UptimeSeconds
+	}
+	return 0
+}
+
+func (m *AgentInfo) GetStateDir() string {
+	if m != nil {
+		return m.StateDir
+	}
+	return ""
+}
+
+func (m *AgentInfo) GetBinaryPath() string {
+	if m != nil {
+		return m.BinaryPath
+	}
+	return ""
+}
+
+func (m *AgentInfo) GetDiskSpace() []*AgentInfo_DiskSpace {
+	if m != nil {
+		return m.DiskSpace
+	}
+	return nil
+}
+
+type AgentInfo_DiskSpace struct {
+	Dir                  string   `protobuf:"bytes,1,opt,name=dir,proto3" json:"dir,omitempty"`
+	Available            uint64   `protobuf:"varint,2,opt,name=available,proto3" json:"available,omitempty"`
+	Error                string   `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AgentInfo_DiskSpace) Reset()         { *m = AgentInfo_DiskSpace{} }
+func (m *AgentInfo_DiskSpace) String() string { return proto.CompactTextString(m) }
+func (*AgentInfo_DiskSpace) ProtoMessage()    {}
+func (*AgentInfo_DiskSpace) Descriptor() ([]byte, []int) {
+	return fileDescriptor_555bd8c177793206, []int{2, 0}
+}
+
+func (m *AgentInfo_DiskSpace) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentInfo_DiskSpace.Unmarshal(m, b)
+}
+func (m *AgentInfo_DiskSpace) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentInfo_DiskSpace.Marshal(b, m, deterministic)
+}
+func (m *AgentInfo_DiskSpace) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentInfo_DiskSpace.Merge(m, src)
+}
+func (m *AgentInfo_DiskSpace) XXX_Size() int {
+	return xxx_messageInfo_AgentInfo_DiskSpace.Size(m)
+}
+func (m *AgentInfo_DiskSpace) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentInfo_DiskSpace.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentInfo_DiskSpace proto.InternalMessageInfo
+
+func (m *AgentInfo_DiskSpace) GetDir() string {
+	if m != nil {
+		return m.Dir
+	}
+	return ""
+}
+
+func (m *AgentInfo_DiskSpace) GetAvailable() uint64 {
+	if m != nil {
+		return m.Available
+	}
+	return 0
+}
+
+func (m *AgentInfo_DiskSpace) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterEnum("idl.Mode", Mode_name, Mode_value)
 	proto.RegisterEnum("idl.ClusterDestination", ClusterDestination_name, ClusterDestination_value)
 	proto.RegisterEnum("idl.Segment_Role", Segment_Role_name, Segment_Role_value)
 	proto.RegisterType((*Segment)(nil), "idl.Segment")
 	proto.RegisterType((*Cluster)(nil), "idl.Cluster")
+	proto.RegisterType((*AgentInfo)(nil), "idl.AgentInfo")
+	proto.RegisterType((*AgentInfo_DiskSpace)(nil), "idl.AgentInfo.DiskSpace")
 }
 
 func init() { proto.RegisterFile("common.proto", fileDescriptor_555bd8c177793206) }
 
 var fileDescriptor_555bd8c177793206 = []byte{
-	// 364 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x52, 0x41, 0xcb, 0xd3, 0x40,
-	0x10, 0xfd, 0x36, 0x49, 0x93, 0x76, 0x52, 0xca, 0xba, 0x82, 0x0d, 0xe2, 0xa1, 0x14, 0x84, 0x52,
-	0x24, 0x60, 0x3d, 0x79, 0x36, 0x07, 0x7b, 0xf0, 0x12, 0x0f, 0x1e, 0x25, 0xcd, 0x2e, 0x75, 0x69,
-	0x76, 0x27, 0x4c, 0xb6, 0x8a, 0x7f, 0xc8, 0xbf, 0xe5, 0x5f, 0x91, 0xdd, 0xa6, 0x36, 0xe2, 0x6d,
-	0x5e, 0xde, 0xe4, 0xcd, 0x7b, 0x8f, 0x85, 0x65, 0x8b, 0xc6, 0xa0, 0x2d, 0x7b, 0x42, 0x87, 0x22,
-	0xd6, 0xb2, 0xdb, 0xfe, 0x66, 0x90, 0x7d, 0x56, 0x67, 0xa3, 0xac, 0x13, 0x02, 0x12, 0x79, 0x3a,
-	0x56, 0x05, 0xdb, 0xb0, 0xdd, 0xac, 0x0e, 0xb3, 0x78, 0x05, 0x8b, 0x16, 0xad, 0x53, 0xd6, 0x1d,
-	0xab, 0x22, 0x0a, 0xc4, 0xe3, 0x83, 0x78, 0x0d, 0x09, 0x61, 0xa7, 0x8a, 0x78, 0xc3, 0x76, 0xab,
-	0xc3, 0xb3, 0x52, 0xcb, 0xae, 0x1c, 0xd5, 0xca, 0x1a, 0x3b, 0x55, 0x07, 0xda, 0x0b, 0xf7, 0x48,
-	0xae, 0x48, 0x6e, 0xc2, 0x7e, 0x16, 0x2f, 0x61, 0xfe, 0x0d, 0x07, 0x67, 0x1b, 0xa3, 0x8a, 0xd9,
-	0x86, 0xed, 0x16, 0xf5, 0x5f, 0x2c, 0x0a, 0xc8, 0x64, 0xe3, 0x9a, 0x4a, 0x53, 0x91, 0x06, 0xea,
-	0x0e, 0xb7, 0x6f, 0x21, 0xf1, 0xba, 0x82, 0xc3, 0xf2, 0x6a, 0x2f, 0x16, 0x7f, 0xd8, 0xaf, 0xfe,
-	0x02, 0x7f, 0x12, 0x39, 0x64, 0x3d, 0x69, 0xd3, 0xd0, 0x4f, 0xce, 0x04, 0x40, 0x6a, 0x34, 0x11,
-	0x12, 0x8f, 0xb6, 0xbf, 0x18, 0x64, 0x1f, 0xba, 0xeb, 0xe0, 0x14, 0x89, 0xf7, 0x90, 0x4b, 0x35,
-	0x38, 0x6d, 0x1b, 0xa7, 0xd1, 0x86, 0xa0, 0xab, 0xc3, 0x3a, 0xd8, 0x1e, 0x57, 0xaa, 0x07, 0x5d,
-	0x4f, 0x77, 0xc5, 0x0b, 0x48, 0xcf, 0xfd, 0x47, 0x34, 0x2a, 0xb4, 0xb0, 0xa8, 0x47, 0xe4, 0xbd,
-	0x7e, 0x57, 0x34, 0x78, 0xb9, 0xf8, 0xe6, 0x75, 0x84, 0xa2, 0x84, 0xbc, 0x45, 0x24, 0xe9, 0x15,
-	0x90, 0x42, 0xf8, 0xfc, 0xb0, 0x9c, 0x76, 0x54, 0x4f, 0x17, 0xf6, 0x6f, 0x20, 0xf9, 0x84, 0xf2,
-	0x9f, 0x6c, 0x06, 0xa5, 0xcf, 0x36, 0x87, 0xa4, 0xc5, 0xde, 0x07, 0x9b, 0x43, 0xd2, 0x69, 0x7b,
-	0xe1, 0xd1, 0xfe, 0x0b, 0x88, 0xff, 0x2d, 0x8b, 0x35, 0x3c, 0xbf, 0xff, 0x3b, 0x31, 0xcf, 0x9f,
-	0x7c, 0x23, 0x03, 0x5e, 0xa9, 0x55, 0x9c, 0xf9, 0x03, 0xda, 0x3a, 0x45, 0x46, 0x49, 0xdd, 0x38,
-	0xc5, 0x23, 0xcf, 0xba, 0x86, 0xce, 0xca, 0xf1, 0xf8, 0x94, 0x86, 0xd7, 0xf1, 0xee, 0x4f, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0x5b, 0xd0, 0xe7, 0xe3, 0x2d, 0x02, 0x00, 0x00,
+	// 494 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x53, 0x4d, 0x8b, 0x13, 0x41,
+	0x10, 0xdd, 0xf9, 0xc8, 0xc7, 0x54, 0xe2, 0x32, 0xb6, 0xe2, 0x0e, 0x8b, 0x48, 0x08, 0x0a, 0x61,
+	0x91, 0x01, 0x23, 0x08, 0x1e, 0xc5, 0x39, 0xb8, 0x07, 0x41, 0x3b, 0x07, 0x8f, 0xd2, 0x99, 0x2e,
+	0xb3, 0x4d, 0x66, 0xba, 0x86, 0x9e, 0xce, 0xca, 0xfe, 0x21, 0x8f, 0xfe, 0x25, 0xff, 0x8a, 0x74,
+	0x67, 0x92, 0x4c, 0xf4, 0x56, 0x55, 0xaf, 0xe6, 0xf5, 0xab, 0x57, 0x35, 0x30, 0x2d, 0xa9, 0xae,
+	0x49, 0xe7, 0x8d, 0x21, 0x4b, 0x2c, 0x52, 0xb2, 0x9a, 0xff, 0x09, 0x60, 0xb4, 0xc2, 0x4d, 0x8d,
+	0xda, 0x32, 0x06, 0xb1, 0x5c, 0xdf, 0x16, 0x59, 0x30, 0x0b, 0x16, 0x03, 0xee, 0x63, 0xf6, 0x1c,
+	0x92, 0x92, 0xb4, 0x45, 0x6d, 0x6f, 0x8b, 0x2c, 0xf4, 0xc0, 0xa9, 0xc0, 0x5e, 0x41, 0x6c, 0xa8,
+	0xc2, 0x2c, 0x9a, 0x05, 0x8b, 0xcb, 0xe5, 0xe3, 0x5c, 0xc9, 0x2a, 0xef, 0xd8, 0x72, 0x4e, 0x15,
+	0x72, 0x0f, 0x3b, 0xe2, 0x86, 0x8c, 0xcd, 0xe2, 0x3d, 0xb1, 0x8b, 0xd9, 0x35, 0x8c, 0xef, 0xa8,
+	0xb5, 0x5a, 0xd4, 0x98, 0x0d, 0x66, 0xc1, 0x22, 0xe1, 0xc7, 0x9c, 0x65, 0x30, 0x92, 0xc2, 0x8a,
+	0x42, 0x99, 0x6c, 0xe8, 0xa1, 0x43, 0x3a, 0x7f, 0x03, 0xb1, 0xe3, 0x65, 0x29, 0x4c, 0x77, 0x7a,
+	0xab, 0xe9, 0xa7, 0xfe, 0xee, 0x5e, 0x48, 0x2f, 0xd8, 0x04, 0x46, 0x8d, 0x51, 0xb5, 0x30, 0x0f,
+	0x69, 0xc0, 0x00, 0x86, 0xb5, 0x32, 0x86, 0x4c, 0x1a, 0xce, 0x7f, 0x05, 0x30, 0xfa, 0x58, 0xed,
+	0x5a, 0x8b, 0x86, 0xbd, 0x87, 0x89, 0xc4, 0xd6, 0x2a, 0x2d, 0xac, 0x22, 0xed, 0x07, 0xbd, 0x5c,
+	0x5e, 0x79, 0xd9, 0x5d, 0x4b, 0x71, 0x82, 0x79, 0xbf, 0x97, 0x3d, 0x83, 0xe1, 0xa6, 0xf9, 0x44,
+	0x35, 0x7a, 0x17, 0x12, 0xde, 0x65, 0x4e, 0xeb, 0x3d, 0x9a, 0xd6, 0xd1, 0x45, 0x7b, 0xad, 0x5d,
+	0xca, 0x72, 0x98, 0x94, 0x44, 0x46, 0x3a, 0x06, 0x32, 0x7e, 0xf8, 0xc9, 0x72, 0xda, 0xf7, 0x88,
+	0xf7, 0x1b, 0xe6, 0xbf, 0x43, 0x48, 0x3e, 0x6c, 0x9c, 0xb1, 0xfa, 0x07, 0xf5, 0x79, 0x83, 0x73,
+	0xde, 0xbe, 0x73, 0xe1, 0x3f, 0xce, 0xbd, 0x84, 0x47, 0xbb, 0xc6, 0xaa, 0x1a, 0x57, 0x58, 0x92,
+	0x96, 0xad, 0xd7, 0x14, 0xf0, 0xf3, 0xa2, 0x63, 0x68, 0xad, 0xb0, 0x58, 0xa8, 0xbd, 0xac, 0x84,
+	0x1f, 0x73, 0xf6, 0x02, 0x60, 0xad, 0xb4, 0x30, 0x0f, 0x5f, 0x84, 0xbd, 0xeb, 0x36, 0xd3, 0xab,
+	0xb0, 0x77, 0x90, 0x48, 0xd5, 0x6e, 0x57, 0x8d, 0x28, 0x31, 0x1b, 0xce, 0xa2, 0xc5, 0x64, 0x99,
+	0xf9, 0x99, 0x8e, 0xd2, 0xf3, 0xe2, 0x80, 0xf3, 0x53, 0xeb, 0xf5, 0x57, 0x48, 0x8e, 0x75, 0x96,
+	0x42, 0x24, 0x95, 0xe9, 0x06, 0x73, 0xa1, 0xbb, 0x33, 0x71, 0x2f, 0x54, 0x25, 0xd6, 0xd5, 0x7e,
+	0xaa, 0x98, 0x9f, 0x0a, 0xec, 0x29, 0x0c, 0xd0, 0xad, 0xb3, 0xb3, 0x78, 0x9f, 0xdc, 0xbc, 0x86,
+	0xf8, 0x33, 0xc9, 0xb3, 0x63, 0xa8, 0x49, 0xba, 0x63, 0x18, 0x43, 0x5c, 0x52, 0xe3, 0x2e, 0x61,
+	0x0c, 0x71, 0xa5, 0xf4, 0x36, 0x0d, 0x6f, 0xbe, 0x01, 0xfb, 0x7f, 0xc7, 0xec, 0x0a, 0x9e, 0x1c,
+	0xbe, 0xed, 0x6d, 0x3b, 0xbd, 0x70, 0x27, 0xd4, 0xd2, 0xce, 0x94, 0x98, 0x06, 0xee, 0x01, 0xa5,
+	0x2d, 0x9a, 0x1a, 0xa5, 0x12, 0x16, 0xd3, 0xd0, 0xa1, 0x56, 0x98, 0x0d, 0xda, 0x34, 0x5a, 0x0f,
+	0xfd, 0xef, 0xf4, 0xf6, 0xef, 0x00, 0x62, 0x5c, 0xe7, 0x31, 0x5e, 0x03, 0x00, 0x00,
 }
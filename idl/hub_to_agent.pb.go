@@ -10,6 +10,7 @@ import (
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	math "math"
 )
 
@@ -321,6 +322,7 @@ func (m *TablespaceInfo) GetUserDefined() bool {
 type UpgradePrimariesRequest struct {
 	Action               PgOptions_Action `protobuf:"varint,1,opt,name=action,proto3,enum=idl.PgOptions_Action" json:"action,omitempty"`
 	Opts                 []*PgOptions     `protobuf:"bytes,2,rep,name=opts,proto3" json:"opts,omitempty"`
+	MaxConcurrency       uint32           `protobuf:"varint,3,opt,name=maxConcurrency,proto3" json:"maxConcurrency,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
 	XXX_unrecognized     []byte           `json:"-"`
 	XXX_sizecache        int32            `json:"-"`
@@ -365,10 +367,22 @@ func (m *UpgradePrimariesRequest) GetOpts() []*PgOptions {
 	return nil
 }
 
+func (m *UpgradePrimariesRequest) GetMaxConcurrency() uint32 {
+	if m != nil {
+		return m.MaxConcurrency
+	}
+	return 0
+}
+
 type UpgradePrimariesReply struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	// Types that are valid to be assigned to Contents:
+	//
+	//	*UpgradePrimariesReply_Progress_
+	//	*UpgradePrimariesReply_Result_
+	Contents             isUpgradePrimariesReply_Contents `protobuf_oneof:"contents"`
+	XXX_NoUnkeyedLiteral struct{}                         `json:"-"`
+	XXX_unrecognized     []byte                           `json:"-"`
+	XXX_sizecache        int32                            `json:"-"`
 }
 
 func (m *UpgradePrimariesReply) Reset()         { *m = UpgradePrimariesReply{} }
@@ -396,6 +410,155 @@ func (m *UpgradePrimariesReply) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_UpgradePrimariesReply proto.InternalMessageInfo
 
+type isUpgradePrimariesReply_Contents interface {
+	isUpgradePrimariesReply_Contents()
+}
+
+type UpgradePrimariesReply_Progress_ struct {
+	Progress *UpgradePrimariesReply_Progress `protobuf:"bytes,1,opt,name=progress,proto3,oneof"`
+}
+
+type UpgradePrimariesReply_Result_ struct {
+	Result *UpgradePrimariesReply_Result `protobuf:"bytes,2,opt,name=result,proto3,oneof"`
+}
+
+func (*UpgradePrimariesReply_Progress_) isUpgradePrimariesReply_Contents() {}
+
+func (*UpgradePrimariesReply_Result_) isUpgradePrimariesReply_Contents() {}
+
+func (m *UpgradePrimariesReply) GetContents() isUpgradePrimariesReply_Contents {
+	if m != nil {
+		return m.Contents
+	}
+	return nil
+}
+
+func (m *UpgradePrimariesReply) GetProgress() *UpgradePrimariesReply_Progress {
+	if x, ok := m.GetContents().(*UpgradePrimariesReply_Progress_); ok {
+		return x.Progress
+	}
+	return nil
+}
+
+func (m *UpgradePrimariesReply) GetResult() *UpgradePrimariesReply_Result {
+	if x, ok := m.GetContents().(*UpgradePrimariesReply_Result_); ok {
+		return x.Result
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*UpgradePrimariesReply) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*UpgradePrimariesReply_Progress_)(nil),
+		(*UpgradePrimariesReply_Result_)(nil),
+	}
+}
+
+// Progress is a line of pg_upgrade output for a segment.
+type UpgradePrimariesReply_Progress struct {
+	ContentID            int32    `protobuf:"varint,1,opt,name=contentID,proto3" json:"contentID,omitempty"`
+	Line                 string   `protobuf:"bytes,2,opt,name=line,proto3" json:"line,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpgradePrimariesReply_Progress) Reset()         { *m = UpgradePrimariesReply_Progress{} }
+func (m *UpgradePrimariesReply_Progress) String() string { return proto.CompactTextString(m) }
+func (*UpgradePrimariesReply_Progress) ProtoMessage()    {}
+func (*UpgradePrimariesReply_Progress) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{3, 0}
+}
+
+func (m *UpgradePrimariesReply_Progress) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpgradePrimariesReply_Progress.Unmarshal(m, b)
+}
+func (m *UpgradePrimariesReply_Progress) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpgradePrimariesReply_Progress.Marshal(b, m, deterministic)
+}
+func (m *UpgradePrimariesReply_Progress) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpgradePrimariesReply_Progress.Merge(m, src)
+}
+func (m *UpgradePrimariesReply_Progress) XXX_Size() int {
+	return xxx_messageInfo_UpgradePrimariesReply_Progress.Size(m)
+}
+func (m *UpgradePrimariesReply_Progress) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpgradePrimariesReply_Progress.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpgradePrimariesReply_Progress proto.InternalMessageInfo
+
+func (m *UpgradePrimariesReply_Progress) GetContentID() int32 {
+	if m != nil {
+		return m.ContentID
+	}
+	return 0
+}
+
+func (m *UpgradePrimariesReply_Progress) GetLine() string {
+	if m != nil {
+		return m.Line
+	}
+	return ""
+}
+
+// Result is sent once pg_upgrade finishes for a segment.
+type UpgradePrimariesReply_Result struct {
+	ContentID            int32    `protobuf:"varint,1,opt,name=contentID,proto3" json:"contentID,omitempty"`
+	DurationSeconds      float64  `protobuf:"fixed64,2,opt,name=durationSeconds,proto3" json:"durationSeconds,omitempty"`
+	Error                string   `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpgradePrimariesReply_Result) Reset()         { *m = UpgradePrimariesReply_Result{} }
+func (m *UpgradePrimariesReply_Result) String() string { return proto.CompactTextString(m) }
+func (*UpgradePrimariesReply_Result) ProtoMessage()    {}
+func (*UpgradePrimariesReply_Result) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{3, 1}
+}
+
+func (m *UpgradePrimariesReply_Result) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpgradePrimariesReply_Result.Unmarshal(m, b)
+}
+func (m *UpgradePrimariesReply_Result) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpgradePrimariesReply_Result.Marshal(b, m, deterministic)
+}
+func (m *UpgradePrimariesReply_Result) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpgradePrimariesReply_Result.Merge(m, src)
+}
+func (m *UpgradePrimariesReply_Result) XXX_Size() int {
+	return xxx_messageInfo_UpgradePrimariesReply_Result.Size(m)
+}
+func (m *UpgradePrimariesReply_Result) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpgradePrimariesReply_Result.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpgradePrimariesReply_Result proto.InternalMessageInfo
+
+func (m *UpgradePrimariesReply_Result) GetContentID() int32 {
+	if m != nil {
+		return m.ContentID
+	}
+	return 0
+}
+
+func (m *UpgradePrimariesReply_Result) GetDurationSeconds() float64 {
+	if m != nil {
+		return m.DurationSeconds
+	}
+	return 0
+}
+
+func (m *UpgradePrimariesReply_Result) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
 type CreateBackupDirectoryRequest struct {
 	BackupDir            string   `protobuf:"bytes,1,opt,name=backupDir,proto3" json:"backupDir,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -925,6 +1088,45 @@ func (m *RenameDirectoriesReply) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_RenameDirectoriesReply proto.InternalMessageInfo
 
+type AlreadyRenamedDirectoriesReply struct {
+	Renamed              bool     `protobuf:"varint,1,opt,name=renamed,proto3" json:"renamed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AlreadyRenamedDirectoriesReply) Reset()         { *m = AlreadyRenamedDirectoriesReply{} }
+func (m *AlreadyRenamedDirectoriesReply) String() string { return proto.CompactTextString(m) }
+func (*AlreadyRenamedDirectoriesReply) ProtoMessage()    {}
+func (*AlreadyRenamedDirectoriesReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{19}
+}
+
+func (m *AlreadyRenamedDirectoriesReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AlreadyRenamedDirectoriesReply.Unmarshal(m, b)
+}
+func (m *AlreadyRenamedDirectoriesReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AlreadyRenamedDirectoriesReply.Marshal(b, m, deterministic)
+}
+func (m *AlreadyRenamedDirectoriesReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AlreadyRenamedDirectoriesReply.Merge(m, src)
+}
+func (m *AlreadyRenamedDirectoriesReply) XXX_Size() int {
+	return xxx_messageInfo_AlreadyRenamedDirectoriesReply.Size(m)
+}
+func (m *AlreadyRenamedDirectoriesReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_AlreadyRenamedDirectoriesReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AlreadyRenamedDirectoriesReply proto.InternalMessageInfo
+
+func (m *AlreadyRenamedDirectoriesReply) GetRenamed() bool {
+	if m != nil {
+		return m.Renamed
+	}
+	return false
+}
+
 type StopAgentRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -935,7 +1137,7 @@ func (m *StopAgentRequest) Reset()         { *m = StopAgentRequest{} }
 func (m *StopAgentRequest) String() string { return proto.CompactTextString(m) }
 func (*StopAgentRequest) ProtoMessage()    {}
 func (*StopAgentRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{19}
+	return fileDescriptor_9e73bb06acc917d8, []int{20}
 }
 
 func (m *StopAgentRequest) XXX_Unmarshal(b []byte) error {
@@ -966,7 +1168,7 @@ func (m *StopAgentReply) Reset()         { *m = StopAgentReply{} }
 func (m *StopAgentReply) String() string { return proto.CompactTextString(m) }
 func (*StopAgentReply) ProtoMessage()    {}
 func (*StopAgentReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{20}
+	return fileDescriptor_9e73bb06acc917d8, []int{21}
 }
 
 func (m *StopAgentReply) XXX_Unmarshal(b []byte) error {
@@ -988,18 +1190,20 @@ func (m *StopAgentReply) XXX_DiscardUnknown() {
 var xxx_messageInfo_StopAgentReply proto.InternalMessageInfo
 
 type CheckSegmentDiskSpaceRequest struct {
-	DiskFreeRatio        float64  `protobuf:"fixed64,1,opt,name=diskFreeRatio,proto3" json:"diskFreeRatio,omitempty"`
-	Dirs                 []string `protobuf:"bytes,2,rep,name=dirs,proto3" json:"dirs,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	DiskFreeRatio float64  `protobuf:"fixed64,1,opt,name=diskFreeRatio,proto3" json:"diskFreeRatio,omitempty"`
+	Dirs          []string `protobuf:"bytes,2,rep,name=dirs,proto3" json:"dirs,omitempty"`
+	// requirements, when set, are checked rather than the diskFreeRatio.
+	Requirements         []*DiskSpaceRequirement `protobuf:"bytes,3,rep,name=requirements,proto3" json:"requirements,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
 }
 
 func (m *CheckSegmentDiskSpaceRequest) Reset()         { *m = CheckSegmentDiskSpaceRequest{} }
 func (m *CheckSegmentDiskSpaceRequest) String() string { return proto.CompactTextString(m) }
 func (*CheckSegmentDiskSpaceRequest) ProtoMessage()    {}
 func (*CheckSegmentDiskSpaceRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{21}
+	return fileDescriptor_9e73bb06acc917d8, []int{22}
 }
 
 func (m *CheckSegmentDiskSpaceRequest) XXX_Unmarshal(b []byte) error {
@@ -1034,6 +1238,71 @@ func (m *CheckSegmentDiskSpaceRequest) GetDirs() []string {
 	return nil
 }
 
+func (m *CheckSegmentDiskSpaceRequest) GetRequirements() []*DiskSpaceRequirement {
+	if m != nil {
+		return m.Requirements
+	}
+	return nil
+}
+
+// DiskSpaceRequirement is the space in KB required on the filesystem of dir.
+// When measure is set the size of dir excluding its WAL and log directories is
+// required in addition.
+type DiskSpaceRequirement struct {
+	Dir                  string   `protobuf:"bytes,1,opt,name=dir,proto3" json:"dir,omitempty"`
+	Required             uint64   `protobuf:"varint,2,opt,name=required,proto3" json:"required,omitempty"`
+	Measure              bool     `protobuf:"varint,3,opt,name=measure,proto3" json:"measure,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DiskSpaceRequirement) Reset()         { *m = DiskSpaceRequirement{} }
+func (m *DiskSpaceRequirement) String() string { return proto.CompactTextString(m) }
+func (*DiskSpaceRequirement) ProtoMessage()    {}
+func (*DiskSpaceRequirement) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{23}
+}
+
+func (m *DiskSpaceRequirement) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiskSpaceRequirement.Unmarshal(m, b)
+}
+func (m *DiskSpaceRequirement) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiskSpaceRequirement.Marshal(b, m, deterministic)
+}
+func (m *DiskSpaceRequirement) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiskSpaceRequirement.Merge(m, src)
+}
+func (m *DiskSpaceRequirement) XXX_Size() int {
+	return xxx_messageInfo_DiskSpaceRequirement.Size(m)
+}
+func (m *DiskSpaceRequirement) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiskSpaceRequirement.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiskSpaceRequirement proto.InternalMessageInfo
+
+func (m *DiskSpaceRequirement) GetDir() string {
+	if m != nil {
+		return m.Dir
+	}
+	return ""
+}
+
+func (m *DiskSpaceRequirement) GetRequired() uint64 {
+	if m != nil {
+		return m.Required
+	}
+	return 0
+}
+
+func (m *DiskSpaceRequirement) GetMeasure() bool {
+	if m != nil {
+		return m.Measure
+	}
+	return false
+}
+
 type CheckDiskSpaceReply struct {
 	Usage                []*CheckDiskSpaceReply_DiskUsage `protobuf:"bytes,1,rep,name=usage,proto3" json:"usage,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                         `json:"-"`
@@ -1045,7 +1314,7 @@ func (m *CheckDiskSpaceReply) Reset()         { *m = CheckDiskSpaceReply{} }
 func (m *CheckDiskSpaceReply) String() string { return proto.CompactTextString(m) }
 func (*CheckDiskSpaceReply) ProtoMessage()    {}
 func (*CheckDiskSpaceReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{22}
+	return fileDescriptor_9e73bb06acc917d8, []int{24}
 }
 
 func (m *CheckDiskSpaceReply) XXX_Unmarshal(b []byte) error {
@@ -1087,7 +1356,7 @@ func (m *CheckDiskSpaceReply_DiskUsage) Reset()         { *m = CheckDiskSpaceRep
 func (m *CheckDiskSpaceReply_DiskUsage) String() string { return proto.CompactTextString(m) }
 func (*CheckDiskSpaceReply_DiskUsage) ProtoMessage()    {}
 func (*CheckDiskSpaceReply_DiskUsage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{22, 0}
+	return fileDescriptor_9e73bb06acc917d8, []int{24, 0}
 }
 
 func (m *CheckDiskSpaceReply_DiskUsage) XXX_Unmarshal(b []byte) error {
@@ -1136,6 +1405,131 @@ func (m *CheckDiskSpaceReply_DiskUsage) GetRequired() uint64 {
 	return 0
 }
 
+type CheckPortsRequest struct {
+	Ports                []int32  `protobuf:"varint,1,rep,packed,name=ports,proto3" json:"ports,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckPortsRequest) Reset()         { *m = CheckPortsRequest{} }
+func (m *CheckPortsRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckPortsRequest) ProtoMessage()    {}
+func (*CheckPortsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{25}
+}
+
+func (m *CheckPortsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckPortsRequest.Unmarshal(m, b)
+}
+func (m *CheckPortsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckPortsRequest.Marshal(b, m, deterministic)
+}
+func (m *CheckPortsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckPortsRequest.Merge(m, src)
+}
+func (m *CheckPortsRequest) XXX_Size() int {
+	return xxx_messageInfo_CheckPortsRequest.Size(m)
+}
+func (m *CheckPortsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckPortsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckPortsRequest proto.InternalMessageInfo
+
+func (m *CheckPortsRequest) GetPorts() []int32 {
+	if m != nil {
+		return m.Ports
+	}
+	return nil
+}
+
+type CheckPortsReply struct {
+	Unavailable          []*CheckPortsReply_UnavailablePort `protobuf:"bytes,1,rep,name=unavailable,proto3" json:"unavailable,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                           `json:"-"`
+	XXX_unrecognized     []byte                             `json:"-"`
+	XXX_sizecache        int32                              `json:"-"`
+}
+
+func (m *CheckPortsReply) Reset()         { *m = CheckPortsReply{} }
+func (m *CheckPortsReply) String() string { return proto.CompactTextString(m) }
+func (*CheckPortsReply) ProtoMessage()    {}
+func (*CheckPortsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{26}
+}
+
+func (m *CheckPortsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckPortsReply.Unmarshal(m, b)
+}
+func (m *CheckPortsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckPortsReply.Marshal(b, m, deterministic)
+}
+func (m *CheckPortsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckPortsReply.Merge(m, src)
+}
+func (m *CheckPortsReply) XXX_Size() int {
+	return xxx_messageInfo_CheckPortsReply.Size(m)
+}
+func (m *CheckPortsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckPortsReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckPortsReply proto.InternalMessageInfo
+
+func (m *CheckPortsReply) GetUnavailable() []*CheckPortsReply_UnavailablePort {
+	if m != nil {
+		return m.Unavailable
+	}
+	return nil
+}
+
+type CheckPortsReply_UnavailablePort struct {
+	Port                 int32    `protobuf:"varint,1,opt,name=port,proto3" json:"port,omitempty"`
+	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckPortsReply_UnavailablePort) Reset()         { *m = CheckPortsReply_UnavailablePort{} }
+func (m *CheckPortsReply_UnavailablePort) String() string { return proto.CompactTextString(m) }
+func (*CheckPortsReply_UnavailablePort) ProtoMessage()    {}
+func (*CheckPortsReply_UnavailablePort) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{26, 0}
+}
+
+func (m *CheckPortsReply_UnavailablePort) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckPortsReply_UnavailablePort.Unmarshal(m, b)
+}
+func (m *CheckPortsReply_UnavailablePort) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckPortsReply_UnavailablePort.Marshal(b, m, deterministic)
+}
+func (m *CheckPortsReply_UnavailablePort) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckPortsReply_UnavailablePort.Merge(m, src)
+}
+func (m *CheckPortsReply_UnavailablePort) XXX_Size() int {
+	return xxx_messageInfo_CheckPortsReply_UnavailablePort.Size(m)
+}
+func (m *CheckPortsReply_UnavailablePort) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckPortsReply_UnavailablePort.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckPortsReply_UnavailablePort proto.InternalMessageInfo
+
+func (m *CheckPortsReply_UnavailablePort) GetPort() int32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *CheckPortsReply_UnavailablePort) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
 type RsyncRequest struct {
 	Options              []*RsyncRequest_RsyncOptions `protobuf:"bytes,1,rep,name=options,proto3" json:"options,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                     `json:"-"`
@@ -1147,7 +1541,7 @@ func (m *RsyncRequest) Reset()         { *m = RsyncRequest{} }
 func (m *RsyncRequest) String() string { return proto.CompactTextString(m) }
 func (*RsyncRequest) ProtoMessage()    {}
 func (*RsyncRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{23}
+	return fileDescriptor_9e73bb06acc917d8, []int{27}
 }
 
 func (m *RsyncRequest) XXX_Unmarshal(b []byte) error {
@@ -1190,7 +1584,7 @@ func (m *RsyncRequest_RsyncOptions) Reset()         { *m = RsyncRequest_RsyncOpt
 func (m *RsyncRequest_RsyncOptions) String() string { return proto.CompactTextString(m) }
 func (*RsyncRequest_RsyncOptions) ProtoMessage()    {}
 func (*RsyncRequest_RsyncOptions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{23, 0}
+	return fileDescriptor_9e73bb06acc917d8, []int{27, 0}
 }
 
 func (m *RsyncRequest_RsyncOptions) XXX_Unmarshal(b []byte) error {
@@ -1246,17 +1640,65 @@ func (m *RsyncRequest_RsyncOptions) GetExcludedFiles() []string {
 	return nil
 }
 
-type RsyncReply struct {
+type RsyncStats struct {
+	TransferredBytes     uint64   `protobuf:"varint,1,opt,name=transferredBytes,proto3" json:"transferredBytes,omitempty"`
+	DurationSeconds      float64  `protobuf:"fixed64,2,opt,name=durationSeconds,proto3" json:"durationSeconds,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
+func (m *RsyncStats) Reset()         { *m = RsyncStats{} }
+func (m *RsyncStats) String() string { return proto.CompactTextString(m) }
+func (*RsyncStats) ProtoMessage()    {}
+func (*RsyncStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{28}
+}
+
+func (m *RsyncStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RsyncStats.Unmarshal(m, b)
+}
+func (m *RsyncStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RsyncStats.Marshal(b, m, deterministic)
+}
+func (m *RsyncStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RsyncStats.Merge(m, src)
+}
+func (m *RsyncStats) XXX_Size() int {
+	return xxx_messageInfo_RsyncStats.Size(m)
+}
+func (m *RsyncStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_RsyncStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RsyncStats proto.InternalMessageInfo
+
+func (m *RsyncStats) GetTransferredBytes() uint64 {
+	if m != nil {
+		return m.TransferredBytes
+	}
+	return 0
+}
+
+func (m *RsyncStats) GetDurationSeconds() float64 {
+	if m != nil {
+		return m.DurationSeconds
+	}
+	return 0
+}
+
+type RsyncReply struct {
+	Stats                []*RsyncStats `protobuf:"bytes,1,rep,name=stats,proto3" json:"stats,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
 func (m *RsyncReply) Reset()         { *m = RsyncReply{} }
 func (m *RsyncReply) String() string { return proto.CompactTextString(m) }
 func (*RsyncReply) ProtoMessage()    {}
 func (*RsyncReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{24}
+	return fileDescriptor_9e73bb06acc917d8, []int{29}
 }
 
 func (m *RsyncReply) XXX_Unmarshal(b []byte) error {
@@ -1277,6 +1719,13 @@ func (m *RsyncReply) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_RsyncReply proto.InternalMessageInfo
 
+func (m *RsyncReply) GetStats() []*RsyncStats {
+	if m != nil {
+		return m.Stats
+	}
+	return nil
+}
+
 type RestorePgControlRequest struct {
 	Datadirs             []string `protobuf:"bytes,1,rep,name=datadirs,proto3" json:"datadirs,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -1288,7 +1737,7 @@ func (m *RestorePgControlRequest) Reset()         { *m = RestorePgControlRequest
 func (m *RestorePgControlRequest) String() string { return proto.CompactTextString(m) }
 func (*RestorePgControlRequest) ProtoMessage()    {}
 func (*RestorePgControlRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{25}
+	return fileDescriptor_9e73bb06acc917d8, []int{30}
 }
 
 func (m *RestorePgControlRequest) XXX_Unmarshal(b []byte) error {
@@ -1326,7 +1775,7 @@ func (m *RestorePgControlReply) Reset()         { *m = RestorePgControlReply{} }
 func (m *RestorePgControlReply) String() string { return proto.CompactTextString(m) }
 func (*RestorePgControlReply) ProtoMessage()    {}
 func (*RestorePgControlReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{26}
+	return fileDescriptor_9e73bb06acc917d8, []int{31}
 }
 
 func (m *RestorePgControlReply) XXX_Unmarshal(b []byte) error {
@@ -1360,7 +1809,7 @@ func (m *UpdateFileConfOptions) Reset()         { *m = UpdateFileConfOptions{} }
 func (m *UpdateFileConfOptions) String() string { return proto.CompactTextString(m) }
 func (*UpdateFileConfOptions) ProtoMessage()    {}
 func (*UpdateFileConfOptions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{27}
+	return fileDescriptor_9e73bb06acc917d8, []int{32}
 }
 
 func (m *UpdateFileConfOptions) XXX_Unmarshal(b []byte) error {
@@ -1413,7 +1862,7 @@ func (m *UpdateConfigurationRequest) Reset()         { *m = UpdateConfigurationR
 func (m *UpdateConfigurationRequest) String() string { return proto.CompactTextString(m) }
 func (*UpdateConfigurationRequest) ProtoMessage()    {}
 func (*UpdateConfigurationRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{28}
+	return fileDescriptor_9e73bb06acc917d8, []int{33}
 }
 
 func (m *UpdateConfigurationRequest) XXX_Unmarshal(b []byte) error {
@@ -1451,7 +1900,7 @@ func (m *UpdateConfigurationReply) Reset()         { *m = UpdateConfigurationRep
 func (m *UpdateConfigurationReply) String() string { return proto.CompactTextString(m) }
 func (*UpdateConfigurationReply) ProtoMessage()    {}
 func (*UpdateConfigurationReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{29}
+	return fileDescriptor_9e73bb06acc917d8, []int{34}
 }
 
 func (m *UpdateConfigurationReply) XXX_Unmarshal(b []byte) error {
@@ -1483,7 +1932,7 @@ func (m *RenameTablespacesRequest) Reset()         { *m = RenameTablespacesReque
 func (m *RenameTablespacesRequest) String() string { return proto.CompactTextString(m) }
 func (*RenameTablespacesRequest) ProtoMessage()    {}
 func (*RenameTablespacesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{30}
+	return fileDescriptor_9e73bb06acc917d8, []int{35}
 }
 
 func (m *RenameTablespacesRequest) XXX_Unmarshal(b []byte) error {
@@ -1523,7 +1972,7 @@ func (m *RenameTablespacesRequest_RenamePair) Reset()         { *m = RenameTable
 func (m *RenameTablespacesRequest_RenamePair) String() string { return proto.CompactTextString(m) }
 func (*RenameTablespacesRequest_RenamePair) ProtoMessage()    {}
 func (*RenameTablespacesRequest_RenamePair) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{30, 0}
+	return fileDescriptor_9e73bb06acc917d8, []int{35, 0}
 }
 
 func (m *RenameTablespacesRequest_RenamePair) XXX_Unmarshal(b []byte) error {
@@ -1568,7 +2017,7 @@ func (m *RenameTablespacesReply) Reset()         { *m = RenameTablespacesReply{}
 func (m *RenameTablespacesReply) String() string { return proto.CompactTextString(m) }
 func (*RenameTablespacesReply) ProtoMessage()    {}
 func (*RenameTablespacesReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{31}
+	return fileDescriptor_9e73bb06acc917d8, []int{36}
 }
 
 func (m *RenameTablespacesReply) XXX_Unmarshal(b []byte) error {
@@ -1600,7 +2049,7 @@ func (m *CreateRecoveryConfRequest) Reset()         { *m = CreateRecoveryConfReq
 func (m *CreateRecoveryConfRequest) String() string { return proto.CompactTextString(m) }
 func (*CreateRecoveryConfRequest) ProtoMessage()    {}
 func (*CreateRecoveryConfRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{32}
+	return fileDescriptor_9e73bb06acc917d8, []int{37}
 }
 
 func (m *CreateRecoveryConfRequest) XXX_Unmarshal(b []byte) error {
@@ -1642,7 +2091,7 @@ func (m *CreateRecoveryConfRequest_Connection) Reset()         { *m = CreateReco
 func (m *CreateRecoveryConfRequest_Connection) String() string { return proto.CompactTextString(m) }
 func (*CreateRecoveryConfRequest_Connection) ProtoMessage()    {}
 func (*CreateRecoveryConfRequest_Connection) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{32, 0}
+	return fileDescriptor_9e73bb06acc917d8, []int{37, 0}
 }
 
 func (m *CreateRecoveryConfRequest_Connection) XXX_Unmarshal(b []byte) error {
@@ -1701,7 +2150,7 @@ func (m *CreateRecoveryConfReply) Reset()         { *m = CreateRecoveryConfReply
 func (m *CreateRecoveryConfReply) String() string { return proto.CompactTextString(m) }
 func (*CreateRecoveryConfReply) ProtoMessage()    {}
 func (*CreateRecoveryConfReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{33}
+	return fileDescriptor_9e73bb06acc917d8, []int{38}
 }
 
 func (m *CreateRecoveryConfReply) XXX_Unmarshal(b []byte) error {
@@ -1733,7 +2182,7 @@ func (m *AddReplicationEntriesRequest) Reset()         { *m = AddReplicationEntr
 func (m *AddReplicationEntriesRequest) String() string { return proto.CompactTextString(m) }
 func (*AddReplicationEntriesRequest) ProtoMessage()    {}
 func (*AddReplicationEntriesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{34}
+	return fileDescriptor_9e73bb06acc917d8, []int{39}
 }
 
 func (m *AddReplicationEntriesRequest) XXX_Unmarshal(b []byte) error {
@@ -1774,7 +2223,7 @@ func (m *AddReplicationEntriesRequest_Entry) Reset()         { *m = AddReplicati
 func (m *AddReplicationEntriesRequest_Entry) String() string { return proto.CompactTextString(m) }
 func (*AddReplicationEntriesRequest_Entry) ProtoMessage()    {}
 func (*AddReplicationEntriesRequest_Entry) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{34, 0}
+	return fileDescriptor_9e73bb06acc917d8, []int{39, 0}
 }
 
 func (m *AddReplicationEntriesRequest_Entry) XXX_Unmarshal(b []byte) error {
@@ -1826,7 +2275,7 @@ func (m *AddReplicationEntriesReply) Reset()         { *m = AddReplicationEntrie
 func (m *AddReplicationEntriesReply) String() string { return proto.CompactTextString(m) }
 func (*AddReplicationEntriesReply) ProtoMessage()    {}
 func (*AddReplicationEntriesReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{35}
+	return fileDescriptor_9e73bb06acc917d8, []int{40}
 }
 
 func (m *AddReplicationEntriesReply) XXX_Unmarshal(b []byte) error {
@@ -1847,6 +2296,156 @@ func (m *AddReplicationEntriesReply) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_AddReplicationEntriesReply proto.InternalMessageInfo
 
+type GetInfoRequest struct {
+	DataDirs             []string `protobuf:"bytes,1,rep,name=dataDirs,proto3" json:"dataDirs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetInfoRequest) Reset()         { *m = GetInfoRequest{} }
+func (m *GetInfoRequest) String() string { return proto.CompactTextString(m) }
+func (*GetInfoRequest) ProtoMessage()    {}
+func (*GetInfoRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{41}
+}
+
+func (m *GetInfoRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetInfoRequest.Unmarshal(m, b)
+}
+func (m *GetInfoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetInfoRequest.Marshal(b, m, deterministic)
+}
+func (m *GetInfoRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetInfoRequest.Merge(m, src)
+}
+func (m *GetInfoRequest) XXX_Size() int {
+	return xxx_messageInfo_GetInfoRequest.Size(m)
+}
+func (m *GetInfoRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetInfoRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetInfoRequest proto.InternalMessageInfo
+
+func (m *GetInfoRequest) GetDataDirs() []string {
+	if m != nil {
+		return m.DataDirs
+	}
+	return nil
+}
+
+type GetInfoReply struct {
+	Info                 *AgentInfo `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *GetInfoReply) Reset()         { *m = GetInfoReply{} }
+func (m *GetInfoReply) String() string { return proto.CompactTextString(m) }
+func (*GetInfoReply) ProtoMessage()    {}
+func (*GetInfoReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{42}
+}
+
+func (m *GetInfoReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetInfoReply.Unmarshal(m, b)
+}
+func (m *GetInfoReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetInfoReply.Marshal(b, m, deterministic)
+}
+func (m *GetInfoReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetInfoReply.Merge(m, src)
+}
+func (m *GetInfoReply) XXX_Size() int {
+	return xxx_messageInfo_GetInfoReply.Size(m)
+}
+func (m *GetInfoReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetInfoReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetInfoReply proto.InternalMessageInfo
+
+func (m *GetInfoReply) GetInfo() *AgentInfo {
+	if m != nil {
+		return m.Info
+	}
+	return nil
+}
+
+// HeartbeatRequest asks the agent to send a heartbeat every interval until
+// the stream is closed.
+type HeartbeatRequest struct {
+	Interval             *durationpb.Duration `protobuf:"bytes,1,opt,name=interval,proto3" json:"interval,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *HeartbeatRequest) Reset()         { *m = HeartbeatRequest{} }
+func (m *HeartbeatRequest) String() string { return proto.CompactTextString(m) }
+func (*HeartbeatRequest) ProtoMessage()    {}
+func (*HeartbeatRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{43}
+}
+
+func (m *HeartbeatRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HeartbeatRequest.Unmarshal(m, b)
+}
+func (m *HeartbeatRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HeartbeatRequest.Marshal(b, m, deterministic)
+}
+func (m *HeartbeatRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HeartbeatRequest.Merge(m, src)
+}
+func (m *HeartbeatRequest) XXX_Size() int {
+	return xxx_messageInfo_HeartbeatRequest.Size(m)
+}
+func (m *HeartbeatRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_HeartbeatRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HeartbeatRequest proto.InternalMessageInfo
+
+func (m *HeartbeatRequest) GetInterval() *durationpb.Duration {
+	if m != nil {
+		return m.Interval
+	}
+	return nil
+}
+
+type HeartbeatReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HeartbeatReply) Reset()         { *m = HeartbeatReply{} }
+func (m *HeartbeatReply) String() string { return proto.CompactTextString(m) }
+func (*HeartbeatReply) ProtoMessage()    {}
+func (*HeartbeatReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{44}
+}
+
+func (m *HeartbeatReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HeartbeatReply.Unmarshal(m, b)
+}
+func (m *HeartbeatReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HeartbeatReply.Marshal(b, m, deterministic)
+}
+func (m *HeartbeatReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HeartbeatReply.Merge(m, src)
+}
+func (m *HeartbeatReply) XXX_Size() int {
+	return xxx_messageInfo_HeartbeatReply.Size(m)
+}
+func (m *HeartbeatReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_HeartbeatReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HeartbeatReply proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterEnum("idl.PgOptions_PgUpgradeMode", PgOptions_PgUpgradeMode_name, PgOptions_PgUpgradeMode_value)
 	proto.RegisterEnum("idl.PgOptions_Action", PgOptions_Action_name, PgOptions_Action_value)
@@ -1855,6 +2454,8 @@ func init() {
 	proto.RegisterType((*TablespaceInfo)(nil), "idl.TablespaceInfo")
 	proto.RegisterType((*UpgradePrimariesRequest)(nil), "idl.UpgradePrimariesRequest")
 	proto.RegisterType((*UpgradePrimariesReply)(nil), "idl.UpgradePrimariesReply")
+	proto.RegisterType((*UpgradePrimariesReply_Progress)(nil), "idl.UpgradePrimariesReply.Progress")
+	proto.RegisterType((*UpgradePrimariesReply_Result)(nil), "idl.UpgradePrimariesReply.Result")
 	proto.RegisterType((*CreateBackupDirectoryRequest)(nil), "idl.CreateBackupDirectoryRequest")
 	proto.RegisterType((*CreateBackupDirectoryReply)(nil), "idl.CreateBackupDirectoryReply")
 	proto.RegisterType((*DeleteDataDirectoriesRequest)(nil), "idl.DeleteDataDirectoriesRequest")
@@ -1870,13 +2471,19 @@ func init() {
 	proto.RegisterType((*RenameDirectories)(nil), "idl.RenameDirectories")
 	proto.RegisterType((*RenameDirectoriesRequest)(nil), "idl.RenameDirectoriesRequest")
 	proto.RegisterType((*RenameDirectoriesReply)(nil), "idl.RenameDirectoriesReply")
+	proto.RegisterType((*AlreadyRenamedDirectoriesReply)(nil), "idl.AlreadyRenamedDirectoriesReply")
 	proto.RegisterType((*StopAgentRequest)(nil), "idl.StopAgentRequest")
 	proto.RegisterType((*StopAgentReply)(nil), "idl.StopAgentReply")
 	proto.RegisterType((*CheckSegmentDiskSpaceRequest)(nil), "idl.CheckSegmentDiskSpaceRequest")
+	proto.RegisterType((*DiskSpaceRequirement)(nil), "idl.DiskSpaceRequirement")
 	proto.RegisterType((*CheckDiskSpaceReply)(nil), "idl.CheckDiskSpaceReply")
 	proto.RegisterType((*CheckDiskSpaceReply_DiskUsage)(nil), "idl.CheckDiskSpaceReply.DiskUsage")
+	proto.RegisterType((*CheckPortsRequest)(nil), "idl.CheckPortsRequest")
+	proto.RegisterType((*CheckPortsReply)(nil), "idl.CheckPortsReply")
+	proto.RegisterType((*CheckPortsReply_UnavailablePort)(nil), "idl.CheckPortsReply.UnavailablePort")
 	proto.RegisterType((*RsyncRequest)(nil), "idl.RsyncRequest")
 	proto.RegisterType((*RsyncRequest_RsyncOptions)(nil), "idl.RsyncRequest.RsyncOptions")
+	proto.RegisterType((*RsyncStats)(nil), "idl.RsyncStats")
 	proto.RegisterType((*RsyncReply)(nil), "idl.RsyncReply")
 	proto.RegisterType((*RestorePgControlRequest)(nil), "idl.RestorePgControlRequest")
 	proto.RegisterType((*RestorePgControlReply)(nil), "idl.RestorePgControlReply")
@@ -1892,115 +2499,149 @@ func init() {
 	proto.RegisterType((*AddReplicationEntriesRequest)(nil), "idl.AddReplicationEntriesRequest")
 	proto.RegisterType((*AddReplicationEntriesRequest_Entry)(nil), "idl.AddReplicationEntriesRequest.Entry")
 	proto.RegisterType((*AddReplicationEntriesReply)(nil), "idl.AddReplicationEntriesReply")
+	proto.RegisterType((*GetInfoRequest)(nil), "idl.GetInfoRequest")
+	proto.RegisterType((*GetInfoReply)(nil), "idl.GetInfoReply")
+	proto.RegisterType((*HeartbeatRequest)(nil), "idl.HeartbeatRequest")
+	proto.RegisterType((*HeartbeatReply)(nil), "idl.HeartbeatReply")
 }
 
 func init() { proto.RegisterFile("hub_to_agent.proto", fileDescriptor_9e73bb06acc917d8) }
 
 var fileDescriptor_9e73bb06acc917d8 = []byte{
-	// 1645 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x18, 0xdb, 0x6e, 0xdb, 0x46,
-	0xd6, 0x94, 0x25, 0xcb, 0x3a, 0xb2, 0x15, 0x65, 0x1c, 0xc7, 0x34, 0x23, 0x3b, 0x5a, 0x22, 0xc0,
-	0x3a, 0x01, 0x62, 0x2c, 0xbc, 0x59, 0x20, 0x1b, 0xe4, 0x45, 0xb6, 0xd6, 0xbb, 0xc9, 0xa6, 0x89,
-	0x4b, 0xe7, 0xd2, 0x16, 0x08, 0x02, 0x9a, 0x1c, 0xcb, 0x84, 0x69, 0x0e, 0x43, 0x52, 0x76, 0xf5,
-	0x0b, 0xfd, 0x89, 0xfe, 0x41, 0x1f, 0x8a, 0xa2, 0x0f, 0xfd, 0xaf, 0x3e, 0xb7, 0x38, 0x73, 0xa1,
-	0x86, 0x12, 0x29, 0x04, 0x7d, 0xe3, 0x9c, 0xfb, 0xfd, 0x1c, 0x09, 0xc8, 0xc5, 0xf8, 0xec, 0x53,
-	0xc6, 0x3e, 0xb9, 0x23, 0x1a, 0x65, 0xfb, 0x71, 0xc2, 0x32, 0x46, 0x96, 0x03, 0x3f, 0xb4, 0xd6,
-	0x3c, 0x76, 0x75, 0xc5, 0x22, 0x01, 0xb2, 0x7f, 0x6e, 0x42, 0xeb, 0x64, 0xf4, 0x26, 0xce, 0x02,
-	0x16, 0xa5, 0xa4, 0x07, 0xad, 0x33, 0xd7, 0xbb, 0x1c, 0xc7, 0xc3, 0x20, 0x31, 0x8d, 0xbe, 0xb1,
-	0xd7, 0x72, 0xa6, 0x00, 0xf2, 0x08, 0xba, 0xf1, 0xe8, 0x5d, 0x3c, 0x4a, 0x5c, 0x9f, 0xbe, 0xa7,
-	0xc9, 0x19, 0x4b, 0xa9, 0x59, 0xeb, 0x1b, 0x7b, 0xab, 0xce, 0x1c, 0x9c, 0xfc, 0x03, 0x36, 0xd2,
-	0xcb, 0x20, 0x3e, 0x51, 0xf0, 0xa3, 0x0b, 0xea, 0x5d, 0xa6, 0xe6, 0x32, 0x27, 0x2f, 0x43, 0x91,
-	0x07, 0xb0, 0x9e, 0x4b, 0x79, 0xc9, 0xce, 0x52, 0xb3, 0xce, 0xf5, 0x17, 0x81, 0xe4, 0x31, 0xac,
-	0xb8, 0x1e, 0x1a, 0x6b, 0x36, 0xfa, 0xc6, 0x5e, 0xe7, 0x60, 0x73, 0x3f, 0xf0, 0xc3, 0xfd, 0xdc,
-	0x83, 0xfd, 0x01, 0x47, 0x3a, 0x92, 0x88, 0x10, 0xa8, 0x27, 0x2c, 0xa4, 0xe6, 0x0a, 0x97, 0xc5,
-	0xbf, 0xd1, 0x49, 0x8f, 0x45, 0x19, 0x8d, 0xb2, 0x17, 0x43, 0xb3, 0xd9, 0x37, 0xf6, 0x1a, 0xce,
-	0x14, 0x40, 0x0e, 0x35, 0x33, 0xbe, 0x62, 0x3e, 0x35, 0x57, 0xb9, 0x9e, 0xde, 0x8c, 0x9e, 0x13,
-	0x9d, 0xc6, 0x29, 0xb2, 0x90, 0x5d, 0x00, 0x16, 0xfa, 0x92, 0xd4, 0x6c, 0x71, 0xdd, 0x1a, 0x84,
-	0xec, 0x40, 0xfd, 0x0a, 0x45, 0x03, 0x17, 0xdd, 0xe2, 0xa2, 0xb9, 0x1c, 0x0e, 0xc6, 0x48, 0x64,
-	0x6e, 0x32, 0xa2, 0xd9, 0x7b, 0x9a, 0xa4, 0xe8, 0x6a, 0x5b, 0x44, 0xa2, 0x00, 0x44, 0x37, 0x58,
-	0xe8, 0x1f, 0x06, 0x11, 0xe6, 0x6a, 0x4d, 0xe4, 0x2a, 0x07, 0x48, 0x13, 0x86, 0x6e, 0xe6, 0x22,
-	0x7a, 0x3d, 0x37, 0x41, 0x42, 0x88, 0x09, 0x4d, 0x16, 0xfa, 0x27, 0x2c, 0xc9, 0xcc, 0x0e, 0x47,
-	0xaa, 0xa7, 0xc4, 0x0c, 0x0f, 0x5f, 0x0c, 0xcd, 0x5b, 0x39, 0x06, 0x9f, 0xa8, 0x31, 0xa2, 0x37,
-	0x52, 0x63, 0x57, 0x68, 0xcc, 0x01, 0xa8, 0x31, 0xa2, 0x37, 0x4a, 0xe3, 0x6d, 0xa1, 0x71, 0x0a,
-	0x41, 0xb9, 0x11, 0xbd, 0xe1, 0x1a, 0x89, 0x90, 0x2b, 0x9f, 0x12, 0xc3, 0x35, 0x6e, 0xe4, 0x18,
-	0xae, 0x71, 0x00, 0xed, 0xb7, 0xee, 0x59, 0x48, 0xd3, 0xd8, 0xf5, 0x68, 0x6a, 0xde, 0xe9, 0x2f,
-	0xef, 0xb5, 0x0f, 0xee, 0xcf, 0xa4, 0x42, 0xa3, 0xf8, 0x4f, 0x94, 0x25, 0x13, 0x47, 0xe7, 0xb1,
-	0x4e, 0xa1, 0x3b, 0x4b, 0x40, 0xba, 0xb0, 0x7c, 0x49, 0x27, 0xbc, 0xc0, 0x1b, 0x0e, 0x7e, 0x92,
-	0x87, 0xd0, 0xb8, 0x76, 0xc3, 0xb1, 0xa8, 0xe7, 0xf6, 0xc1, 0x06, 0x57, 0x31, 0xe5, 0x7b, 0x11,
-	0x9d, 0x33, 0x47, 0x50, 0x3c, 0xab, 0x3d, 0x35, 0xec, 0xff, 0xc2, 0x7a, 0xa1, 0x00, 0xc8, 0x36,
-	0x6c, 0x8e, 0xa3, 0xcb, 0x88, 0xdd, 0x44, 0x9f, 0x0a, 0xa5, 0xd0, 0x5d, 0x22, 0x1d, 0x00, 0x3f,
-	0x48, 0x63, 0x37, 0xf3, 0x2e, 0x68, 0xd2, 0x35, 0x48, 0x1b, 0x9a, 0x29, 0x1d, 0x5d, 0xd1, 0x28,
-	0xeb, 0xd6, 0xec, 0x27, 0xb0, 0x32, 0x50, 0x95, 0xda, 0x51, 0x12, 0x44, 0xed, 0x76, 0x97, 0x90,
-	0x74, 0x2c, 0x64, 0x75, 0x0d, 0xd2, 0x82, 0x86, 0x87, 0x9d, 0xd2, 0xad, 0xd9, 0xaf, 0xa1, 0x53,
-	0xb4, 0x8d, 0x58, 0xb0, 0xfa, 0x8a, 0x79, 0x2e, 0x6f, 0x0c, 0xd1, 0xb7, 0xf9, 0x9b, 0xf4, 0xa1,
-	0xfd, 0x2e, 0xa5, 0xc9, 0x90, 0x9e, 0x07, 0x11, 0xf5, 0x65, 0xc7, 0xea, 0x20, 0x3b, 0x84, 0x2d,
-	0x69, 0xf3, 0x49, 0x12, 0x5c, 0xb9, 0x49, 0x40, 0x53, 0x87, 0x7e, 0x1e, 0xd3, 0x34, 0xd3, 0xfa,
-	0xcd, 0xf8, 0x92, 0x7e, 0xb3, 0xa1, 0xce, 0xe2, 0x2c, 0x35, 0x6b, 0x3c, 0x53, 0x9d, 0x22, 0xb1,
-	0xc3, 0x71, 0xf6, 0x16, 0x6c, 0xce, 0x6b, 0x8b, 0xc3, 0x89, 0xfd, 0x1c, 0x7a, 0x47, 0x09, 0x75,
-	0x33, 0x7a, 0xa8, 0x46, 0x0e, 0xf5, 0x32, 0x96, 0x4c, 0x94, 0x2d, 0x0b, 0xa7, 0x93, 0xdd, 0x03,
-	0xab, 0x82, 0x1b, 0x65, 0x3f, 0x83, 0xde, 0x90, 0x86, 0x34, 0xa3, 0xb2, 0x1c, 0x39, 0x4e, 0xf3,
-	0xd3, 0x82, 0x55, 0xdf, 0xcd, 0x5c, 0x3f, 0x48, 0x52, 0xd3, 0xe8, 0x2f, 0x63, 0x00, 0xd5, 0x1b,
-	0x25, 0x57, 0xf0, 0xa2, 0xe4, 0x1d, 0xb8, 0x27, 0xb0, 0xa7, 0x99, 0x9b, 0xd1, 0x59, 0xa3, 0xed,
-	0x7b, 0xb0, 0x5d, 0x8e, 0x96, 0x1e, 0x0b, 0xe4, 0x5f, 0xf5, 0xb8, 0x82, 0x1b, 0x65, 0x3f, 0x86,
-	0x2d, 0x81, 0x9d, 0x96, 0x8a, 0x12, 0x4b, 0xa0, 0xae, 0x39, 0xca, 0xbf, 0x31, 0x2b, 0xf3, 0xe4,
-	0x28, 0xe7, 0x10, 0xac, 0x41, 0xe2, 0x5d, 0x04, 0xd7, 0xf4, 0x15, 0x1b, 0xcd, 0x59, 0xf8, 0x00,
-	0xd6, 0x43, 0x36, 0x92, 0x04, 0x53, 0x2b, 0x8b, 0x40, 0xdb, 0x02, 0xb3, 0x54, 0x06, 0xca, 0x3f,
-	0x82, 0xdb, 0x0e, 0x8d, 0xdc, 0x2b, 0xaa, 0x45, 0x96, 0xdc, 0x85, 0x95, 0x53, 0x36, 0x4e, 0x3c,
-	0x2a, 0xe5, 0xc9, 0x17, 0xc2, 0xdf, 0xf2, 0x29, 0xc8, 0xcb, 0xb8, 0xe5, 0xc8, 0x97, 0x7d, 0x0c,
-	0xe6, 0x9c, 0x10, 0x65, 0xe2, 0x23, 0xa8, 0x0f, 0x95, 0xb7, 0xed, 0x83, 0xbb, 0xbc, 0x26, 0xe7,
-	0x89, 0x39, 0x8d, 0x6d, 0xc2, 0xdd, 0x12, 0x39, 0x68, 0x26, 0x81, 0xee, 0x69, 0xc6, 0xe2, 0x01,
-	0xae, 0x53, 0x95, 0xdb, 0x2e, 0x74, 0x34, 0x18, 0x52, 0x7d, 0x03, 0x3d, 0xbe, 0xce, 0x4e, 0x45,
-	0x87, 0x0f, 0x83, 0xf4, 0xf2, 0x54, 0x8f, 0xfc, 0x03, 0x58, 0xf7, 0x83, 0xf4, 0xf2, 0x38, 0xa1,
-	0xd4, 0xc1, 0xee, 0xe4, 0xee, 0x19, 0x4e, 0x11, 0x98, 0xe7, 0xa7, 0xa6, 0xe5, 0xe7, 0x37, 0x03,
-	0x36, 0xb8, 0x68, 0x4d, 0x66, 0x1c, 0x4e, 0xc8, 0x53, 0x68, 0x8c, 0x53, 0x77, 0x44, 0xa5, 0x7b,
-	0x36, 0x77, 0xaf, 0x84, 0x70, 0x1f, 0x9f, 0xef, 0x90, 0xd2, 0x11, 0x0c, 0x56, 0x00, 0xad, 0x1c,
-	0x46, 0x3a, 0x50, 0x3b, 0x4f, 0x65, 0xb0, 0x6b, 0xe7, 0x29, 0x9a, 0x70, 0xc1, 0x52, 0x15, 0x66,
-	0xfe, 0x8d, 0xd5, 0xe8, 0x5e, 0xbb, 0x41, 0x88, 0x05, 0xc2, 0x37, 0x79, 0xdd, 0x99, 0x02, 0xb0,
-	0x83, 0x12, 0xfa, 0x79, 0x1c, 0x24, 0xd4, 0xe7, 0xab, 0xbb, 0xee, 0xe4, 0x6f, 0xfb, 0x0f, 0x03,
-	0xd6, 0x9c, 0x74, 0x12, 0x79, 0x2a, 0x0e, 0x4f, 0xa1, 0xc9, 0xe4, 0x7a, 0x14, 0x76, 0xef, 0x8a,
-	0xb4, 0x68, 0x34, 0xe2, 0xa1, 0x46, 0x87, 0x22, 0xb7, 0x7e, 0x51, 0xa2, 0xd4, 0x32, 0x35, 0xa1,
-	0x99, 0xf2, 0xe2, 0x50, 0xf5, 0xac, 0x9e, 0x64, 0x0f, 0x6e, 0xf9, 0x34, 0xcd, 0x82, 0x88, 0xcf,
-	0xc1, 0xff, 0x4d, 0xdd, 0x99, 0x05, 0xe3, 0x88, 0xd4, 0x40, 0xdc, 0xb7, 0x96, 0xa3, 0x83, 0xf8,
-	0x56, 0x94, 0x06, 0xd7, 0x85, 0x16, 0xf9, 0xc4, 0x94, 0xd2, 0xef, 0xbd, 0x70, 0xec, 0x53, 0xff,
-	0x38, 0x08, 0x69, 0x6a, 0x36, 0x38, 0xbe, 0x08, 0xb4, 0xd7, 0x00, 0xa4, 0x73, 0x58, 0x26, 0xff,
-	0x82, 0x2d, 0x87, 0xa6, 0x19, 0x4b, 0xe8, 0xc9, 0xe8, 0x88, 0x45, 0x59, 0xc2, 0xc2, 0x2f, 0x19,
-	0x44, 0x5b, 0xb0, 0x39, 0xcf, 0x86, 0xf2, 0x46, 0x38, 0x52, 0x7d, 0x37, 0xa3, 0xa8, 0xec, 0x88,
-	0x45, 0xe7, 0x2a, 0x38, 0x04, 0xea, 0xb1, 0x9b, 0x5d, 0xc8, 0xc4, 0xf2, 0x6f, 0x74, 0x25, 0x76,
-	0xb3, 0x8c, 0x26, 0x91, 0x0c, 0x87, 0x7a, 0x62, 0x18, 0x12, 0x1a, 0x87, 0xae, 0x47, 0xb1, 0x78,
-	0x55, 0x18, 0x34, 0x90, 0xed, 0x80, 0x25, 0x14, 0xa1, 0x92, 0x60, 0x34, 0x4e, 0x78, 0x74, 0x94,
-	0xed, 0x4f, 0x66, 0xb3, 0x6a, 0xf1, 0xac, 0x96, 0x9a, 0x96, 0x07, 0x10, 0x87, 0x43, 0xa9, 0x4c,
-	0x74, 0xec, 0x27, 0x43, 0x35, 0xb6, 0xb6, 0xc4, 0x95, 0xba, 0x97, 0x68, 0x2e, 0xe2, 0x4e, 0xdc,
-	0x69, 0x7f, 0xef, 0x69, 0xfd, 0x3d, 0xcf, 0x23, 0x11, 0xc8, 0xe0, 0xe8, 0xcc, 0xd6, 0x31, 0xc0,
-	0x14, 0x85, 0x63, 0x26, 0x2d, 0x8c, 0x1f, 0xf1, 0x9a, 0xad, 0x93, 0xda, 0x5c, 0x9d, 0x4c, 0x07,
-	0x48, 0x41, 0x37, 0xba, 0xf2, 0xbb, 0x01, 0xdb, 0x62, 0x41, 0x39, 0xd4, 0x63, 0xd7, 0x34, 0x99,
-	0xa0, 0xbf, 0xca, 0x97, 0xff, 0x43, 0xdb, 0x63, 0x51, 0x44, 0x3d, 0x3d, 0x7c, 0x0f, 0x45, 0x33,
-	0x57, 0x31, 0xed, 0x1f, 0xe5, 0x1c, 0x8e, 0xce, 0x6d, 0xfd, 0x60, 0x00, 0x4c, 0x71, 0x58, 0xa1,
-	0x57, 0x41, 0x92, 0xb0, 0x44, 0x1d, 0x67, 0xc2, 0xee, 0x22, 0x10, 0x4b, 0x65, 0x9c, 0x52, 0x35,
-	0xc0, 0xf9, 0x37, 0xfa, 0x1b, 0xf3, 0x1d, 0x3d, 0xe1, 0xdd, 0x23, 0x0b, 0x42, 0x03, 0x69, 0x14,
-	0xfc, 0xb2, 0xab, 0xf3, 0x93, 0x4a, 0x07, 0xd9, 0xdb, 0xb0, 0x55, 0xe6, 0x01, 0x86, 0xe4, 0x57,
-	0x03, 0x7a, 0x03, 0xdf, 0xc7, 0x47, 0x20, 0x8e, 0x15, 0xbc, 0xcf, 0xb4, 0xd1, 0x3d, 0x80, 0x26,
-	0x15, 0x10, 0x19, 0x91, 0xbf, 0xf3, 0x88, 0x2c, 0xe2, 0xd9, 0x17, 0x37, 0xa0, 0xe2, 0xb3, 0x4e,
-	0xa1, 0x21, 0x8e, 0x3e, 0x13, 0x9a, 0xca, 0x7f, 0xe1, 0x62, 0x53, 0xf3, 0x1c, 0xaf, 0x21, 0x35,
-	0xeb, 0xf0, 0x1b, 0x67, 0x1d, 0xfa, 0x37, 0xf0, 0xfd, 0x04, 0x7f, 0xb5, 0x60, 0x1f, 0x4e, 0x01,
-	0xb8, 0x79, 0x2b, 0x6c, 0x88, 0xc3, 0xc9, 0xc1, 0x8f, 0x6d, 0x68, 0xf0, 0x9d, 0x40, 0x3e, 0xc2,
-	0x66, 0xe9, 0x4d, 0x42, 0xfe, 0xa6, 0x65, 0xb6, 0x7c, 0xf7, 0x5b, 0xf7, 0x17, 0x91, 0x60, 0xf4,
-	0x96, 0xc8, 0x1b, 0xe8, 0x14, 0x27, 0xbd, 0x92, 0xbb, 0x60, 0x05, 0x59, 0x66, 0xd5, 0x86, 0xb0,
-	0x97, 0xc8, 0x6b, 0xe8, 0xce, 0x9e, 0x66, 0xa4, 0x27, 0x7b, 0xb8, 0xf4, 0x3e, 0xb4, 0xac, 0x0a,
-	0xac, 0x90, 0xf7, 0x75, 0xd9, 0x6e, 0xdf, 0xa9, 0xd8, 0xc0, 0x52, 0xe2, 0xbd, 0x2a, 0xb4, 0x10,
-	0xf9, 0x6f, 0x68, 0xe5, 0x3b, 0x97, 0x88, 0x6b, 0x74, 0x76, 0x2f, 0x5b, 0x1b, 0xb3, 0x60, 0xc1,
-	0xfa, 0x51, 0x9d, 0x38, 0x33, 0x77, 0x9c, 0x8c, 0xda, 0xa2, 0xfb, 0x50, 0x66, 0x63, 0xc1, 0x19,
-	0xa8, 0x89, 0x2f, 0x4f, 0xf6, 0xa2, 0x43, 0xaf, 0x20, 0xbe, 0x22, 0xd9, 0xdf, 0xc1, 0x9d, 0xb2,
-	0x43, 0x92, 0xf4, 0x35, 0xd6, 0xd2, 0x13, 0xd4, 0xda, 0x5d, 0x40, 0x21, 0x64, 0x7f, 0xab, 0x6e,
-	0xd8, 0xe9, 0xd4, 0xd2, 0xe3, 0xd3, 0xd3, 0x04, 0xcc, 0x5d, 0x93, 0xb2, 0x04, 0xca, 0x8f, 0xc7,
-	0x25, 0xf2, 0x01, 0x36, 0x4a, 0x4e, 0x3f, 0x22, 0x1c, 0xae, 0x3e, 0x2c, 0xad, 0x9d, 0x6a, 0x02,
-	0x21, 0xf8, 0x39, 0xdc, 0xe1, 0x1b, 0x75, 0x36, 0x99, 0xb7, 0xe7, 0x2e, 0x09, 0xeb, 0x96, 0x0e,
-	0x12, 0xdc, 0x87, 0x60, 0xf1, 0x77, 0xb9, 0xc3, 0x5f, 0x26, 0xe3, 0x03, 0x6c, 0xab, 0x75, 0xac,
-	0x0a, 0x3f, 0xdf, 0xcb, 0x32, 0x66, 0x15, 0x5b, 0x5e, 0xc6, 0xac, 0x7c, 0x99, 0xf3, 0x98, 0x95,
-	0x6c, 0x44, 0x19, 0xb3, 0xea, 0xfd, 0x2b, 0x63, 0x56, 0xb9, 0x4c, 0xb5, 0x7e, 0xd4, 0xb6, 0x53,
-	0xa1, 0x1f, 0xe7, 0x37, 0x66, 0xa1, 0x1f, 0xe7, 0x96, 0xda, 0x12, 0x79, 0x0b, 0x64, 0x7e, 0xbc,
-	0x93, 0xdd, 0xc5, 0x9b, 0xcb, 0xea, 0x55, 0xe2, 0xf3, 0x5e, 0x2a, 0x1d, 0xb0, 0xb2, 0x97, 0x16,
-	0x2d, 0x00, 0xd9, 0x4b, 0xd5, 0xf3, 0xd9, 0x5e, 0x3a, 0x5b, 0xe1, 0x7f, 0x7e, 0xfd, 0xf3, 0xcf,
-	0x00, 0x00, 0x00, 0xff, 0xff, 0x9f, 0x01, 0x14, 0xae, 0x25, 0x13, 0x00, 0x00,
+	// 2115 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x18, 0xdb, 0x72, 0xdb, 0xc6,
+	0x55, 0xa0, 0x49, 0x49, 0x3c, 0x94, 0x28, 0x6a, 0x25, 0x59, 0x10, 0x4c, 0x5d, 0x82, 0xba, 0xad,
+	0x92, 0x69, 0x98, 0x8c, 0x9c, 0xcc, 0xb8, 0xb1, 0xfb, 0x40, 0x89, 0x55, 0xec, 0x34, 0x6d, 0x54,
+	0xc8, 0x4e, 0xa6, 0x9d, 0x49, 0x3d, 0x10, 0xb0, 0xa2, 0x30, 0x82, 0xb0, 0xcc, 0x02, 0x94, 0xc3,
+	0x5f, 0xe8, 0x73, 0xa7, 0xfd, 0x8b, 0x3e, 0x74, 0x32, 0x7d, 0xe8, 0x1f, 0xf4, 0x83, 0xfa, 0xdc,
+	0xce, 0xd9, 0x0b, 0x6e, 0x04, 0x18, 0x4f, 0xdf, 0xb0, 0xe7, 0x7e, 0x76, 0xcf, 0x15, 0x40, 0x6e,
+	0xa6, 0x57, 0x6f, 0x12, 0xf6, 0xc6, 0x1d, 0xd3, 0x28, 0x19, 0x4c, 0x38, 0x4b, 0x18, 0x79, 0x10,
+	0xf8, 0xa1, 0xb5, 0xe6, 0xb1, 0xbb, 0x3b, 0x16, 0x49, 0x90, 0x75, 0x30, 0x66, 0x6c, 0x1c, 0xd2,
+	0x8f, 0xc4, 0xe9, 0x6a, 0x7a, 0xfd, 0x91, 0x3f, 0xe5, 0x6e, 0x12, 0x68, 0xbc, 0xfd, 0x8f, 0x15,
+	0x68, 0x5f, 0x8c, 0xbf, 0x9a, 0x20, 0x28, 0x26, 0x7d, 0x68, 0x5f, 0xb9, 0xde, 0xed, 0x74, 0x32,
+	0x0a, 0xb8, 0x69, 0x1c, 0x19, 0xc7, 0x6d, 0x27, 0x03, 0x90, 0x0f, 0xa0, 0x37, 0x19, 0xbf, 0x9e,
+	0x8c, 0xb9, 0xeb, 0xd3, 0xaf, 0x29, 0xbf, 0x62, 0x31, 0x35, 0x1b, 0x47, 0xc6, 0xf1, 0xaa, 0x33,
+	0x07, 0x27, 0x1f, 0xc3, 0x56, 0x7c, 0x1b, 0x4c, 0x2e, 0x34, 0xfc, 0xec, 0x86, 0x7a, 0xb7, 0xb1,
+	0xf9, 0x40, 0x90, 0x57, 0xa1, 0xc8, 0x63, 0x58, 0x4f, 0xa5, 0x7c, 0xc1, 0xae, 0x62, 0xb3, 0x29,
+	0xf4, 0x17, 0x81, 0xe4, 0x43, 0x58, 0x76, 0x3d, 0x34, 0xd6, 0x6c, 0x1d, 0x19, 0xc7, 0xdd, 0x93,
+	0x9d, 0x41, 0xe0, 0x87, 0x83, 0xd4, 0x83, 0xc1, 0x50, 0x20, 0x1d, 0x45, 0x44, 0x08, 0x34, 0x39,
+	0x0b, 0xa9, 0xb9, 0x2c, 0x64, 0x89, 0x6f, 0x74, 0xd2, 0x63, 0x51, 0x42, 0xa3, 0xe4, 0xe5, 0xc8,
+	0x5c, 0x39, 0x32, 0x8e, 0x5b, 0x4e, 0x06, 0x20, 0xa7, 0x39, 0x33, 0x7e, 0xcb, 0x7c, 0x6a, 0xae,
+	0x0a, 0x3d, 0xfd, 0x92, 0x9e, 0x8b, 0x3c, 0x8d, 0x53, 0x64, 0x21, 0x07, 0x00, 0x2c, 0xf4, 0x15,
+	0xa9, 0xd9, 0x16, 0xba, 0x73, 0x10, 0xb2, 0x0f, 0xcd, 0x3b, 0x14, 0x0d, 0x42, 0x74, 0x5b, 0x88,
+	0x16, 0x72, 0x04, 0x18, 0x6f, 0x22, 0x71, 0xf9, 0x98, 0x26, 0x5f, 0x53, 0x1e, 0xa3, 0xab, 0x1d,
+	0x79, 0x13, 0x05, 0x20, 0xba, 0xc1, 0x42, 0xff, 0x34, 0x88, 0xf0, 0xad, 0xd6, 0xe4, 0x5b, 0xa5,
+	0x00, 0x65, 0xc2, 0xc8, 0x4d, 0x5c, 0x44, 0xaf, 0xa7, 0x26, 0x28, 0x08, 0x31, 0x61, 0x85, 0x85,
+	0xfe, 0x05, 0xe3, 0x89, 0xd9, 0x15, 0x48, 0x7d, 0x54, 0x98, 0xd1, 0xe9, 0xcb, 0x91, 0xb9, 0x91,
+	0x62, 0xf0, 0x88, 0x1a, 0x23, 0xfa, 0x56, 0x69, 0xec, 0x49, 0x8d, 0x29, 0x00, 0x35, 0x46, 0xf4,
+	0xad, 0xd6, 0xb8, 0x29, 0x35, 0x66, 0x10, 0x94, 0x1b, 0xd1, 0xb7, 0x42, 0x23, 0x91, 0x72, 0xd5,
+	0x51, 0x61, 0x84, 0xc6, 0xad, 0x14, 0x23, 0x34, 0x0e, 0xa1, 0xf3, 0xca, 0xbd, 0x0a, 0x69, 0x3c,
+	0x71, 0x3d, 0x1a, 0x9b, 0xdb, 0x47, 0x0f, 0x8e, 0x3b, 0x27, 0x87, 0xa5, 0xa7, 0xc8, 0x51, 0xfc,
+	0x3a, 0x4a, 0xf8, 0xcc, 0xc9, 0xf3, 0x58, 0x97, 0xd0, 0x2b, 0x13, 0x90, 0x1e, 0x3c, 0xb8, 0xa5,
+	0x33, 0x11, 0xe0, 0x2d, 0x07, 0x3f, 0xc9, 0xfb, 0xd0, 0xba, 0x77, 0xc3, 0xa9, 0x8c, 0xe7, 0xce,
+	0xc9, 0x96, 0x50, 0x91, 0xf1, 0xbd, 0x8c, 0xae, 0x99, 0x23, 0x29, 0x3e, 0x6b, 0x3c, 0x35, 0xec,
+	0xcf, 0x61, 0xbd, 0x10, 0x00, 0x64, 0x0f, 0x76, 0xa6, 0xd1, 0x6d, 0xc4, 0xde, 0x46, 0x6f, 0x0a,
+	0xa1, 0xd0, 0x5b, 0x22, 0x5d, 0x00, 0x3f, 0x88, 0x27, 0x6e, 0xe2, 0xdd, 0x50, 0xde, 0x33, 0x48,
+	0x07, 0x56, 0x62, 0x3a, 0xbe, 0xa3, 0x51, 0xd2, 0x6b, 0xd8, 0x9f, 0xc0, 0xf2, 0x50, 0x47, 0x6a,
+	0x57, 0x4b, 0x90, 0xb1, 0xdb, 0x5b, 0x42, 0xd2, 0xa9, 0x94, 0xd5, 0x33, 0x48, 0x1b, 0x5a, 0x1e,
+	0x66, 0x4a, 0xaf, 0x61, 0xff, 0x0e, 0xba, 0x45, 0xdb, 0x88, 0x05, 0xab, 0x5f, 0x32, 0x4f, 0x24,
+	0xb6, 0xca, 0xdb, 0xf4, 0x4c, 0x8e, 0xa0, 0xf3, 0x3a, 0xa6, 0x7c, 0x44, 0xaf, 0x83, 0x88, 0xfa,
+	0x2a, 0x63, 0xf3, 0x20, 0xfb, 0x2f, 0x06, 0xec, 0x2a, 0xa3, 0x2f, 0x78, 0x70, 0xe7, 0xf2, 0x80,
+	0xc6, 0x0e, 0xfd, 0x6e, 0x4a, 0xe3, 0x24, 0x97, 0x70, 0xc6, 0xbb, 0x24, 0x9c, 0x0d, 0x4d, 0x36,
+	0x49, 0x62, 0xb3, 0x21, 0x9e, 0xaa, 0x5b, 0x24, 0x76, 0x04, 0x8e, 0xfc, 0x0c, 0xba, 0x77, 0xee,
+	0xf7, 0x67, 0x2c, 0xf2, 0xa6, 0x9c, 0xd3, 0xc8, 0x9b, 0x89, 0xb2, 0xb0, 0xee, 0x94, 0xa0, 0xf6,
+	0xbf, 0x1b, 0xb0, 0x33, 0x6f, 0xd6, 0x24, 0x9c, 0x91, 0x21, 0xac, 0x4e, 0x38, 0x1b, 0x73, 0x1a,
+	0xc7, 0xc2, 0xac, 0xce, 0xc9, 0x4f, 0x84, 0xa6, 0x4a, 0xea, 0xc1, 0x85, 0x22, 0x7d, 0xb1, 0xe4,
+	0xa4, 0x6c, 0xe4, 0x19, 0x2c, 0x73, 0x1a, 0x4f, 0xc3, 0x44, 0x3d, 0xf9, 0x7b, 0x0b, 0x04, 0x38,
+	0x82, 0xf0, 0xc5, 0x92, 0xa3, 0x58, 0xac, 0xe7, 0xb0, 0xaa, 0x85, 0x16, 0xcb, 0x89, 0x51, 0x2e,
+	0x27, 0x04, 0x9a, 0x61, 0x10, 0xc9, 0xb8, 0x6a, 0x3b, 0xe2, 0xdb, 0xba, 0x86, 0x65, 0x29, 0xf1,
+	0x47, 0x78, 0x8f, 0x61, 0x43, 0x57, 0xeb, 0x4b, 0xea, 0xb1, 0xc8, 0x8f, 0x85, 0x18, 0xc3, 0x29,
+	0x83, 0xc9, 0x36, 0xb4, 0x28, 0xe7, 0x8c, 0x8b, 0x8b, 0x6c, 0x3b, 0xf2, 0x70, 0x0a, 0xb0, 0xaa,
+	0x84, 0xc5, 0xf6, 0x73, 0xe8, 0x9f, 0x71, 0xea, 0x26, 0xf4, 0x54, 0x97, 0x73, 0xea, 0x25, 0x8c,
+	0xcf, 0xf4, 0x33, 0x2f, 0xac, 0xfc, 0x76, 0x1f, 0xac, 0x1a, 0xee, 0x49, 0x38, 0xb3, 0x3f, 0x83,
+	0xfe, 0x88, 0x86, 0x34, 0xa1, 0x2a, 0xd5, 0x05, 0x2e, 0x17, 0x42, 0x16, 0xac, 0xfa, 0x6e, 0xe2,
+	0xfa, 0x01, 0xc7, 0xd7, 0x7a, 0x80, 0xc1, 0xa9, 0xcf, 0x28, 0xb9, 0x86, 0x17, 0x25, 0xef, 0xc3,
+	0x23, 0x89, 0xbd, 0x4c, 0xdc, 0x84, 0x96, 0x8d, 0xb6, 0x1f, 0xc1, 0x5e, 0x35, 0x1a, 0x79, 0x9f,
+	0x6b, 0xab, 0xfe, 0x5f, 0x8f, 0x6b, 0xb8, 0x51, 0xf6, 0x87, 0xb0, 0x2b, 0xb1, 0x59, 0x1a, 0x6a,
+	0xb1, 0x04, 0x9a, 0x39, 0x47, 0xc5, 0xb7, 0xbd, 0x0b, 0x3b, 0xf3, 0xe4, 0x28, 0xe7, 0x14, 0xac,
+	0x21, 0xf7, 0x6e, 0x82, 0x7b, 0xfa, 0x25, 0x1b, 0xcf, 0x59, 0xf8, 0x18, 0xd6, 0x43, 0x36, 0x56,
+	0x04, 0x99, 0x95, 0x45, 0xa0, 0x6d, 0x81, 0x59, 0x29, 0x03, 0xe5, 0x9f, 0xc1, 0xa6, 0x43, 0x23,
+	0xf7, 0x8e, 0xe6, 0x6e, 0x96, 0x3c, 0x84, 0xe5, 0x4b, 0x36, 0xe5, 0x1e, 0x55, 0xf2, 0xd4, 0x09,
+	0xe1, 0xaf, 0x44, 0x87, 0x51, 0xc1, 0xaa, 0x4e, 0xf6, 0x39, 0x98, 0x73, 0x42, 0xb4, 0x89, 0x1f,
+	0x40, 0x73, 0xa4, 0xbd, 0xed, 0x9c, 0x3c, 0x14, 0x39, 0x34, 0x4f, 0x2c, 0x68, 0x6c, 0x13, 0x1e,
+	0xce, 0xa3, 0x54, 0x00, 0x1d, 0x0c, 0x43, 0x4e, 0x5d, 0x7f, 0x26, 0x09, 0xfc, 0x32, 0x05, 0xb6,
+	0x08, 0x2e, 0x51, 0xc2, 0xe8, 0x55, 0x47, 0x1f, 0x6d, 0x02, 0xbd, 0xcb, 0x84, 0x4d, 0x86, 0x38,
+	0x06, 0xe9, 0xb8, 0xe8, 0x41, 0x37, 0x07, 0x43, 0x0d, 0x7f, 0x33, 0xa0, 0x2f, 0xe6, 0x8c, 0x4b,
+	0x59, 0x7a, 0x47, 0x41, 0x7c, 0x7b, 0x99, 0x7f, 0xb6, 0xc7, 0xb0, 0xee, 0x07, 0xf1, 0xed, 0x39,
+	0xa7, 0xd4, 0xc1, 0xd4, 0x12, 0x6a, 0x0c, 0xa7, 0x08, 0x4c, 0x1f, 0xb7, 0x91, 0x3d, 0x2e, 0xf9,
+	0x15, 0xac, 0x71, 0xfa, 0xdd, 0x34, 0xe0, 0x14, 0x05, 0xe3, 0x88, 0x83, 0x57, 0xb1, 0x27, 0xae,
+	0xa2, 0xa0, 0x46, 0x51, 0x38, 0x05, 0x72, 0xfb, 0x4f, 0xb0, 0x5d, 0x45, 0x85, 0x3d, 0xca, 0x4f,
+	0x9f, 0x1c, 0x3f, 0x31, 0x8d, 0x14, 0xa7, 0x2c, 0xe2, 0x4d, 0x27, 0x3d, 0xe3, 0xfd, 0xdc, 0x51,
+	0x37, 0x9e, 0x72, 0xaa, 0x46, 0x2c, 0x7d, 0xb4, 0xff, 0x65, 0xc0, 0x96, 0xf0, 0x3c, 0xa7, 0x05,
+	0x6f, 0xf4, 0x29, 0xb4, 0xa6, 0xb1, 0x3b, 0xa6, 0xea, 0xe9, 0x6c, 0x61, 0x6f, 0x05, 0xa1, 0xf0,
+	0xe1, 0x35, 0x52, 0x3a, 0x92, 0xc1, 0x0a, 0xa0, 0x9d, 0xc2, 0x48, 0x17, 0x1a, 0xd7, 0xb1, 0xb2,
+	0xb2, 0x71, 0x1d, 0xe3, 0x0d, 0xdd, 0xb0, 0x58, 0x87, 0x90, 0xf8, 0xc6, 0x4c, 0x73, 0xef, 0xdd,
+	0x20, 0xc4, 0xe0, 0x17, 0xe6, 0x35, 0x9d, 0x0c, 0x50, 0x70, 0xab, 0x59, 0x74, 0xcb, 0x7e, 0x1f,
+	0x36, 0x85, 0x49, 0x38, 0x26, 0xa4, 0x31, 0xb7, 0x0d, 0xad, 0x09, 0x9e, 0x85, 0xe5, 0x2d, 0x47,
+	0x1e, 0xec, 0xbf, 0x1a, 0xb0, 0x91, 0xa7, 0x45, 0x1f, 0xcf, 0xa1, 0x33, 0x8d, 0x32, 0xd5, 0xd2,
+	0xd3, 0xc7, 0x99, 0xa7, 0x19, 0xe9, 0xe0, 0x75, 0x46, 0x87, 0x50, 0x27, 0xcf, 0x68, 0x3d, 0x83,
+	0x8d, 0x12, 0x1e, 0xfd, 0x44, 0xbd, 0xaa, 0x68, 0x8b, 0xef, 0xac, 0x0a, 0x37, 0x72, 0x55, 0xd8,
+	0xfe, 0xaf, 0x01, 0x6b, 0x4e, 0x3c, 0x8b, 0x3c, 0x6d, 0xff, 0x53, 0x58, 0x61, 0x6a, 0x34, 0x94,
+	0x16, 0x1d, 0xc8, 0xb4, 0xc9, 0xd1, 0xc8, 0x83, 0xee, 0x9a, 0x9a, 0xdc, 0xfa, 0x41, 0x8b, 0x52,
+	0x18, 0x7c, 0xf6, 0x58, 0x24, 0xaf, 0xae, 0x37, 0xfa, 0x28, 0x7a, 0x07, 0x8d, 0x93, 0x20, 0x12,
+	0x7d, 0xe2, 0x45, 0xf6, 0x24, 0x65, 0x30, 0x8e, 0x07, 0x39, 0x90, 0xea, 0x20, 0x79, 0x10, 0x6a,
+	0xd1, 0x06, 0x37, 0xa5, 0x16, 0x75, 0xc4, 0xac, 0xa1, 0xdf, 0x7b, 0xe1, 0xd4, 0xa7, 0xfe, 0x79,
+	0x10, 0xd2, 0xd8, 0x6c, 0x09, 0x7c, 0x11, 0x68, 0x5f, 0x01, 0x08, 0xab, 0xb1, 0x4a, 0xc7, 0xb8,
+	0x45, 0x24, 0xdc, 0x8d, 0xe2, 0x6b, 0xca, 0x39, 0xf5, 0x4f, 0x67, 0x09, 0x95, 0xf1, 0xd3, 0x74,
+	0xe6, 0xe0, 0xef, 0xde, 0x01, 0xed, 0x27, 0x4a, 0x87, 0x7c, 0xf8, 0x9f, 0x42, 0x2b, 0x46, 0x65,
+	0xea, 0x82, 0x37, 0xb2, 0x0b, 0x16, 0x36, 0x38, 0x12, 0x6b, 0x7f, 0x0a, 0xbb, 0x0e, 0x8d, 0x13,
+	0xc6, 0xe9, 0xc5, 0xf8, 0x8c, 0x45, 0x09, 0x67, 0xe1, 0xbb, 0xf4, 0xac, 0x5d, 0xd8, 0x99, 0x67,
+	0xc3, 0x2a, 0x33, 0xc6, 0x79, 0xc5, 0x77, 0x13, 0x8a, 0x7e, 0x9f, 0xb1, 0xe8, 0x5a, 0xbf, 0x13,
+	0x46, 0x8b, 0x9b, 0xdc, 0xa8, 0x3c, 0x11, 0xdf, 0x78, 0xab, 0x13, 0x37, 0x49, 0x28, 0x8f, 0xd4,
+	0xcb, 0xe8, 0x23, 0xbe, 0x08, 0xa7, 0x93, 0xd0, 0xf5, 0x44, 0x25, 0xd0, 0x2f, 0x92, 0x03, 0xd9,
+	0x0e, 0x58, 0x52, 0x11, 0x2a, 0x09, 0xc6, 0xea, 0x2e, 0xb4, 0xed, 0x9f, 0x94, 0x03, 0xcc, 0x52,
+	0xb3, 0x4d, 0x85, 0x69, 0xe9, 0x5b, 0x62, 0x1f, 0xa9, 0x94, 0x89, 0x8e, 0xfd, 0xdd, 0xd0, 0x3d,
+	0x20, 0x37, 0x4b, 0x6b, 0x75, 0x5f, 0xa0, 0xb9, 0x88, 0xbb, 0x70, 0xb3, 0x56, 0x70, 0x9c, 0x6b,
+	0x05, 0xf3, 0x3c, 0x03, 0x27, 0x65, 0x70, 0xf2, 0xcc, 0xd6, 0x39, 0x40, 0x86, 0xc2, 0x8e, 0x14,
+	0x17, 0x3a, 0x95, 0x3c, 0x95, 0x43, 0xb6, 0x31, 0x17, 0xb2, 0x59, 0xaf, 0x29, 0xe8, 0x46, 0x57,
+	0xfe, 0x63, 0xc0, 0x9e, 0x9c, 0x65, 0x1c, 0xea, 0xb1, 0x7b, 0xca, 0x67, 0xe8, 0xaf, 0xf6, 0xe5,
+	0x37, 0xd0, 0xf1, 0x58, 0x14, 0x51, 0x2f, 0x7f, 0x7d, 0xef, 0xcb, 0x8a, 0x51, 0xc7, 0x34, 0x38,
+	0x4b, 0x39, 0x9c, 0x3c, 0xb7, 0xf5, 0x67, 0x03, 0x20, 0xc3, 0x61, 0xb2, 0xdc, 0x05, 0x58, 0x12,
+	0xf4, 0x8e, 0x24, 0xed, 0x2e, 0x02, 0x31, 0x54, 0xa6, 0x31, 0xd5, 0x85, 0x5f, 0x7c, 0xa3, 0xbf,
+	0x13, 0x31, 0x91, 0xce, 0x44, 0x22, 0xab, 0x80, 0xc8, 0x81, 0x72, 0x14, 0x62, 0xc1, 0x6a, 0x8a,
+	0xaa, 0x94, 0x07, 0xd9, 0x7b, 0xb0, 0x5b, 0xe5, 0x01, 0x5e, 0xc9, 0x3f, 0x0d, 0xe8, 0x0f, 0x7d,
+	0x1f, 0x0f, 0x81, 0xdc, 0x19, 0x70, 0x4d, 0xca, 0x75, 0xf9, 0x21, 0xac, 0x50, 0x09, 0x51, 0x37,
+	0xf2, 0x73, 0x71, 0x23, 0x8b, 0x78, 0x06, 0x72, 0x15, 0xd3, 0x7c, 0xd6, 0x25, 0xb4, 0x04, 0x04,
+	0xc3, 0x5e, 0xfb, 0x2f, 0x5d, 0x5c, 0xc9, 0x79, 0x8e, 0x4b, 0x89, 0x6e, 0x1d, 0xf8, 0x8d, 0xad,
+	0x03, 0xfd, 0x1b, 0xfa, 0x3e, 0x97, 0x9d, 0xb5, 0xed, 0x64, 0x00, 0x1c, 0xd2, 0x6a, 0x6c, 0x40,
+	0xb7, 0x7e, 0x01, 0xdd, 0xcf, 0x69, 0x22, 0x56, 0xb7, 0x62, 0x52, 0x8f, 0x4a, 0x49, 0x8d, 0x67,
+	0xfb, 0x04, 0xd6, 0x52, 0x6a, 0x2c, 0x21, 0x36, 0x34, 0x83, 0xe8, 0x9a, 0xa9, 0xf5, 0x42, 0x2e,
+	0x32, 0x62, 0xa0, 0x10, 0x24, 0x02, 0x67, 0xbf, 0x84, 0xde, 0x0b, 0xea, 0xf2, 0xe4, 0x8a, 0xba,
+	0x7a, 0xf6, 0x20, 0x9f, 0xc2, 0x6a, 0x10, 0x25, 0x94, 0xdf, 0xbb, 0xa1, 0xe2, 0xdd, 0x1b, 0xc8,
+	0x7f, 0x30, 0x03, 0xfd, 0x0f, 0x66, 0x30, 0xd2, 0xc9, 0x95, 0x92, 0xe2, 0xc8, 0x92, 0x13, 0x35,
+	0x09, 0x67, 0x27, 0x3f, 0xac, 0x43, 0x4b, 0x28, 0x24, 0xdf, 0xc2, 0x4e, 0xe5, 0xf4, 0x4d, 0xde,
+	0xcb, 0x05, 0x66, 0xf5, 0x94, 0x6b, 0x1d, 0x2e, 0x22, 0xc1, 0x5b, 0x5a, 0x22, 0x5f, 0x41, 0xb7,
+	0xd8, 0xf7, 0xb5, 0xdc, 0x05, 0xf3, 0x92, 0x65, 0xd6, 0xcd, 0x0b, 0xf6, 0x12, 0x79, 0x0e, 0x90,
+	0xb5, 0x57, 0xf2, 0x70, 0xae, 0xdf, 0x4a, 0x09, 0xdb, 0x55, 0x7d, 0xd8, 0x5e, 0x22, 0x17, 0xd0,
+	0x2b, 0x6f, 0x61, 0xa4, 0x5f, 0xb3, 0x9c, 0x49, 0x49, 0x56, 0xfd, 0xea, 0x66, 0x2f, 0x7d, 0x6c,
+	0x90, 0xdf, 0x57, 0x4d, 0xc1, 0xfb, 0x35, 0xb3, 0xaa, 0x92, 0xf9, 0xa8, 0x0e, 0x2d, 0x8d, 0x74,
+	0x61, 0xaf, 0x76, 0x62, 0xfd, 0x31, 0xd1, 0x72, 0x55, 0x5d, 0x3c, 0xf0, 0xda, 0x4b, 0xe4, 0x97,
+	0xd0, 0x4e, 0x87, 0x58, 0x22, 0xb7, 0xee, 0xf2, 0xa0, 0x6b, 0x6d, 0x95, 0xc1, 0x92, 0xf5, 0x5b,
+	0xbd, 0x6f, 0x94, 0x96, 0x2a, 0xf5, 0xb0, 0x8b, 0x96, 0x35, 0xeb, 0x70, 0x11, 0x49, 0x49, 0x7c,
+	0x75, 0x3c, 0x2e, 0xda, 0xba, 0xac, 0xc3, 0x45, 0x24, 0x52, 0xfc, 0x1f, 0x61, 0xbb, 0x6a, 0xab,
+	0x23, 0x47, 0x39, 0xd6, 0xca, 0x7d, 0xd0, 0x3a, 0x58, 0x40, 0x21, 0x65, 0xff, 0x41, 0x2f, 0x94,
+	0x59, 0x5f, 0xc8, 0xdf, 0x4f, 0x3f, 0x27, 0x60, 0x6e, 0xb5, 0xb3, 0xac, 0x1a, 0xac, 0x14, 0xfd,
+	0x0d, 0x6c, 0x55, 0xec, 0x61, 0x44, 0x3a, 0x5c, 0xbf, 0xe5, 0x59, 0xfb, 0xf5, 0x04, 0x3a, 0x9d,
+	0xb6, 0xc5, 0xe8, 0x52, 0x7e, 0xcc, 0xcd, 0xb9, 0xb1, 0xd1, 0xda, 0xc8, 0x83, 0x24, 0xf7, 0x29,
+	0x58, 0xe2, 0x5c, 0xed, 0xf0, 0xbb, 0xc9, 0xf8, 0x06, 0xf6, 0xf4, 0xc0, 0xa3, 0xb3, 0x2b, 0x9d,
+	0x7c, 0xd4, 0x9d, 0xd5, 0xcc, 0x51, 0x96, 0x55, 0x83, 0x4d, 0xef, 0xac, 0x62, 0xe6, 0x50, 0x77,
+	0x56, 0x3f, 0xe1, 0x58, 0xfb, 0xf5, 0x04, 0x52, 0x70, 0x9a, 0xf2, 0x99, 0xdb, 0xc5, 0xbc, 0x9c,
+	0x9f, 0x49, 0xac, 0x47, 0x75, 0x68, 0x29, 0xf2, 0x15, 0x90, 0xf9, 0x06, 0x4a, 0x0e, 0x16, 0xcf,
+	0x06, 0x56, 0xbf, 0x16, 0x9f, 0xe6, 0x52, 0x65, 0x0b, 0x53, 0xb9, 0xb4, 0xa8, 0xc5, 0x5a, 0x87,
+	0x8b, 0x48, 0xa4, 0xf8, 0x27, 0xb0, 0xa2, 0xba, 0x1a, 0x91, 0xb5, 0xa2, 0xd8, 0x11, 0xad, 0xcd,
+	0x22, 0x50, 0x32, 0x3d, 0x83, 0x76, 0xda, 0x8b, 0x54, 0xe5, 0x29, 0xb7, 0x39, 0x6b, 0xab, 0x0c,
+	0x56, 0xc5, 0xf6, 0x6a, 0x59, 0x74, 0xb9, 0x27, 0xff, 0x1b, 0x00, 0xeb, 0xbb, 0xa3, 0x54, 0xa0,
+	0x18, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -2017,8 +2658,10 @@ const _ = grpc.SupportPackageIsVersion4
 type AgentClient interface {
 	CreateBackupDirectory(ctx context.Context, in *CreateBackupDirectoryRequest, opts ...grpc.CallOption) (*CreateBackupDirectoryReply, error)
 	CheckDiskSpace(ctx context.Context, in *CheckSegmentDiskSpaceRequest, opts ...grpc.CallOption) (*CheckDiskSpaceReply, error)
-	UpgradePrimaries(ctx context.Context, in *UpgradePrimariesRequest, opts ...grpc.CallOption) (*UpgradePrimariesReply, error)
+	CheckPorts(ctx context.Context, in *CheckPortsRequest, opts ...grpc.CallOption) (*CheckPortsReply, error)
+	UpgradePrimaries(ctx context.Context, in *UpgradePrimariesRequest, opts ...grpc.CallOption) (Agent_UpgradePrimariesClient, error)
 	RenameDirectories(ctx context.Context, in *RenameDirectoriesRequest, opts ...grpc.CallOption) (*RenameDirectoriesReply, error)
+	AlreadyRenamedDirectories(ctx context.Context, in *RenameDirectoriesRequest, opts ...grpc.CallOption) (*AlreadyRenamedDirectoriesReply, error)
 	StopAgent(ctx context.Context, in *StopAgentRequest, opts ...grpc.CallOption) (*StopAgentReply, error)
 	DeleteDataDirectories(ctx context.Context, in *DeleteDataDirectoriesRequest, opts ...grpc.CallOption) (*DeleteDataDirectoriesReply, error)
 	DeleteBackupDirectory(ctx context.Context, in *DeleteBackupDirectoryRequest, opts ...grpc.CallOption) (*DeleteBackupDirectoryReply, error)
@@ -2032,6 +2675,8 @@ type AgentClient interface {
 	RenameTablespaces(ctx context.Context, in *RenameTablespacesRequest, opts ...grpc.CallOption) (*RenameTablespacesReply, error)
 	CreateRecoveryConf(ctx context.Context, in *CreateRecoveryConfRequest, opts ...grpc.CallOption) (*CreateRecoveryConfReply, error)
 	AddReplicationEntries(ctx context.Context, in *AddReplicationEntriesRequest, opts ...grpc.CallOption) (*AddReplicationEntriesReply, error)
+	GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoReply, error)
+	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (Agent_HeartbeatClient, error)
 }
 
 type agentClient struct {
@@ -2060,15 +2705,47 @@ func (c *agentClient) CheckDiskSpace(ctx context.Context, in *CheckSegmentDiskSp
 	return out, nil
 }
 
-func (c *agentClient) UpgradePrimaries(ctx context.Context, in *UpgradePrimariesRequest, opts ...grpc.CallOption) (*UpgradePrimariesReply, error) {
-	out := new(UpgradePrimariesReply)
-	err := c.cc.Invoke(ctx, "/idl.Agent/UpgradePrimaries", in, out, opts...)
+func (c *agentClient) CheckPorts(ctx context.Context, in *CheckPortsRequest, opts ...grpc.CallOption) (*CheckPortsReply, error) {
+	out := new(CheckPortsReply)
+	err := c.cc.Invoke(ctx, "/idl.Agent/CheckPorts", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
+func (c *agentClient) UpgradePrimaries(ctx context.Context, in *UpgradePrimariesRequest, opts ...grpc.CallOption) (Agent_UpgradePrimariesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Agent_serviceDesc.Streams[0], "/idl.Agent/UpgradePrimaries", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &agentUpgradePrimariesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Agent_UpgradePrimariesClient interface {
+	Recv() (*UpgradePrimariesReply, error)
+	grpc.ClientStream
+}
+
+type agentUpgradePrimariesClient struct {
+	grpc.ClientStream
+}
+
+func (x *agentUpgradePrimariesClient) Recv() (*UpgradePrimariesReply, error) {
+	m := new(UpgradePrimariesReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *agentClient) RenameDirectories(ctx context.Context, in *RenameDirectoriesRequest, opts ...grpc.CallOption) (*RenameDirectoriesReply, error) {
 	out := new(RenameDirectoriesReply)
 	err := c.cc.Invoke(ctx, "/idl.Agent/RenameDirectories", in, out, opts...)
@@ -2078,6 +2755,15 @@ func (c *agentClient) RenameDirectories(ctx context.Context, in *RenameDirectori
 	return out, nil
 }
 
+func (c *agentClient) AlreadyRenamedDirectories(ctx context.Context, in *RenameDirectoriesRequest, opts ...grpc.CallOption) (*AlreadyRenamedDirectoriesReply, error) {
+	out := new(AlreadyRenamedDirectoriesReply)
+	err := c.cc.Invoke(ctx, "/idl.Agent/AlreadyRenamedDirectories", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *agentClient) StopAgent(ctx context.Context, in *StopAgentRequest, opts ...grpc.CallOption) (*StopAgentReply, error) {
 	out := new(StopAgentReply)
 	err := c.cc.Invoke(ctx, "/idl.Agent/StopAgent", in, out, opts...)
@@ -2195,12 +2881,55 @@ func (c *agentClient) AddReplicationEntries(ctx context.Context, in *AddReplicat
 	return out, nil
 }
 
+func (c *agentClient) GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoReply, error) {
+	out := new(GetInfoReply)
+	err := c.cc.Invoke(ctx, "/idl.Agent/GetInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (Agent_HeartbeatClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Agent_serviceDesc.Streams[1], "/idl.Agent/Heartbeat", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &agentHeartbeatClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Agent_HeartbeatClient interface {
+	Recv() (*HeartbeatReply, error)
+	grpc.ClientStream
+}
+
+type agentHeartbeatClient struct {
+	grpc.ClientStream
+}
+
+func (x *agentHeartbeatClient) Recv() (*HeartbeatReply, error) {
+	m := new(HeartbeatReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // AgentServer is the server API for Agent service.
 type AgentServer interface {
 	CreateBackupDirectory(context.Context, *CreateBackupDirectoryRequest) (*CreateBackupDirectoryReply, error)
 	CheckDiskSpace(context.Context, *CheckSegmentDiskSpaceRequest) (*CheckDiskSpaceReply, error)
-	UpgradePrimaries(context.Context, *UpgradePrimariesRequest) (*UpgradePrimariesReply, error)
+	CheckPorts(context.Context, *CheckPortsRequest) (*CheckPortsReply, error)
+	UpgradePrimaries(*UpgradePrimariesRequest, Agent_UpgradePrimariesServer) error
 	RenameDirectories(context.Context, *RenameDirectoriesRequest) (*RenameDirectoriesReply, error)
+	AlreadyRenamedDirectories(context.Context, *RenameDirectoriesRequest) (*AlreadyRenamedDirectoriesReply, error)
 	StopAgent(context.Context, *StopAgentRequest) (*StopAgentReply, error)
 	DeleteDataDirectories(context.Context, *DeleteDataDirectoriesRequest) (*DeleteDataDirectoriesReply, error)
 	DeleteBackupDirectory(context.Context, *DeleteBackupDirectoryRequest) (*DeleteBackupDirectoryReply, error)
@@ -2214,6 +2943,8 @@ type AgentServer interface {
 	RenameTablespaces(context.Context, *RenameTablespacesRequest) (*RenameTablespacesReply, error)
 	CreateRecoveryConf(context.Context, *CreateRecoveryConfRequest) (*CreateRecoveryConfReply, error)
 	AddReplicationEntries(context.Context, *AddReplicationEntriesRequest) (*AddReplicationEntriesReply, error)
+	GetInfo(context.Context, *GetInfoRequest) (*GetInfoReply, error)
+	Heartbeat(*HeartbeatRequest, Agent_HeartbeatServer) error
 }
 
 // UnimplementedAgentServer can be embedded to have forward compatible implementations.
@@ -2226,12 +2957,18 @@ func (*UnimplementedAgentServer) CreateBackupDirectory(ctx context.Context, req
 func (*UnimplementedAgentServer) CheckDiskSpace(ctx context.Context, req *CheckSegmentDiskSpaceRequest) (*CheckDiskSpaceReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CheckDiskSpace not implemented")
 }
-func (*UnimplementedAgentServer) UpgradePrimaries(ctx context.Context, req *UpgradePrimariesRequest) (*UpgradePrimariesReply, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UpgradePrimaries not implemented")
+func (*UnimplementedAgentServer) CheckPorts(ctx context.Context, req *CheckPortsRequest) (*CheckPortsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckPorts not implemented")
+}
+func (*UnimplementedAgentServer) UpgradePrimaries(req *UpgradePrimariesRequest, srv Agent_UpgradePrimariesServer) error {
+	return status.Errorf(codes.Unimplemented, "method UpgradePrimaries not implemented")
 }
 func (*UnimplementedAgentServer) RenameDirectories(ctx context.Context, req *RenameDirectoriesRequest) (*RenameDirectoriesReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RenameDirectories not implemented")
 }
+func (*UnimplementedAgentServer) AlreadyRenamedDirectories(ctx context.Context, req *RenameDirectoriesRequest) (*AlreadyRenamedDirectoriesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AlreadyRenamedDirectories not implemented")
+}
 func (*UnimplementedAgentServer) StopAgent(ctx context.Context, req *StopAgentRequest) (*StopAgentReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method StopAgent not implemented")
 }
@@ -2271,6 +3008,12 @@ func (*UnimplementedAgentServer) CreateRecoveryConf(ctx context.Context, req *Cr
 func (*UnimplementedAgentServer) AddReplicationEntries(ctx context.Context, req *AddReplicationEntriesRequest) (*AddReplicationEntriesReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddReplicationEntries not implemented")
 }
+func (*UnimplementedAgentServer) GetInfo(ctx context.Context, req *GetInfoRequest) (*GetInfoReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInfo not implemented")
+}
+func (*UnimplementedAgentServer) Heartbeat(req *HeartbeatRequest, srv Agent_HeartbeatServer) error {
+	return status.Errorf(codes.Unimplemented, "method Heartbeat not implemented")
+}
 
 func RegisterAgentServer(s *grpc.Server, srv AgentServer) {
 	s.RegisterService(&_Agent_serviceDesc, srv)
@@ -2312,24 +3055,45 @@ func _Agent_CheckDiskSpace_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Agent_UpgradePrimaries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpgradePrimariesRequest)
+func _Agent_CheckPorts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckPortsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AgentServer).UpgradePrimaries(ctx, in)
+		return srv.(AgentServer).CheckPorts(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/idl.Agent/UpgradePrimaries",
+		FullMethod: "/idl.Agent/CheckPorts",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AgentServer).UpgradePrimaries(ctx, req.(*UpgradePrimariesRequest))
+		return srv.(AgentServer).CheckPorts(ctx, req.(*CheckPortsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Agent_UpgradePrimaries_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(UpgradePrimariesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AgentServer).UpgradePrimaries(m, &agentUpgradePrimariesServer{stream})
+}
+
+type Agent_UpgradePrimariesServer interface {
+	Send(*UpgradePrimariesReply) error
+	grpc.ServerStream
+}
+
+type agentUpgradePrimariesServer struct {
+	grpc.ServerStream
+}
+
+func (x *agentUpgradePrimariesServer) Send(m *UpgradePrimariesReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Agent_RenameDirectories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RenameDirectoriesRequest)
 	if err := dec(in); err != nil {
@@ -2348,6 +3112,24 @@ func _Agent_RenameDirectories_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Agent_AlreadyRenamedDirectories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameDirectoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).AlreadyRenamedDirectories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Agent/AlreadyRenamedDirectories",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).AlreadyRenamedDirectories(ctx, req.(*RenameDirectoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Agent_StopAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(StopAgentRequest)
 	if err := dec(in); err != nil {
@@ -2582,6 +3364,45 @@ func _Agent_AddReplicationEntries_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Agent_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).GetInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Agent/GetInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).GetInfo(ctx, req.(*GetInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_Heartbeat_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(HeartbeatRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AgentServer).Heartbeat(m, &agentHeartbeatServer{stream})
+}
+
+type Agent_HeartbeatServer interface {
+	Send(*HeartbeatReply) error
+	grpc.ServerStream
+}
+
+type agentHeartbeatServer struct {
+	grpc.ServerStream
+}
+
+func (x *agentHeartbeatServer) Send(m *HeartbeatReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Agent_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "idl.Agent",
 	HandlerType: (*AgentServer)(nil),
@@ -2595,13 +3416,17 @@ var _Agent_serviceDesc = grpc.ServiceDesc{
 			Handler:    _Agent_CheckDiskSpace_Handler,
 		},
 		{
-			MethodName: "UpgradePrimaries",
-			Handler:    _Agent_UpgradePrimaries_Handler,
+			MethodName: "CheckPorts",
+			Handler:    _Agent_CheckPorts_Handler,
 		},
 		{
 			MethodName: "RenameDirectories",
 			Handler:    _Agent_RenameDirectories_Handler,
 		},
+		{
+			MethodName: "AlreadyRenamedDirectories",
+			Handler:    _Agent_AlreadyRenamedDirectories_Handler,
+		},
 		{
 			MethodName: "StopAgent",
 			Handler:    _Agent_StopAgent_Handler,
@@ -2654,7 +3479,22 @@ var _Agent_serviceDesc = grpc.ServiceDesc{
 			MethodName: "AddReplicationEntries",
 			Handler:    _Agent_AddReplicationEntries_Handler,
 		},
+		{
+			MethodName: "GetInfo",
+			Handler:    _Agent_GetInfo_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UpgradePrimaries",
+			Handler:       _Agent_UpgradePrimaries_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Heartbeat",
+			Handler:       _Agent_Heartbeat_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "hub_to_agent.proto",
 }
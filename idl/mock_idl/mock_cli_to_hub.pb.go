@@ -37,6 +37,26 @@ func (m *MockCliToHubClient) EXPECT() *MockCliToHubClientMockRecorder {
 	return m.recorder
 }
 
+// BreakStepLock mocks base method.
+func (m *MockCliToHubClient) BreakStepLock(arg0 context.Context, arg1 *idl.BreakStepLockRequest, arg2 ...grpc.CallOption) (*idl.BreakStepLockReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BreakStepLock", varargs...)
+	ret0, _ := ret[0].(*idl.BreakStepLockReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BreakStepLock indicates an expected call of BreakStepLock.
+func (mr *MockCliToHubClientMockRecorder) BreakStepLock(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BreakStepLock", reflect.TypeOf((*MockCliToHubClient)(nil).BreakStepLock), varargs...)
+}
+
 // Execute mocks base method.
 func (m *MockCliToHubClient) Execute(arg0 context.Context, arg1 *idl.ExecuteRequest, arg2 ...grpc.CallOption) (idl.CliToHub_ExecuteClient, error) {
 	m.ctrl.T.Helper()
@@ -77,6 +97,26 @@ func (mr *MockCliToHubClientMockRecorder) Finalize(arg0, arg1 interface{}, arg2
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Finalize", reflect.TypeOf((*MockCliToHubClient)(nil).Finalize), varargs...)
 }
 
+// GetAgents mocks base method.
+func (m *MockCliToHubClient) GetAgents(arg0 context.Context, arg1 *idl.GetAgentsRequest, arg2 ...grpc.CallOption) (*idl.GetAgentsReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetAgents", varargs...)
+	ret0, _ := ret[0].(*idl.GetAgentsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAgents indicates an expected call of GetAgents.
+func (mr *MockCliToHubClientMockRecorder) GetAgents(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgents", reflect.TypeOf((*MockCliToHubClient)(nil).GetAgents), varargs...)
+}
+
 // GetConfig mocks base method.
 func (m *MockCliToHubClient) GetConfig(arg0 context.Context, arg1 *idl.GetConfigRequest, arg2 ...grpc.CallOption) (*idl.GetConfigReply, error) {
 	m.ctrl.T.Helper()
@@ -97,6 +137,26 @@ func (mr *MockCliToHubClientMockRecorder) GetConfig(arg0, arg1 interface{}, arg2
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfig", reflect.TypeOf((*MockCliToHubClient)(nil).GetConfig), varargs...)
 }
 
+// GetStatus mocks base method.
+func (m *MockCliToHubClient) GetStatus(arg0 context.Context, arg1 *idl.GetStatusRequest, arg2 ...grpc.CallOption) (*idl.GetStatusReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetStatus", varargs...)
+	ret0, _ := ret[0].(*idl.GetStatusReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatus indicates an expected call of GetStatus.
+func (mr *MockCliToHubClientMockRecorder) GetStatus(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatus", reflect.TypeOf((*MockCliToHubClient)(nil).GetStatus), varargs...)
+}
+
 // Initialize mocks base method.
 func (m *MockCliToHubClient) Initialize(arg0 context.Context, arg1 *idl.InitializeRequest, arg2 ...grpc.CallOption) (idl.CliToHub_InitializeClient, error) {
 	m.ctrl.T.Helper()
@@ -137,6 +197,26 @@ func (mr *MockCliToHubClientMockRecorder) InitializeCreateCluster(arg0, arg1 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitializeCreateCluster", reflect.TypeOf((*MockCliToHubClient)(nil).InitializeCreateCluster), varargs...)
 }
 
+// Recover mocks base method.
+func (m *MockCliToHubClient) Recover(arg0 context.Context, arg1 *idl.RecoverRequest, arg2 ...grpc.CallOption) (*idl.RecoverReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Recover", varargs...)
+	ret0, _ := ret[0].(*idl.RecoverReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recover indicates an expected call of Recover.
+func (mr *MockCliToHubClientMockRecorder) Recover(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recover", reflect.TypeOf((*MockCliToHubClient)(nil).Recover), varargs...)
+}
+
 // RestartAgents mocks base method.
 func (m *MockCliToHubClient) RestartAgents(arg0 context.Context, arg1 *idl.RestartAgentsRequest, arg2 ...grpc.CallOption) (*idl.RestartAgentsReply, error) {
 	m.ctrl.T.Helper()
@@ -220,6 +300,21 @@ func (m *MockCliToHubServer) EXPECT() *MockCliToHubServerMockRecorder {
 	return m.recorder
 }
 
+// BreakStepLock mocks base method.
+func (m *MockCliToHubServer) BreakStepLock(arg0 context.Context, arg1 *idl.BreakStepLockRequest) (*idl.BreakStepLockReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BreakStepLock", arg0, arg1)
+	ret0, _ := ret[0].(*idl.BreakStepLockReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BreakStepLock indicates an expected call of BreakStepLock.
+func (mr *MockCliToHubServerMockRecorder) BreakStepLock(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BreakStepLock", reflect.TypeOf((*MockCliToHubServer)(nil).BreakStepLock), arg0, arg1)
+}
+
 // Execute mocks base method.
 func (m *MockCliToHubServer) Execute(arg0 *idl.ExecuteRequest, arg1 idl.CliToHub_ExecuteServer) error {
 	m.ctrl.T.Helper()
@@ -248,6 +343,21 @@ func (mr *MockCliToHubServerMockRecorder) Finalize(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Finalize", reflect.TypeOf((*MockCliToHubServer)(nil).Finalize), arg0, arg1)
 }
 
+// GetAgents mocks base method.
+func (m *MockCliToHubServer) GetAgents(arg0 context.Context, arg1 *idl.GetAgentsRequest) (*idl.GetAgentsReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAgents", arg0, arg1)
+	ret0, _ := ret[0].(*idl.GetAgentsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAgents indicates an expected call of GetAgents.
+func (mr *MockCliToHubServerMockRecorder) GetAgents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgents", reflect.TypeOf((*MockCliToHubServer)(nil).GetAgents), arg0, arg1)
+}
+
 // GetConfig mocks base method.
 func (m *MockCliToHubServer) GetConfig(arg0 context.Context, arg1 *idl.GetConfigRequest) (*idl.GetConfigReply, error) {
 	m.ctrl.T.Helper()
@@ -263,6 +373,21 @@ func (mr *MockCliToHubServerMockRecorder) GetConfig(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfig", reflect.TypeOf((*MockCliToHubServer)(nil).GetConfig), arg0, arg1)
 }
 
+// GetStatus mocks base method.
+func (m *MockCliToHubServer) GetStatus(arg0 context.Context, arg1 *idl.GetStatusRequest) (*idl.GetStatusReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatus", arg0, arg1)
+	ret0, _ := ret[0].(*idl.GetStatusReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatus indicates an expected call of GetStatus.
+func (mr *MockCliToHubServerMockRecorder) GetStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatus", reflect.TypeOf((*MockCliToHubServer)(nil).GetStatus), arg0, arg1)
+}
+
 // Initialize mocks base method.
 func (m *MockCliToHubServer) Initialize(arg0 *idl.InitializeRequest, arg1 idl.CliToHub_InitializeServer) error {
 	m.ctrl.T.Helper()
@@ -291,6 +416,21 @@ func (mr *MockCliToHubServerMockRecorder) InitializeCreateCluster(arg0, arg1 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitializeCreateCluster", reflect.TypeOf((*MockCliToHubServer)(nil).InitializeCreateCluster), arg0, arg1)
 }
 
+// Recover mocks base method.
+func (m *MockCliToHubServer) Recover(arg0 context.Context, arg1 *idl.RecoverRequest) (*idl.RecoverReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recover", arg0, arg1)
+	ret0, _ := ret[0].(*idl.RecoverReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recover indicates an expected call of Recover.
+func (mr *MockCliToHubServerMockRecorder) Recover(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recover", reflect.TypeOf((*MockCliToHubServer)(nil).Recover), arg0, arg1)
+}
+
 // RestartAgents mocks base method.
 func (m *MockCliToHubServer) RestartAgents(arg0 context.Context, arg1 *idl.RestartAgentsRequest) (*idl.RestartAgentsReply, error) {
 	m.ctrl.T.Helper()
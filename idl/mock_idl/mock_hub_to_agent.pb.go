@@ -11,8 +11,44 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	idl "github.com/greenplum-db/gpupgrade/idl"
 	grpc "google.golang.org/grpc"
+	metadata "google.golang.org/grpc/metadata"
 )
 
+// MockisUpgradePrimariesReply_Contents is a mock of isUpgradePrimariesReply_Contents interface.
+type MockisUpgradePrimariesReply_Contents struct {
+	ctrl     *gomock.Controller
+	recorder *MockisUpgradePrimariesReply_ContentsMockRecorder
+}
+
+// MockisUpgradePrimariesReply_ContentsMockRecorder is the mock recorder for MockisUpgradePrimariesReply_Contents.
+type MockisUpgradePrimariesReply_ContentsMockRecorder struct {
+	mock *MockisUpgradePrimariesReply_Contents
+}
+
+// NewMockisUpgradePrimariesReply_Contents creates a new mock instance.
+func NewMockisUpgradePrimariesReply_Contents(ctrl *gomock.Controller) *MockisUpgradePrimariesReply_Contents {
+	mock := &MockisUpgradePrimariesReply_Contents{ctrl: ctrl}
+	mock.recorder = &MockisUpgradePrimariesReply_ContentsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockisUpgradePrimariesReply_Contents) EXPECT() *MockisUpgradePrimariesReply_ContentsMockRecorder {
+	return m.recorder
+}
+
+// isUpgradePrimariesReply_Contents mocks base method.
+func (m *MockisUpgradePrimariesReply_Contents) isUpgradePrimariesReply_Contents() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "isUpgradePrimariesReply_Contents")
+}
+
+// isUpgradePrimariesReply_Contents indicates an expected call of isUpgradePrimariesReply_Contents.
+func (mr *MockisUpgradePrimariesReply_ContentsMockRecorder) isUpgradePrimariesReply_Contents() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "isUpgradePrimariesReply_Contents", reflect.TypeOf((*MockisUpgradePrimariesReply_Contents)(nil).isUpgradePrimariesReply_Contents))
+}
+
 // MockAgentClient is a mock of AgentClient interface.
 type MockAgentClient struct {
 	ctrl     *gomock.Controller
@@ -56,6 +92,26 @@ func (mr *MockAgentClientMockRecorder) AddReplicationEntries(ctx, in interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReplicationEntries", reflect.TypeOf((*MockAgentClient)(nil).AddReplicationEntries), varargs...)
 }
 
+// AlreadyRenamedDirectories mocks base method.
+func (m *MockAgentClient) AlreadyRenamedDirectories(ctx context.Context, in *idl.RenameDirectoriesRequest, opts ...grpc.CallOption) (*idl.AlreadyRenamedDirectoriesReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AlreadyRenamedDirectories", varargs...)
+	ret0, _ := ret[0].(*idl.AlreadyRenamedDirectoriesReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AlreadyRenamedDirectories indicates an expected call of AlreadyRenamedDirectories.
+func (mr *MockAgentClientMockRecorder) AlreadyRenamedDirectories(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AlreadyRenamedDirectories", reflect.TypeOf((*MockAgentClient)(nil).AlreadyRenamedDirectories), varargs...)
+}
+
 // ArchiveLogDirectory mocks base method.
 func (m *MockAgentClient) ArchiveLogDirectory(ctx context.Context, in *idl.ArchiveLogDirectoryRequest, opts ...grpc.CallOption) (*idl.ArchiveLogDirectoryReply, error) {
 	m.ctrl.T.Helper()
@@ -96,6 +152,26 @@ func (mr *MockAgentClientMockRecorder) CheckDiskSpace(ctx, in interface{}, opts
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckDiskSpace", reflect.TypeOf((*MockAgentClient)(nil).CheckDiskSpace), varargs...)
 }
 
+// CheckPorts mocks base method.
+func (m *MockAgentClient) CheckPorts(ctx context.Context, in *idl.CheckPortsRequest, opts ...grpc.CallOption) (*idl.CheckPortsReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CheckPorts", varargs...)
+	ret0, _ := ret[0].(*idl.CheckPortsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckPorts indicates an expected call of CheckPorts.
+func (mr *MockAgentClientMockRecorder) CheckPorts(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPorts", reflect.TypeOf((*MockAgentClient)(nil).CheckPorts), varargs...)
+}
+
 // CreateBackupDirectory mocks base method.
 func (m *MockAgentClient) CreateBackupDirectory(ctx context.Context, in *idl.CreateBackupDirectoryRequest, opts ...grpc.CallOption) (*idl.CreateBackupDirectoryReply, error) {
 	m.ctrl.T.Helper()
@@ -216,6 +292,46 @@ func (mr *MockAgentClientMockRecorder) DeleteTablespaceDirectories(ctx, in inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTablespaceDirectories", reflect.TypeOf((*MockAgentClient)(nil).DeleteTablespaceDirectories), varargs...)
 }
 
+// GetInfo mocks base method.
+func (m *MockAgentClient) GetInfo(ctx context.Context, in *idl.GetInfoRequest, opts ...grpc.CallOption) (*idl.GetInfoReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetInfo", varargs...)
+	ret0, _ := ret[0].(*idl.GetInfoReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInfo indicates an expected call of GetInfo.
+func (mr *MockAgentClientMockRecorder) GetInfo(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInfo", reflect.TypeOf((*MockAgentClient)(nil).GetInfo), varargs...)
+}
+
+// Heartbeat mocks base method.
+func (m *MockAgentClient) Heartbeat(ctx context.Context, in *idl.HeartbeatRequest, opts ...grpc.CallOption) (idl.Agent_HeartbeatClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Heartbeat", varargs...)
+	ret0, _ := ret[0].(idl.Agent_HeartbeatClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Heartbeat indicates an expected call of Heartbeat.
+func (mr *MockAgentClientMockRecorder) Heartbeat(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Heartbeat", reflect.TypeOf((*MockAgentClient)(nil).Heartbeat), varargs...)
+}
+
 // RenameDirectories mocks base method.
 func (m *MockAgentClient) RenameDirectories(ctx context.Context, in *idl.RenameDirectoriesRequest, opts ...grpc.CallOption) (*idl.RenameDirectoriesReply, error) {
 	m.ctrl.T.Helper()
@@ -357,14 +473,14 @@ func (mr *MockAgentClientMockRecorder) UpdateConfiguration(ctx, in interface{},
 }
 
 // UpgradePrimaries mocks base method.
-func (m *MockAgentClient) UpgradePrimaries(ctx context.Context, in *idl.UpgradePrimariesRequest, opts ...grpc.CallOption) (*idl.UpgradePrimariesReply, error) {
+func (m *MockAgentClient) UpgradePrimaries(ctx context.Context, in *idl.UpgradePrimariesRequest, opts ...grpc.CallOption) (idl.Agent_UpgradePrimariesClient, error) {
 	m.ctrl.T.Helper()
 	varargs := []interface{}{ctx, in}
 	for _, a := range opts {
 		varargs = append(varargs, a)
 	}
 	ret := m.ctrl.Call(m, "UpgradePrimaries", varargs...)
-	ret0, _ := ret[0].(*idl.UpgradePrimariesReply)
+	ret0, _ := ret[0].(idl.Agent_UpgradePrimariesClient)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -376,6 +492,252 @@ func (mr *MockAgentClientMockRecorder) UpgradePrimaries(ctx, in interface{}, opt
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradePrimaries", reflect.TypeOf((*MockAgentClient)(nil).UpgradePrimaries), varargs...)
 }
 
+// MockAgent_UpgradePrimariesClient is a mock of Agent_UpgradePrimariesClient interface.
+type MockAgent_UpgradePrimariesClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockAgent_UpgradePrimariesClientMockRecorder
+}
+
+// MockAgent_UpgradePrimariesClientMockRecorder is the mock recorder for MockAgent_UpgradePrimariesClient.
+type MockAgent_UpgradePrimariesClientMockRecorder struct {
+	mock *MockAgent_UpgradePrimariesClient
+}
+
+// NewMockAgent_UpgradePrimariesClient creates a new mock instance.
+func NewMockAgent_UpgradePrimariesClient(ctrl *gomock.Controller) *MockAgent_UpgradePrimariesClient {
+	mock := &MockAgent_UpgradePrimariesClient{ctrl: ctrl}
+	mock.recorder = &MockAgent_UpgradePrimariesClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAgent_UpgradePrimariesClient) EXPECT() *MockAgent_UpgradePrimariesClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockAgent_UpgradePrimariesClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockAgent_UpgradePrimariesClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockAgent_UpgradePrimariesClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockAgent_UpgradePrimariesClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockAgent_UpgradePrimariesClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockAgent_UpgradePrimariesClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockAgent_UpgradePrimariesClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockAgent_UpgradePrimariesClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockAgent_UpgradePrimariesClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockAgent_UpgradePrimariesClient) Recv() (*idl.UpgradePrimariesReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*idl.UpgradePrimariesReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockAgent_UpgradePrimariesClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockAgent_UpgradePrimariesClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockAgent_UpgradePrimariesClient) RecvMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockAgent_UpgradePrimariesClientMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockAgent_UpgradePrimariesClient)(nil).RecvMsg), m)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockAgent_UpgradePrimariesClient) SendMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockAgent_UpgradePrimariesClientMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockAgent_UpgradePrimariesClient)(nil).SendMsg), m)
+}
+
+// Trailer mocks base method.
+func (m *MockAgent_UpgradePrimariesClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockAgent_UpgradePrimariesClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockAgent_UpgradePrimariesClient)(nil).Trailer))
+}
+
+// MockAgent_HeartbeatClient is a mock of Agent_HeartbeatClient interface.
+type MockAgent_HeartbeatClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockAgent_HeartbeatClientMockRecorder
+}
+
+// MockAgent_HeartbeatClientMockRecorder is the mock recorder for MockAgent_HeartbeatClient.
+type MockAgent_HeartbeatClientMockRecorder struct {
+	mock *MockAgent_HeartbeatClient
+}
+
+// NewMockAgent_HeartbeatClient creates a new mock instance.
+func NewMockAgent_HeartbeatClient(ctrl *gomock.Controller) *MockAgent_HeartbeatClient {
+	mock := &MockAgent_HeartbeatClient{ctrl: ctrl}
+	mock.recorder = &MockAgent_HeartbeatClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAgent_HeartbeatClient) EXPECT() *MockAgent_HeartbeatClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockAgent_HeartbeatClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockAgent_HeartbeatClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockAgent_HeartbeatClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockAgent_HeartbeatClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockAgent_HeartbeatClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockAgent_HeartbeatClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockAgent_HeartbeatClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockAgent_HeartbeatClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockAgent_HeartbeatClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockAgent_HeartbeatClient) Recv() (*idl.HeartbeatReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*idl.HeartbeatReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockAgent_HeartbeatClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockAgent_HeartbeatClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockAgent_HeartbeatClient) RecvMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockAgent_HeartbeatClientMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockAgent_HeartbeatClient)(nil).RecvMsg), m)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockAgent_HeartbeatClient) SendMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockAgent_HeartbeatClientMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockAgent_HeartbeatClient)(nil).SendMsg), m)
+}
+
+// Trailer mocks base method.
+func (m *MockAgent_HeartbeatClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockAgent_HeartbeatClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockAgent_HeartbeatClient)(nil).Trailer))
+}
+
 // MockAgentServer is a mock of AgentServer interface.
 type MockAgentServer struct {
 	ctrl     *gomock.Controller
@@ -414,6 +776,21 @@ func (mr *MockAgentServerMockRecorder) AddReplicationEntries(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReplicationEntries", reflect.TypeOf((*MockAgentServer)(nil).AddReplicationEntries), arg0, arg1)
 }
 
+// AlreadyRenamedDirectories mocks base method.
+func (m *MockAgentServer) AlreadyRenamedDirectories(arg0 context.Context, arg1 *idl.RenameDirectoriesRequest) (*idl.AlreadyRenamedDirectoriesReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AlreadyRenamedDirectories", arg0, arg1)
+	ret0, _ := ret[0].(*idl.AlreadyRenamedDirectoriesReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AlreadyRenamedDirectories indicates an expected call of AlreadyRenamedDirectories.
+func (mr *MockAgentServerMockRecorder) AlreadyRenamedDirectories(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AlreadyRenamedDirectories", reflect.TypeOf((*MockAgentServer)(nil).AlreadyRenamedDirectories), arg0, arg1)
+}
+
 // ArchiveLogDirectory mocks base method.
 func (m *MockAgentServer) ArchiveLogDirectory(arg0 context.Context, arg1 *idl.ArchiveLogDirectoryRequest) (*idl.ArchiveLogDirectoryReply, error) {
 	m.ctrl.T.Helper()
@@ -444,6 +821,21 @@ func (mr *MockAgentServerMockRecorder) CheckDiskSpace(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckDiskSpace", reflect.TypeOf((*MockAgentServer)(nil).CheckDiskSpace), arg0, arg1)
 }
 
+// CheckPorts mocks base method.
+func (m *MockAgentServer) CheckPorts(arg0 context.Context, arg1 *idl.CheckPortsRequest) (*idl.CheckPortsReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckPorts", arg0, arg1)
+	ret0, _ := ret[0].(*idl.CheckPortsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckPorts indicates an expected call of CheckPorts.
+func (mr *MockAgentServerMockRecorder) CheckPorts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPorts", reflect.TypeOf((*MockAgentServer)(nil).CheckPorts), arg0, arg1)
+}
+
 // CreateBackupDirectory mocks base method.
 func (m *MockAgentServer) CreateBackupDirectory(arg0 context.Context, arg1 *idl.CreateBackupDirectoryRequest) (*idl.CreateBackupDirectoryReply, error) {
 	m.ctrl.T.Helper()
@@ -534,6 +926,35 @@ func (mr *MockAgentServerMockRecorder) DeleteTablespaceDirectories(arg0, arg1 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTablespaceDirectories", reflect.TypeOf((*MockAgentServer)(nil).DeleteTablespaceDirectories), arg0, arg1)
 }
 
+// GetInfo mocks base method.
+func (m *MockAgentServer) GetInfo(arg0 context.Context, arg1 *idl.GetInfoRequest) (*idl.GetInfoReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInfo", arg0, arg1)
+	ret0, _ := ret[0].(*idl.GetInfoReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInfo indicates an expected call of GetInfo.
+func (mr *MockAgentServerMockRecorder) GetInfo(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInfo", reflect.TypeOf((*MockAgentServer)(nil).GetInfo), arg0, arg1)
+}
+
+// Heartbeat mocks base method.
+func (m *MockAgentServer) Heartbeat(arg0 *idl.HeartbeatRequest, arg1 idl.Agent_HeartbeatServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Heartbeat", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Heartbeat indicates an expected call of Heartbeat.
+func (mr *MockAgentServerMockRecorder) Heartbeat(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Heartbeat", reflect.TypeOf((*MockAgentServer)(nil).Heartbeat), arg0, arg1)
+}
+
 // RenameDirectories mocks base method.
 func (m *MockAgentServer) RenameDirectories(arg0 context.Context, arg1 *idl.RenameDirectoriesRequest) (*idl.RenameDirectoriesReply, error) {
 	m.ctrl.T.Helper()
@@ -640,12 +1061,11 @@ func (mr *MockAgentServerMockRecorder) UpdateConfiguration(arg0, arg1 interface{
 }
 
 // UpgradePrimaries mocks base method.
-func (m *MockAgentServer) UpgradePrimaries(arg0 context.Context, arg1 *idl.UpgradePrimariesRequest) (*idl.UpgradePrimariesReply, error) {
+func (m *MockAgentServer) UpgradePrimaries(arg0 *idl.UpgradePrimariesRequest, arg1 idl.Agent_UpgradePrimariesServer) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpgradePrimaries", arg0, arg1)
-	ret0, _ := ret[0].(*idl.UpgradePrimariesReply)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // UpgradePrimaries indicates an expected call of UpgradePrimaries.
@@ -653,3 +1073,241 @@ func (mr *MockAgentServerMockRecorder) UpgradePrimaries(arg0, arg1 interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradePrimaries", reflect.TypeOf((*MockAgentServer)(nil).UpgradePrimaries), arg0, arg1)
 }
+
+// MockAgent_UpgradePrimariesServer is a mock of Agent_UpgradePrimariesServer interface.
+type MockAgent_UpgradePrimariesServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockAgent_UpgradePrimariesServerMockRecorder
+}
+
+// MockAgent_UpgradePrimariesServerMockRecorder is the mock recorder for MockAgent_UpgradePrimariesServer.
+type MockAgent_UpgradePrimariesServerMockRecorder struct {
+	mock *MockAgent_UpgradePrimariesServer
+}
+
+// NewMockAgent_UpgradePrimariesServer creates a new mock instance.
+func NewMockAgent_UpgradePrimariesServer(ctrl *gomock.Controller) *MockAgent_UpgradePrimariesServer {
+	mock := &MockAgent_UpgradePrimariesServer{ctrl: ctrl}
+	mock.recorder = &MockAgent_UpgradePrimariesServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAgent_UpgradePrimariesServer) EXPECT() *MockAgent_UpgradePrimariesServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockAgent_UpgradePrimariesServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockAgent_UpgradePrimariesServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockAgent_UpgradePrimariesServer)(nil).Context))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockAgent_UpgradePrimariesServer) RecvMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockAgent_UpgradePrimariesServerMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockAgent_UpgradePrimariesServer)(nil).RecvMsg), m)
+}
+
+// Send mocks base method.
+func (m *MockAgent_UpgradePrimariesServer) Send(arg0 *idl.UpgradePrimariesReply) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockAgent_UpgradePrimariesServerMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockAgent_UpgradePrimariesServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockAgent_UpgradePrimariesServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockAgent_UpgradePrimariesServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockAgent_UpgradePrimariesServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockAgent_UpgradePrimariesServer) SendMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockAgent_UpgradePrimariesServerMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockAgent_UpgradePrimariesServer)(nil).SendMsg), m)
+}
+
+// SetHeader mocks base method.
+func (m *MockAgent_UpgradePrimariesServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockAgent_UpgradePrimariesServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockAgent_UpgradePrimariesServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockAgent_UpgradePrimariesServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockAgent_UpgradePrimariesServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockAgent_UpgradePrimariesServer)(nil).SetTrailer), arg0)
+}
+
+// MockAgent_HeartbeatServer is a mock of Agent_HeartbeatServer interface.
+type MockAgent_HeartbeatServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockAgent_HeartbeatServerMockRecorder
+}
+
+// MockAgent_HeartbeatServerMockRecorder is the mock recorder for MockAgent_HeartbeatServer.
+type MockAgent_HeartbeatServerMockRecorder struct {
+	mock *MockAgent_HeartbeatServer
+}
+
+// NewMockAgent_HeartbeatServer creates a new mock instance.
+func NewMockAgent_HeartbeatServer(ctrl *gomock.Controller) *MockAgent_HeartbeatServer {
+	mock := &MockAgent_HeartbeatServer{ctrl: ctrl}
+	mock.recorder = &MockAgent_HeartbeatServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAgent_HeartbeatServer) EXPECT() *MockAgent_HeartbeatServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockAgent_HeartbeatServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockAgent_HeartbeatServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockAgent_HeartbeatServer)(nil).Context))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockAgent_HeartbeatServer) RecvMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockAgent_HeartbeatServerMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockAgent_HeartbeatServer)(nil).RecvMsg), m)
+}
+
+// Send mocks base method.
+func (m *MockAgent_HeartbeatServer) Send(arg0 *idl.HeartbeatReply) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockAgent_HeartbeatServerMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockAgent_HeartbeatServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockAgent_HeartbeatServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockAgent_HeartbeatServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockAgent_HeartbeatServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockAgent_HeartbeatServer) SendMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockAgent_HeartbeatServerMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockAgent_HeartbeatServer)(nil).SendMsg), m)
+}
+
+// SetHeader mocks base method.
+func (m *MockAgent_HeartbeatServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockAgent_HeartbeatServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockAgent_HeartbeatServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockAgent_HeartbeatServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockAgent_HeartbeatServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockAgent_HeartbeatServer)(nil).SetTrailer), arg0)
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package step
+
+import (
+	"fmt"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+// Option configures how a substep is run.
+type Option func(*optionList)
+
+// Idempotent declares the substep safe to re-run when a previous run was
+// interrupted while running, such as when the hub crashed or the host
+// rebooted. Substeps not declared idempotent require "gpupgrade recover" to
+// determine whether their side effects were applied before they can be run
+// again. It is recorded with each run so that recover knows whether the
+// interrupted run was idempotent.
+func Idempotent() Option {
+	return func(options *optionList) {
+		options.idempotent = true
+	}
+}
+
+type optionList struct {
+	idempotent bool
+}
+
+func newOptionList(opts ...Option) *optionList {
+	o := new(optionList)
+	for _, option := range opts {
+		option(o)
+	}
+	return o
+}
+
+// IsDeclaredIdempotent returns true when the options declare the substep
+// idempotent.
+func IsDeclaredIdempotent(opts ...Option) bool {
+	return newOptionList(opts...).idempotent
+}
+
+// RunningSubstepErr is returned when a substep that is not idempotent was
+// found running from a previous interrupted run.
+func RunningSubstepErr(substep idl.Substep) error {
+	err := fmt.Errorf("Found previous substep %s was running.", substep)
+	nextAction := fmt.Sprintf(`Run "gpupgrade recover --substep %s" to check whether the substep completed and reset its status.`, substep)
+	return utils.NewNextActionErr(err, nextAction)
+}
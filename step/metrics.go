@@ -0,0 +1,46 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package step
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils/metrics"
+)
+
+var substepStatusMetric = metrics.Factory.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "gpupgrade_substep_status",
+	Help: "Whether a substep is in the given status. Exactly one status is 1 for each substep that has been reported.",
+}, []string{"step", "substep", "status"})
+
+var substepDurationMetric = metrics.Factory.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "gpupgrade_substep_duration_seconds",
+	Help: "Duration in seconds of the most recent run of a substep.",
+}, []string{"step", "substep"})
+
+var metricStatuses = []idl.Status{
+	idl.Status_running,
+	idl.Status_complete,
+	idl.Status_failed,
+	idl.Status_skipped,
+	idl.Status_quit,
+}
+
+func recordSubstepStatus(step idl.Step, substep idl.Substep, status idl.Status) {
+	for _, s := range metricStatuses {
+		value := 0.0
+		if s == status {
+			value = 1
+		}
+
+		substepStatusMetric.WithLabelValues(step.String(), substep.String(), s.String()).Set(value)
+	}
+}
+
+func recordSubstepDuration(step idl.Step, substep idl.Substep, duration time.Duration) {
+	substepDurationMetric.WithLabelValues(step.String(), substep.String()).Set(duration.Seconds())
+}
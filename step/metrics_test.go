@@ -0,0 +1,73 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package step_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils/metrics"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Run("records the status and duration of substeps", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		server := mock_idl.NewMockCliToHub_ExecuteServer(ctrl)
+		server.EXPECT().Send(gomock.Any()).AnyTimes()
+
+		s := step.New(context.Background(), idl.Step_revert, server, &TestSubstepStore{}, &testutils.DevNullWithClose{})
+		s.Run(idl.Substep_restore_pgcontrol, func(streams step.OutStreams) error {
+			return nil
+		})
+
+		s = step.New(context.Background(), idl.Step_revert, server, &TestSubstepStore{}, &testutils.DevNullWithClose{})
+		s.Run(idl.Substep_restore_source_cluster, func(streams step.OutStreams) error {
+			return errors.New("ahhh")
+		})
+
+		output := mustWriteMetrics(t)
+
+		expected := []string{
+			`gpupgrade_substep_status{status="complete",step="revert",substep="restore_pgcontrol"} 1`,
+			`gpupgrade_substep_status{status="running",step="revert",substep="restore_pgcontrol"} 0`,
+			`gpupgrade_substep_status{status="failed",step="revert",substep="restore_source_cluster"} 1`,
+			`gpupgrade_substep_status{status="complete",step="revert",substep="restore_source_cluster"} 0`,
+		}
+		for _, line := range expected {
+			if !strings.Contains(output, line+"\n") {
+				t.Errorf("expected metrics to contain %q got %q", line, output)
+			}
+		}
+
+		for _, substep := range []string{"restore_pgcontrol", "restore_source_cluster"} {
+			pattern := regexp.MustCompile(`gpupgrade_substep_duration_seconds{step="revert",substep="` + substep + `"} \S+\n`)
+			if !pattern.MatchString(output) {
+				t.Errorf("expected metrics to contain the duration of %s got %q", substep, output)
+			}
+		}
+	})
+}
+
+func mustWriteMetrics(t *testing.T) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := metrics.Write(&buf); err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package step
+
+import (
+	"errors"
+	"io/fs"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+// Plan records the substeps a step would run without running them. Its Run,
+// AlwaysRun, and RunConditionally methods mirror those of Step so that a plan
+// can be built by walking the same substeps in the same order. Reading the
+// substep store is the only I/O performed.
+type Plan struct {
+	Step     idl.Step
+	Substeps []*PlannedSubstep
+
+	store SubstepStore
+	err   error
+}
+
+// PlannedSubstep describes what would happen to a single substep. Details
+// lists the hosts, directories, and ports the substep touches.
+type PlannedSubstep struct {
+	Substep idl.Substep
+	WillRun bool
+	Reason  string
+	Details []string
+}
+
+func NewPlan(name idl.Step, store SubstepStore) *Plan {
+	return &Plan{Step: name, store: store}
+}
+
+func (p *Plan) Err() error {
+	return p.err
+}
+
+func (p *Plan) AlwaysRun(substep idl.Substep, details ...string) {
+	p.plan(substep, true, "", true, details)
+}
+
+// RunConditionally records whether the substep would run given shouldRun.
+// The condition describes the branch, such as "link mode", and is reported
+// whether or not the branch is taken.
+func (p *Plan) RunConditionally(substep idl.Substep, shouldRun bool, condition string, details ...string) {
+	p.plan(substep, shouldRun, condition, false, details)
+}
+
+func (p *Plan) Run(substep idl.Substep, details ...string) {
+	p.plan(substep, true, "", false, details)
+}
+
+func (p *Plan) plan(substep idl.Substep, shouldRun bool, condition string, alwaysRun bool, details []string) {
+	planned := &PlannedSubstep{Substep: substep, WillRun: shouldRun, Reason: condition, Details: details}
+	p.Substeps = append(p.Substeps, planned)
+
+	if !shouldRun {
+		if condition == "" {
+			planned.Reason = "condition not met"
+		} else {
+			planned.Reason = "not " + condition
+		}
+		return
+	}
+
+	run, err := p.lastRun(substep)
+	if err != nil {
+		p.err = err
+		return
+	}
+
+	status := run.Status.Status
+	switch {
+	case status == idl.Status_running && !run.Idempotent:
+		planned.WillRun = false
+		planned.Reason = "previously interrupted; requires gpupgrade recover"
+	case status == idl.Status_complete && !alwaysRun:
+		planned.WillRun = false
+		planned.Reason = "already complete"
+	}
+}
+
+func (p *Plan) lastRun(substep idl.Substep) (SubstepRun, error) {
+	if p.store == nil {
+		return SubstepRun{}, nil
+	}
+
+	run, err := p.store.ReadRun(p.Step, substep)
+	if errors.Is(err, fs.ErrNotExist) {
+		return SubstepRun{}, nil
+	}
+
+	return run, err
+}
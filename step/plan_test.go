@@ -0,0 +1,139 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package step_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestPlan(t *testing.T) {
+	t.Run("records substeps that would run without writing to the store", func(t *testing.T) {
+		substepStore := &TestSubstepStore{}
+		plan := step.NewPlan(idl.Step_execute, substepStore)
+
+		plan.Run(idl.Substep_upgrade_master, "mdw:5432 /data/qddir/seg-1")
+		plan.AlwaysRun(idl.Substep_start_target_cluster)
+
+		if err := plan.Err(); err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := []*step.PlannedSubstep{
+			{Substep: idl.Substep_upgrade_master, WillRun: true, Details: []string{"mdw:5432 /data/qddir/seg-1"}},
+			{Substep: idl.Substep_start_target_cluster, WillRun: true},
+		}
+		if !reflect.DeepEqual(plan.Substeps, expected) {
+			t.Errorf("got %+v want %+v", plan.Substeps, expected)
+		}
+
+		if substepStore.Status != idl.Status_unknown_status || substepStore.Runs != 0 {
+			t.Errorf("expected the store to not be written to")
+		}
+	})
+
+	t.Run("reports which conditional branches are taken", func(t *testing.T) {
+		plan := step.NewPlan(idl.Step_finalize, &TestSubstepStore{})
+
+		plan.RunConditionally(idl.Substep_upgrade_mirrors, true, "link mode", "rsync")
+		plan.RunConditionally(idl.Substep_upgrade_mirrors, false, "copy mode", "gpaddmirrors")
+		plan.RunConditionally(idl.Substep_upgrade_standby, false, "")
+
+		expected := []*step.PlannedSubstep{
+			{Substep: idl.Substep_upgrade_mirrors, WillRun: true, Reason: "link mode", Details: []string{"rsync"}},
+			{Substep: idl.Substep_upgrade_mirrors, WillRun: false, Reason: "not copy mode", Details: []string{"gpaddmirrors"}},
+			{Substep: idl.Substep_upgrade_standby, WillRun: false, Reason: "condition not met"},
+		}
+		if !reflect.DeepEqual(plan.Substeps, expected) {
+			t.Errorf("got %+v want %+v", plan.Substeps, expected)
+		}
+	})
+
+	t.Run("skips completed substeps unless they are always run", func(t *testing.T) {
+		plan := step.NewPlan(idl.Step_execute, &TestSubstepStore{Status: idl.Status_complete})
+
+		plan.Run(idl.Substep_upgrade_master)
+		plan.AlwaysRun(idl.Substep_shutdown_source_cluster)
+
+		if plan.Substeps[0].WillRun || plan.Substeps[0].Reason != "already complete" {
+			t.Errorf("got %+v want upgrade_master to be skipped", plan.Substeps[0])
+		}
+
+		if !plan.Substeps[1].WillRun {
+			t.Errorf("got %+v want shutdown_source_cluster to run", plan.Substeps[1])
+		}
+	})
+
+	t.Run("reports running substeps that must be recovered", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		path := filepath.Join(stateDir, step.SubstepsFileName)
+		testutils.MustWriteToFile(t, path, "{}")
+
+		store := step.NewSubstepStoreUsingFile(path)
+		mustStart(t, store, idl.Step_execute, idl.Substep_upgrade_master, false)
+		mustStart(t, store, idl.Step_execute, idl.Substep_shutdown_source_cluster, true)
+
+		plan := step.NewPlan(idl.Step_execute, store)
+		plan.Run(idl.Substep_upgrade_master)
+		plan.Run(idl.Substep_shutdown_source_cluster)
+
+		if plan.Substeps[0].WillRun || plan.Substeps[0].Reason != "previously interrupted; requires gpupgrade recover" {
+			t.Errorf("got %+v want upgrade_master to not run", plan.Substeps[0])
+		}
+
+		if !plan.Substeps[1].WillRun {
+			t.Errorf("got %+v want idempotent shutdown_source_cluster to run", plan.Substeps[1])
+		}
+	})
+
+	t.Run("treats a missing status file as no substeps having run", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		path := filepath.Join(stateDir, step.SubstepsFileName)
+		plan := step.NewPlan(idl.Step_execute, step.NewSubstepStoreUsingFile(path))
+		plan.Run(idl.Substep_upgrade_master)
+
+		if err := plan.Err(); err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		if !plan.Substeps[0].WillRun {
+			t.Errorf("got %+v want upgrade_master to run", plan.Substeps[0])
+		}
+
+		testutils.PathMustNotExist(t, path)
+	})
+
+	t.Run("returns errors reading the status file", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		path := filepath.Join(stateDir, step.SubstepsFileName)
+		testutils.MustWriteToFile(t, path, "not json")
+
+		plan := step.NewPlan(idl.Step_execute, step.NewSubstepStoreUsingFile(path))
+		plan.Run(idl.Substep_upgrade_master)
+
+		if plan.Err() == nil {
+			t.Errorf("expected an error")
+		}
+	})
+}
+
+func mustStart(t *testing.T, store *step.SubstepFileStore, stepName idl.Step, substep idl.Substep, idempotent bool) {
+	t.Helper()
+
+	err := store.Start(stepName, substep, idempotent)
+	if err != nil {
+		t.Fatalf("Start() returned error %#v", err)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package step
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+// Steps lists the user facing steps in the order they are typically run.
+var Steps = []idl.Step{idl.Step_initialize, idl.Step_execute, idl.Step_finalize, idl.Step_revert}
+
+// StepStatusReader reads the overall status of a step, such as
+// clistep.StepStore.
+type StepStatusReader interface {
+	Read(idl.Step) (idl.Status, error)
+}
+
+// stepStatusFile reads the overall step status from steps.json in the same way
+// as clistep.StepStoreFileStore for callers that cannot import clistep.
+type stepStatusFile struct {
+	store *SubstepFileStore
+}
+
+func (f stepStatusFile) Read(step idl.Step) (idl.Status, error) {
+	return f.store.Read(step, idl.Substep_step_status)
+}
+
+// LoadStatus reads the step and substep status files from the state directory
+// and returns the status of each step that has started. It does not create
+// the status files if they do not exist so that it can be safely used when
+// gpupgrade has not yet been initialized, or when the hub is not running.
+func LoadStatus(stateDir string) ([]*idl.StepStatus, error) {
+	stepStore := stepStatusFile{NewSubstepStoreUsingFile(filepath.Join(stateDir, StepsFileName))}
+	substepStore := NewSubstepStoreUsingFile(filepath.Join(stateDir, SubstepsFileName))
+	return LoadStatusFromStores(stepStore, substepStore)
+}
+
+// LoadStatusFromStores is LoadStatus using the given step and substep stores.
+// Missing status files are treated as no steps having started.
+func LoadStatusFromStores(stepStore StepStatusReader, substepStore *SubstepFileStore) ([]*idl.StepStatus, error) {
+	substeps, err := substepStore.load()
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, xerrors.Errorf("read %q: %w", SubstepsFileName, err)
+	}
+
+	var statuses []*idl.StepStatus
+	for _, name := range Steps {
+		stepStatus, err := stepStore.Read(name)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, xerrors.Errorf("read %s status from %q: %w", name, StepsFileName, err)
+		}
+
+		substepEntries := substeps[name.String()]
+		if stepStatus == idl.Status_unknown_status && len(substepEntries) == 0 {
+			continue
+		}
+
+		status := &idl.StepStatus{Step: name, Status: stepStatus}
+		for substepName, entry := range substepEntries {
+			substep := idl.Substep(idl.Substep_value[substepName])
+			if substep == idl.Substep_step_status {
+				continue
+			}
+
+			status.Substeps = append(status.Substeps, substepDetails(substep, entry))
+		}
+
+		// Order substeps by when they started. Substeps without a start time,
+		// such as those written by older versions of gpupgrade, are ordered
+		// last by their enum value.
+		sort.Slice(status.Substeps, func(i, j int) bool {
+			a, b := status.Substeps[i], status.Substeps[j]
+			if (a.StartTime == nil) != (b.StartTime == nil) {
+				return a.StartTime != nil
+			}
+
+			if a.StartTime != nil && !a.StartTime.AsTime().Equal(b.StartTime.AsTime()) {
+				return a.StartTime.AsTime().Before(b.StartTime.AsTime())
+			}
+
+			return a.Substep < b.Substep
+		})
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+func substepDetails(substep idl.Substep, entry *SubstepEntry) *idl.SubstepDetails {
+	details := &idl.SubstepDetails{
+		Substep: substep,
+		Status:  entry.Status.Status,
+		Error:   entry.Error,
+	}
+
+	if entry.StartTime != nil {
+		details.StartTime = timestamppb.New(*entry.StartTime)
+	}
+
+	if entry.Duration != nil {
+		details.Duration = durationpb.New(entry.Duration.Duration)
+	}
+
+	return details
+}
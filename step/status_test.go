@@ -0,0 +1,174 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package step_test
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestLoadStatus(t *testing.T) {
+	t.Run("returns no steps when the status files do not exist", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		steps, err := step.LoadStatus(stateDir)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if len(steps) != 0 {
+			t.Errorf("got %v want no steps", steps)
+		}
+	})
+
+	t.Run("returns the status of each started step and its substeps", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		testutils.MustWriteToFile(t, filepath.Join(stateDir, step.StepsFileName), "{}")
+		testutils.MustWriteToFile(t, filepath.Join(stateDir, step.SubstepsFileName), "{}")
+
+		stepStore := step.NewSubstepStoreUsingFile(filepath.Join(stateDir, step.StepsFileName))
+		mustWrite(t, stepStore, idl.Step_initialize, idl.Substep_step_status, idl.Status_complete)
+		mustWrite(t, stepStore, idl.Step_execute, idl.Substep_step_status, idl.Status_failed)
+
+		substepStore := step.NewSubstepStoreUsingFile(filepath.Join(stateDir, step.SubstepsFileName))
+		mustWrite(t, substepStore, idl.Step_initialize, idl.Substep_check_upgrade, idl.Status_complete)
+		mustWrite(t, substepStore, idl.Step_initialize, idl.Substep_saving_source_cluster_config, idl.Status_complete)
+		mustWrite(t, substepStore, idl.Step_execute, idl.Substep_upgrade_master, idl.Status_failed)
+
+		steps, err := step.LoadStatus(stateDir)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := []*idl.StepStatus{
+			{Step: idl.Step_initialize, Status: idl.Status_complete, Substeps: []*idl.SubstepDetails{
+				{Substep: idl.Substep_saving_source_cluster_config, Status: idl.Status_complete},
+				{Substep: idl.Substep_check_upgrade, Status: idl.Status_complete},
+			}},
+			{Step: idl.Step_execute, Status: idl.Status_failed, Substeps: []*idl.SubstepDetails{
+				{Substep: idl.Substep_upgrade_master, Status: idl.Status_failed},
+			}},
+		}
+
+		if !reflect.DeepEqual(steps, expected) {
+			t.Errorf("got %v want %v", steps, expected)
+		}
+	})
+
+	t.Run("returns the timing and error of each substep ordered by start time", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		testutils.MustWriteToFile(t, filepath.Join(stateDir, step.SubstepsFileName), `{
+  "execute": {
+    "upgrade_master": {"status": "failed", "start_time": "2023-03-01T10:32:00Z", "duration": "5s", "error": "pg_upgrade failed"},
+    "shutdown_source_cluster": {"status": "complete", "start_time": "2023-03-01T10:30:00Z", "duration": "1m30s"}
+  }
+}`)
+
+		steps, err := step.LoadStatus(stateDir)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if len(steps) != 1 || len(steps[0].Substeps) != 2 {
+			t.Fatalf("got %v want one step with two substeps", steps)
+		}
+
+		first, second := steps[0].Substeps[0], steps[0].Substeps[1]
+		if first.Substep != idl.Substep_shutdown_source_cluster || first.Duration.AsDuration() != 90*time.Second {
+			t.Errorf("unexpected first substep %v", first)
+		}
+
+		expectedStart := time.Date(2023, time.March, 1, 10, 32, 0, 0, time.UTC)
+		if second.Substep != idl.Substep_upgrade_master || !second.StartTime.AsTime().Equal(expectedStart) || second.Error != "pg_upgrade failed" {
+			t.Errorf("unexpected second substep %v", second)
+		}
+	})
+
+	t.Run("reads the step status from the given step store", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		substepStore := step.NewSubstepStoreUsingFile(filepath.Join(stateDir, step.SubstepsFileName))
+		stepStore := stepStatuses{idl.Step_initialize: idl.Status_complete, idl.Step_execute: idl.Status_running}
+
+		steps, err := step.LoadStatusFromStores(stepStore, substepStore)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := []*idl.StepStatus{
+			{Step: idl.Step_initialize, Status: idl.Status_complete},
+			{Step: idl.Step_execute, Status: idl.Status_running},
+		}
+
+		if !reflect.DeepEqual(steps, expected) {
+			t.Errorf("got %v want %v", steps, expected)
+		}
+	})
+
+	t.Run("returns an error when the step store fails", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		substepStore := step.NewSubstepStoreUsingFile(filepath.Join(stateDir, step.SubstepsFileName))
+
+		expected := errors.New("permission denied")
+		_, err := step.LoadStatusFromStores(failingStepStore{expected}, substepStore)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+
+	t.Run("returns an error when a status file is malformed", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		testutils.MustWriteToFile(t, filepath.Join(stateDir, step.SubstepsFileName), "{")
+
+		steps, err := step.LoadStatus(stateDir)
+		if err == nil || !strings.Contains(err.Error(), step.SubstepsFileName) {
+			t.Errorf("got error %#v want error mentioning %q", err, step.SubstepsFileName)
+		}
+
+		if steps != nil {
+			t.Errorf("got %v want nil", steps)
+		}
+	})
+}
+
+type stepStatuses map[idl.Step]idl.Status
+
+func (s stepStatuses) Read(stepName idl.Step) (idl.Status, error) {
+	return s[stepName], nil
+}
+
+type failingStepStore struct {
+	err error
+}
+
+func (f failingStepStore) Read(idl.Step) (idl.Status, error) {
+	return idl.Status_unknown_status, f.err
+}
+
+func mustWrite(t *testing.T, store *step.SubstepFileStore, stepName idl.Step, substep idl.Substep, status idl.Status) {
+	t.Helper()
+
+	err := store.Write(stepName, substep, status)
+	if err != nil {
+		t.Fatalf("Write() returned error %#v", err)
+	}
+}
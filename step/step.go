@@ -4,10 +4,12 @@
 package step
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -25,7 +27,12 @@ import (
 
 const SubstepsFileName = "substeps.json"
 
+// StepsFileName tracks the overall status of each step and is written by the
+// CLI. It shares the same format as SubstepsFileName.
+const StepsFileName = "steps.json"
+
 type Step struct {
+	ctx          context.Context // canceled when the client goes away
 	name         idl.Step
 	sender       idl.MessageSender // sends substep status messages
 	substepStore SubstepStore      // persistent substep status storage
@@ -33,8 +40,9 @@ type Step struct {
 	err          error
 }
 
-func New(name idl.Step, sender idl.MessageSender, substepStore SubstepStore, streams OutStreamsCloser) *Step {
+func New(ctx context.Context, name idl.Step, sender idl.MessageSender, substepStore SubstepStore, streams OutStreamsCloser) *Step {
 	return &Step{
+		ctx:          ctx,
 		name:         name,
 		sender:       sender,
 		substepStore: substepStore,
@@ -42,7 +50,7 @@ func New(name idl.Step, sender idl.MessageSender, substepStore SubstepStore, str
 	}
 }
 
-func Begin(step idl.Step, sender idl.MessageSender) (*Step, error) {
+func Begin(ctx context.Context, step idl.Step, sender idl.MessageSender) (*Step, error) {
 	logFile, err := logger.OpenFile("hub")
 	if err != nil {
 		return nil, xerrors.Errorf(`getting log file for step "%s": %w`, step, err)
@@ -60,7 +68,7 @@ func Begin(step idl.Step, sender idl.MessageSender) (*Step, error) {
 
 	streams := newMultiplexedStream(sender, logFile)
 
-	return New(step, sender, substepStore, streams), nil
+	return New(ctx, step, sender, substepStore, streams), nil
 }
 
 func HasStarted(step idl.Step) (bool, error) {
@@ -155,24 +163,24 @@ func (s *Step) Err() error {
 	return statusErr.Err()
 }
 
-func (s *Step) AlwaysRun(substep idl.Substep, f func(OutStreams) error) {
-	s.run(substep, f, true)
+func (s *Step) AlwaysRun(substep idl.Substep, f func(OutStreams) error, opts ...Option) {
+	s.run(substep, f, true, opts...)
 }
 
-func (s *Step) RunConditionally(substep idl.Substep, shouldRun bool, f func(OutStreams) error) {
+func (s *Step) RunConditionally(substep idl.Substep, shouldRun bool, f func(OutStreams) error, opts ...Option) {
 	if !shouldRun {
 		log.Printf("skipping %s", substep)
 		return
 	}
 
-	s.run(substep, f, false)
+	s.run(substep, f, false, opts...)
 }
 
-func (s *Step) Run(substep idl.Substep, f func(OutStreams) error) {
-	s.run(substep, f, false)
+func (s *Step) Run(substep idl.Substep, f func(OutStreams) error, opts ...Option) {
+	s.run(substep, f, false, opts...)
 }
 
-func (s *Step) run(substep idl.Substep, f func(OutStreams) error, alwaysRun bool) {
+func (s *Step) run(substep idl.Substep, f func(OutStreams) error, alwaysRun bool, opts ...Option) {
 	var err error
 	defer func() {
 		if err != nil {
@@ -184,22 +192,34 @@ func (s *Step) run(substep idl.Substep, f func(OutStreams) error, alwaysRun bool
 		return
 	}
 
+	// Do not start any further substeps once the client has gone away.
+	err = s.ctx.Err()
+	if err != nil {
+		return
+	}
+
 	status, err := s.substepStore.Read(s.name, substep)
 	if err != nil {
 		return
 	}
 
+	idempotent := IsDeclaredIdempotent(opts...)
 	if status == idl.Status_running {
-		// TODO: Finalize error wording and recommended action
-		err = fmt.Errorf("Found previous substep %s was running. Manual intervention needed to cleanup. Please contact support.", substep)
-		s.sendStatus(substep, idl.Status_failed)
-		return
+		if !idempotent {
+			err = RunningSubstepErr(substep)
+			s.sendStatus(substep, idl.Status_failed)
+			s.logEvent(logger.SubstepFailed, substep, 0, err)
+			return
+		}
+
+		log.Printf("Found previous substep %s was running. Re-running since it is idempotent.", substep)
 	}
 
 	// Only re-run substeps that are failed or pending. Do not skip substeps that must always be run.
 	if status == idl.Status_complete && !alwaysRun {
 		// Only send the status back to the UI; don't re-persist to the store
 		s.sendStatus(substep, idl.Status_skipped)
+		s.logEvent(logger.SubstepSkipped, substep, 0, nil)
 		return
 	}
 
@@ -215,26 +235,49 @@ func (s *Step) run(substep idl.Substep, f func(OutStreams) error, alwaysRun bool
 		return
 	}
 
-	err = s.write(substep, idl.Status_running)
+	err = s.substepStore.Start(s.name, substep, idempotent)
 	if err != nil {
 		return
 	}
+	s.sendStatus(substep, idl.Status_running)
+
+	s.logEvent(logger.SubstepStarted, substep, 0, nil)
+
+	defer func() {
+		recordSubstepDuration(s.name, substep, timer.Stop().Elapsed())
+
+		if wErr := s.substepStore.WriteRun(s.name, substep, timer.Stop().Elapsed(), err); wErr != nil {
+			err = errorlist.Append(err, wErr)
+		}
+	}()
 
 	err = f(s.streams)
 
 	switch {
 	case errors.Is(err, Skip):
 		// The substep has requested a manual skip; this isn't really an error.
+		s.logEvent(logger.SubstepSkipped, substep, timer.Stop().Elapsed(), nil)
 		err = s.write(substep, idl.Status_skipped)
 		return
 
+	case err != nil && s.ctx.Err() != nil:
+		// The client canceled the step, such as with Ctrl-C, which
+		// interrupted the substep.
+		s.logEvent(logger.SubstepQuit, substep, timer.Stop().Elapsed(), err)
+		if werr := s.write(substep, idl.Status_quit); werr != nil {
+			err = errorlist.Append(err, werr)
+		}
+		return
+
 	case err != nil:
+		s.logEvent(logger.SubstepFailed, substep, timer.Stop().Elapsed(), err)
 		if werr := s.write(substep, idl.Status_failed); werr != nil {
 			err = errorlist.Append(err, werr)
 		}
 		return
 	}
 
+	s.logEvent(logger.SubstepCompleted, substep, timer.Stop().Elapsed(), nil)
 	err = s.write(substep, idl.Status_complete)
 }
 
@@ -256,6 +299,8 @@ func (s *Step) write(substep idl.Substep, status idl.Status) error {
 }
 
 func (s *Step) sendStatus(substep idl.Substep, status idl.Status) {
+	recordSubstepStatus(s.name, substep, status)
+
 	// A stream is not guaranteed to remain connected during execution, so
 	// errors are explicitly ignored.
 	_ = s.sender.Send(&idl.Message{
@@ -266,6 +311,16 @@ func (s *Step) sendStatus(substep idl.Substep, status idl.Status) {
 	})
 }
 
+func (s *Step) logEvent(eventType logger.EventType, substep idl.Substep, duration time.Duration, err error) {
+	logger.LogEvent(logger.Event{
+		Type:            eventType,
+		Step:            s.name.String(),
+		Substep:         substep.String(),
+		DurationSeconds: duration.Seconds(),
+		Error:           logger.ErrorString(err),
+	})
+}
+
 func (s *Step) printDuration(substep idl.Substep, duration string) error {
 	divider := "-----------------------------------------------------------------------------"
 	_, err := fmt.Fprintf(s.streams.Stdout(), "\n%s took %s\n\n%s\n", substep, duration, divider)
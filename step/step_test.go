@@ -4,12 +4,15 @@
 package step_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"google.golang.org/grpc/status"
@@ -21,6 +24,7 @@ import (
 	"github.com/greenplum-db/gpupgrade/testutils/testlog"
 	"github.com/greenplum-db/gpupgrade/utils"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+	"github.com/greenplum-db/gpupgrade/utils/logger"
 )
 
 func TestStepRun(t *testing.T) {
@@ -42,7 +46,7 @@ func TestStepRun(t *testing.T) {
 				Status: idl.Status_complete,
 			}}})
 
-		s := step.New(idl.Step_initialize, server, &TestSubstepStore{}, &testutils.DevNullWithClose{})
+		s := step.New(context.Background(), idl.Step_initialize, server, &TestSubstepStore{}, &testutils.DevNullWithClose{})
 
 		var called bool
 		s.Run(idl.Substep_saving_source_cluster_config, func(streams step.OutStreams) error {
@@ -55,6 +59,30 @@ func TestStepRun(t *testing.T) {
 		}
 	})
 
+	t.Run("records the run of a failed substep", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		server := mock_idl.NewMockCliToHub_ExecuteServer(ctrl)
+		server.EXPECT().Send(gomock.Any()).AnyTimes()
+
+		substepStore := &TestSubstepStore{}
+		s := step.New(context.Background(), idl.Step_initialize, server, substepStore, &testutils.DevNullWithClose{})
+
+		expected := errors.New("oops")
+		s.Run(idl.Substep_saving_source_cluster_config, func(streams step.OutStreams) error {
+			return expected
+		})
+
+		if substepStore.Runs != 1 {
+			t.Errorf("got %d runs recorded want 1", substepStore.Runs)
+		}
+
+		if !errors.Is(substepStore.RunErr, expected) {
+			t.Errorf("got run error %#v want %#v", substepStore.RunErr, expected)
+		}
+	})
+
 	t.Run("reports an explicitly skipped substep and marks the status complete on disk", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
@@ -75,7 +103,7 @@ func TestStepRun(t *testing.T) {
 		)
 
 		substepStore := &TestSubstepStore{}
-		s := step.New(idl.Step_initialize, server, substepStore, &testutils.DevNullWithClose{})
+		s := step.New(context.Background(), idl.Step_initialize, server, substepStore, &testutils.DevNullWithClose{})
 
 		s.Run(idl.Substep_saving_source_cluster_config, func(streams step.OutStreams) error {
 			return step.Skip
@@ -103,7 +131,7 @@ func TestStepRun(t *testing.T) {
 			}}})
 
 		substepStore := &TestSubstepStore{}
-		s := step.New(idl.Step_initialize, server, substepStore, &testutils.DevNullWithClose{})
+		s := step.New(context.Background(), idl.Step_initialize, server, substepStore, &testutils.DevNullWithClose{})
 
 		var status idl.Status
 		s.Run(idl.Substep_saving_source_cluster_config, func(streams step.OutStreams) error {
@@ -140,7 +168,7 @@ func TestStepRun(t *testing.T) {
 			}}})
 
 		substepStore := &TestSubstepStore{Status: idl.Status_complete}
-		s := step.New(idl.Step_initialize, server, substepStore, &testutils.DevNullWithClose{})
+		s := step.New(context.Background(), idl.Step_initialize, server, substepStore, &testutils.DevNullWithClose{})
 
 		var called bool
 		s.AlwaysRun(idl.Substep_check_upgrade, func(streams step.OutStreams) error {
@@ -164,7 +192,7 @@ func TestStepRun(t *testing.T) {
 				Status: idl.Status_running,
 			}}}).Times(0)
 
-		s := step.New(idl.Step_initialize, server, &TestSubstepStore{}, &testutils.DevNullWithClose{})
+		s := step.New(context.Background(), idl.Step_initialize, server, &TestSubstepStore{}, &testutils.DevNullWithClose{})
 
 		var called bool
 		s.RunConditionally(idl.Substep_check_upgrade, false, func(streams step.OutStreams) error {
@@ -199,7 +227,7 @@ func TestStepRun(t *testing.T) {
 				Status: idl.Status_complete,
 			}}})
 
-		s := step.New(idl.Step_initialize, server, &TestSubstepStore{}, &testutils.DevNullWithClose{})
+		s := step.New(context.Background(), idl.Step_initialize, server, &TestSubstepStore{}, &testutils.DevNullWithClose{})
 
 		var called bool
 		s.RunConditionally(idl.Substep_check_upgrade, true, func(streams step.OutStreams) error {
@@ -228,7 +256,7 @@ func TestStepRun(t *testing.T) {
 				Status: idl.Status_failed,
 			}}})
 
-		s := step.New(idl.Step_initialize, server, &TestSubstepStore{}, &testutils.DevNullWithClose{})
+		s := step.New(context.Background(), idl.Step_initialize, server, &TestSubstepStore{}, &testutils.DevNullWithClose{})
 
 		var called bool
 		s.Run(idl.Substep_saving_source_cluster_config, func(streams step.OutStreams) error {
@@ -248,7 +276,7 @@ func TestStepRun(t *testing.T) {
 		server := mock_idl.NewMockCliToHub_ExecuteServer(ctrl)
 
 		failingSubstepStore := &TestSubstepStore{WriteErr: errors.New("oops")}
-		s := step.New(idl.Step_initialize, server, failingSubstepStore, &testutils.DevNullWithClose{})
+		s := step.New(context.Background(), idl.Step_initialize, server, failingSubstepStore, &testutils.DevNullWithClose{})
 
 		var called bool
 		s.Run(idl.Substep_check_upgrade, func(streams step.OutStreams) error {
@@ -277,7 +305,7 @@ func TestStepRun(t *testing.T) {
 			}}})
 
 		substepStore := &TestSubstepStore{Status: idl.Status_complete}
-		s := step.New(idl.Step_initialize, server, substepStore, &testutils.DevNullWithClose{})
+		s := step.New(context.Background(), idl.Step_initialize, server, substepStore, &testutils.DevNullWithClose{})
 
 		var called bool
 		s.Run(idl.Substep_check_upgrade, func(streams step.OutStreams) error {
@@ -297,7 +325,7 @@ func TestStepRun(t *testing.T) {
 		server := mock_idl.NewMockCliToHub_ExecuteServer(ctrl)
 		server.EXPECT().Send(gomock.Any()).AnyTimes()
 
-		s := step.New(idl.Step_initialize, server, &TestSubstepStore{}, &testutils.DevNullWithClose{})
+		s := step.New(context.Background(), idl.Step_initialize, server, &TestSubstepStore{}, &testutils.DevNullWithClose{})
 
 		expected := errors.New("oops")
 		s.Run(idl.Substep_saving_source_cluster_config, func(streams step.OutStreams) error {
@@ -327,7 +355,7 @@ func TestStepRun(t *testing.T) {
 		server.EXPECT().Send(gomock.Any()).AnyTimes()
 
 		substepStore := &TestSubstepStore{Status: idl.Status_running}
-		s := step.New(idl.Step_initialize, server, substepStore, &testutils.DevNullWithClose{})
+		s := step.New(context.Background(), idl.Step_initialize, server, substepStore, &testutils.DevNullWithClose{})
 
 		var called bool
 		s.Run(idl.Substep_saving_source_cluster_config, func(streams step.OutStreams) error {
@@ -339,12 +367,222 @@ func TestStepRun(t *testing.T) {
 			t.Error("expected substep to not be called")
 		}
 
-		if s.Err() == nil {
-			t.Error("got nil want err")
+		st, ok := status.FromError(s.Err())
+		if !ok || len(st.Details()) != 1 {
+			t.Fatalf("got %#v want gRPC status error with next actions", s.Err())
+		}
+
+		nextActions, ok := st.Details()[0].(*idl.NextActions)
+		if !ok {
+			t.Fatalf("got details %#v want %T", st.Details()[0], nextActions)
+		}
+
+		expected := "gpupgrade recover --substep saving_source_cluster_config"
+		if !strings.Contains(nextActions.GetNextActions(), expected) {
+			t.Errorf("expected next actions %q to contain %q", nextActions.GetNextActions(), expected)
+		}
+	})
+
+	t.Run("re-runs an idempotent substep that was running", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		server := mock_idl.NewMockCliToHub_ExecuteServer(ctrl)
+		server.EXPECT().Send(gomock.Any()).AnyTimes()
+
+		substepStore := &TestSubstepStore{Status: idl.Status_running}
+		s := step.New(context.Background(), idl.Step_execute, server, substepStore, &testutils.DevNullWithClose{})
+
+		var called bool
+		s.Run(idl.Substep_shutdown_source_cluster, func(streams step.OutStreams) error {
+			called = true
+			return nil
+		}, step.Idempotent())
+
+		if !called {
+			t.Error("expected substep to be called")
+		}
+
+		if s.Err() != nil {
+			t.Errorf("unexpected error %#v", s.Err())
+		}
+
+		if substepStore.Status != idl.Status_complete {
+			t.Errorf("substep status was %s, want %s", substepStore.Status, idl.Status_complete)
+		}
+
+		if !substepStore.Idempotent {
+			t.Errorf("expected the run to be recorded as idempotent")
+		}
+	})
+
+	t.Run("marks a substep interrupted by the client going away as quit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		server := mock_idl.NewMockCliToHub_ExecuteServer(ctrl)
+		server.EXPECT().
+			Send(&idl.Message{Contents: &idl.Message_Status{Status: &idl.SubstepStatus{
+				Step:   idl.Substep_upgrade_primaries,
+				Status: idl.Status_running,
+			}}})
+		server.EXPECT().
+			Send(&idl.Message{Contents: &idl.Message_Status{Status: &idl.SubstepStatus{
+				Step:   idl.Substep_upgrade_primaries,
+				Status: idl.Status_quit,
+			}}})
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		substepStore := &TestSubstepStore{}
+		s := step.New(ctx, idl.Step_execute, server, substepStore, &testutils.DevNullWithClose{})
+
+		s.Run(idl.Substep_upgrade_primaries, func(streams step.OutStreams) error {
+			cancel()
+			return context.Canceled
+		})
+
+		if !errors.Is(s.Err(), context.Canceled) {
+			t.Errorf("got error %#v, want %#v", s.Err(), context.Canceled)
+		}
+
+		if substepStore.Status != idl.Status_quit {
+			t.Errorf("substep status was %s, want %s", substepStore.Status, idl.Status_quit)
+		}
+
+		if substepStore.Runs != 1 {
+			t.Errorf("got %d runs recorded want 1", substepStore.Runs)
+		}
+	})
+
+	t.Run("does not run substeps once the client has gone away", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		server := mock_idl.NewMockCliToHub_ExecuteServer(ctrl)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		substepStore := &TestSubstepStore{}
+		s := step.New(ctx, idl.Step_execute, server, substepStore, &testutils.DevNullWithClose{})
+
+		var called bool
+		s.Run(idl.Substep_upgrade_primaries, func(streams step.OutStreams) error {
+			called = true
+			return nil
+		})
+
+		if called {
+			t.Error("expected substep to not be called")
+		}
+
+		if !errors.Is(s.Err(), context.Canceled) {
+			t.Errorf("got error %#v, want %#v", s.Err(), context.Canceled)
+		}
+
+		if substepStore.Status != idl.Status_unknown_status {
+			t.Errorf("substep status was %s, want %s", substepStore.Status, idl.Status_unknown_status)
+		}
+	})
+
+	t.Run("re-runs a substep that was quit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		server := mock_idl.NewMockCliToHub_ExecuteServer(ctrl)
+		server.EXPECT().Send(gomock.Any()).AnyTimes()
+
+		substepStore := &TestSubstepStore{Status: idl.Status_quit}
+		s := step.New(context.Background(), idl.Step_execute, server, substepStore, &testutils.DevNullWithClose{})
+
+		var called bool
+		s.Run(idl.Substep_upgrade_primaries, func(streams step.OutStreams) error {
+			called = true
+			return nil
+		})
+
+		if !called {
+			t.Error("expected substep to be called")
+		}
+
+		if substepStore.Status != idl.Status_complete {
+			t.Errorf("substep status was %s, want %s", substepStore.Status, idl.Status_complete)
 		}
 	})
 }
 
+func TestStepRunEvents(t *testing.T) {
+	testlog.SetupTestLogger()
+
+	cases := []struct {
+		name     string
+		status   idl.Status
+		result   error
+		expected []logger.EventType
+		err      string
+	}{
+		{
+			name:     "records a completed substep",
+			expected: []logger.EventType{logger.SubstepStarted, logger.SubstepCompleted},
+		},
+		{
+			name:     "records a failed substep along with its error",
+			result:   errors.New("oops"),
+			expected: []logger.EventType{logger.SubstepStarted, logger.SubstepFailed},
+			err:      "oops",
+		},
+		{
+			name:     "records an explicitly skipped substep",
+			result:   step.Skip,
+			expected: []logger.EventType{logger.SubstepStarted, logger.SubstepSkipped},
+		},
+		{
+			name:     "records a previously completed substep as skipped",
+			status:   idl.Status_complete,
+			expected: []logger.EventType{logger.SubstepSkipped},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			output, reset := testlog.SetupTestEvents()
+			defer reset()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			server := mock_idl.NewMockCliToHub_ExecuteServer(ctrl)
+			server.EXPECT().Send(gomock.Any()).AnyTimes()
+
+			s := step.New(context.Background(), idl.Step_execute, server, &TestSubstepStore{Status: c.status}, &testutils.DevNullWithClose{})
+			s.Run(idl.Substep_upgrade_primaries, func(streams step.OutStreams) error {
+				return c.result
+			})
+
+			events := testlog.ParseEvents(t, output)
+
+			var types []logger.EventType
+			for _, event := range events {
+				types = append(types, event.Type)
+
+				if event.Step != "execute" || event.Substep != "upgrade_primaries" {
+					t.Errorf("got step %q substep %q want execute upgrade_primaries", event.Step, event.Substep)
+				}
+			}
+
+			if !reflect.DeepEqual(types, c.expected) {
+				t.Fatalf("got events %v want %v", types, c.expected)
+			}
+
+			last := events[len(events)-1]
+			if last.Error != c.err {
+				t.Errorf("got error %q want %q", last.Error, c.err)
+			}
+		})
+	}
+}
+
 func TestHasStarted(t *testing.T) {
 	stateDir, err := os.MkdirTemp("", "")
 	if err != nil {
@@ -663,7 +901,7 @@ func TestHasCompleted(t *testing.T) {
 func TestStepFinish(t *testing.T) {
 	t.Run("closes the output streams", func(t *testing.T) {
 		streams := &testutils.DevNullWithClose{}
-		s := step.New(idl.Step_initialize, nil, nil, streams)
+		s := step.New(context.Background(), idl.Step_initialize, nil, nil, streams)
 
 		err := s.Finish()
 		if err != nil {
@@ -678,7 +916,7 @@ func TestStepFinish(t *testing.T) {
 	t.Run("returns an error when failing to close the output streams", func(t *testing.T) {
 		expected := errors.New("oops")
 		streams := &testutils.DevNullWithClose{CloseErr: expected}
-		s := step.New(idl.Step_initialize, nil, nil, streams)
+		s := step.New(context.Background(), idl.Step_initialize, nil, nil, streams)
 
 		err := s.Finish()
 		if !errors.Is(err, expected) {
@@ -704,7 +942,7 @@ func TestStepErr(t *testing.T) {
 				Status: idl.Status_complete,
 			}}})
 
-		s := step.New(idl.Step_initialize, server, &TestSubstepStore{}, &testutils.DevNullWithClose{})
+		s := step.New(context.Background(), idl.Step_initialize, server, &TestSubstepStore{}, &testutils.DevNullWithClose{})
 
 		s.Run(idl.Substep_saving_source_cluster_config, func(streams step.OutStreams) error {
 			return nil
@@ -732,7 +970,7 @@ func TestStepErr(t *testing.T) {
 				Status: idl.Status_failed,
 			}}})
 
-		s := step.New(idl.Step_initialize, server, &TestSubstepStore{}, &testutils.DevNullWithClose{})
+		s := step.New(context.Background(), idl.Step_initialize, server, &TestSubstepStore{}, &testutils.DevNullWithClose{})
 
 		expected := os.ErrPermission
 		s.Run(idl.Substep_saving_source_cluster_config, func(streams step.OutStreams) error {
@@ -762,7 +1000,7 @@ func TestStepErr(t *testing.T) {
 				Status: idl.Status_failed,
 			}}})
 
-		s := step.New(idl.Step_initialize, server, &TestSubstepStore{}, &testutils.DevNullWithClose{})
+		s := step.New(context.Background(), idl.Step_initialize, server, &TestSubstepStore{}, &testutils.DevNullWithClose{})
 
 		expected := utils.NewNextActionErr(os.ErrPermission, "change permissions to gpadmin")
 		s.Run(idl.Substep_saving_source_cluster_config, func(streams step.OutStreams) error {
@@ -803,7 +1041,7 @@ func TestStepErr(t *testing.T) {
 				Status: idl.Status_failed,
 			}}})
 
-		s := step.New(idl.Step_initialize, server, &TestSubstepStore{}, &testutils.DevNullWithClose{})
+		s := step.New(context.Background(), idl.Step_initialize, server, &TestSubstepStore{}, &testutils.DevNullWithClose{})
 
 		expected1 := utils.NewNextActionErr(os.ErrPermission, "change permissions to gpadmin")
 		expected2 := utils.NewNextActionErr(os.ErrDeadlineExceeded, "stop and rerun")
@@ -839,15 +1077,34 @@ func TestStepErr(t *testing.T) {
 }
 
 type TestSubstepStore struct {
-	Status   idl.Status
-	WriteErr error
+	Status     idl.Status
+	Idempotent bool
+	WriteErr   error
+	RunErr     error
+	Runs       int
 }
 
 func (t *TestSubstepStore) Read(_ idl.Step, substep idl.Substep) (idl.Status, error) {
 	return t.Status, nil
 }
 
+func (t *TestSubstepStore) ReadRun(_ idl.Step, substep idl.Substep) (step.SubstepRun, error) {
+	return step.SubstepRun{Status: step.PrettyStatus{Status: t.Status}, Idempotent: t.Idempotent}, nil
+}
+
 func (t *TestSubstepStore) Write(_ idl.Step, substep idl.Substep, status idl.Status) (err error) {
 	t.Status = status
 	return t.WriteErr
 }
+
+func (t *TestSubstepStore) Start(_ idl.Step, substep idl.Substep, idempotent bool) error {
+	t.Status = idl.Status_running
+	t.Idempotent = idempotent
+	return t.WriteErr
+}
+
+func (t *TestSubstepStore) WriteRun(_ idl.Step, substep idl.Substep, duration time.Duration, err error) error {
+	t.Runs++
+	t.RunErr = err
+	return nil
+}
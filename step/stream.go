@@ -23,6 +23,32 @@ type OutStreamsCloser interface {
 	Close() error
 }
 
+// SegmentProgressSender is implemented by streams that relay per-segment
+// progress to the client in addition to output.
+type SegmentProgressSender interface {
+	SendSegmentProgress(progress *idl.SegmentProgress)
+}
+
+// SendSegmentProgress relays the progress when the streams support it.
+func SendSegmentProgress(streams OutStreams, progress *idl.SegmentProgress) {
+	if sender, ok := streams.(SegmentProgressSender); ok {
+		sender.SendSegmentProgress(progress)
+	}
+}
+
+// AgentLostSender is implemented by streams that notify the client when an
+// agent is lost.
+type AgentLostSender interface {
+	SendAgentLost(lost *idl.AgentLost)
+}
+
+// SendAgentLost notifies the client when the streams support it.
+func SendAgentLost(streams OutStreams, lost *idl.AgentLost) {
+	if sender, ok := streams.(AgentLostSender); ok {
+		sender.SendAgentLost(lost)
+	}
+}
+
 // DevNullStream provides an implementation of OutStreams that drops
 // all writes to it.
 var DevNullStream = devNullStream{}
@@ -112,6 +138,37 @@ func (m *multiplexedStream) Close() error {
 	return nil
 }
 
+// SendSegmentProgress sends the progress to the client. Like output, send
+// errors are logged and otherwise ignored.
+func (m *multiplexedStream) SendSegmentProgress(progress *idl.SegmentProgress) {
+	m.send(&idl.Message{
+		Contents: &idl.Message_SegmentProgress{SegmentProgress: progress},
+	})
+}
+
+// SendAgentLost notifies the client that an agent was lost. Like output, send
+// errors are logged and otherwise ignored.
+func (m *multiplexedStream) SendAgentLost(lost *idl.AgentLost) {
+	m.send(&idl.Message{
+		Contents: &idl.Message_AgentLost{AgentLost: lost},
+	})
+}
+
+func (m *multiplexedStream) send(msg *idl.Message) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.stream == nil {
+		return
+	}
+
+	err := m.stream.Send(msg)
+	if err != nil {
+		log.Printf("halting client stream: %v", err)
+		m.stream = nil
+	}
+}
+
 type streamWriter struct {
 	*multiplexedStream
 	cType idl.Chunk_Type
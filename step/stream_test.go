@@ -172,6 +172,70 @@ func TestMultiplexedStream(t *testing.T) {
 	})
 }
 
+func TestSendSegmentProgress(t *testing.T) {
+	testlog.SetupTestLogger()
+
+	progress := &idl.SegmentProgress{Host: "sdw1", ContentID: 1, Status: idl.Status_running, Progress: "copying"}
+
+	t.Run("sends segment progress to the client", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockStream := mock_idl.NewMockCliToHub_ExecuteServer(ctrl)
+		mockStream.EXPECT().
+			Send(&idl.Message{Contents: &idl.Message_SegmentProgress{SegmentProgress: progress}}).
+			Times(1)
+
+		var buf bytes.Buffer
+		SendSegmentProgress(newMultiplexedStream(mockStream, &buf), progress)
+
+		if buf.Len() != 0 {
+			t.Errorf("got %q want nothing written to the local io.Writer", buf.String())
+		}
+	})
+
+	t.Run("stops sending after the first failure", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockStream := mock_idl.NewMockCliToHub_ExecuteServer(ctrl)
+		mockStream.EXPECT().
+			Send(gomock.Any()).
+			Return(errors.New("error during send")).
+			Times(1)
+
+		stream := newMultiplexedStream(mockStream, io.Discard)
+		SendSegmentProgress(stream, progress)
+		SendSegmentProgress(stream, progress)
+	})
+
+	t.Run("ignores streams that do not support segment progress", func(t *testing.T) {
+		SendSegmentProgress(DevNullStream, progress)
+	})
+}
+
+func TestSendAgentLost(t *testing.T) {
+	testlog.SetupTestLogger()
+
+	lost := &idl.AgentLost{Host: "sdw1"}
+
+	t.Run("notifies the client that an agent was lost", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockStream := mock_idl.NewMockCliToHub_ExecuteServer(ctrl)
+		mockStream.EXPECT().
+			Send(&idl.Message{Contents: &idl.Message_AgentLost{AgentLost: lost}}).
+			Times(1)
+
+		SendAgentLost(newMultiplexedStream(mockStream, io.Discard), lost)
+	})
+
+	t.Run("ignores streams that do not support notifying the client", func(t *testing.T) {
+		SendAgentLost(DevNullStream, lost)
+	})
+}
+
 // failingWriter is an io.Writer for which all calls to Write() return an error.
 type failingWriter struct {
 	err error
@@ -4,9 +4,12 @@
 package step
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"time"
 
 	"golang.org/x/xerrors"
 
@@ -16,7 +19,10 @@ import (
 
 type SubstepStore interface {
 	Read(idl.Step, idl.Substep) (idl.Status, error)
+	ReadRun(idl.Step, idl.Substep) (SubstepRun, error)
 	Write(idl.Step, idl.Substep, idl.Status) error
+	Start(idl.Step, idl.Substep, bool) error
+	WriteRun(idl.Step, idl.Substep, time.Duration, error) error
 }
 
 // SubstepFileStore implements SubstepStore by providing persistent storage on disk.
@@ -37,7 +43,52 @@ func NewSubstepStoreUsingFile(path string) *SubstepFileStore {
 	return &SubstepFileStore{path}
 }
 
-type prettyMap = map[string]map[string]PrettyStatus
+type substepMap = map[string]map[string]*SubstepEntry
+
+// SubstepRun records a single attempt at running a substep.
+type SubstepRun struct {
+	Status    PrettyStatus    `json:"status"`
+	StartTime *time.Time      `json:"start_time,omitempty"`
+	EndTime   *time.Time      `json:"end_time,omitempty"`
+	Duration  *PrettyDuration `json:"duration,omitempty"`
+	Error     string          `json:"error,omitempty"`
+
+	// Idempotent records whether the substep was declared safe to re-run
+	// should this run be interrupted.
+	Idempotent bool `json:"idempotent,omitempty"`
+}
+
+// SubstepEntry is the on-disk representation of a substep. The latest run is
+// stored inline while earlier runs, such as failed attempts that were later
+// retried, are kept in History oldest first.
+type SubstepEntry struct {
+	SubstepRun
+	History []SubstepRun `json:"history,omitempty"`
+}
+
+// UnmarshalJSON also accepts the older format where only the status was
+// stored for each substep.
+func (e *SubstepEntry) UnmarshalJSON(buf []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(buf), []byte(`"`)) {
+		var status PrettyStatus
+		if err := json.Unmarshal(buf, &status); err != nil {
+			return err
+		}
+
+		*e = SubstepEntry{SubstepRun: SubstepRun{Status: status}}
+		return nil
+	}
+
+	// Use a type without the UnmarshalJSON method to avoid infinite recursion.
+	type substepEntry SubstepEntry
+	var entry substepEntry
+	if err := json.Unmarshal(buf, &entry); err != nil {
+		return err
+	}
+
+	*e = SubstepEntry(entry)
+	return nil
+}
 
 // PrettyStatus exists only to write a string description of idl.Status to
 // the JSON representation, instead of an integer.
@@ -61,13 +112,33 @@ func (p *PrettyStatus) UnmarshalText(buf []byte) error {
 	return nil
 }
 
-func (f *SubstepFileStore) load() (prettyMap, error) {
+// PrettyDuration exists only to write a string description of time.Duration
+// to the JSON representation, instead of an integer.
+type PrettyDuration struct {
+	time.Duration
+}
+
+func (p PrettyDuration) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *PrettyDuration) UnmarshalText(buf []byte) error {
+	duration, err := time.ParseDuration(string(buf))
+	if err != nil {
+		return err
+	}
+
+	p.Duration = duration
+	return nil
+}
+
+func (f *SubstepFileStore) load() (substepMap, error) {
 	data, err := os.ReadFile(f.path)
 	if err != nil {
 		return nil, err
 	}
 
-	var substeps prettyMap
+	var substeps substepMap
 	err = json.Unmarshal(data, &substeps)
 	if err != nil {
 		return nil, err
@@ -87,7 +158,23 @@ func (f *SubstepFileStore) ReadStep(step idl.Step) (map[string]PrettyStatus, err
 		return nil, nil
 	}
 
-	return sectionMap, nil
+	statuses := make(map[string]PrettyStatus, len(sectionMap))
+	for substep, entry := range sectionMap {
+		statuses[substep] = entry.Status
+	}
+
+	return statuses, nil
+}
+
+// ReadEntries returns the entry of each substep of the step including the
+// history of earlier runs.
+func (f *SubstepFileStore) ReadEntries(step idl.Step) (map[string]*SubstepEntry, error) {
+	steps, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return steps[step.String()], nil
 }
 
 func (f *SubstepFileStore) Read(step idl.Step, substep idl.Substep) (idl.Status, error) {
@@ -104,20 +191,126 @@ func (f *SubstepFileStore) Read(step idl.Step, substep idl.Substep) (idl.Status,
 	return status.Status, nil
 }
 
-// Write atomically updates the status file.
+// ReadRun returns the latest run of the substep. Its status is unknown when
+// the substep has not run.
+func (f *SubstepFileStore) ReadRun(step idl.Step, substep idl.Substep) (SubstepRun, error) {
+	entries, err := f.ReadEntries(step)
+	if err != nil {
+		return SubstepRun{}, err
+	}
+
+	entry, ok := entries[substep.String()]
+	if !ok {
+		return SubstepRun{}, nil
+	}
+
+	return entry.SubstepRun, nil
+}
+
+// Write atomically updates the status file. Writing a running status begins a
+// new run of the substep moving any previous run into its history.
 // Load the latest values from the filesystem, rather than storing
 // in-memory on a struct to avoid having two sources of truth.
-func (f *SubstepFileStore) Write(step idl.Step, substep idl.Substep, status idl.Status) (err error) {
+func (f *SubstepFileStore) Write(step idl.Step, substep idl.Substep, status idl.Status) error {
+	return f.write(step, substep, status, false)
+}
+
+// Start begins a new run of the substep by marking it running, and records
+// whether the substep is idempotent.
+func (f *SubstepFileStore) Start(step idl.Step, substep idl.Substep, idempotent bool) error {
+	return f.write(step, substep, idl.Status_running, idempotent)
+}
+
+func (f *SubstepFileStore) write(step idl.Step, substep idl.Substep, status idl.Status, idempotent bool) error {
+	steps, err := f.load()
+	if err != nil {
+		return err
+	}
+
+	entry := getEntry(steps, step, substep)
+	if status == idl.Status_running {
+		if entry.Status.Status != idl.Status_unknown_status {
+			entry.History = append(entry.History, entry.SubstepRun)
+		}
+
+		now := time.Now()
+		entry.SubstepRun = SubstepRun{StartTime: &now, Idempotent: idempotent}
+	}
+	entry.Status = PrettyStatus{status}
+
+	return f.save(steps)
+}
+
+// WriteRun records the end time, duration, and error of the latest run of
+// the substep.
+func (f *SubstepFileStore) WriteRun(step idl.Step, substep idl.Substep, duration time.Duration, runErr error) error {
 	steps, err := f.load()
 	if err != nil {
 		return err
 	}
 
+	entry := getEntry(steps, step, substep)
+
+	now := time.Now()
+	entry.EndTime = &now
+	entry.Duration = &PrettyDuration{duration}
+	entry.Error = ""
+	if runErr != nil {
+		entry.Error = runErr.Error()
+	}
+
+	return f.save(steps)
+}
+
+// FailRunning marks the substeps of the step that are still running as failed
+// with the error, such as when the hub stops while they are in progress. It
+// returns the substeps that were marked.
+func (f *SubstepFileStore) FailRunning(step idl.Step, runErr error) ([]string, error) {
+	steps, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var failed []string
+	for substep, entry := range steps[step.String()] {
+		if entry.Status.Status != idl.Status_running {
+			continue
+		}
+
+		now := time.Now()
+		entry.Status = PrettyStatus{idl.Status_failed}
+		entry.EndTime = &now
+		if entry.StartTime != nil {
+			entry.Duration = &PrettyDuration{now.Sub(*entry.StartTime)}
+		}
+		entry.Error = runErr.Error()
+
+		failed = append(failed, substep)
+	}
+
+	if len(failed) == 0 {
+		return nil, nil
+	}
+
+	sort.Strings(failed)
+	return failed, f.save(steps)
+}
+
+func getEntry(steps substepMap, step idl.Step, substep idl.Substep) *SubstepEntry {
 	if _, ok := steps[step.String()]; !ok {
-		steps[step.String()] = make(map[string]PrettyStatus)
+		steps[step.String()] = make(map[string]*SubstepEntry)
 	}
-	steps[step.String()][substep.String()] = PrettyStatus{status}
 
+	entry, ok := steps[step.String()][substep.String()]
+	if !ok {
+		entry = &SubstepEntry{}
+		steps[step.String()][substep.String()] = entry
+	}
+
+	return entry
+}
+
+func (f *SubstepFileStore) save(steps substepMap) error {
 	data, err := json.MarshalIndent(steps, "", "  ") // pretty print JSON
 	if err != nil {
 		return err
@@ -5,10 +5,12 @@ package step_test
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/step"
@@ -199,16 +201,263 @@ func TestFileStore(t *testing.T) {
 		defer f.Close()
 
 		dec := json.NewDecoder(f)
-		raw := make(map[string]map[string]string)
+		raw := make(map[string]map[string]map[string]interface{})
 		if err := dec.Decode(&raw); err != nil {
 			t.Fatalf("decoding statuses: %+v", err)
 		}
 
 		key := substep.String()
-		if raw[initialize.String()][key] != status.String() {
-			t.Errorf("status[%q][%q] = %q, want %q", initialize, key, raw[initialize.String()][key], status.String())
+		if raw[initialize.String()][key]["status"] != status.String() {
+			t.Errorf("status[%q][%q] = %q, want %q", initialize, key, raw[initialize.String()][key]["status"], status.String())
 		}
 	})
+
+	t.Run("reads files that only store the status of each substep", func(t *testing.T) {
+		testutils.MustWriteToFile(t, path, `{"initialize": {"check_upgrade": "complete", "init_target_cluster": "failed"}}`)
+
+		status, err := fs.Read(initialize, idl.Substep_check_upgrade)
+		if err != nil {
+			t.Errorf("Read() returned error %#v", err)
+		}
+
+		if status != idl.Status_complete {
+			t.Errorf("read %v, want %v", status, idl.Status_complete)
+		}
+
+		err = fs.Write(initialize, idl.Substep_init_target_cluster, idl.Status_running)
+		if err != nil {
+			t.Fatalf("Write() returned error %#v", err)
+		}
+
+		status, err = fs.Read(initialize, idl.Substep_init_target_cluster)
+		if err != nil {
+			t.Errorf("Read() returned error %#v", err)
+		}
+
+		if status != idl.Status_running {
+			t.Errorf("read %v, want %v", status, idl.Status_running)
+		}
+	})
+
+	t.Run("records the start time, end time, duration, and error of each run", func(t *testing.T) {
+		clear(t, path)
+
+		substep := idl.Substep_check_upgrade
+		start := time.Now()
+
+		mustWriteRun(t, fs, initialize, substep, idl.Status_failed, 2*time.Second, errors.New("pg_upgrade failed"))
+		mustWriteRun(t, fs, initialize, substep, idl.Status_complete, 3*time.Second, nil)
+
+		entries := readEntries(t, path)
+		entry := entries[initialize.String()][substep.String()]
+
+		if entry.Status.Status != idl.Status_complete {
+			t.Errorf("got status %v want %v", entry.Status, idl.Status_complete)
+		}
+
+		if entry.StartTime == nil || entry.StartTime.Before(start) {
+			t.Errorf("got start time %v want after %v", entry.StartTime, start)
+		}
+
+		if entry.EndTime == nil || entry.EndTime.Before(*entry.StartTime) {
+			t.Errorf("got end time %v want after %v", entry.EndTime, entry.StartTime)
+		}
+
+		if entry.Duration == nil || entry.Duration.Duration != 3*time.Second {
+			t.Errorf("got duration %v want %v", entry.Duration, 3*time.Second)
+		}
+
+		if entry.Error != "" {
+			t.Errorf("got error %q want none", entry.Error)
+		}
+
+		if len(entry.History) != 1 {
+			t.Fatalf("got %d runs in history want 1", len(entry.History))
+		}
+
+		previous := entry.History[0]
+		if previous.Status.Status != idl.Status_failed {
+			t.Errorf("got status %v want %v", previous.Status, idl.Status_failed)
+		}
+
+		if previous.Duration == nil || previous.Duration.Duration != 2*time.Second {
+			t.Errorf("got duration %v want %v", previous.Duration, 2*time.Second)
+		}
+
+		if previous.Error != "pg_upgrade failed" {
+			t.Errorf("got error %q want %q", previous.Error, "pg_upgrade failed")
+		}
+	})
+
+	t.Run("Start begins a new run recording whether the substep is idempotent", func(t *testing.T) {
+		clear(t, path)
+
+		substep := idl.Substep_check_upgrade
+		mustWriteRun(t, fs, initialize, substep, idl.Status_failed, time.Second, errors.New("oops"))
+
+		err := fs.Start(initialize, substep, true)
+		if err != nil {
+			t.Fatalf("Start() returned error %#v", err)
+		}
+
+		run, err := fs.ReadRun(initialize, substep)
+		if err != nil {
+			t.Fatalf("ReadRun() returned error %#v", err)
+		}
+
+		if run.Status.Status != idl.Status_running || !run.Idempotent || run.StartTime == nil || run.Error != "" {
+			t.Errorf("unexpected run %+v", run)
+		}
+
+		entry := readEntries(t, path)[initialize.String()][substep.String()]
+		if len(entry.History) != 1 || entry.History[0].Idempotent {
+			t.Errorf("unexpected history %+v", entry.History)
+		}
+	})
+
+	t.Run("ReadRun returns an unknown status if the substep has not run", func(t *testing.T) {
+		clear(t, path)
+
+		run, err := fs.ReadRun(initialize, idl.Substep_check_upgrade)
+		if err != nil {
+			t.Fatalf("ReadRun() returned error %#v", err)
+		}
+
+		if run.Status.Status != idl.Status_unknown_status || run.Idempotent {
+			t.Errorf("unexpected run %+v", run)
+		}
+	})
+
+	t.Run("ReadEntries returns the runs of each substep of the step", func(t *testing.T) {
+		clear(t, path)
+
+		substep := idl.Substep_check_upgrade
+		mustWriteRun(t, fs, initialize, substep, idl.Status_failed, time.Second, errors.New("pg_upgrade failed"))
+		mustWriteRun(t, fs, initialize, substep, idl.Status_complete, time.Second, nil)
+		mustWriteRun(t, fs, idl.Step_execute, idl.Substep_upgrade_master, idl.Status_complete, time.Second, nil)
+
+		entries, err := fs.ReadEntries(initialize)
+		if err != nil {
+			t.Fatalf("ReadEntries() returned error %#v", err)
+		}
+
+		if len(entries) != 1 {
+			t.Fatalf("got %d entries want 1", len(entries))
+		}
+
+		entry := entries[substep.String()]
+		if entry.Status.Status != idl.Status_complete || len(entry.History) != 1 || entry.History[0].Error != "pg_upgrade failed" {
+			t.Errorf("unexpected entry %+v", entry)
+		}
+
+		entries, err = fs.ReadEntries(idl.Step_finalize)
+		if err != nil {
+			t.Fatalf("ReadEntries() returned error %#v", err)
+		}
+
+		if entries != nil {
+			t.Errorf("got %+v want nil", entries)
+		}
+	})
+
+	t.Run("does not add a run to the history when only the status is updated", func(t *testing.T) {
+		clear(t, path)
+
+		substep := idl.Substep_check_upgrade
+		mustWriteRun(t, fs, initialize, substep, idl.Status_failed, time.Second, errors.New("oops"))
+
+		err := fs.Write(initialize, substep, idl.Status_complete)
+		if err != nil {
+			t.Fatalf("Write() returned error %#v", err)
+		}
+
+		entry := readEntries(t, path)[initialize.String()][substep.String()]
+		if entry.Status.Status != idl.Status_complete || entry.Error != "oops" || len(entry.History) != 0 {
+			t.Errorf("unexpected entry %+v", entry)
+		}
+	})
+
+	t.Run("FailRunning marks the running substeps of the step failed", func(t *testing.T) {
+		clear(t, path)
+
+		mustWriteRun(t, fs, initialize, idl.Substep_check_upgrade, idl.Status_complete, time.Second, nil)
+		mustWrite(t, fs, initialize, idl.Substep_init_target_cluster, idl.Status_running)
+		mustWrite(t, fs, idl.Step_execute, idl.Substep_upgrade_master, idl.Status_running)
+
+		failed, err := fs.FailRunning(initialize, errors.New("interrupted"))
+		if err != nil {
+			t.Fatalf("FailRunning() returned error %#v", err)
+		}
+
+		expected := []string{idl.Substep_init_target_cluster.String()}
+		if !reflect.DeepEqual(failed, expected) {
+			t.Errorf("got failed substeps %v want %v", failed, expected)
+		}
+
+		entries := readEntries(t, path)
+
+		entry := entries[initialize.String()][idl.Substep_init_target_cluster.String()]
+		if entry.Status.Status != idl.Status_failed || entry.Error != "interrupted" || entry.EndTime == nil || entry.Duration == nil {
+			t.Errorf("unexpected entry %+v", entry)
+		}
+
+		entry = entries[initialize.String()][idl.Substep_check_upgrade.String()]
+		if entry.Status.Status != idl.Status_complete {
+			t.Errorf("got status %v want %v", entry.Status, idl.Status_complete)
+		}
+
+		// Substeps of other steps are left as is.
+		entry = entries[idl.Step_execute.String()][idl.Substep_upgrade_master.String()]
+		if entry.Status.Status != idl.Status_running {
+			t.Errorf("got status %v want %v", entry.Status, idl.Status_running)
+		}
+	})
+
+	t.Run("FailRunning does nothing when no substeps are running", func(t *testing.T) {
+		clear(t, path)
+
+		mustWriteRun(t, fs, initialize, idl.Substep_check_upgrade, idl.Status_complete, time.Second, nil)
+
+		failed, err := fs.FailRunning(initialize, errors.New("interrupted"))
+		if err != nil {
+			t.Fatalf("FailRunning() returned error %#v", err)
+		}
+
+		if len(failed) != 0 {
+			t.Errorf("got failed substeps %v want none", failed)
+		}
+	})
+}
+
+func mustWriteRun(t *testing.T, fs *step.SubstepFileStore, stepName idl.Step, substep idl.Substep, status idl.Status, duration time.Duration, runErr error) {
+	t.Helper()
+
+	err := fs.Write(stepName, substep, idl.Status_running)
+	if err != nil {
+		t.Fatalf("Write() returned error %#v", err)
+	}
+
+	err = fs.Write(stepName, substep, status)
+	if err != nil {
+		t.Fatalf("Write() returned error %#v", err)
+	}
+
+	err = fs.WriteRun(stepName, substep, duration, runErr)
+	if err != nil {
+		t.Fatalf("WriteRun() returned error %#v", err)
+	}
+}
+
+func readEntries(t *testing.T, path string) map[string]map[string]step.SubstepEntry {
+	t.Helper()
+
+	var entries map[string]map[string]step.SubstepEntry
+	err := json.Unmarshal([]byte(testutils.MustReadFile(t, path)), &entries)
+	if err != nil {
+		t.Fatalf("unmarshal %q: %v", path, err)
+	}
+
+	return entries
 }
 
 // clear writes an empty JSON map to the given SubstepFileStore backing path.
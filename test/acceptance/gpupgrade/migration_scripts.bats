@@ -62,7 +62,7 @@ teardown() {
     echo "$output"
     [ "$status" -ne 0 ] || fail "expected initialize to fail due to pg_upgrade check"
 
-    egrep "\"check_upgrade\": \"failed\"" $GPUPGRADE_HOME/substeps.json
+    [ "$(jq -r '.initialize.check_upgrade.status' "$GPUPGRADE_HOME"/substeps.json)" = "failed" ] || fail "expected check_upgrade to be failed"
     egrep "^Checking.*fatal$" ~/gpAdminLogs/gpupgrade/pg_upgrade/p-1/pg_upgrade_internal.log
 
     PGOPTIONS='--client-min-messages=warning' $PSQL -v ON_ERROR_STOP=1 -d testdb -f "${SEED_DIR}"/"${VERSION_SEED_DIR}"/test/drop_unfixable_objects.sql
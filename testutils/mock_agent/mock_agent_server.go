@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net"
 	"sync"
+	"time"
 
 	"github.com/greenplum-db/gpupgrade/idl"
 
@@ -24,6 +25,10 @@ type MockAgentServer struct {
 	DeleteDataDirectoriesRequest         *idl.DeleteDataDirectoriesRequest
 
 	Err chan error
+
+	// Delays are waited out by successive CheckDiskSpace,
+	// DeleteDataDirectories, and GetInfo calls, or until the call is canceled.
+	Delays chan time.Duration
 }
 
 // NewMockAgentServer starts a locally running Agent server and returns a struct
@@ -43,6 +48,7 @@ func NewMockAgentServer() (*MockAgentServer, func(ctx context.Context, target st
 		addr:       lis.Addr(),
 		grpcServer: grpc.NewServer(),
 		Err:        make(chan error, 10000),
+		Delays:     make(chan time.Duration, 10000),
 	}
 
 	idl.RegisterAgentServer(mockServer.grpcServer, mockServer)
@@ -66,13 +72,22 @@ func (m *MockAgentServer) CreateBackupDirectory(context.Context, *idl.CreateBack
 	return &idl.CreateBackupDirectoryReply{}, nil
 }
 
-func (m *MockAgentServer) CheckDiskSpace(context.Context, *idl.CheckSegmentDiskSpaceRequest) (*idl.CheckDiskSpaceReply, error) {
+func (m *MockAgentServer) CheckDiskSpace(ctx context.Context, in *idl.CheckSegmentDiskSpaceRequest) (*idl.CheckDiskSpaceReply, error) {
 	m.increaseCalls()
 
+	if err := m.delay(ctx); err != nil {
+		return nil, err
+	}
+
 	return &idl.CheckDiskSpaceReply{}, nil
 }
 
-func (m *MockAgentServer) UpgradePrimaries(ctx context.Context, in *idl.UpgradePrimariesRequest) (*idl.UpgradePrimariesReply, error) {
+func (m *MockAgentServer) CheckPorts(context.Context, *idl.CheckPortsRequest) (*idl.CheckPortsReply, error) {
+	m.increaseCalls()
+	return &idl.CheckPortsReply{}, nil
+}
+
+func (m *MockAgentServer) UpgradePrimaries(in *idl.UpgradePrimariesRequest, stream idl.Agent_UpgradePrimariesServer) error {
 	m.increaseCalls()
 
 	m.mu.Lock()
@@ -84,7 +99,7 @@ func (m *MockAgentServer) UpgradePrimaries(ctx context.Context, in *idl.UpgradeP
 		err = <-m.Err
 	}
 
-	return &idl.UpgradePrimariesReply{}, err
+	return err
 }
 
 func (m *MockAgentServer) RenameDirectories(context.Context, *idl.RenameDirectoriesRequest) (*idl.RenameDirectoriesReply, error) {
@@ -92,8 +107,18 @@ func (m *MockAgentServer) RenameDirectories(context.Context, *idl.RenameDirector
 	return &idl.RenameDirectoriesReply{}, nil
 }
 
-func (m *MockAgentServer) DeleteDataDirectories(context.Context, *idl.DeleteDataDirectoriesRequest) (*idl.DeleteDataDirectoriesReply, error) {
+func (m *MockAgentServer) AlreadyRenamedDirectories(context.Context, *idl.RenameDirectoriesRequest) (*idl.AlreadyRenamedDirectoriesReply, error) {
 	m.increaseCalls()
+	return &idl.AlreadyRenamedDirectoriesReply{}, nil
+}
+
+func (m *MockAgentServer) DeleteDataDirectories(ctx context.Context, in *idl.DeleteDataDirectoriesRequest) (*idl.DeleteDataDirectoriesReply, error) {
+	m.increaseCalls()
+
+	if err := m.delay(ctx); err != nil {
+		return nil, err
+	}
+
 	return &idl.DeleteDataDirectoriesReply{}, nil
 }
 
@@ -142,6 +167,21 @@ func (m *MockAgentServer) increaseCalls() {
 	m.numCalls++
 }
 
+// delay waits out the next queued delay, if any, or until ctx is done.
+func (m *MockAgentServer) delay(ctx context.Context) error {
+	select {
+	case d := <-m.Delays:
+		select {
+		case <-time.After(d):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	default:
+		return nil
+	}
+}
+
 func (m *MockAgentServer) NumberOfCalls() int {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -166,6 +206,31 @@ func (m *MockAgentServer) CreateRecoveryConf(context context.Context, in *idl.Cr
 	return &idl.CreateRecoveryConfReply{}, nil
 }
 
+func (m *MockAgentServer) GetInfo(ctx context.Context, in *idl.GetInfoRequest) (*idl.GetInfoReply, error) {
+	if err := m.delay(ctx); err != nil {
+		return nil, err
+	}
+
+	return &idl.GetInfoReply{Info: &idl.AgentInfo{}}, nil
+}
+
+func (m *MockAgentServer) Heartbeat(in *idl.HeartbeatRequest, stream idl.Agent_HeartbeatServer) error {
+	ticker := time.NewTicker(in.GetInterval().AsDuration())
+	defer ticker.Stop()
+
+	for {
+		if err := stream.Send(&idl.HeartbeatReply{}); err != nil {
+			return err
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
 func (m *MockAgentServer) AddReplicationEntries(context context.Context, in *idl.AddReplicationEntriesRequest) (*idl.AddReplicationEntriesReply, error) {
 	return &idl.AddReplicationEntriesReply{}, nil
 }
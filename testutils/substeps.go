@@ -0,0 +1,65 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package testutils
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+// MustGetSubstepCalls parses the Go source file and returns the Run,
+// AlwaysRun, and RunConditionally calls made by the named function in the
+// order they appear, such as "AlwaysRun(start_target_cluster)". It is used to
+// check that a step and its dry run plan run the same substeps.
+func MustGetSubstepCalls(t *testing.T, path string, funcName string) []string {
+	t.Helper()
+
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil {
+		t.Fatalf("parsing %q: %v", path, err)
+	}
+
+	var body *ast.BlockStmt
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == funcName {
+			body = fn.Body
+		}
+	}
+
+	if body == nil {
+		t.Fatalf("function %q not found in %q", funcName, path)
+	}
+
+	var calls []string
+	ast.Inspect(body, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+
+		method, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		switch method.Sel.Name {
+		case "Run", "AlwaysRun", "RunConditionally":
+		default:
+			return true
+		}
+
+		substep, ok := call.Args[0].(*ast.SelectorExpr)
+		if !ok || !strings.HasPrefix(substep.Sel.Name, "Substep_") {
+			return true
+		}
+
+		calls = append(calls, method.Sel.Name+"("+strings.TrimPrefix(substep.Sel.Name, "Substep_")+")")
+		return true
+	})
+
+	return calls
+}
@@ -4,10 +4,14 @@
 package testlog
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
 	"log"
 	"strings"
 	"testing"
 
+	"github.com/greenplum-db/gpupgrade/utils/logger"
 	"github.com/greenplum-db/gpupgrade/utils/syncbuf"
 )
 
@@ -18,6 +22,39 @@ func SetupTestLogger() *syncbuf.Syncbuf {
 	return output
 }
 
+// SetupTestEvents captures the event log. Call the returned function to stop
+// capturing.
+func SetupTestEvents() (*syncbuf.Syncbuf, func()) {
+	output := syncbuf.New()
+	logger.SetEventOutput("test", output)
+
+	return output, func() {
+		logger.SetEventOutput("", nil)
+	}
+}
+
+// ParseEvents returns the events written to the event log.
+func ParseEvents(t *testing.T, output *syncbuf.Syncbuf) []logger.Event {
+	t.Helper()
+
+	var events []logger.Event
+	scanner := bufio.NewScanner(bytes.NewReader(output.Bytes()))
+	for scanner.Scan() {
+		var event logger.Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("unmarshal event %q: %v", scanner.Text(), err)
+		}
+
+		events = append(events, event)
+	}
+
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("scan events: %v", err)
+	}
+
+	return events
+}
+
 func VerifyLogContains(t *testing.T, testlog *syncbuf.Syncbuf, expected string) {
 	t.Helper()
 	verifyLog(t, testlog, expected, true)
@@ -4,6 +4,7 @@
 package upgrade
 
 import (
+	"context"
 	"io"
 	"log"
 	"os/exec"
@@ -14,6 +15,7 @@ import (
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/testutils/exectest"
 	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/logger"
 )
 
 const DefaultHubPort = 7527
@@ -22,7 +24,9 @@ const DefaultDynamicLibraryPath = "$libdir"
 
 var pgupgradeCmd = exec.Command
 
-func Run(stdout, stderr io.Writer, opts *idl.PgOptions) error {
+// Run executes pg_upgrade, which is killed along with the postgres instances
+// it started when ctx is done.
+func Run(ctx context.Context, stdout, stderr io.Writer, opts *idl.PgOptions) error {
 	upgradeDir, err := utils.GetPgUpgradeDir(opts.GetRole(), opts.GetContentID())
 	if err != nil {
 		return err
@@ -90,7 +94,9 @@ func Run(stdout, stderr io.Writer, opts *idl.PgOptions) error {
 
 	log.Printf("Executing: %q", cmd.String())
 
-	return cmd.Run()
+	return logger.LogCommand(cmd.Args, func() error {
+		return utils.RunCommandContext(ctx, cmd)
+	})
 }
 
 func SetPgUpgradeCommand(cmdFunc exectest.Command) {
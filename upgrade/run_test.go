@@ -6,6 +6,7 @@ package upgrade_test
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -22,6 +23,7 @@ import (
 	"github.com/greenplum-db/gpupgrade/testutils/testlog"
 	"github.com/greenplum-db/gpupgrade/upgrade"
 	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/logger"
 )
 
 // Prints the strings "stdout" and "stderr" to the respective streams.
@@ -87,7 +89,7 @@ func TestRun(t *testing.T) {
 			TargetVersion: "6.20.0",
 		}
 
-		err := upgrade.Run(nil, nil, opts)
+		err := upgrade.Run(context.Background(), nil, nil, opts)
 		if err != nil {
 			t.Fatalf("unexpected error %+v", err)
 		}
@@ -114,7 +116,7 @@ func TestRun(t *testing.T) {
 			TargetVersion: "6.20.0",
 		}
 
-		err = upgrade.Run(nil, nil, opts)
+		err = upgrade.Run(context.Background(), nil, nil, opts)
 		if err != nil {
 			t.Fatalf("unexpected error %+v", err)
 		}
@@ -130,7 +132,7 @@ func TestRun(t *testing.T) {
 		upgrade.SetPgUpgradeCommand(exectest.NewCommand(upgrade.Success))
 		defer upgrade.ResetPgUpgradeCommand()
 
-		err := upgrade.Run(nil, nil, &idl.PgOptions{})
+		err := upgrade.Run(context.Background(), nil, nil, &idl.PgOptions{})
 		if !errors.Is(err, expected) {
 			t.Errorf("got error %#v want %#v", err, expected)
 		}
@@ -146,7 +148,7 @@ func TestRun(t *testing.T) {
 		upgrade.SetPgUpgradeCommand(exectest.NewCommand(upgrade.Success))
 		defer upgrade.ResetPgUpgradeCommand()
 
-		err := upgrade.Run(nil, nil, &idl.PgOptions{})
+		err := upgrade.Run(context.Background(), nil, nil, &idl.PgOptions{})
 		if !errors.Is(err, expected) {
 			t.Errorf("got error %#v want %#v", err, expected)
 		}
@@ -164,7 +166,7 @@ func TestRun(t *testing.T) {
 			ContentID:     3,
 			TargetVersion: "6.20.0",
 		}
-		err := upgrade.Run(stdout, stderr, opts)
+		err := upgrade.Run(context.Background(), stdout, stderr, opts)
 		if err != nil {
 			t.Fatalf("unexpected error %+v", err)
 		}
@@ -192,7 +194,7 @@ func TestRun(t *testing.T) {
 			ContentID:     3,
 			TargetVersion: "6.20.0",
 		}
-		err := upgrade.Run(stdout, nil, opts)
+		err := upgrade.Run(context.Background(), stdout, nil, opts)
 		if err != nil {
 			t.Fatalf("unexpected error %+v", err)
 		}
@@ -227,7 +229,7 @@ func TestRun(t *testing.T) {
 			ContentID:     3,
 			TargetVersion: "6.20.0",
 		}
-		err := upgrade.Run(stdout, nil, opts)
+		err := upgrade.Run(context.Background(), stdout, nil, opts)
 		if err != nil {
 			t.Fatalf("unexpected error %+v", err)
 		}
@@ -258,7 +260,7 @@ func TestRun(t *testing.T) {
 			TargetVersion: "6.20.0",
 		}
 
-		err := upgrade.Run(nil, nil, opts)
+		err := upgrade.Run(context.Background(), nil, nil, opts)
 		var exitErr *exec.ExitError
 		if !errors.As(err, &exitErr) {
 			t.Fatalf("got error %#v, want type *exec.ExitError", err)
@@ -269,6 +271,44 @@ func TestRun(t *testing.T) {
 		}
 	})
 
+	t.Run("records the pg_upgrade command in the event log", func(t *testing.T) {
+		output, reset := testlog.SetupTestEvents()
+		defer reset()
+
+		upgrade.SetPgUpgradeCommand(exectest.NewCommand(upgrade.Failure))
+		defer upgrade.ResetPgUpgradeCommand()
+
+		opts := &idl.PgOptions{
+			Role:          greenplum.MirrorRole,
+			ContentID:     3,
+			TargetVersion: "6.20.0",
+			OldDataDir:    "/old/data/dir",
+		}
+
+		err := upgrade.Run(context.Background(), nil, nil, opts)
+		if err == nil {
+			t.Fatalf("expected an error")
+		}
+
+		events := testlog.ParseEvents(t, output)
+		if len(events) != 2 {
+			t.Fatalf("got %d events want 2", len(events))
+		}
+
+		if events[0].Type != logger.CommandStarted || events[1].Type != logger.CommandFinished {
+			t.Errorf("got events %s, %s want %s, %s", events[0].Type, events[1].Type, logger.CommandStarted, logger.CommandFinished)
+		}
+
+		argv := strings.Join(events[1].Argv, " ")
+		if !strings.Contains(argv, "--old-datadir /old/data/dir") {
+			t.Errorf("expected argv %q to contain the pg_upgrade arguments", argv)
+		}
+
+		if events[1].Error != err.Error() {
+			t.Errorf("got error %q want %q", events[1].Error, err)
+		}
+	})
+
 	backupDir := "/data/.gpupgrade"
 
 	cases := []struct {
@@ -707,7 +747,7 @@ func TestRun(t *testing.T) {
 			}))
 			defer upgrade.ResetPgUpgradeCommand()
 
-			err := upgrade.Run(nil, nil, &c.opts)
+			err := upgrade.Run(context.Background(), nil, nil, &c.opts)
 			if err != nil {
 				t.Fatalf("unexpected error %+v", err)
 			}
@@ -0,0 +1,68 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"golang.org/x/xerrors"
+)
+
+// RunCommandContext runs the command and kills it when ctx is done. The
+// command is started in its own process group such that any processes it
+// started, such as the postgres instances started by pg_upgrade, are killed
+// as well.
+func RunCommandContext(ctx context.Context, cmd *exec.Cmd) error {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+
+	err := cmd.Start()
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	killed := make(chan struct{})
+	go func() {
+		defer close(killed)
+
+		select {
+		case <-ctx.Done():
+			// A negative pid signals the entire process group.
+			_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		case <-done:
+		}
+	}()
+
+	err = cmd.Wait()
+	close(done)
+	<-killed
+
+	if err != nil && ctx.Err() != nil {
+		return xerrors.Errorf("%v: %w", err, ctx.Err())
+	}
+
+	return err
+}
+
+// ShellQuote quotes the argument such that a shell, such as the one ssh runs
+// remote commands with, passes it through unchanged.
+func ShellQuote(arg string) string {
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// ShellJoin quotes each argument and joins them into a shell command.
+func ShellJoin(args ...string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = ShellQuote(arg)
+	}
+
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package utils_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+func TestRunCommandContext(t *testing.T) {
+	t.Run("runs the command", func(t *testing.T) {
+		var stdout bytes.Buffer
+		cmd := exec.Command("echo", "hello")
+		cmd.Stdout = &stdout
+
+		err := utils.RunCommandContext(context.Background(), cmd)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if stdout.String() != "hello\n" {
+			t.Errorf("got stdout %q want %q", stdout.String(), "hello\n")
+		}
+	})
+
+	t.Run("returns the error of the command", func(t *testing.T) {
+		err := utils.RunCommandContext(context.Background(), exec.Command("false"))
+
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			t.Errorf("got error %#v want %T", err, exitErr)
+		}
+	})
+
+	t.Run("kills the command and the processes it started when the context is canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		// The background sleep holds stdout open, so waiting on the command
+		// only returns once the sleep is killed as well.
+		var stdout bytes.Buffer
+		cmd := exec.Command("sh", "-c", "sleep 60 & wait")
+		cmd.Stdout = &stdout
+
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			cancel()
+		}()
+
+		start := time.Now()
+		err := utils.RunCommandContext(ctx, cmd)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got error %#v want %#v", err, context.Canceled)
+		}
+
+		if elapsed := time.Since(start); elapsed > 30*time.Second {
+			t.Errorf("took %s want the command to be killed", elapsed)
+		}
+	})
+
+	t.Run("does not run the command when the context is already canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := utils.RunCommandContext(ctx, exec.Command("sleep", "60"))
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got error %#v want %#v", err, context.Canceled)
+		}
+	})
+}
+
+func TestShellJoin(t *testing.T) {
+	args := []string{"/usr/local/gpupgrade/gpupgrade", "--tls-key", "/etc/certs/my key.pem", "it's", `"$HOME"`, "`id`", ""}
+
+	t.Run("quotes each argument", func(t *testing.T) {
+		expected := `'/usr/local/gpupgrade/gpupgrade' '--tls-key' '/etc/certs/my key.pem' 'it'\''s' '"$HOME"' '` + "`id`" + `' ''`
+		if actual := utils.ShellJoin(args...); actual != expected {
+			t.Errorf("got %s want %s", actual, expected)
+		}
+	})
+
+	t.Run("a shell passes the arguments through unchanged", func(t *testing.T) {
+		// Quote the command again as is done when running bash -c over ssh.
+		script := `printf '%s\n' ` + utils.ShellJoin(args...)
+		output, err := exec.Command("bash", "-c", "bash -c "+utils.ShellQuote(script)).Output()
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := strings.Join(args, "\n") + "\n"
+		if string(output) != expected {
+			t.Errorf("got %q want %q", output, expected)
+		}
+	})
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package daemon
+
+import (
+	"net"
+	"os"
+	"strconv"
+
+	"golang.org/x/xerrors"
+)
+
+// listenFdsStart is the first file descriptor passed by the service manager.
+// It is a variable for testing.
+var listenFdsStart = 3
+
+// Listener returns the socket passed by systemd when running as a socket
+// activated service, or nil when no socket was passed. See sd_listen_fds(3).
+func Listener() (net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+
+	fds, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || fds < 1 {
+		return nil, nil
+	}
+
+	// Unset the variables such that the commands run by the agent do not
+	// mistake the socket as their own.
+	for _, env := range []string{"LISTEN_PID", "LISTEN_FDS", "LISTEN_FDNAMES"} {
+		if err := os.Unsetenv(env); err != nil {
+			return nil, xerrors.Errorf("unset %s: %w", env, err)
+		}
+	}
+
+	if fds != 1 {
+		return nil, xerrors.Errorf("expected one socket from the service manager but got %d", fds)
+	}
+
+	file := os.NewFile(uintptr(listenFdsStart), "LISTEN_FD_"+strconv.Itoa(listenFdsStart))
+	defer file.Close()
+
+	// FileListener duplicates the file descriptor with close-on-exec set, so
+	// the original is closed above.
+	listener, err := net.FileListener(file)
+	if err != nil {
+		return nil, xerrors.Errorf("listen on socket from the service manager: %w", err)
+	}
+
+	return listener, nil
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package daemon
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"testing"
+)
+
+func TestListener(t *testing.T) {
+	t.Run("returns nil when not socket activated", func(t *testing.T) {
+		t.Setenv("LISTEN_PID", "")
+		t.Setenv("LISTEN_FDS", "")
+
+		listener, err := Listener()
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		if listener != nil {
+			t.Errorf("got listener %v want nil", listener)
+		}
+	})
+
+	t.Run("returns nil when the sockets are for another process", func(t *testing.T) {
+		t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()+1))
+		t.Setenv("LISTEN_FDS", "1")
+
+		listener, err := Listener()
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		if listener != nil {
+			t.Errorf("got listener %v want nil", listener)
+		}
+	})
+
+	t.Run("listens on the socket passed by the service manager", func(t *testing.T) {
+		socket, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+		defer socket.Close()
+
+		file, err := socket.(*net.TCPListener).File()
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		listenFdsStart = int(file.Fd())
+		defer func() { listenFdsStart = 3 }()
+
+		t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+		t.Setenv("LISTEN_FDS", "1")
+
+		listener, err := Listener()
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+		defer listener.Close()
+
+		if listener.Addr().String() != socket.Addr().String() {
+			t.Errorf("got address %q want %q", listener.Addr(), socket.Addr())
+		}
+
+		if _, ok := os.LookupEnv("LISTEN_FDS"); ok {
+			t.Errorf("expected LISTEN_FDS to be unset")
+		}
+	})
+
+	t.Run("errors when passed more than one socket", func(t *testing.T) {
+		t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+		t.Setenv("LISTEN_FDS", "2")
+
+		_, err := Listener()
+		if err == nil {
+			t.Errorf("expected an error")
+		}
+	})
+}
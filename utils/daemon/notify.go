@@ -0,0 +1,41 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package daemon
+
+import (
+	"net"
+	"os"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// Notify sends the state such as "READY=1" or "STOPPING=1" to the service
+// manager when running as a systemd service with Type=notify. It does nothing
+// when not running under systemd, which sets NOTIFY_SOCKET. See sd_notify(3).
+func Notify(state string) error {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return nil
+	}
+
+	// A leading @ denotes a socket in the abstract namespace.
+	name := socket
+	if strings.HasPrefix(name, "@") {
+		name = "\x00" + name[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: name, Net: "unixgram"})
+	if err != nil {
+		return xerrors.Errorf("connect to notify socket %q: %w", socket, err)
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(state))
+	if err != nil {
+		return xerrors.Errorf("notify %q: %w", state, err)
+	}
+
+	return nil
+}
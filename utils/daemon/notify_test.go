@@ -0,0 +1,63 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package daemon
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNotify(t *testing.T) {
+	t.Run("does nothing when not running under systemd", func(t *testing.T) {
+		t.Setenv("NOTIFY_SOCKET", "")
+
+		err := Notify("READY=1")
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+
+	t.Run("sends the state to the notify socket", func(t *testing.T) {
+		dir, err := os.MkdirTemp("", "notify")
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+		defer os.RemoveAll(dir)
+
+		socket := filepath.Join(dir, "notify.sock")
+		conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+		defer conn.Close()
+
+		t.Setenv("NOTIFY_SOCKET", socket)
+
+		err = Notify("READY=1")
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		buf := make([]byte, 64)
+		n, err := conn.Read(buf)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if string(buf[:n]) != "READY=1" {
+			t.Errorf("got %q want %q", buf[:n], "READY=1")
+		}
+	})
+
+	t.Run("errors when the notify socket does not exist", func(t *testing.T) {
+		t.Setenv("NOTIFY_SOCKET", "/does/not/exist.sock")
+
+		err := Notify("READY=1")
+		if err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
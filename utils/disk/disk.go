@@ -4,8 +4,12 @@
 package disk
 
 import (
+	"context"
+	"errors"
+	"io/fs"
 	"log"
 	"os"
+	"path/filepath"
 
 	"golang.org/x/sys/unix"
 	"golang.org/x/xerrors"
@@ -67,26 +71,9 @@ func CheckUsage(streams step.OutStreams, d Disk, diskFreeRatio float64, paths ..
 
 	failures := make(map[string]*idl.CheckDiskSpaceReply_DiskUsage)
 
-	// Find the device ID for every filesystem. We'll use these to map data
-	// directories to filesystems later.
-	fs, err := d.Filesystems()
+	fsByID, err := filesystemsByID(d, hostname)
 	if err != nil {
-		return nil, xerrors.Errorf("enumerating filesystems: %w", err)
-	}
-
-	fsByID := make(map[uint64]string)
-	for _, f := range fs.List {
-		stat, err := d.Stat(f.DirName)
-		if os.IsPermission(err) {
-			log.Printf("Ignoring filesystem %s on host %s when checking disk space. Unable to stat filesystem due to %v.", f.DirName, hostname, err)
-			continue
-		}
-
-		if err != nil {
-			return nil, xerrors.Errorf("stat'ing %s: %w", f.DirName, err)
-		}
-
-		fsByID[uint64(stat.Dev)] = f.DirName
+		return nil, err
 	}
 
 	for _, path := range paths {
@@ -109,13 +96,7 @@ func CheckUsage(streams step.OutStreams, d Disk, diskFreeRatio float64, paths ..
 				return nil, xerrors.Errorf("stat'ing %s: %w", path, err)
 			}
 
-			fs, ok := fsByID[uint64(stat.Dev)]
-			if !ok {
-				// Rather than blow up if we can't associate a path with a
-				// filesystem, just use the path itself.
-				fs = path
-			}
-
+			fs := filesystem(fsByID, stat, path)
 			failures[fs] = &idl.CheckDiskSpaceReply_DiskUsage{
 				Fs:        fs,
 				Host:      hostname,
@@ -136,6 +117,169 @@ func CheckUsage(streams step.OutStreams, d Disk, diskFreeRatio float64, paths ..
 	return usage, nil
 }
 
+type CheckRequirementsType func(ctx context.Context, streams step.OutStreams, d Disk, requirements ...*idl.DiskSpaceRequirement) (FileSystemDiskUsage, error)
+
+// CheckRequirements compares the available space of each filesystem to the
+// sum of the requirements of the directories on it, rather than to a ratio of
+// its total size. Requirements to be measured include the size of their
+// directory as reported by DirSize. Any filesystems that don't have enough
+// space will be given an entry in the returned usage.
+func CheckRequirements(ctx context.Context, streams step.OutStreams, d Disk, requirements ...*idl.DiskSpaceRequirement) (FileSystemDiskUsage, error) {
+	hostname, err := utils.System.Hostname()
+	if err != nil {
+		return nil, xerrors.Errorf("determining hostname: %w", err)
+	}
+
+	fsByID, err := filesystemsByID(d, hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	usages := make(map[string]*idl.CheckDiskSpaceReply_DiskUsage)
+	var filesystems []string
+
+	for _, requirement := range requirements {
+		path := requirement.GetDir()
+
+		required := requirement.GetRequired()
+		if requirement.GetMeasure() {
+			size, err := DirSize(ctx, path)
+			if err != nil {
+				return nil, err
+			}
+
+			required += size
+		}
+
+		usage, err := d.Usage(path)
+		if err != nil {
+			return nil, xerrors.Errorf("getting fs usage for %s: %w", path, err)
+		}
+
+		stat, err := d.Stat(path)
+		if err != nil {
+			return nil, xerrors.Errorf("stat'ing %s: %w", path, err)
+		}
+
+		log.Printf("%s: %d avail of %d required (%d used, %d total)",
+			path, usage.Avail, required, usage.Used, usage.Total)
+
+		fs := filesystem(fsByID, stat, path)
+		if _, ok := usages[fs]; !ok {
+			usages[fs] = &idl.CheckDiskSpaceReply_DiskUsage{Fs: fs, Host: hostname, Available: usage.Avail}
+			filesystems = append(filesystems, fs)
+		}
+
+		usages[fs].Required += required
+	}
+
+	var failures FileSystemDiskUsage
+	for _, fs := range filesystems {
+		if usages[fs].GetAvailable() < usages[fs].GetRequired() {
+			failures = append(failures, usages[fs])
+		}
+	}
+
+	return failures, nil
+}
+
+// excludedDirs are not copied by the upgrade and are excluded from DirSize.
+var excludedDirs = map[string]bool{
+	"pg_xlog": true,
+	"pg_wal":  true,
+	"pg_log":  true,
+	"log":     true,
+}
+
+// DirSize returns the size in KB of the files within path, matching the units
+// of the filesystem usage. The WAL and log directories at the top level of
+// path are excluded since the upgrade does not copy them. Files removed while
+// measuring, such as temporary files of the running cluster, are skipped.
+// Measuring stops once the context is canceled.
+func DirSize(ctx context.Context, path string) (uint64, error) {
+	var bytes uint64
+
+	err := filepath.WalkDir(path, func(p string, entry fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err != nil {
+			if p != path && errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		if entry.IsDir() {
+			if filepath.Dir(p) == filepath.Clean(path) && excludedDirs[entry.Name()] {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		bytes += uint64(info.Size())
+		return nil
+	})
+	if err != nil {
+		return 0, xerrors.Errorf("measuring size of %s: %w", path, err)
+	}
+
+	return (bytes + 1023) / 1024, nil
+}
+
+// filesystemsByID finds the device ID for every filesystem. They are used to
+// map data directories to filesystems.
+func filesystemsByID(d Disk, hostname string) (map[uint64]string, error) {
+	fs, err := d.Filesystems()
+	if err != nil {
+		return nil, xerrors.Errorf("enumerating filesystems: %w", err)
+	}
+
+	fsByID := make(map[uint64]string)
+	for _, f := range fs.List {
+		stat, err := d.Stat(f.DirName)
+		if os.IsPermission(err) {
+			log.Printf("Ignoring filesystem %s on host %s when checking disk space. Unable to stat filesystem due to %v.", f.DirName, hostname, err)
+			continue
+		}
+
+		if err != nil {
+			return nil, xerrors.Errorf("stat'ing %s: %w", f.DirName, err)
+		}
+
+		fsByID[uint64(stat.Dev)] = f.DirName
+	}
+
+	return fsByID, nil
+}
+
+// filesystem returns the filesystem that the path belongs to. Rather than blow
+// up if we can't associate a path with a filesystem, just use the path itself.
+func filesystem(fsByID map[uint64]string, stat *unix.Stat_t, path string) string {
+	fs, ok := fsByID[uint64(stat.Dev)]
+	if !ok {
+		return path
+	}
+
+	return fs
+}
+
 // Local is a standard implementation of the Disk interface that uses gosigar
 // and unix.Stat to obtain statistics for the local machine.
 var Local = local{}
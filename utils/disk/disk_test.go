@@ -4,8 +4,10 @@
 package disk_test
 
 import (
+	"context"
 	"errors"
 	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"strings"
@@ -347,6 +349,224 @@ func TestCheckUsage(t *testing.T) {
 	})
 }
 
+func TestCheckRequirements(t *testing.T) {
+	testlog.SetupTestLogger()
+
+	host := "localhost"
+	utils.System.Hostname = func() (string, error) {
+		return host, nil
+	}
+	defer func() {
+		utils.System.Hostname = os.Hostname
+	}()
+
+	// This test disk has two mount points with 100 KB available on / and
+	// 10 KB available on /tmp.
+	d := testDisk{
+		filesystems: func() (sigar.FileSystemList, error) {
+			return sigar.FileSystemList{List: []sigar.FileSystem{
+				{DirName: "/"},
+				{DirName: "/tmp"},
+			}}, nil
+		},
+
+		usage: func(path string) (sigar.FileSystemUsage, error) {
+			u := sigar.FileSystemUsage{Total: 1000, Avail: 100}
+			if strings.HasPrefix(path, os.TempDir()) {
+				u.Avail = 10
+			}
+			u.Free = u.Avail
+			u.Used = u.Total - u.Free
+
+			return u, nil
+		},
+
+		stat: func(path string) (*unix.Stat_t, error) {
+			stat := new(unix.Stat_t)
+
+			switch {
+			case strings.HasPrefix(path, os.TempDir()):
+				stat.Dev = 2 // tmp filesystem
+
+			case path == "/unmounted/path":
+				stat.Dev = 100
+
+			default:
+				stat.Dev = 1 // root filesystem
+			}
+
+			return stat, nil
+		},
+	}
+
+	cases := []struct {
+		name         string
+		requirements []*idl.DiskSpaceRequirement
+		expected     disk.FileSystemDiskUsage
+	}{
+		{
+			name: "returns no failures with adequate space",
+			requirements: []*idl.DiskSpaceRequirement{
+				{Dir: "/data/qddir/seg-1", Required: 60},
+				{Dir: "/data/dbfast/seg1", Required: 40},
+			},
+			expected: nil,
+		},
+		{
+			name: "sums the requirements of each filesystem",
+			requirements: []*idl.DiskSpaceRequirement{
+				{Dir: "/data/qddir/seg-1", Required: 60},
+				{Dir: "/data/dbfast/seg1", Required: 50},
+				{Dir: "/unmounted/path", Required: 200},
+			},
+			expected: disk.FileSystemDiskUsage{
+				&idl.CheckDiskSpaceReply_DiskUsage{
+					Fs:        "/",
+					Host:      host,
+					Required:  110,
+					Available: 100,
+				},
+				&idl.CheckDiskSpaceReply_DiskUsage{
+					Fs:        "/unmounted/path",
+					Host:      host,
+					Required:  200,
+					Available: 100,
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := disk.CheckRequirements(context.Background(), step.DevNullStream, d, c.requirements...)
+			if err != nil {
+				t.Fatalf("returned error %#v", err)
+			}
+
+			sort.Sort(actual)
+			if !reflect.DeepEqual(actual, c.expected) {
+				t.Errorf("returned %v want %v", actual, c.expected)
+			}
+		})
+	}
+
+	t.Run("adds the measured size of directories", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "base", "16384"), 8*1024)
+
+		actual, err := disk.CheckRequirements(context.Background(), step.DevNullStream, d,
+			&idl.DiskSpaceRequirement{Dir: dir, Required: 4, Measure: true},
+		)
+		if err != nil {
+			t.Fatalf("returned error %#v", err)
+		}
+
+		expected := disk.FileSystemDiskUsage{
+			&idl.CheckDiskSpaceReply_DiskUsage{
+				Fs:        "/tmp",
+				Host:      host,
+				Required:  12,
+				Available: 10,
+			},
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("returned %v want %v", actual, expected)
+		}
+	})
+
+	t.Run("bubbles up any errors", func(t *testing.T) {
+		expected := errors.New("the correct error")
+
+		d := d
+		d.usage = nil
+		d.err = expected
+
+		_, err := disk.CheckRequirements(context.Background(), step.DevNullStream, d, &idl.DiskSpaceRequirement{Dir: "/data/qddir/seg-1"})
+		if !errors.Is(err, expected) {
+			t.Errorf("returned %#v want %#v", err, expected)
+		}
+
+		_, err = disk.CheckRequirements(context.Background(), step.DevNullStream, d, &idl.DiskSpaceRequirement{Dir: filepath.Join(t.TempDir(), "missing"), Measure: true})
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("returned %#v want %#v", err, os.ErrNotExist)
+		}
+	})
+}
+
+func TestDirSize(t *testing.T) {
+	t.Run("returns the size of the files in KB", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "PG_VERSION"), 3)
+		writeFile(t, filepath.Join(dir, "base", "1", "1249"), 16*1024)
+		writeFile(t, filepath.Join(dir, "global", "1262"), 8*1024)
+
+		actual, err := disk.DirSize(context.Background(), dir)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		var expected uint64 = 25
+		if actual != expected {
+			t.Errorf("got size %d want %d", actual, expected)
+		}
+	})
+
+	t.Run("excludes the WAL and log directories", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "base", "1", "1249"), 1024)
+		for _, excluded := range []string{"pg_xlog", "pg_wal", "pg_log", "log"} {
+			writeFile(t, filepath.Join(dir, excluded, "000000010000000000000001"), 64*1024)
+		}
+
+		// only the top level directories are excluded
+		writeFile(t, filepath.Join(dir, "base", "log", "16384"), 1024)
+
+		actual, err := disk.DirSize(context.Background(), dir)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		var expected uint64 = 2
+		if actual != expected {
+			t.Errorf("got size %d want %d", actual, expected)
+		}
+	})
+
+	t.Run("stops when the context is canceled", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "base", "1", "1249"), 1024)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := disk.DirSize(ctx, dir)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("returned %#v want %#v", err, context.Canceled)
+		}
+	})
+
+	t.Run("errors when the directory does not exist", func(t *testing.T) {
+		_, err := disk.DirSize(context.Background(), filepath.Join(t.TempDir(), "missing"))
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("returned %#v want %#v", err, os.ErrNotExist)
+		}
+	})
+}
+
+func writeFile(t *testing.T, path string, size int) {
+	t.Helper()
+
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		t.Fatalf("creating directory: %v", err)
+	}
+
+	err = os.WriteFile(path, make([]byte, size), 0600)
+	if err != nil {
+		t.Fatalf("writing file: %v", err)
+	}
+}
+
 func TestLocal(t *testing.T) {
 	// disk.Local is a passthrough to more complicated implementations. Rather
 	// than duplicate the tests for those implementations, just verify simple
@@ -0,0 +1,137 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+// EventType identifies an entry in the machine-readable event log.
+type EventType string
+
+const (
+	SubstepStarted   EventType = "substep_started"
+	SubstepCompleted EventType = "substep_completed"
+	SubstepFailed    EventType = "substep_failed"
+	SubstepSkipped   EventType = "substep_skipped"
+	SubstepQuit      EventType = "substep_quit"
+	AgentRPCIssued   EventType = "agent_rpc_issued"
+	AgentRPCReturned EventType = "agent_rpc_returned"
+	CommandStarted   EventType = "command_started"
+	CommandFinished  EventType = "command_finished"
+)
+
+// Event is a single line of the event log. Time, Process, Hostname, and PID
+// are populated by LogEvent.
+type Event struct {
+	Time            time.Time `json:"time"`
+	Process         string    `json:"process"`
+	Hostname        string    `json:"hostname"`
+	PID             int       `json:"pid"`
+	Type            EventType `json:"event"`
+	Step            string    `json:"step,omitempty"`
+	Substep         string    `json:"substep,omitempty"`
+	Host            string    `json:"host,omitempty"`
+	Method          string    `json:"method,omitempty"`
+	Argv            []string  `json:"argv,omitempty"`
+	DurationSeconds float64   `json:"duration_seconds,omitempty"`
+	Error           string    `json:"error,omitempty"`
+}
+
+var events struct {
+	mutex   sync.Mutex
+	process string
+	output  io.Writer
+}
+
+// OpenEventsFile opens the JSON-lines event log which lives in the log
+// directory alongside the process log.
+func OpenEventsFile(process string) (*os.File, error) {
+	logDir, err := utils.GetLogDir()
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(logDir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(logDir, fmt.Sprintf("%s_events_%s.jsonl", process, time.Now().Format("20060102")))
+	return os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+}
+
+// SetEventOutput sets where events are written. A nil writer disables the
+// event log, which is the default.
+func SetEventOutput(process string, w io.Writer) {
+	events.mutex.Lock()
+	defer events.mutex.Unlock()
+
+	events.process = process
+	events.output = w
+}
+
+// LogEvent writes the event as a single line of JSON. Failures are logged
+// rather than returned since the event log is supplementary to the process
+// log and should never fail an upgrade.
+func LogEvent(event Event) {
+	events.mutex.Lock()
+	defer events.mutex.Unlock()
+
+	if events.output == nil {
+		return
+	}
+
+	event.Time = time.Now()
+	event.Process = events.process
+	event.Hostname, _ = os.Hostname()
+	event.PID = os.Getpid()
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("marshal event %q: %v", event.Type, err)
+		return
+	}
+
+	if _, err := events.output.Write(append(line, '\n')); err != nil {
+		log.Printf("write event %q: %v", event.Type, err)
+	}
+}
+
+// LogCommand runs the command via run, recording when the command with the
+// given argv starts and finishes.
+func LogCommand(argv []string, run func() error) error {
+	LogEvent(Event{Type: CommandStarted, Argv: argv})
+
+	start := time.Now()
+	err := run()
+
+	LogEvent(Event{
+		Type:            CommandFinished,
+		Argv:            argv,
+		DurationSeconds: time.Since(start).Seconds(),
+		Error:           ErrorString(err),
+	})
+
+	return err
+}
+
+// ErrorString returns the error text for an event or an empty string when
+// there is no error.
+func ErrorString(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package logger_test
+
+import (
+	"errors"
+	"log"
+	"os"
+	"os/user"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/logger"
+)
+
+func TestLogEvent(t *testing.T) {
+	t.Run("writes each event as a line of JSON", func(t *testing.T) {
+		output, reset := testlog.SetupTestEvents()
+		defer reset()
+
+		logger.LogEvent(logger.Event{Type: logger.SubstepStarted, Step: "initialize", Substep: "check_disk_space"})
+		logger.LogEvent(logger.Event{Type: logger.AgentRPCReturned, Host: "sdw1", Method: "/idl.Agent/CheckDiskSpace", DurationSeconds: 1.5, Error: "oops"})
+
+		lines := strings.Split(strings.TrimSpace(string(output.Bytes())), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("got %d lines want 2: %q", len(lines), lines)
+		}
+
+		events := testlog.ParseEvents(t, output)
+		for _, event := range events {
+			if event.Process != "test" || event.PID != os.Getpid() || event.Hostname == "" {
+				t.Errorf("got process %q pid %d hostname %q want them populated", event.Process, event.PID, event.Hostname)
+			}
+
+			if time.Since(event.Time) > time.Minute {
+				t.Errorf("got time %v want the current time", event.Time)
+			}
+		}
+
+		expected := logger.Event{Type: logger.AgentRPCReturned, Host: "sdw1", Method: "/idl.Agent/CheckDiskSpace", DurationSeconds: 1.5, Error: "oops"}
+		actual := events[1]
+		actual.Time, actual.Process, actual.Hostname, actual.PID = time.Time{}, "", "", 0
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("got %+v want %+v", actual, expected)
+		}
+	})
+
+	t.Run("omits empty fields", func(t *testing.T) {
+		output, reset := testlog.SetupTestEvents()
+		defer reset()
+
+		logger.LogEvent(logger.Event{Type: logger.SubstepSkipped, Step: "execute", Substep: "upgrade_primaries"})
+
+		contents := string(output.Bytes())
+		for _, field := range []string{`"host"`, `"method"`, `"argv"`, `"duration_seconds"`, `"error"`} {
+			if strings.Contains(contents, field) {
+				t.Errorf("expected %q to not contain %s", contents, field)
+			}
+		}
+	})
+
+	t.Run("does not write events when no output is set", func(t *testing.T) {
+		output, reset := testlog.SetupTestEvents()
+		reset()
+
+		logger.LogEvent(logger.Event{Type: logger.SubstepStarted})
+
+		if len(output.Bytes()) != 0 {
+			t.Errorf("got %q want no events", output.Bytes())
+		}
+	})
+}
+
+func TestLogCommand(t *testing.T) {
+	output, reset := testlog.SetupTestEvents()
+	defer reset()
+
+	argv := []string{"pg_upgrade", "--check"}
+	expected := errors.New("exit status 1")
+
+	var called bool
+	err := logger.LogCommand(argv, func() error {
+		called = true
+		return expected
+	})
+	if !errors.Is(err, expected) {
+		t.Errorf("got error %#v want %#v", err, expected)
+	}
+
+	if !called {
+		t.Errorf("expected command to be run")
+	}
+
+	events := testlog.ParseEvents(t, output)
+	if len(events) != 2 {
+		t.Fatalf("got %d events want 2", len(events))
+	}
+
+	if events[0].Type != logger.CommandStarted || !reflect.DeepEqual(events[0].Argv, argv) || events[0].Error != "" {
+		t.Errorf("got %+v want a command started event with argv %q", events[0], argv)
+	}
+
+	if events[1].Type != logger.CommandFinished || !reflect.DeepEqual(events[1].Argv, argv) || events[1].Error != expected.Error() {
+		t.Errorf("got %+v want a command finished event with argv %q and error %q", events[1], argv, expected)
+	}
+}
+
+func TestOpenEventsFile(t *testing.T) {
+	home := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, home)
+
+	utils.System.Current = func() (*user.User, error) {
+		return &user.User{HomeDir: home}, nil
+	}
+	defer utils.ResetSystemFunctions()
+
+	file, err := logger.OpenEventsFile("hub")
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+	defer file.Close()
+
+	expected := filepath.Join(home, "gpAdminLogs", "gpupgrade", "hub_events_"+time.Now().Format("20060102")+".jsonl")
+	if file.Name() != expected {
+		t.Errorf("got %q want %q", file.Name(), expected)
+	}
+}
+
+func TestInitialize(t *testing.T) {
+	t.Run("continues with events disabled when the events file cannot be opened", func(t *testing.T) {
+		home := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, home)
+
+		utils.System.Current = func() (*user.User, error) {
+			return &user.User{HomeDir: home}, nil
+		}
+		defer utils.ResetSystemFunctions()
+
+		// Create a directory where the events file is expected so that it
+		// cannot be opened.
+		logDir := filepath.Join(home, "gpAdminLogs", "gpupgrade")
+		date := time.Now().Format("20060102")
+		testutils.MustCreateDir(t, filepath.Join(logDir, "hub_events_"+date+".jsonl"))
+
+		logger.Initialize("hub")
+		defer func() {
+			log.SetOutput(os.Stderr)
+			log.SetPrefix("")
+			log.SetFlags(log.LstdFlags)
+		}()
+
+		logger.LogEvent(logger.Event{Type: logger.SubstepStarted, Step: "initialize", Substep: "check_disk_space"})
+
+		contents := testutils.MustReadFile(t, filepath.Join(logDir, "hub_"+date+".log"))
+		expected := "open events file, continuing with events disabled"
+		if !strings.Contains(contents, expected) {
+			t.Errorf("expected log to contain %q got %q", expected, contents)
+		}
+	})
+}
@@ -25,6 +25,17 @@ func Initialize(process string) {
 	log.SetOutput(f)
 	log.SetPrefix(prefix())
 	log.SetFlags(log.Ldate | log.Ltime | log.Lmsgprefix)
+
+	// The event log is supplementary to the process log, so continue without
+	// it rather than failing to start.
+	eventsFile, err := OpenEventsFile(process)
+	if err != nil {
+		log.Printf("open events file, continuing with events disabled: %v", err)
+		SetEventOutput(process, nil)
+		return
+	}
+
+	SetEventOutput(process, eventsFile)
 }
 
 func OpenFile(process string) (*os.File, error) {
@@ -0,0 +1,57 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+// Package metrics contains the Prometheus registry of the gpupgrade hub. Only
+// the hub serves metrics, so metrics are registered by the hub and the
+// packages it runs, rather than by code running on the agents.
+package metrics
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/common/expfmt"
+)
+
+// Registry contains all gpupgrade metrics along with the Go runtime and
+// process metrics of the hub.
+var Registry = prometheus.NewRegistry()
+
+// Factory creates metrics registered with Registry.
+var Factory = promauto.With(Registry)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// DefaultBuckets are histogram buckets in seconds suitable for operations
+// ranging from milliseconds to hours.
+var DefaultBuckets = []float64{0.01, 0.1, 1, 10, 60, 300, 900, 1800, 3600, 7200, 14400}
+
+// Handler serves the registry for Prometheus to scrape.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// Write writes all metrics in the Prometheus text exposition format.
+func Write(w io.Writer) error {
+	families, err := Registry.Gather()
+	if err != nil {
+		return err
+	}
+
+	for _, family := range families {
+		if _, err := expfmt.MetricFamilyToText(w, family); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/greenplum-db/gpupgrade/utils/metrics"
+)
+
+var testGauge = metrics.Factory.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "gpupgrade_test_temperature",
+	Help: "Current temperature.",
+}, []string{"host"})
+
+func TestWrite(t *testing.T) {
+	testGauge.WithLabelValues("sdw1").Set(1.5)
+
+	var buf bytes.Buffer
+	if err := metrics.Write(&buf); err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	expected := []string{
+		"# HELP gpupgrade_test_temperature Current temperature.\n",
+		"# TYPE gpupgrade_test_temperature gauge\n",
+		`gpupgrade_test_temperature{host="sdw1"} 1.5` + "\n",
+		"# TYPE go_goroutines gauge\n",
+	}
+	for _, line := range expected {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("expected metrics to contain %q got %q", line, buf.String())
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	testGauge.WithLabelValues("sdw2").Set(-2)
+
+	server := httptest.NewServer(metrics.Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+	defer resp.Body.Close()
+
+	contentType := resp.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, "text/plain; version=0.0.4") {
+		t.Errorf("got content type %q want Prometheus text format", contentType)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	expected := `gpupgrade_test_temperature{host="sdw2"} -2` + "\n"
+	if !strings.Contains(string(body), expected) {
+		t.Errorf("expected metrics to contain %q got %q", expected, body)
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+// Package mtls secures the gRPC connections between the CLI, hub, and agents
+// using mutual TLS.
+package mtls
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Config contains the certificates used by the CLI, hub, and agents. The zero
+// value disables TLS.
+//
+// All certificates must be signed by CACertificate. The CLI certificate is
+// presented when connecting to the hub, and is the only client certificate
+// the hub accepts. The hub certificate is presented both when serving the CLI
+// and when connecting to agents, and is the only client certificate agents
+// accept. Since the CLI connects to the hub on localhost, or the hub listen
+// address when set, the hub certificate must be valid for that address. Agent
+// certificates must be valid for the hostname of each agent, and are expected
+// to be at the same path on all hosts.
+type Config struct {
+	CACertificate    string
+	CLICertificate   string
+	CLIKey           string
+	HubCertificate   string
+	HubKey           string
+	AgentCertificate string
+	AgentKey         string
+}
+
+func (c Config) Enabled() bool {
+	return c != Config{}
+}
+
+// Validate ensures either none or all of the certificates are set.
+func (c Config) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+
+	params := []struct {
+		name  string
+		value string
+	}{
+		{"tls_ca_certificate", c.CACertificate},
+		{"tls_cli_certificate", c.CLICertificate},
+		{"tls_cli_key", c.CLIKey},
+		{"tls_hub_certificate", c.HubCertificate},
+		{"tls_hub_key", c.HubKey},
+		{"tls_agent_certificate", c.AgentCertificate},
+		{"tls_agent_key", c.AgentKey},
+	}
+
+	for _, param := range params {
+		if param.value == "" {
+			return fmt.Errorf("Expected %q to be set since TLS is enabled. Either set all or none of tls_ca_certificate, tls_cli_certificate, tls_cli_key, tls_hub_certificate, tls_hub_key, tls_agent_certificate, and tls_agent_key.", param.name)
+		}
+	}
+
+	return nil
+}
+
+// HubServerOptions returns the gRPC server options for the hub which only
+// accept clients presenting the CLI certificate.
+func (c Config) HubServerOptions() ([]grpc.ServerOption, error) {
+	if !c.Enabled() {
+		return nil, nil
+	}
+
+	tlsConfig, err := serverTLSConfig(c.CACertificate, c.HubCertificate, c.HubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprint, err := Fingerprint(c.CLICertificate)
+	if err != nil {
+		return nil, err
+	}
+
+	err = pinClientCertificate(tlsConfig, fingerprint, "CLI")
+	if err != nil {
+		return nil, err
+	}
+
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsConfig))}, nil
+}
+
+// MetricsTLSConfig returns the TLS configuration of the hub metrics listener.
+// It presents the hub certificate and requires scrapers to present a
+// certificate signed by the CA. It returns nil when TLS is disabled.
+func (c Config) MetricsTLSConfig() (*tls.Config, error) {
+	if !c.Enabled() {
+		return nil, nil
+	}
+
+	return serverTLSConfig(c.CACertificate, c.HubCertificate, c.HubKey)
+}
+
+// CLIDialOption returns the gRPC dial option used by the CLI to connect to the
+// hub. The CLI certificate is presented as the client certificate and the hub
+// is verified against the CA.
+func (c Config) CLIDialOption() (grpc.DialOption, error) {
+	return c.dialOption(c.CLICertificate, c.CLIKey)
+}
+
+// HubDialOption returns the gRPC dial option used by the hub to connect to
+// agents. The hub certificate is presented as the client certificate and the
+// agent is verified against the CA.
+func (c Config) HubDialOption() (grpc.DialOption, error) {
+	return c.dialOption(c.HubCertificate, c.HubKey)
+}
+
+func (c Config) dialOption(certFile string, keyFile string) (grpc.DialOption, error) {
+	if !c.Enabled() {
+		return grpc.WithInsecure(), nil
+	}
+
+	pool, err := certPool(c.CACertificate)
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, xerrors.Errorf("load client certificate: %w", err)
+	}
+
+	return grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		MinVersion:   tls.VersionTLS12,
+	})), nil
+}
+
+// AgentArgs returns the command line arguments needed to start an agent such
+// that it only accepts connections from the hub's certificate.
+func (c Config) AgentArgs() ([]string, error) {
+	if !c.Enabled() {
+		return nil, nil
+	}
+
+	fingerprint, err := Fingerprint(c.HubCertificate)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{
+		"--tls-ca-certificate", c.CACertificate,
+		"--tls-certificate", c.AgentCertificate,
+		"--tls-key", c.AgentKey,
+		"--tls-hub-fingerprint", fingerprint,
+	}, nil
+}
+
+// AgentConfig contains the certificates used by an agent. HubFingerprint is
+// the SHA-256 fingerprint of the hub certificate, which is the only client
+// certificate the agent accepts. The zero value disables TLS.
+type AgentConfig struct {
+	CACertificate  string
+	Certificate    string
+	Key            string
+	HubFingerprint string
+}
+
+func (c AgentConfig) Enabled() bool {
+	return c != AgentConfig{}
+}
+
+// ServerOptions returns the gRPC server options for an agent which only
+// accept clients presenting the hub certificate.
+func (c AgentConfig) ServerOptions() ([]grpc.ServerOption, error) {
+	if !c.Enabled() {
+		return nil, nil
+	}
+
+	if c.CACertificate == "" || c.Certificate == "" || c.Key == "" || c.HubFingerprint == "" {
+		return nil, errors.New("expected a CA certificate, certificate, key, and hub fingerprint when TLS is enabled")
+	}
+
+	tlsConfig, err := serverTLSConfig(c.CACertificate, c.Certificate, c.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	err = pinClientCertificate(tlsConfig, c.HubFingerprint, "hub")
+	if err != nil {
+		return nil, err
+	}
+
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsConfig))}, nil
+}
+
+// Fingerprint returns the hex encoded SHA-256 fingerprint of the first
+// certificate in the PEM encoded file.
+func Fingerprint(certFile string) (string, error) {
+	contents, err := os.ReadFile(certFile)
+	if err != nil {
+		return "", xerrors.Errorf("read certificate: %w", err)
+	}
+
+	block, _ := pem.Decode(contents)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return "", xerrors.Errorf("no PEM encoded certificate found in %q", certFile)
+	}
+
+	sum := sha256.Sum256(block.Bytes)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// pinClientCertificate only accepts the client certificate with the hex
+// encoded SHA-256 fingerprint. The certificate chain is still verified against
+// the CA, but other holders of certificates signed by the same CA, such as
+// agents, are rejected.
+func pinClientCertificate(tlsConfig *tls.Config, fingerprint string, name string) error {
+	expected, err := hex.DecodeString(fingerprint)
+	if err != nil {
+		return xerrors.Errorf("decode %s fingerprint: %w", name, err)
+	}
+
+	tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.New("no client certificate presented")
+		}
+
+		actual := sha256.Sum256(rawCerts[0])
+		if subtle.ConstantTimeCompare(actual[:], expected) != 1 {
+			return fmt.Errorf("client certificate does not match the %s certificate", name)
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+func serverTLSConfig(caFile string, certFile string, keyFile string) (*tls.Config, error) {
+	pool, err := certPool(caFile)
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, xerrors.Errorf("load certificate: %w", err)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+func certPool(caFile string) (*x509.CertPool, error) {
+	contents, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, xerrors.Errorf("read CA certificate: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(contents) {
+		return nil, xerrors.Errorf("no PEM encoded certificates found in %q", caFile)
+	}
+
+	return pool, nil
+}
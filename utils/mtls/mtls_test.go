@@ -0,0 +1,414 @@
+// Copyright (c) 2017-2023 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package mtls_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils/mtls"
+)
+
+func TestConfig(t *testing.T) {
+	t.Run("is disabled when empty", func(t *testing.T) {
+		var config mtls.Config
+		if config.Enabled() {
+			t.Errorf("expected TLS to be disabled")
+		}
+
+		if err := config.Validate(); err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		opts, err := config.HubServerOptions()
+		if err != nil || opts != nil {
+			t.Errorf("got options %v error %#v want no options", opts, err)
+		}
+
+		args, err := config.AgentArgs()
+		if err != nil || args != nil {
+			t.Errorf("got args %v error %#v want no args", args, err)
+		}
+	})
+
+	t.Run("validate errors when only some certificates are set", func(t *testing.T) {
+		config := mtls.Config{CACertificate: "/certs/ca.crt", HubCertificate: "/certs/hub.crt"}
+
+		err := config.Validate()
+		if err == nil || !strings.Contains(err.Error(), "tls_cli_certificate") {
+			t.Errorf("got error %#v want error about tls_cli_certificate", err)
+		}
+
+		config.CLICertificate = "/certs/cli.crt"
+		config.CLIKey = "/certs/cli.key"
+
+		err = config.Validate()
+		if err == nil || !strings.Contains(err.Error(), "tls_hub_key") {
+			t.Errorf("got error %#v want error about tls_hub_key", err)
+		}
+	})
+
+	t.Run("agent args include the hub fingerprint", func(t *testing.T) {
+		certs := mustCreateCertificates(t)
+		defer testutils.MustRemoveAll(t, certs.dir)
+
+		args, err := certs.config.AgentArgs()
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := []string{
+			"--tls-ca-certificate", certs.config.CACertificate,
+			"--tls-certificate", certs.config.AgentCertificate,
+			"--tls-key", certs.config.AgentKey,
+			"--tls-hub-fingerprint", certs.hubFingerprint,
+		}
+		if strings.Join(args, " ") != strings.Join(expected, " ") {
+			t.Errorf("got %q want %q", args, expected)
+		}
+	})
+}
+
+func TestFingerprint(t *testing.T) {
+	certs := mustCreateCertificates(t)
+	defer testutils.MustRemoveAll(t, certs.dir)
+
+	fingerprint, err := mtls.Fingerprint(certs.config.HubCertificate)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if fingerprint != certs.hubFingerprint {
+		t.Errorf("got %q want %q", fingerprint, certs.hubFingerprint)
+	}
+
+	path := filepath.Join(certs.dir, "invalid.crt")
+	testutils.MustWriteToFile(t, path, "not a certificate")
+
+	_, err = mtls.Fingerprint(path)
+	if err == nil {
+		t.Errorf("expected an error")
+	}
+}
+
+func TestAgentServerOptions(t *testing.T) {
+	certs := mustCreateCertificates(t)
+	defer testutils.MustRemoveAll(t, certs.dir)
+
+	agentConfig := mtls.AgentConfig{
+		CACertificate:  certs.config.CACertificate,
+		Certificate:    certs.config.AgentCertificate,
+		Key:            certs.config.AgentKey,
+		HubFingerprint: certs.hubFingerprint,
+	}
+
+	address, stop := mustServe(t, agentConfig)
+	defer stop()
+
+	t.Run("accepts the hub certificate", func(t *testing.T) {
+		err := check(t, address, certs.config.HubDialOption)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+
+	t.Run("rejects other certificates signed by the CA", func(t *testing.T) {
+		err := check(t, address, certs.config.CLIDialOption)
+		if err == nil {
+			t.Errorf("expected an error")
+		}
+
+		config := certs.config
+		config.HubCertificate = certs.config.AgentCertificate
+		config.HubKey = certs.config.AgentKey
+
+		err = check(t, address, config.HubDialOption)
+		if err == nil {
+			t.Errorf("expected an error")
+		}
+	})
+
+	t.Run("rejects clients without TLS", func(t *testing.T) {
+		err := check(t, address, mtls.Config{}.HubDialOption)
+		if err == nil {
+			t.Errorf("expected an error")
+		}
+	})
+
+	t.Run("errors when only some certificates are set", func(t *testing.T) {
+		_, err := mtls.AgentConfig{CACertificate: certs.config.CACertificate}.ServerOptions()
+		if err == nil {
+			t.Errorf("expected an error")
+		}
+	})
+}
+
+func TestHubServerOptions(t *testing.T) {
+	certs := mustCreateCertificates(t)
+	defer testutils.MustRemoveAll(t, certs.dir)
+
+	opts, err := certs.config.HubServerOptions()
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	address, stop := mustServeWithOptions(t, opts)
+	defer stop()
+
+	t.Run("accepts the CLI certificate", func(t *testing.T) {
+		err := check(t, address, certs.config.CLIDialOption)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+
+	t.Run("rejects other certificates signed by the CA", func(t *testing.T) {
+		err := check(t, address, certs.config.HubDialOption)
+		if err == nil {
+			t.Errorf("expected an error")
+		}
+
+		config := certs.config
+		config.CLICertificate = certs.config.AgentCertificate
+		config.CLIKey = certs.config.AgentKey
+
+		err = check(t, address, config.CLIDialOption)
+		if err == nil {
+			t.Errorf("expected an error")
+		}
+	})
+
+	t.Run("rejects clients without TLS", func(t *testing.T) {
+		err := check(t, address, mtls.Config{}.CLIDialOption)
+		if err == nil {
+			t.Errorf("expected an error")
+		}
+	})
+}
+
+func TestMetricsTLSConfig(t *testing.T) {
+	t.Run("is nil when TLS is disabled", func(t *testing.T) {
+		tlsConfig, err := mtls.Config{}.MetricsTLSConfig()
+		if err != nil || tlsConfig != nil {
+			t.Errorf("got config %v error %#v want no config", tlsConfig, err)
+		}
+	})
+
+	certs := mustCreateCertificates(t)
+	defer testutils.MustRemoveAll(t, certs.dir)
+
+	tlsConfig, err := certs.config.MetricsTLSConfig()
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = tlsConfig
+	server.StartTLS()
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM([]byte(testutils.MustReadFile(t, certs.config.CACertificate)))
+
+	get := func(certificates []tls.Certificate) error {
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			Certificates: certificates,
+			RootCAs:      pool,
+		}}}
+
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return err
+		}
+
+		return resp.Body.Close()
+	}
+
+	t.Run("accepts scrapers with certificates signed by the CA", func(t *testing.T) {
+		cert, err := tls.LoadX509KeyPair(certs.config.AgentCertificate, certs.config.AgentKey)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		err = get([]tls.Certificate{cert})
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+
+	t.Run("rejects scrapers without a certificate", func(t *testing.T) {
+		err := get(nil)
+		if err == nil {
+			t.Errorf("expected an error")
+		}
+	})
+}
+
+type certificates struct {
+	dir            string
+	config         mtls.Config
+	hubFingerprint string
+}
+
+// mustCreateCertificates creates a CA along with CLI, hub, and agent
+// certificates valid for localhost.
+func mustCreateCertificates(t *testing.T) certificates {
+	t.Helper()
+
+	dir := testutils.GetTempDir(t, "")
+
+	caKey := mustGenerateKey(t)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "gpupgrade test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("create CA certificate: %v", err)
+	}
+
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatalf("parse CA certificate: %v", err)
+	}
+
+	config := mtls.Config{CACertificate: filepath.Join(dir, "ca.crt")}
+	mustWritePEM(t, config.CACertificate, "CERTIFICATE", caDER)
+
+	createLeaf := func(name string, serial int64) (string, string, []byte) {
+		key := mustGenerateKey(t)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			DNSNames:     []string{"localhost"},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		}
+
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatalf("create %s certificate: %v", name, err)
+		}
+
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatalf("marshal %s key: %v", name, err)
+		}
+
+		certPath := filepath.Join(dir, name+".crt")
+		keyPath := filepath.Join(dir, name+".key")
+		mustWritePEM(t, certPath, "CERTIFICATE", der)
+		mustWritePEM(t, keyPath, "EC PRIVATE KEY", keyDER)
+
+		return certPath, keyPath, der
+	}
+
+	var hubDER []byte
+	config.HubCertificate, config.HubKey, hubDER = createLeaf("hub", 2)
+	config.AgentCertificate, config.AgentKey, _ = createLeaf("agent", 3)
+	config.CLICertificate, config.CLIKey, _ = createLeaf("cli", 4)
+
+	sum := sha256.Sum256(hubDER)
+	return certificates{dir: dir, config: config, hubFingerprint: hex.EncodeToString(sum[:])}
+}
+
+func mustGenerateKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	return key
+}
+
+func mustWritePEM(t *testing.T, path string, blockType string, der []byte) {
+	t.Helper()
+
+	contents := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	testutils.MustWriteToFile(t, path, string(contents))
+}
+
+func mustServe(t *testing.T, agentConfig mtls.AgentConfig) (string, func()) {
+	t.Helper()
+
+	opts, err := agentConfig.ServerOptions()
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	return mustServeWithOptions(t, opts)
+}
+
+// mustServeWithOptions starts a gRPC health server and returns its address
+// along with a function to stop the server.
+func mustServeWithOptions(t *testing.T, opts []grpc.ServerOption) (string, func()) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	server := grpc.NewServer(opts...)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(listener) //nolint
+
+	return listener.Addr().String(), server.Stop
+}
+
+// check issues an RPC rather than only dialing, since with TLS 1.3 a client
+// completes its side of the handshake before the server verifies the client
+// certificate.
+func check(t *testing.T, address string, dialOption func() (grpc.DialOption, error)) error {
+	t.Helper()
+
+	credentials, err := dialOption()
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, address, credentials)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	return err
+}
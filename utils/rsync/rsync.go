@@ -4,14 +4,22 @@
 package rsync
 
 import (
+	"context"
+	"io"
 	"log"
 	"os/exec"
+	"regexp"
 	"runtime"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/greenplum-db/gpupgrade/step"
 	"github.com/greenplum-db/gpupgrade/testutils/exectest"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/logger"
 )
 
 var rsyncCommand = exec.Command
@@ -20,6 +28,55 @@ var rsyncCommand = exec.Command
 // used to rsync from a remote source host
 var ErrInvalidRsyncSourcePath = errors.New("multiple remote source path passed")
 
+// Stats are the statistics of a single rsync run. TransferredBytes is only
+// set when rsync is run with --stats.
+type Stats struct {
+	TransferredBytes uint64
+	Duration         time.Duration
+}
+
+// statsBufferSize bounds the rsync output kept to parse the statistics printed
+// at the end by --stats, such that verbose output from options such as
+// --progress or --itemize-changes is not held in memory.
+const statsBufferSize = 4096
+
+// tailBuffer keeps only the last size bytes written to it.
+type tailBuffer struct {
+	size int
+	buf  []byte
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.buf = append(t.buf, p...)
+	if len(t.buf) > t.size {
+		t.buf = append(t.buf[:0], t.buf[len(t.buf)-t.size:]...)
+	}
+
+	return len(p), nil
+}
+
+func (t *tailBuffer) String() string {
+	return string(t.buf)
+}
+
+// Matches the "Total transferred file size" line printed by rsync --stats,
+// which depending on the rsync version may contain thousands separators.
+var transferredBytesPattern = regexp.MustCompile(`(?m)^Total transferred file size: ([\d,]+) bytes`)
+
+func parseTransferredBytes(output string) uint64 {
+	matches := transferredBytesPattern.FindStringSubmatch(output)
+	if matches == nil {
+		return 0
+	}
+
+	size, err := strconv.ParseUint(strings.ReplaceAll(matches[1], ",", ""), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return size
+}
+
 // Rsync executes "rsync" on the system with the given options.
 // The caller needs to consider adding "/" using os.PathSeparator to the
 // source directory to control where in the destination directory the
@@ -70,9 +127,30 @@ func Rsync(options ...Option) error {
 		cmd.Stderr = opts.stream.Stderr()
 	}
 
+	// only capture the trailing output of --stats to parse the statistics
+	stdout := &tailBuffer{size: statsBufferSize}
+	if hasOption(opts.options, "--stats") {
+		cmd.Stdout = stdout
+		if opts.useStream {
+			cmd.Stdout = io.MultiWriter(opts.stream.Stdout(), stdout)
+		}
+	}
+
 	log.Printf("Executing: %q", cmd.String())
 
-	err := cmd.Run()
+	start := time.Now()
+	err := logger.LogCommand(cmd.Args, func() error {
+		return utils.RunCommandContext(opts.ctx, cmd)
+	})
+
+	stats := Stats{
+		TransferredBytes: parseTransferredBytes(stdout.String()),
+		Duration:         time.Since(start),
+	}
+	if opts.stats != nil {
+		*opts.stats = stats
+	}
+
 	if err != nil {
 		errorText := err.Error()
 
@@ -87,6 +165,16 @@ func Rsync(options ...Option) error {
 	return nil
 }
 
+func hasOption(options []string, option string) bool {
+	for _, o := range options {
+		if o == option {
+			return true
+		}
+	}
+
+	return false
+}
+
 // XXX: for internal testing only
 func SetRsyncCommand(command exectest.Command) {
 	rsyncCommand = command
@@ -158,7 +246,24 @@ func WithStream(stream step.OutStreams) Option {
 	}
 }
 
+// WithStats populates stats after rsync completes. Pass --stats to rsync for
+// the transferred bytes to be populated. Since rsync only reports the
+// statistics, callers such as the hub record them as metrics.
+func WithStats(stats *Stats) Option {
+	return func(options *optionList) {
+		options.stats = stats
+	}
+}
+
+// WithContext kills rsync when ctx is done.
+func WithContext(ctx context.Context) Option {
+	return func(options *optionList) {
+		options.ctx = ctx
+	}
+}
+
 type optionList struct {
+	ctx                context.Context
 	sources            []string
 	hasSourceHost      bool
 	sourceHost         string
@@ -169,10 +274,11 @@ type optionList struct {
 	excludedFiles      []string
 	useStream          bool
 	stream             step.OutStreams
+	stats              *Stats
 }
 
 func newOptionList(opts ...Option) *optionList {
-	o := new(optionList)
+	o := &optionList{ctx: context.Background()}
 	for _, option := range opts {
 		option(o)
 	}
@@ -5,6 +5,7 @@ package rsync_test
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -15,14 +16,36 @@ import (
 	"github.com/greenplum-db/gpupgrade/testutils"
 	"github.com/greenplum-db/gpupgrade/testutils/exectest"
 	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+	"github.com/greenplum-db/gpupgrade/utils/logger"
 	"github.com/greenplum-db/gpupgrade/utils/rsync"
 )
 
 func Success() {}
 
+func SuccessWithStats() {
+	fmt.Println(`
+Number of files: 3 (reg: 2, dir: 1)
+Number of regular files transferred: 2
+Total file size: 1,234,567 bytes
+Total transferred file size: 1,048,576 bytes
+Literal data: 1,048,576 bytes
+Total bytes sent: 1,049,000
+Total bytes received: 57`)
+}
+
+func SuccessWithProgressAndStats() {
+	for i := 0; i < 10000; i++ {
+		fmt.Printf("base/%d/%d\n      1,048,576 100%%  100.00MB/s    0:00:00 (xfr#%d, to-chk=0/3)\n", i, i, i)
+	}
+
+	SuccessWithStats()
+}
+
 func init() {
 	exectest.RegisterMains(
 		Success,
+		SuccessWithStats,
+		SuccessWithProgressAndStats,
 	)
 }
 
@@ -395,3 +418,103 @@ func TestRsync(t *testing.T) {
 		}
 	})
 }
+
+func TestRsyncEvents(t *testing.T) {
+	testlog.SetupTestLogger()
+
+	output, reset := testlog.SetupTestEvents()
+	defer reset()
+
+	rsync.SetRsyncCommand(exectest.NewCommand(Success))
+	defer rsync.ResetRsyncCommand()
+
+	err := rsync.Rsync(rsync.WithSources("/data/source"), rsync.WithDestination("/data/destination"))
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	events := testlog.ParseEvents(t, output)
+	if len(events) != 2 {
+		t.Fatalf("got %d events want 2", len(events))
+	}
+
+	for i, expected := range []logger.EventType{logger.CommandStarted, logger.CommandFinished} {
+		argv := strings.Join(events[i].Argv, " ")
+		if events[i].Type != expected || !strings.Contains(argv, "/data/source /data/destination") {
+			t.Errorf("got %+v want %s event with the rsync argv", events[i], expected)
+		}
+	}
+}
+
+func TestRsyncStats(t *testing.T) {
+	testlog.SetupTestLogger()
+
+	t.Run("populates the transferred bytes and duration from --stats", func(t *testing.T) {
+		rsync.SetRsyncCommand(exectest.NewCommand(SuccessWithStats))
+		defer rsync.ResetRsyncCommand()
+
+		var stats rsync.Stats
+		err := rsync.Rsync(rsync.WithSources("/data/source"), rsync.WithDestination("/data/destination"), rsync.WithOptions("--stats"), rsync.WithStats(&stats))
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if stats.TransferredBytes != 1048576 {
+			t.Errorf("got transferred bytes %d want %d", stats.TransferredBytes, 1048576)
+		}
+
+		if stats.Duration <= 0 {
+			t.Errorf("got duration %v want a positive duration", stats.Duration)
+		}
+	})
+
+	t.Run("writes the stats to the stream", func(t *testing.T) {
+		rsync.SetRsyncCommand(exectest.NewCommand(SuccessWithStats))
+		defer rsync.ResetRsyncCommand()
+
+		streams := &step.BufferedStreams{}
+		var stats rsync.Stats
+		err := rsync.Rsync(rsync.WithSources("/data/source"), rsync.WithDestination("/data/destination"), rsync.WithOptions("--stats"), rsync.WithStream(streams), rsync.WithStats(&stats))
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if !strings.Contains(streams.StdoutBuf.String(), "Total transferred file size") {
+			t.Errorf("expected stdout to contain the stats but has %q", streams.StdoutBuf.String())
+		}
+
+		if stats.TransferredBytes != 1048576 {
+			t.Errorf("got transferred bytes %d want %d", stats.TransferredBytes, 1048576)
+		}
+	})
+
+	t.Run("parses the stats following verbose output", func(t *testing.T) {
+		rsync.SetRsyncCommand(exectest.NewCommand(SuccessWithProgressAndStats))
+		defer rsync.ResetRsyncCommand()
+
+		var stats rsync.Stats
+		err := rsync.Rsync(rsync.WithSources("/data/source"), rsync.WithDestination("/data/destination"), rsync.WithOptions("--progress", "--stats"), rsync.WithStats(&stats))
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if stats.TransferredBytes != 1048576 {
+			t.Errorf("got transferred bytes %d want %d", stats.TransferredBytes, 1048576)
+		}
+	})
+
+	t.Run("leaves the transferred bytes unset without --stats", func(t *testing.T) {
+		rsync.SetRsyncCommand(exectest.NewCommand(SuccessWithStats))
+		defer rsync.ResetRsyncCommand()
+
+		var stats rsync.Stats
+		err := rsync.Rsync(rsync.WithSources("/data/source"), rsync.WithDestination("/data/destination"), rsync.WithStats(&stats))
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if stats.TransferredBytes != 0 {
+			t.Errorf("got transferred bytes %d want 0", stats.TransferredBytes)
+		}
+	})
+}
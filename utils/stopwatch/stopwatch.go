@@ -34,7 +34,12 @@ func (s *Stopwatch) Stop() *Stopwatch {
 }
 
 func (s *Stopwatch) String() string {
-	return round(s.elapsedTime).String()
+	return s.Elapsed().String()
+}
+
+// Elapsed returns the rounded duration between starting and stopping.
+func (s *Stopwatch) Elapsed() time.Duration {
+	return round(s.elapsedTime)
 }
 
 // round returns a pretty-printable duration. This may omit precision and
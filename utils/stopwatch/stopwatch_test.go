@@ -24,6 +24,17 @@ func TestStopwatch(t *testing.T) {
 		}
 	})
 
+	t.Run("returns the elapsed duration", func(t *testing.T) {
+		startTime := mustParseDuration(t, "-3h26m8s")
+		timer := stopwatch.NewTime(time.Now().Add(startTime))
+		timer.Stop()
+
+		expected := mustParseDuration(t, "3h26m")
+		if timer.Elapsed() != expected {
+			t.Errorf("got %v want %v", timer.Elapsed(), expected)
+		}
+	})
+
 	t.Run("correctly rounds duration", func(t *testing.T) {
 		cases := []struct {
 			duration time.Duration
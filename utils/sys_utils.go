@@ -140,6 +140,17 @@ func GetPgUpgradeDir(role string, contentID int32) (string, error) {
 	return filepath.Join(logDir, "pg_upgrade", fmt.Sprintf(role+"%d", contentID)), nil
 }
 
+// GetPgUpgradeLogFile is the log file capturing the pg_upgrade output of a
+// segment across runs.
+func GetPgUpgradeLogFile(role string, contentID int32) (string, error) {
+	logDir, err := GetLogDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(logDir, fmt.Sprintf("pg_upgrade_%s%d.log", role, contentID)), nil
+}
+
 func GetAddMirrorsConfig() string {
 	return filepath.Join(GetStateDir(), "add_mirrors_config")
 }
@@ -258,6 +269,26 @@ func Sanitize(ports []int) []int {
 	return dedupe
 }
 
+// UnavailablePorts returns the ports that cannot be listened on, such as when
+// another process already holds them, along with the reason for each. Like
+// Greenplum the ports are listened on all interfaces.
+func UnavailablePorts(ports []int) map[int]error {
+	unavailable := make(map[int]error)
+	for _, port := range ports {
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+		if err != nil {
+			unavailable[port] = err
+			continue
+		}
+
+		if err := listener.Close(); err != nil {
+			unavailable[port] = err
+		}
+	}
+
+	return unavailable
+}
+
 // FilterEnv selects only the specified variables from the environment and
 // returns those key/value pairs, in the key=value format expected by
 // os/exec.Cmd.Env.
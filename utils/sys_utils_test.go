@@ -5,6 +5,7 @@ package utils_test
 
 import (
 	"errors"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -172,3 +173,23 @@ func TestRemoveDuplicates(t *testing.T) {
 		}
 	})
 }
+
+func TestUnavailablePorts(t *testing.T) {
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+
+	usedPort := listener.Addr().(*net.TCPAddr).Port
+	freePort := testutils.MustGetPort(t)
+
+	unavailable := utils.UnavailablePorts([]int{usedPort, freePort})
+	if len(unavailable) != 1 {
+		t.Fatalf("got unavailable ports %v want only %d", unavailable, usedPort)
+	}
+
+	if unavailable[usedPort] == nil {
+		t.Errorf("expected port %d to be unavailable", usedPort)
+	}
+}